@@ -9,6 +9,7 @@ import (
 	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/skunorm"
 	"go.uber.org/zap"
 )
 
@@ -57,12 +58,15 @@ func main() {
 	// Create SKU mapping
 	mapping := &domain.SKUMapping{
 		SKU:              sku,
+		NormalizedSKU:    skunorm.New(cfg.SKUNormalization).Normalize(sku),
 		ShopifyProductID: productID,
 		ShopifyVariantID: variantID,
 		IsActive:         true,
 	}
 
-	err = repos.SKUMapping.Upsert(context.Background(), mapping)
+	// The history records who ran the command
+	actor := domain.SKUMappingActor{Source: domain.SKUMappingSourceCLI, ChangedBy: os.Getenv("USER")}
+	err = repos.SKUMapping.Upsert(context.Background(), mapping, actor)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create SKU mapping: %v\n", err)
 		os.Exit(1)
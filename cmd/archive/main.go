@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/archive"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+const usage = `Usage: go run cmd/archive/main.go <command> [flags]
+
+Commands:
+  list     List archive files of a table dated in a period
+  query    Print archived rows (JSONL) of a period, optionally filtered
+  restore  Insert archived rows back into the database
+
+Examples:
+  go run cmd/archive/main.go list -table order_events -from 2024-01-01 -to 2024-01-31
+  go run cmd/archive/main.go query -table order_events -from 2024-01-01 -to 2024-01-31 -order-id <uuid>
+  go run cmd/archive/main.go query -table audit_logs -from 2024-01-01 -to 2024-01-31 -resource-id <id>
+  go run cmd/archive/main.go restore -table order_events -key <key>
+  go run cmd/archive/main.go restore -table order_events -from 2024-01-01 -to 2024-01-01 -order-id <uuid>`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	table := flags.String("table", archive.TableOrderEvents, "order_events or audit_logs")
+	fromFlag := flags.String("from", "", "first day of the period (YYYY-MM-DD)")
+	toFlag := flags.String("to", "", "last day of the period (YYYY-MM-DD, default: from)")
+	key := flags.String("key", "", "a single archive file instead of -from/-to")
+	orderID := flags.String("order-id", "", "only events of this order (order_events)")
+	eventType := flags.String("type", "", "only events of this type (order_events)")
+	resourceID := flags.String("resource-id", "", "only audit logs of this resource (audit_logs)")
+	action := flags.String("action", "", "only audit logs with this action (audit_logs)")
+	flags.Parse(os.Args[2:])
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if !cfg.Archive.Enabled() {
+		fmt.Fprintln(os.Stderr, "ARCHIVE_S3_BUCKET is not set")
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	repos := postgres.NewRepositories(db, logger)
+	archiveService := service.NewArchiveService(cfg.Archive, repos, logger)
+
+	var keys []string
+	if *key != "" {
+		keys = []string{*key}
+	} else {
+		from, to, err := parsePeriod(*fromFlag, *toFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if keys, err = archiveService.Keys(ctx, *table, from, to); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to list archive files: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if command == "list" {
+		for _, k := range keys {
+			fmt.Println(k)
+		}
+		fmt.Fprintf(os.Stderr, "%d archive file(s)\n", len(keys))
+		return
+	}
+	if command != "query" && command != "restore" {
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	var matched, restored int64
+	for _, k := range keys {
+		switch *table {
+		case archive.TableOrderEvents:
+			events, err := archiveService.OrderEvents(ctx, k)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", k, err)
+				os.Exit(1)
+			}
+			var selected []*domain.OrderEvent
+			for _, event := range events {
+				if (*orderID == "" || event.SupplierOrderID.String() == *orderID) &&
+					(*eventType == "" || event.EventType == *eventType) {
+					selected = append(selected, event)
+				}
+			}
+			matched += int64(len(selected))
+			if command == "query" {
+				for _, event := range selected {
+					encoder.Encode(archive.FromOrderEvent(event))
+				}
+				continue
+			}
+			n, err := repos.OrderEvent.Restore(ctx, selected)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to restore %s: %v\n", k, err)
+				os.Exit(1)
+			}
+			restored += n
+
+		case archive.TableAuditLogs:
+			logs, err := archiveService.AuditLogs(ctx, k)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", k, err)
+				os.Exit(1)
+			}
+			var selected []*domain.AuditLog
+			for _, log := range logs {
+				if (*resourceID == "" || log.ResourceID == *resourceID) &&
+					(*action == "" || log.Action == *action) {
+					selected = append(selected, log)
+				}
+			}
+			matched += int64(len(selected))
+			if command == "query" {
+				for _, log := range selected {
+					encoder.Encode(archive.FromAuditLog(log))
+				}
+				continue
+			}
+			n, err := repos.AuditLog.Restore(ctx, selected)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to restore %s: %v\n", k, err)
+				os.Exit(1)
+			}
+			restored += n
+
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown table %q (want order_events or audit_logs)\n", *table)
+			os.Exit(1)
+		}
+	}
+
+	if command == "query" {
+		fmt.Fprintf(os.Stderr, "%d row(s) in %d archive file(s)\n", matched, len(keys))
+		return
+	}
+	// Rows still in the database are skipped, so restoring twice is harmless
+	fmt.Printf("Restored %d of %d row(s) from %d archive file(s)\n", restored, matched, len(keys))
+}
+
+// parsePeriod parses -from/-to days; -to defaults to -from
+func parsePeriod(fromValue, toValue string) (time.Time, time.Time, error) {
+	if fromValue == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("-from (or -key) is required")
+	}
+	if toValue == "" {
+		toValue = fromValue
+	}
+	from, err := time.Parse("2006-01-02", fromValue)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("-from must be YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", toValue)
+	if err != nil || to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("-to must be YYYY-MM-DD, not before -from")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// Creates Shopify draft orders for supplier orders that have none: orders from before the
+// Shopify integration, or whose draft order failed during an outage. Safe to run again;
+// orders that already have a draft order are skipped. E.g.:
+//   go run cmd/backfill-draft-orders/main.go -since 2024-01-01 -rate 1 -dry-run
+
+func main() {
+	statuses := flag.String("statuses", "PENDING_CONFIRMATION,CONFIRMED,ON_HOLD", "comma-separated order statuses to backfill")
+	since := flag.String("since", "", "only orders created on or after this date (YYYY-MM-DD)")
+	batchSize := flag.Int("batch-size", 50, "orders read per batch")
+	rate := flag.Float64("rate", 2, "draft orders created per second")
+	limit := flag.Int("limit", 0, "stop after this many orders (0: all)")
+	dryRun := flag.Bool("dry-run", false, "only count the orders that would be backfilled")
+	flag.Parse()
+
+	opts := service.DraftBackfillOptions{
+		BatchSize: *batchSize,
+		Rate:      *rate,
+		Limit:     *limit,
+		DryRun:    *dryRun,
+	}
+	for _, status := range strings.Split(*statuses, ",") {
+		opts.Statuses = append(opts.Statuses, domain.OrderStatus(strings.TrimSpace(status)))
+	}
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -since date: %v\n", err)
+			os.Exit(1)
+		}
+		opts.Since = t
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	// Stop between orders on Ctrl+C; run again to continue
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	backfillService := service.NewDraftBackfillService(cfg, repos, logger)
+	progress, err := backfillService.Run(ctx, opts, func(p service.DraftBackfillProgress) {
+		fmt.Printf("[%s] processed %d: %d created, %d linked, %d failed\n",
+			time.Since(start).Round(time.Second), p.Processed, p.Created, p.Linked, p.Failed)
+	})
+
+	if *dryRun {
+		fmt.Printf("\n%d order(s) without a Shopify draft order\n", progress.Processed)
+	} else {
+		fmt.Printf("\nProcessed: %d\n", progress.Processed)
+		fmt.Printf("Draft orders created: %d\n", progress.Created)
+		fmt.Printf("Existing draft orders linked: %d\n", progress.Linked)
+		fmt.Printf("Failed: %d\n", progress.Failed)
+		for _, failure := range progress.Failures {
+			fmt.Printf("  %s: %s\n", failure.OrderID, failure.Error)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Backfill stopped: %v\n", err)
+		os.Exit(1)
+	}
+	if progress.Failed > 0 {
+		os.Exit(1)
+	}
+}
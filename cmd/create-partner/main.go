@@ -2,26 +2,46 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/apikey"
 	"go.uber.org/zap"
 )
 
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: go run cmd/create-partner/main.go <partner-name> <api-key>")
-		fmt.Println("Example: go run cmd/create-partner/main.go \"Zain Shop\" \"zain-api-key-12345\"")
+	importKey := flag.String("import-key", "", "legacy import: use this existing key instead of generating one")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Println("Usage: go run cmd/create-partner/main.go [-import-key <api-key>] <partner-name>")
+		fmt.Println("Example: go run cmd/create-partner/main.go \"Zain Shop\"")
+		fmt.Println("\nThe API key is generated and shown once. -import-key is only for keys the partner")
+		fmt.Println("already uses with another system.")
 		os.Exit(1)
 	}
 
-	partnerName := os.Args[1]
-	apiKey := os.Args[2]
+	partnerName := flag.Arg(0)
+	apiKey := *importKey
+	if apiKey == "" {
+		generated, err := apikey.Generate(apikey.ShortCode(partnerName))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate API key: %v\n", err)
+			os.Exit(1)
+		}
+		apiKey = generated
+	} else if len(apiKey) < service.MinImportedAPIKeyLength || strings.ContainsAny(apiKey, " \t\r\n") {
+		fmt.Fprintf(os.Stderr, "Imported API keys must be at least %d characters without whitespace\n", service.MinImportedAPIKeyLength)
+		os.Exit(1)
+	}
 
 	// Load configuration
 	cfg, err := config.Load()
@@ -54,9 +74,14 @@ func main() {
 
 	// Create partner
 	partner := &domain.Partner{
-		Name:       partnerName,
-		APIKeyHash: string(apiKeyHash),
-		IsActive:   true,
+		Name:             partnerName,
+		APIKeyHash:       string(apiKeyHash),
+		APIKeyLookupHash: domain.APIKeyLookupHash(apiKey),
+		IsActive:         true,
+	}
+	// Imported keys in the b2b_<short code>_v2_ format keep their prefix for support lookups
+	if prefix, ok := apikey.Parse(apiKey); ok {
+		partner.APIKeyPrefix = prefix.String()
 	}
 
 	err = repos.Partner.Create(context.Background(), partner)
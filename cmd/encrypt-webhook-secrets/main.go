@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/secrets"
+)
+
+// Encrypts the webhook secrets stored in plain text before ENCRYPTION_KEY was set.
+// Safe to run more than once: encrypted secrets are skipped.
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	cipher, err := secrets.NewCipher(cfg.Encryption.Key)
+	if err != nil || cipher == nil {
+		fmt.Fprintln(os.Stderr, "ENCRYPTION_KEY must be set to a valid key")
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	partners := repository.NewEncryptedPartnerRepository(postgres.NewPartnerRepository(db, logger), cipher)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id FROM partners
+		WHERE webhook_secret IS NOT NULL AND webhook_secret NOT LIKE 'enc:%'
+	`)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to query partners: %v\n", err)
+		os.Exit(1)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to scan partner: %v\n", err)
+			os.Exit(1)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	// Reading and updating through the encrypted repository stores the secret encrypted
+	for _, id := range ids {
+		partner, err := partners.GetByID(ctx, id)
+		if err == nil {
+			err = partners.Update(ctx, partner)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to encrypt webhook secret of partner %s: %v\n", id, err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("Encrypted %d webhook secret(s)\n", len(ids))
+}
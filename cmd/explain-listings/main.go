@@ -0,0 +1,114 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+)
+
+// Compares offset and keyset pagination for the hot order listings by running
+// EXPLAIN (ANALYZE, BUFFERS) for a page deep into the result set.
+// Seed a realistic volume first (cmd/seed), then e.g.:
+//   go run cmd/explain-listings/main.go -status CONFIRMED -depth 20000
+
+func main() {
+	partnerID := flag.String("partner-id", "", "partner listing to explain (default: the partner with most orders)")
+	status := flag.String("status", "PENDING_CONFIRMATION", "status listing to explain")
+	depth := flag.Int("depth", 10000, "number of rows to page past")
+	limit := flag.Int("limit", 50, "page size")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if *partnerID == "" {
+		err := db.QueryRow(`
+			SELECT partner_id FROM supplier_orders
+			GROUP BY partner_id ORDER BY COUNT(*) DESC LIMIT 1
+		`).Scan(partnerID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to pick a partner (no orders?): %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	compare(db, "partner_id", *partnerID, *depth, *limit)
+	compare(db, "status", *status, *depth, *limit)
+}
+
+func compare(db *sql.DB, column, value string, depth, limit int) {
+	fmt.Printf("\n=== Listing by %s = %s, page after %d rows ===\n", column, value, depth)
+
+	// Find the cursor a client would hold after paging through depth rows
+	var createdAt time.Time
+	var id string
+	err := db.QueryRow(fmt.Sprintf(`
+		SELECT created_at, id FROM supplier_orders
+		WHERE %s = $1
+		ORDER BY created_at DESC, id DESC
+		OFFSET $2 LIMIT 1
+	`, column), value, depth-1).Scan(&createdAt, &id)
+	if err == sql.ErrNoRows {
+		fmt.Printf("Fewer than %d rows - seed more data or lower -depth\n", depth)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find cursor: %v\n", err)
+		os.Exit(1)
+	}
+
+	offsetQuery := fmt.Sprintf(`
+		SELECT * FROM supplier_orders
+		WHERE %s = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, column)
+	keysetQuery := fmt.Sprintf(`
+		SELECT * FROM supplier_orders
+		WHERE %s = $1 AND (created_at, id) < ($3, $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`, column)
+
+	fmt.Printf("\n--- offset ---\n")
+	explain(db, offsetQuery, value, limit, depth)
+	fmt.Printf("\n--- keyset ---\n")
+	explain(db, keysetQuery, value, limit, createdAt, id)
+}
+
+func explain(db *sql.DB, query string, args ...interface{}) {
+	rows, err := db.Query("EXPLAIN (ANALYZE, BUFFERS) "+query, args...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "EXPLAIN failed: %v\n", err)
+		os.Exit(1)
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read plan: %v\n", err)
+			os.Exit(1)
+		}
+		plan = append(plan, line)
+	}
+
+	fmt.Println(strings.Join(plan, "\n"))
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -55,127 +54,68 @@ func main() {
 	}
 
 	// Parse response
-	var result struct {
-		Data struct {
-			Orders struct {
-				Edges []struct {
-					Node struct {
-						ID                        string `json:"id"`
-						Name                      string `json:"name"`
-						DisplayFulfillmentStatus  string `json:"displayFulfillmentStatus"`
-						DisplayFinancialStatus    string `json:"displayFinancialStatus"`
-						CreatedAt                 string `json:"createdAt"`
-						UpdatedAt                 string `json:"updatedAt"`
-						TotalPriceSet             struct {
-							ShopMoney struct {
-								Amount       string `json:"amount"`
-								CurrencyCode string `json:"currencyCode"`
-							} `json:"shopMoney"`
-						} `json:"totalPriceSet"`
-						Customer struct {
-							FirstName string `json:"firstName"`
-							LastName  string `json:"lastName"`
-							Email     string `json:"email"`
-							Phone     string `json:"phone"`
-						} `json:"customer"`
-						ShippingAddress struct {
-							Address1 string `json:"address1"`
-							Address2 string `json:"address2"`
-							City     string `json:"city"`
-							Province string `json:"province"`
-							Zip      string `json:"zip"`
-							Country  string `json:"country"`
-						} `json:"shippingAddress"`
-						LineItems struct {
-							Edges []struct {
-								Node struct {
-									ID       string `json:"id"`
-									Title    string `json:"title"`
-									Quantity int    `json:"quantity"`
-									Variant  *struct {
-										ID    string `json:"id"`
-										SKU   string `json:"sku"`
-										Title string `json:"title"`
-										Price string `json:"price"`
-									} `json:"variant"`
-									OriginalUnitPriceSet struct {
-										ShopMoney struct {
-											Amount       string `json:"amount"`
-											CurrencyCode string `json:"currencyCode"`
-										} `json:"shopMoney"`
-									} `json:"originalUnitPriceSet"`
-								} `json:"node"`
-							} `json:"edges"`
-						} `json:"lineItems"`
-						Fulfillments []struct {
-							ID         string `json:"id"`
-							Status     string `json:"status"`
-							TrackingInfo []struct {
-								Number  string `json:"number"`
-								URL     string `json:"url"`
-								Company string `json:"company"`
-							} `json:"trackingInfo"`
-						} `json:"fulfillments"`
-					} `json:"node"`
-				} `json:"edges"`
-			} `json:"orders"`
-		} `json:"data"`
-	}
-
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed to parse response: %v\n", err)
+	order, err := shopify.ParseOrderByNumber(resp.Data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 		fmt.Fprintf(os.Stderr, "Response: %s\n", string(resp.Data))
 		os.Exit(1)
 	}
 
-	if len(result.Data.Orders.Edges) == 0 {
+	if order == nil {
 		fmt.Printf("❌ Order not found in Shopify\n")
 		os.Exit(1)
 	}
 
-	order := result.Data.Orders.Edges[0].Node
-
 	fmt.Printf("✅ Order found!\n\n")
 	fmt.Printf("Order Information:\n")
 	fmt.Printf("  Order Number: %s\n", order.Name)
 	fmt.Printf("  Order ID: %s\n", order.ID)
 	fmt.Printf("  Fulfillment Status: %s\n", order.DisplayFulfillmentStatus)
 	fmt.Printf("  Financial Status: %s\n", order.DisplayFinancialStatus)
-	fmt.Printf("  Total: %s %s\n", order.TotalPriceSet.ShopMoney.Amount, order.TotalPriceSet.ShopMoney.CurrencyCode)
+	if order.TotalPriceSet != nil {
+		fmt.Printf("  Total: %s %s\n", order.TotalPriceSet.ShopMoney.Amount, order.TotalPriceSet.ShopMoney.CurrencyCode)
+	}
 	fmt.Printf("  Created: %s\n", order.CreatedAt)
 	fmt.Printf("  Updated: %s\n", order.UpdatedAt)
-	
-	if order.Customer.FirstName != "" || order.Customer.LastName != "" {
+
+	if order.Customer == nil {
+		fmt.Printf("\nCustomer: None (guest checkout)\n")
+	} else {
 		fmt.Printf("\nCustomer:\n")
-		fmt.Printf("  Name: %s %s\n", order.Customer.FirstName, order.Customer.LastName)
-		if order.Customer.Email != "" {
-			fmt.Printf("  Email: %s\n", order.Customer.Email)
+		if name := order.Customer.Name(); name != "" {
+			fmt.Printf("  Name: %s\n", name)
+		}
+		if email := shopify.Value(order.Customer.Email); email != "" {
+			fmt.Printf("  Email: %s\n", email)
 		}
-		if order.Customer.Phone != "" {
-			fmt.Printf("  Phone: %s\n", order.Customer.Phone)
+		if phone := shopify.Value(order.Customer.Phone); phone != "" {
+			fmt.Printf("  Phone: %s\n", phone)
 		}
 	}
 
-	if order.ShippingAddress.Address1 != "" {
+	if addr := order.ShippingAddress; addr != nil {
 		fmt.Printf("\nShipping Address:\n")
-		fmt.Printf("  %s\n", order.ShippingAddress.Address1)
-		if order.ShippingAddress.Address2 != "" {
-			fmt.Printf("  %s\n", order.ShippingAddress.Address2)
+		fmt.Printf("  %s\n", shopify.Value(addr.Address1))
+		if address2 := shopify.Value(addr.Address2); address2 != "" {
+			fmt.Printf("  %s\n", address2)
 		}
-		fmt.Printf("  %s, %s %s\n", order.ShippingAddress.City, order.ShippingAddress.Province, order.ShippingAddress.Zip)
-		fmt.Printf("  %s\n", order.ShippingAddress.Country)
+		fmt.Printf("  %s, %s %s\n", shopify.Value(addr.City), shopify.Value(addr.Province), shopify.Value(addr.Zip))
+		fmt.Printf("  %s\n", shopify.Value(addr.Country))
 	}
 
-	if len(order.LineItems.Edges) > 0 {
+	if len(order.LineItems) > 0 {
 		fmt.Printf("\nLine Items:\n")
-		for i, item := range order.LineItems.Edges {
-			fmt.Printf("  %d. %s (x%d)\n", i+1, item.Node.Title, item.Node.Quantity)
-			if item.Node.Variant != nil {
-				if item.Node.Variant.SKU != "" {
-					fmt.Printf("     SKU: %s\n", item.Node.Variant.SKU)
-				}
+		for i, item := range order.LineItems {
+			fmt.Printf("  %d. %s (x%d)\n", i+1, item.Title, item.Quantity)
+			if sku := item.EffectiveSKU(); sku != "" {
+				fmt.Printf("     SKU: %s\n", sku)
+			}
+			if item.Variant == nil {
+				fmt.Printf("     Variant: None (custom or deleted)\n")
+			}
+			if price := item.OriginalUnitPriceSet; price != nil {
+				fmt.Printf("     Price: %s %s\n", price.ShopMoney.Amount, price.ShopMoney.CurrencyCode)
 			}
-			fmt.Printf("     Price: %s %s\n", item.Node.OriginalUnitPriceSet.ShopMoney.Amount, item.Node.OriginalUnitPriceSet.ShopMoney.CurrencyCode)
 		}
 	}
 
@@ -184,11 +124,11 @@ func main() {
 		for i, fulfillment := range order.Fulfillments {
 			fmt.Printf("  %d. Status: %s\n", i+1, fulfillment.Status)
 			for _, tracking := range fulfillment.TrackingInfo {
-				if tracking.Number != "" {
-					fmt.Printf("     Tracking: %s (%s)\n", tracking.Number, tracking.Company)
+				if number := shopify.Value(tracking.Number); number != "" {
+					fmt.Printf("     Tracking: %s (%s)\n", number, shopify.Value(tracking.Company))
 				}
-				if tracking.URL != "" {
-					fmt.Printf("     URL: %s\n", tracking.URL)
+				if url := shopify.Value(tracking.URL); url != "" {
+					fmt.Printf("     URL: %s\n", url)
 				}
 			}
 		}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// Recomputes the daily order rollups from supplier_orders, e.g. after restoring orders,
+// fixing order data by hand or when first enabling rollups. Safe to run at any time. E.g.:
+//   go run cmd/rebuild-rollups/main.go -from 2024-01-01
+
+func main() {
+	from := flag.String("from", "", "first day to rebuild (YYYY-MM-DD, UTC; default: day of the oldest order)")
+	to := flag.String("to", "", "last day to rebuild, inclusive (YYYY-MM-DD, UTC; default: yesterday)")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	// Stop between days on Ctrl+C; rebuilt days are complete
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Today is still changing and is always counted live
+	end := time.Now().UTC()
+	if *to != "" {
+		day, err := time.Parse("2006-01-02", *to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -to date: %v\n", err)
+			os.Exit(1)
+		}
+		end = day.AddDate(0, 0, 1)
+	}
+
+	var start time.Time
+	if *from != "" {
+		start, err = time.Parse("2006-01-02", *from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -from date: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		first, err := repos.OrderRollup.FirstOrderDay(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to find the oldest order: %v\n", err)
+			os.Exit(1)
+		}
+		if first == nil {
+			fmt.Println("No orders to roll up")
+			return
+		}
+		start = *first
+	}
+
+	rollupService := service.NewRollupService(cfg, repos, logger)
+	var days int
+	var rows int64
+	err = rollupService.Rebuild(ctx, start, end, func(day time.Time, n int64) {
+		days++
+		rows += n
+		fmt.Printf("%s: %d rollup row(s)\n", day.Format("2006-01-02"), n)
+	})
+	fmt.Printf("\nRebuilt %d day(s), %d rollup row(s)\n", days, rows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Rebuild stopped: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+)
+
+// Seed data is written straight through the repositories; Shopify is never called,
+// so orders have no draft/Shopify order IDs and SKU mappings point at fake variants.
+// Do not run against production.
+
+var cities = []string{"Amman", "Irbid", "Zarqa", "Aqaba", "Madaba", "Salt"}
+
+var firstNames = []string{"Omar", "Lina", "Sami", "Rana", "Khaled", "Dana", "Yousef", "Noor"}
+
+var lastNames = []string{"Haddad", "Khoury", "Nasser", "Saleh", "Masri", "Aziz"}
+
+// statusWeights controls how orders are spread across statuses
+var statusWeights = []struct {
+	status domain.OrderStatus
+	weight int
+}{
+	{domain.OrderStatusPendingConfirmation, 30},
+	{domain.OrderStatusConfirmed, 20},
+	{domain.OrderStatusShipped, 25},
+	{domain.OrderStatusDelivered, 10},
+	{domain.OrderStatusRejected, 8},
+	{domain.OrderStatusCancelled, 4},
+	{domain.OrderStatusOnHold, 3},
+}
+
+func main() {
+	partnerCount := flag.Int("partners", 5, "number of partners to create")
+	skuCount := flag.Int("skus", 200, "number of SKU mappings to create")
+	orderCount := flag.Int("orders", 1000, "number of orders to create")
+	days := flag.Int("days", 90, "spread order creation times over this many past days")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed (reuse to reproduce a data set)")
+	flag.Parse()
+
+	if *partnerCount < 1 || *skuCount < 1 || *orderCount < 0 || *days < 1 {
+		fmt.Println("Usage: go run cmd/seed/main.go [-partners 5] [-skus 200] [-orders 1000] [-days 90] [-seed N]")
+		os.Exit(1)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(*seed))
+	runID := time.Now().Format("20060102150405")
+
+	fmt.Printf("Seeding with seed %d (run %s)\n", *seed, runID)
+
+	partners, err := seedPartners(ctx, repos, *partnerCount, runID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to seed partners: %v\n", err)
+		os.Exit(1)
+	}
+
+	mappings, err := seedSKUMappings(ctx, repos, *skuCount, runID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to seed SKU mappings: %v\n", err)
+		os.Exit(1)
+	}
+
+	start := time.Now()
+	for i := 0; i < *orderCount; i++ {
+		partner := partners[rng.Intn(len(partners))]
+		if err := seedOrder(ctx, repos, rng, partner, mappings, runID, i, *days, cfg.Orders.ReferencePrefix); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to seed order %d: %v\n", i, err)
+			os.Exit(1)
+		}
+		if (i+1)%500 == 0 {
+			fmt.Printf("  %d/%d orders\n", i+1, *orderCount)
+		}
+	}
+
+	fmt.Printf("\n✅ Seeded %d partners, %d SKU mappings and %d orders in %s\n",
+		len(partners), len(mappings), *orderCount, time.Since(start).Round(time.Millisecond))
+	fmt.Printf("\nPartner API keys:\n")
+	for i, partner := range partners {
+		fmt.Printf("  %s  %s\n", partner.Name, seedAPIKey(runID, i))
+	}
+}
+
+func seedAPIKey(runID string, i int) string {
+	return fmt.Sprintf("seed-%s-%03d", runID, i)
+}
+
+func seedPartners(ctx context.Context, repos *repository.Repositories, n int, runID string) ([]*domain.Partner, error) {
+	partners := make([]*domain.Partner, 0, n)
+	for i := 0; i < n; i++ {
+		// Minimum bcrypt cost keeps seeding fast; these keys are for staging only
+		apiKeyHash, err := bcrypt.GenerateFromPassword([]byte(seedAPIKey(runID, i)), bcrypt.MinCost)
+		if err != nil {
+			return nil, err
+		}
+
+		partner := &domain.Partner{
+			Name:             fmt.Sprintf("Seed Partner %s-%03d", runID, i),
+			APIKeyHash:       string(apiKeyHash),
+			APIKeyLookupHash: domain.APIKeyLookupHash(seedAPIKey(runID, i)),
+			IsActive:         true,
+		}
+		if err := repos.Partner.Create(ctx, partner); err != nil {
+			return nil, err
+		}
+		partners = append(partners, partner)
+	}
+	return partners, nil
+}
+
+func seedSKUMappings(ctx context.Context, repos *repository.Repositories, n int, runID string) ([]*domain.SKUMapping, error) {
+	mappings := make([]*domain.SKUMapping, 0, n)
+	for i := 0; i < n; i++ {
+		mapping := &domain.SKUMapping{
+			SKU:              fmt.Sprintf("SEED-%s-%05d", runID, i),
+			ShopifyProductID: 9000000000000 + int64(i),
+			ShopifyVariantID: 9100000000000 + int64(i),
+			IsActive:         true,
+		}
+		if err := repos.SKUMapping.Upsert(ctx, mapping, domain.SKUMappingActor{Source: domain.SKUMappingSourceSeed, ChangedBy: "run " + runID}); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+func seedOrder(
+	ctx context.Context,
+	repos *repository.Repositories,
+	rng *rand.Rand,
+	partner *domain.Partner,
+	mappings []*domain.SKUMapping,
+	runID string,
+	i int,
+	days int,
+	referencePrefix string,
+) error {
+	createdAt := time.Now().Add(-time.Duration(rng.Int63n(int64(days) * int64(24*time.Hour))))
+	phone := fmt.Sprintf("+9627%08d", rng.Intn(100000000))
+
+	order := &domain.SupplierOrder{
+		PartnerID:         partner.ID,
+		PartnerOrderID:    fmt.Sprintf("SEED-%s-%06d", runID, i),
+		Status:            domain.OrderStatusPendingConfirmation,
+		CustomerName:      firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))],
+		CustomerPhone:     phone,
+		CustomerPhoneE164: &phone,
+		ShippingAddress: domain.Address{
+			Street:     fmt.Sprintf("%d Seed Street", rng.Intn(500)+1),
+			City:       cities[rng.Intn(len(cities))],
+			PostalCode: fmt.Sprintf("%05d", rng.Intn(100000)),
+			Country:    "JO",
+		},
+		PaymentStatus: "paid",
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+	}
+
+	// 1-4 supplier items, plus a partner-only item now and then (mixed cart)
+	var items []*domain.SupplierOrderItem
+	for j := rng.Intn(4) + 1; j > 0; j-- {
+		mapping := mappings[rng.Intn(len(mappings))]
+		variantID := mapping.ShopifyVariantID
+		items = append(items, &domain.SupplierOrderItem{
+			SKU:              mapping.SKU,
+			Title:            "Seed product " + mapping.SKU,
+			Price:            float64(rng.Intn(9000)+100) / 100,
+			Quantity:         rng.Intn(3) + 1,
+			IsSupplierItem:   true,
+			ShopifyVariantID: &variantID,
+		})
+	}
+	if rng.Intn(5) == 0 {
+		items = append(items, &domain.SupplierOrderItem{
+			SKU:      "PARTNER-ONLY",
+			Title:    "Partner product",
+			Price:    9.99,
+			Quantity: 1,
+		})
+	}
+	for _, item := range items {
+		item.ListPrice = item.Price
+		order.CartTotal += item.Price * float64(item.Quantity)
+	}
+
+	referenceNumber, err := repos.SupplierOrder.NextReferenceNumber(ctx)
+	if err != nil {
+		return err
+	}
+	reference := domain.FormatOrderReference(referencePrefix, createdAt, referenceNumber)
+	order.Reference = &reference
+
+	if err := repos.SupplierOrder.Create(ctx, order); err != nil {
+		return err
+	}
+	for _, item := range items {
+		item.SupplierOrderID = order.ID
+		item.CreatedAt = createdAt
+	}
+	if err := repos.SupplierOrderItem.CreateBatch(ctx, items); err != nil {
+		return err
+	}
+
+	return advanceOrder(ctx, repos, rng, order, pickStatus(rng))
+}
+
+func pickStatus(rng *rand.Rand) domain.OrderStatus {
+	total := 0
+	for _, sw := range statusWeights {
+		total += sw.weight
+	}
+	n := rng.Intn(total)
+	for _, sw := range statusWeights {
+		if n < sw.weight {
+			return sw.status
+		}
+		n -= sw.weight
+	}
+	return domain.OrderStatusPendingConfirmation
+}
+
+// advanceOrder walks the order through the same repository transitions the service uses,
+// so status timestamps are populated
+func advanceOrder(ctx context.Context, repos *repository.Repositories, rng *rand.Rand, order *domain.SupplierOrder, target domain.OrderStatus) error {
+	at := order.CreatedAt
+	step := func() time.Time {
+		at = at.Add(time.Duration(rng.Intn(48*60)+5) * time.Minute)
+		return at
+	}
+	id := order.ID
+
+	switch target {
+	case domain.OrderStatusPendingConfirmation:
+		return nil
+	case domain.OrderStatusOnHold:
+		return repos.SupplierOrder.Hold(ctx, id, domain.OrderStatusPendingConfirmation, "Seed: payment verification")
+	case domain.OrderStatusRejected:
+		reason := "Seed: out of stock"
+		return repos.SupplierOrder.UpdateStatus(ctx, id, domain.OrderStatusRejected, &reason, step())
+	case domain.OrderStatusCancelled:
+		return repos.SupplierOrder.UpdateStatus(ctx, id, domain.OrderStatusCancelled, nil, step())
+	}
+
+	// Confirmed, shipped or delivered
+	if err := repos.SupplierOrder.UpdateStatus(ctx, id, domain.OrderStatusConfirmed, nil, step()); err != nil {
+		return err
+	}
+	if target == domain.OrderStatusConfirmed {
+		return nil
+	}
+
+	carrier := "Aramex"
+	trackingNumber := "SEED" + uuid.NewString()[:8]
+	if err := repos.SupplierOrder.UpdateStatusWithTracking(ctx, id, domain.OrderStatusConfirmed, domain.OrderStatusShipped, &carrier, &trackingNumber, nil, step()); err != nil {
+		return err
+	}
+	if target == domain.OrderStatusShipped {
+		return nil
+	}
+
+	return repos.SupplierOrder.UpdateStatus(ctx, id, domain.OrderStatusDelivered, nil, step())
+}
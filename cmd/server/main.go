@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +11,17 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"github.com/jafarshop/b2bapi/internal/api"
+	"github.com/jafarshop/b2bapi/internal/authguard"
 	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/events"
+	"github.com/jafarshop/b2bapi/internal/grpcapi"
+	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/secrets"
+	"github.com/jafarshop/b2bapi/internal/service"
 )
 
 func main() {
@@ -23,12 +31,19 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Initialize logger
-	var logger *zap.Logger
+	// Initialize logger (the level is atomic so a config reload can change it)
+	logLevel, err := zap.ParseAtomicLevel(cfg.Tunables().LogLevel)
+	if err != nil {
+		log.Fatalf("Invalid log level: %v", err)
+	}
+	loggerConfig := zap.NewDevelopmentConfig()
 	if cfg.Environment == "production" {
-		logger, _ = zap.NewProduction()
-	} else {
-		logger, _ = zap.NewDevelopment()
+		loggerConfig = zap.NewProductionConfig()
+	}
+	loggerConfig.Level = logLevel
+	logger, err := loggerConfig.Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 	defer logger.Sync()
 
@@ -44,16 +59,52 @@ func main() {
 	}
 	defer db.Close()
 
+	// Connect to the read replica (optional, serves listings and reports)
+	replica, err := postgres.NewReplicaConnection(cfg.Database)
+	if err != nil {
+		logger.Fatal("Failed to connect to read replica", zap.Error(err))
+	}
+	if replica != nil {
+		defer replica.Close()
+		logger.Info("Listings and reports use the read replica")
+	}
+
 	// Run migrations
 	if err := postgres.RunMigrations(cfg.Database); err != nil {
 		logger.Fatal("Failed to run migrations", zap.Error(err))
 	}
 
 	// Initialize repositories
-	repos := postgres.NewRepositories(db, logger)
+	repos := postgres.NewRepositoriesWithReplica(db, replica, logger)
+	cipher, err := secrets.NewCipher(cfg.Encryption.Key)
+	if err != nil {
+		logger.Fatal("Invalid encryption key", zap.Error(err))
+	}
+	repos.Partner = repository.NewEncryptedPartnerRepository(repos.Partner, cipher)
+	if cfg.API.AuthCacheTTL > 0 {
+		repos.Partner = repository.NewCachedPartnerRepository(repos.Partner, cfg.API.AuthCacheTTL)
+	}
+
+	// Initialize order event publisher (optional)
+	publisher, err := events.NewPublisher(cfg.Events, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize event publisher", zap.Error(err))
+	}
+	defer publisher.Close()
+	repos.OrderEvent = events.NewPublishingOrderEventRepository(repos.OrderEvent, publisher, logger)
+
+	// Initialize the services shared by the REST and gRPC APIs
+	services := service.NewServices(cfg, repos, logger)
+
+	// Initialize live admin order feed (optional)
+	var orderFeed *events.Feed
+	if cfg.Orders.StreamEnabled {
+		orderFeed = events.NewFeed(repos, logger)
+	}
 
 	// Initialize router
-	router := api.NewRouter(cfg, repos, logger)
+	authGuard := authguard.New(cfg.AuthGuard, logger)
+	router := api.NewRouter(cfg, repos, services, authGuard, orderFeed, logger, logLevel)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -73,20 +124,201 @@ func main() {
 
 	logger.Info("Server started successfully", zap.String("address", srv.Addr))
 
+	// Start gRPC server for internal consumers (optional)
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			logger.Fatal("Failed to listen for gRPC", zap.Error(err))
+		}
+
+		grpcServer = grpcapi.NewServer(cfg, repos, services, authGuard, logger)
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+
+		logger.Info("gRPC server started successfully", zap.String("address", lis.Addr().String()))
+	}
+
+	// Background jobs report the errors they log to Sentry (optional), under the job's name
+	jobLogger := services.Errors.Wrap(logger)
+
+	// Start background duplicate order check (optional)
+	checkCtx, stopChecks := context.WithCancel(context.Background())
+	defer stopChecks()
+	if cfg.Duplicates.CheckInterval > 0 {
+		duplicateService := service.NewDuplicateService(repos, jobLogger.Named("duplicate_check"))
+		go duplicateService.RunDuplicateCheck(checkCtx, cfg.Duplicates.CheckInterval, func() time.Duration {
+			return cfg.Tunables().DuplicateWindow
+		})
+	}
+
+	// Start carrier delivery polling (optional, only carriers with a tracking adapter are polled)
+	if cfg.Carriers.PollInterval > 0 {
+		shippingService := service.NewShippingService(cfg.Carriers, repos, services.Orders, jobLogger.Named("delivery_polling"))
+		go shippingService.RunDeliveryPolling(checkCtx, cfg.Carriers.PollInterval)
+	}
+
+	// Start Shopify order edit reconciliation (optional)
+	if cfg.ShopifyEditSync.Interval > 0 {
+		editSyncService := service.NewOrderEditSyncService(cfg, repos, services.Shopify, services.Webhooks, jobLogger.Named("edit_sync"))
+		go editSyncService.RunEditSync(checkCtx, cfg.ShopifyEditSync.Interval, cfg.ShopifyEditSync.Window)
+	}
+
+	// Start cart/Shopify total reconciliation (optional)
+	if cfg.TotalCheck.Interval > 0 {
+		totalCheckService := service.NewTotalCheckService(cfg, repos, services.Shopify, jobLogger.Named("total_check"))
+		go totalCheckService.RunTotalCheck(checkCtx, cfg.TotalCheck.Interval, cfg.TotalCheck.Window)
+	}
+
+	// Start daily partner email digests (optional)
+	if cfg.Digest.Enabled {
+		digestService := service.NewDigestService(cfg, repos, jobLogger.Named("digest"))
+		go digestService.RunDigests(checkCtx)
+	}
+
+	// Start purging rows past their retention period (optional)
+	if cfg.Retention.Interval > 0 {
+		retentionService := service.NewRetentionService(cfg, repos, jobLogger.Named("retention"))
+		go retentionService.RunPurge(checkCtx, cfg.Retention.Interval)
+	}
+
+	// Start rolling up recent days of orders for reports (optional)
+	if cfg.Rollups.Interval > 0 {
+		rollupService := service.NewRollupService(cfg, repos, jobLogger.Named("rollups"))
+		go rollupService.RunRollups(checkCtx, cfg.Rollups.Interval)
+	}
+
+	// Start cancelling orders left pending confirmation too long (optional)
+	if cfg.Orders.PendingExpiry > 0 {
+		expiryService := service.NewOrderExpiryService(cfg, repos, services.Shopify, services.Webhooks, jobLogger.Named("order_expiry"))
+		go expiryService.RunExpiry(checkCtx, cfg.Orders.ExpiryCheckInterval)
+	}
+
+	// Start checking orders for impossible data (optional)
+	if cfg.Invariants.Interval > 0 {
+		invariantService := service.NewInvariantService(cfg, repos, jobLogger.Named("invariant_check"))
+		go invariantService.RunInvariantCheck(checkCtx, cfg.Invariants.Interval)
+	}
+
+	// Start alerting partners about low stock of SKUs they ordered recently (optional)
+	if cfg.LowStock.Interval > 0 {
+		lowStockService := service.NewLowStockService(cfg, repos, services.Shopify, services.Webhooks, jobLogger.Named("low_stock"))
+		go lowStockService.RunLowStockCheck(checkCtx, cfg.LowStock.Interval)
+	}
+
+	// Start submitting orders from partners' CSV batch files (optional)
+	if cfg.BatchIngest.Interval > 0 {
+		batchIngestService := service.NewBatchIngestService(cfg.BatchIngest, repos, services.Carts, jobLogger.Named("batch_ingest"))
+		go batchIngestService.RunIngest(checkCtx, cfg.BatchIngest.Interval)
+	}
+
+	// Start exchanging order and shipment files with the 3PL (optional)
+	if cfg.Fulfillment.Interval > 0 {
+		fulfillmentService := service.NewFulfillmentService(cfg.Fulfillment, repos, services.Orders, jobLogger.Named("fulfillment"))
+		go fulfillmentService.RunFulfillment(checkCtx, cfg.Fulfillment.Interval)
+	}
+
+	// Start alerting the ops channel about orders pending confirmation past the SLA (optional)
+	if cfg.OpsAlerts.ConfirmationSLA > 0 {
+		opsAlertService := service.NewOpsAlertService(cfg, repos, jobLogger.Named("ops_alerts"))
+		go opsAlertService.RunSLACheck(checkCtx, cfg.OpsAlerts.SLACheckInterval)
+	}
+
+	// Start listening for order events to feed the admin order stream (optional)
+	if orderFeed != nil {
+		go orderFeed.Run(checkCtx, postgres.NewListener(cfg.Database, logger))
+	}
+
+	// Pick up credentials rotated in the secrets backend (optional)
+	if cfg.Secrets.Backend != "env" && cfg.Secrets.RefreshInterval > 0 {
+		go refreshSecrets(checkCtx, cfg, cfg.Secrets.RefreshInterval, jobLogger.Named("secrets_refresh"))
+	}
+
+	// Reload tunable settings on SIGHUP
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go watchConfig(checkCtx, reload, cfg, logLevel, logger)
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopChecks()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
 	logger.Info("Server exited")
 }
+
+// watchConfig reloads the tunable settings each time a signal arrives on reload
+func watchConfig(ctx context.Context, reload <-chan os.Signal, cfg *config.Config, logLevel zap.AtomicLevel, logger *zap.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reload:
+		}
+
+		changes, err := config.Reload(cfg)
+		if err != nil {
+			logger.Error("Configuration reload failed, keeping current settings", zap.Error(err))
+			continue
+		}
+		if len(changes) == 0 {
+			logger.Info("Configuration reloaded, no changes")
+			continue
+		}
+
+		for _, change := range changes {
+			logger.Info("Configuration setting changed",
+				zap.String("key", change.Key),
+				zap.String("from", change.From),
+				zap.String("to", change.To),
+			)
+			// Only a changed LOG_LEVEL overrides a level set through the admin API
+			if change.Key == "LOG_LEVEL" {
+				if err := logLevel.UnmarshalText([]byte(change.To)); err != nil {
+					logger.Error("Failed to apply log level", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// refreshSecrets periodically re-reads the secrets backend. New Shopify requests and new
+// database connections use a rotated value; nothing needs a restart.
+func refreshSecrets(ctx context.Context, cfg *config.Config, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := config.RefreshSecrets(ctx, cfg)
+		if err != nil {
+			logger.Error("Failed to refresh secrets, keeping current values", zap.Error(err))
+		}
+		for _, key := range changed {
+			logger.Info("Secret rotated", zap.String("key", key))
+		}
+	}
+}
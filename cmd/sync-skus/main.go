@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository/postgres"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"go.uber.org/zap"
+)
+
+// Syncs sku_mappings from the full Shopify catalog using a bulk operation.
+// Usage: go run cmd/sync-skus/main.go
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Initialize logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Connect to database
+	db, err := postgres.NewConnection(cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repos := postgres.NewRepositories(db, logger)
+
+	// Stop polling on Ctrl+C (the bulk operation keeps running on Shopify's side)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println("Exporting catalog with a Shopify bulk operation (this can take a few minutes)...")
+
+	syncService := service.NewSKUSyncService(cfg, repos, logger)
+	result, err := syncService.SyncFromShopify(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "SKU sync failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n✅ SKU sync complete\n")
+	fmt.Printf("Products: %d\n", result.Products)
+	fmt.Printf("Variants: %d\n", result.Variants)
+	fmt.Printf("Mappings upserted: %d\n", result.Upserted)
+	fmt.Printf("Variants without SKU: %d\n", result.SkippedNoSKU)
+}
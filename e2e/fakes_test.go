@@ -0,0 +1,213 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var operationPattern = regexp.MustCompile(`(?:query|mutation)\s+(\w+)`)
+
+// fakeShopify answers the Admin GraphQL operations of the cart → order flow
+type fakeShopify struct {
+	*httptest.Server
+
+	nextID     int64
+	mu         sync.Mutex
+	operations []string
+}
+
+func newFakeShopify() *fakeShopify {
+	f := &fakeShopify{nextID: 1000}
+	f.Server = httptest.NewTLSServer(http.HandlerFunc(f.serveGraphQL))
+	return f
+}
+
+func (f *fakeShopify) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	operation := ""
+	if match := operationPattern.FindStringSubmatch(req.Query); match != nil {
+		operation = match[1]
+	}
+	f.mu.Lock()
+	f.operations = append(f.operations, operation)
+	f.mu.Unlock()
+
+	var data interface{}
+	switch operation {
+	case "draftOrderCreate":
+		data = map[string]interface{}{
+			"draftOrderCreate": map[string]interface{}{
+				"draftOrder": map[string]interface{}{"id": fmt.Sprintf("gid://shopify/DraftOrder/%d", atomic.AddInt64(&f.nextID, 1))},
+				"userErrors": []interface{}{},
+			},
+		}
+	case "draftOrderComplete":
+		data = map[string]interface{}{
+			"draftOrderComplete": map[string]interface{}{
+				"draftOrder": map[string]interface{}{
+					"id":    req.Variables["id"],
+					"order": map[string]interface{}{"id": fmt.Sprintf("gid://shopify/Order/%d", atomic.AddInt64(&f.nextID, 1))},
+				},
+				"userErrors": []interface{}{},
+			},
+		}
+	case "metafieldsSet":
+		data = map[string]interface{}{
+			"metafieldsSet": map[string]interface{}{"userErrors": []interface{}{}},
+		}
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]string{{"message": "fake Shopify does not support " + operation}},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// count returns how often operation was called
+func (f *fakeShopify) count(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, op := range f.operations {
+		if op == operation {
+			n++
+		}
+	}
+	return n
+}
+
+// fakeDHL is the DHL tracking API; tracking numbers added with deliver are reported delivered
+type fakeDHL struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	delivered map[string]bool
+}
+
+func newFakeDHL() *fakeDHL {
+	f := &fakeDHL{delivered: make(map[string]bool)}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trackingNumber := r.URL.Query().Get("trackingNumber")
+		f.mu.Lock()
+		delivered := f.delivered[trackingNumber]
+		f.mu.Unlock()
+
+		status := map[string]interface{}{"statusCode": "transit", "description": "In transit"}
+		if delivered {
+			status = map[string]interface{}{
+				"statusCode":  "delivered",
+				"description": "Delivered",
+				"timestamp":   time.Now().UTC().Format(time.RFC3339),
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"shipments": []interface{}{map[string]interface{}{"id": trackingNumber, "status": status}},
+		})
+	}))
+	return f
+}
+
+func (f *fakeDHL) deliver(trackingNumber string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delivered[trackingNumber] = true
+}
+
+// webhook is a webhook delivery received from the API
+type webhook struct {
+	EventType string
+	Body      []byte
+	Payload   map[string]interface{}
+}
+
+// webhookReceiver is the partners' webhook endpoint
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	received []webhook
+	notify   chan struct{}
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	rcv := &webhookReceiver{notify: make(chan struct{}, 1)}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+
+		rcv.mu.Lock()
+		rcv.received = append(rcv.received, webhook{EventType: r.Header.Get("X-B2B-Event"), Body: body, Payload: payload})
+		rcv.mu.Unlock()
+		select {
+		case rcv.notify <- struct{}{}:
+		default:
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return rcv
+}
+
+// wait returns the webhook of eventType about the order, failing the test if none arrives
+func (rcv *webhookReceiver) wait(t *testing.T, eventType, orderID string) webhook {
+	t.Helper()
+	deadline := time.After(10 * time.Second)
+	for {
+		if hook, ok := rcv.find(eventType, orderID); ok {
+			return hook
+		}
+		select {
+		case <-rcv.notify:
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("no %s webhook for order %s", eventType, orderID)
+		}
+	}
+}
+
+// count returns how many webhooks of eventType were received about the order
+func (rcv *webhookReceiver) count(eventType, orderID string) int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	n := 0
+	for _, hook := range rcv.received {
+		if hook.EventType == eventType && hook.Payload["supplier_order_id"] == orderID {
+			n++
+		}
+	}
+	return n
+}
+
+func (rcv *webhookReceiver) find(eventType, orderID string) (webhook, bool) {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	for _, hook := range rcv.received {
+		if hook.EventType == eventType && hook.Payload["supplier_order_id"] == orderID {
+			return hook, true
+		}
+	}
+	return webhook{}, false
+}
@@ -0,0 +1,270 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/schemas"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// testPartner is a partner with its API key and a supplier SKU it can order
+type testPartner struct {
+	*domain.Partner
+	apiKey string
+	sku    string
+}
+
+func newPartner(t *testing.T) *testPartner {
+	t.Helper()
+	ctx := context.Background()
+
+	apiKey := "e2e-" + uuid.NewString()
+	hash, err := bcrypt.GenerateFromPassword([]byte(apiKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhookURL := env.webhooks.URL
+	partner := &domain.Partner{
+		Name:             "E2E " + t.Name(),
+		APIKeyHash:       string(hash),
+		APIKeyLookupHash: domain.APIKeyLookupHash(apiKey),
+		WebhookURL:       &webhookURL,
+		IsActive:         true,
+	}
+	if err := env.repos.Partner.Create(ctx, partner); err != nil {
+		t.Fatalf("Failed to create partner: %v", err)
+	}
+
+	sku := "E2E-" + strings.ToUpper(uuid.NewString()[:8])
+	mapping := &domain.SKUMapping{
+		SKU:              sku,
+		ShopifyProductID: 1,
+		ShopifyVariantID: 2,
+		IsActive:         true,
+	}
+	if err := env.repos.SKUMapping.Upsert(ctx, mapping, domain.SKUMappingActor{Source: domain.SKUMappingSourceSeed, ChangedBy: "e2e " + t.Name()}); err != nil {
+		t.Fatalf("Failed to create SKU mapping: %v", err)
+	}
+
+	return &testPartner{Partner: partner, apiKey: apiKey, sku: sku}
+}
+
+// cart returns a cart body; supplier selects whether its item is the partner's supplier SKU
+func (p *testPartner) cart(partnerOrderID string, supplier bool) map[string]interface{} {
+	sku := "NOT-OURS-1"
+	if supplier {
+		sku = p.sku
+	}
+	return map[string]interface{}{
+		"partner_order_id": partnerOrderID,
+		"items": []map[string]interface{}{
+			{"sku": sku, "title": "Widget", "price": 10, "quantity": 2},
+		},
+		"customer": map[string]interface{}{"name": "Jane Doe", "phone": "0791234567"},
+		"shipping": map[string]interface{}{"street": "1 Main St", "city": "Amman", "postal_code": "11118", "country": "JO"},
+		"totals":   map[string]interface{}{"subtotal": 20, "total": 20},
+	}
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   map[string]interface{}
+}
+
+// do calls the API as the partner
+func (p *testPartner) do(t *testing.T, method, path string, body interface{}, headers map[string]string) response {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, env.api.URL+path, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	r := response{status: resp.StatusCode, header: resp.Header}
+	if resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(&r.body); err != nil {
+			t.Fatalf("%s %s: response is not JSON: %v", method, path, err)
+		}
+	}
+	return r
+}
+
+func expectStatus(t *testing.T, what string, r response, want int) {
+	t.Helper()
+	if r.status != want {
+		t.Fatalf("%s: status = %d, want %d (body %v)", what, r.status, want, r.body)
+	}
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	partner := newPartner(t)
+	idempotency := map[string]string{"Idempotency-Key": uuid.NewString()}
+	cart := partner.cart("E2E-"+uuid.NewString()[:8], true)
+
+	// Submit: the order is created and its Shopify draft order completed
+	submitted := partner.do(t, http.MethodPost, "/v1/carts/submit", cart, idempotency)
+	expectStatus(t, "submit", submitted, http.StatusCreated)
+	orderID, _ := submitted.body["supplier_order_id"].(string)
+	if submitted.body["status"] != string(domain.OrderStatusPendingConfirmation) {
+		t.Errorf("submit: status = %v, want %s", submitted.body["status"], domain.OrderStatusPendingConfirmation)
+	}
+	if got, want := submitted.header.Get("Location"), "/v1/orders/"+orderID; got != want {
+		t.Errorf("submit: Location = %q, want %q", got, want)
+	}
+
+	order := partner.do(t, http.MethodGet, "/v1/orders/"+orderID, nil, nil)
+	expectStatus(t, "get order", order, http.StatusOK)
+	if order.body["shopify_draft_order_id"] == nil || order.body["shopify_order_id"] == nil {
+		t.Errorf("order has no Shopify draft order or order: %v", order.body)
+	}
+
+	// Resubmitting with the same key returns the same order without creating another
+	drafts := env.shopify.count("draftOrderCreate")
+	replayed := partner.do(t, http.MethodPost, "/v1/carts/submit", cart, idempotency)
+	expectStatus(t, "idempotent resubmit", replayed, http.StatusOK)
+	if replayed.body["supplier_order_id"] != orderID {
+		t.Errorf("idempotent resubmit: supplier_order_id = %v, want %s", replayed.body["supplier_order_id"], orderID)
+	}
+	if n := env.shopify.count("draftOrderCreate"); n != drafts {
+		t.Errorf("idempotent resubmit created %d more draft orders", n-drafts)
+	}
+
+	// Same key, different cart
+	changed := partner.cart(cart["partner_order_id"].(string), true)
+	changed["totals"] = map[string]interface{}{"subtotal": 20, "total": 25}
+	expectStatus(t, "resubmit with a different cart", partner.do(t, http.MethodPost, "/v1/carts/submit", changed, idempotency), http.StatusConflict)
+
+	// Shipping a pending order is not a valid transition
+	ship := map[string]interface{}{"carrier": "dhl", "tracking_number": "JD" + strings.ToUpper(uuid.NewString()[:10])}
+	expectStatus(t, "ship before confirm", partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/ship", ship, nil), http.StatusBadRequest)
+
+	// Confirm
+	confirmed := partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/confirm", nil, nil)
+	expectStatus(t, "confirm", confirmed, http.StatusOK)
+	if confirmed.body["status"] != string(domain.OrderStatusConfirmed) {
+		t.Errorf("confirm: status = %v, want %s", confirmed.body["status"], domain.OrderStatusConfirmed)
+	}
+	expectWebhook(t, service.WebhookEventOrderConfirmed, orderID, domain.OrderStatusConfirmed)
+	expectStatus(t, "confirm twice", partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/confirm", nil, nil), http.StatusBadRequest)
+
+	// Ship
+	shipped := partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/ship", ship, nil)
+	expectStatus(t, "ship", shipped, http.StatusOK)
+	hook := expectWebhook(t, service.WebhookEventOrderShipped, orderID, domain.OrderStatusShipped)
+	if data, _ := hook.Payload["data"].(map[string]interface{}); data["tracking_number"] != ship["tracking_number"] {
+		t.Errorf("order.shipped data = %v, want tracking_number %v", data, ship["tracking_number"])
+	}
+	reject := map[string]interface{}{"code": "OTHER", "reason": "too late"}
+	expectStatus(t, "reject after ship", partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/reject", reject, nil), http.StatusBadRequest)
+
+	// Deliver: the carrier reports the shipment delivered on the next tracking poll
+	env.dhl.deliver(ship["tracking_number"].(string))
+	if _, err := env.services.Shipping.PollShippedOrders(context.Background()); err != nil {
+		t.Fatalf("PollShippedOrders() error = %v", err)
+	}
+	expectWebhook(t, service.WebhookEventOrderDelivered, orderID, domain.OrderStatusDelivered)
+
+	order = partner.do(t, http.MethodGet, "/v1/orders/"+orderID, nil, nil)
+	expectStatus(t, "get delivered order", order, http.StatusOK)
+	if order.body["status"] != string(domain.OrderStatusDelivered) {
+		t.Errorf("order status = %v, want %s", order.body["status"], domain.OrderStatusDelivered)
+	}
+
+	// Every transition was sent once
+	for _, eventType := range []string{service.WebhookEventOrderConfirmed, service.WebhookEventOrderShipped, service.WebhookEventOrderDelivered} {
+		if n := env.webhooks.count(eventType, orderID); n != 1 {
+			t.Errorf("received %d %s webhooks, want 1", n, eventType)
+		}
+	}
+}
+
+// expectWebhook waits for the order's webhook and checks it matches the published schema
+func expectWebhook(t *testing.T, eventType, orderID string, status domain.OrderStatus) webhook {
+	t.Helper()
+	hook := env.webhooks.wait(t, eventType, orderID)
+	if hook.Payload["status"] != string(status) {
+		t.Errorf("%s webhook status = %v, want %s", eventType, hook.Payload["status"], status)
+	}
+	fields, err := schemas.Validate(schemas.WebhookOrderEvent, hook.Body)
+	if err != nil || fields != nil {
+		t.Errorf("%s webhook does not match its schema: %v %v", eventType, fields, err)
+	}
+	return hook
+}
+
+func TestCartWithoutSupplierSKUs(t *testing.T) {
+	partner := newPartner(t)
+	drafts := env.shopify.count("draftOrderCreate")
+
+	r := partner.do(t, http.MethodPost, "/v1/carts/submit", partner.cart("E2E-"+uuid.NewString()[:8], false), nil)
+
+	expectStatus(t, "submit", r, http.StatusNoContent)
+	if n := env.shopify.count("draftOrderCreate"); n != drafts {
+		t.Errorf("created %d draft orders for a cart without supplier SKUs", n-drafts)
+	}
+}
+
+func TestRejectedOrder(t *testing.T) {
+	partner := newPartner(t)
+
+	submitted := partner.do(t, http.MethodPost, "/v1/carts/submit", partner.cart("E2E-"+uuid.NewString()[:8], true), nil)
+	expectStatus(t, "submit", submitted, http.StatusCreated)
+	orderID := submitted.body["supplier_order_id"].(string)
+
+	reject := map[string]interface{}{"code": "OUT_OF_STOCK", "reason": "sold out"}
+	expectStatus(t, "reject", partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/reject", reject, nil), http.StatusOK)
+	hook := expectWebhook(t, service.WebhookEventOrderRejected, orderID, domain.OrderStatusRejected)
+	if data, _ := hook.Payload["data"].(map[string]interface{}); data["code"] != "OUT_OF_STOCK" {
+		t.Errorf("order.rejected data = %v, want code OUT_OF_STOCK", data)
+	}
+
+	// Rejected is final
+	expectStatus(t, "confirm after reject", partner.do(t, http.MethodPost, "/v1/admin/orders/"+orderID+"/confirm", nil, nil), http.StatusBadRequest)
+}
+
+func TestOtherPartnersOrder(t *testing.T) {
+	owner := newPartner(t)
+	other := newPartner(t)
+
+	submitted := owner.do(t, http.MethodPost, "/v1/carts/submit", owner.cart("E2E-"+uuid.NewString()[:8], true), nil)
+	expectStatus(t, "submit", submitted, http.StatusCreated)
+
+	r := other.do(t, http.MethodGet, fmt.Sprintf("/v1/orders/%s", submitted.body["supplier_order_id"]), nil, nil)
+	if r.status != http.StatusForbidden && r.status != http.StatusNotFound {
+		t.Errorf("another partner's order: status = %d, want 403 or 404", r.status)
+	}
+}
@@ -0,0 +1,28 @@
+// Package adminui serves the embedded admin dashboard under /admin.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Register mounts the admin dashboard at /admin. The dashboard is a static
+// page that calls the existing /v1/admin API with the operator's API key.
+func Register(router *gin.Engine) {
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		// The embedded directory is fixed at build time
+		panic(err)
+	}
+
+	router.GET("/admin", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/admin/")
+	})
+	router.StaticFS("/admin/", http.FS(static))
+}
@@ -0,0 +1,201 @@
+(function () {
+  'use strict';
+
+  var KEY_STORAGE = 'b2b_admin_api_key';
+  var currentOrderId = null;
+  var streaming = false;
+
+  function $(id) { return document.getElementById(id); }
+
+  function showMessage(text, isError) {
+    var el = $('message');
+    el.textContent = text || '';
+    el.className = isError ? 'error' : '';
+  }
+
+  function api(method, path, body) {
+    var headers = { 'Authorization': 'Bearer ' + (sessionStorage.getItem(KEY_STORAGE) || '') };
+    if (body) headers['Content-Type'] = 'application/json';
+    return fetch('/v1/admin' + path, {
+      method: method,
+      headers: headers,
+      body: body ? JSON.stringify(body) : undefined
+    }).then(function (res) {
+      return res.json().catch(function () { return {}; }).then(function (data) {
+        if (!res.ok) throw new Error(data.error || ('HTTP ' + res.status));
+        return data;
+      });
+    });
+  }
+
+  function cell(row, text) {
+    var td = document.createElement('td');
+    td.textContent = text == null ? '' : text;
+    row.appendChild(td);
+  }
+
+  function loadOrders() {
+    showMessage('');
+    api('GET', '/orders?status=' + encodeURIComponent($('status-filter').value)).then(function (data) {
+      var tbody = $('orders');
+      tbody.innerHTML = '';
+      (data.orders || []).forEach(function (order) {
+        var row = document.createElement('tr');
+        row.className = 'clickable';
+        cell(row, order.partner_order_id);
+        cell(row, order.customer_name);
+        cell(row, order.cart_total);
+        cell(row, order.status);
+        cell(row, order.created_at);
+        row.addEventListener('click', function () { openOrder(order.id); });
+        tbody.appendChild(row);
+      });
+      if (!data.orders || data.orders.length === 0) showMessage('No orders.');
+    }).catch(function (err) { showMessage(err.message, true); });
+  }
+
+  function openOrder(id) {
+    currentOrderId = id;
+    api('GET', '/orders/' + id).then(function (order) {
+      $('list').hidden = true;
+      $('detail').hidden = false;
+      $('detail-title').textContent = order.partner_order_id + ' - ' + order.status;
+
+      var fields = $('detail-fields');
+      fields.innerHTML = '';
+      [
+        ['Order ID', order.id],
+        ['Partner ID', order.partner_id],
+        ['Customer', order.customer_name],
+        ['Phone', order.customer_phone],
+        ['Address', JSON.stringify(order.shipping_address)],
+        ['Total', order.cart_total],
+        ['Payment', [order.payment_status, order.payment_method].filter(Boolean).join(' / ')],
+        ['Shopify order', order.shopify_order_id],
+        ['Tracking', [order.tracking_carrier, order.tracking_number].filter(Boolean).join(' ')],
+        ['Rejection', [order.rejection_code, order.rejection_reason].filter(Boolean).join(': ')]
+      ].forEach(function (pair) {
+        if (pair[1] == null || pair[1] === '') return;
+        var dt = document.createElement('dt');
+        dt.textContent = pair[0];
+        var dd = document.createElement('dd');
+        dd.textContent = pair[1];
+        fields.appendChild(dt);
+        fields.appendChild(dd);
+      });
+
+      var items = $('detail-items');
+      items.innerHTML = '';
+      (order.items || []).forEach(function (item) {
+        var row = document.createElement('tr');
+        cell(row, item.sku);
+        cell(row, item.title);
+        cell(row, item.quantity);
+        cell(row, item.price);
+        cell(row, item.is_supplier_item ? 'yes' : 'no');
+        items.appendChild(row);
+      });
+
+      var events = $('detail-events');
+      events.innerHTML = '';
+      (order.events || []).forEach(function (event) {
+        var li = document.createElement('li');
+        li.textContent = event.created_at + ' ' + event.event_type + ' ' + JSON.stringify(event.event_data || {});
+        events.appendChild(li);
+      });
+    }).catch(function (err) { showMessage(err.message, true); });
+  }
+
+  function loadCarriers() {
+    api('GET', '/carriers').then(function (data) {
+      var list = $('carriers');
+      list.innerHTML = '';
+      (data.carriers || []).forEach(function (carrier) {
+        var option = document.createElement('option');
+        option.value = carrier.name;
+        list.appendChild(option);
+      });
+    }).catch(function () { /* free text still works */ });
+  }
+
+  // Keeps the page current from the live order stream. EventSource can't send the
+  // Authorization header, so the stream is read with fetch instead.
+  function watchOrders() {
+    if (streaming) return;
+    streaming = true;
+    fetch('/v1/admin/orders/stream', {
+      headers: { 'Authorization': 'Bearer ' + (sessionStorage.getItem(KEY_STORAGE) || '') }
+    }).then(function (res) {
+      if (!res.ok || !res.body) {
+        // 503: the stream is disabled on the server, keep using the refresh button
+        if (res.status === 503) return 'disabled';
+        throw new Error('HTTP ' + res.status);
+      }
+      var reader = res.body.getReader();
+      var decoder = new TextDecoder();
+      var buffer = '';
+      function read() {
+        return reader.read().then(function (chunk) {
+          if (chunk.done) return;
+          buffer += decoder.decode(chunk.value, { stream: true });
+          var blocks = buffer.split('\n\n');
+          buffer = blocks.pop();
+          blocks.forEach(function (block) {
+            var data = null;
+            block.split('\n').forEach(function (line) {
+              if (line.indexOf('data:') === 0) data = JSON.parse(line.slice(5));
+            });
+            if (!data) return;
+            if (!$('list').hidden) loadOrders();
+            else if (data.order_id === currentOrderId || !data.order_id) openOrder(currentOrderId);
+          });
+          return read();
+        });
+      }
+      return read();
+    }).catch(function () { /* reconnect below */ }).then(function (result) {
+      streaming = false;
+      if (result !== 'disabled') setTimeout(watchOrders, 5000);
+    });
+  }
+
+  function transition(action, body) {
+    api('POST', '/orders/' + currentOrderId + '/' + action, body).then(function () {
+      showMessage('Order ' + action + ' succeeded.');
+      openOrder(currentOrderId);
+    }).catch(function (err) { showMessage(err.message, true); });
+  }
+
+  $('login').addEventListener('submit', function (e) {
+    e.preventDefault();
+    sessionStorage.setItem(KEY_STORAGE, $('api-key').value);
+    $('api-key').value = '';
+    loadOrders();
+    loadCarriers();
+    watchOrders();
+  });
+  $('refresh').addEventListener('click', loadOrders);
+  $('status-filter').addEventListener('change', loadOrders);
+  $('back').addEventListener('click', function () {
+    $('detail').hidden = true;
+    $('list').hidden = false;
+    loadOrders();
+  });
+  $('confirm').addEventListener('click', function () { transition('confirm'); });
+  $('reject-form').addEventListener('submit', function (e) {
+    e.preventDefault();
+    transition('reject', { code: e.target.code.value, reason: e.target.reason.value });
+  });
+  $('ship-form').addEventListener('submit', function (e) {
+    e.preventDefault();
+    var body = { carrier: e.target.carrier.value, tracking_number: e.target.tracking_number.value };
+    if (e.target.tracking_url.value) body.tracking_url = e.target.tracking_url.value;
+    transition('ship', body);
+  });
+
+  if (sessionStorage.getItem(KEY_STORAGE)) {
+    loadOrders();
+    loadCarriers();
+    watchOrders();
+  }
+})();
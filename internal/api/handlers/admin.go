@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/internal/api/middleware"
@@ -20,11 +24,33 @@ type ConfirmOrderRequest struct {
 	// Empty for now, can add fields later
 }
 
-// RejectOrderRequest represents reject order request
+// RejectOrderRequest represents reject order request. Without a code the reason is
+// required and the code is OTHER.
 type RejectOrderRequest struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// BulkRejectOrdersRequest represents bulk reject orders request. ETags maps order IDs to
+// the ETag each order was read with, the per-order If-Match.
+type BulkRejectOrdersRequest struct {
+	OrderIDs []string          `json:"order_ids" binding:"required,min=1,max=100"`
+	ETags    map[string]string `json:"etags"`
+	Code     string            `json:"code"`
+	Reason   string            `json:"reason" binding:"max=1000"`
+}
+
+// HoldOrderRequest represents hold order request
+type HoldOrderRequest struct {
 	Reason string `json:"reason" binding:"required"`
 }
 
+// ReassignOrderRequest represents reassign order request
+type ReassignOrderRequest struct {
+	PartnerID string `json:"partner_id" binding:"required"`
+	Reason    string `json:"reason" binding:"required,max=1000"`
+}
+
 // ShipOrderRequest represents ship order request
 type ShipOrderRequest struct {
 	Carrier        string `json:"carrier" binding:"required"`
@@ -32,10 +58,29 @@ type ShipOrderRequest struct {
 	TrackingURL    *string `json:"tracking_url,omitempty"`
 }
 
-// HandleConfirmOrder handles POST /v1/admin/orders/:id/confirm
-func HandleConfirmOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+// AdminOrderResponse is the admin view of an order, including its event timeline
+type AdminOrderResponse struct {
+	OrderResponse
+	PartnerID string               `json:"partner_id"`
+	Events    []OrderEventResponse `json:"events"`
+}
+
+// OrderEventResponse represents an order event in the timeline
+type OrderEventResponse struct {
+	ID        string                 `json:"id"`
+	EventType string                 `json:"event_type"`
+	EventData map[string]interface{} `json:"event_data,omitempty"`
+	CreatedAt string                 `json:"created_at"`
+}
+
+// adminOrderResponseFields are the fields ?fields= can select on admin order responses
+var adminOrderResponseFields = jsonFieldNames(reflect.TypeOf(AdminOrderResponse{}))
+
+// HandleAdminGetOrder handles GET /v1/admin/orders/:id
+// ?fields=id,status,events returns only those fields
+func HandleAdminGetOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get partner from context (for now, admin uses same auth)
+		// Get partner from context
 		_, ok := middleware.GetPartnerFromContext(c)
 		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
@@ -50,6 +95,12 @@ func HandleConfirmOrder(repos *repository.Repositories, logger *zap.Logger) gin.
 			return
 		}
 
+		fields, err := parseFields(c, adminOrderResponseFields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		// Get order
 		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
 		if err != nil {
@@ -62,30 +113,123 @@ func HandleConfirmOrder(repos *repository.Repositories, logger *zap.Logger) gin.
 			return
 		}
 
-		// Confirm order
-		orderService := service.NewOrderService(repos, logger)
-		if err := orderService.ConfirmOrder(c.Request.Context(), orderID); err != nil {
-			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		// Get order items and timeline, unless the selected fields do not need them
+		var items []*domain.SupplierOrderItem
+		if fields.has("items") || fields.has("parcel") {
+			items, err = repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), orderID)
+			if err != nil {
+				logger.Error("Failed to get order items", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
 				return
 			}
-			logger.Error("Failed to confirm order", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to confirm order"})
+		}
+		substitutions, ok := loadItemSubstitutions(c, repos, fields, orderID, logger)
+		if !ok {
+			return
+		}
+
+		var events []*domain.OrderEvent
+		if fields.has("events") {
+			events, err = repos.OrderEvent.GetByOrderID(c.Request.Context(), orderID)
+			if err != nil {
+				logger.Error("Failed to get order events", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+
+		eventResponses := make([]OrderEventResponse, len(events))
+		for i, event := range events {
+			eventResponses[i] = OrderEventResponse{
+				ID:        event.ID.String(),
+				EventType: event.EventType,
+				EventData: event.EventData,
+				CreatedAt: formatTimestamp(event.CreatedAt),
+			}
+		}
+
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
+		c.JSON(http.StatusOK, fields.apply(AdminOrderResponse{
+			OrderResponse: buildOrderResponse(order, items, substitutions),
+			PartnerID:     order.PartnerID.String(),
+			Events:        eventResponses,
+		}))
+	}
+}
+
+// HandleConfirmOrder handles POST /v1/admin/orders/:id/confirm
+// Orders over APPROVAL_THRESHOLD need two distinct admins: the first confirmation is recorded
+// as an approval (202), the second confirms the order and completes its Shopify draft.
+// With dry_run=true it returns what the confirmation would do instead.
+func HandleConfirmOrder(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context (for now, admin uses same auth)
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderIDStr := c.Param("id")
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		// Approve (and, once fully approved, confirm) order, or only work out what would happen
+		dryRun := c.Query("dry_run") == "true"
+		var approvals *service.ApprovalStatus
+		var plan *service.TransitionPlan
+		if dryRun {
+			plan, err = services.Approvals.PlanApprove(c.Request.Context(), orderID, admin.ID)
+		} else {
+			approvals, err = services.Approvals.Approve(c.Request.Context(), orderID, admin.ID)
+		}
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			case *errors.ErrInvalidStateTransition:
+				c.JSON(http.StatusBadRequest, gin.H{"error": e.Error()})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
+			default:
+				logger.Error("Failed to confirm order", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to confirm order"})
+			}
+			return
+		}
+		if dryRun {
+			c.JSON(http.StatusOK, transitionPlanResponse(plan))
 			return
 		}
 
 		// Get updated order
-		order, _ = repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		if err != nil {
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"id":     order.ID.String(),
-			"status": order.Status,
+		statusCode := http.StatusOK
+		if !approvals.Complete() {
+			statusCode = http.StatusAccepted
+		}
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
+		c.JSON(statusCode, gin.H{
+			"id":        order.ID.String(),
+			"status":    order.Status,
+			"approvals": buildApprovalResponse(approvals),
 		})
 	}
 }
 
 // HandleRejectOrder handles POST /v1/admin/orders/:id/reject
-func HandleRejectOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+// With dry_run=true it returns what the rejection would do instead.
+func HandleRejectOrder(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get partner from context
 		_, ok := middleware.GetPartnerFromContext(c)
@@ -107,26 +251,45 @@ func HandleRejectOrder(repos *repository.Repositories, logger *zap.Logger) gin.H
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusUnprocessableEntity, gin.H{
 				"error":   "validation failed",
-				"details": err.Error(),
+				"details": bindingErrors(err),
 			})
 			return
 		}
 
-		// Reject order
-		orderService := service.NewOrderService(repos, logger)
-		if err := orderService.RejectOrder(c.Request.Context(), orderID, req.Reason); err != nil {
+		// Reject order, or only work out what would happen
+		dryRun := c.Query("dry_run") == "true"
+		var plan *service.TransitionPlan
+		if dryRun {
+			plan, err = services.Orders.PlanRejectOrder(c.Request.Context(), orderID, domain.RejectionCode(req.Code), req.Reason)
+		} else {
+			err = services.Orders.RejectOrder(c.Request.Context(), orderID, domain.RejectionCode(req.Code), req.Reason)
+		}
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
 			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
 			logger.Error("Failed to reject order", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reject order"})
 			return
 		}
+		if dryRun {
+			c.JSON(http.StatusOK, transitionPlanResponse(plan))
+			return
+		}
 
 		// Get updated order
 		order, _ := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
 
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
 		c.JSON(http.StatusOK, gin.H{
 			"id":     order.ID.String(),
 			"status": order.Status,
@@ -134,8 +297,138 @@ func HandleRejectOrder(repos *repository.Repositories, logger *zap.Logger) gin.H
 	}
 }
 
+// HandleBulkRejectOrders handles POST /v1/admin/orders/bulk-reject
+// Orders whose ETag no longer matches are left alone and reported as precondition_failed.
+func HandleBulkRejectOrders(cfg *config.Config, services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req BulkRejectOrdersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		orderIDs := make([]uuid.UUID, len(req.OrderIDs))
+		invalid := make(map[string]string)
+		for i, idStr := range req.OrderIDs {
+			orderID, err := uuid.Parse(idStr)
+			if err != nil {
+				invalid[fmt.Sprintf("order_ids[%d]", i)] = "invalid order ID"
+				continue
+			}
+			orderIDs[i] = orderID
+		}
+		if len(invalid) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": fieldErrors(invalid)})
+			return
+		}
+
+		// Orders read from a stale copy are not rejected; the rest go to the service
+		preconditions := make(map[uuid.UUID]service.BulkRejectResult)
+		var current []uuid.UUID
+		for i, orderID := range orderIDs {
+			result, ok, err := bulkRejectPrecondition(c, cfg, repos, orderID, req.ETags[req.OrderIDs[i]])
+			if err != nil {
+				logger.Error("Failed to get order for If-Match", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			if !ok {
+				preconditions[orderID] = result
+				continue
+			}
+			current = append(current, orderID)
+		}
+
+		// Each order is rejected on its own; the results say which were
+		rejections, err := services.Orders.RejectOrders(c.Request.Context(), current, domain.RejectionCode(req.Code), req.Reason)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to reject orders", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reject orders"})
+			return
+		}
+
+		// Results follow the request order, repeated IDs once
+		byID := make(map[uuid.UUID]service.BulkRejectResult, len(orderIDs))
+		for _, result := range rejections {
+			byID[result.OrderID] = result
+		}
+		for orderID, result := range preconditions {
+			byID[orderID] = result
+		}
+		results := make([]service.BulkRejectResult, 0, len(byID))
+		for _, orderID := range orderIDs {
+			if result, ok := byID[orderID]; ok {
+				results = append(results, result)
+				delete(byID, orderID)
+			}
+		}
+
+		rejected := 0
+		for _, result := range results {
+			if result.Result == service.BulkRejectRejected {
+				rejected++
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"rejected": rejected,
+			"failed":   len(results) - rejected,
+			"results":  results,
+		})
+	}
+}
+
+// bulkRejectPrecondition applies OrderIfMatchMiddleware's check to one order of a bulk
+// rejection. It returns false with the order's result when the order must be left alone.
+// Missing orders pass, for RejectOrders to report.
+func bulkRejectPrecondition(c *gin.Context, cfg *config.Config, repos *repository.Repositories, orderID uuid.UUID, ifMatch string) (service.BulkRejectResult, bool, error) {
+	if ifMatch == "" {
+		if cfg.Orders.RequireIfMatch {
+			return service.BulkRejectResult{
+				OrderID: orderID,
+				Result:  service.BulkRejectPreconditionRequired,
+				Error:   "etags must have the order's ETag",
+			}, false, nil
+		}
+		return service.BulkRejectResult{}, true, nil
+	}
+
+	order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return service.BulkRejectResult{}, true, nil
+		}
+		return service.BulkRejectResult{}, false, err
+	}
+	if etag := middleware.OrderETag(order); !middleware.ETagMatches(ifMatch, etag) {
+		return service.BulkRejectResult{
+			OrderID: orderID,
+			Result:  service.BulkRejectPreconditionFailed,
+			Error:   "order has changed since it was read",
+			ETag:    etag,
+		}, false, nil
+	}
+	return service.BulkRejectResult{}, true, nil
+}
+
 // HandleShipOrder handles POST /v1/admin/orders/:id/ship
-func HandleShipOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+// With dry_run=true it returns what the shipment would do instead.
+func HandleShipOrder(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get partner from context
 		_, ok := middleware.GetPartnerFromContext(c)
@@ -157,26 +450,52 @@ func HandleShipOrder(repos *repository.Repositories, logger *zap.Logger) gin.Han
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusUnprocessableEntity, gin.H{
 				"error":   "validation failed",
-				"details": err.Error(),
+				"details": bindingErrors(err),
 			})
 			return
 		}
 
-		// Ship order
-		orderService := service.NewOrderService(repos, logger)
-		if err := orderService.ShipOrder(c.Request.Context(), orderID, req.Carrier, req.TrackingNumber, req.TrackingURL); err != nil {
+		// Ship order, or only work out what would happen
+		dryRun := c.Query("dry_run") == "true"
+		var plan *service.TransitionPlan
+		if dryRun {
+			plan, err = services.Shipping.PlanShipOrder(c.Request.Context(), orderID, req.Carrier, req.TrackingNumber, req.TrackingURL)
+		} else {
+			err = services.Shipping.ShipOrder(c.Request.Context(), orderID, req.Carrier, req.TrackingNumber, req.TrackingURL)
+		}
+		if err != nil {
 			if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 				return
 			}
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   e.Error(),
+					"details": fieldErrors(e.Fields),
+				})
+				return
+			}
+			if _, ok := err.(*errors.ErrConflict); ok {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
 			logger.Error("Failed to ship order", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to ship order"})
 			return
 		}
+		if dryRun {
+			c.JSON(http.StatusOK, transitionPlanResponse(plan))
+			return
+		}
 
 		// Get updated order
 		order, _ := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
 
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
 		c.JSON(http.StatusOK, gin.H{
 			"id":              order.ID.String(),
 			"status":          order.Status,
@@ -187,7 +506,301 @@ func HandleShipOrder(repos *repository.Repositories, logger *zap.Logger) gin.Han
 	}
 }
 
+// HandleGetOrderByShopifyID handles GET /v1/admin/shopify-orders/:shopify_order_id
+func HandleGetOrderByShopifyID(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse Shopify order ID
+		shopifyOrderID, err := strconv.ParseInt(c.Param("shopify_order_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Shopify order ID"})
+			return
+		}
+
+		order, err := services.Lookup.ResolveShopifyOrder(c.Request.Context(), shopifyOrderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to resolve Shopify order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":                     order.ID.String(),
+			"partner_id":             order.PartnerID.String(),
+			"partner_order_id":       order.PartnerOrderID,
+			"reference":              order.Reference,
+			"status":                 order.Status,
+			"shopify_draft_order_id": order.ShopifyDraftOrderID,
+			"shopify_order_id":       order.ShopifyOrderID,
+		})
+	}
+}
+
+// HandleGetOrderByReference handles GET /v1/admin/order-references/:reference
+func HandleGetOrderByReference(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		reference := strings.TrimSpace(c.Param("reference"))
+
+		order, err := repos.SupplierOrder.GetByReference(c.Request.Context(), reference)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order by reference", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":                     order.ID.String(),
+			"partner_id":             order.PartnerID.String(),
+			"partner_order_id":       order.PartnerOrderID,
+			"reference":              order.Reference,
+			"status":                 order.Status,
+			"shopify_draft_order_id": order.ShopifyDraftOrderID,
+			"shopify_order_id":       order.ShopifyOrderID,
+		})
+	}
+}
+
+// HandleReconcileOrder handles POST /v1/admin/orders/:id/reconcile
+func HandleReconcileOrder(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.ShopifyOrderID == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "order has no Shopify order"})
+			return
+		}
+
+		divergences, err := services.EditSync.ReconcileOrder(c.Request.Context(), order)
+		if err != nil {
+			logger.Error("Failed to reconcile order with Shopify", zap.Error(err))
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to reconcile order with Shopify"})
+			return
+		}
+		if divergences == nil {
+			divergences = []service.LineItemDivergence{}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":          order.ID.String(),
+			"in_sync":     len(divergences) == 0,
+			"differences": divergences,
+		})
+	}
+}
+
+// HandleHoldOrder handles POST /v1/admin/orders/:id/hold
+func HandleHoldOrder(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderIDStr := c.Param("id")
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		// Parse request
+		var req HoldOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		// Hold order
+		if err := services.Orders.HoldOrder(c.Request.Context(), orderID, req.Reason); err != nil {
+			switch err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			case *errors.ErrInvalidStateTransition:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				logger.Error("Failed to hold order", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hold order"})
+			}
+			return
+		}
+
+		// Get updated order
+		order, _ := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
+		c.JSON(http.StatusOK, gin.H{
+			"id":               order.ID.String(),
+			"status":           order.Status,
+			"hold_reason":      order.HoldReason,
+			"held_from_status": order.HeldFromStatus,
+		})
+	}
+}
+
+// HandleReleaseOrder handles POST /v1/admin/orders/:id/release
+func HandleReleaseOrder(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderIDStr := c.Param("id")
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		// Release order; an order held for the credit limit gets its draft order completed
+		if err := services.Carts.ReleaseOrder(c.Request.Context(), orderID); err != nil {
+			switch err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				logger.Error("Failed to release order", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to release order"})
+			}
+			return
+		}
+
+		// Get updated order
+		order, _ := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
+		c.JSON(http.StatusOK, gin.H{
+			"id":     order.ID.String(),
+			"status": order.Status,
+		})
+	}
+}
+
+// HandleReassignOrder handles POST /v1/admin/orders/:id/reassign
+// Moves an order submitted from the wrong partner account to another partner
+func HandleReassignOrder(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderIDStr := c.Param("id")
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		var req ReassignOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		toPartnerID, err := uuid.Parse(req.PartnerID)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(map[string]string{"partner_id": "invalid partner ID"}),
+			})
+			return
+		}
+
+		// Reassign order
+		order, err := services.Orders.ReassignOrder(c.Request.Context(), orderID, toPartnerID, req.Reason, admin.ID)
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			case *errors.ErrValidation:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Message, "details": fieldErrors(e.Fields)})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				logger.Error("Failed to reassign order", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reassign order"})
+			}
+			return
+		}
+
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
+		c.JSON(http.StatusOK, gin.H{
+			"id":         order.ID.String(),
+			"partner_id": order.PartnerID.String(),
+			"status":     order.Status,
+		})
+	}
+}
+
+// orderListFields are the fields of each order in listings, for ?fields=
+var orderListFields = []string{
+	"id", "partner_order_id", "reference", "status", "shopify_draft_order_id",
+	"shopify_draft_order_name", "shopify_order_id", "shopify_order_name",
+	"customer_name", "cart_total", "tracking_number", "created_at", "updated_at",
+}
+
 // HandleListOrders handles GET /v1/admin/orders
+// ?fields=id,status,tracking_number returns only those fields of each order
 func HandleListOrders(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get partner from context
@@ -198,6 +811,11 @@ func HandleListOrders(repos *repository.Repositories, logger *zap.Logger) gin.Ha
 		}
 
 		// Parse query parameters
+		fields, err := parseFields(c, orderListFields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		statusStr := c.Query("status")
 		limitStr := c.DefaultQuery("limit", "50")
 		offsetStr := c.DefaultQuery("offset", "0")
@@ -212,6 +830,17 @@ func HandleListOrders(repos *repository.Repositories, logger *zap.Logger) gin.Ha
 			offset = 0
 		}
 
+		// Keyset pagination by default; offset is still honoured for older clients
+		var cursor *domain.OrderCursor
+		if cursorStr := c.Query("cursor"); cursorStr != "" {
+			cursor, err = domain.DecodeOrderCursor(cursorStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+			offset = 0
+		}
+
 		var orders []*domain.SupplierOrder
 		if statusStr != "" {
 			status := domain.OrderStatus(statusStr)
@@ -219,9 +848,17 @@ func HandleListOrders(repos *repository.Repositories, logger *zap.Logger) gin.Ha
 				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
 				return
 			}
-			orders, err = repos.SupplierOrder.ListByStatus(c.Request.Context(), status, limit, offset)
+			if offset > 0 {
+				orders, err = repos.SupplierOrder.ListByStatus(c.Request.Context(), status, limit, offset)
+			} else {
+				orders, err = repos.SupplierOrder.ListByStatusAfter(c.Request.Context(), status, cursor, limit)
+			}
 		} else {
-			orders, err = repos.SupplierOrder.ListByPartnerID(c.Request.Context(), partner.ID, limit, offset)
+			if offset > 0 {
+				orders, err = repos.SupplierOrder.ListByPartnerID(c.Request.Context(), partner.ID, limit, offset)
+			} else {
+				orders, err = repos.SupplierOrder.ListByPartnerIDAfter(c.Request.Context(), partner.ID, cursor, limit)
+			}
 		}
 
 		if err != nil {
@@ -231,24 +868,34 @@ func HandleListOrders(repos *repository.Repositories, logger *zap.Logger) gin.Ha
 		}
 
 		// Build response
-		orderResponses := make([]gin.H, len(orders))
+		orderResponses := make([]interface{}, len(orders))
 		for i, order := range orders {
-			orderResponses[i] = gin.H{
-				"id":                  order.ID.String(),
-				"partner_order_id":   order.PartnerOrderID,
-				"status":             order.Status,
-				"shopify_draft_order_id": order.ShopifyDraftOrderID,
-				"customer_name":      order.CustomerName,
-				"cart_total":         order.CartTotal,
-				"created_at":         order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-				"updated_at":         order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			}
+			orderResponses[i] = fields.apply(gin.H{
+				"id":                       order.ID.String(),
+				"partner_order_id":         order.PartnerOrderID,
+				"reference":                order.Reference,
+				"status":                   order.Status,
+				"shopify_draft_order_id":   order.ShopifyDraftOrderID,
+				"shopify_draft_order_name": order.ShopifyDraftOrderName,
+				"shopify_order_id":         order.ShopifyOrderID,
+				"shopify_order_name":       order.ShopifyOrderName,
+				"customer_name":            order.CustomerName,
+				"cart_total":               order.CartTotal,
+				"tracking_number":          order.TrackingNumber,
+				"created_at":               formatTimestamp(order.CreatedAt),
+				"updated_at":               formatTimestamp(order.UpdatedAt),
+			})
 		}
 
-		c.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"orders": orderResponses,
 			"limit":  limit,
 			"offset": offset,
-		})
+		}
+		if len(orders) == limit {
+			response["next_cursor"] = domain.CursorAfter(orders[len(orders)-1]).Encode()
+		}
+
+		c.JSON(http.StatusOK, response)
 	}
 }
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/internal/service/servicemock"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+func TestHandleRejectOrder(t *testing.T) {
+	orderID := uuid.New()
+
+	tests := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "rejected",
+			path:       orderID.String(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid order ID",
+			path:       "not-a-uuid",
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid order ID",
+		},
+		{
+			name:       "invalid transition",
+			path:       orderID.String(),
+			err:        &errors.ErrInvalidStateTransition{From: domain.OrderStatusShipped, To: domain.OrderStatusRejected},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid code",
+			path:       orderID.String(),
+			err:        &errors.ErrValidation{Message: "validation failed", Fields: map[string]string{"code": "unknown rejection code"}},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantError:  "validation failed",
+		},
+		{
+			name:       "unexpected failure",
+			path:       orderID.String(),
+			err:        fmt.Errorf("database unavailable"),
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "failed to reject order",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := servicemock.NewOrderService(t)
+			orders.RejectOrderFunc = func(ctx context.Context, id uuid.UUID, code domain.RejectionCode, reason string) error {
+				if id != orderID || code != domain.RejectionCode("OUT_OF_STOCK") || reason != "gone" {
+					t.Errorf("RejectOrder(%s, %s, %q)", id, code, reason)
+				}
+				return tt.err
+			}
+			repos := &repository.Repositories{
+				SupplierOrder: &fakeOrders{orders: map[uuid.UUID]*domain.SupplierOrder{
+					orderID: {ID: orderID, Status: domain.OrderStatusRejected},
+				}},
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/admin/orders/:id/reject", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, &domain.Partner{ID: uuid.New()})
+			}, HandleRejectOrder(&service.Services{Orders: orders}, repos, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			body := `{"code": "OUT_OF_STOCK", "reason": "gone"}`
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/orders/"+tt.path+"/reject", strings.NewReader(body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			got := decodeBody(t, w)
+			if tt.wantError != "" && got["error"] != tt.wantError {
+				t.Errorf("error = %v, want %q", got["error"], tt.wantError)
+			}
+			if tt.wantStatus == http.StatusOK && got["status"] != string(domain.OrderStatusRejected) {
+				t.Errorf("status = %v, want %s", got["status"], domain.OrderStatusRejected)
+			}
+			if tt.path == "not-a-uuid" && orders.CallCount("RejectOrder") != 0 {
+				t.Error("RejectOrder called for an invalid order ID")
+			}
+		})
+	}
+}
+
+func TestHandleRejectOrderDryRun(t *testing.T) {
+	orderID := uuid.New()
+	orders := servicemock.NewOrderService(t)
+	orders.PlanRejectOrderFunc = func(ctx context.Context, id uuid.UUID, code domain.RejectionCode, reason string) (*service.TransitionPlan, error) {
+		return &service.TransitionPlan{
+			OrderID: id,
+			From:    domain.OrderStatusPendingConfirmation,
+			To:      domain.OrderStatusRejected,
+			Effects: []service.TransitionEffect{{Kind: service.EffectStatus, Description: "PENDING_CONFIRMATION -> REJECTED"}},
+		}, nil
+	}
+	repos := &repository.Repositories{
+		SupplierOrder: &fakeOrders{orders: map[uuid.UUID]*domain.SupplierOrder{
+			orderID: {ID: orderID, Status: domain.OrderStatusPendingConfirmation},
+		}},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/admin/orders/:id/reject", func(c *gin.Context) {
+		c.Set(middleware.PartnerContextKey, &domain.Partner{ID: uuid.New()})
+	}, HandleRejectOrder(&service.Services{Orders: orders}, repos, zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	body := `{"code": "OUT_OF_STOCK", "reason": "gone"}`
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/orders/"+orderID.String()+"/reject?dry_run=true", strings.NewReader(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	got := decodeBody(t, w)
+	if got["dry_run"] != true || got["to"] != string(domain.OrderStatusRejected) {
+		t.Errorf("response = %v, want a dry run to REJECTED", got)
+	}
+	if effects, _ := got["effects"].([]interface{}); len(effects) != 1 {
+		t.Errorf("effects = %v, want 1", got["effects"])
+	}
+	if orders.CallCount("RejectOrder") != 0 {
+		t.Error("RejectOrder called for a dry run")
+	}
+}
+
+func TestHandleBulkRejectOrders(t *testing.T) {
+	rejected, shipped := uuid.New(), uuid.New()
+	stored := &domain.SupplierOrder{ID: rejected, Status: domain.OrderStatusPendingConfirmation, UpdatedAt: time.Now()}
+	etag := middleware.OrderETag(stored)
+
+	tests := []struct {
+		name         string
+		body         string
+		err          error
+		wantStatus   int
+		wantRejected float64
+		wantResults  []string
+		wantCalls    int
+	}{
+		{
+			name:         "per-order results",
+			body:         fmt.Sprintf(`{"order_ids": [%q, %q], "code": "OUT_OF_STOCK"}`, rejected, shipped),
+			wantStatus:   http.StatusOK,
+			wantRejected: 1,
+			wantResults:  []string{service.BulkRejectRejected, service.BulkRejectInvalidTransition},
+			wantCalls:    1,
+		},
+		{
+			name:         "current ETag",
+			body:         fmt.Sprintf(`{"order_ids": [%q, %q], "etags": {%q: %q}, "code": "OUT_OF_STOCK"}`, rejected, shipped, rejected, etag),
+			wantStatus:   http.StatusOK,
+			wantRejected: 1,
+			wantResults:  []string{service.BulkRejectRejected, service.BulkRejectInvalidTransition},
+			wantCalls:    1,
+		},
+		{
+			name:         "stale ETag",
+			body:         fmt.Sprintf(`{"order_ids": [%q, %q], "etags": {%q: "\"stale\""}, "code": "OUT_OF_STOCK"}`, shipped, rejected, rejected),
+			wantStatus:   http.StatusOK,
+			wantRejected: 0,
+			wantResults:  []string{service.BulkRejectInvalidTransition, service.BulkRejectPreconditionFailed},
+			wantCalls:    1,
+		},
+		{
+			name:       "invalid order ID",
+			body:       fmt.Sprintf(`{"order_ids": [%q, "not-a-uuid"], "code": "OUT_OF_STOCK"}`, rejected),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "no orders",
+			body:       `{"order_ids": [], "code": "OUT_OF_STOCK"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid code",
+			body:       fmt.Sprintf(`{"order_ids": [%q], "code": "SOLD"}`, rejected),
+			err:        &errors.ErrValidation{Message: "validation failed", Fields: map[string]string{"code": "unknown rejection code"}},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := servicemock.NewOrderService(t)
+			orders.RejectOrdersFunc = func(ctx context.Context, ids []uuid.UUID, code domain.RejectionCode, reason string) ([]service.BulkRejectResult, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				var results []service.BulkRejectResult
+				for _, id := range ids {
+					if id == shipped {
+						results = append(results, service.BulkRejectResult{OrderID: id, Result: service.BulkRejectInvalidTransition, Error: "invalid state transition from SHIPPED to REJECTED"})
+						continue
+					}
+					results = append(results, service.BulkRejectResult{OrderID: id, Result: service.BulkRejectRejected})
+				}
+				return results, nil
+			}
+			repos := &repository.Repositories{
+				SupplierOrder: &fakeOrders{orders: map[uuid.UUID]*domain.SupplierOrder{rejected: stored}},
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/admin/orders/bulk-reject", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, &domain.Partner{ID: uuid.New()})
+			}, HandleBulkRejectOrders(&config.Config{}, &service.Services{Orders: orders}, repos, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/orders/bulk-reject", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if n := orders.CallCount("RejectOrders"); n != tt.wantCalls {
+				t.Errorf("RejectOrders called %d times, want %d", n, tt.wantCalls)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			got := decodeBody(t, w)
+			wantFailed := float64(len(tt.wantResults)) - tt.wantRejected
+			if got["rejected"] != tt.wantRejected || got["failed"] != wantFailed {
+				t.Errorf("rejected = %v, failed = %v; want %v and %v", got["rejected"], got["failed"], tt.wantRejected, wantFailed)
+			}
+			results, _ := got["results"].([]interface{})
+			if len(results) != len(tt.wantResults) {
+				t.Fatalf("results = %v, want %d", got["results"], len(tt.wantResults))
+			}
+			// Results keep the request order, orders left alone included
+			for i, want := range tt.wantResults {
+				result := results[i].(map[string]interface{})
+				if result["result"] != want {
+					t.Errorf("results[%d] = %v, want %s", i, result, want)
+				}
+				if want == service.BulkRejectPreconditionFailed && result["etag"] != etag {
+					t.Errorf("results[%d] etag = %v, want the current %s", i, result["etag"], etag)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleReassignOrder(t *testing.T) {
+	orderID, toPartnerID, adminID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "reassigned",
+			body:       fmt.Sprintf(`{"partner_id": %q, "reason": "Submitted from the test account"}`, toPartnerID),
+			wantStatus: http.StatusOK,
+			wantCalls:  1,
+		},
+		{
+			name:       "missing reason",
+			body:       fmt.Sprintf(`{"partner_id": %q}`, toPartnerID),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid partner ID",
+			body:       `{"partner_id": "not-a-uuid", "reason": "wrong account"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "inactive partner",
+			body:       fmt.Sprintf(`{"partner_id": %q, "reason": "wrong account"}`, toPartnerID),
+			err:        &errors.ErrValidation{Message: "validation failed", Fields: map[string]string{"partner_id": "partner is inactive"}},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+		{
+			name:       "same partner",
+			body:       fmt.Sprintf(`{"partner_id": %q, "reason": "wrong account"}`, toPartnerID),
+			err:        &errors.ErrConflict{Message: "order already belongs to this partner"},
+			wantStatus: http.StatusConflict,
+			wantCalls:  1,
+		},
+		{
+			name:       "order not found",
+			body:       fmt.Sprintf(`{"partner_id": %q, "reason": "wrong account"}`, toPartnerID),
+			err:        &errors.ErrNotFound{Resource: "supplier_order", ID: orderID.String()},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := servicemock.NewOrderService(t)
+			orders.ReassignOrderFunc = func(ctx context.Context, id, to uuid.UUID, reason string, admin uuid.UUID) (*domain.SupplierOrder, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				if id != orderID || to != toPartnerID || admin != adminID {
+					t.Errorf("ReassignOrder(%s, %s, admin %s), want (%s, %s, admin %s)", id, to, admin, orderID, toPartnerID, adminID)
+				}
+				return &domain.SupplierOrder{ID: id, PartnerID: to, Status: domain.OrderStatusConfirmed}, nil
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/admin/orders/:id/reassign", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, &domain.Partner{ID: adminID})
+			}, HandleReassignOrder(&service.Services{Orders: orders}, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/orders/"+orderID.String()+"/reassign", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if n := orders.CallCount("ReassignOrder"); n != tt.wantCalls {
+				t.Errorf("ReassignOrder called %d times, want %d", n, tt.wantCalls)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if got := decodeBody(t, w); got["partner_id"] != toPartnerID.String() {
+					t.Errorf("partner_id = %v, want %s", got["partner_id"], toPartnerID)
+				}
+			}
+		})
+	}
+}
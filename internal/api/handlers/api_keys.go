@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// IssueAPIKeyRequest represents a request to issue a partner a new API key. APIKey is only
+// accepted with API_KEY_LEGACY_IMPORT, for keys migrated from another system.
+type IssueAPIKeyRequest struct {
+	APIKey string `json:"api_key"`
+}
+
+// HandleIssueAPIKey handles POST /v1/admin/partners/:id/api-key
+// The new key is generated server-side and shown only in this response.
+func HandleIssueAPIKey(cfg *config.Config, services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, _ := middleware.GetPartnerFromContext(c)
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// The body is optional
+		var req IssueAPIKeyRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": bindingErrors(err),
+				})
+				return
+			}
+		}
+		if req.APIKey != "" && !cfg.API.LegacyKeyImport {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(map[string]string{"api_key": "keys are generated by the server; supplied keys need API_KEY_LEGACY_IMPORT"}),
+			})
+			return
+		}
+
+		actor := fmt.Sprintf("partner:%s", admin.ID)
+		apiKey := req.APIKey
+		var err error
+		if apiKey != "" {
+			err = services.Onboarding.ImportAPIKey(c.Request.Context(), actor, partner, apiKey)
+		} else {
+			apiKey, err = services.Onboarding.IssueAPIKey(c.Request.Context(), actor, partner)
+		}
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to issue API key", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue API key"})
+			return
+		}
+
+		response := gin.H{
+			"partner_id":     partner.ID.String(),
+			"api_key_prefix": partner.APIKeyPrefix,
+			"imported":       req.APIKey != "",
+		}
+		// Imported keys are known to the operator already and are not echoed
+		if req.APIKey == "" {
+			response["api_key"] = apiKey
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusCreated, response)
+	}
+}
+
+// RevokeAPIKeyRequest represents a request to revoke a partner's API key
+type RevokeAPIKeyRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// HandleRevokeAPIKey handles POST /v1/admin/partners/:id/api-key/revoke
+func HandleRevokeAPIKey(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, _ := middleware.GetPartnerFromContext(c)
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Parse request
+		var req RevokeAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		revoked, err := services.Onboarding.RevokeAPIKey(c.Request.Context(), fmt.Sprintf("partner:%s", admin.ID), partner, req.Reason)
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrValidation:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
+			default:
+				logger.Error("Failed to revoke API key", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke API key"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, revokedAPIKeyResponse(revoked))
+	}
+}
+
+// HandleListRevokedAPIKeys handles GET /v1/admin/partners/:id/api-key/revocations
+func HandleListRevokedAPIKeys(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		keys, err := repos.RevokedAPIKey.ListByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			logger.Error("Failed to list revoked API keys", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		revocations := make([]gin.H, len(keys))
+		for i, revoked := range keys {
+			revocations[i] = revokedAPIKeyResponse(revoked)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":  partner.ID.String(),
+			"revocations": revocations,
+		})
+	}
+}
+
+// revokedAPIKeyResponse describes a revocation; the key's hash is not exposed
+func revokedAPIKeyResponse(revoked *domain.RevokedAPIKey) gin.H {
+	return gin.H{
+		"partner_id": revoked.PartnerID.String(),
+		"reason":     revoked.Reason,
+		"revoked_by": revoked.RevokedBy,
+		"revoked_at": formatTimestamp(revoked.RevokedAt),
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleGetOrderApprovals handles GET /v1/admin/orders/:id/approvals
+func HandleGetOrderApprovals(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		approvals, err := services.Approvals.Status(c.Request.Context(), order.ID)
+		if err != nil {
+			logger.Error("Failed to get order approvals", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":        order.ID.String(),
+			"status":    order.Status,
+			"approvals": buildApprovalResponse(approvals),
+		})
+	}
+}
+
+// buildApprovalResponse describes an order's progress through the two-person rule
+func buildApprovalResponse(status *service.ApprovalStatus) gin.H {
+	approvedBy := make([]gin.H, len(status.Approvals))
+	for i, approval := range status.Approvals {
+		approvedBy[i] = gin.H{
+			"admin_partner_id": approval.AdminID.String(),
+			"approved_at":      formatTimestamp(approval.ApprovedAt),
+		}
+	}
+	return gin.H{
+		"required":    status.Required,
+		"received":    len(status.Approvals),
+		"complete":    status.Complete(),
+		"approved_by": approvedBy,
+	}
+}
+
+// transitionPlanResponse renders what a dry-run transition would do
+func transitionPlanResponse(plan *service.TransitionPlan) gin.H {
+	response := gin.H{
+		"dry_run": true,
+		"id":      plan.OrderID.String(),
+		"from":    plan.From,
+		"to":      plan.To,
+		"effects": plan.Effects,
+	}
+	if plan.Approvals != nil {
+		response["approvals"] = buildApprovalResponse(plan.Approvals)
+	}
+	if plan.Carrier != "" {
+		response["tracking_carrier"] = plan.Carrier
+		response["tracking_number"] = plan.TrackingNumber
+		response["tracking_url"] = plan.TrackingURL
+	}
+	return response
+}
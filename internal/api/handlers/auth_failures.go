@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/authguard"
+	"github.com/jafarshop/b2bapi/internal/config"
+)
+
+// HandleGetAuthFailures handles GET /v1/admin/auth-failures
+// Returns the failed authentication counters and current lockouts (format=prometheus for metrics)
+func HandleGetAuthFailures(cfg *config.Config, guard *authguard.Guard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		stats := guard.Stats()
+		if c.Query("format") == "prometheus" {
+			c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(formatAuthFailureMetrics(stats)))
+			return
+		}
+
+		lockouts := make([]gin.H, len(stats.Active))
+		for i, lockout := range stats.Active {
+			lockouts[i] = gin.H{
+				"scope":        lockout.Scope,
+				"value":        lockout.Value,
+				"lockouts":     lockout.Lockouts,
+				"locked_until": formatTimestamp(lockout.LockedUntil),
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"max_failures":    cfg.AuthGuard.MaxFailures,
+			"window":          cfg.AuthGuard.Window.String(),
+			"failures_total":  stats.Failures,
+			"rejected_total":  stats.Rejected,
+			"lockouts_total":  stats.Lockouts,
+			"active_lockouts": lockouts,
+		})
+	}
+}
+
+// formatAuthFailureMetrics renders the auth guard counters in the Prometheus text exposition format
+func formatAuthFailureMetrics(stats authguard.Stats) string {
+	var b strings.Builder
+
+	b.WriteString("# HELP b2b_auth_failures_total Failed API key authentications since the process started.\n")
+	b.WriteString("# TYPE b2b_auth_failures_total counter\n")
+	fmt.Fprintf(&b, "b2b_auth_failures_total %d\n", stats.Failures)
+
+	b.WriteString("# HELP b2b_auth_rejected_total Requests rejected because their source was locked out.\n")
+	b.WriteString("# TYPE b2b_auth_rejected_total counter\n")
+	fmt.Fprintf(&b, "b2b_auth_rejected_total %d\n", stats.Rejected)
+
+	b.WriteString("# HELP b2b_auth_lockouts_total Sources locked out after too many failures.\n")
+	b.WriteString("# TYPE b2b_auth_lockouts_total counter\n")
+	fmt.Fprintf(&b, "b2b_auth_lockouts_total %d\n", stats.Lockouts)
+
+	active := make(map[string]int)
+	for _, lockout := range stats.Active {
+		active[lockout.Scope]++
+	}
+	b.WriteString("# HELP b2b_auth_active_lockouts Sources locked out now, by scope.\n")
+	b.WriteString("# TYPE b2b_auth_active_lockouts gauge\n")
+	for _, scope := range []string{authguard.ScopeIP, authguard.ScopeKeyPrefix} {
+		fmt.Fprintf(&b, "b2b_auth_active_lockouts{scope=%s} %d\n", promLabel(scope), active[scope])
+	}
+
+	return b.String()
+}
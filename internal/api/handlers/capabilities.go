@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleGetCapabilities handles GET /v1/capabilities. It describes the optional features as
+// they apply to the calling partner, so integrations can adapt without asking us.
+func HandleGetCapabilities(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		eventTypes, err := repos.WebhookSubscription.ListByPartnerID(ctx, partner.ID)
+		if err != nil {
+			logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		catalog, err := repos.PartnerCatalog.ListByPartnerID(ctx, partner.ID)
+		if err != nil {
+			logger.Error("Failed to list partner catalog", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		digestSubscribed := true
+		if _, err := repos.DigestSubscription.GetByPartnerID(ctx, partner.ID); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); !ok {
+				logger.Error("Failed to get digest subscription", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			digestSubscribed = false
+		}
+
+		webhooks := buildWebhookSubscriptionsResponse(eventTypes)
+		webhooks["enabled"] = partner.WebhookURL != nil && *partner.WebhookURL != ""
+		webhooks["signed"] = partner.WebhookSecret != nil
+
+		c.JSON(http.StatusOK, gin.H{
+			"api_version": middleware.GetAPIVersion(c),
+			"features": gin.H{
+				// Carts are processed while the request waits; the response has the order
+				"async_submission": false,
+				"cart_validation":  true,
+				"idempotency_keys": true,
+				"webhooks":         webhooks,
+				"catalog_feed": gin.H{
+					"enabled": true,
+					// Without SKUs assigned to the partner the feed lists every active SKU
+					"partner_catalog": len(catalog) > 0,
+				},
+				// Nothing is sent to Shopify: orders are accepted but not fulfilled
+				"sandbox": cfg.Shopify.DryRun,
+				"digest": gin.H{
+					"enabled":    cfg.Digest.Enabled,
+					"subscribed": digestSubscribed,
+				},
+				"low_stock_alerts": cfg.LowStock.Interval > 0,
+			},
+			"rate_limits": gin.H{
+				// Requests are not rate limited; repeated failed authentications lock the caller out
+				"requests": nil,
+				"auth_failures": gin.H{
+					"enabled":         cfg.AuthGuard.MaxFailures > 0,
+					"max_failures":    cfg.AuthGuard.MaxFailures,
+					"window_seconds":  int(cfg.AuthGuard.Window.Seconds()),
+					"lockout_seconds": int(cfg.AuthGuard.Lockout.Seconds()),
+				},
+			},
+			"limits": gin.H{
+				"status_batch_size": MaxStatusBatchSize,
+			},
+			"currencies":    []string{cfg.Shopify.Currency},
+			"locales":       []domain.Locale{domain.LocaleEnglish, domain.LocaleArabic},
+			"payment_terms": partner.PaymentTerms,
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/carriers"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleListCarriers handles GET /v1/admin/carriers
+func HandleListCarriers(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		registry := carriers.NewRegistry(cfg.Carriers)
+		list := registry.Carriers()
+		carrierResponses := make([]gin.H, len(list))
+		for i, carrier := range list {
+			carrierResponses[i] = gin.H{
+				"code":                  carrier.Code,
+				"name":                  carrier.Name,
+				"tracking_url_template": carrier.TrackingURLTemplate,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"carriers":      carrierResponses,
+			"allow_unknown": registry.AllowUnknown(),
+		})
+	}
+}
+
+// HandleGetShippingQuotes handles GET /v1/admin/orders/:id/shipping-quotes
+func HandleGetShippingQuotes(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		parcel, results, err := services.Shipping.QuoteOrder(c.Request.Context(), orderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to quote shipping", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		quoteResponses := make([]gin.H, len(results))
+		for i, result := range results {
+			rates := make([]gin.H, len(result.Quotes))
+			for j, quote := range result.Quotes {
+				rates[j] = gin.H{
+					"service":        quote.Service,
+					"amount":         quote.Amount,
+					"currency":       quote.Currency,
+					"estimated_days": quote.EstimatedDays,
+				}
+			}
+			quoteResponses[i] = gin.H{
+				"carrier": result.Carrier,
+				"rates":   rates,
+			}
+			if result.Error != "" {
+				quoteResponses[i]["error"] = result.Error
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":     orderID.String(),
+			"parcel": buildParcelResponse(parcel),
+			"quotes": quoteResponses,
+		})
+	}
+}
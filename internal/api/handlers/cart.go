@@ -10,8 +10,10 @@ import (
 	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/schemas"
 	"github.com/jafarshop/b2bapi/internal/api/middleware"
 	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
 // CartSubmitRequest represents the cart submission payload
@@ -35,14 +37,17 @@ type CartItem struct {
 type CustomerInfo struct {
 	Name  string  `json:"name" binding:"required"`
 	Phone *string `json:"phone,omitempty"`
+	Email *string `json:"email,omitempty" binding:"omitempty,email,max=255"`
 }
 
 type ShippingAddress struct {
 	Street     string  `json:"street" binding:"required"`
+	Address2   *string `json:"address2,omitempty"`
 	City       string  `json:"city" binding:"required"`
 	State      *string `json:"state,omitempty"`
 	PostalCode string  `json:"postal_code" binding:"required"`
 	Country    string  `json:"country" binding:"required"`
+	Notes      *string `json:"notes,omitempty" binding:"omitempty,max=500"`
 }
 
 type CartTotals struct {
@@ -55,10 +60,15 @@ type CartTotals struct {
 // CartSubmitResponse represents the response
 type CartSubmitResponse struct {
 	SupplierOrderID string                `json:"supplier_order_id"`
+	Reference       *string               `json:"reference,omitempty"`
 	Status          domain.OrderStatus    `json:"status"`
+	// Warnings are problems found in the cart that did not stop the order (e.g. tax_mismatch)
+	Warnings        []service.CartWarning `json:"warnings,omitempty"`
+	// Duplicate is set when the cart was submitted before and this is the order it created
+	Duplicate       bool                  `json:"duplicate,omitempty"`
 }
 
-func HandleCartSubmit(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+func HandleCartSubmit(cfg *config.Config, services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get partner from context
 		partner, ok := middleware.GetPartnerFromContext(c)
@@ -85,8 +95,10 @@ func HandleCartSubmit(cfg *config.Config, repos *repository.Repositories, logger
 				return
 			}
 
+			c.Header("Location", orderLocation(c, order.ID))
 			c.JSON(http.StatusOK, CartSubmitResponse{
 				SupplierOrderID: order.ID.String(),
+				Reference:       order.Reference,
 				Status:          order.Status,
 			})
 			return
@@ -94,71 +106,56 @@ func HandleCartSubmit(cfg *config.Config, repos *repository.Repositories, logger
 
 		// Parse request - use service types
 		var req service.CartSubmitRequest
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusUnprocessableEntity, gin.H{
-				"error":   "validation failed",
-				"details": err.Error(),
-			})
+		if !bindJSONSchema(c, schemas.Cart, &req) {
 			return
 		}
 
-		// Check for supplier SKUs
-		skuService := service.NewSKUService(repos, logger)
-		hasSupplierSKU, supplierItems, err := skuService.CheckCartForSupplierSKUs(
-			c.Request.Context(),
-			req.Items, // []service.CartItem
-		)
-		if err != nil {
-			logger.Error("Failed to check SKUs", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
-			return
-		}
-
-		// If no supplier SKUs, return 204
-		if !hasSupplierSKU {
-			c.Status(http.StatusNoContent)
-			return
+		// Without an idempotency key, a cart submitted again shortly after gets its first order
+		if key, _, _, _ := middleware.GetIdempotencyInfo(c); key == "" && cfg.Duplicates.SubmissionWindow > 0 {
+			existing, err := services.Carts.CheckDuplicateSubmission(c.Request.Context(), partner, req)
+			if err != nil {
+				// Not worth failing the submission over
+				logger.Warn("Failed to check for a duplicate submission", zap.Error(err))
+			} else if existing != nil {
+				c.Header("Location", orderLocation(c, existing.ID))
+				c.JSON(http.StatusOK, CartSubmitResponse{
+					SupplierOrderID: existing.ID.String(),
+					Reference:       existing.Reference,
+					Status:          existing.Status,
+					Duplicate:       true,
+					Warnings: []service.CartWarning{{
+						Code:    service.CartWarningDuplicateSubmission,
+						Message: "the same cart was submitted within the duplicate window; this is the order it created",
+					}},
+				})
+				return
+			}
 		}
 
-		// Create order
-		orderService := service.NewOrderService(repos, logger)
-		order, err := orderService.CreateOrderFromCart(c.Request.Context(), partner.ID, req, supplierItems)
+		// Check for supplier SKUs, create the order and its Shopify draft order
+		order, hasSupplierSKU, err := services.Carts.SubmitCart(c.Request.Context(), partner, req)
 		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   e.Error(),
+					"details": fieldErrors(e.Fields),
+				})
+				return
+			}
+			if !hasSupplierSKU {
+				logger.Error("Failed to check SKUs", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
 			logger.Error("Failed to create order", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create order"})
 			return
 		}
 
-		// Create Shopify draft order
-		// Get order items for draft order creation
-		orderItems, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), order.ID)
-		if err != nil {
-			logger.Error("Failed to get order items for draft order", zap.Error(err))
-			// Don't fail the request, draft order can be created later
-		} else {
-			shopifyService := service.NewShopifyService(cfg.Shopify, repos, logger)
-			draftOrderID, err := shopifyService.CreateDraftOrder(c.Request.Context(), order, orderItems, partner.Name)
-			if err != nil {
-				logger.Error("Failed to create Shopify draft order", zap.Error(err))
-				// Don't fail the request, draft order can be created later
-			} else {
-				// Update order with draft order ID
-				if err := repos.SupplierOrder.UpdateShopifyDraftOrderID(c.Request.Context(), order.ID, draftOrderID); err != nil {
-					logger.Warn("Failed to update order with draft order ID", zap.Error(err))
-				}
-				order.ShopifyDraftOrderID = &draftOrderID
-
-				// Complete draft order -> create a real Shopify Order (so it shows under Orders, not Drafts)
-				shopifyOrderID, err := shopifyService.CompleteDraftOrder(c.Request.Context(), draftOrderID)
-				if err != nil {
-					logger.Error("Failed to complete Shopify draft order", zap.Error(err))
-				} else {
-					if err := repos.SupplierOrder.UpdateShopifyOrderID(c.Request.Context(), order.ID, shopifyOrderID); err != nil {
-						logger.Warn("Failed to update order with Shopify order ID", zap.Error(err))
-					}
-					order.ShopifyOrderID = &shopifyOrderID
-				}
-			}
+		// If no supplier SKUs, return 204
+		if !hasSupplierSKU {
+			c.Status(http.StatusNoContent)
+			return
 		}
 
 		// Store idempotency key if provided
@@ -176,9 +173,61 @@ func HandleCartSubmit(cfg *config.Config, repos *repository.Repositories, logger
 			}
 		}
 
-		c.JSON(http.StatusOK, CartSubmitResponse{
+		response := CartSubmitResponse{
 			SupplierOrderID: order.ID.String(),
+			Reference:       order.Reference,
 			Status:          order.Status,
-		})
+		}
+		if warning := service.CheckCartTax(cfg.Tax, req); warning != nil {
+			response.Warnings = append(response.Warnings, *warning)
+		}
+
+		c.Header("Location", orderLocation(c, order.ID))
+		c.JSON(cartSubmitStatus(c, partner, order), response)
+	}
+}
+
+// cartSubmitStatus is 201 for a new order, or 202 when its Shopify draft order is still
+// pending (dry-run or a failed attempt). Partners on legacy status codes get 200 on /v1.
+func cartSubmitStatus(c *gin.Context, partner *domain.Partner, order *domain.SupplierOrder) int {
+	if partner.LegacyStatusCodes && middleware.GetAPIVersion(c) == "v1" {
+		return http.StatusOK
+	}
+	if order.ShopifyDraftOrderID == nil {
+		return http.StatusAccepted
+	}
+	return http.StatusCreated
+}
+
+// orderLocation is the partner URL of an order in the request's API version
+func orderLocation(c *gin.Context, orderID uuid.UUID) string {
+	return "/" + middleware.GetAPIVersion(c) + "/orders/" + orderID.String()
+}
+
+// HandleCartValidate handles POST /v1/carts/validate: the checks of cart submission
+// without creating an order
+func HandleCartValidate(cfg *config.Config, services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request - same payload as submission
+		var req service.CartSubmitRequest
+		if !bindJSONSchema(c, schemas.Cart, &req) {
+			return
+		}
+
+		result, err := services.Carts.ValidateCart(c.Request.Context(), partner, req)
+		if err != nil {
+			logger.Error("Failed to validate cart", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
 	}
 }
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/internal/service/servicemock"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+const testCart = `{
+	"partner_order_id": "ORD-1",
+	"items": [{"sku": "SUP-1", "title": "Widget", "price": 10, "quantity": 2}],
+	"customer": {"name": "Jane Doe"},
+	"shipping": {"street": "1 Main St", "city": "Amman", "postal_code": "11118", "country": "JO"},
+	"totals": {"subtotal": 20, "total": 20}
+}`
+
+// fakeOrders serves orders from a map; other SupplierOrderRepository methods are not expected
+type fakeOrders struct {
+	repository.SupplierOrderRepository
+	orders map[uuid.UUID]*domain.SupplierOrder
+}
+
+func (f *fakeOrders) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
+	order, ok := f.orders[id]
+	if !ok {
+		return nil, &errors.ErrNotFound{Resource: "order", ID: id.String()}
+	}
+	return order, nil
+}
+
+// fakeIdempotencyKeys records stored keys
+type fakeIdempotencyKeys struct {
+	repository.IdempotencyKeyRepository
+	created []*domain.IdempotencyKey
+}
+
+func (f *fakeIdempotencyKeys) Create(ctx context.Context, key *domain.IdempotencyKey) error {
+	f.created = append(f.created, key)
+	return nil
+}
+
+type cartTest struct {
+	cfg   *config.Config
+	carts *servicemock.CartService
+	keys  *fakeIdempotencyKeys
+	repos *repository.Repositories
+	// context values set by the auth and idempotency middleware
+	values map[string]interface{}
+}
+
+func newCartTest(t *testing.T) *cartTest {
+	keys := &fakeIdempotencyKeys{}
+	return &cartTest{
+		cfg:   &config.Config{},
+		carts: servicemock.NewCartService(t),
+		keys:  keys,
+		repos: &repository.Repositories{
+			SupplierOrder:  &fakeOrders{orders: map[uuid.UUID]*domain.SupplierOrder{}},
+			IdempotencyKey: keys,
+		},
+		values: map[string]interface{}{
+			middleware.PartnerContextKey: &domain.Partner{ID: uuid.New(), Name: "Partner"},
+		},
+	}
+}
+
+func (ct *cartTest) submit(body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/carts/submit", func(c *gin.Context) {
+		for key, value := range ct.values {
+			c.Set(key, value)
+		}
+	}, HandleCartSubmit(ct.cfg, &service.Services{Carts: ct.carts}, ct.repos, zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/carts/submit", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %q", w.Body.String())
+	}
+	return body
+}
+
+func TestHandleCartSubmitCreatesOrder(t *testing.T) {
+	ct := newCartTest(t)
+	ct.values["idempotency_key"] = "key-1"
+	ct.values["idempotency_request_hash"] = "hash-1"
+
+	draftID := int64(42)
+	order := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusPendingConfirmation, ShopifyDraftOrderID: &draftID}
+	ct.carts.SubmitCartFunc = func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+		if req.PartnerOrderID != "ORD-1" || len(req.Items) != 1 || req.Items[0].Quantity != 2 {
+			t.Errorf("SubmitCart() got request %+v", req)
+		}
+		return order, true, nil
+	}
+
+	w := ct.submit(testCart)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if got, want := w.Header().Get("Location"), "/v1/orders/"+order.ID.String(); got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+	if got := decodeBody(t, w)["supplier_order_id"]; got != order.ID.String() {
+		t.Errorf("supplier_order_id = %v, want %s", got, order.ID)
+	}
+	if len(ct.keys.created) != 1 || ct.keys.created[0].Key != "key-1" || ct.keys.created[0].SupplierOrderID != order.ID {
+		t.Errorf("stored idempotency keys = %+v, want key-1 for the order", ct.keys.created)
+	}
+}
+
+func TestHandleCartSubmitDraftPending(t *testing.T) {
+	ct := newCartTest(t)
+	ct.carts.SubmitCartFunc = func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+		return &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusPendingConfirmation}, true, nil
+	}
+
+	if w := ct.submit(testCart); w.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+}
+
+func TestHandleCartSubmitNoSupplierSKUs(t *testing.T) {
+	ct := newCartTest(t)
+	ct.values["idempotency_key"] = "key-1"
+	ct.carts.SubmitCartFunc = func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+		return nil, false, nil
+	}
+
+	w := ct.submit(testCart)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", w.Body.String())
+	}
+	if len(ct.keys.created) != 0 {
+		t.Errorf("stored idempotency keys = %+v, want none", ct.keys.created)
+	}
+}
+
+func TestHandleCartSubmitIdempotentReplay(t *testing.T) {
+	ct := newCartTest(t)
+	reference := "B2B-2024-000123"
+	order := &domain.SupplierOrder{ID: uuid.New(), Reference: &reference, Status: domain.OrderStatusConfirmed}
+	ct.repos.SupplierOrder.(*fakeOrders).orders[order.ID] = order
+	ct.values["idempotency_existing_order_id"] = order.ID.String()
+
+	// No SubmitCartFunc: the cart must not be submitted again
+	w := ct.submit(testCart)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	body := decodeBody(t, w)
+	if body["supplier_order_id"] != order.ID.String() || body["reference"] != reference || body["status"] != string(domain.OrderStatusConfirmed) {
+		t.Errorf("body = %v, want the existing order", body)
+	}
+	if n := ct.carts.CallCount("SubmitCart"); n != 0 {
+		t.Errorf("SubmitCart called %d times, want 0", n)
+	}
+}
+
+func TestHandleCartSubmitDuplicate(t *testing.T) {
+	ct := newCartTest(t)
+	ct.cfg.Duplicates.SubmissionWindow = 10 * time.Minute
+	order := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusPendingConfirmation}
+	ct.carts.CheckDuplicateSubmissionFunc = func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, error) {
+		return order, nil
+	}
+
+	// No SubmitCartFunc: the cart must not be submitted again
+	w := ct.submit(testCart)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	body := decodeBody(t, w)
+	if body["supplier_order_id"] != order.ID.String() || body["duplicate"] != true {
+		t.Errorf("body = %v, want the existing order flagged as a duplicate", body)
+	}
+	warnings, _ := body["warnings"].([]interface{})
+	if len(warnings) != 1 || warnings[0].(map[string]interface{})["code"] != service.CartWarningDuplicateSubmission {
+		t.Errorf("warnings = %v, want duplicate_submission", body["warnings"])
+	}
+
+	// With an idempotency key the key decides
+	ct = newCartTest(t)
+	ct.cfg.Duplicates.SubmissionWindow = 10 * time.Minute
+	ct.values["idempotency_key"] = "key-1"
+	ct.carts.SubmitCartFunc = func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+		return order, true, nil
+	}
+	if w := ct.submit(testCart); w.Code != http.StatusAccepted {
+		t.Errorf("with an idempotency key: status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+}
+
+func TestHandleCartSubmitErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		hasSupplierSKU bool
+		err            error
+		wantStatus     int
+		wantError      string
+	}{
+		{
+			name:       "schema violation",
+			body:       `{"partner_order_id": "ORD-1"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantError:  "validation failed",
+		},
+		{
+			name:       "service validation",
+			body:       testCart,
+			err:        &errors.ErrValidation{Message: "validation failed", Fields: map[string]string{"customer.phone": "invalid"}},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantError:  "validation failed",
+		},
+		{
+			name:       "SKU check failure",
+			body:       testCart,
+			err:        fmt.Errorf("database unavailable"),
+			wantStatus: http.StatusInternalServerError,
+			wantError:  "internal error",
+		},
+		{
+			name:           "order creation failure",
+			body:           testCart,
+			hasSupplierSKU: true,
+			err:            fmt.Errorf("database unavailable"),
+			wantStatus:     http.StatusInternalServerError,
+			wantError:      "failed to create order",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := newCartTest(t)
+			ct.carts.SubmitCartFunc = func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+				return nil, tt.hasSupplierSKU, tt.err
+			}
+
+			w := ct.submit(tt.body)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			body := decodeBody(t, w)
+			if body["error"] != tt.wantError {
+				t.Errorf("error = %v, want %q", body["error"], tt.wantError)
+			}
+			if tt.wantStatus == http.StatusUnprocessableEntity && body["details"] == nil {
+				t.Error("details missing from validation error")
+			}
+		})
+	}
+}
+
+func TestHandleCartSubmitUnauthorized(t *testing.T) {
+	ct := newCartTest(t)
+	delete(ct.values, middleware.PartnerContextKey)
+
+	if w := ct.submit(testCart); w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
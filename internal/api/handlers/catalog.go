@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// AddCatalogSKUsRequest represents add SKUs to partner catalog request
+type AddCatalogSKUsRequest struct {
+	SKUs []string `json:"skus" binding:"required,min=1"`
+}
+
+// HandleGetPartnerCatalog handles GET /v1/admin/partners/:id/catalog
+func HandleGetPartnerCatalog(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		catalog, err := repos.PartnerCatalog.ListByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			logger.Error("Failed to list partner catalog", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, catalogResponse(partner, catalog))
+	}
+}
+
+// HandleGetCatalog handles GET /v1/catalog. It lists the SKUs the partner may order with the
+// product details cached from Shopify: the partner's catalog, or every active SKU when unrestricted.
+func HandleGetCatalog(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		catalog, err := repos.PartnerCatalog.ListByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			logger.Error("Failed to list partner catalog", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if len(catalog) == 0 {
+			catalog, err = repos.SKUMapping.GetAllActive(c.Request.Context())
+			if err != nil {
+				logger.Error("Failed to list active SKU mappings", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+
+		items := make([]gin.H, 0, len(catalog))
+		for _, mapping := range catalog {
+			if !mapping.IsActive {
+				continue
+			}
+			items = append(items, catalogItemResponse(mapping))
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": items})
+	}
+}
+
+// HandleAddPartnerCatalogSKUs handles POST /v1/admin/partners/:id/catalog
+func HandleAddPartnerCatalogSKUs(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Parse request
+		var req AddCatalogSKUsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		unknown := make(map[string]string)
+		var added []string
+		for _, sku := range req.SKUs {
+			if err := repos.PartnerCatalog.Add(c.Request.Context(), partner.ID, sku); err != nil {
+				if _, ok := err.(*errors.ErrNotFound); ok {
+					unknown[sku] = "no SKU mapping exists for this SKU"
+					continue
+				}
+				logger.Error("Failed to add SKU to partner catalog", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			added = append(added, sku)
+		}
+
+		// SKUs added before an unknown one stay added, so the partner hears about them either way
+		if len(added) > 0 {
+			services.Webhooks.NotifyPartnerEvent(partner, service.WebhookEventCatalogUpdated, map[string]interface{}{
+				"added": added,
+			})
+		}
+
+		if len(unknown) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "some SKUs could not be added",
+				"details": unknown,
+			})
+			return
+		}
+
+		catalog, err := repos.PartnerCatalog.ListByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			logger.Error("Failed to list partner catalog", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, catalogResponse(partner, catalog))
+	}
+}
+
+// HandleRemovePartnerCatalogSKU handles DELETE /v1/admin/partners/:id/catalog/:sku
+// An empty catalog means unrestricted, so removing the last SKU needs ?unrestrict=true
+func HandleRemovePartnerCatalogSKU(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		catalog, err := repos.PartnerCatalog.ListByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			logger.Error("Failed to list partner catalog", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if len(catalog) == 1 && catalog[0].SKU == c.Param("sku") && c.Query("unrestrict") != "true" {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "removing the last SKU would let the partner order every active SKU; pass unrestrict=true to lift the restriction",
+			})
+			return
+		}
+
+		if err := repos.PartnerCatalog.Remove(c.Request.Context(), partner.ID, c.Param("sku")); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "SKU not in partner catalog"})
+				return
+			}
+			logger.Error("Failed to remove SKU from partner catalog", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		services.Webhooks.NotifyPartnerEvent(partner, service.WebhookEventCatalogUpdated, map[string]interface{}{
+			"removed": []string{c.Param("sku")},
+		})
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// loadPartnerParam authorizes the caller and loads the partner from the :id path param.
+// It writes the error response itself and returns false on failure.
+func loadPartnerParam(c *gin.Context, repos *repository.Repositories, logger *zap.Logger) (*domain.Partner, bool) {
+	// Get partner from context (for now, admin uses same auth)
+	if _, ok := middleware.GetPartnerFromContext(c); !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+
+	partnerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid partner ID"})
+		return nil, false
+	}
+
+	partner, err := repos.Partner.GetByID(c.Request.Context(), partnerID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "partner not found"})
+			return nil, false
+		}
+		logger.Error("Failed to get partner", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+
+	return partner, true
+}
+
+func catalogResponse(partner *domain.Partner, catalog []*domain.SKUMapping) gin.H {
+	skus := make([]gin.H, len(catalog))
+	for i, mapping := range catalog {
+		skus[i] = catalogItemResponse(mapping)
+	}
+
+	return gin.H{
+		"partner_id": partner.ID.String(),
+		// An empty catalog means the partner may order every active SKU
+		"restricted": len(catalog) > 0,
+		"skus":       skus,
+	}
+}
+
+func catalogItemResponse(mapping *domain.SKUMapping) gin.H {
+	imageURLs := mapping.ImageURLs
+	if imageURLs == nil {
+		imageURLs = []string{}
+	}
+	return gin.H{
+		"sku":                mapping.SKU,
+		"shopify_product_id": mapping.ShopifyProductID,
+		"shopify_variant_id": mapping.ShopifyVariantID,
+		"product_title":      mapping.ProductTitle,
+		"variant_title":      mapping.VariantTitle,
+		"vendor":             mapping.Vendor,
+		"product_type":       mapping.ProductType,
+		"barcode":            mapping.Barcode,
+		"image_url":          mapping.ImageURL,
+		"image_urls":         imageURLs,
+		"weight_grams":       mapping.WeightGrams,
+		"is_active":          mapping.IsActive,
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/internal/service/servicemock"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+func (f *fakePartners) GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error) {
+	for _, partner := range f.partners {
+		if partner.ID == id {
+			return partner, nil
+		}
+	}
+	return nil, &errors.ErrNotFound{Resource: "partner", ID: id.String()}
+}
+
+// fakeCatalog keeps one partner's catalog in memory
+type fakeCatalog struct {
+	repository.PartnerCatalogRepository
+	skus []string
+}
+
+func (f *fakeCatalog) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.SKUMapping, error) {
+	catalog := make([]*domain.SKUMapping, len(f.skus))
+	for i, sku := range f.skus {
+		catalog[i] = &domain.SKUMapping{SKU: sku, IsActive: true}
+	}
+	return catalog, nil
+}
+
+func (f *fakeCatalog) Remove(ctx context.Context, partnerID uuid.UUID, sku string) error {
+	for i, held := range f.skus {
+		if held == sku {
+			f.skus = append(f.skus[:i], f.skus[i+1:]...)
+			return nil
+		}
+	}
+	return &errors.ErrNotFound{Resource: "partner_catalog", ID: sku}
+}
+
+func TestHandleRemovePartnerCatalogSKU(t *testing.T) {
+	partner := &domain.Partner{ID: uuid.New(), Name: "Partner"}
+
+	tests := []struct {
+		name       string
+		catalog    []string
+		query      string
+		wantStatus int
+		wantLeft   int
+	}{
+		{
+			name:       "removes one of several SKUs",
+			catalog:    []string{"PROD-001", "PROD-002"},
+			wantStatus: http.StatusNoContent,
+			wantLeft:   1,
+		},
+		{
+			name:       "keeps the last SKU so the partner stays restricted",
+			catalog:    []string{"PROD-001"},
+			wantStatus: http.StatusConflict,
+			wantLeft:   1,
+		},
+		{
+			name:       "removes the last SKU when lifting the restriction",
+			catalog:    []string{"PROD-001"},
+			query:      "?unrestrict=true",
+			wantStatus: http.StatusNoContent,
+			wantLeft:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := &fakeCatalog{skus: tt.catalog}
+			repos := &repository.Repositories{
+				Partner:        &fakePartners{partners: []*domain.Partner{partner}},
+				PartnerCatalog: catalog,
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.DELETE("/v1/admin/partners/:id/catalog/:sku", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, &domain.Partner{ID: uuid.New()})
+			}, HandleRemovePartnerCatalogSKU(&service.Services{Webhooks: servicemock.NewWebhookService(t)}, repos, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/admin/partners/"+partner.ID.String()+"/catalog/PROD-001"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if len(catalog.skus) != tt.wantLeft {
+				t.Errorf("catalog has %d SKUs left, want %d", len(catalog.skus), tt.wantLeft)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// PublishChangelogEntryRequest represents publish changelog entry request
+type PublishChangelogEntryRequest struct {
+	Type        domain.ChangelogType `json:"type" binding:"required,oneof=addition change deprecation maintenance"`
+	Title       string               `json:"title" binding:"required,max=255"`
+	Description string               `json:"description" binding:"max=5000"`
+	APIVersion  *string              `json:"api_version,omitempty" binding:"omitempty,oneof=v1 v2"`
+	Affects     []string             `json:"affects,omitempty" binding:"max=50,dive,required,max=255"`
+	EffectiveAt *time.Time           `json:"effective_at,omitempty"`
+	EndsAt      *time.Time           `json:"ends_at,omitempty"`
+}
+
+// HandleListChangelog handles GET /v1/changelog
+// Lists announcements newest first; integrations poll with since set to the newest
+// published_at they have seen
+func HandleListChangelog(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		filter := domain.ChangelogFilter{Type: domain.ChangelogType(c.Query("type"))}
+		if filter.Type != "" && !filter.Type.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid type"})
+			return
+		}
+		if sinceStr := c.Query("since"); sinceStr != "" {
+			since, err := time.Parse(time.RFC3339, sinceStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+				return
+			}
+			filter.Since = &since
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		var cursor *domain.OrderCursor
+		if cursorStr := c.Query("cursor"); cursorStr != "" {
+			cursor, err = domain.DecodeOrderCursor(cursorStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+		}
+
+		entries, err := repos.Changelog.ListAfter(c.Request.Context(), filter, cursor, limit)
+		if err != nil {
+			logger.Error("Failed to list changelog entries", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		entryResponses := make([]gin.H, len(entries))
+		for i, entry := range entries {
+			entryResponses[i] = changelogEntryResponse(entry)
+		}
+
+		response := gin.H{
+			"entries": entryResponses,
+			"limit":   limit,
+		}
+		if len(entries) == limit {
+			last := entries[len(entries)-1]
+			response["next_cursor"] = domain.OrderCursor{CreatedAt: last.PublishedAt, ID: last.ID}.Encode()
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandlePublishChangelogEntry handles POST /v1/admin/changelog
+func HandlePublishChangelogEntry(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req PublishChangelogEntryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		invalid := make(map[string]string)
+		if req.EndsAt != nil && req.Type != domain.ChangelogTypeMaintenance {
+			invalid["ends_at"] = "is only allowed for maintenance entries"
+		}
+		if req.Type == domain.ChangelogTypeMaintenance && (req.EffectiveAt == nil || req.EndsAt == nil) {
+			invalid["effective_at"] = "maintenance entries need effective_at and ends_at"
+		}
+		if req.EffectiveAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.EffectiveAt) {
+			invalid["ends_at"] = "must be after effective_at"
+		}
+		if len(invalid) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(invalid),
+			})
+			return
+		}
+
+		entry := &domain.ChangelogEntry{
+			Type:        req.Type,
+			Title:       req.Title,
+			Description: req.Description,
+			APIVersion:  req.APIVersion,
+			Affects:     req.Affects,
+			EffectiveAt: req.EffectiveAt,
+			EndsAt:      req.EndsAt,
+			PublishedBy: admin.ID,
+		}
+		if err := repos.Changelog.Create(c.Request.Context(), entry); err != nil {
+			logger.Error("Failed to publish changelog entry", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to publish changelog entry"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, changelogEntryResponse(entry))
+	}
+}
+
+// HandleDeleteChangelogEntry handles DELETE /v1/admin/changelog/:id
+func HandleDeleteChangelogEntry(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		entryID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid changelog entry ID"})
+			return
+		}
+
+		if err := repos.Changelog.Delete(c.Request.Context(), entryID); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "changelog entry not found"})
+				return
+			}
+			logger.Error("Failed to delete changelog entry", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete changelog entry"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func changelogEntryResponse(entry *domain.ChangelogEntry) gin.H {
+	return gin.H{
+		"id":           entry.ID.String(),
+		"type":         entry.Type,
+		"title":        entry.Title,
+		"description":  entry.Description,
+		"api_version":  entry.APIVersion,
+		"affects":      entry.Affects,
+		"effective_at": formatTimestampPtr(entry.EffectiveAt),
+		"ends_at":      formatTimestampPtr(entry.EndsAt),
+		"published_at": formatTimestamp(entry.PublishedAt),
+	}
+}
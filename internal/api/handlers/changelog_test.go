@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// fakeChangelog keeps published entries in memory
+type fakeChangelog struct {
+	repository.ChangelogRepository
+	entries []*domain.ChangelogEntry
+}
+
+func (f *fakeChangelog) Create(ctx context.Context, entry *domain.ChangelogEntry) error {
+	entry.ID = uuid.New()
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func TestHandlePublishChangelogEntry(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantField  string
+	}{
+		{
+			name:       "deprecation",
+			body:       `{"type": "deprecation", "title": "v1 is sunset", "api_version": "v1", "affects": ["POST /v1/carts/submit"], "effective_at": "2030-01-01T00:00:00Z"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "maintenance window",
+			body:       `{"type": "maintenance", "title": "Database upgrade", "effective_at": "2030-01-01T02:00:00Z", "ends_at": "2030-01-01T03:00:00Z"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "maintenance without an end",
+			body:       `{"type": "maintenance", "title": "Database upgrade", "effective_at": "2030-01-01T02:00:00Z"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "effective_at",
+		},
+		{
+			name:       "window ending before it starts",
+			body:       `{"type": "maintenance", "title": "Database upgrade", "effective_at": "2030-01-01T02:00:00Z", "ends_at": "2030-01-01T01:00:00Z"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "ends_at",
+		},
+		{
+			name:       "unknown type",
+			body:       `{"type": "rumour", "title": "Something"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changelog := &fakeChangelog{}
+			repos := &repository.Repositories{Changelog: changelog}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/admin/changelog", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, &domain.Partner{ID: uuid.New()})
+			}, HandlePublishChangelogEntry(repos, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/changelog", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusCreated {
+				if len(changelog.entries) != 1 {
+					t.Errorf("published %d entries, want 1", len(changelog.entries))
+				}
+				return
+			}
+			details, _ := decodeBody(t, w)["details"].([]interface{})
+			if len(details) != 1 || details[0].(map[string]interface{})["field"] != tt.wantField {
+				t.Errorf("details = %v, want an error for %s", details, tt.wantField)
+			}
+			if len(changelog.entries) != 0 {
+				t.Errorf("published %d entries, want none", len(changelog.entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// UpdateCreditLimitRequest represents a partner credit limit update; a null credit_limit removes the limit
+type UpdateCreditLimitRequest struct {
+	CreditLimit *float64 `json:"credit_limit"`
+	Action      string   `json:"action"`
+}
+
+// HandleGetMe handles GET /v1/me
+func HandleGetMe(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		credit, err := services.Credit.Status(c.Request.Context(), partner)
+		if err != nil {
+			logger.Error("Failed to get partner credit", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":                  partner.ID.String(),
+			"name":                partner.Name,
+			"locale":              partner.Locale,
+			"webhook_url":         partner.WebhookURL,
+			"payment_terms":       partner.PaymentTerms,
+			"legacy_status_codes": partner.LegacyStatusCodes,
+			"substitutions":       partner.Substitutions,
+			"organization_id":     partner.OrganizationID,
+			"credit":              creditResponse(credit),
+		})
+	}
+}
+
+// HandleGetPartnerCredit handles GET /v1/admin/partners/:id/credit
+func HandleGetPartnerCredit(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		credit, err := services.Credit.Status(c.Request.Context(), partner)
+		if err != nil {
+			logger.Error("Failed to get partner credit", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		response := creditResponse(credit)
+		response["partner_id"] = partner.ID.String()
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleUpdateCreditLimit handles PUT /v1/admin/partners/:id/credit-limit
+func HandleUpdateCreditLimit(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, _ := middleware.GetPartnerFromContext(c)
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Parse request
+		var req UpdateCreditLimitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+		action := domain.CreditLimitAction(req.Action)
+		if req.Action == "" {
+			action = partner.CreditLimitAction
+		}
+
+		err := services.Onboarding.UpdateCreditLimit(
+			c.Request.Context(),
+			fmt.Sprintf("partner:%s", admin.ID),
+			partner,
+			req.CreditLimit,
+			action,
+		)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update credit limit", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update credit limit"})
+			return
+		}
+
+		credit, err := services.Credit.Status(c.Request.Context(), partner)
+		if err != nil {
+			logger.Error("Failed to get partner credit", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		response := creditResponse(credit)
+		response["partner_id"] = partner.ID.String()
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func creditResponse(credit *service.CreditStatus) gin.H {
+	var usage *float64
+	if credit.Limit != nil {
+		u := credit.Usage()
+		usage = &u
+	}
+	return gin.H{
+		"credit_limit": credit.Limit,
+		"outstanding":  credit.Outstanding,
+		"remaining":    credit.Remaining,
+		"usage":        usage,
+		"action":       credit.Action,
+	}
+}
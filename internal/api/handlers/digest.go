@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// UpdateDigestRequest represents digest subscription request
+type UpdateDigestRequest struct {
+	Email    string `json:"email" binding:"required"`
+	SendTime string `json:"send_time,omitempty"` // HH:MM, defaults to DIGEST_SEND_TIME
+}
+
+// HandleGetDigest handles GET /v1/partner/digest
+func HandleGetDigest(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		subscription, err := repos.DigestSubscription.GetByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not subscribed to the digest"})
+				return
+			}
+			logger.Error("Failed to get digest subscription", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, buildDigestResponse(cfg, subscription))
+	}
+}
+
+// HandleUpdateDigest handles PUT /v1/partner/digest
+func HandleUpdateDigest(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req UpdateDigestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		digestService := service.NewDigestService(cfg, repos, logger)
+		subscription, err := digestService.Subscribe(c.Request.Context(), partner, req.Email, req.SendTime)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update digest subscription", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update digest subscription"})
+			return
+		}
+
+		c.JSON(http.StatusOK, buildDigestResponse(cfg, subscription))
+	}
+}
+
+// HandleDeleteDigest handles DELETE /v1/partner/digest
+func HandleDeleteDigest(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		digestService := service.NewDigestService(cfg, repos, logger)
+		if err := digestService.Unsubscribe(c.Request.Context(), partner); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "not subscribed to the digest"})
+				return
+			}
+			logger.Error("Failed to delete digest subscription", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete digest subscription"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func buildDigestResponse(cfg *config.Config, subscription *domain.DigestSubscription) gin.H {
+	return gin.H{
+		"partner_id":   subscription.PartnerID.String(),
+		"email":        subscription.Email,
+		"send_time":    subscription.SendTime,
+		"time_zone":    cfg.Digest.Location.String(),
+		"last_sent_at": formatTimestampPtr(subscription.LastSentAt),
+	}
+}
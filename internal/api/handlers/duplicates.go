@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// HandleListDuplicateOrders handles GET /v1/admin/orders/duplicates
+func HandleListDuplicateOrders(cfg *config.Config, services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		window := cfg.Tunables().DuplicateWindow
+		if hoursStr := c.Query("window_hours"); hoursStr != "" {
+			hours, err := strconv.Atoi(hoursStr)
+			if err != nil || hours < 1 || hours > 24*30 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "window_hours must be between 1 and 720"})
+				return
+			}
+			window = time.Duration(hours) * time.Hour
+		}
+
+		clusters, err := services.Duplicates.FindDuplicates(c.Request.Context(), window)
+		if err != nil {
+			logger.Error("Failed to find duplicate orders", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		// Build response
+		clusterResponses := make([]gin.H, len(clusters))
+		for i, cluster := range clusters {
+			orders := make([]gin.H, len(cluster.Orders))
+			for j, order := range cluster.Orders {
+				orders[j] = gin.H{
+					"id":               order.ID.String(),
+					"partner_id":       order.PartnerID.String(),
+					"partner_order_id": order.PartnerOrderID,
+					"reference":        order.Reference,
+					"status":           order.Status,
+					"customer_name":    order.CustomerName,
+					"customer_phone":   order.CustomerPhone,
+					"shipping_address": buildAddressResponse(order.ShippingAddress),
+					"created_at":       formatTimestamp(order.CreatedAt),
+				}
+			}
+			clusterResponses[i] = gin.H{
+				"matched_on": cluster.MatchedOn,
+				"key":        cluster.Key,
+				"orders":     orders,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"clusters":     clusterResponses,
+			"window_hours": int(window.Hours()),
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/signedurl"
+)
+
+// CreateExportRequest represents create export request
+type CreateExportRequest struct {
+	Type      domain.ExportType   `json:"type" binding:"required,oneof=orders_csv"`
+	PartnerID *uuid.UUID          `json:"partner_id,omitempty"`
+	Status    *domain.OrderStatus `json:"status,omitempty"`
+	From      *time.Time          `json:"from,omitempty"`
+	To        *time.Time          `json:"to,omitempty"`
+}
+
+// HandleCreateExport handles POST /v1/admin/exports
+// Small exports are generated during the request (201 with a download_url); larger ones in
+// the background (202), to be polled at GET /v1/admin/exports/:id
+func HandleCreateExport(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req CreateExportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		invalid := make(map[string]string)
+		if req.Status != nil && !req.Status.IsValid() {
+			invalid["status"] = "is not a valid order status"
+		}
+		if req.From != nil && req.To != nil && !req.To.After(*req.From) {
+			invalid["to"] = "must be after from"
+		}
+		if len(invalid) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(invalid),
+			})
+			return
+		}
+
+		filter := domain.OrderFilter{
+			PartnerID: req.PartnerID,
+			Status:    req.Status,
+			From:      req.From,
+			To:        req.To,
+		}
+
+		exportService := service.NewExportService(cfg, repos, logger)
+		job, err := exportService.Start(c.Request.Context(), admin.ID, filter)
+		if err != nil {
+			logger.Error("Failed to start export", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start export"})
+			return
+		}
+
+		status := http.StatusCreated
+		if job.Status == domain.ExportStatusRunning {
+			status = http.StatusAccepted
+		}
+		c.JSON(status, exportJobResponse(cfg, basePath, job))
+	}
+}
+
+// HandleGetExport handles GET /v1/admin/exports/:id
+// Returns the export's status, with a fresh download_url once it is completed
+func HandleGetExport(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		exportID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export ID"})
+			return
+		}
+
+		job, err := repos.ExportJob.GetByID(c.Request.Context(), exportID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "export not found"})
+				return
+			}
+			logger.Error("Failed to get export", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, exportJobResponse(cfg, basePath, job))
+	}
+}
+
+// HandleDownloadExport handles GET /v1/exports/:id/download (unauthenticated - the signed
+// link is the credential)
+func HandleDownloadExport(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now()
+		err := service.VerifyExportDownload(cfg, c.Request.URL.Path, c.Query(signedurl.ExpiresParam), c.Query(signedurl.SignatureParam), now)
+		if err == signedurl.ErrExpired {
+			c.JSON(http.StatusGone, gin.H{"error": "download link expired"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "invalid download link"})
+			return
+		}
+
+		exportID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export ID"})
+			return
+		}
+
+		job, err := repos.ExportJob.GetByID(c.Request.Context(), exportID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "export not found"})
+				return
+			}
+			logger.Error("Failed to get export", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if !now.Before(job.ExpiresAt) {
+			c.JSON(http.StatusGone, gin.H{"error": "export expired"})
+			return
+		}
+
+		content, err := repos.ExportJob.GetContent(c.Request.Context(), exportID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "export not found"})
+				return
+			}
+			logger.Error("Failed to get export content", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		contentType := "application/octet-stream"
+		if job.ContentType != nil {
+			contentType = *job.ContentType
+		}
+		if job.Filename != nil {
+			c.Header("Content-Disposition", `attachment; filename="`+*job.Filename+`"`)
+		}
+		c.Header("Cache-Control", "private, no-store")
+		c.Data(http.StatusOK, contentType, content)
+	}
+}
+
+// exportJobResponse renders an export job; completed jobs get a download link signed now
+func exportJobResponse(cfg *config.Config, basePath string, job *domain.ExportJob) gin.H {
+	response := gin.H{
+		"id":           job.ID.String(),
+		"type":         job.Type,
+		"status":       job.Status,
+		"row_count":    job.RowCount,
+		"error":        job.Error,
+		"created_at":   formatTimestamp(job.CreatedAt),
+		"completed_at": formatTimestampPtr(job.CompletedAt),
+		"expires_at":   formatTimestamp(job.ExpiresAt),
+		"status_url":   basePath + "/admin/exports/" + job.ID.String(),
+	}
+	if job.Status == domain.ExportStatusCompleted {
+		downloadURL, linkExpiresAt := service.SignedExportDownloadURL(cfg, basePath, job, time.Now())
+		response["download_url"] = downloadURL
+		response["download_url_expires_at"] = formatTimestamp(linkExpiresAt)
+	}
+	return response
+}
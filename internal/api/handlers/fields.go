@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldSet is a ?fields= selection of top-level response fields; nil selects every field
+type fieldSet map[string]bool
+
+// parseFields reads a selection such as ?fields=id,status,tracking_number. Names that are not
+// in allowed are an error, so typos do not silently return less than expected.
+func parseFields(c *gin.Context, allowed []string) (fieldSet, error) {
+	raw := c.Query("fields")
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		known[name] = true
+	}
+
+	fields := make(fieldSet)
+	var unknown []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			unknown = append(unknown, name)
+			continue
+		}
+		fields[name] = true
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// has reports whether name is selected
+func (f fieldSet) has(name string) bool {
+	return f == nil || f[name]
+}
+
+// apply keeps the selected fields of a response. Selected fields the response leaves out
+// because they are empty are returned as null, so clients always get the keys they asked for.
+func (f fieldSet) apply(response interface{}) interface{} {
+	if f == nil {
+		return response
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return response
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return response
+	}
+
+	selected := make(map[string]json.RawMessage, len(f))
+	for name := range f {
+		if value, ok := all[name]; ok {
+			selected[name] = value
+		} else {
+			selected[name] = json.RawMessage("null")
+		}
+	}
+	return selected
+}
+
+// jsonFieldNames lists the JSON names of a response struct's fields, including the fields of
+// embedded structs
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			names = append(names, jsonFieldNames(field.Type)...)
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
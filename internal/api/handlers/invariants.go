@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// HandleGetInvariants handles GET /v1/admin/invariants
+// Returns the results of the latest order invariant check (format=prometheus for metrics)
+func HandleGetInvariants(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		metrics := service.InvariantCheckMetrics()
+		if c.Query("format") == "prometheus" {
+			c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(formatInvariantMetrics(metrics)))
+			return
+		}
+
+		response := gin.H{
+			"interval":    cfg.Invariants.Interval.String(),
+			"runs":        metrics.Runs,
+			"failures":    metrics.Failures,
+			"last_run_at": nil,
+			"invariants":  invariantResults(metrics.Results),
+		}
+		if !metrics.LastRunAt.IsZero() {
+			response["last_run_at"] = formatTimestamp(metrics.LastRunAt)
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleCheckInvariants handles POST /v1/admin/invariants/check
+// Checks the order invariants now, the same as the background job
+func HandleCheckInvariants(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		invariantService := service.NewInvariantService(cfg, repos, logger)
+		results := invariantService.Check(c.Request.Context(), time.Now())
+
+		c.JSON(http.StatusOK, gin.H{"invariants": invariantResults(results)})
+	}
+}
+
+// invariantResults renders invariant check results with their sample of offending orders
+func invariantResults(results []service.InvariantResult) []gin.H {
+	rendered := make([]gin.H, len(results))
+	for i, result := range results {
+		orders := make([]gin.H, len(result.Sample))
+		for j, violation := range result.Sample {
+			orders[j] = gin.H{
+				"id":         violation.OrderID.String(),
+				"partner_id": violation.PartnerID.String(),
+				"status":     violation.Status,
+				"created_at": formatTimestamp(violation.CreatedAt),
+			}
+		}
+		rendered[i] = gin.H{
+			"invariant":  result.Invariant,
+			"violations": result.Violations,
+			"failed":     result.Failed,
+			"orders":     orders,
+		}
+	}
+	return rendered
+}
+
+// formatInvariantMetrics renders the invariant check results in the Prometheus text exposition format
+func formatInvariantMetrics(metrics service.InvariantMetrics) string {
+	var b strings.Builder
+
+	b.WriteString("# HELP b2b_order_invariant_violations Orders breaking each invariant at the latest check.\n")
+	b.WriteString("# TYPE b2b_order_invariant_violations gauge\n")
+	for _, result := range metrics.Results {
+		fmt.Fprintf(&b, "b2b_order_invariant_violations{invariant=%s} %d\n", promLabel(string(result.Invariant)), result.Violations)
+	}
+
+	b.WriteString("# HELP b2b_order_invariant_checks_total Order invariant checks since the process started.\n")
+	b.WriteString("# TYPE b2b_order_invariant_checks_total counter\n")
+	fmt.Fprintf(&b, "b2b_order_invariant_checks_total %d\n", metrics.Runs)
+
+	b.WriteString("# HELP b2b_order_invariant_check_failures_total Order invariant checks that could not check every invariant.\n")
+	b.WriteString("# TYPE b2b_order_invariant_check_failures_total counter\n")
+	fmt.Fprintf(&b, "b2b_order_invariant_check_failures_total %d\n", metrics.Failures)
+
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+)
+
+// maxLogLevelRevertAfter caps how long a temporary log level can stay in place
+const maxLogLevelRevertAfter = 24 * time.Hour
+
+// UpdateLogLevelRequest represents update log level request
+type UpdateLogLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+	// RevertAfter restores the previous level after this duration (e.g. "30m")
+	RevertAfter string `json:"revert_after,omitempty"`
+}
+
+// HandleGetLogLevel handles GET /v1/admin/log-level
+func HandleGetLogLevel(logLevel zap.AtomicLevel) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"level": logLevel.Level().String()})
+	}
+}
+
+// HandleUpdateLogLevel handles PUT /v1/admin/log-level
+func HandleUpdateLogLevel(logLevel zap.AtomicLevel, logger *zap.Logger) gin.HandlerFunc {
+	// A pending revert is cancelled when the level is changed again
+	var mu sync.Mutex
+	var revert *time.Timer
+
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req UpdateLogLevelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		level, err := zapcore.ParseLevel(req.Level)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(map[string]string{"level": "must be debug, info, warn or error"}),
+			})
+			return
+		}
+
+		var revertAfter time.Duration
+		if req.RevertAfter != "" {
+			revertAfter, err = time.ParseDuration(req.RevertAfter)
+			if err != nil || revertAfter <= 0 || revertAfter > maxLogLevelRevertAfter {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": fieldErrors(map[string]string{"revert_after": "must be a duration between 1s and 24h, e.g. 30m"}),
+				})
+				return
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if revert != nil {
+			revert.Stop()
+			revert = nil
+		}
+		previous := logLevel.Level()
+		logLevel.SetLevel(level)
+
+		logger.Warn("Log level changed",
+			zap.String("from", previous.String()),
+			zap.String("to", level.String()),
+			zap.String("changed_by", admin.ID.String()),
+		)
+
+		resp := gin.H{"level": level.String()}
+		if revertAfter > 0 {
+			revertAt := time.Now().Add(revertAfter)
+			revert = time.AfterFunc(revertAfter, func() {
+				logLevel.SetLevel(previous)
+				logger.Warn("Log level reverted",
+					zap.String("from", level.String()),
+					zap.String("to", previous.String()),
+				)
+			})
+			resp["reverts_to"] = previous.String()
+			resp["reverts_at"] = formatTimestamp(revertAt)
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
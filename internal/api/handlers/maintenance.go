@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// StartMaintenanceRequest represents start maintenance request
+type StartMaintenanceRequest struct {
+	// Message is shown to partners in rejected responses (e.g. "Database upgrade")
+	Message string `json:"message" binding:"max=500"`
+	// EndsAt is when maintenance is expected to end; Retry-After counts down to it
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+}
+
+// HandleGetMaintenance handles GET /v1/admin/maintenance
+func HandleGetMaintenance(services *service.Services) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusOK, maintenanceResponse(services.Maintenance.Status()))
+	}
+}
+
+// HandleStartMaintenance handles PUT /v1/admin/maintenance
+func HandleStartMaintenance(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req StartMaintenanceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+		if req.EndsAt != nil && !req.EndsAt.After(time.Now()) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(map[string]string{"ends_at": "must be in the future"}),
+			})
+			return
+		}
+
+		status := services.Maintenance.Start(req.Message, req.EndsAt, admin.ID)
+
+		logger.Warn("Maintenance mode started",
+			zap.String("message", req.Message),
+			zap.String("started_by", admin.ID.String()),
+		)
+
+		c.JSON(http.StatusOK, maintenanceResponse(status))
+	}
+}
+
+// HandleEndMaintenance handles DELETE /v1/admin/maintenance
+func HandleEndMaintenance(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		status := services.Maintenance.End()
+
+		logger.Warn("Maintenance mode ended", zap.String("ended_by", admin.ID.String()))
+
+		c.JSON(http.StatusOK, maintenanceResponse(status))
+	}
+}
+
+func maintenanceResponse(status service.MaintenanceStatus) gin.H {
+	resp := gin.H{"enabled": status.Enabled}
+	if !status.Enabled {
+		return resp
+	}
+	resp["source"] = status.Source
+	resp["retry_after"] = int(status.RetryAfter.Seconds())
+	if status.Source == service.MaintenanceSourceAdmin {
+		resp["message"] = status.Message
+		resp["started_at"] = formatTimestampPtr(status.StartedAt)
+		resp["ends_at"] = formatTimestampPtr(status.EndsAt)
+		resp["started_by"] = status.StartedBy
+	}
+	return resp
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// CreateInvitationRequest represents create invitation request
+type CreateInvitationRequest struct {
+	PartnerName    string `json:"partner_name" binding:"required"`
+	ExpiresInHours int    `json:"expires_in_hours" binding:"min=0"`
+}
+
+// AcceptInvitationRequest represents accept invitation request
+type AcceptInvitationRequest struct {
+	Token      string  `json:"token" binding:"required"`
+	WebhookURL *string `json:"webhook_url,omitempty"`
+}
+
+// UpdateWebhookURLRequest represents update webhook URL request (null clears it)
+type UpdateWebhookURLRequest struct {
+	WebhookURL *string `json:"webhook_url"`
+}
+
+// UpdateLocaleRequest represents update locale request
+type UpdateLocaleRequest struct {
+	Locale string `json:"locale" binding:"required"`
+}
+
+// UpdateStatusCodesRequest represents update status codes request
+type UpdateStatusCodesRequest struct {
+	LegacyStatusCodes *bool `json:"legacy_status_codes" binding:"required"`
+}
+
+// UpdateSubstitutionsRequest represents update substitutions request
+type UpdateSubstitutionsRequest struct {
+	Substitutions *bool `json:"substitutions" binding:"required"`
+}
+
+// UpdatePaymentTermsRequest represents update payment terms request
+type UpdatePaymentTermsRequest struct {
+	PaymentTerms string  `json:"payment_terms" binding:"required"`
+	InvoiceEmail *string `json:"invoice_email,omitempty" binding:"omitempty,email,max=255"`
+}
+
+// HandleCreateInvitation handles POST /v1/admin/invitations
+func HandleCreateInvitation(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context (for now, admin uses same auth)
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req CreateInvitationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		invitation, token, err := services.Onboarding.CreateInvitation(
+			c.Request.Context(),
+			fmt.Sprintf("partner:%s", admin.ID),
+			req.PartnerName,
+			time.Duration(req.ExpiresInHours)*time.Hour,
+		)
+		if err != nil {
+			logger.Error("Failed to create invitation", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invitation"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"id":           invitation.ID.String(),
+			"partner_name": invitation.PartnerName,
+			"token":        token,
+			"expires_at":   formatTimestamp(invitation.ExpiresAt),
+		})
+	}
+}
+
+// HandleAcceptInvitation handles POST /v1/onboarding/accept (unauthenticated - the token is the credential)
+func HandleAcceptInvitation(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Parse request
+		var req AcceptInvitationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		partner, apiKey, err := services.Onboarding.AcceptInvitation(c.Request.Context(), req.Token, req.WebhookURL)
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrValidation:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+			case *errors.ErrUnauthorized:
+				c.JSON(http.StatusUnauthorized, gin.H{"error": e.Error()})
+			default:
+				logger.Error("Failed to accept invitation", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept invitation"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"partner_id":     partner.ID.String(),
+			"name":           partner.Name,
+			"api_key":        apiKey,
+			"api_key_prefix": partner.APIKeyPrefix,
+			"webhook_url":    partner.WebhookURL,
+			"webhook_secret": partner.WebhookSecret,
+		})
+	}
+}
+
+// HandleUpdateWebhookURL handles PUT /v1/partner/webhook
+func HandleUpdateWebhookURL(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req UpdateWebhookURLRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		secret, err := services.Onboarding.UpdateWebhookURL(c.Request.Context(), partner, req.WebhookURL)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update webhook URL", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update webhook URL"})
+			return
+		}
+
+		response := gin.H{
+			"partner_id":  partner.ID.String(),
+			"webhook_url": partner.WebhookURL,
+		}
+		// The secret is only shown when it is created
+		if secret != "" {
+			response["webhook_secret"] = secret
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleRotateWebhookSecret handles POST /v1/partner/webhook/secret
+func HandleRotateWebhookSecret(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		secret, err := services.Onboarding.RotateWebhookSecret(c.Request.Context(), partner)
+		if err != nil {
+			logger.Error("Failed to rotate webhook secret", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rotate webhook secret"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":     partner.ID.String(),
+			"webhook_secret": secret,
+		})
+	}
+}
+
+// HandleUpdateLocale handles PUT /v1/partner/locale
+func HandleUpdateLocale(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req UpdateLocaleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		if err := services.Onboarding.UpdateLocale(c.Request.Context(), partner, req.Locale); err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update locale", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update locale"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id": partner.ID.String(),
+			"locale":     partner.Locale,
+		})
+	}
+}
+
+// HandleUpdatePaymentTerms handles PUT /v1/admin/partners/:id/payment-terms
+func HandleUpdatePaymentTerms(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, _ := middleware.GetPartnerFromContext(c)
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Parse request
+		var req UpdatePaymentTermsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		err := services.Onboarding.UpdatePaymentTerms(
+			c.Request.Context(),
+			fmt.Sprintf("partner:%s", admin.ID),
+			partner,
+			domain.PaymentTerms(req.PaymentTerms),
+			req.InvoiceEmail,
+		)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update payment terms", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update payment terms"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":    partner.ID.String(),
+			"payment_terms": partner.PaymentTerms,
+			"invoice_email": partner.InvoiceEmail,
+		})
+	}
+}
+
+// HandleUpdateSubstitutions handles PUT /v1/admin/partners/:id/substitutions
+func HandleUpdateSubstitutions(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, _ := middleware.GetPartnerFromContext(c)
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		// Parse request
+		var req UpdateSubstitutionsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		err := services.Onboarding.UpdateSubstitutions(c.Request.Context(), fmt.Sprintf("partner:%s", admin.ID), partner, *req.Substitutions)
+		if err != nil {
+			logger.Error("Failed to update substitutions", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update substitutions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":    partner.ID.String(),
+			"substitutions": partner.Substitutions,
+		})
+	}
+}
+
+// HandleUpdateStatusCodes handles PUT /v1/partner/status-codes
+func HandleUpdateStatusCodes(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req UpdateStatusCodesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		if err := services.Onboarding.UpdateLegacyStatusCodes(c.Request.Context(), partner, *req.LegacyStatusCodes); err != nil {
+			logger.Error("Failed to update status codes", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update status codes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":          partner.ID.String(),
+			"legacy_status_codes": partner.LegacyStatusCodes,
+		})
+	}
+}
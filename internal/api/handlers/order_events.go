@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleCreateOrderEvent handles POST /v1/orders/:id/events
+func HandleCreateOrderEvent(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		// Parse request
+		var req service.PartnerOrderEventRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		// Get order
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		// Verify partner owns this order
+		if order.PartnerID != partner.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		result, err := services.PartnerEvents.RecordEvent(c.Request.Context(), order, req)
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrValidation:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   e.Error(),
+					"details": fieldErrors(e.Fields),
+				})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
+			case *errors.ErrInvalidStateTransition:
+				c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
+			default:
+				logger.Error("Failed to record partner event", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"id":           result.Event.ID.String(),
+			"type":         req.Type,
+			"action":       result.Action,
+			"order_status": order.Status,
+			"created_at":   formatTimestamp(result.Event.CreatedAt),
+		})
+	}
+}
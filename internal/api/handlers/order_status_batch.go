@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// MaxStatusBatchSize is the most orders one status-batch request may ask for
+const MaxStatusBatchSize = 100
+
+// OrderStatusBatchRequest selects orders by ID, partner order ID or both
+type OrderStatusBatchRequest struct {
+	IDs             []string `json:"ids"`
+	PartnerOrderIDs []string `json:"partner_order_ids"`
+}
+
+// OrderStatusResponse is the status and tracking of one order in a batch
+type OrderStatusResponse struct {
+	ID              string             `json:"id"`
+	PartnerOrderID  string             `json:"partner_order_id"`
+	Reference       *string            `json:"reference,omitempty"`
+	Status          domain.OrderStatus `json:"status"`
+	StatusLabel     string             `json:"status_label"`
+	TrackingCarrier *string            `json:"tracking_carrier,omitempty"`
+	TrackingNumber  *string            `json:"tracking_number,omitempty"`
+	TrackingURL     *string            `json:"tracking_url,omitempty"`
+	ShippedAt       *string            `json:"shipped_at,omitempty"`
+	DeliveredAt     *string            `json:"delivered_at,omitempty"`
+	UpdatedAt       string             `json:"updated_at"`
+}
+
+// OrderStatusNotFound lists the requested identifiers that matched none of the partner's orders
+type OrderStatusNotFound struct {
+	IDs             []string `json:"ids"`
+	PartnerOrderIDs []string `json:"partner_order_ids"`
+}
+
+// OrderStatusBatchResponse is the response of POST /v1/orders/status-batch
+type OrderStatusBatchResponse struct {
+	Orders   []OrderStatusResponse `json:"orders"`
+	NotFound OrderStatusNotFound   `json:"not_found"`
+}
+
+// HandleOrderStatusBatch handles POST /v1/orders/status-batch
+// Orders are returned in request order, IDs first; an order named by both identifiers appears once
+func HandleOrderStatusBatch(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req OrderStatusBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		fields := make(map[string]string)
+		total := len(req.IDs) + len(req.PartnerOrderIDs)
+		if total == 0 {
+			fields["ids"] = "ids or partner_order_ids is required"
+		} else if total > MaxStatusBatchSize {
+			fields["ids"] = fmt.Sprintf("at most %d orders can be requested at once", MaxStatusBatchSize)
+		}
+
+		orderIDs := make([]uuid.UUID, 0, len(req.IDs))
+		for i, raw := range req.IDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				fields[fmt.Sprintf("ids[%d]", i)] = "must be a valid order ID"
+				continue
+			}
+			orderIDs = append(orderIDs, id)
+		}
+		for i, partnerOrderID := range req.PartnerOrderIDs {
+			if partnerOrderID == "" {
+				fields[fmt.Sprintf("partner_order_ids[%d]", i)] = "must not be empty"
+			}
+		}
+		if len(fields) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "validation failed", "details": fieldErrors(fields)})
+			return
+		}
+
+		ctx := c.Request.Context()
+		byID := make(map[uuid.UUID]*domain.SupplierOrder)
+		if len(orderIDs) > 0 {
+			orders, err := repos.SupplierOrder.ListByPartnerIDAndIDs(ctx, partner.ID, orderIDs)
+			if err != nil {
+				logger.Error("Failed to list orders for status batch", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			for _, order := range orders {
+				byID[order.ID] = order
+			}
+		}
+
+		byPartnerOrderID := make(map[string]*domain.SupplierOrder)
+		if len(req.PartnerOrderIDs) > 0 {
+			orders, err := repos.SupplierOrder.ListByPartnerIDAndPartnerOrderIDs(ctx, partner.ID, req.PartnerOrderIDs)
+			if err != nil {
+				logger.Error("Failed to list orders for status batch", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+			for _, order := range orders {
+				byPartnerOrderID[order.PartnerOrderID] = order
+			}
+		}
+
+		response := OrderStatusBatchResponse{
+			Orders: make([]OrderStatusResponse, 0, total),
+			NotFound: OrderStatusNotFound{
+				IDs:             []string{},
+				PartnerOrderIDs: []string{},
+			},
+		}
+		seen := make(map[uuid.UUID]bool)
+		add := func(order *domain.SupplierOrder) {
+			if seen[order.ID] {
+				return
+			}
+			seen[order.ID] = true
+			response.Orders = append(response.Orders, buildOrderStatusResponse(order))
+		}
+
+		for i, id := range orderIDs {
+			if order, ok := byID[id]; ok {
+				add(order)
+			} else {
+				response.NotFound.IDs = append(response.NotFound.IDs, req.IDs[i])
+			}
+		}
+		for _, partnerOrderID := range req.PartnerOrderIDs {
+			if order, ok := byPartnerOrderID[partnerOrderID]; ok {
+				add(order)
+			} else {
+				response.NotFound.PartnerOrderIDs = append(response.NotFound.PartnerOrderIDs, partnerOrderID)
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func buildOrderStatusResponse(order *domain.SupplierOrder) OrderStatusResponse {
+	return OrderStatusResponse{
+		ID:              order.ID.String(),
+		PartnerOrderID:  order.PartnerOrderID,
+		Reference:       order.Reference,
+		Status:          order.Status,
+		StatusLabel:     service.StatusLabel(service.ResolveLocale(order), order.Status),
+		TrackingCarrier: order.TrackingCarrier,
+		TrackingNumber:  order.TrackingNumber,
+		TrackingURL:     order.TrackingURL,
+		ShippedAt:       formatTimestampPtr(order.ShippedAt),
+		DeliveredAt:     formatTimestampPtr(order.DeliveredAt),
+		UpdatedAt:       formatTimestamp(order.UpdatedAt),
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/events"
+)
+
+// streamHeartbeat keeps idle connections from being closed by proxies
+const streamHeartbeat = 25 * time.Second
+
+// HandleOrderStream handles GET /v1/admin/orders/stream. It streams new orders and status
+// changes as Server-Sent Events until the client disconnects.
+func HandleOrderStream(feed *events.Feed, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		if feed == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "order stream is not enabled"})
+			return
+		}
+
+		// The server's write timeout would cut the stream off
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			logger.Warn("Failed to lift write deadline for order stream", zap.Error(err))
+		}
+
+		messages, unsubscribe := feed.Subscribe()
+		defer unsubscribe()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.WriteString(": connected\n\n")
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		ctx := c.Request.Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				c.Writer.WriteString(": ping\n\n")
+				c.Writer.Flush()
+			case msg, ok := <-messages:
+				if !ok {
+					// Dropped for falling behind; the client reconnects and reloads
+					return
+				}
+				c.SSEvent(msg.Type, msg.Data)
+				c.Writer.Flush()
+			}
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"math"
 	"net/http"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,6 +12,7 @@ import (
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/service"
 	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
@@ -17,35 +20,100 @@ import (
 type OrderResponse struct {
 	ID                  string                 `json:"id"`
 	PartnerOrderID      string                 `json:"partner_order_id"`
+	Reference           *string                `json:"reference,omitempty"`
 	Status              domain.OrderStatus     `json:"status"`
+	StatusLabel         string                 `json:"status_label"`
+	Locale              domain.Locale          `json:"locale"`
+	TextDirection       string                 `json:"text_direction"`
 	ShopifyDraftOrderID *int64                 `json:"shopify_draft_order_id,omitempty"`
 	ShopifyOrderID      *int64                 `json:"shopify_order_id,omitempty"`
+
+	// ShopifyDraftOrderName and ShopifyOrderName are the names shown in Shopify admin (e.g. #D12, #1001)
+	ShopifyDraftOrderName *string `json:"shopify_draft_order_name,omitempty"`
+	ShopifyOrderName      *string `json:"shopify_order_name,omitempty"`
+
+	ShopifyCustomerID   *int64                 `json:"shopify_customer_id,omitempty"`
 	CustomerName        string                 `json:"customer_name"`
 	CustomerPhone       string                 `json:"customer_phone,omitempty"`
-	ShippingAddress     map[string]interface{} `json:"shipping_address"`
+	CustomerPhoneE164   *string                `json:"customer_phone_e164,omitempty"`
+	CustomerEmail       *string                `json:"customer_email,omitempty"`
+	ShippingAddress     AddressResponse        `json:"shipping_address"`
 	CartTotal           float64               `json:"cart_total"`
 	PaymentStatus       string                 `json:"payment_status,omitempty"`
 	PaymentMethod       *string               `json:"payment_method,omitempty"`
+	Channel             *string               `json:"channel,omitempty"`
+	RejectionCode       *domain.RejectionCode `json:"rejection_code,omitempty"`
 	RejectionReason     *string               `json:"rejection_reason,omitempty"`
 	TrackingCarrier     *string               `json:"tracking_carrier,omitempty"`
 	TrackingNumber      *string               `json:"tracking_number,omitempty"`
 	TrackingURL         *string               `json:"tracking_url,omitempty"`
+	TrackingMessage     *string               `json:"tracking_message,omitempty"`
+	HoldReason          *string               `json:"hold_reason,omitempty"`
+	HeldAt              *string               `json:"held_at,omitempty"`
+	ConfirmedAt         *string               `json:"confirmed_at,omitempty"`
+	RejectedAt          *string               `json:"rejected_at,omitempty"`
+	ShippedAt           *string               `json:"shipped_at,omitempty"`
+	DeliveredAt         *string               `json:"delivered_at,omitempty"`
+	CancelledAt         *string               `json:"cancelled_at,omitempty"`
 	Items               []OrderItemResponse   `json:"items"`
+	Parcel              ParcelResponse         `json:"parcel"`
 	CreatedAt           string                 `json:"created_at"`
 	UpdatedAt           string                 `json:"updated_at"`
 }
 
+type AddressResponse struct {
+	Street     string  `json:"street"`
+	Address2   *string `json:"address2,omitempty"`
+	City       string  `json:"city"`
+	State      *string `json:"state,omitempty"`
+	PostalCode string  `json:"postal_code"`
+	Country    string  `json:"country"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
 type OrderItemResponse struct {
 	SKU             string  `json:"sku"`
 	Title           string  `json:"title"`
 	Price           float64 `json:"price"`
+	ListPrice       float64 `json:"list_price"`
+	Discount        float64 `json:"discount"`
+	LineTotal       float64 `json:"line_total"`
 	Quantity        int     `json:"quantity"`
 	ProductURL      *string `json:"product_url,omitempty"`
 	IsSupplierItem  bool    `json:"is_supplier_item"`
 	ShopifyVariantID *int64 `json:"shopify_variant_id,omitempty"`
+	WeightGrams      *int    `json:"weight_grams,omitempty"`
+	Dimensions       *DimensionsResponse `json:"dimensions,omitempty"`
+	MatchMethod      *string `json:"match_method,omitempty"`
+	// Status is where the item stands (see service.ItemProgressFor); ShippedQuantity is how many units left
+	Status           string  `json:"status"`
+	ShippedQuantity  int     `json:"shipped_quantity"`
+	SubstitutedSKU   *string `json:"substituted_sku,omitempty"`
+}
+
+// DimensionsResponse represents the dimensions of one unit in centimetres
+type DimensionsResponse struct {
+	LengthCm float64 `json:"length_cm"`
+	WidthCm  float64 `json:"width_cm"`
+	HeightCm float64 `json:"height_cm"`
+}
+
+// ParcelResponse represents the aggregated weight and size of an order's items
+type ParcelResponse struct {
+	WeightGrams            int                 `json:"weight_grams"`
+	VolumeCm3              float64             `json:"volume_cm3"`
+	LargestItem            *DimensionsResponse `json:"largest_item,omitempty"`
+	Units                  int                 `json:"units"`
+	UnitsWithoutWeight     int                 `json:"units_without_weight"`
+	UnitsWithoutDimensions int                 `json:"units_without_dimensions"`
+	Complete               bool                `json:"complete"`
 }
 
+// orderResponseFields are the fields ?fields= can select on order responses
+var orderResponseFields = jsonFieldNames(reflect.TypeOf(OrderResponse{}))
+
 // HandleGetOrder handles GET /v1/orders/:id
+// ?fields=id,status,tracking_number returns only those fields
 func HandleGetOrder(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get partner from context
@@ -63,6 +131,12 @@ func HandleGetOrder(repos *repository.Repositories, logger *zap.Logger) gin.Hand
 			return
 		}
 
+		fields, err := parseFields(c, orderResponseFields)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		// Get order
 		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
 		if err != nil {
@@ -81,64 +155,165 @@ func HandleGetOrder(repos *repository.Repositories, logger *zap.Logger) gin.Hand
 			return
 		}
 
-		// Get order items
-		items, err := repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), orderID)
-		if err != nil {
-			logger.Error("Failed to get order items", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		// Get order items, unless the selected fields do not need them
+		var items []*domain.SupplierOrderItem
+		if fields.has("items") || fields.has("parcel") {
+			items, err = repos.SupplierOrderItem.GetByOrderID(c.Request.Context(), orderID)
+			if err != nil {
+				logger.Error("Failed to get order items", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+				return
+			}
+		}
+		substitutions, ok := loadItemSubstitutions(c, repos, fields, orderID, logger)
+		if !ok {
 			return
 		}
 
-		// Build response
-		itemResponses := make([]OrderItemResponse, len(items))
-		for i, item := range items {
-			itemResponses[i] = OrderItemResponse{
-				SKU:              item.SKU,
-				Title:            item.Title,
-				Price:            item.Price,
-				Quantity:         item.Quantity,
-				ProductURL:       item.ProductURL,
-				IsSupplierItem:   item.IsSupplierItem,
-				ShopifyVariantID: item.ShopifyVariantID,
-			}
-		}
+		c.Header(middleware.ETagHeader, middleware.OrderETag(order))
+		c.JSON(http.StatusOK, fields.apply(buildOrderResponse(order, items, substitutions)))
+	}
+}
 
-		response := OrderResponse{
-			ID:                  order.ID.String(),
-			PartnerOrderID:      order.PartnerOrderID,
-			Status:              order.Status,
-			ShopifyDraftOrderID: order.ShopifyDraftOrderID,
-			ShopifyOrderID:      order.ShopifyOrderID,
-			CustomerName:        order.CustomerName,
-			ShippingAddress:     order.ShippingAddress,
-			CartTotal:           order.CartTotal,
-			Items:               itemResponses,
-			CreatedAt:           order.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:           order.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+// loadItemSubstitutions loads the order's substitutions when items are selected, for their
+// item status. It writes the error response and returns false when they cannot be loaded.
+func loadItemSubstitutions(c *gin.Context, repos *repository.Repositories, fields fieldSet, orderID uuid.UUID, logger *zap.Logger) ([]*domain.ItemSubstitution, bool) {
+	if !fields.has("items") {
+		return nil, true
+	}
+	substitutions, err := repos.ItemSubstitution.ListByOrderID(c.Request.Context(), orderID)
+	if err != nil {
+		logger.Error("Failed to list item substitutions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+	return substitutions, true
+}
 
-		if order.CustomerPhone != "" {
-			response.CustomerPhone = order.CustomerPhone
-		}
-		if order.PaymentStatus != "" {
-			response.PaymentStatus = order.PaymentStatus
-		}
-		if order.PaymentMethod != nil {
-			response.PaymentMethod = order.PaymentMethod
-		}
-		if order.RejectionReason != nil {
-			response.RejectionReason = order.RejectionReason
-		}
-		if order.TrackingCarrier != nil {
-			response.TrackingCarrier = order.TrackingCarrier
-		}
-		if order.TrackingNumber != nil {
-			response.TrackingNumber = order.TrackingNumber
+// buildParcelResponse converts an aggregated parcel into the API response
+func buildParcelResponse(parcel domain.Parcel) ParcelResponse {
+	return ParcelResponse{
+		WeightGrams:            parcel.WeightGrams,
+		VolumeCm3:              math.Round(parcel.VolumeCm3*100) / 100,
+		LargestItem:            buildDimensionsResponse(parcel.LargestItem),
+		Units:                  parcel.Units,
+		UnitsWithoutWeight:     parcel.UnitsWithoutWeight,
+		UnitsWithoutDimensions: parcel.UnitsWithoutDimensions,
+		Complete:               parcel.Complete(),
+	}
+}
+
+func buildDimensionsResponse(dimensions *domain.Dimensions) *DimensionsResponse {
+	if dimensions == nil {
+		return nil
+	}
+	return &DimensionsResponse{
+		LengthCm: dimensions.LengthCm,
+		WidthCm:  dimensions.WidthCm,
+		HeightCm: dimensions.HeightCm,
+	}
+}
+
+// buildOrderResponse converts an order, its items and their substitutions into the API response
+func buildOrderResponse(order *domain.SupplierOrder, items []*domain.SupplierOrderItem, substitutions []*domain.ItemSubstitution) OrderResponse {
+	itemResponses := make([]OrderItemResponse, len(items))
+	for i, item := range items {
+		progress := service.ItemProgressFor(order, item, substitutions)
+		itemResponses[i] = OrderItemResponse{
+			SKU:              item.SKU,
+			Title:            item.Title,
+			Price:            item.Price,
+			ListPrice:        item.ListPrice,
+			Discount:         item.Discount,
+			LineTotal:        math.Round(item.Price*float64(item.Quantity)*100) / 100,
+			Quantity:         item.Quantity,
+			ProductURL:       item.ProductURL,
+			IsSupplierItem:   item.IsSupplierItem,
+			ShopifyVariantID: item.ShopifyVariantID,
+			WeightGrams:      item.WeightGrams,
+			Dimensions:       buildDimensionsResponse(item.Dimensions),
+			MatchMethod:      item.MatchMethod,
+			Status:           progress.Status,
+			ShippedQuantity:  progress.ShippedQuantity,
+			SubstitutedSKU:   progress.SubstitutedSKU,
 		}
-		if order.TrackingURL != nil {
-			response.TrackingURL = order.TrackingURL
+	}
+
+	locale := service.ResolveLocale(order)
+	response := OrderResponse{
+		ID:                  order.ID.String(),
+		PartnerOrderID:      order.PartnerOrderID,
+		Reference:           order.Reference,
+		Status:              order.Status,
+		StatusLabel:         service.StatusLabel(locale, order.Status),
+		Locale:              locale,
+		TextDirection:       locale.Direction(),
+		ShopifyDraftOrderID: order.ShopifyDraftOrderID,
+		ShopifyOrderID:      order.ShopifyOrderID,
+		ShopifyDraftOrderName: order.ShopifyDraftOrderName,
+		ShopifyOrderName:    order.ShopifyOrderName,
+		CustomerName:        order.CustomerName,
+		ShippingAddress:     buildAddressResponse(order.ShippingAddress),
+		CartTotal:           order.CartTotal,
+		Items:               itemResponses,
+		Parcel:              buildParcelResponse(domain.ParcelFor(items)),
+		CreatedAt:           formatTimestamp(order.CreatedAt),
+		UpdatedAt:           formatTimestamp(order.UpdatedAt),
+	}
+
+	if order.CustomerPhone != "" {
+		response.CustomerPhone = order.CustomerPhone
+	}
+	response.CustomerPhoneE164 = order.CustomerPhoneE164
+	response.CustomerEmail = order.CustomerEmail
+	response.ShopifyCustomerID = order.ShopifyCustomerID
+	if order.PaymentStatus != "" {
+		response.PaymentStatus = order.PaymentStatus
+	}
+	response.Channel = order.Channel
+	if order.PaymentMethod != nil {
+		response.PaymentMethod = order.PaymentMethod
+	}
+	response.RejectionCode = order.RejectionCode
+	if order.RejectionReason != nil {
+		response.RejectionReason = order.RejectionReason
+	}
+	if order.TrackingCarrier != nil {
+		response.TrackingCarrier = order.TrackingCarrier
+	}
+	if order.TrackingNumber != nil {
+		response.TrackingNumber = order.TrackingNumber
+	}
+	if order.TrackingURL != nil {
+		response.TrackingURL = order.TrackingURL
+	}
+	if order.TrackingNumber != nil && order.TrackingCarrier != nil {
+		// Ready-made text for the partner's tracking page
+		if message, ok := service.RenderOrderMessage(locale, service.MessageOrderTracking, order); ok {
+			response.TrackingMessage = &message
 		}
+	}
+	if order.HoldReason != nil {
+		response.HoldReason = order.HoldReason
+	}
+	response.HeldAt = formatTimestampPtr(order.HeldAt)
+	response.ConfirmedAt = formatTimestampPtr(order.ConfirmedAt)
+	response.RejectedAt = formatTimestampPtr(order.RejectedAt)
+	response.ShippedAt = formatTimestampPtr(order.ShippedAt)
+	response.DeliveredAt = formatTimestampPtr(order.DeliveredAt)
+	response.CancelledAt = formatTimestampPtr(order.CancelledAt)
+
+	return response
+}
 
-		c.JSON(http.StatusOK, response)
+func buildAddressResponse(addr domain.Address) AddressResponse {
+	return AddressResponse{
+		Street:     addr.Street,
+		Address2:   addr.Address2,
+		City:       addr.City,
+		State:      addr.State,
+		PostalCode: addr.PostalCode,
+		Country:    addr.Country,
+		Notes:      addr.Notes,
 	}
 }
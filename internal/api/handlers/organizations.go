@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// CreateOrganizationRequest represents create organization request
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// UpdatePartnerOrganizationRequest represents update partner organization request; a null
+// organization_id removes the partner from its organization
+type UpdatePartnerOrganizationRequest struct {
+	OrganizationID *string `json:"organization_id"`
+}
+
+// HandleCreateOrganization handles POST /v1/admin/organizations
+func HandleCreateOrganization(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var req CreateOrganizationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		organization, err := services.Onboarding.CreateOrganization(c.Request.Context(), fmt.Sprintf("partner:%s", admin.ID), req.Name)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to create organization", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create organization"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, organizationResponse(organization, nil))
+	}
+}
+
+// HandleListOrganizations handles GET /v1/admin/organizations
+func HandleListOrganizations(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		organizations, err := repos.Organization.List(c.Request.Context())
+		if err != nil {
+			logger.Error("Failed to list organizations", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		response := make([]gin.H, len(organizations))
+		for i, organization := range organizations {
+			response[i] = organizationResponse(organization, nil)
+		}
+		c.JSON(http.StatusOK, gin.H{"organizations": response})
+	}
+}
+
+// HandleAdminGetOrganization handles GET /v1/admin/organizations/:id
+func HandleAdminGetOrganization(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		organizationID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization ID"})
+			return
+		}
+
+		organization, members, ok := loadOrganization(c, repos, organizationID, logger)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, organizationResponse(organization, members))
+	}
+}
+
+// HandleUpdatePartnerOrganization handles PUT /v1/admin/partners/:id/organization
+func HandleUpdatePartnerOrganization(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, _ := middleware.GetPartnerFromContext(c)
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		var req UpdatePartnerOrganizationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		var organizationID *uuid.UUID
+		if req.OrganizationID != nil {
+			id, err := uuid.Parse(*req.OrganizationID)
+			if err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": fieldErrors(map[string]string{"organization_id": "invalid organization ID"}),
+				})
+				return
+			}
+			organizationID = &id
+		}
+
+		err := services.Onboarding.UpdatePartnerOrganization(
+			c.Request.Context(),
+			fmt.Sprintf("partner:%s", admin.ID),
+			partner,
+			organizationID,
+		)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update partner organization", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update partner organization"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id":      partner.ID.String(),
+			"organization_id": partner.OrganizationID,
+		})
+	}
+}
+
+// HandleGetOrganization handles GET /v1/organization
+// Returns the calling partner's organization and its partners
+func HandleGetOrganization(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		organization, members, ok := loadPartnerOrganization(c, repos, partner, logger)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, organizationResponse(organization, members))
+	}
+}
+
+// HandleListOrganizationOrders handles GET /v1/organization/orders
+// Lists the orders of every partner in the caller's organization, newest first. Each order
+// names the partner (API key) that submitted it; ?partner_id= limits the list to one partner.
+func HandleListOrganizationOrders(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		_, members, ok := loadPartnerOrganization(c, repos, partner, logger)
+		if !ok {
+			return
+		}
+
+		names := make(map[uuid.UUID]string, len(members))
+		partnerIDs := make([]uuid.UUID, len(members))
+		for i, member := range members {
+			names[member.ID] = member.Name
+			partnerIDs[i] = member.ID
+		}
+		if partnerIDStr := c.Query("partner_id"); partnerIDStr != "" {
+			partnerID, err := uuid.Parse(partnerIDStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid partner ID"})
+				return
+			}
+			if _, ok := names[partnerID]; !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "partner not found in organization"})
+				return
+			}
+			partnerIDs = []uuid.UUID{partnerID}
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		var cursor *domain.OrderCursor
+		if cursorStr := c.Query("cursor"); cursorStr != "" {
+			cursor, err = domain.DecodeOrderCursor(cursorStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+		}
+
+		orders, err := repos.SupplierOrder.ListByPartnerIDsAfter(c.Request.Context(), partnerIDs, cursor, limit)
+		if err != nil {
+			logger.Error("Failed to list organization orders", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		orderResponses := make([]gin.H, len(orders))
+		for i, order := range orders {
+			orderResponses[i] = gin.H{
+				"id":                 order.ID.String(),
+				"partner_id":         order.PartnerID.String(),
+				"partner_name":       names[order.PartnerID],
+				"partner_order_id":   order.PartnerOrderID,
+				"reference":          order.Reference,
+				"status":             order.Status,
+				"shopify_order_name": order.ShopifyOrderName,
+				"customer_name":      order.CustomerName,
+				"cart_total":         order.CartTotal,
+				"tracking_number":    order.TrackingNumber,
+				"created_at":         formatTimestamp(order.CreatedAt),
+				"updated_at":         formatTimestamp(order.UpdatedAt),
+			}
+		}
+
+		response := gin.H{
+			"orders": orderResponses,
+			"limit":  limit,
+		}
+		if len(orders) == limit {
+			response["next_cursor"] = domain.CursorAfter(orders[len(orders)-1]).Encode()
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleGetOrganizationReport handles GET /v1/organization/report
+// Returns the order funnel of the caller's organization over ?from= and ?to=, in total and
+// per partner
+func HandleGetOrganizationReport(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		organization, members, ok := loadPartnerOrganization(c, repos, partner, logger)
+		if !ok {
+			return
+		}
+
+		from, to, ok := parseReportPeriod(c)
+		if !ok {
+			return
+		}
+
+		partnerIDs := make([]uuid.UUID, len(members))
+		for i, member := range members {
+			partnerIDs[i] = member.ID
+		}
+
+		statsService := service.NewStatsService(repos, logger)
+		report, err := statsService.PartnersFunnel(c.Request.Context(), partnerIDs, from, to)
+		if err != nil {
+			logger.Error("Failed to build organization report", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"organization_id": organization.ID.String(),
+			"from":            formatTimestamp(report.From),
+			"to":              formatTimestamp(report.To),
+			"total":           funnelResponse(report.Total()),
+			"partners":        partnerFunnelsResponse(report),
+		})
+	}
+}
+
+// loadPartnerOrganization loads the partner's organization and its partners, responding 404
+// when the partner is not in one
+func loadPartnerOrganization(c *gin.Context, repos *repository.Repositories, partner *domain.Partner, logger *zap.Logger) (*domain.Organization, []*domain.Partner, bool) {
+	if partner.OrganizationID == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "partner does not belong to an organization"})
+		return nil, nil, false
+	}
+	return loadOrganization(c, repos, *partner.OrganizationID, logger)
+}
+
+func loadOrganization(c *gin.Context, repos *repository.Repositories, organizationID uuid.UUID, logger *zap.Logger) (*domain.Organization, []*domain.Partner, bool) {
+	organization, err := repos.Organization.GetByID(c.Request.Context(), organizationID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+			return nil, nil, false
+		}
+		logger.Error("Failed to get organization", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, nil, false
+	}
+
+	members, err := repos.Partner.ListByOrganizationID(c.Request.Context(), organizationID)
+	if err != nil {
+		logger.Error("Failed to list organization partners", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, nil, false
+	}
+	if members == nil {
+		members = []*domain.Partner{}
+	}
+
+	return organization, members, true
+}
+
+// organizationResponse renders an organization; members are left out when nil
+func organizationResponse(organization *domain.Organization, members []*domain.Partner) gin.H {
+	response := gin.H{
+		"id":         organization.ID.String(),
+		"name":       organization.Name,
+		"created_at": formatTimestamp(organization.CreatedAt),
+	}
+	if members != nil {
+		partners := make([]gin.H, len(members))
+		for i, member := range members {
+			partners[i] = gin.H{
+				"id":        member.ID.String(),
+				"name":      member.Name,
+				"is_active": member.IsActive,
+			}
+		}
+		response["partners"] = partners
+	}
+	return response
+}
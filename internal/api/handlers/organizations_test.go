@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+func (f *fakeOrders) ListByPartnerIDsAfter(ctx context.Context, partnerIDs []uuid.UUID, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	var orders []*domain.SupplierOrder
+	for _, order := range f.orders {
+		for _, id := range partnerIDs {
+			if order.PartnerID == id {
+				orders = append(orders, order)
+			}
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.After(orders[j].CreatedAt) })
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+// fakeOrganizations serves one organization and its partners
+type fakeOrganizations struct {
+	repository.OrganizationRepository
+	organization *domain.Organization
+}
+
+func (f *fakeOrganizations) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	if f.organization == nil || f.organization.ID != id {
+		return nil, &errors.ErrNotFound{Resource: "organization", ID: id.String()}
+	}
+	return f.organization, nil
+}
+
+type fakePartners struct {
+	repository.PartnerRepository
+	partners []*domain.Partner
+}
+
+func (f *fakePartners) ListByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]*domain.Partner, error) {
+	var members []*domain.Partner
+	for _, partner := range f.partners {
+		if partner.OrganizationID != nil && *partner.OrganizationID == organizationID {
+			members = append(members, partner)
+		}
+	}
+	return members, nil
+}
+
+func TestHandleListOrganizationOrders(t *testing.T) {
+	organization := &domain.Organization{ID: uuid.New(), Name: "Acme"}
+	downtown := &domain.Partner{ID: uuid.New(), Name: "Acme Downtown", OrganizationID: &organization.ID}
+	airport := &domain.Partner{ID: uuid.New(), Name: "Acme Airport", OrganizationID: &organization.ID}
+	outsider := &domain.Partner{ID: uuid.New(), Name: "Other Shop"}
+
+	now := time.Now()
+	orders := map[uuid.UUID]*domain.SupplierOrder{}
+	for i, partner := range []*domain.Partner{downtown, airport, outsider} {
+		order := &domain.SupplierOrder{ID: uuid.New(), PartnerID: partner.ID, Status: domain.OrderStatusConfirmed, CreatedAt: now.Add(-time.Duration(i) * time.Minute)}
+		orders[order.ID] = order
+	}
+
+	repos := &repository.Repositories{
+		Partner:       &fakePartners{partners: []*domain.Partner{downtown, airport, outsider}},
+		Organization:  &fakeOrganizations{organization: organization},
+		SupplierOrder: &fakeOrders{orders: orders},
+	}
+
+	tests := []struct {
+		name        string
+		caller      *domain.Partner
+		query       string
+		wantStatus  int
+		wantPartner []string
+	}{
+		{
+			name:        "every partner of the organization",
+			caller:      airport,
+			wantStatus:  http.StatusOK,
+			wantPartner: []string{"Acme Downtown", "Acme Airport"},
+		},
+		{
+			name:        "one partner",
+			caller:      airport,
+			query:       "?partner_id=" + downtown.ID.String(),
+			wantStatus:  http.StatusOK,
+			wantPartner: []string{"Acme Downtown"},
+		},
+		{
+			name:       "partner outside the organization",
+			caller:     airport,
+			query:      "?partner_id=" + outsider.ID.String(),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "caller without organization",
+			caller:     outsider,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/organization/orders", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, tt.caller)
+			}, HandleListOrganizationOrders(repos, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/organization/orders"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			got, _ := decodeBody(t, w)["orders"].([]interface{})
+			if len(got) != len(tt.wantPartner) {
+				t.Fatalf("orders = %v, want %d", got, len(tt.wantPartner))
+			}
+			for i, name := range tt.wantPartner {
+				if order := got[i].(map[string]interface{}); order["partner_name"] != name {
+					t.Errorf("orders[%d].partner_name = %v, want %s", i, order["partner_name"], name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+)
+
+// HandleGetPanics handles GET /v1/admin/panics
+// Returns the panics recovered from handlers since the process started (format=prometheus for metrics)
+func HandleGetPanics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		stats := middleware.Panics()
+		if c.Query("format") == "prometheus" {
+			c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(formatPanicMetrics(stats)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"panics_total":  stats.Total,
+			"last_panic_at": formatTimestampPtr(stats.LastAt),
+		})
+	}
+}
+
+// formatPanicMetrics renders the panic counter in the Prometheus text exposition format
+func formatPanicMetrics(stats middleware.PanicStats) string {
+	var b strings.Builder
+
+	b.WriteString("# HELP b2b_http_panics_total Panics recovered from request handlers since the process started.\n")
+	b.WriteString("# TYPE b2b_http_panics_total counter\n")
+	fmt.Fprintf(&b, "b2b_http_panics_total %d\n", stats.Total)
+
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+const (
+	// maxReportRows is the most groups a report returns; more set truncated
+	maxReportRows = 5000
+	// maxReportPeriod is the longest period a report covers
+	maxReportPeriod = 366 * 24 * time.Hour
+	// reportTimeout bounds the report query
+	reportTimeout = 30 * time.Second
+)
+
+// RunReportRequest represents run report request
+type RunReportRequest struct {
+	GroupBy []domain.ReportDimension `json:"group_by" binding:"max=3,dive,oneof=partner status day"`
+	Metrics []domain.ReportMetric    `json:"metrics" binding:"required,min=1,max=3,dive,oneof=count revenue avg_time_to_ship"`
+	Filters ReportFilters            `json:"filters"`
+	Format  string                   `json:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// ReportFilters represents the order filters of a report; from and to default to the last 30 days
+type ReportFilters struct {
+	PartnerID *uuid.UUID          `json:"partner_id,omitempty"`
+	Status    *domain.OrderStatus `json:"status,omitempty"`
+	From      *time.Time          `json:"from,omitempty"`
+	To        *time.Time          `json:"to,omitempty"`
+}
+
+// HandleRunReport handles POST /v1/admin/reports
+// Runs a custom report over orders: grouped by partner, status and/or day, with count,
+// revenue and average time to ship per group, as JSON or CSV
+func HandleRunReport(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req RunReportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		to := time.Now()
+		if req.Filters.To != nil {
+			to = *req.Filters.To
+		}
+		from := to.Add(-defaultUsagePeriod)
+		if req.Filters.From != nil {
+			from = *req.Filters.From
+		}
+
+		invalid := make(map[string]string)
+		if hasDuplicates(req.GroupBy) {
+			invalid["group_by"] = "must not repeat a dimension"
+		}
+		if hasDuplicates(req.Metrics) {
+			invalid["metrics"] = "must not repeat a metric"
+		}
+		if req.Filters.Status != nil && !req.Filters.Status.IsValid() {
+			invalid["filters.status"] = "is not a valid order status"
+		}
+		if !from.Before(to) {
+			invalid["filters.to"] = "must be after from"
+		} else if to.Sub(from) > maxReportPeriod {
+			invalid["filters.from"] = "the period must not exceed 366 days"
+		}
+		if len(invalid) > 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(invalid),
+			})
+			return
+		}
+
+		query := domain.ReportQuery{
+			GroupBy: req.GroupBy,
+			Metrics: req.Metrics,
+			Filter: domain.OrderFilter{
+				PartnerID: req.Filters.PartnerID,
+				Status:    req.Filters.Status,
+				From:      &from,
+				To:        &to,
+			},
+			// One more than returned, to tell a truncated report
+			Limit: maxReportRows + 1,
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), reportTimeout)
+		defer cancel()
+
+		rows, err := repos.SupplierOrder.Report(ctx, query)
+		if err != nil {
+			logger.Error("Failed to run report", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to run report"})
+			return
+		}
+		truncated := len(rows) > maxReportRows
+		if truncated {
+			rows = rows[:maxReportRows]
+		}
+
+		columns := reportColumns(query)
+		if req.Format == "csv" {
+			var buf bytes.Buffer
+			w := csv.NewWriter(&buf)
+			w.Write(columns)
+			for _, row := range rows {
+				record := make([]string, len(columns))
+				values := reportRowValues(row)
+				for i, column := range columns {
+					record[i] = formatReportCSVValue(values[column])
+				}
+				w.Write(record)
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				logger.Error("Failed to write report CSV", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to run report"})
+				return
+			}
+			if truncated {
+				c.Header("X-Report-Truncated", "true")
+			}
+			c.Header("Content-Disposition", `attachment; filename="report-`+time.Now().UTC().Format("20060102-150405")+`.csv"`)
+			c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+			return
+		}
+
+		rowResponses := make([]gin.H, len(rows))
+		for i, row := range rows {
+			values := reportRowValues(row)
+			rowResponse := gin.H{}
+			for _, column := range columns {
+				rowResponse[column] = values[column]
+			}
+			rowResponses[i] = rowResponse
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":      formatTimestamp(from),
+			"to":        formatTimestamp(to),
+			"group_by":  query.GroupBy,
+			"metrics":   query.Metrics,
+			"columns":   columns,
+			"rows":      rowResponses,
+			"truncated": truncated,
+		})
+	}
+}
+
+// reportColumns names the columns of a report: the dimensions in group_by order, then the metrics
+func reportColumns(query domain.ReportQuery) []string {
+	var columns []string
+	for _, dimension := range query.GroupBy {
+		switch dimension {
+		case domain.ReportDimensionPartner:
+			columns = append(columns, "partner_id", "partner_name")
+		case domain.ReportDimensionStatus:
+			columns = append(columns, "status")
+		case domain.ReportDimensionDay:
+			columns = append(columns, "day")
+		}
+	}
+	for _, metric := range query.Metrics {
+		if metric == domain.ReportMetricAvgTimeToShip {
+			columns = append(columns, "avg_time_to_ship_hours")
+			continue
+		}
+		columns = append(columns, string(metric))
+	}
+	return columns
+}
+
+// reportRowValues maps the columns of a report row to their values; nil for no value
+func reportRowValues(row *domain.ReportRow) map[string]interface{} {
+	values := map[string]interface{}{
+		"partner_id":             nil,
+		"partner_name":           row.PartnerName,
+		"status":                 row.Status,
+		"day":                    nil,
+		"count":                  row.Count,
+		"revenue":                nil,
+		"avg_time_to_ship_hours": nil,
+	}
+	if row.PartnerID != nil {
+		values["partner_id"] = row.PartnerID.String()
+	}
+	if row.Day != nil {
+		values["day"] = row.Day.Format("2006-01-02")
+	}
+	if row.Revenue != nil {
+		values["revenue"] = math.Round(*row.Revenue*100) / 100
+	}
+	if row.AvgTimeToShip != nil {
+		values["avg_time_to_ship_hours"] = math.Round(*row.AvgTimeToShip*100) / 100
+	}
+	return values
+}
+
+// formatReportCSVValue renders a report value as a CSV field; no value is an empty field
+func formatReportCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case *domain.OrderStatus:
+		if v != nil {
+			return string(*v)
+		}
+	case *int64:
+		if v != nil {
+			return strconv.FormatInt(*v, 10)
+		}
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	return ""
+}
+
+// hasDuplicates reports whether a value appears more than once
+func hasDuplicates[T comparable](values []T) bool {
+	seen := make(map[T]bool, len(values))
+	for _, value := range values {
+		if seen[value] {
+			return true
+		}
+		seen[value] = true
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// fakeReportOrders answers every report with fixed rows and keeps the query
+type fakeReportOrders struct {
+	repository.SupplierOrderRepository
+	rows  []*domain.ReportRow
+	query *domain.ReportQuery
+}
+
+func (f *fakeReportOrders) Report(ctx context.Context, query domain.ReportQuery) ([]*domain.ReportRow, error) {
+	f.query = &query
+	return f.rows, nil
+}
+
+func TestHandleRunReport(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	status := domain.OrderStatusShipped
+	count := int64(3)
+	revenue := 120.456
+	row := &domain.ReportRow{Day: &day, Status: &status, Count: &count, Revenue: &revenue}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantField  string
+		wantBody   string
+	}{
+		{
+			name:       "json",
+			body:       `{"group_by": ["day", "status"], "metrics": ["count", "revenue"]}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `"rows":[{"count":3,"day":"2024-05-01","revenue":120.46,"status":"SHIPPED"}]`,
+		},
+		{
+			name:       "csv",
+			body:       `{"group_by": ["day", "status"], "metrics": ["count", "revenue"], "format": "csv"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   "day,status,count,revenue\n2024-05-01,SHIPPED,3,120.46\n",
+		},
+		{
+			name:       "unknown metric",
+			body:       `{"metrics": ["profit"]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "metrics[0]",
+		},
+		{
+			name:       "repeated dimension",
+			body:       `{"group_by": ["day", "day"], "metrics": ["count"]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "group_by",
+		},
+		{
+			name:       "period too long",
+			body:       `{"metrics": ["count"], "filters": {"from": "2020-01-01T00:00:00Z", "to": "2024-01-01T00:00:00Z"}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantField:  "filters.from",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &fakeReportOrders{rows: []*domain.ReportRow{row}}
+			repos := &repository.Repositories{SupplierOrder: orders}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/admin/reports", func(c *gin.Context) {
+				c.Set(middleware.PartnerContextKey, &domain.Partner{ID: uuid.New()})
+			}, HandleRunReport(repos, zap.NewNop()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/admin/reports", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK {
+				if !strings.Contains(w.Body.String(), tt.wantBody) {
+					t.Errorf("body = %s, want it to contain %s", w.Body.String(), tt.wantBody)
+				}
+				if orders.query.Filter.From == nil || orders.query.Limit != maxReportRows+1 {
+					t.Errorf("query = %+v, want the default period and the row limit", orders.query)
+				}
+				return
+			}
+			details, _ := decodeBody(t, w)["details"].([]interface{})
+			if len(details) != 1 || details[0].(map[string]interface{})["field"] != tt.wantField {
+				t.Errorf("details = %v, want an error for %s", details, tt.wantField)
+			}
+			if orders.query != nil {
+				t.Errorf("ran a report for an invalid request")
+			}
+		})
+	}
+}
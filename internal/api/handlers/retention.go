@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// HandleGetRetention handles GET /v1/admin/retention
+// Returns the retention periods and purge counters (format=prometheus for metrics)
+func HandleGetRetention(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		metrics := service.RetentionMetrics()
+		if c.Query("format") == "prometheus" {
+			c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(formatRetentionMetrics(metrics)))
+			return
+		}
+
+		tables := make([]gin.H, len(metrics))
+		for i, m := range metrics {
+			table := gin.H{
+				"table":        m.Table,
+				"purged_total": m.PurgedTotal,
+				"runs":         m.Runs,
+				"failures":     m.Failures,
+				"last_purged":  m.LastPurged,
+				"last_run_at":  nil,
+			}
+			if !m.LastRunAt.IsZero() {
+				table["last_run_at"] = formatTimestamp(m.LastRunAt)
+			}
+			tables[i] = table
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"interval": cfg.Retention.Interval.String(),
+			"archive":  cfg.Archive.Enabled(),
+			"periods": gin.H{
+				service.RetentionTableIdempotencyKeys: retentionPeriod(cfg.Retention.IdempotencyKeys),
+				service.RetentionTableOrderEvents:     retentionPeriod(cfg.Retention.OrderEvents),
+				service.RetentionTableAuditLogs:       retentionPeriod(cfg.Retention.AuditLogs),
+			},
+			"tables": tables,
+		})
+	}
+}
+
+// HandlePurgeRetention handles POST /v1/admin/retention/purge
+// Runs one purge now, the same as the background job
+func HandlePurgeRetention(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		retentionService := service.NewRetentionService(cfg, repos, logger)
+		results := retentionService.Purge(c.Request.Context())
+
+		purged := make([]gin.H, len(results))
+		for i, result := range results {
+			purged[i] = gin.H{
+				"table":    result.Table,
+				"before":   formatTimestamp(result.Before),
+				"purged":   result.Purged,
+				"complete": result.Complete,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tables": purged})
+	}
+}
+
+// retentionPeriod renders a retention period, nil meaning rows are kept forever
+func retentionPeriod(period time.Duration) *string {
+	if period <= 0 {
+		return nil
+	}
+	value := period.String()
+	return &value
+}
+
+// formatRetentionMetrics renders the purge counters in the Prometheus text exposition format
+func formatRetentionMetrics(metrics []service.RetentionTableMetrics) string {
+	var b strings.Builder
+
+	b.WriteString("# HELP b2b_retention_purged_rows_total Rows deleted by the retention job since the process started.\n")
+	b.WriteString("# TYPE b2b_retention_purged_rows_total counter\n")
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "b2b_retention_purged_rows_total{table=%s} %d\n", promLabel(m.Table), m.PurgedTotal)
+	}
+
+	b.WriteString("# HELP b2b_retention_runs_total Retention purge runs, by table.\n")
+	b.WriteString("# TYPE b2b_retention_runs_total counter\n")
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "b2b_retention_runs_total{table=%s} %d\n", promLabel(m.Table), m.Runs)
+	}
+
+	b.WriteString("# HELP b2b_retention_failures_total Retention purge runs that failed, by table.\n")
+	b.WriteString("# TYPE b2b_retention_failures_total counter\n")
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "b2b_retention_failures_total{table=%s} %d\n", promLabel(m.Table), m.Failures)
+	}
+
+	return b.String()
+}
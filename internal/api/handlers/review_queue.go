@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// AssignReviewOrderRequest represents a request to hand a review queue order to a staff member
+type AssignReviewOrderRequest struct {
+	AssigneeID string `json:"assignee_id" binding:"required"`
+}
+
+// buildReviewAssignmentResponse is null for orders nobody works
+func buildReviewAssignmentResponse(assignment *domain.ReviewAssignment) gin.H {
+	if assignment == nil {
+		return nil
+	}
+	return gin.H{
+		"assignee_id": assignment.AssigneeID.String(),
+		"assigned_by": assignment.AssignedBy.String(),
+		"assigned_at": formatTimestamp(assignment.AssignedAt),
+	}
+}
+
+// HandleListReviewQueue handles GET /v1/admin/review-queue
+// ?assignee=me, ?assignee=none or ?assignee=<partner id> narrows the queue
+func HandleListReviewQueue(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		var filter service.ReviewQueueFilter
+		switch assignee := c.Query("assignee"); assignee {
+		case "":
+		case "me":
+			filter.AssigneeID = &admin.ID
+		case "none":
+			filter.Unassigned = true
+		default:
+			assigneeID, err := uuid.Parse(assignee)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "assignee must be me, none or a partner ID"})
+				return
+			}
+			filter.AssigneeID = &assigneeID
+		}
+
+		queue, err := services.ReviewQueue.List(c.Request.Context(), filter, time.Now())
+		if err != nil {
+			logger.Error("Failed to list review queue", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		// Build response
+		orders := make([]gin.H, len(queue))
+		for i, item := range queue {
+			orders[i] = gin.H{
+				"id":               item.Order.ID.String(),
+				"partner_id":       item.Order.PartnerID.String(),
+				"partner_order_id": item.Order.PartnerOrderID,
+				"reference":        item.Order.Reference,
+				"status":           item.Order.Status,
+				"customer_name":    item.Order.CustomerName,
+				"cart_total":       item.Order.CartTotal,
+				"reasons":          item.Reasons,
+				"assignment":       buildReviewAssignmentResponse(item.Assignment),
+				"created_at":       formatTimestamp(item.Order.CreatedAt),
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"orders": orders,
+			"total":  len(orders),
+		})
+	}
+}
+
+// HandleClaimReviewOrder handles POST /v1/admin/review-queue/:id/claim
+func HandleClaimReviewOrder(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		assignment, err := services.ReviewQueue.Claim(c.Request.Context(), orderID, admin.ID)
+		if err != nil {
+			switch err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				logger.Error("Failed to claim order for review", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to claim order"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":         orderID.String(),
+			"assignment": buildReviewAssignmentResponse(assignment),
+		})
+	}
+}
+
+// HandleReleaseReviewOrder handles POST /v1/admin/review-queue/:id/release
+func HandleReleaseReviewOrder(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		if err := services.ReviewQueue.Release(c.Request.Context(), orderID, admin.ID); err != nil {
+			switch err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order is not assigned"})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				logger.Error("Failed to release review order", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to release order"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":         orderID.String(),
+			"assignment": nil,
+		})
+	}
+}
+
+// HandleAssignReviewOrder handles PUT /v1/admin/review-queue/:id/assignee
+func HandleAssignReviewOrder(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		admin, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		var req AssignReviewOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		assigneeID, err := uuid.Parse(req.AssigneeID)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(map[string]string{"assignee_id": "invalid partner ID"}),
+			})
+			return
+		}
+
+		assignment, err := services.ReviewQueue.Assign(c.Request.Context(), orderID, assigneeID, admin.ID)
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			case *errors.ErrValidation:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Message, "details": fieldErrors(e.Fields)})
+			case *errors.ErrConflict:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				logger.Error("Failed to assign review order", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to assign order"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":         orderID.String(),
+			"assignment": buildReviewAssignmentResponse(assignment),
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/jafarshop/b2bapi/internal/schemas"
+)
+
+// HandleListSchemas handles GET /v1/schemas
+func HandleListSchemas() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		names := schemas.Names()
+		list := make([]gin.H, len(names))
+		for i, name := range names {
+			list[i] = gin.H{
+				"name": name,
+				"url":  c.FullPath() + "/" + name,
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"schemas": list})
+	}
+}
+
+// HandleGetSchema handles GET /v1/schemas/:name
+func HandleGetSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schema, ok := schemas.Get(c.Param("name"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "schema not found"})
+			return
+		}
+		c.Data(http.StatusOK, "application/schema+json", schema)
+	}
+}
+
+// bindJSONSchema validates the request body against the named schema, then binds it into
+// req. Schema errors are reported per field, e.g. {"field": "items[0].price", "code": "GT",
+// "message": "must be greater than 0"}.
+func bindJSONSchema(c *gin.Context, schema string, req interface{}) bool {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+		return false
+	}
+
+	errs, err := schemas.Check(schema, body)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "validation failed",
+			"details": []FieldError{{Field: "body", Code: ValidationCodeInvalidJSON, Message: "must be a JSON document: " + err.Error()}},
+		})
+		return false
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "validation failed",
+			"details": schemaErrors(errs),
+		})
+		return false
+	}
+
+	// The schema covers what binding checks; binding still guards against drift between the two
+	if err := binding.JSON.BindBody(body, req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "validation failed",
+			"details": bindingErrors(err),
+		})
+		return false
+	}
+	return true
+}
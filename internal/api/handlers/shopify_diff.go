@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleGetOrderShopifyDiff handles GET /v1/admin/orders/:id/shopify-diff
+func HandleGetOrderShopifyDiff(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.ShopifyOrderID == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "order has no Shopify order"})
+			return
+		}
+
+		diff, err := services.Diffs.DiffOrder(c.Request.Context(), order)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Shopify order not found"})
+				return
+			}
+			logger.Error("Failed to diff order with Shopify", zap.Error(err))
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch the Shopify order"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":   order.ID.String(),
+			"diff": diff,
+		})
+	}
+}
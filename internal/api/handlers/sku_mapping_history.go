@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// HandleListSKUMappingChanges handles GET /v1/admin/sku-mapping-changes
+// Lists changes to SKU mappings newest first, optionally for one SKU or for the mappings
+// that pointed to or away from a Shopify variant
+func HandleListSKUMappingChanges(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		filter := domain.SKUMappingChangeFilter{SKU: strings.TrimSpace(c.Query("sku"))}
+		if variantStr := c.Query("variant_id"); variantStr != "" {
+			variantID, err := strconv.ParseInt(variantStr, 10, 64)
+			if err != nil || variantID <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid variant_id"})
+				return
+			}
+			filter.VariantID = &variantID
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		var cursor *domain.OrderCursor
+		if cursorStr := c.Query("cursor"); cursorStr != "" {
+			cursor, err = domain.DecodeOrderCursor(cursorStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+		}
+
+		changes, err := repos.SKUMapping.ListChangesAfter(c.Request.Context(), filter, cursor, limit)
+		if err != nil {
+			logger.Error("Failed to list SKU mapping changes", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		changeResponses := make([]gin.H, len(changes))
+		for i, change := range changes {
+			changeResponses[i] = gin.H{
+				"id":             change.ID.String(),
+				"sku_mapping_id": change.SKUMappingID.String(),
+				"sku":            change.SKU,
+				"version":        change.Version,
+				"action":         change.Action,
+				"previous": gin.H{
+					"normalized_sku":     change.PreviousNormalizedSKU,
+					"shopify_product_id": change.PreviousShopifyProductID,
+					"shopify_variant_id": change.PreviousShopifyVariantID,
+					"is_active":          change.PreviousIsActive,
+				},
+				"current": gin.H{
+					"normalized_sku":     change.NormalizedSKU,
+					"shopify_product_id": change.ShopifyProductID,
+					"shopify_variant_id": change.ShopifyVariantID,
+					"is_active":          change.IsActive,
+				},
+				"source":     change.Source,
+				"changed_by": change.ChangedBy,
+				"changed_at": formatTimestamp(change.ChangedAt),
+			}
+		}
+
+		response := gin.H{
+			"changes": changeResponses,
+			"limit":   limit,
+		}
+		if len(changes) == limit {
+			last := changes[len(changes)-1]
+			response["next_cursor"] = domain.OrderCursor{CreatedAt: last.ChangedAt, ID: last.ID}.Encode()
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// HandleListMissedSKUMatches handles GET /v1/admin/reports/missed-sku-matches
+func HandleListMissedSKUMatches(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		from, to, ok := parseReportPeriod(c)
+		if !ok {
+			return
+		}
+
+		missed, err := services.SKUs.MissedMatches(c.Request.Context(), from, to)
+		if err != nil {
+			logger.Error("Failed to find missed SKU matches", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		matches := make([]gin.H, len(missed))
+		for i, match := range missed {
+			matches[i] = gin.H{
+				"sku":          match.SKU,
+				"matched_sku":  match.MatchedSKU,
+				"match_method": match.MatchMethod,
+				"orders":       match.Orders,
+				"last_seen_at": formatTimestamp(match.LastSeenAt),
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":    formatTimestamp(from),
+			"to":      formatTimestamp(to),
+			"matches": matches,
+		})
+	}
+}
+
+// HandleListUnmatchedSKUs handles GET /v1/admin/reports/unmatched-skus
+func HandleListUnmatchedSKUs(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		from, to, ok := parseReportPeriod(c)
+		if !ok {
+			return
+		}
+
+		filter := domain.UnmatchedSKUFilter{
+			Reason: c.Query("reason"),
+			From:   from,
+			To:     to,
+		}
+		if partnerIDStr := c.Query("partner_id"); partnerIDStr != "" {
+			partnerID, err := uuid.Parse(partnerIDStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid partner ID"})
+				return
+			}
+			filter.PartnerID = &partnerID
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit < 1 || limit > 1000 {
+			limit = 100
+		}
+		filter.Limit = limit
+
+		skus, err := repos.UnmatchedSKU.List(c.Request.Context(), filter)
+		if err != nil {
+			logger.Error("Failed to list unmatched SKUs", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		skuResponses := make([]gin.H, len(skus))
+		for i, sku := range skus {
+			skuResponses[i] = gin.H{
+				"partner_id":    sku.PartnerID.String(),
+				"sku":           sku.SKU,
+				"title":         sku.Title,
+				"reason":        sku.Reason,
+				"occurrences":   sku.Occurrences,
+				"first_seen_at": formatTimestamp(sku.FirstSeenAt),
+				"last_seen_at":  formatTimestamp(sku.LastSeenAt),
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":  formatTimestamp(from),
+			"to":    formatTimestamp(to),
+			"limit": limit,
+			"skus":  skuResponses,
+		})
+	}
+}
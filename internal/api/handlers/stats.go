@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// HandleGetStats handles GET /v1/admin/stats
+func HandleGetStats(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		from, to, ok := parseReportPeriod(c)
+		if !ok {
+			return
+		}
+
+		statsService := service.NewStatsService(repos, logger)
+		report, err := statsService.OrderFunnel(c.Request.Context(), from, to)
+		if err != nil {
+			logger.Error("Failed to build order funnel stats", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		if c.Query("format") == "prometheus" {
+			c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(formatFunnelMetrics(report)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":     formatTimestamp(report.From),
+			"to":       formatTimestamp(report.To),
+			"partners": partnerFunnelsResponse(report),
+		})
+	}
+}
+
+func partnerFunnelsResponse(report *service.FunnelReport) []gin.H {
+	partners := make([]gin.H, len(report.Partners))
+	for i, p := range report.Partners {
+		partners[i] = funnelResponse(p)
+		partners[i]["partner_id"] = p.PartnerID.String()
+		partners[i]["partner_name"] = p.PartnerName
+	}
+	return partners
+}
+
+func funnelResponse(p *service.PartnerFunnel) gin.H {
+	return gin.H{
+		"funnel": gin.H{
+			"submitted": p.Submitted,
+			"confirmed": p.Confirmed,
+			"shipped":   p.Shipped,
+			"delivered": p.Delivered,
+			"rejected":  p.Rejected,
+			"cancelled": p.Cancelled,
+		},
+		"conversion": gin.H{
+			"confirmation_rate": p.ConfirmationRate(),
+			"ship_rate":         p.ShipRate(),
+			"delivery_rate":     p.DeliveryRate(),
+			"overall":           p.Conversion(),
+			"rejection_rate":    p.RejectionRate(),
+		},
+		"revenue":           p.Revenue,
+		"rejection_reasons": p.RejectionReasons,
+		"rejection_codes":   p.RejectionCodes,
+	}
+}
+
+// formatFunnelMetrics renders the funnel report in the Prometheus text exposition format.
+// Values are gauges over the report period, labelled by partner.
+func formatFunnelMetrics(report *service.FunnelReport) string {
+	var b strings.Builder
+
+	b.WriteString("# HELP b2b_order_funnel_orders Orders created in the report period that reached each funnel stage.\n")
+	b.WriteString("# TYPE b2b_order_funnel_orders gauge\n")
+	for _, p := range report.Partners {
+		stages := []struct {
+			name  string
+			count int
+		}{
+			{"submitted", p.Submitted},
+			{"confirmed", p.Confirmed},
+			{"shipped", p.Shipped},
+			{"delivered", p.Delivered},
+			{"rejected", p.Rejected},
+			{"cancelled", p.Cancelled},
+		}
+		for _, stage := range stages {
+			fmt.Fprintf(&b, "b2b_order_funnel_orders{partner_id=%s,partner_name=%s,stage=%s} %d\n",
+				promLabel(p.PartnerID.String()), promLabel(p.PartnerName), promLabel(stage.name), stage.count)
+		}
+	}
+
+	b.WriteString("# HELP b2b_order_funnel_conversion_ratio Share of submitted orders that were delivered.\n")
+	b.WriteString("# TYPE b2b_order_funnel_conversion_ratio gauge\n")
+	for _, p := range report.Partners {
+		fmt.Fprintf(&b, "b2b_order_funnel_conversion_ratio{partner_id=%s,partner_name=%s} %g\n",
+			promLabel(p.PartnerID.String()), promLabel(p.PartnerName), p.Conversion())
+	}
+
+	b.WriteString("# HELP b2b_order_revenue Cart total of the orders created in the report period that were not rejected or cancelled.\n")
+	b.WriteString("# TYPE b2b_order_revenue gauge\n")
+	for _, p := range report.Partners {
+		fmt.Fprintf(&b, "b2b_order_revenue{partner_id=%s,partner_name=%s} %g\n",
+			promLabel(p.PartnerID.String()), promLabel(p.PartnerName), p.Revenue)
+	}
+
+	b.WriteString("# HELP b2b_order_rejections Rejected orders created in the report period, by reason.\n")
+	b.WriteString("# TYPE b2b_order_rejections gauge\n")
+	for _, p := range report.Partners {
+		reasons := make([]string, 0, len(p.RejectionReasons))
+		for reason := range p.RejectionReasons {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(&b, "b2b_order_rejections{partner_id=%s,partner_name=%s,reason=%s} %d\n",
+				promLabel(p.PartnerID.String()), promLabel(p.PartnerName), promLabel(reason), p.RejectionReasons[reason])
+		}
+	}
+
+	b.WriteString("# HELP b2b_order_rejection_codes Rejected orders created in the report period, by rejection code.\n")
+	b.WriteString("# TYPE b2b_order_rejection_codes gauge\n")
+	for _, p := range report.Partners {
+		for _, code := range domain.RejectionCodes {
+			if count, ok := p.RejectionCodes[code]; ok {
+				fmt.Fprintf(&b, "b2b_order_rejection_codes{partner_id=%s,partner_name=%s,code=%s} %d\n",
+					promLabel(p.PartnerID.String()), promLabel(p.PartnerName), promLabel(string(code)), count)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// labelEscaper escapes label values as the Prometheus text format expects
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promLabel renders a quoted label value
+func promLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
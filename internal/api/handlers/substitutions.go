@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleProposeSubstitution handles POST /v1/admin/orders/:id/substitutions
+func HandleProposeSubstitution(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, ok := loadSubstitutionOrder(c, repos, false, logger)
+		if !ok {
+			return
+		}
+
+		var req service.ProposeSubstitutionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+
+		substitution, err := services.Substitutions.Propose(c.Request.Context(), order, req)
+		if err != nil {
+			writeSubstitutionError(c, err, logger)
+			return
+		}
+
+		c.JSON(http.StatusCreated, substitutionResponse(substitution))
+	}
+}
+
+// HandleListSubstitutions handles GET /v1/orders/:id/substitutions
+func HandleListSubstitutions(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, ok := loadSubstitutionOrder(c, repos, true, logger)
+		if !ok {
+			return
+		}
+		listSubstitutions(c, repos, order, logger)
+	}
+}
+
+// HandleAdminListSubstitutions handles GET /v1/admin/orders/:id/substitutions
+func HandleAdminListSubstitutions(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, ok := loadSubstitutionOrder(c, repos, false, logger)
+		if !ok {
+			return
+		}
+		listSubstitutions(c, repos, order, logger)
+	}
+}
+
+func listSubstitutions(c *gin.Context, repos *repository.Repositories, order *domain.SupplierOrder, logger *zap.Logger) {
+	substitutions, err := repos.ItemSubstitution.ListByOrderID(c.Request.Context(), order.ID)
+	if err != nil {
+		logger.Error("Failed to list item substitutions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	response := make([]gin.H, len(substitutions))
+	for i, substitution := range substitutions {
+		response[i] = substitutionResponse(substitution)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"order_id":      order.ID.String(),
+		"substitutions": response,
+	})
+}
+
+// HandleAcceptSubstitution handles POST /v1/orders/:id/substitutions/:substitution_id/accept
+func HandleAcceptSubstitution(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, ok := loadSubstitutionOrder(c, repos, true, logger)
+		if !ok {
+			return
+		}
+		substitutionID, err := uuid.Parse(c.Param("substitution_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid substitution ID"})
+			return
+		}
+
+		substitution, err := services.Substitutions.Accept(c.Request.Context(), order, substitutionID)
+		if err != nil {
+			writeSubstitutionError(c, err, logger)
+			return
+		}
+
+		// The item and cart total changed; return the order as it is now
+		ctx := c.Request.Context()
+		order, err = repos.SupplierOrder.GetByID(ctx, order.ID)
+		if err != nil {
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		items, err := repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+		if err != nil {
+			logger.Error("Failed to get order items", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		substitutions, err := repos.ItemSubstitution.ListByOrderID(ctx, order.ID)
+		if err != nil {
+			logger.Error("Failed to list item substitutions", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		response := substitutionResponse(substitution)
+		response["order"] = buildOrderResponse(order, items, substitutions)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleDeclineSubstitution handles POST /v1/orders/:id/substitutions/:substitution_id/decline
+func HandleDeclineSubstitution(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		order, ok := loadSubstitutionOrder(c, repos, true, logger)
+		if !ok {
+			return
+		}
+		substitutionID, err := uuid.Parse(c.Param("substitution_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid substitution ID"})
+			return
+		}
+
+		// The body is optional
+		var req service.DeclineSubstitutionRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{
+					"error":   "validation failed",
+					"details": bindingErrors(err),
+				})
+				return
+			}
+		}
+
+		substitution, err := services.Substitutions.Decline(c.Request.Context(), order, substitutionID, req.Reason)
+		if err != nil {
+			writeSubstitutionError(c, err, logger)
+			return
+		}
+
+		c.JSON(http.StatusOK, substitutionResponse(substitution))
+	}
+}
+
+// loadSubstitutionOrder authorizes the caller and loads the order from the :id path param,
+// checking the caller owns it when owned is set. It writes the error response itself and
+// returns false on failure.
+func loadSubstitutionOrder(c *gin.Context, repos *repository.Repositories, owned bool, logger *zap.Logger) (*domain.SupplierOrder, bool) {
+	// Get partner from context
+	partner, ok := middleware.GetPartnerFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return nil, false
+	}
+
+	order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+			return nil, false
+		}
+		logger.Error("Failed to get order", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return nil, false
+	}
+
+	// Verify partner owns this order
+	if owned && order.PartnerID != partner.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+
+	return order, true
+}
+
+func writeSubstitutionError(c *gin.Context, err error, logger *zap.Logger) {
+	switch e := err.(type) {
+	case *errors.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "substitution not found"})
+	case *errors.ErrValidation:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   e.Error(),
+			"details": fieldErrors(e.Fields),
+		})
+	case *errors.ErrConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": e.Error()})
+	default:
+		logger.Error("Failed to process item substitution", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+func substitutionResponse(substitution *domain.ItemSubstitution) gin.H {
+	return gin.H{
+		"id":             substitution.ID.String(),
+		"item_id":        substitution.SupplierOrderItemID.String(),
+		"original_sku":   substitution.OriginalSKU,
+		"original_price": substitution.OriginalPrice,
+		"sku":            substitution.SKU,
+		"title":          substitution.Title,
+		"price":          substitution.Price,
+		"note":           substitution.Note,
+		"status":         substitution.Status,
+		"decline_reason": substitution.DeclineReason,
+		"created_at":     formatTimestamp(substitution.CreatedAt),
+		"responded_at":   formatTimestampPtr(substitution.RespondedAt),
+	}
+}
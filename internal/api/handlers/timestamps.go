@@ -0,0 +1,17 @@
+package handlers
+
+import "time"
+
+// formatTimestamp encodes a timestamp for API responses: RFC3339 in UTC
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatTimestampPtr is formatTimestamp for optional timestamps
+func formatTimestampPtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := formatTimestamp(*t)
+	return &formatted
+}
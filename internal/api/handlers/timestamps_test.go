@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimestampIsUTC(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want string
+	}{
+		{"utc", time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), "2024-03-01T10:00:00Z"},
+		{"east of utc", time.Date(2024, 3, 1, 1, 30, 0, 0, time.FixedZone("+03", 3*60*60)), "2024-02-29T22:30:00Z"},
+		{"west of utc", time.Date(2024, 12, 31, 20, 0, 0, 0, time.FixedZone("-05", -5*60*60)), "2025-01-01T01:00:00Z"},
+		{"sub-second", time.Date(2024, 3, 1, 10, 0, 0, 999_999_999, time.FixedZone("+01", 60*60)), "2024-03-01T09:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTimestamp(tt.t); got != tt.want {
+				t.Errorf("formatTimestamp() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimestampPtr(t *testing.T) {
+	if got := formatTimestampPtr(nil); got != nil {
+		t.Errorf("formatTimestampPtr(nil) = %q, want nil", *got)
+	}
+
+	shipped := time.Date(2024, 3, 1, 1, 30, 0, 0, time.FixedZone("+03", 3*60*60))
+	got := formatTimestampPtr(&shipped)
+	if got == nil || *got != "2024-02-29T22:30:00Z" {
+		t.Errorf("formatTimestampPtr() = %v, want 2024-02-29T22:30:00Z", got)
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// HandleCheckOrderTotal handles POST /v1/admin/orders/:id/check-total
+func HandleCheckOrderTotal(services *service.Services, repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse order ID
+		orderID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+			return
+		}
+
+		order, err := repos.SupplierOrder.GetByID(c.Request.Context(), orderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+				return
+			}
+			logger.Error("Failed to get order", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+		if order.ShopifyOrderID == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "order has no Shopify order"})
+			return
+		}
+
+		comparison, err := services.TotalChecks.CompareOrder(c.Request.Context(), order)
+		if err != nil {
+			logger.Error("Failed to compare order total with Shopify", zap.Error(err))
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to compare order total with Shopify"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"id":             order.ID.String(),
+			"cart_total":     comparison.CartTotal,
+			"expected_total": comparison.ExpectedTotal,
+			"shopify_total":  comparison.ShopifyTotal,
+			"difference":     comparison.Difference,
+			"currency":       comparison.Currency,
+			"mismatch":       comparison.Mismatch,
+		})
+	}
+}
+
+// HandleListTotalMismatches handles GET /v1/admin/reports/total-mismatches
+func HandleListTotalMismatches(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		_, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		from, to, ok := parseReportPeriod(c)
+		if !ok {
+			return
+		}
+
+		events, err := repos.OrderEvent.ListLatestByType(c.Request.Context(), service.EventTypeShopifyTotalMismatch, from, to)
+		if err != nil {
+			logger.Error("Failed to list total mismatches", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		mismatches := make([]gin.H, len(events))
+		for i, event := range events {
+			mismatches[i] = gin.H{
+				"order_id":         event.SupplierOrderID.String(),
+				"partner_id":       event.EventData["partner_id"],
+				"partner_order_id": event.EventData["partner_order_id"],
+				"shopify_order_id": event.EventData["shopify_order_id"],
+				"cart_total":       event.EventData["cart_total"],
+				"expected_total":   event.EventData["expected_total"],
+				"shopify_total":    event.EventData["shopify_total"],
+				"difference":       event.EventData["difference"],
+				"currency":         event.EventData["currency"],
+				"detected_at":      formatTimestamp(event.CreatedAt),
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"from":       formatTimestamp(from),
+			"to":         formatTimestamp(to),
+			"mismatches": mismatches,
+		})
+	}
+}
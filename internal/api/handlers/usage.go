@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// defaultUsagePeriod is the report period when no from/to is given
+const defaultUsagePeriod = 30 * 24 * time.Hour
+
+// HandleGetPartnerUsage handles GET /v1/admin/partners/:id/usage
+func HandleGetPartnerUsage(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		partner, ok := loadPartnerParam(c, repos, logger)
+		if !ok {
+			return
+		}
+
+		from, to, ok := parseReportPeriod(c)
+		if !ok {
+			return
+		}
+
+		usageService := service.NewUsageService(repos, logger)
+		report, err := usageService.PartnerUsage(c.Request.Context(), partner.ID, from, to)
+		if err != nil {
+			logger.Error("Failed to build partner usage report", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		ordersByStatus := make(gin.H, len(report.Orders))
+		for status, count := range report.Orders {
+			ordersByStatus[string(status)] = count
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"partner_id": partner.ID.String(),
+			"from":       formatTimestamp(report.From),
+			"to":         formatTimestamp(report.To),
+			"requests": gin.H{
+				"total":          report.API.TotalRequests,
+				"client_errors":  report.API.ClientErrors,
+				"server_errors":  report.API.ServerErrors,
+				"error_rate":     report.ErrorRate(),
+				"p95_latency_ms": report.API.P95LatencyMs,
+			},
+			"orders": gin.H{
+				"total":     report.TotalOrders(),
+				"by_status": ordersByStatus,
+			},
+			"webhooks": gin.H{
+				"total":        report.Webhooks.Total,
+				"succeeded":    report.Webhooks.Succeeded,
+				"success_rate": report.WebhookSuccessRate(),
+			},
+		})
+	}
+}
+
+// parseReportPeriod reads the from/to query parameters (RFC3339, defaults to the last
+// 30 days). It writes a 400 response and returns false when they are invalid.
+func parseReportPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC3339 timestamp"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultUsagePeriod)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC3339 timestamp"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
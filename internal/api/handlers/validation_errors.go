@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"github.com/jafarshop/b2bapi/pkg/jsonschema"
+)
+
+// Validation error codes. Binding and schema checks of the same kind share a code, so a
+// min=1 tag and a "minimum": 1 schema both report MIN.
+const (
+	ValidationCodeRequired    = "REQUIRED"
+	ValidationCodeMin         = "MIN"
+	ValidationCodeMax         = "MAX"
+	ValidationCodeGreaterThan = "GT"
+	ValidationCodeLessThan    = "LT"
+	ValidationCodeOneOf       = "ONEOF"
+	ValidationCodeFormat      = "FORMAT"
+	ValidationCodePattern     = "PATTERN"
+	ValidationCodeType        = "TYPE"
+	ValidationCodeNotAllowed  = "NOT_ALLOWED"
+	ValidationCodeInvalidJSON = "INVALID_JSON"
+	// ValidationCodeInvalid is used for checks that have no finer code, such as an unknown
+	// carrier or a partner ID that does not parse
+	ValidationCodeInvalid = "INVALID"
+)
+
+// FieldError is one reason a request failed validation. Field is the path of the value in
+// the request body, e.g. items[2].quantity, or "body" for the body as a whole.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Report fields by their JSON name rather than the Go one
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// bindingErrors translates an error of binding a JSON body into field errors
+func bindingErrors(err error) []FieldError {
+	switch e := err.(type) {
+	case validator.ValidationErrors:
+		fields := make([]FieldError, 0, len(e))
+		for _, fieldErr := range e {
+			fields = append(fields, validatorFieldError(fieldErr))
+		}
+		return fields
+	case *json.UnmarshalTypeError:
+		field := "body"
+		if e.Field != "" {
+			field = jsonFieldPath(e.Field)
+		}
+		return []FieldError{{Field: field, Code: ValidationCodeType, Message: "must be " + jsonTypeName(e.Type)}}
+	case *json.SyntaxError:
+		return []FieldError{{Field: "body", Code: ValidationCodeInvalidJSON, Message: "must be a JSON document"}}
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return []FieldError{{Field: "body", Code: ValidationCodeInvalidJSON, Message: "must be a JSON document"}}
+	}
+	return []FieldError{{Field: "body", Code: ValidationCodeInvalid, Message: err.Error()}}
+}
+
+// jsonFieldPath rewrites the dotted path of a JSON decoding error, e.g. items.2.quantity, in
+// the items[2].quantity form the other validation errors use
+func jsonFieldPath(path string) string {
+	var b strings.Builder
+	for i, segment := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(segment); err == nil {
+			b.WriteString("[" + segment + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+// validatorFieldError translates one failed binding tag
+func validatorFieldError(e validator.FieldError) FieldError {
+	field := e.Namespace()
+	// The namespace starts with the Go name of the request struct
+	if i := strings.IndexByte(field, '.'); i >= 0 {
+		field = field[i+1:]
+	}
+
+	kind := e.Kind()
+	if kind == reflect.Ptr {
+		kind = e.Type().Elem().Kind()
+	}
+	param := e.Param()
+
+	switch e.Tag() {
+	case "required":
+		return FieldError{Field: field, Code: ValidationCodeRequired, Message: "is required"}
+	case "min", "gte":
+		return FieldError{Field: field, Code: ValidationCodeMin, Message: boundMessage("at least", kind, param)}
+	case "max", "lte":
+		return FieldError{Field: field, Code: ValidationCodeMax, Message: boundMessage("at most", kind, param)}
+	case "gt":
+		return FieldError{Field: field, Code: ValidationCodeGreaterThan, Message: "must be greater than " + param}
+	case "lt":
+		return FieldError{Field: field, Code: ValidationCodeLessThan, Message: "must be less than " + param}
+	case "oneof":
+		return FieldError{Field: field, Code: ValidationCodeOneOf, Message: "must be one of " + strings.Join(strings.Fields(param), ", ")}
+	case "email", "uuid", "url":
+		return FieldError{Field: field, Code: ValidationCodeFormat, Message: "must be a valid " + e.Tag()}
+	}
+	return FieldError{Field: field, Code: strings.ToUpper(e.Tag()), Message: fmt.Sprintf("failed the %s check", e.Tag())}
+}
+
+// boundMessage words a min or max bound the way the JSON Schemas do
+func boundMessage(bound string, kind reflect.Kind, param string) string {
+	switch kind {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters", bound, param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("must have %s %s item(s)", bound, param)
+	}
+	return fmt.Sprintf("must be %s %s", bound, param)
+}
+
+// jsonTypeName names the JSON type a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "a different type"
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "a different type"
+}
+
+// schemaKeywordCodes maps JSON Schema keywords to validation codes
+var schemaKeywordCodes = map[string]string{
+	"required":         ValidationCodeRequired,
+	"minimum":          ValidationCodeMin,
+	"minLength":        ValidationCodeMin,
+	"minItems":         ValidationCodeMin,
+	"maximum":          ValidationCodeMax,
+	"maxLength":        ValidationCodeMax,
+	"maxItems":         ValidationCodeMax,
+	"exclusiveMinimum": ValidationCodeGreaterThan,
+	"exclusiveMaximum": ValidationCodeLessThan,
+	"enum":             ValidationCodeOneOf,
+	"format":           ValidationCodeFormat,
+	"pattern":          ValidationCodePattern,
+	"type":             ValidationCodeType,
+	"false":            ValidationCodeNotAllowed,
+}
+
+// schemaErrors translates JSON Schema validation errors into field errors
+func schemaErrors(errs []jsonschema.ValidationError) []FieldError {
+	fields := make([]FieldError, len(errs))
+	for i, e := range errs {
+		field := e.Path
+		if field == "" {
+			field = "body"
+		}
+		code, ok := schemaKeywordCodes[e.Keyword]
+		if !ok {
+			code = ValidationCodeInvalid
+		}
+		fields[i] = FieldError{Field: field, Code: code, Message: e.Message}
+	}
+	return fields
+}
+
+// fieldErrors lists checks reported as field → message, such as errors.ErrValidation
+// fields, ordered by field
+func fieldErrors(fields map[string]string) []FieldError {
+	list := make([]FieldError, 0, len(fields))
+	for field, message := range fields {
+		list = append(list, FieldError{Field: field, Code: ValidationCodeInvalid, Message: message})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Field < list[j].Field })
+	return list
+}
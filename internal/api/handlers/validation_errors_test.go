@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestBindingErrors(t *testing.T) {
+	type item struct {
+		SKU      string `json:"sku" binding:"required"`
+		Quantity int    `json:"quantity" binding:"min=1"`
+	}
+	type request struct {
+		Email  string `json:"email" binding:"omitempty,email,max=255"`
+		Reason string `json:"reason" binding:"required,max=5"`
+		Kind   string `json:"kind" binding:"omitempty,oneof=a b"`
+		Items  []item `json:"items" binding:"required,min=1,dive"`
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []FieldError
+	}{
+		{
+			name: "nested item",
+			body: `{"reason": "ok", "items": [{"sku": "A", "quantity": 1}, {"sku": "B", "quantity": 1}, {"quantity": 0}]}`,
+			want: []FieldError{
+				{Field: "items[2].sku", Code: ValidationCodeRequired, Message: "is required"},
+				{Field: "items[2].quantity", Code: ValidationCodeMin, Message: "must be at least 1"},
+			},
+		},
+		{
+			name: "string and list bounds",
+			body: `{"reason": "too long", "items": [], "email": "nope", "kind": "c"}`,
+			want: []FieldError{
+				{Field: "email", Code: ValidationCodeFormat, Message: "must be a valid email"},
+				{Field: "reason", Code: ValidationCodeMax, Message: "must be at most 5 characters"},
+				{Field: "kind", Code: ValidationCodeOneOf, Message: "must be one of a, b"},
+				{Field: "items", Code: ValidationCodeMin, Message: "must have at least 1 item(s)"},
+			},
+		},
+		{
+			name: "wrong type",
+			body: `{"reason": "ok", "items": [{"sku": "A", "quantity": "two"}]}`,
+			want: []FieldError{{Field: "items[0].quantity", Code: ValidationCodeType, Message: "must be integer"}},
+		},
+		{
+			name: "not JSON",
+			body: `{"reason": `,
+			want: []FieldError{{Field: "body", Code: ValidationCodeInvalidJSON, Message: "must be a JSON document"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req request
+			err := binding.JSON.BindBody([]byte(tt.body), &req)
+			if err == nil {
+				t.Fatal("BindBody() error = nil")
+			}
+			if got := bindingErrors(err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bindingErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleCartSubmitSchemaErrors(t *testing.T) {
+	ct := newCartTest(t)
+
+	body := strings.Replace(testCart, `"quantity": 2`, `"quantity": 0`, 1)
+	w := ct.submit(body)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+
+	details, _ := decodeBody(t, w)["details"].([]interface{})
+	want := map[string]interface{}{"field": "items[0].quantity", "code": "MIN", "message": "must be at least 1"}
+	if len(details) != 1 || !reflect.DeepEqual(details[0], want) {
+		t.Errorf("details = %v, want [%v]", details, want)
+	}
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// payloadSnippetLength is how many characters of a delivery's payload listings show
+const payloadSnippetLength = 500
+
+// Webhook delivery statuses in listings
+const (
+	webhookDeliveryDelivered = "delivered"
+	webhookDeliveryFailed    = "failed"
+)
+
+// HandleListWebhookDeliveries handles GET /v1/webhooks/deliveries
+func HandleListWebhookDeliveries(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		filter := domain.WebhookDeliveryFilter{EventType: c.Query("event_type")}
+		switch status := c.Query("status"); status {
+		case "":
+		case webhookDeliveryDelivered, webhookDeliveryFailed:
+			delivered := status == webhookDeliveryDelivered
+			filter.Delivered = &delivered
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+		if err != nil || limit < 1 || limit > 100 {
+			limit = 50
+		}
+		var cursor *domain.OrderCursor
+		if cursorStr := c.Query("cursor"); cursorStr != "" {
+			cursor, err = domain.DecodeOrderCursor(cursorStr)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+		}
+
+		deliveries, err := repos.WebhookDelivery.ListHistoryByPartnerIDAfter(c.Request.Context(), partner.ID, filter, cursor, limit)
+		if err != nil {
+			logger.Error("Failed to list webhook deliveries", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		deliveryResponses := make([]gin.H, len(deliveries))
+		for i, delivery := range deliveries {
+			status := webhookDeliveryFailed
+			if delivery.Delivered {
+				status = webhookDeliveryDelivered
+			}
+			deliveryResponses[i] = gin.H{
+				"id":                delivery.ID.String(),
+				"event_type":        delivery.EventType,
+				"supplier_order_id": delivery.SupplierOrderID,
+				"status":            status,
+				"attempts":          delivery.Attempts,
+				"url":               delivery.URL,
+				"response_status":   delivery.LastAttempt.ResponseStatus,
+				"error":             delivery.LastAttempt.Error,
+				"duration_ms":       delivery.LastAttempt.DurationMs,
+				"payload_snippet":   payloadSnippet(delivery.Payload),
+				"created_at":        formatTimestamp(delivery.CreatedAt),
+				"last_attempt_at":   formatTimestamp(delivery.LastAttempt.CreatedAt),
+			}
+		}
+
+		response := gin.H{
+			"deliveries": deliveryResponses,
+			"limit":      limit,
+		}
+		if len(deliveries) == limit {
+			last := deliveries[len(deliveries)-1]
+			response["next_cursor"] = domain.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleRetryWebhookDelivery handles POST /v1/webhooks/deliveries/:id/retry
+func HandleRetryWebhookDelivery(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		deliveryID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery ID"})
+			return
+		}
+
+		retry, err := services.Webhooks.RetryDelivery(c.Request.Context(), partner, deliveryID)
+		if err != nil {
+			switch e := err.(type) {
+			case *errors.ErrNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": "delivery not found"})
+			case *errors.ErrValidation:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+			default:
+				logger.Error("Failed to retry webhook delivery", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retry delivery"})
+			}
+			return
+		}
+
+		status := webhookDeliveryFailed
+		if retry.Success {
+			status = webhookDeliveryDelivered
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"id":              retry.ID.String(),
+			"retry_of":        retry.RetryOf.String(),
+			"event_type":      retry.EventType,
+			"status":          status,
+			"url":             retry.URL,
+			"response_status": retry.ResponseStatus,
+			"error":           retry.Error,
+			"duration_ms":     retry.DurationMs,
+			"created_at":      formatTimestamp(retry.CreatedAt),
+		})
+	}
+}
+
+// payloadSnippet is the start of a payload as sent, cut to payloadSnippetLength characters
+func payloadSnippet(payload map[string]interface{}) string {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	snippet := string(body)
+	if utf8.RuneCountInString(snippet) <= payloadSnippetLength {
+		return snippet
+	}
+	return string([]rune(snippet)[:payloadSnippetLength]) + "…"
+}
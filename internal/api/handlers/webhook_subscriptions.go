@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// UpdateWebhookSubscriptionsRequest represents a webhook subscription update; an empty
+// list subscribes to every event
+type UpdateWebhookSubscriptionsRequest struct {
+	EventTypes []string `json:"event_types"`
+}
+
+// HandleListWebhookEventTypes handles GET /v1/webhooks/event-types
+func HandleListWebhookEventTypes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		if _, ok := middleware.GetPartnerFromContext(c); !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"event_types": service.WebhookEventTypes})
+	}
+}
+
+// HandleGetWebhookSubscriptions handles GET /v1/partner/webhook/subscriptions
+func HandleGetWebhookSubscriptions(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		eventTypes, err := repos.WebhookSubscription.ListByPartnerID(c.Request.Context(), partner.ID)
+		if err != nil {
+			logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, buildWebhookSubscriptionsResponse(eventTypes))
+	}
+}
+
+// HandleUpdateWebhookSubscriptions handles PUT /v1/partner/webhook/subscriptions
+func HandleUpdateWebhookSubscriptions(services *service.Services, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get partner from context
+		partner, ok := middleware.GetPartnerFromContext(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		// Parse request
+		var req UpdateWebhookSubscriptionsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": bindingErrors(err),
+			})
+			return
+		}
+		if req.EventTypes == nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":   "validation failed",
+				"details": fieldErrors(map[string]string{"event_types": "event_types is required; use [] to receive every event"}),
+			})
+			return
+		}
+
+		eventTypes, err := services.Webhooks.UpdateSubscriptions(c.Request.Context(), partner, req.EventTypes)
+		if err != nil {
+			if e, ok := err.(*errors.ErrValidation); ok {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": e.Error(), "details": fieldErrors(e.Fields)})
+				return
+			}
+			logger.Error("Failed to update webhook subscriptions", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update webhook subscriptions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, buildWebhookSubscriptionsResponse(eventTypes))
+	}
+}
+
+func buildWebhookSubscriptionsResponse(eventTypes []string) gin.H {
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return gin.H{
+		// Partners without subscriptions receive every event
+		"all_events":  len(eventTypes) == 0,
+		"event_types": eventTypes,
+	}
+}
@@ -1,21 +1,27 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/jafarshop/b2bapi/internal/authguard"
 	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/apikey"
+	"github.com/jafarshop/b2bapi/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const PartnerContextKey = "partner"
 
-// AuthMiddleware authenticates requests using API key
-func AuthMiddleware(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+// AuthMiddleware authenticates requests using API key. Sources with too many failures are
+// rejected with 429 before the key is checked (see authguard).
+func AuthMiddleware(repos *repository.Repositories, guard *authguard.Guard, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -39,30 +45,59 @@ func AuthMiddleware(repos *repository.Repositories, logger *zap.Logger) gin.Hand
 			return
 		}
 
-		// Note: The current implementation requires that we can look up partners by API key hash.
-		// Since bcrypt produces different hashes each time (due to salt), we can't directly lookup.
-		// For MVP, we'll need to either:
-		// 1. Store a SHA256 hash for lookup + bcrypt for verification (recommended for production)
-		// 2. Iterate through all active partners and verify (works but inefficient)
-		//
-		// For now, the repository's GetByAPIKeyHash should handle this by iterating and verifying.
-		// This is a limitation of the current schema - in production, add a lookup_hash column.
-		
-		// Look up partner - the repository should handle verification
+		ip := c.ClientIP()
+		if wait := guard.Check(ip, apiKey); wait > 0 {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":               "too many failed authentication attempts",
+				"retry_after_seconds": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		// The repository finds the partner by the key's SHA256 lookup hash and verifies it
+		// against the bcrypt hash (cached in memory when API_AUTH_CACHE_TTL is set)
 		partner, err := repos.Partner.GetByAPIKeyHash(c.Request.Context(), apiKey)
 		if err != nil {
-			logger.Warn("Failed to authenticate partner", zap.Error(err))
+			if _, ok := err.(*errors.ErrUnauthorized); ok {
+				guard.Failure(ip, apiKey)
+			}
+			fields := []zap.Field{zap.String("ip", ip), zap.Error(err)}
+			// Only the prefix: it names the partner the key claims to be from without revealing it
+			if prefix, ok := apikey.Parse(apiKey); ok {
+				fields = append(fields, zap.String("key_prefix", prefix.String()))
+			}
+			logger.Warn("Failed to authenticate partner", fields...)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
 			c.Abort()
 			return
 		}
 
+		// Checked on every request, so a revoked key is rejected even while the partner is cached
+		revoked, err := repos.RevokedAPIKey.IsRevoked(c.Request.Context(), partner.APIKeyHash)
+		if err != nil {
+			logger.Error("Failed to check API key revocation", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			guard.Failure(ip, apiKey)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key has been revoked"})
+			c.Abort()
+			return
+		}
+
 		if !partner.IsActive {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "partner account is inactive"})
 			c.Abort()
 			return
 		}
 
+		guard.Success(apiKey)
+
 		// Store partner in context
 		c.Set(PartnerContextKey, partner)
 		c.Next()
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/errortracking"
+)
+
+// ErrorReportingMiddleware reports server errors (5xx responses) to Sentry, tagged with the
+// partner, the order (for order routes), the operation (method and route) and the request
+// ID. Panics are reported by RecoveryMiddleware instead. Does nothing when the tracker is
+// disabled.
+func ErrorReportingMiddleware(tracker *errortracking.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracker.Enabled() {
+			c.Next()
+			return
+		}
+
+		writer := &errorEnvelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		body := writer.body.Bytes()
+		if status := writer.Status(); status >= http.StatusInternalServerError {
+			operation := c.Request.Method + " " + c.FullPath()
+			tags := map[string]string{
+				"operation":  operation,
+				"status":     fmt.Sprint(status),
+				"request_id": GetRequestID(c),
+			}
+			if partner, ok := GetPartnerFromContext(c); ok {
+				tags["partner_id"] = partner.ID.String()
+			}
+			if strings.Contains(c.FullPath(), "/orders/:id") {
+				tags["order_id"] = c.Param("id")
+			}
+			var err error
+			if last := c.Errors.Last(); last != nil {
+				err = last.Err
+			}
+			tracker.CaptureError(operation+": "+responseError(body), err, tags)
+		}
+		if len(body) > 0 {
+			c.Writer.Write(body)
+		}
+	}
+}
+
+// responseError is the "error" of an error body
+func responseError(body []byte) string {
+	var fields struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil || fields.Error == "" {
+		return "server error"
+	}
+	return fields.Error
+}
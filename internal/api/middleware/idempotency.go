@@ -55,6 +55,16 @@ func IdempotencyMiddleware(repos *repository.Repositories, logger *zap.Logger) g
 		}
 
 		if existingKey != nil {
+			// Keys move with their order when an admin reassigns it to another partner; the
+			// old partner must neither see that order again nor create a second one
+			if partner, ok := GetPartnerFromContext(c); ok && existingKey.PartnerID != partner.ID {
+				c.JSON(http.StatusConflict, gin.H{
+					"error": "idempotency key conflict: key belongs to an order of another partner",
+				})
+				c.Abort()
+				return
+			}
+
 			// Key exists - check if request hash matches
 			if existingKey.RequestHash != requestHash {
 				// Same key, different payload - conflict
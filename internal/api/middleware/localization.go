@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// errorMessages translates the English error messages of the API, keyed by locale and then
+// by the English text. Messages without a translation are shown in English.
+var errorMessages = map[domain.Locale]map[string]string{
+	domain.LocaleArabic: {
+		"internal error":                             "حدث خطأ داخلي. يرجى المحاولة لاحقاً.",
+		"unauthorized":                               "غير مصرح.",
+		"validation failed":                          "بعض البيانات المرسلة غير صالحة.",
+		"access denied":                              "تم رفض الوصول.",
+		"missing authorization header":               "ترويسة التفويض مفقودة.",
+		"invalid authorization header format":        "صيغة ترويسة التفويض غير صحيحة.",
+		"missing API key":                            "مفتاح API مفقود.",
+		"invalid API key":                            "مفتاح API غير صالح.",
+		"API key has been revoked":                   "تم إلغاء مفتاح API.",
+		"partner account is inactive":                "حساب الشريك غير مفعّل.",
+		"too many failed authentication attempts":    "محاولات مصادقة فاشلة كثيرة. يرجى المحاولة لاحقاً.",
+		"temporarily unavailable for maintenance":    "الخدمة متوقفة مؤقتاً للصيانة. يرجى المحاولة لاحقاً.",
+		"order not found":                            "الطلب غير موجود.",
+		"invalid order ID":                           "رقم الطلب غير صالح.",
+		"invalid partner ID":                         "رقم الشريك غير صالح.",
+		"invalid substitution ID":                    "رقم الاستبدال غير صالح.",
+		"invalid delivery ID":                        "رقم الإرسال غير صالح.",
+		"delivery not found":                         "الإرسال غير موجود.",
+		"substitution not found":                     "الاستبدال غير موجود.",
+		"invalid cursor":                             "مؤشر الصفحة غير صالح.",
+		"invalid status":                             "الحالة غير صالحة.",
+		"partner not found":                          "الشريك غير موجود.",
+		"organization not found":                     "المؤسسة غير موجودة.",
+		"partner not found in organization":          "الشريك غير موجود في المؤسسة.",
+		"partner does not belong to an organization": "الشريك لا ينتمي إلى مؤسسة.",
+		"not subscribed to the digest":               "غير مشترك في الملخص اليومي.",
+		"schema not found":                           "المخطط غير موجود.",
+		"SKU not in partner catalog":                 "رمز المنتج غير موجود في كتالوج الشريك.",
+		"some SKUs could not be added":               "تعذرت إضافة بعض رموز المنتجات.",
+		"failed to read request body":                "تعذرت قراءة محتوى الطلب.",
+		"failed to process request":                  "تعذرت معالجة الطلب.",
+		"failed to create order":                     "تعذر إنشاء الطلب.",
+		"order stream is not enabled":                "بث الطلبات غير مفعّل.",
+		"from must be an RFC3339 timestamp":          "يجب أن تكون قيمة from تاريخاً بصيغة RFC3339.",
+		"to must be an RFC3339 timestamp":            "يجب أن تكون قيمة to تاريخاً بصيغة RFC3339.",
+		"from must be before to":                     "يجب أن تكون قيمة from قبل to.",
+		"since must be an RFC3339 timestamp":         "يجب أن تكون قيمة since تاريخاً بصيغة RFC3339.",
+		"invalid type":                               "النوع غير صالح.",
+		"window_hours must be between 1 and 720":     "يجب أن تكون قيمة window_hours بين 1 و720.",
+		"idempotency key conflict: same key used with different payload":       "تعارض في مفتاح عدم التكرار: استُخدم المفتاح نفسه مع محتوى مختلف.",
+		"idempotency key conflict: key belongs to an order of another partner": "تعارض في مفتاح عدم التكرار: المفتاح يخص طلباً لشريك آخر.",
+		"failed to update locale":                "تعذر تحديث اللغة.",
+		"failed to update webhook URL":           "تعذر تحديث رابط الويب هوك.",
+		"failed to update webhook subscriptions": "تعذر تحديث اشتراكات الويب هوك.",
+		"failed to rotate webhook secret":        "تعذر تغيير سر الويب هوك.",
+		"failed to update status codes":          "تعذر تحديث رموز الحالة.",
+		"failed to update digest subscription":   "تعذر تحديث الاشتراك في الملخص اليومي.",
+		"failed to delete digest subscription":   "تعذر حذف الاشتراك في الملخص اليومي.",
+		"failed to accept invitation":            "تعذر قبول الدعوة.",
+		"failed to revoke API key":               "تعذر إلغاء مفتاح API.",
+	},
+}
+
+// detailMessages translates the messages of validation error details, which carry values
+// such as bounds. Patterns are tried in order; the first match wins.
+var detailMessages = map[domain.Locale][]detailMessage{
+	domain.LocaleArabic: {
+		{regexp.MustCompile(`^is required$`), "هذا الحقل مطلوب"},
+		{regexp.MustCompile(`^must not be empty$`), "يجب ألا يكون فارغاً"},
+		{regexp.MustCompile(`^is not allowed$`), "هذا الحقل غير مسموح به"},
+		{regexp.MustCompile(`^must be a JSON document`), "يجب أن يكون المحتوى مستند JSON صالحاً"},
+		{regexp.MustCompile(`^must be at least (\S+) characters$`), "يجب ألا يقل طوله عن $1 حرفاً"},
+		{regexp.MustCompile(`^must be at most (\S+) characters$`), "يجب ألا يزيد طوله عن $1 حرفاً"},
+		{regexp.MustCompile(`^must have at least (\S+) item\(s\)$`), "يجب أن يحتوي على $1 عنصر على الأقل"},
+		{regexp.MustCompile(`^must have at most (\S+) item\(s\)$`), "يجب ألا يحتوي على أكثر من $1 عنصر"},
+		{regexp.MustCompile(`^must be at least (\S+)$`), "يجب ألا يقل عن $1"},
+		{regexp.MustCompile(`^must be at most (\S+)$`), "يجب ألا يزيد عن $1"},
+		{regexp.MustCompile(`^must be greater than (\S+)$`), "يجب أن يكون أكبر من $1"},
+		{regexp.MustCompile(`^must be less than (\S+)$`), "يجب أن يكون أقل من $1"},
+		{regexp.MustCompile(`^must be one of (.+)$`), "يجب أن يكون إحدى القيم: $1"},
+		{regexp.MustCompile(`^must be a valid (\S+)$`), "يجب أن يكون $1 صالحاً"},
+		{regexp.MustCompile(`^must match (.+)$`), "يجب أن يطابق النمط $1"},
+		{regexp.MustCompile(`^must be (string|integer|number|boolean|array|object)$`), "يجب أن يكون من النوع $1"},
+	},
+}
+
+// Unicode directional isolates (LRI ... PDI), as in customer-facing messages
+const (
+	leftToRightIsolate    = "\u2066"
+	popDirectionalIsolate = "\u2069"
+)
+
+type detailMessage struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// ErrorLocalizationMiddleware adds a "message" to error bodies, in the language of the
+// client: the first supported language of Accept-Language, otherwise the partner's locale
+// (PUT /v1/partner/locale), otherwise English. "error" keeps the English text so clients
+// that match on it are not affected; validation details get their messages translated
+// while field and code stay as they are. Must run inside V2ResponseMiddleware, which moves
+// the message into the v2 envelope.
+func ErrorLocalizationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &errorEnvelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.body.Len() == 0 {
+			return
+		}
+		body := writer.body.Bytes()
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "application/json") {
+			locale := requestLocale(c)
+			if localized, ok := localizeErrorBody(locale, body); ok {
+				body = localized
+				c.Writer.Header().Set("Content-Language", string(locale))
+				c.Writer.Header().Add("Vary", "Accept-Language")
+			}
+		}
+		c.Writer.Write(body)
+	}
+}
+
+// requestLocale is the locale error messages are shown in
+func requestLocale(c *gin.Context) domain.Locale {
+	if locale, ok := domain.ParseAcceptLanguage(c.GetHeader("Accept-Language")); ok {
+		return locale
+	}
+	if partner, ok := GetPartnerFromContext(c); ok && partner.Locale.IsValid() {
+		return partner.Locale
+	}
+	return domain.DefaultLocale
+}
+
+// localizeErrorBody adds the localized message to an error body. ok is false for bodies
+// that are not errors.
+func localizeErrorBody(locale domain.Locale, body []byte) ([]byte, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, false
+	}
+	message, ok := fields["error"].(string)
+	if !ok {
+		return nil, false
+	}
+
+	fields["message"] = localizeMessage(locale, message)
+	if details, ok := fields["details"].([]interface{}); ok {
+		for _, detail := range details {
+			if entry, ok := detail.(map[string]interface{}); ok {
+				if text, ok := entry["message"].(string); ok {
+					entry["message"] = localizeDetail(locale, text)
+				}
+			}
+		}
+	}
+
+	localized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return localized, true
+}
+
+// localizeMessage translates an error message, keeping the English text when there is no
+// translation
+func localizeMessage(locale domain.Locale, message string) string {
+	if translated, ok := errorMessages[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// localizeDetail translates a validation detail message. Values taken from the English
+// text are wrapped in directional isolates so they read correctly in right-to-left text.
+func localizeDetail(locale domain.Locale, message string) string {
+	for _, m := range detailMessages[locale] {
+		if match := m.pattern.FindStringSubmatchIndex(message); match != nil {
+			replacement := m.replacement
+			if locale.IsRTL() {
+				replacement = strings.ReplaceAll(replacement, "$1", leftToRightIsolate+"${1}"+popDirectionalIsolate)
+			}
+			return string(m.pattern.ExpandString(nil, replacement, message, match))
+		}
+	}
+	return message
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// arabicMinimum is "must be at least 1", with the bound isolated left-to-right
+const arabicMinimum = "يجب ألا يقل عن \u2066" + "1\u2069"
+
+func TestErrorLocalizationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validationError := func(c *gin.Context) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "validation failed",
+			"details": []gin.H{{"field": "items[2].quantity", "code": "MIN", "message": "must be at least 1"}},
+		})
+	}
+
+	tests := []struct {
+		name           string
+		version        string
+		acceptLanguage string
+		partnerLocale  domain.Locale
+		wantMessage    string
+		wantDetail     string
+	}{
+		{
+			name:        "English by default",
+			version:     "v1",
+			wantMessage: "validation failed",
+			wantDetail:  "must be at least 1",
+		},
+		{
+			name:           "Accept-Language",
+			version:        "v1",
+			acceptLanguage: "fr;q=1, ar-JO;q=0.9, en;q=0.5",
+			wantMessage:    "بعض البيانات المرسلة غير صالحة.",
+			wantDetail:     arabicMinimum,
+		},
+		{
+			name:          "partner preference",
+			version:       "v1",
+			partnerLocale: domain.LocaleArabic,
+			wantMessage:   "بعض البيانات المرسلة غير صالحة.",
+			wantDetail:    arabicMinimum,
+		},
+		{
+			name:           "Accept-Language wins over the partner preference",
+			version:        "v2",
+			acceptLanguage: "en",
+			partnerLocale:  domain.LocaleArabic,
+			wantMessage:    "validation failed",
+			wantDetail:     "must be at least 1",
+		},
+		{
+			name:           "v2 envelope",
+			version:        "v2",
+			acceptLanguage: "ar",
+			wantMessage:    "بعض البيانات المرسلة غير صالحة.",
+			wantDetail:     arabicMinimum,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if tt.version == "v2" {
+				router.Use(V2ResponseMiddleware())
+			}
+			router.Use(ErrorLocalizationMiddleware())
+			router.POST("/carts", func(c *gin.Context) {
+				if tt.partnerLocale != "" {
+					c.Set(PartnerContextKey, &domain.Partner{Locale: tt.partnerLocale})
+				}
+			}, validationError)
+
+			req := httptest.NewRequest(http.MethodPost, "/carts", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not JSON: %q", w.Body.String())
+			}
+
+			var message, code interface{}
+			var details []interface{}
+			if tt.version == "v2" {
+				envelope, _ := body["error"].(map[string]interface{})
+				message, code = envelope["message"], envelope["code"]
+				details, _ = envelope["details"].([]interface{})
+			} else {
+				if body["error"] != "validation failed" {
+					t.Errorf("error = %v, want the English message", body["error"])
+				}
+				message = body["message"]
+				details, _ = body["details"].([]interface{})
+			}
+
+			if message != tt.wantMessage {
+				t.Errorf("message = %v, want %q", message, tt.wantMessage)
+			}
+			if tt.version == "v2" && code != "validation_failed" {
+				t.Errorf("code = %v, want validation_failed", code)
+			}
+			if len(details) != 1 {
+				t.Fatalf("details = %v, want one entry", details)
+			}
+			detail := details[0].(map[string]interface{})
+			if detail["message"] != tt.wantDetail || detail["code"] != "MIN" || detail["field"] != "items[2].quantity" {
+				t.Errorf("details[0] = %v, want message %q with field and code unchanged", detail, tt.wantDetail)
+			}
+		})
+	}
+}
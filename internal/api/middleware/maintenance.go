@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// MaintenanceMiddleware rejects mutations (POST, PUT, PATCH, DELETE) with 503 and a
+// Retry-After header while maintenance mode is on; reads keep working. exempt lists the
+// routes (full paths, e.g. /v1/carts/validate) that only read despite their method and the
+// ones that switch maintenance mode off.
+func MaintenanceMiddleware(maintenance *service.Maintenance, exempt ...string) gin.HandlerFunc {
+	exempted := make(map[string]bool, len(exempt))
+	for _, route := range exempt {
+		exempted[route] = true
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if exempted[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		status := maintenance.Status()
+		if !status.Enabled {
+			c.Next()
+			return
+		}
+
+		retryAfter := int(status.RetryAfter.Seconds())
+		body := gin.H{
+			"error":       "temporarily unavailable for maintenance",
+			"retry_after": retryAfter,
+		}
+		if status.Message != "" {
+			body["notice"] = status.Message
+		}
+		if status.EndsAt != nil {
+			body["ends_at"] = status.EndsAt.UTC().Format(time.RFC3339)
+		}
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, body)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{}
+	cfg.SetTunables(config.Tunables{MaintenanceRetryAfter: 5 * time.Minute})
+	maintenance := service.NewMaintenance(cfg)
+
+	router := gin.New()
+	router.Use(MaintenanceMiddleware(maintenance, "/carts/validate"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/orders/:id", ok)
+	router.POST("/carts/submit", ok)
+	router.POST("/carts/validate", ok)
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	if w := request(http.MethodPost, "/carts/submit"); w.Code != http.StatusOK {
+		t.Fatalf("submit outside maintenance: status = %d, want 200", w.Code)
+	}
+
+	endsAt := time.Now().Add(90 * time.Second)
+	maintenance.Start("Database upgrade", &endsAt, uuid.New())
+
+	w := request(http.MethodPost, "/carts/submit")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("submit during maintenance: status = %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("Retry-After = %q, want the seconds until the announced end", got)
+	}
+	if w := request(http.MethodGet, "/orders/1"); w.Code != http.StatusOK {
+		t.Errorf("read during maintenance: status = %d, want 200", w.Code)
+	}
+	if w := request(http.MethodPost, "/carts/validate"); w.Code != http.StatusOK {
+		t.Errorf("exempt route during maintenance: status = %d, want 200", w.Code)
+	}
+
+	maintenance.End()
+	cfg.SetTunables(config.Tunables{Maintenance: true, MaintenanceRetryAfter: 5 * time.Minute})
+
+	w = request(http.MethodPost, "/carts/submit")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "300" {
+		t.Errorf("submit with MAINTENANCE_MODE: status = %d, Retry-After = %q, want 503 and 300", w.Code, w.Header().Get("Retry-After"))
+	}
+}
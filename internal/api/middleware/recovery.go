@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/errortracking"
+)
+
+// panicStats counts panics recovered since the process started
+var panicStats struct {
+	total atomic.Int64
+	last  atomic.Int64 // Unix time of the latest panic
+}
+
+// PanicStats are the recovered panic counters
+type PanicStats struct {
+	Total int64
+	// LastAt is the time of the latest panic, nil when there was none
+	LastAt *time.Time
+}
+
+// Panics returns the recovered panic counters
+func Panics() PanicStats {
+	stats := PanicStats{Total: panicStats.total.Load()}
+	if last := panicStats.last.Load(); last != 0 {
+		lastAt := time.Unix(last, 0)
+		stats.LastAt = &lastAt
+	}
+	return stats
+}
+
+// RecoveryMiddleware turns a panic in a handler into a 500 with the standard error body and
+// the request ID, so the partner can quote it. The panic is logged with its stack and the
+// request it happened in, counted (see Panics) and reported to Sentry when it is enabled.
+// Register it after RequestIDMiddleware and before everything else.
+func RecoveryMiddleware(tracker *errortracking.Tracker, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Response middleware swaps the writer and would not restore it on a panic
+		writer := c.Writer
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			// The server closes the connection for ErrAbortHandler; leave that to it
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			stack := debug.Stack()
+			panicStats.total.Add(1)
+			panicStats.last.Store(time.Now().Unix())
+
+			requestID := GetRequestID(c)
+			tags := map[string]string{
+				"method":     c.Request.Method,
+				"route":      c.FullPath(),
+				"request_id": requestID,
+			}
+			fields := []zap.Field{
+				zap.Any("panic", value),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("route", c.FullPath()),
+				zap.String("request_id", requestID),
+				zap.ByteString("stack", stack),
+				errortracking.Reported(),
+			}
+			if partner, ok := GetPartnerFromContext(c); ok {
+				tags["partner_id"] = partner.ID.String()
+				fields = append(fields, zap.String("partner_id", partner.ID.String()))
+			}
+			if orderID := c.Param("id"); orderID != "" {
+				tags["resource_id"] = orderID
+			}
+			logger.Error("Recovered from panic in handler", fields...)
+			tracker.CapturePanic(value, stack, tags)
+
+			c.Writer = writer
+			if c.Writer.Written() {
+				// Part of the response is out; all that is left is to stop
+				c.Abort()
+				return
+			}
+			c.Data(http.StatusInternalServerError, "application/json; charset=utf-8", panicResponse(c, requestID))
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// panicResponse is the error body of a recovered panic, in the shape and language the
+// response middleware would have produced
+func panicResponse(c *gin.Context, requestID string) []byte {
+	body, _ := json.Marshal(gin.H{"error": "internal error", "request_id": requestID})
+	if localized, ok := localizeErrorBody(requestLocale(c), body); ok {
+		body = localized
+	}
+	if GetAPIVersion(c) == "v2" {
+		if converted, ok := toV2ErrorEnvelope(http.StatusInternalServerError, body); ok {
+			body = converted
+		}
+	}
+	return body
+}
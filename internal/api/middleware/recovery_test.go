@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware())
+	router.Use(RecoveryMiddleware(nil, zap.NewNop()))
+	v1 := router.Group("/v1", APIVersionMiddleware("v1"))
+	v1.GET("/boom", func(c *gin.Context) { panic("boom") })
+	v2 := router.Group("/v2", APIVersionMiddleware("v2"), V2ResponseMiddleware(), ErrorLocalizationMiddleware())
+	v2.GET("/boom", func(c *gin.Context) { panic("boom") })
+
+	before := Panics().Total
+
+	t.Run("v1", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/boom", nil)
+		req.Header.Set(RequestIDHeader, "req-123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want 500", w.Code)
+		}
+		var body struct {
+			Error     string `json:"error"`
+			RequestID string `json:"request_id"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("body %s is not JSON: %v", w.Body.String(), err)
+		}
+		if body.Error != "internal error" || body.RequestID != "req-123" {
+			t.Errorf("body = %s, want internal error with the request ID", w.Body.String())
+		}
+		if got := w.Header().Get(RequestIDHeader); got != "req-123" {
+			t.Errorf("X-Request-ID = %q, want the one sent", got)
+		}
+	})
+
+	t.Run("v2 envelope in Arabic", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v2/boom", nil)
+		req.Header.Set(RequestIDHeader, "not a valid id!")
+		req.Header.Set("Accept-Language", "ar")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want 500", w.Code)
+		}
+		var body struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("body %s is not JSON: %v", w.Body.String(), err)
+		}
+		if body.Error.Code != "internal_error" || body.Error.Message != errorMessages[domain.LocaleArabic]["internal error"] {
+			t.Errorf("body = %s, want the localized v2 envelope", w.Body.String())
+		}
+		if got := w.Header().Get(RequestIDHeader); got == "" || got == "not a valid id!" {
+			t.Errorf("X-Request-ID = %q, want a generated ID", got)
+		}
+	})
+
+	if got := Panics().Total - before; got != 2 {
+		t.Errorf("panics counted = %d, want 2", got)
+	}
+}
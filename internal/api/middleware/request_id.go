@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDContextKey holds the request ID
+const RequestIDContextKey = "request_id"
+
+// validRequestID limits the request IDs taken from clients to what is safe to log and echo
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestIDMiddleware gives every request an ID, echoed in X-Request-ID. An ID the client
+// (or a proxy in front of the API) sent is kept so requests can be traced end to end.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.New().String()
+		}
+		c.Set(RequestIDContextKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID of the request, empty when RequestIDMiddleware did not run
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDContextKey)
+}
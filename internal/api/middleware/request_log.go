@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// RequestLogMiddleware records each authenticated partner request for usage reporting.
+// Must run after AuthMiddleware. Logs are written in the background so they never slow down
+// or fail the request.
+func RequestLogMiddleware(repos *repository.Repositories, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		partner, ok := GetPartnerFromContext(c)
+		if !ok {
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		entry := &domain.APIRequestLog{
+			PartnerID:  partner.ID,
+			Method:     c.Request.Method,
+			Route:      route,
+			Status:     c.Writer.Status(),
+			DurationMs: int(time.Since(start).Milliseconds()),
+			CreatedAt:  start,
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := repos.APIRequestLog.Create(ctx, entry); err != nil {
+				logger.Warn("Failed to record API request", zap.Error(err))
+			}
+		}()
+	}
+}
@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionContextKey holds the API version ("v1", "v2") of the request
+const APIVersionContextKey = "api_version"
+
+// APIVersionMiddleware records the API version a route group serves
+func APIVersionMiddleware(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(APIVersionContextKey, version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the API version of the request, "v1" when unset
+func GetAPIVersion(c *gin.Context) string {
+	if version := c.GetString(APIVersionContextKey); version != "" {
+		return version
+	}
+	return "v1"
+}
+
+// DeprecationMiddleware marks every response of a deprecated API version: Deprecation
+// (RFC 9745) from deprecatedAt, Sunset (RFC 8594) with the shutdown date and a Link to the
+// migration guide. Zero times and an empty link are left out.
+func DeprecationMiddleware(deprecatedAt, sunsetAt time.Time, link string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !deprecatedAt.IsZero() {
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+		}
+		if !sunsetAt.IsZero() {
+			c.Header("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+		}
+		if link != "" {
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", link))
+		}
+		c.Next()
+	}
+}
+
+// V2ResponseMiddleware adapts responses written by the shared handlers to the v2 shapes.
+// Handlers write the v1 error body {"error": "message", "details": ...}; v2 clients get
+// {"error": {"code": "not_found", "message": "...", "details": ...}}, with a stable code
+// derived from the status. Successful responses pass through unchanged.
+func V2ResponseMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &errorEnvelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.body.Len() == 0 {
+			return
+		}
+		body := writer.body.Bytes()
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "application/json") {
+			if converted, ok := toV2ErrorEnvelope(writer.Status(), body); ok {
+				body = converted
+			}
+		}
+		c.Writer.Write(body)
+	}
+}
+
+// errorEnvelopeWriter holds back error (4xx/5xx) bodies so they can be rewritten
+type errorEnvelopeWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *errorEnvelopeWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *errorEnvelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. for streaming responses
+func (w *errorEnvelopeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// toV2ErrorEnvelope nests a v1 error body under "error". Fields other than error and
+// details stay at the top level. ok is false for bodies that are not v1 errors.
+func toV2ErrorEnvelope(status int, body []byte) ([]byte, bool) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, false
+	}
+	message, ok := fields["error"].(string)
+	if !ok {
+		return nil, false
+	}
+
+	// ErrorLocalizationMiddleware puts the message to show next to the English error
+	if localized, ok := fields["message"].(string); ok {
+		message = localized
+		delete(fields, "message")
+	}
+	envelope := map[string]interface{}{
+		"code":    errorCode(status),
+		"message": message,
+	}
+	if details, ok := fields["details"]; ok {
+		envelope["details"] = details
+		delete(fields, "details")
+	}
+	fields["error"] = envelope
+
+	converted, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return converted, true
+}
+
+// errorCode is the machine-readable v2 error code for an HTTP status
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusUnprocessableEntity:
+		return "validation_failed"
+	case http.StatusTooManyRequests:
+		return "rate_limited"
+	case http.StatusBadGateway:
+		return "upstream_error"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	}
+	if status >= http.StatusInternalServerError {
+		return "internal_error"
+	}
+	return "error"
+}
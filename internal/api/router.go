@@ -6,12 +6,19 @@ import (
 
 	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/api/adminui"
 	"github.com/jafarshop/b2bapi/internal/api/handlers"
 	"github.com/jafarshop/b2bapi/internal/api/middleware"
+	"github.com/jafarshop/b2bapi/internal/authguard"
+	"github.com/jafarshop/b2bapi/internal/events"
+	"github.com/jafarshop/b2bapi/internal/service"
 )
 
-// NewRouter creates and configures the Gin router
-func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *gin.Engine {
+// NewRouter creates and configures the Gin router. logLevel is the logger's level,
+// which admins can change at runtime. authGuard tracks failed authentications.
+// services are shared by all requests. orderFeed serves the live admin order stream and is
+// nil when it is disabled.
+func NewRouter(cfg *config.Config, repos *repository.Repositories, services *service.Services, authGuard *authguard.Guard, orderFeed *events.Feed, logger *zap.Logger, logLevel zap.AtomicLevel) *gin.Engine {
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -19,7 +26,8 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.L
 	router := gin.New()
 
 	// Middleware
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.RecoveryMiddleware(services.Errors, logger))
 	router.Use(loggingMiddleware(logger))
 
 	// Health check
@@ -27,32 +35,157 @@ func NewRouter(cfg *config.Config, repos *repository.Repositories, logger *zap.L
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Admin dashboard (static UI calling the admin API below)
+	adminui.Register(router)
+
 	// API v1 routes
 	v1 := router.Group("/v1")
-	{
-		// Partner routes (require authentication)
-		partnerRoutes := v1.Group("")
-		partnerRoutes.Use(middleware.AuthMiddleware(repos, logger))
-		partnerRoutes.Use(middleware.IdempotencyMiddleware(repos, logger))
-		{
-			partnerRoutes.POST("/carts/submit", handlers.HandleCartSubmit(cfg, repos, logger))
-			partnerRoutes.GET("/orders/:id", handlers.HandleGetOrder(repos, logger))
-		}
-
-		// Admin routes (internal - for now using same auth, can be separated later)
-		adminRoutes := v1.Group("/admin")
-		adminRoutes.Use(middleware.AuthMiddleware(repos, logger))
-		{
-			adminRoutes.POST("/orders/:id/confirm", handlers.HandleConfirmOrder(repos, logger))
-			adminRoutes.POST("/orders/:id/reject", handlers.HandleRejectOrder(repos, logger))
-			adminRoutes.POST("/orders/:id/ship", handlers.HandleShipOrder(repos, logger))
-			adminRoutes.GET("/orders", handlers.HandleListOrders(repos, logger))
-		}
+	v1.Use(middleware.APIVersionMiddleware("v1"))
+	if !cfg.API.V1DeprecatedAt.IsZero() || !cfg.API.V1SunsetAt.IsZero() {
+		v1.Use(middleware.DeprecationMiddleware(cfg.API.V1DeprecatedAt, cfg.API.V1SunsetAt, cfg.API.DeprecationLink))
 	}
+	registerRoutes(v1, cfg, repos, services, authGuard, orderFeed, logger, logLevel)
+
+	// API v2 routes: the same handlers, with v2 response shapes applied on the way out
+	v2 := router.Group("/v2")
+	v2.Use(middleware.APIVersionMiddleware("v2"))
+	v2.Use(middleware.V2ResponseMiddleware())
+	registerRoutes(v2, cfg, repos, services, authGuard, orderFeed, logger, logLevel)
 
 	return router
 }
 
+// registerRoutes registers the API under a version group. Versions share handlers; response
+// shape differences between versions live in middleware (see middleware.V2ResponseMiddleware).
+func registerRoutes(version *gin.RouterGroup, cfg *config.Config, repos *repository.Repositories, services *service.Services, authGuard *authguard.Guard, orderFeed *events.Feed, logger *zap.Logger, logLevel zap.AtomicLevel) {
+	// Error messages in the client's language; inside the v2 envelope middleware
+	version.Use(middleware.ErrorLocalizationMiddleware())
+	// Server errors to Sentry (optional), with the handler's English error
+	version.Use(middleware.ErrorReportingMiddleware(services.Errors))
+	// Mutations fail with 503 during maintenance, except the POSTs that only read and the
+	// switch itself
+	version.Use(middleware.MaintenanceMiddleware(services.Maintenance,
+		version.BasePath()+"/carts/validate",
+		version.BasePath()+"/orders/status-batch",
+		version.BasePath()+"/admin/reports",
+		version.BasePath()+"/admin/maintenance",
+	))
+
+	// Onboarding (public - the invitation token is the credential)
+	version.POST("/onboarding/accept", handlers.HandleAcceptInvitation(services, logger))
+
+	// JSON Schemas of request and webhook payloads (public - for partner tooling)
+	version.GET("/schemas", handlers.HandleListSchemas())
+	version.GET("/schemas/:name", handlers.HandleGetSchema())
+
+	// Export downloads (public - the signed, expiring link is the credential)
+	version.GET("/exports/:id/download", handlers.HandleDownloadExport(cfg, repos, logger))
+
+	// Partner routes (require authentication)
+	partnerRoutes := version.Group("")
+	partnerRoutes.Use(middleware.AuthMiddleware(repos, authGuard, logger))
+	partnerRoutes.Use(middleware.RequestLogMiddleware(repos, logger))
+	partnerRoutes.Use(middleware.IdempotencyMiddleware(repos, logger))
+	{
+		partnerRoutes.POST("/carts/submit", handlers.HandleCartSubmit(cfg, services, repos, logger))
+		partnerRoutes.POST("/carts/validate", handlers.HandleCartValidate(cfg, services, repos, logger))
+		partnerRoutes.GET("/orders/:id", handlers.HandleGetOrder(repos, logger))
+		partnerRoutes.POST("/orders/status-batch", handlers.HandleOrderStatusBatch(repos, logger))
+		partnerRoutes.GET("/catalog", handlers.HandleGetCatalog(repos, logger))
+		partnerRoutes.GET("/me", handlers.HandleGetMe(services, logger))
+		partnerRoutes.GET("/organization", handlers.HandleGetOrganization(repos, logger))
+		partnerRoutes.GET("/organization/orders", handlers.HandleListOrganizationOrders(repos, logger))
+		partnerRoutes.GET("/organization/report", handlers.HandleGetOrganizationReport(repos, logger))
+		partnerRoutes.GET("/capabilities", handlers.HandleGetCapabilities(cfg, repos, logger))
+		partnerRoutes.POST("/orders/:id/events", handlers.HandleCreateOrderEvent(services, repos, logger))
+		partnerRoutes.GET("/orders/:id/substitutions", handlers.HandleListSubstitutions(repos, logger))
+		partnerRoutes.POST("/orders/:id/substitutions/:substitution_id/accept", handlers.HandleAcceptSubstitution(services, repos, logger))
+		partnerRoutes.POST("/orders/:id/substitutions/:substitution_id/decline", handlers.HandleDeclineSubstitution(services, repos, logger))
+		partnerRoutes.PUT("/partner/webhook", handlers.HandleUpdateWebhookURL(services, logger))
+		partnerRoutes.POST("/partner/webhook/secret", handlers.HandleRotateWebhookSecret(services, logger))
+		partnerRoutes.GET("/partner/webhook/subscriptions", handlers.HandleGetWebhookSubscriptions(repos, logger))
+		partnerRoutes.PUT("/partner/webhook/subscriptions", handlers.HandleUpdateWebhookSubscriptions(services, logger))
+		partnerRoutes.GET("/webhooks/event-types", handlers.HandleListWebhookEventTypes())
+		partnerRoutes.GET("/webhooks/deliveries", handlers.HandleListWebhookDeliveries(repos, logger))
+		partnerRoutes.POST("/webhooks/deliveries/:id/retry", handlers.HandleRetryWebhookDelivery(services, logger))
+		partnerRoutes.PUT("/partner/locale", handlers.HandleUpdateLocale(services, logger))
+		partnerRoutes.PUT("/partner/status-codes", handlers.HandleUpdateStatusCodes(services, logger))
+		partnerRoutes.GET("/partner/digest", handlers.HandleGetDigest(cfg, repos, logger))
+		partnerRoutes.PUT("/partner/digest", handlers.HandleUpdateDigest(cfg, repos, logger))
+		partnerRoutes.DELETE("/partner/digest", handlers.HandleDeleteDigest(cfg, repos, logger))
+		partnerRoutes.GET("/changelog", handlers.HandleListChangelog(repos, logger))
+	}
+
+	// Admin routes (internal - for now using same auth, can be separated later)
+	adminRoutes := version.Group("/admin")
+	adminRoutes.Use(middleware.AuthMiddleware(repos, authGuard, logger))
+	ifMatch := middleware.OrderIfMatchMiddleware(repos, cfg.Orders.RequireIfMatch, logger)
+	{
+		adminRoutes.POST("/orders/:id/confirm", ifMatch, handlers.HandleConfirmOrder(services, repos, logger))
+		adminRoutes.GET("/orders/:id/approvals", handlers.HandleGetOrderApprovals(services, repos, logger))
+		adminRoutes.POST("/orders/:id/reject", ifMatch, handlers.HandleRejectOrder(services, repos, logger))
+		adminRoutes.POST("/orders/bulk-reject", handlers.HandleBulkRejectOrders(cfg, services, repos, logger))
+		adminRoutes.POST("/orders/:id/ship", ifMatch, handlers.HandleShipOrder(services, repos, logger))
+		adminRoutes.GET("/orders/:id/shipping-quotes", handlers.HandleGetShippingQuotes(services, logger))
+		adminRoutes.POST("/orders/:id/hold", ifMatch, handlers.HandleHoldOrder(services, repos, logger))
+		adminRoutes.POST("/orders/:id/release", ifMatch, handlers.HandleReleaseOrder(services, repos, logger))
+		adminRoutes.POST("/orders/:id/reassign", ifMatch, handlers.HandleReassignOrder(services, logger))
+		adminRoutes.GET("/orders/:id/substitutions", handlers.HandleAdminListSubstitutions(repos, logger))
+		adminRoutes.POST("/orders/:id/substitutions", ifMatch, handlers.HandleProposeSubstitution(services, repos, logger))
+		adminRoutes.POST("/orders/:id/reconcile", handlers.HandleReconcileOrder(services, repos, logger))
+		adminRoutes.POST("/orders/:id/check-total", handlers.HandleCheckOrderTotal(services, repos, logger))
+		adminRoutes.GET("/orders/:id/shopify-diff", handlers.HandleGetOrderShopifyDiff(services, repos, logger))
+		adminRoutes.GET("/orders", handlers.HandleListOrders(repos, logger))
+		adminRoutes.GET("/orders/duplicates", handlers.HandleListDuplicateOrders(cfg, services, logger))
+		adminRoutes.GET("/orders/stream", handlers.HandleOrderStream(orderFeed, logger))
+		adminRoutes.GET("/orders/:id", handlers.HandleAdminGetOrder(repos, logger))
+		adminRoutes.GET("/review-queue", handlers.HandleListReviewQueue(services, logger))
+		adminRoutes.POST("/review-queue/:id/claim", handlers.HandleClaimReviewOrder(services, logger))
+		adminRoutes.POST("/review-queue/:id/release", handlers.HandleReleaseReviewOrder(services, logger))
+		adminRoutes.PUT("/review-queue/:id/assignee", handlers.HandleAssignReviewOrder(services, logger))
+		adminRoutes.GET("/shopify-orders/:shopify_order_id", handlers.HandleGetOrderByShopifyID(services, logger))
+		adminRoutes.GET("/order-references/:reference", handlers.HandleGetOrderByReference(repos, logger))
+		adminRoutes.GET("/carriers", handlers.HandleListCarriers(cfg))
+		adminRoutes.POST("/invitations", handlers.HandleCreateInvitation(services, logger))
+		adminRoutes.GET("/partners/:id/catalog", handlers.HandleGetPartnerCatalog(repos, logger))
+		adminRoutes.POST("/partners/:id/catalog", handlers.HandleAddPartnerCatalogSKUs(services, repos, logger))
+		adminRoutes.DELETE("/partners/:id/catalog/:sku", handlers.HandleRemovePartnerCatalogSKU(services, repos, logger))
+		adminRoutes.PUT("/partners/:id/payment-terms", handlers.HandleUpdatePaymentTerms(services, repos, logger))
+		adminRoutes.PUT("/partners/:id/substitutions", handlers.HandleUpdateSubstitutions(services, repos, logger))
+		adminRoutes.GET("/partners/:id/credit", handlers.HandleGetPartnerCredit(services, repos, logger))
+		adminRoutes.PUT("/partners/:id/credit-limit", handlers.HandleUpdateCreditLimit(services, repos, logger))
+		adminRoutes.GET("/partners/:id/usage", handlers.HandleGetPartnerUsage(repos, logger))
+		adminRoutes.POST("/partners/:id/api-key", handlers.HandleIssueAPIKey(cfg, services, repos, logger))
+		adminRoutes.POST("/partners/:id/api-key/revoke", handlers.HandleRevokeAPIKey(services, repos, logger))
+		adminRoutes.GET("/partners/:id/api-key/revocations", handlers.HandleListRevokedAPIKeys(repos, logger))
+		adminRoutes.PUT("/partners/:id/organization", handlers.HandleUpdatePartnerOrganization(services, repos, logger))
+		adminRoutes.POST("/organizations", handlers.HandleCreateOrganization(services, logger))
+		adminRoutes.GET("/organizations", handlers.HandleListOrganizations(repos, logger))
+		adminRoutes.GET("/organizations/:id", handlers.HandleAdminGetOrganization(repos, logger))
+		adminRoutes.GET("/stats", handlers.HandleGetStats(repos, logger))
+		adminRoutes.GET("/retention", handlers.HandleGetRetention(cfg))
+		adminRoutes.POST("/retention/purge", handlers.HandlePurgeRetention(cfg, repos, logger))
+		adminRoutes.GET("/invariants", handlers.HandleGetInvariants(cfg))
+		adminRoutes.POST("/invariants/check", handlers.HandleCheckInvariants(cfg, repos, logger))
+		adminRoutes.POST("/reports", handlers.HandleRunReport(repos, logger))
+		adminRoutes.GET("/reports/total-mismatches", handlers.HandleListTotalMismatches(repos, logger))
+		adminRoutes.GET("/reports/missed-sku-matches", handlers.HandleListMissedSKUMatches(services, logger))
+		adminRoutes.GET("/reports/unmatched-skus", handlers.HandleListUnmatchedSKUs(repos, logger))
+		adminRoutes.GET("/sku-mapping-changes", handlers.HandleListSKUMappingChanges(repos, logger))
+		adminRoutes.GET("/auth-failures", handlers.HandleGetAuthFailures(cfg, authGuard))
+		adminRoutes.GET("/panics", handlers.HandleGetPanics())
+		adminRoutes.GET("/log-level", handlers.HandleGetLogLevel(logLevel))
+		adminRoutes.PUT("/log-level", handlers.HandleUpdateLogLevel(logLevel, logger))
+		adminRoutes.GET("/maintenance", handlers.HandleGetMaintenance(services))
+		adminRoutes.PUT("/maintenance", handlers.HandleStartMaintenance(services, logger))
+		adminRoutes.DELETE("/maintenance", handlers.HandleEndMaintenance(services, logger))
+		adminRoutes.POST("/changelog", handlers.HandlePublishChangelogEntry(repos, logger))
+		adminRoutes.DELETE("/changelog/:id", handlers.HandleDeleteChangelogEntry(repos, logger))
+		adminRoutes.POST("/exports", handlers.HandleCreateExport(cfg, repos, logger, version.BasePath()))
+		adminRoutes.GET("/exports/:id", handlers.HandleGetExport(cfg, repos, logger, version.BasePath()))
+	}
+}
+
 // loggingMiddleware logs HTTP requests
 func loggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -66,6 +199,7 @@ func loggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 			zap.String("method", method),
 			zap.String("path", path),
 			zap.Int("status", status),
+			zap.String("request_id", middleware.GetRequestID(c)),
 		)
 	}
 }
@@ -0,0 +1,160 @@
+// Package archive writes old order events and audit logs to S3-compatible object storage as
+// gzip-compressed JSONL files before they are purged from the database, and reads them back
+// for investigations.
+//
+// Files are named <prefix>/<table>/<yyyy>/<mm>/<dd>/<oldest>_<newest>_<batch>.jsonl.gz, dated
+// by the oldest record they contain, so a time range maps to a set of key prefixes.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// Archived tables
+const (
+	TableOrderEvents = "order_events"
+	TableAuditLogs   = "audit_logs"
+)
+
+// OrderEventRecord is one order event line in an archive file
+type OrderEventRecord struct {
+	ID              uuid.UUID              `json:"id"`
+	SupplierOrderID uuid.UUID              `json:"supplier_order_id"`
+	EventType       string                 `json:"event_type"`
+	EventData       map[string]interface{} `json:"event_data,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+}
+
+// AuditLogRecord is one audit log line in an archive file
+type AuditLogRecord struct {
+	ID           uuid.UUID              `json:"id"`
+	Actor        string                 `json:"actor"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// FromOrderEvent converts an order event to its archive record
+func FromOrderEvent(event *domain.OrderEvent) OrderEventRecord {
+	return OrderEventRecord{
+		ID:              event.ID,
+		SupplierOrderID: event.SupplierOrderID,
+		EventType:       event.EventType,
+		EventData:       event.EventData,
+		CreatedAt:       event.CreatedAt,
+	}
+}
+
+// OrderEvent converts the record back to an order event
+func (r OrderEventRecord) OrderEvent() *domain.OrderEvent {
+	return &domain.OrderEvent{
+		ID:              r.ID,
+		SupplierOrderID: r.SupplierOrderID,
+		EventType:       r.EventType,
+		EventData:       r.EventData,
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+// FromAuditLog converts an audit log to its archive record
+func FromAuditLog(log *domain.AuditLog) AuditLogRecord {
+	return AuditLogRecord{
+		ID:           log.ID,
+		Actor:        log.Actor,
+		Action:       log.Action,
+		ResourceType: log.ResourceType,
+		ResourceID:   log.ResourceID,
+		Data:         log.Data,
+		CreatedAt:    log.CreatedAt,
+	}
+}
+
+// AuditLog converts the record back to an audit log
+func (r AuditLogRecord) AuditLog() *domain.AuditLog {
+	return &domain.AuditLog{
+		ID:           r.ID,
+		Actor:        r.Actor,
+		Action:       r.Action,
+		ResourceType: r.ResourceType,
+		ResourceID:   r.ResourceID,
+		Data:         r.Data,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// Key names the archive file of a batch of records created between oldest and newest
+func Key(prefix, table string, oldest, newest time.Time) string {
+	oldest, newest = oldest.UTC(), newest.UTC()
+	return fmt.Sprintf("%s%s/%s_%s_%s.jsonl.gz",
+		TablePrefix(prefix, table), oldest.Format("2006/01/02"),
+		oldest.Format("20060102T150405Z"), newest.Format("20060102T150405Z"), uuid.New().String()[:8])
+}
+
+// TablePrefix is the key prefix of a table's archive files
+func TablePrefix(prefix, table string) string {
+	if prefix != "" {
+		prefix += "/"
+	}
+	return prefix + table + "/"
+}
+
+// DayPrefix is the key prefix of a table's archive files dated day
+func DayPrefix(prefix, table string, day time.Time) string {
+	return TablePrefix(prefix, table) + day.UTC().Format("2006/01/02") + "/"
+}
+
+// Encode writes records as gzip-compressed JSONL
+func Encode[T any](records []T) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode reads gzip-compressed JSONL records
+func Decode[T any](data []byte) ([]T, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var records []T
+	reader := bufio.NewReader(gz)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var record T
+			if err := json.Unmarshal(line, &record); err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
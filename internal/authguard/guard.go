@@ -0,0 +1,224 @@
+// Package authguard slows down API key guessing. Failed authentications are counted per source
+// IP and per presented key prefix; a source with too many failures is locked out for a while,
+// without touching the database or bcrypt, and each repeated lockout doubles the wait.
+//
+// Counters live in memory, so every server instance keeps its own.
+package authguard
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/pkg/apikey"
+)
+
+// Scopes failures are counted in
+const (
+	ScopeIP        = "ip"
+	ScopeKeyPrefix = "key_prefix"
+)
+
+// keyPrefixLength is how much of a presented key identifies it; API keys are long random
+// tokens, so a prefix does not help guessing the rest
+const keyPrefixLength = 8
+
+// maxTracked bounds the tracked sources; expired ones are dropped first
+const maxTracked = 50000
+
+type source struct {
+	failures    int
+	windowStart time.Time
+	// lockouts is how many times in a row the source was locked out; it sets the next duration
+	lockouts    int
+	lockedUntil time.Time
+}
+
+// Lockout is a source that is currently locked out
+type Lockout struct {
+	Scope       string
+	Value       string
+	Lockouts    int
+	LockedUntil time.Time
+}
+
+// Stats are the guard's counters since the process started
+type Stats struct {
+	Failures int64
+	Rejected int64
+	Lockouts int64
+	Active   []Lockout
+}
+
+// Guard tracks authentication failures
+type Guard struct {
+	cfg    config.AuthGuardConfig
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	sources  map[string]*source
+	failures int64
+	rejected int64
+	lockouts int64
+}
+
+// New creates a guard; with MaxFailures <= 0 it never locks anyone out
+func New(cfg config.AuthGuardConfig, logger *zap.Logger) *Guard {
+	return &Guard{
+		cfg:     cfg,
+		logger:  logger,
+		sources: make(map[string]*source),
+	}
+}
+
+// KeyPrefix is the part of a presented API key failures are counted under. For keys in the
+// b2b_<short code>_v2_ format (see pkg/apikey) the random part starts after the partner's
+// prefix, which is public, so the prefix alone would let anyone lock out all of the
+// partner's keys.
+func KeyPrefix(apiKey string) string {
+	if prefix, ok := apikey.Parse(apiKey); ok {
+		identifying := len(prefix.String()) + 1
+		return apiKey[:identifying+keyPrefixLength]
+	}
+	if len(apiKey) > keyPrefixLength {
+		return apiKey[:keyPrefixLength]
+	}
+	return apiKey
+}
+
+// Check returns how long the IP or the key's prefix is still locked out (0 when neither is).
+// Locked out requests should be rejected before the key is verified.
+func (g *Guard) Check(ip, apiKey string) time.Duration {
+	if g == nil || g.cfg.MaxFailures <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var wait time.Duration
+	for _, id := range sourceIDs(ip, apiKey) {
+		if s, ok := g.sources[id]; ok && now.Before(s.lockedUntil) && s.lockedUntil.Sub(now) > wait {
+			wait = s.lockedUntil.Sub(now)
+		}
+	}
+	if wait > 0 {
+		g.rejected++
+	}
+	return wait
+}
+
+// Failure records a failed authentication with apiKey from ip
+func (g *Guard) Failure(ip, apiKey string) {
+	if g == nil || g.cfg.MaxFailures <= 0 {
+		return
+	}
+
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.failures++
+	for _, id := range sourceIDs(ip, apiKey) {
+		s, ok := g.sources[id]
+		if !ok {
+			if len(g.sources) >= maxTracked {
+				g.evictExpired(now)
+			}
+			if len(g.sources) >= maxTracked {
+				continue
+			}
+			s = &source{}
+			g.sources[id] = s
+		}
+
+		if now.Sub(s.windowStart) > g.cfg.Window {
+			// A source that behaved for a whole window starts over
+			if now.After(s.lockedUntil.Add(g.cfg.Window)) {
+				s.lockouts = 0
+			}
+			s.failures = 0
+			s.windowStart = now
+		}
+		s.failures++
+		if s.failures < g.cfg.MaxFailures {
+			continue
+		}
+
+		duration := g.cfg.Lockout << s.lockouts
+		if duration > g.cfg.MaxLockout || duration <= 0 {
+			duration = g.cfg.MaxLockout
+		}
+		s.lockouts++
+		s.lockedUntil = now.Add(duration)
+		s.failures = 0
+		s.windowStart = now
+		g.lockouts++
+
+		scope, value := splitSourceID(id)
+		g.logger.Error("Authentication failure threshold exceeded, locking out",
+			zap.String("scope", scope),
+			zap.String("value", value),
+			zap.Int("max_failures", g.cfg.MaxFailures),
+			zap.Duration("window", g.cfg.Window),
+			zap.Duration("lockout", duration),
+			zap.Int("consecutive_lockouts", s.lockouts),
+		)
+	}
+}
+
+// Success clears the failures of the key's prefix. The IP keeps its count, so one valid key
+// does not give a source unlimited guesses.
+func (g *Guard) Success(apiKey string) {
+	if g == nil || g.cfg.MaxFailures <= 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sources, ScopeKeyPrefix+":"+KeyPrefix(apiKey))
+}
+
+// Stats returns the counters and the sources locked out now, soonest released first
+func (g *Guard) Stats() Stats {
+	if g == nil {
+		return Stats{}
+	}
+
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := Stats{Failures: g.failures, Rejected: g.rejected, Lockouts: g.lockouts}
+	for id, s := range g.sources {
+		if now.Before(s.lockedUntil) {
+			scope, value := splitSourceID(id)
+			stats.Active = append(stats.Active, Lockout{Scope: scope, Value: value, Lockouts: s.lockouts, LockedUntil: s.lockedUntil})
+		}
+	}
+	sort.Slice(stats.Active, func(i, j int) bool { return stats.Active[i].LockedUntil.Before(stats.Active[j].LockedUntil) })
+	return stats
+}
+
+func (g *Guard) evictExpired(now time.Time) {
+	for id, s := range g.sources {
+		if now.Sub(s.windowStart) > g.cfg.Window && !now.Before(s.lockedUntil) {
+			delete(g.sources, id)
+		}
+	}
+}
+
+func sourceIDs(ip, apiKey string) []string {
+	return []string{ScopeIP + ":" + ip, ScopeKeyPrefix + ":" + KeyPrefix(apiKey)}
+}
+
+// splitSourceID splits "<scope>:<value>"; scopes have no colon, IPv6 values do
+func splitSourceID(id string) (string, string) {
+	scope, value, _ := strings.Cut(id, ":")
+	return scope, value
+}
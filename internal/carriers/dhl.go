@@ -0,0 +1,122 @@
+package carriers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultDHLTrackingURL is the DHL Shipment Tracking - Unified API endpoint
+const DefaultDHLTrackingURL = "https://api-eu.dhl.com/track/shipments"
+
+const dhlStatusDelivered = "delivered"
+
+type dhlTracker struct {
+	apiKey     string
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewDHLTracker creates a tracking adapter for the DHL Shipment Tracking - Unified API
+func NewDHLTracker(apiKey, endpoint string) Tracker {
+	if endpoint == "" {
+		endpoint = DefaultDHLTrackingURL
+	}
+	return &dhlTracker{
+		apiKey:   apiKey,
+		endpoint: endpoint,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// dhlTrackingResponse is the part of the Unified API response we use
+type dhlTrackingResponse struct {
+	Shipments []struct {
+		ID     string `json:"id"`
+		Status struct {
+			Timestamp   string `json:"timestamp"`
+			StatusCode  string `json:"statusCode"`
+			Status      string `json:"status"`
+			Description string `json:"description"`
+		} `json:"status"`
+		Details struct {
+			ProofOfDelivery *struct {
+				Timestamp    string `json:"timestamp"`
+				SignatureURL string `json:"signatureUrl"`
+				DocumentURL  string `json:"documentUrl"`
+				Signed       *struct {
+					Name string `json:"name"`
+				} `json:"signed"`
+			} `json:"proofOfDelivery"`
+		} `json:"details"`
+	} `json:"shipments"`
+}
+
+func (t *dhlTracker) Track(ctx context.Context, trackingNumber string) (*TrackingStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.endpoint+"?trackingNumber="+url.QueryEscape(trackingNumber), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DHL tracking request: %w", err)
+	}
+	req.Header.Set("DHL-API-Key", t.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call DHL tracking API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// Not yet known to DHL (e.g. label created but not scanned)
+		return &TrackingStatus{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DHL tracking API returned status %d", resp.StatusCode)
+	}
+
+	var body dhlTrackingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode DHL tracking response: %w", err)
+	}
+	if len(body.Shipments) == 0 {
+		return &TrackingStatus{}, nil
+	}
+
+	shipment := body.Shipments[0]
+	status := &TrackingStatus{
+		Delivered:   shipment.Status.StatusCode == dhlStatusDelivered,
+		Description: shipment.Status.Description,
+	}
+	if !status.Delivered {
+		return status, nil
+	}
+
+	if at, err := time.Parse(time.RFC3339, shipment.Status.Timestamp); err == nil {
+		status.DeliveredAt = &at
+	}
+
+	if pod := shipment.Details.ProofOfDelivery; pod != nil {
+		status.ProofOfDelivery = map[string]interface{}{
+			"carrier_shipment_id": shipment.ID,
+		}
+		if pod.Timestamp != "" {
+			status.ProofOfDelivery["timestamp"] = pod.Timestamp
+		}
+		if pod.SignatureURL != "" {
+			status.ProofOfDelivery["signature_url"] = pod.SignatureURL
+		}
+		if pod.DocumentURL != "" {
+			status.ProofOfDelivery["document_url"] = pod.DocumentURL
+		}
+		if pod.Signed != nil && pod.Signed.Name != "" {
+			status.ProofOfDelivery["signed_by"] = pod.Signed.Name
+		}
+	}
+
+	return status, nil
+}
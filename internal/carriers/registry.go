@@ -0,0 +1,163 @@
+// Package carriers knows the shipping carriers orders can be shipped with: how to build
+// their public tracking URLs and, for carriers with API adapters, how to ask their API
+// whether a shipment was delivered and what shipping a parcel would cost.
+package carriers
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// TrackingNumberPlaceholder is replaced with the tracking number in URL templates
+const TrackingNumberPlaceholder = "{tracking_number}"
+
+// Carrier is a shipping carrier orders can be shipped with
+type Carrier struct {
+	Code                string
+	Name                string
+	TrackingURLTemplate string
+	// Tracker polls the carrier's API; nil when the carrier has no adapter
+	Tracker Tracker
+	// Quoter asks the carrier's API for shipping rates; nil when the carrier has no adapter
+	Quoter Quoter
+}
+
+// TrackingURL builds the public tracking URL for a tracking number, or "" without a template
+func (c *Carrier) TrackingURL(trackingNumber string) string {
+	if c.TrackingURLTemplate == "" {
+		return ""
+	}
+	return strings.ReplaceAll(c.TrackingURLTemplate, TrackingNumberPlaceholder, url.QueryEscape(trackingNumber))
+}
+
+// Tracker is implemented by carrier API adapters
+type Tracker interface {
+	Track(ctx context.Context, trackingNumber string) (*TrackingStatus, error)
+}
+
+// TrackingStatus is what a carrier reports for a shipment
+type TrackingStatus struct {
+	Delivered   bool
+	DeliveredAt *time.Time
+	// Description is the carrier's own wording of the latest update
+	Description string
+	// ProofOfDelivery holds whatever the carrier provides (signed by, photo URL, ...)
+	ProofOfDelivery map[string]interface{}
+}
+
+// Quoter is implemented by carrier rate API adapters
+type Quoter interface {
+	Quote(ctx context.Context, parcel domain.Parcel, destination domain.Address) ([]RateQuote, error)
+}
+
+// RateQuote is one shipping service a carrier offers for a parcel
+type RateQuote struct {
+	Service  string
+	Amount   float64
+	Currency string
+	// EstimatedDays is the carrier's transit time estimate, nil when not given
+	EstimatedDays *int
+}
+
+// builtIn are the carriers known without configuration
+var builtIn = []Carrier{
+	{Code: "aramex", Name: "Aramex", TrackingURLTemplate: "https://www.aramex.com/track/results?ShipmentNumber={tracking_number}"},
+	{Code: "dhl", Name: "DHL", TrackingURLTemplate: "https://www.dhl.com/global-en/home/tracking/tracking-express.html?tracking-id={tracking_number}"},
+	{Code: "fedex", Name: "FedEx", TrackingURLTemplate: "https://www.fedex.com/fedextrack/?trknbr={tracking_number}"},
+	{Code: "ups", Name: "UPS", TrackingURLTemplate: "https://www.ups.com/track?tracknum={tracking_number}"},
+	{Code: "smsa", Name: "SMSA Express", TrackingURLTemplate: "https://www.smsaexpress.com/trackingdetails?tracknumbers={tracking_number}"},
+}
+
+// Registry holds the carriers enabled by configuration
+type Registry struct {
+	carriers     map[string]*Carrier
+	allowUnknown bool
+}
+
+// NewRegistry builds the registry from the built-in carriers and configuration
+func NewRegistry(cfg config.CarriersConfig) *Registry {
+	r := &Registry{
+		carriers:     make(map[string]*Carrier),
+		allowUnknown: cfg.AllowUnknown,
+	}
+
+	for i := range builtIn {
+		carrier := builtIn[i]
+		r.carriers[carrier.Code] = &carrier
+	}
+	for code, template := range cfg.TrackingURLTemplates {
+		code = normalizeCode(code)
+		if carrier, ok := r.carriers[code]; ok {
+			carrier.TrackingURLTemplate = template
+			continue
+		}
+		r.carriers[code] = &Carrier{Code: code, Name: code, TrackingURLTemplate: template}
+	}
+
+	if len(cfg.Enabled) > 0 {
+		enabled := make(map[string]bool, len(cfg.Enabled))
+		for _, code := range cfg.Enabled {
+			enabled[normalizeCode(code)] = true
+		}
+		for code := range r.carriers {
+			if !enabled[code] {
+				delete(r.carriers, code)
+			}
+		}
+	}
+
+	return r
+}
+
+// Lookup finds an enabled carrier by code or display name, case-insensitively
+func (r *Registry) Lookup(value string) (*Carrier, bool) {
+	code := normalizeCode(value)
+	if carrier, ok := r.carriers[code]; ok {
+		return carrier, true
+	}
+	for _, carrier := range r.carriers {
+		if normalizeCode(carrier.Name) == code {
+			return carrier, true
+		}
+	}
+	return nil, false
+}
+
+// AllowUnknown reports whether carriers outside the registry are accepted
+func (r *Registry) AllowUnknown() bool {
+	return r.allowUnknown
+}
+
+// SetTracker attaches an API adapter to an enabled carrier; ignored if the carrier is disabled
+func (r *Registry) SetTracker(code string, tracker Tracker) {
+	if carrier, ok := r.carriers[normalizeCode(code)]; ok {
+		carrier.Tracker = tracker
+	}
+}
+
+// SetQuoter attaches a rate API adapter to an enabled carrier; ignored if the carrier is disabled
+func (r *Registry) SetQuoter(code string, quoter Quoter) {
+	if carrier, ok := r.carriers[normalizeCode(code)]; ok {
+		carrier.Quoter = quoter
+	}
+}
+
+// Carriers returns the enabled carriers sorted by code
+func (r *Registry) Carriers() []*Carrier {
+	list := make([]*Carrier, 0, len(r.carriers))
+	for _, carrier := range r.carriers {
+		list = append(list, carrier)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+func normalizeCode(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), "_")
+}
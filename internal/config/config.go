@@ -1,19 +1,89 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata" // DIGEST_TIMEZONE has to resolve in minimal images too
 
 	"github.com/spf13/viper"
+
+	"github.com/jafarshop/b2bapi/internal/secrets"
+	"github.com/jafarshop/b2bapi/pkg/sentry"
 )
 
 type Config struct {
-	Port        string
-	Environment string
-	Database    DatabaseConfig
-	Shopify     ShopifyConfig
-	API         APIConfig
-	LogLevel    string
+	Port             string
+	Environment      string
+	Database         DatabaseConfig
+	Shopify          ShopifyConfig
+	API              APIConfig
+	GRPC             GRPCConfig
+	Events           EventsConfig
+	Duplicates       DuplicatesConfig
+	Orders           OrdersConfig
+	Carriers         CarriersConfig
+	ShopifyEditSync  ShopifyEditSyncConfig
+	TotalCheck       TotalCheckConfig
+	Approvals        ApprovalsConfig
+	Retention        RetentionConfig
+	Rollups          RollupsConfig
+	LowStock         LowStockConfig
+	Invariants       InvariantsConfig
+	CreditAlerts     CreditAlertsConfig
+	Archive          ArchiveConfig
+	BatchIngest      BatchIngestConfig
+	Fulfillment      FulfillmentConfig
+	OpsAlerts        OpsAlertsConfig
+	ReviewQueue      ReviewQueueConfig
+	Exports          ExportsConfig
+	SKUNormalization SKUNormalizationConfig
+	Digest           DigestConfig
+	Tax              TaxConfig
+	SMTP             SMTPConfig
+	Secrets          SecretsConfig
+	Encryption       EncryptionConfig
+	AuthGuard        AuthGuardConfig
+	Sentry           SentryConfig
+
+	// secretProvider and secretValues are set when settings are read from a secrets backend
+	secretProvider secrets.Provider
+	secretValues   []*secretValue
+
+	// tunables are swapped by Reload, so they are only read through Tunables()
+	tunables atomic.Pointer[Tunables]
+}
+
+// Tunables are the non-secret settings that can change without a restart (see Reload)
+type Tunables struct {
+	LogLevel string
+	// CatalogRestrictionMode decides what happens to SKUs outside a partner's catalog:
+	// "ignore" treats them as non-supplier items, "reject" fails the cart
+	CatalogRestrictionMode string
+	// DuplicateWindow is how far back orders are compared for duplicates
+	DuplicateWindow time.Duration
+	// NotifyPartnerOnShopifyEdits sends an order.items_changed webhook when a Shopify divergence is found
+	NotifyPartnerOnShopifyEdits bool
+	// Maintenance rejects mutations with 503 while reads keep working
+	Maintenance bool
+	// MaintenanceRetryAfter is the Retry-After sent during maintenance without a known end
+	MaintenanceRetryAfter time.Duration
+}
+
+// Tunables returns the current reloadable settings
+func (c *Config) Tunables() Tunables {
+	return *c.tunables.Load()
+}
+
+// SetTunables replaces the reloadable settings, e.g. in tests
+func (c *Config) SetTunables(t Tunables) {
+	c.tunables.Store(&t)
 }
 
 type DatabaseConfig struct {
@@ -23,15 +93,440 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// ReplicaDSN points listings and reports at a read replica (lib/pq DSN or postgres:// URL);
+	// empty sends everything to the primary
+	ReplicaDSN string
+
+	// user and password are set when DB_USER_SECRET / DB_PASSWORD_SECRET are used (see Credentials)
+	user     *secretValue
+	password *secretValue
+}
+
+// SecretsConfig selects where credentials come from
+type SecretsConfig struct {
+	// Backend is env (plaintext environment variables), vault or aws
+	Backend string
+	// RefreshInterval is how often secrets are re-read to pick up rotations (0 reads them once at startup)
+	RefreshInterval time.Duration
+}
+
+// AuthGuardConfig limits failed authentications per source IP and per presented key prefix
+type AuthGuardConfig struct {
+	// MaxFailures within Window lock the source out (0 disables the guard)
+	MaxFailures int
+	Window      time.Duration
+	// Lockout is the first lockout's duration; each repeated one doubles it up to MaxLockout
+	Lockout    time.Duration
+	MaxLockout time.Duration
+}
+
+// SentryConfig reports errors to Sentry or a compatible service
+type SentryConfig struct {
+	// DSN of the project errors go to; empty disables error reporting
+	DSN         string
+	Environment string
+	Release     string
+}
+
+// EncryptionConfig protects credentials stored in the database
+type EncryptionConfig struct {
+	// Key (32 bytes) encrypts partner webhook secrets; nil stores them in plain text
+	Key []byte
 }
 
 type ShopifyConfig struct {
 	ShopDomain  string
 	AccessToken string
+	// DryRun simulates Shopify calls: nothing is sent, draft orders are recorded as pending operations
+	DryRun bool
+	// LinkCustomers attaches draft orders to a Shopify customer found (or created) by phone or email
+	LinkCustomers bool
+	// TaxMode decides how tax reaches the Shopify order (TaxModeShopify, TaxModeExempt or TaxModeCart)
+	TaxMode string
+	// TagTemplates are the extra tags put on draft orders, e.g. "country:{{shipping.country}}"
+	// (see TagTemplateFields). A tag with a field that is empty for the order is left out.
+	TagTemplates []string
+	// Currency is the shop currency; cart amounts are taken to be in it and are not converted
+	Currency string
+
+	// accessToken is set when SHOPIFY_ACCESS_TOKEN_SECRET is used (see CurrentAccessToken)
+	accessToken *secretValue
 }
 
 type APIConfig struct {
 	KeyHashSalt string
+	// V1DeprecatedAt announces /v1 as deprecated (Deprecation header) from this time; zero means not deprecated
+	V1DeprecatedAt time.Time
+	// V1SunsetAt announces when /v1 stops working (Sunset header); zero means no date yet
+	V1SunsetAt time.Time
+	// DeprecationLink points partners to the migration guide (Link header)
+	DeprecationLink string
+	// AuthCacheTTL is how long authenticated partners are cached in memory (0 disables the cache)
+	AuthCacheTTL time.Duration
+	// LegacyKeyImport lets admins set a partner's API key to a given value (keys migrated from
+	// another system) instead of a generated one
+	LegacyKeyImport bool
+}
+
+type GRPCConfig struct {
+	Enabled bool
+	Port    string
+}
+
+type OrdersConfig struct {
+	// ReferencePrefix starts human-friendly order references (<prefix>-<year>-<number>)
+	ReferencePrefix string
+	// RequireIfMatch rejects admin order changes without an If-Match header (428)
+	RequireIfMatch bool
+	// PendingExpiry cancels orders still pending confirmation this long after they were
+	// created (0 keeps them pending)
+	PendingExpiry time.Duration
+	// ExpiryCheckInterval is how often expired pending orders are looked for
+	ExpiryCheckInterval time.Duration
+	// StreamEnabled serves the live admin order stream, fed by Postgres LISTEN/NOTIFY
+	StreamEnabled bool
+}
+
+type CarriersConfig struct {
+	// Enabled limits which registry carriers can be used (codes); empty enables all
+	Enabled []string
+	// TrackingURLTemplates adds carriers or overrides their tracking URL, by code.
+	// {tracking_number} in a template is replaced with the (URL-escaped) number.
+	TrackingURLTemplates map[string]string
+	// AllowUnknown accepts carriers that are not in the registry (without a generated tracking URL)
+	AllowUnknown bool
+	// PollInterval is how often shipped orders are checked with carrier tracking APIs (0 disables it)
+	PollInterval time.Duration
+	// DHLAPIKey enables the DHL tracking adapter (DHL Shipment Tracking - Unified API)
+	DHLAPIKey string
+	// DHLTrackingURL overrides the DHL tracking API endpoint (e.g. the sandbox)
+	DHLTrackingURL string
+}
+
+type ShopifyEditSyncConfig struct {
+	// Interval is how often orders are compared with their Shopify orders (0 disables it)
+	Interval time.Duration
+	// Window is how far back (by creation time) orders are compared
+	Window time.Duration
+}
+
+type TotalCheckConfig struct {
+	// Interval is how often cart totals are compared with Shopify order totals (0 disables it)
+	Interval time.Duration
+	// Window is how far back (by creation time) orders are compared
+	Window time.Duration
+	// Tolerance is the largest difference between the totals that is not flagged
+	Tolerance float64
+}
+
+type ApprovalsConfig struct {
+	// Threshold is the cart total from which an order needs two distinct admins to confirm
+	// it before its Shopify order is completed (0 disables the two-person rule)
+	Threshold float64
+}
+
+type RetentionConfig struct {
+	// Interval is how often old rows are purged (0 disables it)
+	Interval time.Duration
+	// BatchSize is how many rows one DELETE removes
+	BatchSize int
+	// MaxBatches caps the batches per table and run, so one run never holds the database for long
+	MaxBatches int
+	// Retention periods by table; 0 keeps rows forever
+	IdempotencyKeys time.Duration
+	OrderEvents     time.Duration
+	AuditLogs       time.Duration
+}
+
+type RollupsConfig struct {
+	// Interval is how often recent days are rolled up again, e.g. 24h (0 disables it)
+	Interval time.Duration
+	// Lookback is how far back each run recomputes, since orders keep changing status after
+	// the day they were created; older days keep their rollups until they are rebuilt
+	Lookback time.Duration
+}
+
+type LowStockConfig struct {
+	// Interval is how often the stock of recently ordered variants is checked (0 disables it)
+	Interval time.Duration
+	// Threshold alerts partners when a variant's Shopify inventory drops below it
+	Threshold int
+	// Window is how recently a partner must have ordered a variant to be alerted about it
+	Window time.Duration
+}
+
+type InvariantsConfig struct {
+	// Interval is how often orders are checked for impossible data (0 disables it)
+	Interval time.Duration
+	// SampleSize is how many offending orders are kept per invariant for the admin endpoint
+	SampleSize int
+}
+
+type ExportsConfig struct {
+	// SigningKey signs export download links; it defaults to API_KEY_HASH_SALT
+	SigningKey string
+	// LinkTTL is how long a download link works
+	LinkTTL time.Duration
+	// FileTTL is how long a generated file is kept
+	FileTTL time.Duration
+	// InlineRows is the most rows generated during the request; larger exports are generated
+	// in the background
+	InlineRows int
+}
+
+type CreditAlertsConfig struct {
+	// Thresholds are the percentages of their credit limit at which partners are alerted,
+	// in ascending order; empty disables the alerts
+	Thresholds []int
+}
+
+type ArchiveConfig struct {
+	// Bucket enables archiving: order events and audit logs are uploaded before they are purged
+	Bucket string
+	// Endpoint is the S3-compatible service URL; empty means AWS S3 in Region
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Prefix starts the keys of archive files
+	Prefix string
+}
+
+// Enabled reports whether old rows are archived before they are purged
+func (c ArchiveConfig) Enabled() bool {
+	return c.Bucket != ""
+}
+
+type BatchIngestConfig struct {
+	// Interval is how often partners' CSV order files are picked up (0 disables it)
+	Interval time.Duration
+	// Bucket is the S3-compatible bucket partners drop order files in
+	Bucket string
+	// Endpoint is the S3-compatible service URL; empty means AWS S3 in Region
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Prefix starts the keys of order and result files: <prefix>/<partner id>/inbox/<file>.csv
+	Prefix string
+}
+
+type FulfillmentConfig struct {
+	// Interval is how often confirmed orders are exported to the 3PL and its shipment files
+	// are imported (0 disables it)
+	Interval time.Duration
+	// Bucket is the S3-compatible bucket shared with the 3PL
+	Bucket string
+	// Endpoint is the S3-compatible service URL; empty means AWS S3 in Region
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Prefix starts the keys of order and shipment files
+	Prefix string
+	// Format of the order files: FulfillmentFormatCSV or FulfillmentFormatFixed
+	Format string
+	// Fields are the columns of the order files, one line per item
+	Fields []FulfillmentField
+	// Carrier is used for shipment lines without a carrier
+	Carrier string
+}
+
+// FulfillmentField is a column of the 3PL order files; Width is only used by fixed-width files
+type FulfillmentField struct {
+	Name  string
+	Width int
+}
+
+// Fulfillment order file formats
+const (
+	FulfillmentFormatCSV   = "csv"
+	FulfillmentFormatFixed = "fixed"
+)
+
+// FulfillmentFields are the order and item fields 3PL order files can contain
+var FulfillmentFields = []string{
+	"order_id",
+	"reference",
+	"partner_order_id",
+	"partner_name",
+	"confirmed_at",
+	"customer_name",
+	"customer_phone",
+	"customer_email",
+	"street",
+	"address2",
+	"city",
+	"state",
+	"postal_code",
+	"country",
+	"notes",
+	"sku",
+	"title",
+	"quantity",
+	"price",
+	"weight_grams",
+}
+
+// DefaultFulfillmentFields are the columns used when FULFILLMENT_FIELDS is not set
+const DefaultFulfillmentFields = "reference:20,customer_name:40,customer_phone:20,street:60,city:30,postal_code:10,country:2,sku:30,quantity:6"
+
+// parseFulfillmentFields reads a list of fields such as "reference:20,sku:30". Widths are
+// required for fixed-width files and optional otherwise.
+func parseFulfillmentFields(values []string, fixed bool) ([]FulfillmentField, error) {
+	fields := make([]FulfillmentField, 0, len(values))
+	for _, value := range values {
+		name, width, hasWidth := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		known := false
+		for _, field := range FulfillmentFields {
+			if name == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		field := FulfillmentField{Name: name}
+		if hasWidth {
+			n, err := strconv.Atoi(strings.TrimSpace(width))
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("width of %s must be a positive number", name)
+			}
+			field.Width = n
+		} else if fixed {
+			return nil, fmt.Errorf("%s needs a width in fixed-width files, e.g. %s:20", name, name)
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields")
+	}
+	return fields, nil
+}
+
+type OpsAlertsConfig struct {
+	// WebhookURL is the Slack or Microsoft Teams incoming webhook that gets every alert
+	// without a route of its own (empty disables them)
+	WebhookURL string
+	// Routes send alerts of an event type to another webhook, or nowhere with "off"
+	Routes map[string]string
+	// ConfirmationSLA alerts about orders pending confirmation longer than this (0 disables it)
+	ConfirmationSLA time.Duration
+	// SLACheckInterval is how often orders are checked against ConfirmationSLA
+	SLACheckInterval time.Duration
+}
+
+// URL returns the webhook alerts of the event type are posted to, empty when they are off
+func (c OpsAlertsConfig) URL(eventType string) string {
+	if url, ok := c.Routes[eventType]; ok {
+		if strings.EqualFold(url, "off") {
+			return ""
+		}
+		return url
+	}
+	return c.WebhookURL
+}
+
+type ReviewQueueConfig struct {
+	// FlagEvents are the order event types that put an order in the review queue (empty uses
+	// the defaults: duplicates, tax and Shopify total mismatches, Shopify item edits)
+	FlagEvents []string
+	// FlagWindow is how long a flag keeps an order in the queue
+	FlagWindow time.Duration
+}
+
+type SKUNormalizationConfig struct {
+	// CaseFold matches SKUs case-insensitively
+	CaseFold bool
+	// CollapseWhitespace turns runs of whitespace inside SKUs into a single space
+	CollapseWhitespace bool
+	// StripPrefixes are removed from the start of SKUs (e.g. a partner's own "JS-" prefix)
+	StripPrefixes []string
+}
+
+// Tax modes
+const (
+	TaxModeShopify = "shopify" // Shopify calculates tax on the draft order
+	TaxModeExempt  = "exempt"  // the order is tax exempt in Shopify
+	TaxModeCart    = "cart"    // the cart's tax amount is added as a line, Shopify tax is off
+)
+
+// DefaultTagTemplates are the draft order tags used when SHOPIFY_TAG_TEMPLATES is not set
+var DefaultTagTemplates = []string{"pending_confirmation", "b2b_ref:{{reference}}"}
+
+// TagTemplateFields are the order fields tag templates can use as {{field}}
+var TagTemplateFields = []string{
+	"name", // partner name
+	"partner_order_id",
+	"reference",
+	"channel",
+	"payment_method",
+	"locale",
+	"shipping.country",
+	"shipping.city",
+	"shipping.state",
+}
+
+var tagTemplateField = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// validateTagTemplate rejects templates with unknown fields or unbalanced braces, which
+// would otherwise end up in Shopify as literal tags
+func validateTagTemplate(template string) error {
+	for _, match := range tagTemplateField.FindAllStringSubmatch(template, -1) {
+		known := false
+		for _, field := range TagTemplateFields {
+			if match[1] == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown field {{%s}} in %q", match[1], template)
+		}
+	}
+	if rest := tagTemplateField.ReplaceAllString(template, ""); strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return fmt.Errorf("unbalanced braces in %q", template)
+	}
+	return nil
+}
+
+type TaxConfig struct {
+	// Rates are the expected tax rates by country code (e.g. JO: 0.16); cart tax is checked against them
+	Rates map[string]float64
+}
+
+type DigestConfig struct {
+	// Enabled starts the daily partner email digest job
+	Enabled bool
+	// SendTime is the default HH:MM (in Location) for partners that do not choose one
+	SendTime string
+	// Location is the time zone of send times and digest periods
+	Location *time.Location
+}
+
+type SMTPConfig struct {
+	// Host is the SMTP server; without it emails are only logged
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type DuplicatesConfig struct {
+	// CheckInterval is how often the background duplicate check runs (0 disables it)
+	CheckInterval time.Duration
+	// SubmissionWindow is how long a cart submitted again without an idempotency key is
+	// answered with the order it already created (0 disables it)
+	SubmissionWindow time.Duration
+}
+
+type EventsConfig struct {
+	Driver  string // none, kafka or nats
+	Brokers []string
+	Topic   string
 }
 
 func Load() (*Config, error) {
@@ -47,6 +542,28 @@ func Load() (*Config, error) {
 	viper.SetDefault("DB_PORT", "5432")
 	viper.SetDefault("DB_SSLMODE", "disable")
 	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("GRPC_ENABLED", "false")
+	viper.SetDefault("GRPC_PORT", "9090")
+	viper.SetDefault("EVENTS_DRIVER", "none")
+	viper.SetDefault("EVENTS_TOPIC", "b2b.order_events")
+	viper.SetDefault("CATALOG_RESTRICTION_MODE", "ignore")
+	viper.SetDefault("DUPLICATE_CHECK_INTERVAL", "15m")
+	viper.SetDefault("DUPLICATE_CHECK_WINDOW", "72h")
+	viper.SetDefault("ORDER_REFERENCE_PREFIX", "B2B")
+	viper.SetDefault("SHOPIFY_DRY_RUN", "false")
+	viper.SetDefault("SHOPIFY_LINK_CUSTOMERS", "false")
+	viper.SetDefault("CARRIERS_ALLOW_UNKNOWN", "false")
+	viper.SetDefault("CARRIER_POLL_INTERVAL", "0")
+	viper.SetDefault("SKU_NORMALIZE_CASE", "true")
+	viper.SetDefault("SKU_NORMALIZE_WHITESPACE", "true")
+	viper.SetDefault("SHOPIFY_EDIT_SYNC_INTERVAL", "0")
+	viper.SetDefault("SHOPIFY_EDIT_SYNC_WINDOW", "336h")
+	viper.SetDefault("SHOPIFY_EDIT_SYNC_NOTIFY_PARTNER", "false")
+	viper.SetDefault("TAX_MODE", "shopify")
+	viper.SetDefault("DIGEST_ENABLED", "false")
+	viper.SetDefault("DIGEST_SEND_TIME", "08:00")
+	viper.SetDefault("DIGEST_TIMEZONE", "Asia/Amman")
+	viper.SetDefault("SMTP_PORT", "587")
 
 	// Read from environment variables
 	viper.AutomaticEnv()
@@ -69,28 +586,310 @@ func Load() (*Config, error) {
 			Password: getEnvOrViper("DB_PASSWORD", "postgres"),
 			DBName:   getEnvOrViper("DB_NAME", "b2bapi"),
 			SSLMode:  getEnvOrViper("DB_SSLMODE", "disable"),
+			// e.g. "host=replica.internal user=... dbname=b2bapi sslmode=require timezone=UTC"
+			ReplicaDSN: getEnvOrViper("DB_REPLICA_DSN", ""),
 		},
 		Shopify: ShopifyConfig{
-			ShopDomain:  getEnvOrViper("SHOPIFY_SHOP_DOMAIN", ""),
-			AccessToken: getEnvOrViper("SHOPIFY_ACCESS_TOKEN", ""),
+			// SHOPIFY_SHOP_DOMAIN_<ENVIRONMENT> (e.g. _STAGING) points each environment at its own store
+			ShopDomain:    getEnvOrViper("SHOPIFY_SHOP_DOMAIN_"+environmentSuffix(), getEnvOrViper("SHOPIFY_SHOP_DOMAIN", "")),
+			AccessToken:   getEnvOrViper("SHOPIFY_ACCESS_TOKEN_"+environmentSuffix(), getEnvOrViper("SHOPIFY_ACCESS_TOKEN", "")),
+			DryRun:        getBoolEnvOrViper("SHOPIFY_DRY_RUN", false),
+			LinkCustomers: getBoolEnvOrViper("SHOPIFY_LINK_CUSTOMERS", false),
+			TaxMode:       getEnvOrViper("TAX_MODE", TaxModeShopify),
+			TagTemplates:  getListEnvOrViper("SHOPIFY_TAG_TEMPLATES"),
+			Currency:      strings.ToUpper(getEnvOrViper("SHOPIFY_CURRENCY", "JOD")),
 		},
 		API: APIConfig{
-			KeyHashSalt: getEnvOrViper("API_KEY_HASH_SALT", "default-salt-change-in-production"),
+			KeyHashSalt:     getEnvOrViper("API_KEY_HASH_SALT", "default-salt-change-in-production"),
+			DeprecationLink: getEnvOrViper("API_DEPRECATION_LINK", ""),
+			AuthCacheTTL:    getDurationEnvOrViper("API_AUTH_CACHE_TTL", 30*time.Second),
+			LegacyKeyImport: getBoolEnvOrViper("API_KEY_LEGACY_IMPORT", false),
+		},
+		GRPC: GRPCConfig{
+			Enabled: getBoolEnvOrViper("GRPC_ENABLED", false),
+			Port:    getEnvOrViper("GRPC_PORT", "9090"),
+		},
+		Events: EventsConfig{
+			Driver:  getEnvOrViper("EVENTS_DRIVER", "none"),
+			Brokers: getListEnvOrViper("EVENTS_BROKERS"),
+			Topic:   getEnvOrViper("EVENTS_TOPIC", "b2b.order_events"),
+		},
+		Duplicates: DuplicatesConfig{
+			CheckInterval:    getDurationEnvOrViper("DUPLICATE_CHECK_INTERVAL", 15*time.Minute),
+			SubmissionWindow: getDurationEnvOrViper("DUPLICATE_SUBMISSION_WINDOW", 0),
+		},
+		Orders: OrdersConfig{
+			ReferencePrefix:     getEnvOrViper("ORDER_REFERENCE_PREFIX", "B2B"),
+			RequireIfMatch:      getBoolEnvOrViper("ORDER_REQUIRE_IF_MATCH", false),
+			PendingExpiry:       getDurationEnvOrViper("ORDER_PENDING_EXPIRY", 0),
+			ExpiryCheckInterval: getDurationEnvOrViper("ORDER_EXPIRY_CHECK_INTERVAL", 15*time.Minute),
+			StreamEnabled:       getBoolEnvOrViper("ORDER_STREAM_ENABLED", false),
+		},
+		Carriers: CarriersConfig{
+			Enabled:              getListEnvOrViper("CARRIERS"),
+			TrackingURLTemplates: getMapEnvOrViper("CARRIER_TRACKING_URLS"),
+			AllowUnknown:         getBoolEnvOrViper("CARRIERS_ALLOW_UNKNOWN", false),
+			PollInterval:         getDurationEnvOrViper("CARRIER_POLL_INTERVAL", 0),
+			DHLAPIKey:            getEnvOrViper("DHL_API_KEY", ""),
+			DHLTrackingURL:       getEnvOrViper("DHL_TRACKING_URL", ""),
+		},
+		ShopifyEditSync: ShopifyEditSyncConfig{
+			Interval: getDurationEnvOrViper("SHOPIFY_EDIT_SYNC_INTERVAL", 0),
+			Window:   getDurationEnvOrViper("SHOPIFY_EDIT_SYNC_WINDOW", 14*24*time.Hour),
+		},
+		TotalCheck: TotalCheckConfig{
+			Interval:  getDurationEnvOrViper("TOTAL_CHECK_INTERVAL", 0),
+			Window:    getDurationEnvOrViper("TOTAL_CHECK_WINDOW", 30*24*time.Hour),
+			Tolerance: 0.01,
+		},
+		Retention: RetentionConfig{
+			Interval:        getDurationEnvOrViper("RETENTION_INTERVAL", 0),
+			BatchSize:       getIntEnvOrViper("RETENTION_BATCH_SIZE", 1000),
+			MaxBatches:      getIntEnvOrViper("RETENTION_MAX_BATCHES", 100),
+			IdempotencyKeys: getDurationEnvOrViper("RETENTION_IDEMPOTENCY_KEYS", 30*24*time.Hour),
+			OrderEvents:     getDurationEnvOrViper("RETENTION_ORDER_EVENTS", 0),
+			AuditLogs:       getDurationEnvOrViper("RETENTION_AUDIT_LOGS", 0),
+		},
+		Rollups: RollupsConfig{
+			Interval: getDurationEnvOrViper("ROLLUP_INTERVAL", 0),
+			Lookback: getDurationEnvOrViper("ROLLUP_LOOKBACK", 30*24*time.Hour),
+		},
+		LowStock: LowStockConfig{
+			Interval:  getDurationEnvOrViper("LOW_STOCK_INTERVAL", 0),
+			Threshold: getIntEnvOrViper("LOW_STOCK_THRESHOLD", 5),
+			Window:    getDurationEnvOrViper("LOW_STOCK_WINDOW", 30*24*time.Hour),
+		},
+		Invariants: InvariantsConfig{
+			Interval:   getDurationEnvOrViper("INVARIANT_CHECK_INTERVAL", 0),
+			SampleSize: getIntEnvOrViper("INVARIANT_CHECK_SAMPLE_SIZE", 20),
+		},
+		Exports: ExportsConfig{
+			SigningKey: getEnvOrViper("EXPORT_SIGNING_KEY", ""),
+			LinkTTL:    getDurationEnvOrViper("EXPORT_LINK_TTL", 15*time.Minute),
+			FileTTL:    getDurationEnvOrViper("EXPORT_FILE_TTL", 24*time.Hour),
+			InlineRows: getIntEnvOrViper("EXPORT_INLINE_ROWS", 1000),
+		},
+		CreditAlerts: CreditAlertsConfig{
+			Thresholds: getPercentagesEnvOrViper("CREDIT_ALERT_THRESHOLDS", []int{80, 95}),
+		},
+		Archive: ArchiveConfig{
+			Bucket:          getEnvOrViper("ARCHIVE_S3_BUCKET", ""),
+			Endpoint:        getEnvOrViper("ARCHIVE_S3_ENDPOINT", ""),
+			Region:          getEnvOrViper("ARCHIVE_S3_REGION", "us-east-1"),
+			AccessKeyID:     getEnvOrViper("ARCHIVE_S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnvOrViper("ARCHIVE_S3_SECRET_ACCESS_KEY", ""),
+			Prefix:          strings.Trim(getEnvOrViper("ARCHIVE_PREFIX", "b2b-archive"), "/"),
+		},
+		BatchIngest: BatchIngestConfig{
+			Interval:        getDurationEnvOrViper("BATCH_INGEST_INTERVAL", 0),
+			Bucket:          getEnvOrViper("BATCH_INGEST_S3_BUCKET", ""),
+			Endpoint:        getEnvOrViper("BATCH_INGEST_S3_ENDPOINT", ""),
+			Region:          getEnvOrViper("BATCH_INGEST_S3_REGION", "us-east-1"),
+			AccessKeyID:     getEnvOrViper("BATCH_INGEST_S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnvOrViper("BATCH_INGEST_S3_SECRET_ACCESS_KEY", ""),
+			Prefix:          strings.Trim(getEnvOrViper("BATCH_INGEST_PREFIX", "b2b-batch"), "/"),
+		},
+		Fulfillment: FulfillmentConfig{
+			Interval:        getDurationEnvOrViper("FULFILLMENT_INTERVAL", 0),
+			Bucket:          getEnvOrViper("FULFILLMENT_S3_BUCKET", ""),
+			Endpoint:        getEnvOrViper("FULFILLMENT_S3_ENDPOINT", ""),
+			Region:          getEnvOrViper("FULFILLMENT_S3_REGION", "us-east-1"),
+			AccessKeyID:     getEnvOrViper("FULFILLMENT_S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnvOrViper("FULFILLMENT_S3_SECRET_ACCESS_KEY", ""),
+			Prefix:          strings.Trim(getEnvOrViper("FULFILLMENT_PREFIX", "b2b-3pl"), "/"),
+			Format:          strings.ToLower(getEnvOrViper("FULFILLMENT_FORMAT", FulfillmentFormatCSV)),
+			Carrier:         getEnvOrViper("FULFILLMENT_CARRIER", ""),
+		},
+		OpsAlerts: OpsAlertsConfig{
+			WebhookURL:       getEnvOrViper("OPS_ALERTS_WEBHOOK_URL", ""),
+			Routes:           getMapEnvOrViper("OPS_ALERTS_ROUTES"),
+			ConfirmationSLA:  getDurationEnvOrViper("OPS_ALERTS_CONFIRMATION_SLA", 0),
+			SLACheckInterval: getDurationEnvOrViper("OPS_ALERTS_SLA_CHECK_INTERVAL", 5*time.Minute),
+		},
+		ReviewQueue: ReviewQueueConfig{
+			FlagEvents: getListEnvOrViper("REVIEW_QUEUE_FLAG_EVENTS"),
+			FlagWindow: getDurationEnvOrViper("REVIEW_QUEUE_FLAG_WINDOW", 7*24*time.Hour),
+		},
+		SKUNormalization: SKUNormalizationConfig{
+			CaseFold:           getBoolEnvOrViper("SKU_NORMALIZE_CASE", true),
+			CollapseWhitespace: getBoolEnvOrViper("SKU_NORMALIZE_WHITESPACE", true),
+			StripPrefixes:      getListEnvOrViper("SKU_STRIP_PREFIXES"),
+		},
+		Tax: TaxConfig{
+			Rates: make(map[string]float64),
+		},
+		Digest: DigestConfig{
+			Enabled:  getBoolEnvOrViper("DIGEST_ENABLED", false),
+			SendTime: getEnvOrViper("DIGEST_SEND_TIME", "08:00"),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnvOrViper("SMTP_HOST", ""),
+			Port:     getEnvOrViper("SMTP_PORT", "587"),
+			Username: getEnvOrViper("SMTP_USERNAME", ""),
+			Password: getEnvOrViper("SMTP_PASSWORD", ""),
+			From:     getEnvOrViper("SMTP_FROM", ""),
 		},
-		LogLevel: getEnvOrViper("LOG_LEVEL", "info"),
+		AuthGuard: AuthGuardConfig{
+			MaxFailures: getIntEnvOrViper("AUTH_MAX_FAILURES", 10),
+			Window:      getDurationEnvOrViper("AUTH_FAILURE_WINDOW", 5*time.Minute),
+			Lockout:     getDurationEnvOrViper("AUTH_LOCKOUT", time.Minute),
+			MaxLockout:  getDurationEnvOrViper("AUTH_MAX_LOCKOUT", time.Hour),
+		},
+		Secrets: SecretsConfig{
+			Backend:         getEnvOrViper("SECRETS_BACKEND", "env"),
+			RefreshInterval: getDurationEnvOrViper("SECRETS_REFRESH_INTERVAL", 5*time.Minute),
+		},
+		Sentry: SentryConfig{
+			DSN:         getEnvOrViper("SENTRY_DSN", ""),
+			Environment: getEnvOrViper("SENTRY_ENVIRONMENT", ""),
+			Release:     getEnvOrViper("SENTRY_RELEASE", ""),
+		},
+	}
+	if cfg.Exports.SigningKey == "" {
+		cfg.Exports.SigningKey = cfg.API.KeyHashSalt
+	}
+	if cfg.Exports.LinkTTL <= 0 || cfg.Exports.FileTTL < cfg.Exports.LinkTTL {
+		return nil, fmt.Errorf("EXPORT_LINK_TTL must be positive and EXPORT_FILE_TTL at least as long")
+	}
+	if cfg.Sentry.Environment == "" {
+		cfg.Sentry.Environment = cfg.Environment
+	}
+	if cfg.Sentry.DSN != "" {
+		if _, err := sentry.New(cfg.Sentry.DSN, sentry.Options{}); err != nil {
+			return nil, fmt.Errorf("SENTRY_DSN: %w", err)
+		}
+	}
+
+	if mode := cfg.Shopify.TaxMode; mode != TaxModeShopify && mode != TaxModeExempt && mode != TaxModeCart {
+		return nil, fmt.Errorf("TAX_MODE must be shopify, exempt or cart")
+	}
+	if cfg.Orders.PendingExpiry > 0 && cfg.Orders.ExpiryCheckInterval <= 0 {
+		return nil, fmt.Errorf("ORDER_EXPIRY_CHECK_INTERVAL must be positive when ORDER_PENDING_EXPIRY is set")
+	}
+	if len(cfg.Shopify.TagTemplates) == 0 {
+		cfg.Shopify.TagTemplates = DefaultTagTemplates
+	}
+	for _, template := range cfg.Shopify.TagTemplates {
+		if err := validateTagTemplate(template); err != nil {
+			return nil, fmt.Errorf("SHOPIFY_TAG_TEMPLATES: %w", err)
+		}
+	}
+	for country, value := range getMapEnvOrViper("TAX_RATES") {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate >= 1 {
+			return nil, fmt.Errorf("TAX_RATES: rate for %s must be a fraction such as 0.16", country)
+		}
+		cfg.Tax.Rates[strings.ToUpper(country)] = rate
+	}
+
+	for key, target := range map[string]*time.Time{
+		"API_V1_DEPRECATED_AT": &cfg.API.V1DeprecatedAt,
+		"API_V1_SUNSET_AT":     &cfg.API.V1SunsetAt,
+	} {
+		if value := getEnvOrViper(key, ""); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an RFC3339 timestamp", key)
+			}
+			*target = parsed
+		}
+	}
+
+	if value := getEnvOrViper("TOTAL_CHECK_TOLERANCE", ""); value != "" {
+		tolerance, err := strconv.ParseFloat(value, 64)
+		if err != nil || tolerance < 0 {
+			return nil, fmt.Errorf("TOTAL_CHECK_TOLERANCE must be a non-negative amount such as 0.01")
+		}
+		cfg.TotalCheck.Tolerance = tolerance
+	}
+	if value := getEnvOrViper("APPROVAL_THRESHOLD", ""); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("APPROVAL_THRESHOLD must be a non-negative amount such as 5000")
+		}
+		cfg.Approvals.Threshold = threshold
 	}
 
+	if cfg.AuthGuard.MaxFailures > 0 && (cfg.AuthGuard.Window <= 0 || cfg.AuthGuard.Lockout <= 0 || cfg.AuthGuard.MaxLockout < cfg.AuthGuard.Lockout) {
+		return nil, fmt.Errorf("AUTH_FAILURE_WINDOW and AUTH_LOCKOUT must be positive and AUTH_MAX_LOCKOUT at least AUTH_LOCKOUT")
+	}
+	if cfg.Retention.BatchSize <= 0 || cfg.Retention.MaxBatches <= 0 {
+		return nil, fmt.Errorf("RETENTION_BATCH_SIZE and RETENTION_MAX_BATCHES must be positive")
+	}
+	if cfg.Archive.Enabled() && (cfg.Archive.AccessKeyID == "" || cfg.Archive.SecretAccessKey == "") {
+		return nil, fmt.Errorf("ARCHIVE_S3_ACCESS_KEY_ID and ARCHIVE_S3_SECRET_ACCESS_KEY are required when ARCHIVE_S3_BUCKET is set")
+	}
+	if cfg.BatchIngest.Interval > 0 && (cfg.BatchIngest.Bucket == "" || cfg.BatchIngest.AccessKeyID == "" || cfg.BatchIngest.SecretAccessKey == "") {
+		return nil, fmt.Errorf("BATCH_INGEST_S3_BUCKET, BATCH_INGEST_S3_ACCESS_KEY_ID and BATCH_INGEST_S3_SECRET_ACCESS_KEY are required when BATCH_INGEST_INTERVAL is set")
+	}
+	if cfg.Fulfillment.Interval > 0 && (cfg.Fulfillment.Bucket == "" || cfg.Fulfillment.AccessKeyID == "" || cfg.Fulfillment.SecretAccessKey == "") {
+		return nil, fmt.Errorf("FULFILLMENT_S3_BUCKET, FULFILLMENT_S3_ACCESS_KEY_ID and FULFILLMENT_S3_SECRET_ACCESS_KEY are required when FULFILLMENT_INTERVAL is set")
+	}
+	if format := cfg.Fulfillment.Format; format != FulfillmentFormatCSV && format != FulfillmentFormatFixed {
+		return nil, fmt.Errorf("FULFILLMENT_FORMAT must be csv or fixed")
+	}
+	if cfg.OpsAlerts.ConfirmationSLA > 0 && cfg.OpsAlerts.SLACheckInterval <= 0 {
+		return nil, fmt.Errorf("OPS_ALERTS_SLA_CHECK_INTERVAL must be positive when OPS_ALERTS_CONFIRMATION_SLA is set")
+	}
+	if cfg.ReviewQueue.FlagWindow <= 0 {
+		return nil, fmt.Errorf("REVIEW_QUEUE_FLAG_WINDOW must be positive")
+	}
+	fulfillmentFields := getListEnvOrViper("FULFILLMENT_FIELDS")
+	if len(fulfillmentFields) == 0 {
+		fulfillmentFields = strings.Split(DefaultFulfillmentFields, ",")
+	}
+	fields, err := parseFulfillmentFields(fulfillmentFields, cfg.Fulfillment.Format == FulfillmentFormatFixed)
+	if err != nil {
+		return nil, fmt.Errorf("FULFILLMENT_FIELDS: %w", err)
+	}
+	cfg.Fulfillment.Fields = fields
+
+	location, err := time.LoadLocation(getEnvOrViper("DIGEST_TIMEZONE", "Asia/Amman"))
+	if err != nil {
+		return nil, fmt.Errorf("DIGEST_TIMEZONE is not a valid time zone: %w", err)
+	}
+	cfg.Digest.Location = location
+	if _, err := time.Parse("15:04", cfg.Digest.SendTime); err != nil {
+		return nil, fmt.Errorf("DIGEST_SEND_TIME must be HH:MM")
+	}
+	if cfg.SMTP.Host != "" && cfg.SMTP.From == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required when SMTP_HOST is set")
+	}
+
+	if value := getEnvOrViper("ENCRYPTION_KEY", ""); value != "" {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("ENCRYPTION_KEY must be 32 bytes, base64-encoded (openssl rand -base64 32)")
+		}
+		cfg.Encryption.Key = key
+	}
+
+	if err := loadSecrets(cfg); err != nil {
+		return nil, err
+	}
+
+	tunables, err := loadTunables()
+	if err != nil {
+		return nil, err
+	}
+	cfg.tunables.Store(tunables)
+
 	// Validate required fields
-	if cfg.Shopify.ShopDomain == "" {
-		return nil, fmt.Errorf("SHOPIFY_SHOP_DOMAIN is required")
+	if cfg.Shopify.ShopDomain == "" && !cfg.Shopify.DryRun {
+		return nil, fmt.Errorf("SHOPIFY_SHOP_DOMAIN is required (or set SHOPIFY_DRY_RUN=true)")
 	}
-	if cfg.Shopify.AccessToken == "" {
-		return nil, fmt.Errorf("SHOPIFY_ACCESS_TOKEN is required")
+	if cfg.Shopify.AccessToken == "" && !cfg.Shopify.DryRun {
+		return nil, fmt.Errorf("SHOPIFY_ACCESS_TOKEN is required (or set SHOPIFY_DRY_RUN=true)")
 	}
 
 	return cfg, nil
 }
 
+// environmentSuffix is ENVIRONMENT upper-cased for per-environment keys, e.g. "STAGING"
+func environmentSuffix() string {
+	return strings.ToUpper(getEnvOrViper("ENVIRONMENT", "development"))
+}
+
 func getEnvOrViper(key, defaultValue string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -100,3 +899,77 @@ func getEnvOrViper(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getBoolEnvOrViper(key string, defaultValue bool) bool {
+	val := getEnvOrViper(key, "")
+	if val == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
+
+func getDurationEnvOrViper(key string, defaultValue time.Duration) time.Duration {
+	val := getEnvOrViper(key, "")
+	if val == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return defaultValue
+	}
+	return d
+}
+
+func getIntEnvOrViper(key string, defaultValue int) int {
+	val := getEnvOrViper(key, "")
+	if val == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return defaultValue
+	}
+	return i
+}
+
+func getListEnvOrViper(key string) []string {
+	var values []string
+	for _, part := range strings.Split(getEnvOrViper(key, ""), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// getPercentagesEnvOrViper parses a comma-separated list of percentages (1-100), sorted
+// ascending. Invalid values are skipped, so "off" gives an empty list.
+func getPercentagesEnvOrViper(key string, defaultValue []int) []int {
+	if getEnvOrViper(key, "") == "" {
+		return defaultValue
+	}
+	values := []int{}
+	for _, part := range getListEnvOrViper(key) {
+		if p, err := strconv.Atoi(part); err == nil && p > 0 && p <= 100 {
+			values = append(values, p)
+		}
+	}
+	sort.Ints(values)
+	return values
+}
+
+// getMapEnvOrViper parses "key=value;key=value" (semicolons, since values may be URLs with commas)
+func getMapEnvOrViper(key string) map[string]string {
+	values := make(map[string]string)
+	for _, part := range strings.Split(getEnvOrViper(key, ""), ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if k, v = strings.TrimSpace(k), strings.TrimSpace(v); ok && k != "" && v != "" {
+			values[k] = v
+		}
+	}
+	return values
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+)
+
+// Change is one reloaded setting that differs from its previous value
+type Change struct {
+	Key  string
+	From string
+	To   string
+}
+
+// Reload re-reads .env and the environment and applies the tunable settings to cfg.
+// Secrets, connection settings and intervals of background jobs keep their startup
+// values and need a restart. On error cfg is left unchanged.
+func Reload(cfg *Config) ([]Change, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, fmt.Errorf("error reading config file: %w", err)
+		}
+	}
+
+	next, err := loadTunables()
+	if err != nil {
+		return nil, err
+	}
+	prev := cfg.tunables.Swap(next)
+
+	var changes []Change
+	diff := func(key, from, to string) {
+		if from != to {
+			changes = append(changes, Change{Key: key, From: from, To: to})
+		}
+	}
+	diff("LOG_LEVEL", prev.LogLevel, next.LogLevel)
+	diff("CATALOG_RESTRICTION_MODE", prev.CatalogRestrictionMode, next.CatalogRestrictionMode)
+	diff("DUPLICATE_CHECK_WINDOW", prev.DuplicateWindow.String(), next.DuplicateWindow.String())
+	diff("SHOPIFY_EDIT_SYNC_NOTIFY_PARTNER", strconv.FormatBool(prev.NotifyPartnerOnShopifyEdits), strconv.FormatBool(next.NotifyPartnerOnShopifyEdits))
+	diff("MAINTENANCE_MODE", strconv.FormatBool(prev.Maintenance), strconv.FormatBool(next.Maintenance))
+	diff("MAINTENANCE_RETRY_AFTER", prev.MaintenanceRetryAfter.String(), next.MaintenanceRetryAfter.String())
+	return changes, nil
+}
+
+func loadTunables() (*Tunables, error) {
+	t := &Tunables{
+		LogLevel:                    getEnvOrViper("LOG_LEVEL", "info"),
+		CatalogRestrictionMode:      getEnvOrViper("CATALOG_RESTRICTION_MODE", "ignore"),
+		DuplicateWindow:             getDurationEnvOrViper("DUPLICATE_CHECK_WINDOW", 72*time.Hour),
+		NotifyPartnerOnShopifyEdits: getBoolEnvOrViper("SHOPIFY_EDIT_SYNC_NOTIFY_PARTNER", false),
+		Maintenance:                 getBoolEnvOrViper("MAINTENANCE_MODE", false),
+		MaintenanceRetryAfter:       getDurationEnvOrViper("MAINTENANCE_RETRY_AFTER", 5*time.Minute),
+	}
+
+	if _, err := zapcore.ParseLevel(t.LogLevel); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error")
+	}
+	if t.CatalogRestrictionMode != "ignore" && t.CatalogRestrictionMode != "reject" {
+		return nil, fmt.Errorf("CATALOG_RESTRICTION_MODE must be ignore or reject")
+	}
+	if t.MaintenanceRetryAfter < time.Second {
+		return nil, fmt.Errorf("MAINTENANCE_RETRY_AFTER must be at least 1s")
+	}
+	return t, nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jafarshop/b2bapi/internal/secrets"
+)
+
+// secretValue is a setting read from the secrets backend. Copies of a config share it, so a
+// rotated value reaches every holder.
+type secretValue struct {
+	// key is the setting it replaces, e.g. DB_PASSWORD
+	key   string
+	ref   string
+	value atomic.Pointer[string]
+}
+
+func (v *secretValue) get(fallback string) string {
+	if v == nil {
+		return fallback
+	}
+	return *v.value.Load()
+}
+
+// CurrentAccessToken is the Shopify access token, following rotations in the secrets backend
+func (c ShopifyConfig) CurrentAccessToken() string {
+	return c.accessToken.get(c.AccessToken)
+}
+
+// Credentials are the database user and password, following rotations in the secrets backend
+func (c DatabaseConfig) Credentials() (string, string) {
+	return c.user.get(c.User), c.password.get(c.Password)
+}
+
+// loadSecrets reads the settings that reference a secret (SHOPIFY_ACCESS_TOKEN_SECRET,
+// DB_USER_SECRET, DB_PASSWORD_SECRET) from the secrets backend
+func loadSecrets(cfg *Config) error {
+	var values []*secretValue
+	reference := func(key, ref string) *secretValue {
+		if ref == "" {
+			return nil
+		}
+		v := &secretValue{key: key, ref: ref}
+		values = append(values, v)
+		return v
+	}
+	cfg.Shopify.accessToken = reference("SHOPIFY_ACCESS_TOKEN",
+		getEnvOrViper("SHOPIFY_ACCESS_TOKEN_SECRET_"+environmentSuffix(), getEnvOrViper("SHOPIFY_ACCESS_TOKEN_SECRET", "")))
+	cfg.Database.user = reference("DB_USER", getEnvOrViper("DB_USER_SECRET", ""))
+	cfg.Database.password = reference("DB_PASSWORD", getEnvOrViper("DB_PASSWORD_SECRET", ""))
+
+	provider, err := secrets.NewProvider(secrets.Config{
+		Backend:            cfg.Secrets.Backend,
+		VaultAddr:          getEnvOrViper("VAULT_ADDR", ""),
+		VaultToken:         getEnvOrViper("VAULT_TOKEN", ""),
+		VaultNamespace:     getEnvOrViper("VAULT_NAMESPACE", ""),
+		AWSRegion:          getEnvOrViper("AWS_REGION", ""),
+		AWSAccessKeyID:     getEnvOrViper("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey: getEnvOrViper("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:    getEnvOrViper("AWS_SESSION_TOKEN", ""),
+		AWSEndpoint:        getEnvOrViper("SECRETS_AWS_ENDPOINT", ""),
+	})
+	if err != nil {
+		return err
+	}
+	if provider == nil {
+		if len(values) > 0 {
+			return fmt.Errorf("%s_SECRET is set but SECRETS_BACKEND is env (want vault or aws)", values[0].key)
+		}
+		return nil
+	}
+	cfg.secretProvider = provider
+	cfg.secretValues = values
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, v := range values {
+		value, err := provider.Get(ctx, v.ref)
+		if err != nil {
+			return fmt.Errorf("failed to read %s from the secrets backend: %w", v.key, err)
+		}
+		v.value.Store(&value)
+	}
+
+	// The plain fields hold the startup values, for validation and the command-line tools
+	cfg.Shopify.AccessToken = cfg.Shopify.CurrentAccessToken()
+	cfg.Database.User, cfg.Database.Password = cfg.Database.Credentials()
+	return nil
+}
+
+// RefreshSecrets re-reads the secrets from the backend and returns the settings whose value
+// changed (never the values). A secret that fails to read keeps its current value.
+func RefreshSecrets(ctx context.Context, cfg *Config) ([]string, error) {
+	if cfg.secretProvider == nil {
+		return nil, nil
+	}
+
+	var changed []string
+	var errs []error
+	for _, v := range cfg.secretValues {
+		value, err := cfg.secretProvider.Get(ctx, v.ref)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.key, err))
+			continue
+		}
+		if prev := v.value.Swap(&value); *prev != value {
+			changed = append(changed, v.key)
+		}
+	}
+	return changed, errors.Join(errs...)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyLookupHash is the hex SHA-256 of an API key. API keys are long random tokens, so an
+// unsalted fast hash is enough to find the partner; the bcrypt hash still verifies the key.
+func APIKeyLookupHash(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// RevokedAPIKey is an API key that is rejected even while the partner is active. Keys are
+// identified by their bcrypt hash, which every partner has.
+type RevokedAPIKey struct {
+	APIKeyHash string
+	PartnerID  uuid.UUID
+	Reason     string
+	// RevokedBy is the actor that revoked the key, e.g. "partner:<admin id>"
+	RevokedBy string
+	RevokedAt time.Time
+}
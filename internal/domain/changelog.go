@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangelogType classifies a changelog entry
+type ChangelogType string
+
+const (
+	// ChangelogTypeAddition announces new endpoints, fields or event types
+	ChangelogTypeAddition ChangelogType = "addition"
+	// ChangelogTypeChange announces changed behaviour
+	ChangelogTypeChange ChangelogType = "change"
+	// ChangelogTypeDeprecation announces something going away at EffectiveAt
+	ChangelogTypeDeprecation ChangelogType = "deprecation"
+	// ChangelogTypeMaintenance announces a maintenance window from EffectiveAt to EndsAt
+	ChangelogTypeMaintenance ChangelogType = "maintenance"
+)
+
+// ChangelogTypes lists every changelog entry type
+var ChangelogTypes = []ChangelogType{
+	ChangelogTypeAddition,
+	ChangelogTypeChange,
+	ChangelogTypeDeprecation,
+	ChangelogTypeMaintenance,
+}
+
+// IsValid reports whether t is a known changelog entry type
+func (t ChangelogType) IsValid() bool {
+	for _, changelogType := range ChangelogTypes {
+		if t == changelogType {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangelogEntry is an announcement to partner integrations
+type ChangelogEntry struct {
+	ID          uuid.UUID
+	Type        ChangelogType
+	Title       string
+	Description string
+	// APIVersion is the API version concerned (e.g. "v1"), nil for all of them
+	APIVersion *string
+	// Affects lists the endpoints or fields concerned, e.g. "POST /v1/carts/submit" or "order.reference"
+	Affects []string
+	// EffectiveAt is when the change takes effect (a deprecation's removal, a maintenance window's start)
+	EffectiveAt *time.Time
+	// EndsAt is when a maintenance window ends
+	EndsAt      *time.Time
+	PublishedBy uuid.UUID
+	PublishedAt time.Time
+}
+
+// ChangelogFilter narrows a changelog listing
+type ChangelogFilter struct {
+	Type ChangelogType
+	// Since lists only entries published after this time, for polling
+	Since *time.Time
+}
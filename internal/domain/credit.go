@@ -0,0 +1,26 @@
+package domain
+
+// CreditLimitAction decides what happens to a cart that would take a partner over its credit limit
+type CreditLimitAction string
+
+const (
+	// CreditLimitActionHold creates the order and puts it ON_HOLD for review
+	CreditLimitActionHold CreditLimitAction = "hold"
+	// CreditLimitActionReject fails the cart with 422
+	CreditLimitActionReject CreditLimitAction = "reject"
+)
+
+// IsValid reports whether a is a supported credit limit action
+func (a CreditLimitAction) IsValid() bool {
+	return a == CreditLimitActionHold || a == CreditLimitActionReject
+}
+
+// OutstandingStatuses are the statuses of orders whose value counts against the credit
+// limit until they are paid
+var OutstandingStatuses = []OrderStatus{
+	OrderStatusPendingConfirmation,
+	OrderStatusConfirmed,
+	OrderStatusOnHold,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+}
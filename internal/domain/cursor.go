@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderCursor is a keyset pagination position in an order listing sorted by
+// created_at DESC, id DESC. The next page starts strictly after it.
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorAfter returns the cursor pointing past the given order
+func CursorAfter(order *SupplierOrder) *OrderCursor {
+	return &OrderCursor{CreatedAt: order.CreatedAt, ID: order.ID}
+}
+
+// Encode returns the opaque string form handed to API clients
+func (c OrderCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeOrderCursor parses a cursor produced by Encode
+func DecodeOrderCursor(s string) (*OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &OrderCursor{CreatedAt: createdAt, ID: id}, nil
+}
@@ -10,6 +10,7 @@ const (
 	OrderStatusShipped             OrderStatus = "SHIPPED"
 	OrderStatusDelivered           OrderStatus = "DELIVERED"
 	OrderStatusCancelled           OrderStatus = "CANCELLED"
+	OrderStatusOnHold              OrderStatus = "ON_HOLD"
 )
 
 // IsValid checks if the order status is valid
@@ -20,7 +21,8 @@ func (s OrderStatus) IsValid() bool {
 		OrderStatusRejected,
 		OrderStatusShipped,
 		OrderStatusDelivered,
-		OrderStatusCancelled:
+		OrderStatusCancelled,
+		OrderStatusOnHold:
 		return true
 	default:
 		return false
@@ -33,9 +35,16 @@ func (s OrderStatus) CanTransitionTo(newStatus OrderStatus) bool {
 	case OrderStatusPendingConfirmation:
 		return newStatus == OrderStatusConfirmed ||
 			newStatus == OrderStatusRejected ||
-			newStatus == OrderStatusCancelled
+			newStatus == OrderStatusCancelled ||
+			newStatus == OrderStatusOnHold
 	case OrderStatusConfirmed:
 		return newStatus == OrderStatusShipped ||
+			newStatus == OrderStatusCancelled ||
+			newStatus == OrderStatusOnHold
+	case OrderStatusOnHold:
+		// Releasing a hold restores the status the order was held from
+		// (see CanHoldFrom); while held an order can only be rejected or cancelled
+		return newStatus == OrderStatusRejected ||
 			newStatus == OrderStatusCancelled
 	case OrderStatusShipped:
 		return newStatus == OrderStatusDelivered
@@ -45,3 +54,9 @@ func (s OrderStatus) CanTransitionTo(newStatus OrderStatus) bool {
 		return false
 	}
 }
+
+// CanHoldFrom checks if an order in this status can be put on hold
+// (and therefore be released back into it)
+func (s OrderStatus) CanHoldFrom() bool {
+	return s == OrderStatusPendingConfirmation || s == OrderStatusConfirmed
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+// orderTransitions is the order lifecycle as specified; CanTransitionTo must allow
+// exactly these transitions. Releasing a hold is not listed: it restores the held-from
+// status and is checked with CanHoldFrom.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPendingConfirmation: {OrderStatusConfirmed, OrderStatusRejected, OrderStatusCancelled, OrderStatusOnHold},
+	OrderStatusConfirmed:           {OrderStatusShipped, OrderStatusCancelled, OrderStatusOnHold},
+	OrderStatusOnHold:              {OrderStatusRejected, OrderStatusCancelled},
+	OrderStatusShipped:             {OrderStatusDelivered},
+	OrderStatusRejected:            nil,
+	OrderStatusDelivered:           nil,
+	OrderStatusCancelled:           nil,
+}
+
+// undefinedStatuses are statuses the lifecycle does not define (yet); no transition
+// may lead into or out of them
+var undefinedStatuses = []OrderStatus{"PARTIALLY_SHIPPED", "", "pending_confirmation"}
+
+func allStatuses() []OrderStatus {
+	statuses := make([]OrderStatus, 0, len(orderTransitions)+len(undefinedStatuses))
+	for status := range orderTransitions {
+		statuses = append(statuses, status)
+	}
+	return append(statuses, undefinedStatuses...)
+}
+
+func allowed(from, to OrderStatus) bool {
+	for _, status := range orderTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func TestOrderTransitionsCoverEveryStatus(t *testing.T) {
+	for status := range orderTransitions {
+		if !status.IsValid() {
+			t.Errorf("transition table has undefined status %q", status)
+		}
+	}
+	for _, status := range []OrderStatus{
+		OrderStatusPendingConfirmation, OrderStatusConfirmed, OrderStatusRejected, OrderStatusShipped,
+		OrderStatusDelivered, OrderStatusCancelled, OrderStatusOnHold,
+	} {
+		if _, ok := orderTransitions[status]; !ok {
+			t.Errorf("transition table is missing %s", status)
+		}
+	}
+	for _, status := range undefinedStatuses {
+		if status.IsValid() {
+			t.Errorf("%q is valid; add it to the transition table", status)
+		}
+	}
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	for _, from := range allStatuses() {
+		for _, to := range allStatuses() {
+			if got, want := from.CanTransitionTo(to), allowed(from, to); got != want {
+				t.Errorf("%q.CanTransitionTo(%q) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestCanHoldFrom(t *testing.T) {
+	for _, status := range allStatuses() {
+		want := status == OrderStatusPendingConfirmation || status == OrderStatusConfirmed
+		if got := status.CanHoldFrom(); got != want {
+			t.Errorf("%q.CanHoldFrom() = %v, want %v", status, got, want)
+		}
+		// Statuses an order is held from are the ones it can be put on hold in
+		if status.CanHoldFrom() && !status.CanTransitionTo(OrderStatusOnHold) {
+			t.Errorf("%s can be held from but not transition to %s", status, OrderStatusOnHold)
+		}
+	}
+}
+
+// TestOrderLifecycleWalks drives orders through random sequences of requested
+// transitions (and hold releases) and checks every step stays on the table
+func TestOrderLifecycleWalks(t *testing.T) {
+	statuses := allStatuses()
+
+	walk := func(steps []uint8) bool {
+		status := OrderStatusPendingConfirmation
+		var heldFrom *OrderStatus
+		for _, step := range steps {
+			if status == OrderStatusOnHold && step%8 == 0 {
+				// Release
+				if heldFrom == nil || !heldFrom.CanHoldFrom() {
+					t.Errorf("released a hold without a valid held-from status")
+					return false
+				}
+				status, heldFrom = *heldFrom, nil
+				continue
+			}
+
+			to := statuses[int(step)%len(statuses)]
+			if !status.CanTransitionTo(to) {
+				continue
+			}
+			if !allowed(status, to) || !to.IsValid() {
+				t.Errorf("walk took undefined transition %q -> %q", status, to)
+				return false
+			}
+			if len(orderTransitions[status]) == 0 {
+				t.Errorf("walk left terminal status %s", status)
+				return false
+			}
+			if to == OrderStatusOnHold {
+				from := status
+				heldFrom = &from
+			}
+			status = to
+		}
+		return status.IsValid()
+	}
+
+	if err := quick.Check(walk, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
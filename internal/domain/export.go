@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportType is the kind of file an export job generates
+type ExportType string
+
+const (
+	// ExportTypeOrdersCSV is a CSV file of orders, one row per order
+	ExportTypeOrdersCSV ExportType = "orders_csv"
+)
+
+// ExportTypes lists every export type
+var ExportTypes = []ExportType{
+	ExportTypeOrdersCSV,
+}
+
+// IsValid reports whether t is a known export type
+func (t ExportType) IsValid() bool {
+	for _, exportType := range ExportTypes {
+		if t == exportType {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportStatus is where an export job stands
+type ExportStatus string
+
+const (
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob is an export file, generated when the job is created or in the background. Content is kept until ExpiresAt
+// and downloaded through a signed link.
+type ExportJob struct {
+	ID          uuid.UUID
+	Type        ExportType
+	Params      OrderFilter
+	Status      ExportStatus
+	Content     []byte
+	ContentType *string
+	Filename    *string
+	RowCount    int
+	Error       *string
+	RequestedBy uuid.UUID
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	ExpiresAt   time.Time
+}
+
+// OrderFilter selects the orders of an export or report; nil fields do not filter
+type OrderFilter struct {
+	PartnerID *uuid.UUID   `json:"partner_id,omitempty"`
+	Status    *OrderStatus `json:"status,omitempty"`
+	// From and To bound created_at: From inclusive, To exclusive
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+// OrderInvariant names a rule every order should satisfy; orders breaking one point to a bug
+// or a half-finished change
+type OrderInvariant string
+
+const (
+	// OrderInvariantShippedWithTracking: SHIPPED orders have a tracking number
+	OrderInvariantShippedWithTracking OrderInvariant = "shipped_with_tracking"
+	// OrderInvariantConfirmedInShopify: CONFIRMED orders have a Shopify draft order or order
+	// (only checked when Shopify is not in dry-run mode)
+	OrderInvariantConfirmedInShopify OrderInvariant = "confirmed_in_shopify"
+	// OrderInvariantHasItems: every order has at least one item
+	OrderInvariantHasItems OrderInvariant = "has_items"
+)
+
+// OrderInvariants lists every order invariant
+var OrderInvariants = []OrderInvariant{
+	OrderInvariantShippedWithTracking,
+	OrderInvariantConfirmedInShopify,
+	OrderInvariantHasItems,
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Locale is the language customer-facing text is rendered in
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleArabic  Locale = "ar"
+)
+
+// DefaultLocale is used when neither the order nor the partner sets one
+const DefaultLocale = LocaleEnglish
+
+// IsValid checks if the locale is supported
+func (l Locale) IsValid() bool {
+	return l == LocaleEnglish || l == LocaleArabic
+}
+
+// IsRTL reports whether text in this locale is written right-to-left
+func (l Locale) IsRTL() bool {
+	return l == LocaleArabic
+}
+
+// Direction returns the text direction ("rtl" or "ltr") for rendering clients
+func (l Locale) Direction() string {
+	if l.IsRTL() {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// ParseLocale maps a language tag such as "ar-JO" or "EN" to a supported locale,
+// returning false when the language is not supported
+func ParseLocale(tag string) (Locale, bool) {
+	lang := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	l := Locale(lang)
+	return l, l.IsValid()
+}
+
+// ParseAcceptLanguage picks the supported locale an Accept-Language header prefers, e.g.
+// "ar-JO,ar;q=0.9,en;q=0.8". Languages are tried by quality, then in order; false when
+// none is supported.
+func ParseAcceptLanguage(header string) (Locale, bool) {
+	best, bestQuality := Locale(""), 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		locale, ok := ParseLocale(tag)
+		if ok && quality > bestQuality {
+			best, bestQuality = locale, quality
+		}
+	}
+	return best, bestQuality > 0
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,10 +12,35 @@ type Partner struct {
 	ID         uuid.UUID
 	Name       string
 	APIKeyHash string
+	// APIKeyLookupHash finds the partner by API key (see APIKeyLookupHash); empty for partners
+	// that have not authenticated since it was introduced
+	APIKeyLookupHash string
+	// APIKeyPrefix identifies the partner's API key without revealing it, e.g. b2b_zain_v2
+	// (see pkg/apikey); empty for keys issued before the format
+	APIKeyPrefix string
 	WebhookURL *string
+	// WebhookSecret signs webhooks (see pkg/webhookverify); nil sends them unsigned
+	WebhookSecret *string
+	Locale     Locale
 	IsActive   bool
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	// PaymentTerms decide whether draft orders are completed or invoiced
+	PaymentTerms PaymentTerms
+	// InvoiceEmail receives Shopify invoices; without it Shopify uses the draft order's customer email
+	InvoiceEmail *string
+	// LegacyStatusCodes keeps 200 responses for new orders on /v1 (instead of 201/202)
+	LegacyStatusCodes bool
+	// CreditLimit caps the partner's outstanding (unpaid) order value; nil means no limit
+	CreditLimit *float64
+	// CreditLimitAction decides whether carts over the limit are held or rejected
+	CreditLimitAction CreditLimitAction
+	// OrganizationID groups the partner with the other API keys of the same merchant; nil
+	// when the partner is on its own
+	OrganizationID *uuid.UUID
+	// Substitutions keeps the partner's draft orders open until the order is confirmed, so
+	// the supplier can propose substitutes for items it cannot supply
+	Substitutions bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 // SupplierOrder represents an order from a partner
@@ -22,37 +48,93 @@ type SupplierOrder struct {
 	ID                  uuid.UUID
 	PartnerID           uuid.UUID
 	PartnerOrderID      string
+	Reference           *string // human-friendly, e.g. B2B-2024-000123
 	Status              OrderStatus
 	ShopifyDraftOrderID *int64
 	ShopifyOrderID      *int64
+	// ShopifyDraftOrderName and ShopifyOrderName are the names staff see in Shopify admin (e.g. #D12, #1001)
+	ShopifyDraftOrderName *string
+	ShopifyOrderName      *string
 	CustomerName        string
-	CustomerPhone       string
-	ShippingAddress     map[string]interface{} // JSONB
+	CustomerPhone       string  // as sent by the partner
+	CustomerPhoneE164   *string // normalized, nil if the country's numbering plan is unknown
+	CustomerEmail       *string
+	ShopifyCustomerID   *int64 // Shopify customer the draft order was linked to
+	ShippingAddress     Address // JSONB
 	CartTotal           float64
+	CartTax             float64
+	CartShipping        float64
 	PaymentStatus       string
 	PaymentMethod       *string
+	Channel             *string // partner sales channel the cart came from, e.g. web or app
+	Locale              Locale
+	ContentHash         *string // hash of the normalized cart, see CartContentHash in the service package
+	RejectionCode       *RejectionCode
 	RejectionReason     *string
 	TrackingCarrier     *string
 	TrackingNumber      *string
 	TrackingURL         *string
+	HoldReason          *string
+	HeldAt              *time.Time
+	HeldFromStatus      *OrderStatus
+	HeldSeconds         int64 // total time spent on hold before the current hold
+	ConfirmedAt         *time.Time
+	RejectedAt          *time.Time
+	ShippedAt           *time.Time
+	DeliveredAt         *time.Time
+	CancelledAt         *time.Time
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
 }
 
+// PendingSince is when the order's confirmation timers started, moved forward by the time it
+// spent on hold, so holds pause them
+func (o *SupplierOrder) PendingSince() time.Time {
+	return o.CreatedAt.Add(time.Duration(o.HeldSeconds) * time.Second)
+}
+
+// Address represents a shipping address
+type Address struct {
+	Street     string
+	Address2   *string
+	City       string
+	State      *string // Shopify province
+	PostalCode string
+	Country    string
+	Notes      *string // delivery instructions for the courier
+}
+
+// FormatOrderReference builds a human-friendly order reference, e.g. B2B-2024-000123
+func FormatOrderReference(prefix string, createdAt time.Time, number int64) string {
+	return fmt.Sprintf("%s-%d-%06d", prefix, createdAt.UTC().Year(), number)
+}
+
 // SupplierOrderItem represents an item in a supplier order
 type SupplierOrderItem struct {
 	ID              uuid.UUID
 	SupplierOrderID uuid.UUID
 	SKU             string
 	Title           string
-	Price           float64
+	Price           float64 // final unit price charged: ListPrice - Discount
+	ListPrice       float64 // unit list price before discount
+	Discount        float64 // unit discount
 	Quantity        int
 	ProductURL      *string
 	IsSupplierItem  bool
 	ShopifyVariantID *int64
+	WeightGrams     *int        // per unit, from the cart or the Shopify variant
+	Dimensions      *Dimensions // per unit, from the cart
+	MatchMethod     *string     // how a supplier item matched its SKU mapping (MatchMethodSKU, MatchMethodBarcode)
 	CreatedAt       time.Time
 }
 
+// How a cart item was matched to a SKU mapping
+const (
+	MatchMethodSKU           = "sku"
+	MatchMethodNormalizedSKU = "normalized_sku"
+	MatchMethodBarcode       = "barcode"
+)
+
 // IdempotencyKey stores idempotency information
 type IdempotencyKey struct {
 	Key             string
@@ -66,9 +148,21 @@ type IdempotencyKey struct {
 type SKUMapping struct {
 	ID              uuid.UUID
 	SKU             string
+	NormalizedSKU   string // SKU as compared with cart SKUs (see skunorm), SKU when empty
 	ShopifyProductID  int64
 	ShopifyVariantID  int64
+	WeightGrams     *int // variant weight cached from Shopify
+	// Product details cached from Shopify for the partner catalog feed
+	ProductTitle    *string
+	VariantTitle    *string
+	Vendor          *string
+	ProductType     *string
+	Barcode         *string
+	ImageURL        *string  // variant image, or the product's featured image
+	ImageURLs       []string // all product images
 	IsActive        bool
+	// Version counts the changes to what the SKU maps to, see SKUMappingChange
+	Version         int
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -81,3 +175,187 @@ type OrderEvent struct {
 	EventData       map[string]interface{} // JSONB
 	CreatedAt       time.Time
 }
+
+// Organization groups partners of one merchant (e.g. one API key per store branch) so
+// they can see each other's orders and reports
+type Organization struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PartnerInvitation is a one-time onboarding token issued by an admin
+type PartnerInvitation struct {
+	ID          uuid.UUID
+	PartnerName string
+	TokenHash   string
+	CreatedBy   string
+	ExpiresAt   time.Time
+	AcceptedAt  *time.Time
+	PartnerID   *uuid.UUID
+	CreatedAt   time.Time
+}
+
+// AuditLog records an administrative or self-service action
+type AuditLog struct {
+	ID           uuid.UUID
+	Actor        string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Data         map[string]interface{} // JSONB
+	CreatedAt    time.Time
+}
+
+// WebhookDelivery records one attempt to notify a partner webhook
+type WebhookDelivery struct {
+	ID              uuid.UUID
+	PartnerID       uuid.UUID
+	SupplierOrderID *uuid.UUID
+	EventType       string
+	URL             string
+	Payload         map[string]interface{} // JSONB
+	ResponseStatus  *int
+	Error           *string
+	Success         bool
+	DurationMs      int
+	CreatedAt       time.Time
+	// RetryOf is the original delivery of a retry sent on the partner's request
+	RetryOf *uuid.UUID
+}
+
+// WebhookDeliveryHistory is a delivery (its first attempt) with the outcome of its retries
+type WebhookDeliveryHistory struct {
+	WebhookDelivery
+	Attempts int
+	// Delivered is set when any attempt succeeded
+	Delivered bool
+	// LastAttempt holds the outcome of the latest attempt: response status, error, success,
+	// duration and time
+	LastAttempt WebhookDelivery
+}
+
+// WebhookDeliveryFilter narrows a partner's delivery history
+type WebhookDeliveryFilter struct {
+	EventType string
+	Delivered *bool
+}
+
+// DigestSubscription is a partner's opt-in to the daily email digest
+type DigestSubscription struct {
+	PartnerID  uuid.UUID
+	Email      string
+	SendTime   string // HH:MM in the digest time zone
+	LastSentAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// OrderStatusChange is one status_change event with the order it belongs to
+type OrderStatusChange struct {
+	OrderID        uuid.UUID
+	PartnerOrderID string
+	Reference      *string
+	From           OrderStatus
+	To             OrderStatus
+	ChangedAt      time.Time
+}
+
+// APIRequestLog records one authenticated partner API request
+type APIRequestLog struct {
+	ID         uuid.UUID
+	PartnerID  uuid.UUID
+	Method     string
+	Route      string
+	Status     int
+	DurationMs int
+	CreatedAt  time.Time
+}
+
+// APIUsageStats aggregates a partner's API requests over a period
+type APIUsageStats struct {
+	TotalRequests int
+	ClientErrors  int // 4xx responses
+	ServerErrors  int // 5xx responses
+	P95LatencyMs  float64
+}
+
+// WebhookDeliveryStats aggregates a partner's webhook deliveries over a period
+type WebhookDeliveryStats struct {
+	Total     int
+	Succeeded int
+}
+
+// OrderFunnelStats counts a partner's orders created in a period by how far they got.
+// A stage counts every order that reached it, including orders that moved past it.
+type OrderFunnelStats struct {
+	PartnerID   uuid.UUID
+	PartnerName string
+	Submitted   int
+	Confirmed   int
+	Shipped     int
+	Delivered   int
+	Rejected    int
+	Cancelled   int
+	// Revenue is the cart total of the orders that were not rejected or cancelled
+	Revenue float64
+}
+
+// NonSupplierSKU is a cart SKU that was stored as a non-supplier item
+type NonSupplierSKU struct {
+	SKU        string
+	Orders     int
+	LastSeenAt time.Time
+}
+
+// OrderedVariant is a Shopify variant a partner ordered, with when they last did
+type OrderedVariant struct {
+	PartnerID        uuid.UUID
+	SKU              string
+	ShopifyVariantID int64
+	LastOrderedAt    time.Time
+}
+
+// LowStockAlert records that a partner was told a variant they order is running low
+type LowStockAlert struct {
+	PartnerID        uuid.UUID
+	ShopifyVariantID int64
+	SKU              string
+	Quantity         int
+	AlertedAt        time.Time
+}
+
+// UnmatchedSKU is a partner's cart SKU that did not match a supplier SKU, with how often it was sent
+type UnmatchedSKU struct {
+	PartnerID   uuid.UUID
+	SKU         string
+	Title       string
+	Reason      string
+	Occurrences int
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+// UnmatchedSKUFilter narrows the unmatched SKU report; zero values do not filter
+type UnmatchedSKUFilter struct {
+	PartnerID *uuid.UUID
+	Reason    string
+	From      time.Time // last seen at or after
+	To        time.Time // last seen before
+	Limit     int
+}
+
+// RejectionReasonCount is the number of a partner's orders rejected with one reason
+type RejectionReasonCount struct {
+	PartnerID uuid.UUID
+	Reason    string
+	Count     int
+}
+
+// RejectionCodeCount is how many of a partner's orders were rejected with a code
+type RejectionCodeCount struct {
+	PartnerID uuid.UUID
+	Code      RejectionCode
+	Count     int
+}
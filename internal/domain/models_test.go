@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPendingSinceSkipsTimeOnHold(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	order := &SupplierOrder{CreatedAt: createdAt}
+	if got := order.PendingSince(); !got.Equal(createdAt) {
+		t.Errorf("PendingSince() of an order never held = %v, want %v", got, createdAt)
+	}
+	order.HeldSeconds = 90 * 60
+	if got, want := order.PendingSince(), createdAt.Add(90*time.Minute); !got.Equal(want) {
+		t.Errorf("PendingSince() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+// Dimensions are the outer dimensions of one unit in centimetres
+type Dimensions struct {
+	LengthCm float64
+	WidthCm  float64
+	HeightCm float64
+}
+
+// Volume returns the volume in cubic centimetres
+func (d Dimensions) Volume() float64 {
+	return d.LengthCm * d.WidthCm * d.HeightCm
+}
+
+// Parcel is the shipping weight and size of an order's items, for rate calculation
+type Parcel struct {
+	WeightGrams int
+	// VolumeCm3 is the summed volume of the items that have dimensions
+	VolumeCm3 float64
+	// LargestItem is the item with the largest volume, nil when no item has dimensions
+	LargestItem *Dimensions
+	Units       int
+	// UnitsWithoutWeight / UnitsWithoutDimensions count units the totals are missing
+	UnitsWithoutWeight     int
+	UnitsWithoutDimensions int
+}
+
+// Complete reports whether every unit has a weight and dimensions
+func (p Parcel) Complete() bool {
+	return p.UnitsWithoutWeight == 0 && p.UnitsWithoutDimensions == 0
+}
+
+// ParcelFor aggregates the weight and dimensions of the items, multiplied by quantity
+func ParcelFor(items []*SupplierOrderItem) Parcel {
+	var parcel Parcel
+	for _, item := range items {
+		parcel.Units += item.Quantity
+		if item.WeightGrams != nil {
+			parcel.WeightGrams += *item.WeightGrams * item.Quantity
+		} else {
+			parcel.UnitsWithoutWeight += item.Quantity
+		}
+		if item.Dimensions != nil {
+			parcel.VolumeCm3 += item.Dimensions.Volume() * float64(item.Quantity)
+			if parcel.LargestItem == nil || item.Dimensions.Volume() > parcel.LargestItem.Volume() {
+				largest := *item.Dimensions
+				parcel.LargestItem = &largest
+			}
+		} else {
+			parcel.UnitsWithoutDimensions += item.Quantity
+		}
+	}
+	return parcel
+}
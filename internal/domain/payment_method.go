@@ -0,0 +1,67 @@
+package domain
+
+import "strings"
+
+// PaymentMethod is how the customer pays for an order
+type PaymentMethod string
+
+const (
+	// PaymentMethodCOD is collected by the courier on delivery, in cash or by card
+	PaymentMethodCOD PaymentMethod = "cod"
+	// PaymentMethodPrepaid is paid before shipping (card, bank transfer, wallets such as ZainCash)
+	PaymentMethodPrepaid PaymentMethod = "prepaid"
+	// PaymentMethodCredit is charged to the partner's credit account
+	PaymentMethodCredit PaymentMethod = "credit"
+)
+
+// PaymentMethods lists every payment method
+var PaymentMethods = []PaymentMethod{
+	PaymentMethodCOD,
+	PaymentMethodPrepaid,
+	PaymentMethodCredit,
+}
+
+// paymentMethodLabels are the free-text values partners sent before payment methods
+// were codes, as documented in the partner guide
+var paymentMethodLabels = map[string]PaymentMethod{
+	"cash on delivery":       PaymentMethodCOD,
+	"cash on delivery (cod)": PaymentMethodCOD,
+	"card on delivery":       PaymentMethodCOD,
+	"credit card":            PaymentMethodPrepaid,
+	"zaincash":               PaymentMethodPrepaid,
+}
+
+// IsValid reports whether m is a known payment method
+func (m PaymentMethod) IsValid() bool {
+	for _, method := range PaymentMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Label is the payment method as shown to the shop's staff
+func (m PaymentMethod) Label() string {
+	switch m {
+	case PaymentMethodCOD:
+		return "Cash on delivery"
+	case PaymentMethodPrepaid:
+		return "Prepaid"
+	case PaymentMethodCredit:
+		return "Partner credit"
+	}
+	return string(m)
+}
+
+// ParsePaymentMethod maps a payment method code in any case, or one of the labels used
+// before codes existed (e.g. "Cash On Delivery (COD)"), to a payment method, returning
+// false when it is unknown
+func ParsePaymentMethod(value string) (PaymentMethod, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if method, ok := paymentMethodLabels[value]; ok {
+		return method, true
+	}
+	m := PaymentMethod(value)
+	return m, m.IsValid()
+}
@@ -0,0 +1,24 @@
+package domain
+
+// PaymentTerms decide when a partner's Shopify draft orders are completed
+type PaymentTerms string
+
+const (
+	// PaymentTermsPrepaid completes the draft order as soon as the cart is submitted, or on
+	// confirmation for partners with substitutions
+	PaymentTermsPrepaid PaymentTerms = "prepaid"
+	// PaymentTermsInvoice sends the draft order as a Shopify invoice and completes it
+	// only when payment is recorded
+	PaymentTermsInvoice PaymentTerms = "invoice"
+)
+
+// IsValid reports whether t is a supported payment term
+func (t PaymentTerms) IsValid() bool {
+	return t == PaymentTermsPrepaid || t == PaymentTermsInvoice
+}
+
+// Payment statuses set by the API (partners may send their own on submission)
+const (
+	PaymentStatusInvoiced = "invoiced"
+	PaymentStatusPaid     = "paid"
+)
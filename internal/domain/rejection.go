@@ -0,0 +1,32 @@
+package domain
+
+// RejectionCode classifies why an order was rejected; free text goes with it as the reason
+type RejectionCode string
+
+const (
+	RejectionCodeOutOfStock     RejectionCode = "OUT_OF_STOCK"
+	RejectionCodePriceChanged   RejectionCode = "PRICE_CHANGED"
+	RejectionCodeInvalidAddress RejectionCode = "INVALID_ADDRESS"
+	RejectionCodeFraudSuspected RejectionCode = "FRAUD_SUSPECTED"
+	// RejectionCodeOther needs a reason; orders rejected before codes existed have it too
+	RejectionCodeOther RejectionCode = "OTHER"
+)
+
+// RejectionCodes lists every rejection code
+var RejectionCodes = []RejectionCode{
+	RejectionCodeOutOfStock,
+	RejectionCodePriceChanged,
+	RejectionCodeInvalidAddress,
+	RejectionCodeFraudSuspected,
+	RejectionCodeOther,
+}
+
+// IsValid reports whether c is a known rejection code
+func (c RejectionCode) IsValid() bool {
+	for _, code := range RejectionCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportDimension is what a custom report groups orders by
+type ReportDimension string
+
+const (
+	ReportDimensionPartner ReportDimension = "partner"
+	ReportDimensionStatus  ReportDimension = "status"
+	// ReportDimensionDay groups by the UTC day the order was created
+	ReportDimensionDay ReportDimension = "day"
+)
+
+// ReportDimensions lists every report dimension
+var ReportDimensions = []ReportDimension{
+	ReportDimensionPartner,
+	ReportDimensionStatus,
+	ReportDimensionDay,
+}
+
+// ReportMetric is a number computed per group of a custom report
+type ReportMetric string
+
+const (
+	// ReportMetricCount is the number of orders
+	ReportMetricCount ReportMetric = "count"
+	// ReportMetricRevenue is the cart total of the orders not rejected or cancelled
+	ReportMetricRevenue ReportMetric = "revenue"
+	// ReportMetricAvgTimeToShip is the average time from submission to shipping, in hours,
+	// of the orders that shipped
+	ReportMetricAvgTimeToShip ReportMetric = "avg_time_to_ship"
+)
+
+// ReportMetrics lists every report metric
+var ReportMetrics = []ReportMetric{
+	ReportMetricCount,
+	ReportMetricRevenue,
+	ReportMetricAvgTimeToShip,
+}
+
+// ReportQuery describes a custom report: the orders matching Filter, grouped by GroupBy
+// (all of them in one row when empty), with Metrics per group
+type ReportQuery struct {
+	GroupBy []ReportDimension
+	Metrics []ReportMetric
+	Filter  OrderFilter
+	// Limit is the most rows returned
+	Limit int
+}
+
+// ReportRow is one group of a custom report; fields of dimensions and metrics that were
+// not asked for are nil
+type ReportRow struct {
+	PartnerID   *uuid.UUID
+	PartnerName *string
+	Status      *OrderStatus
+	Day         *time.Time
+	Count       *int64
+	Revenue     *float64
+	// AvgTimeToShip is nil when no order of the group shipped
+	AvgTimeToShip *float64
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewAssignment is the staff member working an order of the review queue
+type ReviewAssignment struct {
+	SupplierOrderID uuid.UUID
+	AssigneeID      uuid.UUID
+	// AssignedBy is the assignee for claims, the admin who handed the order over otherwise
+	AssignedBy uuid.UUID
+	AssignedAt time.Time
+}
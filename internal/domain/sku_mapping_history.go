@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources of SKU mapping changes. Changes made outside the API (e.g. manual SQL) are
+// recorded with source "sql" and the database user as actor.
+const (
+	SKUMappingSourceShopifySync = "shopify_sync"
+	SKUMappingSourceCLI         = "cli"
+	SKUMappingSourceSeed        = "seed"
+)
+
+// SKUMappingActor is who changes SKU mappings, as recorded in their history
+type SKUMappingActor struct {
+	Source string
+	// ChangedBy names the person or process; the database user when empty
+	ChangedBy string
+}
+
+// SKUMappingChangeAction is what happened to a SKU mapping
+type SKUMappingChangeAction string
+
+const (
+	SKUMappingChangeCreated SKUMappingChangeAction = "created"
+	SKUMappingChangeUpdated SKUMappingChangeAction = "updated"
+	SKUMappingChangeDeleted SKUMappingChangeAction = "deleted"
+)
+
+// SKUMappingChange is a version of a SKU mapping: what it mapped to before and after a
+// change. Previous fields are nil for a created mapping, the others for a deleted one.
+type SKUMappingChange struct {
+	ID                       uuid.UUID
+	SKUMappingID             uuid.UUID
+	SKU                      string
+	Version                  int
+	Action                   SKUMappingChangeAction
+	PreviousNormalizedSKU    *string
+	NormalizedSKU            *string
+	PreviousShopifyProductID *int64
+	ShopifyProductID         *int64
+	PreviousShopifyVariantID *int64
+	ShopifyVariantID         *int64
+	PreviousIsActive         *bool
+	IsActive                 *bool
+	Source                   string
+	ChangedBy                string
+	ChangedAt                time.Time
+}
+
+// SKUMappingChangeFilter narrows a SKU mapping history listing
+type SKUMappingChangeFilter struct {
+	SKU string
+	// VariantID matches changes to or from the Shopify variant
+	VariantID *int64
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubstitutionStatus is where a proposed item substitution stands
+type SubstitutionStatus string
+
+const (
+	SubstitutionStatusProposed SubstitutionStatus = "PROPOSED"
+	SubstitutionStatusAccepted SubstitutionStatus = "ACCEPTED"
+	SubstitutionStatusDeclined SubstitutionStatus = "DECLINED"
+)
+
+// ItemSubstitution is an alternative SKU an admin proposes for an order item that cannot be
+// supplied. The partner accepts it, replacing the item, or declines it.
+type ItemSubstitution struct {
+	ID                  uuid.UUID
+	SupplierOrderID     uuid.UUID
+	SupplierOrderItemID uuid.UUID
+	OriginalSKU         string
+	OriginalPrice       float64
+	SKU                 string
+	Title               string
+	ShopifyVariantID    int64
+	Price               float64 // unit price of the substitute
+	Note                *string
+	Status              SubstitutionStatus
+	DeclineReason       *string
+	CreatedAt           time.Time
+	RespondedAt         *time.Time
+}
@@ -0,0 +1,54 @@
+package errortracking
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jafarshop/b2bapi/pkg/apikey"
+	"github.com/jafarshop/b2bapi/pkg/sentry"
+)
+
+// leakedKeyTag lists the prefixes of API keys found in a report, so leaks can be searched for
+const leakedKeyTag = "leaked_api_key"
+
+// redactAPIKeys replaces partner API keys (see pkg/apikey) in the report's message, exceptions,
+// tags and string extras with their prefix, tags the report with the prefixes and returns them.
+// Keys end up in reports through upstream error messages, echoed requests and the like; they
+// must not be stored at Sentry, and the partner should be given a new key.
+func redactAPIKeys(event *sentry.Event) []string {
+	found := make(map[string]bool)
+	redact := func(text string) string {
+		redacted, prefixes := apikey.Redact(text)
+		for _, prefix := range prefixes {
+			found[prefix.String()] = true
+		}
+		return redacted
+	}
+
+	event.Message = redact(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = redact(event.Exception[i].Value)
+	}
+	for key, value := range event.Tags {
+		event.Tags[key] = redact(value)
+	}
+	for key, value := range event.Extra {
+		if text, ok := value.(string); ok {
+			event.Extra[key] = redact(text)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+	prefixes := make([]string, 0, len(found))
+	for prefix := range found {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	if event.Tags == nil {
+		event.Tags = make(map[string]string)
+	}
+	event.Tags[leakedKeyTag] = strings.Join(prefixes, ",")
+	return prefixes
+}
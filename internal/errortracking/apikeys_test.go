@@ -0,0 +1,36 @@
+package errortracking
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jafarshop/b2bapi/pkg/apikey"
+	"github.com/jafarshop/b2bapi/pkg/sentry"
+)
+
+func TestRedactAPIKeys(t *testing.T) {
+	key, err := apikey.Generate("zain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &sentry.Event{
+		Message:   "POST /v1/carts: upstream rejected Bearer " + key,
+		Exception: []sentry.Exception{{Type: "*errors.errorString", Value: "auth " + key}},
+		Extra:     map[string]interface{}{"body": `{"api_key":"` + key + `"}`, "attempt": int64(2)},
+	}
+
+	prefixes := redactAPIKeys(event)
+
+	if len(prefixes) != 1 || prefixes[0] != "b2b_zain_v2" || event.Tags[leakedKeyTag] != "b2b_zain_v2" {
+		t.Errorf("prefixes = %v, tags = %v, want b2b_zain_v2", prefixes, event.Tags)
+	}
+	for _, text := range []string{event.Message, event.Exception[0].Value, event.Extra["body"].(string)} {
+		if strings.Contains(text, key) || !strings.Contains(text, "b2b_zain_v2_[REDACTED]") {
+			t.Errorf("%q, want the key redacted", text)
+		}
+	}
+
+	if prefixes := redactAPIKeys(&sentry.Event{Message: "zain-api-key-12345 rejected"}); prefixes != nil {
+		t.Errorf("prefixes = %v for a report without keys", prefixes)
+	}
+}
@@ -0,0 +1,86 @@
+// Package errortracking reports panics and errors to Sentry (or a compatible service) when
+// SENTRY_DSN is set. A nil or disabled Tracker does nothing, so callers need not check.
+package errortracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/pkg/sentry"
+)
+
+// sendTimeout bounds sending one report
+const sendTimeout = 10 * time.Second
+
+// Tracker sends reports in the background so requests never wait for Sentry
+type Tracker struct {
+	client *sentry.Client
+	logger *zap.Logger
+}
+
+// New creates a tracker; without a DSN it is disabled
+func New(cfg config.SentryConfig, logger *zap.Logger) *Tracker {
+	tracker := &Tracker{logger: logger}
+	if cfg.DSN == "" {
+		return tracker
+	}
+
+	client, err := sentry.New(cfg.DSN, sentry.Options{Environment: cfg.Environment, Release: cfg.Release})
+	if err != nil {
+		logger.Error("Error reporting disabled", zap.Error(err))
+		return tracker
+	}
+	tracker.client = client
+	return tracker
+}
+
+// Enabled reports whether reports are sent
+func (t *Tracker) Enabled() bool {
+	return t != nil && t.client != nil
+}
+
+// CapturePanic reports a recovered panic with the stack it was raised on
+func (t *Tracker) CapturePanic(value interface{}, stack []byte, tags map[string]string) {
+	if !t.Enabled() {
+		return
+	}
+	t.send(&sentry.Event{
+		Level:     sentry.LevelFatal,
+		Exception: []sentry.Exception{{Type: "panic", Value: fmt.Sprint(value)}},
+		Tags:      tags,
+		Extra:     map[string]interface{}{"stack": string(stack)},
+	})
+}
+
+// CaptureError reports an error that did not panic. message is the title of the report, err
+// (optional) the error behind it.
+func (t *Tracker) CaptureError(message string, err error, tags map[string]string) {
+	if !t.Enabled() {
+		return
+	}
+	event := &sentry.Event{Message: message, Tags: tags}
+	if err != nil {
+		event.Exception = []sentry.Exception{{Type: fmt.Sprintf("%T", err), Value: err.Error()}}
+	}
+	t.send(event)
+}
+
+func (t *Tracker) send(event *sentry.Event) {
+	if prefixes := redactAPIKeys(event); len(prefixes) > 0 {
+		t.logger.Warn("API key found in error report, issue the partner a new key",
+			zap.Strings("key_prefixes", prefixes),
+		)
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		if _, err := t.client.Capture(ctx, event); err != nil {
+			t.logger.Warn("Failed to report error to Sentry", zap.Error(err))
+		}
+	}()
+}
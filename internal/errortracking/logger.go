@@ -0,0 +1,111 @@
+package errortracking
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/jafarshop/b2bapi/pkg/sentry"
+)
+
+// tagFields are the log fields reported as tags, so reports can be searched by them
+var tagFields = []string{"partner_id", "order_id", "operation"}
+
+// reportedKey marks log entries whose error was already reported
+const reportedKey = "error_reported"
+
+// Reported marks a log entry as already reported, e.g. with CapturePanic, so Wrap does not
+// report it a second time
+func Reported() zap.Field {
+	return zap.Bool(reportedKey, true)
+}
+
+// Wrap returns a logger that also reports every entry at error level or above. The message
+// is the title of the report, the "error" field its exception, partner_id, order_id and
+// operation its tags (operation defaults to the logger name) and the other fields its extra
+// data. Without a DSN the logger is returned as is.
+func (t *Tracker) Wrap(logger *zap.Logger) *zap.Logger {
+	if !t.Enabled() {
+		return logger
+	}
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, &reportingCore{tracker: t})
+	}))
+}
+
+// reportingCore is the zapcore.Core behind Wrap
+type reportingCore struct {
+	tracker *Tracker
+	fields  []zapcore.Field
+}
+
+func (c *reportingCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+func (c *reportingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reportingCore{
+		tracker: c.tracker,
+		fields:  append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *reportingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *reportingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	if event, ok := logEvent(entry, all); ok {
+		c.tracker.send(event)
+	}
+	return nil
+}
+
+func (c *reportingCore) Sync() error {
+	return nil
+}
+
+// logEvent turns a log entry into a report; ok is false for entries marked Reported
+func logEvent(entry zapcore.Entry, fields []zapcore.Field) (*sentry.Event, bool) {
+	event := &sentry.Event{
+		Timestamp: entry.Time,
+		Level:     sentry.LevelError,
+		Logger:    entry.LoggerName,
+		Message:   entry.Message,
+		Tags:      make(map[string]string),
+	}
+	if entry.Level > zapcore.ErrorLevel {
+		event.Level = sentry.LevelFatal
+	}
+
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		if field.Key == reportedKey {
+			return nil, false
+		}
+		if err, ok := field.Interface.(error); ok && field.Type == zapcore.ErrorType {
+			event.Exception = append(event.Exception, sentry.Exception{Type: fmt.Sprintf("%T", err), Value: err.Error()})
+			continue
+		}
+		field.AddTo(encoder)
+	}
+
+	for _, key := range tagFields {
+		if value, ok := encoder.Fields[key]; ok {
+			event.Tags[key] = fmt.Sprint(value)
+			delete(encoder.Fields, key)
+		}
+	}
+	if _, ok := event.Tags["operation"]; !ok && entry.LoggerName != "" {
+		event.Tags["operation"] = entry.LoggerName
+	}
+	if len(encoder.Fields) > 0 {
+		event.Extra = encoder.Fields
+	}
+	return event, true
+}
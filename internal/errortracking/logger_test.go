@@ -0,0 +1,45 @@
+package errortracking
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/jafarshop/b2bapi/pkg/sentry"
+)
+
+func TestLogEvent(t *testing.T) {
+	entry := zapcore.Entry{Level: zapcore.ErrorLevel, LoggerName: "total_check", Message: "Total check failed"}
+	fields := []zapcore.Field{
+		zap.String("order_id", "o1"),
+		zap.String("partner_id", "p1"),
+		zap.Int("attempt", 3),
+		zap.Error(errors.New("connection refused")),
+	}
+
+	event, ok := logEvent(entry, fields)
+	if !ok {
+		t.Fatal("logEvent() ok = false, want true")
+	}
+	if event.Message != "Total check failed" || event.Level != sentry.LevelError {
+		t.Errorf("event = %+v, want the message at error level", event)
+	}
+	want := map[string]string{"order_id": "o1", "partner_id": "p1", "operation": "total_check"}
+	for key, value := range want {
+		if event.Tags[key] != value {
+			t.Errorf("tag %s = %q, want %q", key, event.Tags[key], value)
+		}
+	}
+	if len(event.Exception) != 1 || event.Exception[0].Value != "connection refused" {
+		t.Errorf("exception = %+v, want the error field", event.Exception)
+	}
+	if event.Extra["attempt"] != int64(3) || event.Extra["order_id"] != nil {
+		t.Errorf("extra = %v, want the fields that are not tags", event.Extra)
+	}
+
+	if _, ok := logEvent(entry, append(fields, Reported())); ok {
+		t.Error("logEvent() ok = true for an entry already reported, want false")
+	}
+}
@@ -0,0 +1,192 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// FeedChannel is the Postgres NOTIFY channel order events are announced on
+// (see the notify_order_events trigger)
+const FeedChannel = "order_events"
+
+// Feed message types
+const (
+	FeedOrderCreated       = "order.created"
+	FeedOrderStatusChanged = "order.status_changed"
+	// FeedResync tells subscribers notifications may have been missed (e.g. after the
+	// database connection dropped) and they should reload what they show
+	FeedResync = "resync"
+)
+
+// feedBuffer is how many messages a subscriber can lag behind before it is dropped
+const feedBuffer = 64
+
+// FeedMessage is one message of the live order feed
+type FeedMessage struct {
+	Type string
+	Data map[string]interface{}
+}
+
+// feedNotification is the NOTIFY payload sent by the notify_order_events trigger
+type feedNotification struct {
+	ID              string  `json:"id"`
+	SupplierOrderID string  `json:"supplier_order_id"`
+	EventType       string  `json:"event_type"`
+	From            *string `json:"from"`
+	To              *string `json:"to"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// Feed fans order events out to the admin order stream. Every instance listens on the
+// database itself, so a subscriber sees events written by any instance.
+type Feed struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+
+	mu          sync.Mutex
+	subscribers map[chan FeedMessage]struct{}
+}
+
+// NewFeed creates the live order feed
+func NewFeed(repos *repository.Repositories, logger *zap.Logger) *Feed {
+	return &Feed{
+		repos:       repos,
+		logger:      logger,
+		subscribers: make(map[chan FeedMessage]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber. The channel is closed when the subscriber falls too
+// far behind; call the returned function to unsubscribe.
+func (f *Feed) Subscribe() (<-chan FeedMessage, func()) {
+	ch := make(chan FeedMessage, feedBuffer)
+
+	f.mu.Lock()
+	f.subscribers[ch] = struct{}{}
+	f.mu.Unlock()
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subscribers[ch]; ok {
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Run listens for order event notifications until ctx is cancelled
+func (f *Feed) Run(ctx context.Context, listener *pq.Listener) {
+	defer listener.Close()
+
+	if err := listener.Listen(FeedChannel); err != nil {
+		f.logger.Error("Failed to listen for order events", zap.Error(err))
+		return
+	}
+	f.logger.Info("Order feed started", zap.String("channel", FeedChannel))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				// Reconnected; anything sent in between is lost
+				f.broadcast(FeedMessage{Type: FeedResync, Data: map[string]interface{}{}})
+				continue
+			}
+			f.handle(ctx, n.Extra)
+		case <-time.After(90 * time.Second):
+			// Check the connection is still alive; a dead one is reconnected by the listener
+			go func() {
+				_ = listener.Ping()
+			}()
+		}
+	}
+}
+
+func (f *Feed) handle(ctx context.Context, payload string) {
+	if !f.hasSubscribers() {
+		return
+	}
+
+	var n feedNotification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		f.logger.Warn("Invalid order event notification", zap.Error(err))
+		return
+	}
+
+	var msgType string
+	switch n.EventType {
+	case "order_created":
+		msgType = FeedOrderCreated
+	case "status_change":
+		msgType = FeedOrderStatusChanged
+	default:
+		return
+	}
+
+	orderID, err := uuid.Parse(n.SupplierOrderID)
+	if err != nil {
+		f.logger.Warn("Invalid order event notification", zap.String("supplier_order_id", n.SupplierOrderID))
+		return
+	}
+	order, err := f.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		f.logger.Warn("Failed to get order for order feed", zap.String("order_id", n.SupplierOrderID), zap.Error(err))
+		return
+	}
+
+	status := string(order.Status)
+	if n.To != nil {
+		// The order may have moved on already; report the transition this event is about
+		status = *n.To
+	}
+
+	f.broadcast(FeedMessage{
+		Type: msgType,
+		Data: map[string]interface{}{
+			"event_id":         n.ID,
+			"order_id":         order.ID.String(),
+			"partner_id":       order.PartnerID.String(),
+			"partner_order_id": order.PartnerOrderID,
+			"reference":        order.Reference,
+			"status":           status,
+			"previous_status":  n.From,
+			"customer_name":    order.CustomerName,
+			"cart_total":       order.CartTotal,
+			"created_at":       order.CreatedAt.UTC().Format(time.RFC3339),
+			"occurred_at":      n.CreatedAt,
+		},
+	})
+}
+
+func (f *Feed) hasSubscribers() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers) > 0
+}
+
+func (f *Feed) broadcast(msg FeedMessage) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- msg:
+		default:
+			// Too slow to keep up; drop it rather than hold up everyone else
+			delete(f.subscribers, ch)
+			close(ch)
+			f.logger.Warn("Dropped slow order feed subscriber")
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type kafkaPublisher struct {
+	writer *kafka.Writer
+	logger *zap.Logger
+}
+
+func newKafkaPublisher(cfg config.EventsConfig, logger *zap.Logger) (*kafkaPublisher, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("EVENTS_BROKERS is required for the kafka driver")
+	}
+
+	return &kafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// Publish writes one message at a time and waits for it; with the default
+			// 1s batch timeout every order event write would wait a second for a batch
+			BatchTimeout: 10 * time.Millisecond,
+		},
+		logger: logger,
+	}, nil
+}
+
+// Publish writes the event keyed by supplier order ID so all events of an
+// order land on the same partition and keep their order.
+func (p *kafkaPublisher) Publish(ctx context.Context, event *domain.OrderEvent) error {
+	payload, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(event.SupplierOrderID.String()),
+		Value: payload,
+	}); err != nil {
+		return fmt.Errorf("failed to publish event to kafka: %w", err)
+	}
+
+	return nil
+}
+
+func (p *kafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type natsPublisher struct {
+	conn    *nats.Conn
+	subject string
+	logger  *zap.Logger
+}
+
+func newNATSPublisher(cfg config.EventsConfig, logger *zap.Logger) (*natsPublisher, error) {
+	url := nats.DefaultURL
+	if len(cfg.Brokers) > 0 {
+		url = strings.Join(cfg.Brokers, ",")
+	}
+
+	conn, err := nats.Connect(url, nats.Name("b2bapi"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+
+	return &natsPublisher{
+		conn:    conn,
+		subject: cfg.Topic,
+		logger:  logger,
+	}, nil
+}
+
+// Publish sends the event on "<topic>.<event_type>" so subscribers can
+// filter by type (e.g. "b2b.order_events.status_change") or take all (">").
+func (p *natsPublisher) Publish(ctx context.Context, event *domain.OrderEvent) error {
+	payload, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := p.conn.Publish(p.subject+"."+event.EventType, payload); err != nil {
+		return fmt.Errorf("failed to publish event to nats: %w", err)
+	}
+
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// SchemaVersion is the version of the Envelope JSON schema.
+// Bump it only for breaking changes; adding fields is not breaking.
+const SchemaVersion = 1
+
+// Supported broker drivers
+const (
+	DriverNone  = "none"
+	DriverKafka = "kafka"
+	DriverNATS  = "nats"
+)
+
+// Envelope is the stable JSON message published for every OrderEvent
+type Envelope struct {
+	SchemaVersion   int                    `json:"schema_version"`
+	ID              string                 `json:"id"`
+	SupplierOrderID string                 `json:"supplier_order_id"`
+	EventType       string                 `json:"event_type"`
+	Data            map[string]interface{} `json:"data"`
+	OccurredAt      string                 `json:"occurred_at"`
+}
+
+// NewEnvelope builds the published message for an order event
+func NewEnvelope(event *domain.OrderEvent) Envelope {
+	data := event.EventData
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return Envelope{
+		SchemaVersion:   SchemaVersion,
+		ID:              event.ID.String(),
+		SupplierOrderID: event.SupplierOrderID.String(),
+		EventType:       event.EventType,
+		Data:            data,
+		OccurredAt:      event.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// Publisher emits order events to a message broker
+type Publisher interface {
+	Publish(ctx context.Context, event *domain.OrderEvent) error
+	Close() error
+}
+
+// NewPublisher creates the publisher for the configured driver
+func NewPublisher(cfg config.EventsConfig, logger *zap.Logger) (Publisher, error) {
+	switch cfg.Driver {
+	case "", DriverNone:
+		return noopPublisher{}, nil
+	case DriverKafka:
+		return newKafkaPublisher(cfg, logger)
+	case DriverNATS:
+		return newNATSPublisher(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unsupported events driver: %s", cfg.Driver)
+	}
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(ctx context.Context, event *domain.OrderEvent) error { return nil }
+func (noopPublisher) Close() error                                                { return nil }
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// publishTimeout bounds how long a broker outage can slow down a request
+const publishTimeout = 5 * time.Second
+
+type publishingOrderEventRepository struct {
+	repository.OrderEventRepository
+	publisher Publisher
+	logger    *zap.Logger
+}
+
+// NewPublishingOrderEventRepository wraps an OrderEventRepository so every event
+// that is persisted is also emitted to the broker. Publishing failures are logged
+// and never fail the write - the order_events table stays the source of truth.
+func NewPublishingOrderEventRepository(
+	inner repository.OrderEventRepository,
+	publisher Publisher,
+	logger *zap.Logger,
+) repository.OrderEventRepository {
+	return &publishingOrderEventRepository{
+		OrderEventRepository: inner,
+		publisher:            publisher,
+		logger:               logger,
+	}
+}
+
+func (r *publishingOrderEventRepository) Create(ctx context.Context, event *domain.OrderEvent) error {
+	if err := r.OrderEventRepository.Create(ctx, event); err != nil {
+		return err
+	}
+
+	publishCtx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if err := r.publisher.Publish(publishCtx, event); err != nil {
+		r.logger.Warn("Failed to publish order event",
+			zap.String("event_id", event.ID.String()),
+			zap.String("event_type", event.EventType),
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
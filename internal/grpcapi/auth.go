@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"math"
+	"net"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/jafarshop/b2bapi/internal/authguard"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type partnerContextKey struct{}
+
+// authInterceptor authenticates calls using the same partner API keys as the REST API.
+// Clients send the key as "authorization: Bearer <api-key>" metadata. Failures count towards
+// the same lockouts as REST requests.
+func authInterceptor(repos *repository.Repositories, guard *authguard.Guard, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+
+		// Extract Bearer token
+		parts := strings.SplitN(values[0], " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+		}
+
+		apiKey := parts[1]
+		ip := peerIP(ctx)
+		if wait := guard.Check(ip, apiKey); wait > 0 {
+			return nil, status.Errorf(codes.ResourceExhausted, "too many failed authentication attempts, retry in %ds", int(math.Ceil(wait.Seconds())))
+		}
+
+		partner, err := repos.Partner.GetByAPIKeyHash(ctx, apiKey)
+		if err != nil {
+			if _, ok := err.(*errors.ErrUnauthorized); ok {
+				guard.Failure(ip, apiKey)
+			}
+			logger.Warn("Failed to authenticate partner", zap.String("ip", ip), zap.Error(err))
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+
+		revoked, err := repos.RevokedAPIKey.IsRevoked(ctx, partner.APIKeyHash)
+		if err != nil {
+			logger.Error("Failed to check API key revocation", zap.Error(err))
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+		if revoked {
+			guard.Failure(ip, apiKey)
+			return nil, status.Error(codes.Unauthenticated, "API key has been revoked")
+		}
+
+		if !partner.IsActive {
+			return nil, status.Error(codes.Unauthenticated, "partner account is inactive")
+		}
+
+		guard.Success(apiKey)
+
+		return handler(context.WithValue(ctx, partnerContextKey{}, partner), req)
+	}
+}
+
+// peerIP is the caller's IP address, or its full address when that has no port
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// partnerFromContext retrieves the authenticated partner
+func partnerFromContext(ctx context.Context) (*domain.Partner, bool) {
+	partner, ok := ctx.Value(partnerContextKey{}).(*domain.Partner)
+	return partner, ok
+}
@@ -0,0 +1,41 @@
+package grpcapi
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jafarshop/b2bapi/internal/grpcapi/pb"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+// mutatingMethods are the calls rejected during maintenance
+var mutatingMethods = map[string]bool{
+	pb.OrderService_SubmitCart_FullMethodName:   true,
+	pb.OrderService_ConfirmOrder_FullMethodName: true,
+	pb.OrderService_RejectOrder_FullMethodName:  true,
+	pb.OrderService_ShipOrder_FullMethodName:    true,
+}
+
+// maintenanceInterceptor fails mutating calls with Unavailable while maintenance mode is on,
+// like the REST API does with 503. The "retry-after" header metadata holds the seconds to wait.
+func maintenanceInterceptor(maintenance *service.Maintenance) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !mutatingMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		maintenanceStatus := maintenance.Status()
+		if !maintenanceStatus.Enabled {
+			return handler(ctx, req)
+		}
+
+		retryAfter := strconv.Itoa(int(maintenanceStatus.RetryAfter.Seconds()))
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+		return nil, status.Errorf(codes.Unavailable, "temporarily unavailable for maintenance, retry in %ss", retryAfter)
+	}
+}
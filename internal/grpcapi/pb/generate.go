@@ -0,0 +1,4 @@
+// Package pb contains the generated protobuf and gRPC code for proto/b2b/v1.
+package pb
+
+//go:generate protoc -I ../../../proto --go_out=../../.. --go_opt=module=github.com/jafarshop/b2bapi --go-grpc_out=../../.. --go-grpc_opt=module=github.com/jafarshop/b2bapi b2b/v1/orders.proto
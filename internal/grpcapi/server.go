@@ -0,0 +1,375 @@
+package grpcapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jafarshop/b2bapi/internal/authguard"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/grpcapi/pb"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/service"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type orderServer struct {
+	pb.UnimplementedOrderServiceServer
+	cfg      *config.Config
+	repos    *repository.Repositories
+	services *service.Services
+	logger   *zap.Logger
+}
+
+// NewServer creates the gRPC server for internal consumers
+func NewServer(cfg *config.Config, repos *repository.Repositories, services *service.Services, authGuard *authguard.Guard, logger *zap.Logger) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		authInterceptor(repos, authGuard, logger),
+		maintenanceInterceptor(services.Maintenance),
+	))
+	pb.RegisterOrderServiceServer(srv, &orderServer{
+		cfg:      cfg,
+		repos:    repos,
+		services: services,
+		logger:   logger,
+	})
+	return srv
+}
+
+// SubmitCart implements pb.OrderServiceServer
+func (s *orderServer) SubmitCart(ctx context.Context, in *pb.SubmitCartRequest) (*pb.SubmitCartResponse, error) {
+	partner, ok := partnerFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	req := cartRequestFromProto(in)
+	// Reuse the REST binding rules so both APIs accept the same carts
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	order, hasSupplierSKU, err := s.services.Carts.SubmitCart(ctx, partner, req)
+	if err != nil {
+		if e, ok := err.(*errors.ErrValidation); ok {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", e.Error(), e.Fields)
+		}
+		s.logger.Error("Failed to submit cart", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to create order")
+	}
+
+	if !hasSupplierSKU {
+		return &pb.SubmitCartResponse{Created: false}, nil
+	}
+
+	return &pb.SubmitCartResponse{
+		Created:         true,
+		SupplierOrderId: order.ID.String(),
+		Status:          string(order.Status),
+	}, nil
+}
+
+// GetOrder implements pb.OrderServiceServer
+func (s *orderServer) GetOrder(ctx context.Context, in *pb.GetOrderRequest) (*pb.Order, error) {
+	partner, ok := partnerFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	order, err := s.getOrder(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify partner owns this order
+	if order.PartnerID != partner.ID {
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	return s.orderWithItems(ctx, order)
+}
+
+// ListOrders implements pb.OrderServiceServer
+func (s *orderServer) ListOrders(ctx context.Context, in *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	partner, ok := partnerFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	limit := int(in.GetLimit())
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	offset := int(in.GetOffset())
+	if offset < 0 {
+		offset = 0
+	}
+
+	var cursor *domain.OrderCursor
+	if in.GetCursor() != "" {
+		var err error
+		cursor, err = domain.DecodeOrderCursor(in.GetCursor())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		offset = 0
+	}
+
+	var orders []*domain.SupplierOrder
+	var err error
+	if in.GetStatus() != "" {
+		orderStatus := domain.OrderStatus(in.GetStatus())
+		if !orderStatus.IsValid() {
+			return nil, status.Error(codes.InvalidArgument, "invalid status")
+		}
+		if offset > 0 {
+			orders, err = s.repos.SupplierOrder.ListByPartnerIDAndStatus(ctx, partner.ID, orderStatus, limit, offset)
+		} else {
+			orders, err = s.repos.SupplierOrder.ListByPartnerIDAndStatusAfter(ctx, partner.ID, orderStatus, cursor, limit)
+		}
+	} else {
+		if offset > 0 {
+			orders, err = s.repos.SupplierOrder.ListByPartnerID(ctx, partner.ID, limit, offset)
+		} else {
+			orders, err = s.repos.SupplierOrder.ListByPartnerIDAfter(ctx, partner.ID, cursor, limit)
+		}
+	}
+	if err != nil {
+		s.logger.Error("Failed to list orders", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.ListOrdersResponse{
+		Orders: make([]*pb.Order, len(orders)),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}
+	for i, order := range orders {
+		resp.Orders[i] = orderToProto(order)
+	}
+	if len(orders) == limit {
+		resp.NextCursor = domain.CursorAfter(orders[len(orders)-1]).Encode()
+	}
+
+	return resp, nil
+}
+
+// ConfirmOrder implements pb.OrderServiceServer
+// Orders over APPROVAL_THRESHOLD stay PENDING_CONFIRMATION until a second admin confirms them
+func (s *orderServer) ConfirmOrder(ctx context.Context, in *pb.ConfirmOrderRequest) (*pb.Order, error) {
+	admin, ok := partnerFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	orderID, err := parseOrderID(in.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.services.Approvals.Approve(ctx, orderID, admin.ID); err != nil {
+		return nil, s.transitionError("confirm", err)
+	}
+
+	return s.reloadOrder(ctx, orderID)
+}
+
+// RejectOrder implements pb.OrderServiceServer
+func (s *orderServer) RejectOrder(ctx context.Context, in *pb.RejectOrderRequest) (*pb.Order, error) {
+	orderID, err := parseOrderID(in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetReason() == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	if err := s.services.Orders.RejectOrder(ctx, orderID, domain.RejectionCodeOther, in.GetReason()); err != nil {
+		return nil, s.transitionError("reject", err)
+	}
+
+	return s.reloadOrder(ctx, orderID)
+}
+
+// ShipOrder implements pb.OrderServiceServer
+func (s *orderServer) ShipOrder(ctx context.Context, in *pb.ShipOrderRequest) (*pb.Order, error) {
+	orderID, err := parseOrderID(in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetCarrier() == "" || in.GetTrackingNumber() == "" {
+		return nil, status.Error(codes.InvalidArgument, "carrier and tracking_number are required")
+	}
+
+	if err := s.services.Shipping.ShipOrder(ctx, orderID, in.GetCarrier(), in.GetTrackingNumber(), in.TrackingUrl); err != nil {
+		return nil, s.transitionError("ship", err)
+	}
+
+	return s.reloadOrder(ctx, orderID)
+}
+
+func (s *orderServer) getOrder(ctx context.Context, id string) (*domain.SupplierOrder, error) {
+	orderID, err := parseOrderID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		s.logger.Error("Failed to get order", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return order, nil
+}
+
+func (s *orderServer) reloadOrder(ctx context.Context, orderID uuid.UUID) (*pb.Order, error) {
+	order, err := s.getOrder(ctx, orderID.String())
+	if err != nil {
+		return nil, err
+	}
+	return s.orderWithItems(ctx, order)
+}
+
+func (s *orderServer) orderWithItems(ctx context.Context, order *domain.SupplierOrder) (*pb.Order, error) {
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		s.logger.Error("Failed to get order items", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := orderToProto(order)
+	resp.Items = make([]*pb.OrderItem, len(items))
+	for i, item := range items {
+		resp.Items[i] = &pb.OrderItem{
+			Sku:              item.SKU,
+			Title:            item.Title,
+			Price:            item.Price,
+			Quantity:         int32(item.Quantity),
+			ProductUrl:       item.ProductURL,
+			IsSupplierItem:   item.IsSupplierItem,
+			ShopifyVariantId: item.ShopifyVariantID,
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *orderServer) transitionError(action string, err error) error {
+	switch e := err.(type) {
+	case *errors.ErrNotFound:
+		return status.Error(codes.NotFound, "order not found")
+	case *errors.ErrInvalidStateTransition, *errors.ErrConflict:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case *errors.ErrValidation:
+		return status.Errorf(codes.InvalidArgument, "%s: %v", e.Error(), e.Fields)
+	}
+	s.logger.Error("Failed to "+action+" order", zap.Error(err))
+	return status.Errorf(codes.Internal, "failed to %s order", action)
+}
+
+func parseOrderID(id string) (uuid.UUID, error) {
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid order ID")
+	}
+	return orderID, nil
+}
+
+func cartRequestFromProto(in *pb.SubmitCartRequest) service.CartSubmitRequest {
+	req := service.CartSubmitRequest{
+		PartnerOrderID: in.GetPartnerOrderId(),
+		Items:          make([]service.CartItem, len(in.GetItems())),
+		Customer: service.CustomerInfo{
+			Name:  in.GetCustomer().GetName(),
+			Phone: in.GetCustomer().Phone,
+		},
+		Shipping: service.ShippingAddress{
+			Street:     in.GetShipping().GetStreet(),
+			Address2:   in.GetShipping().Address2,
+			City:       in.GetShipping().GetCity(),
+			State:      in.GetShipping().State,
+			PostalCode: in.GetShipping().GetPostalCode(),
+			Country:    in.GetShipping().GetCountry(),
+			Notes:      in.GetShipping().Notes,
+		},
+		Totals: service.CartTotals{
+			Subtotal: in.GetTotals().GetSubtotal(),
+			Tax:      in.GetTotals().GetTax(),
+			Shipping: in.GetTotals().GetShipping(),
+			Total:    in.GetTotals().GetTotal(),
+		},
+		PaymentStatus: in.GetPaymentStatus(),
+		PaymentMethod: in.PaymentMethod,
+	}
+
+	for i, item := range in.GetItems() {
+		req.Items[i] = service.CartItem{
+			SKU:        item.GetSku(),
+			Title:      item.GetTitle(),
+			Price:      item.GetPrice(),
+			Quantity:   int(item.GetQuantity()),
+			ProductURL: item.ProductUrl,
+		}
+	}
+
+	return req
+}
+
+func orderToProto(order *domain.SupplierOrder) *pb.Order {
+	return &pb.Order{
+		Id:                  order.ID.String(),
+		PartnerId:           order.PartnerID.String(),
+		PartnerOrderId:      order.PartnerOrderID,
+		Reference:           order.Reference,
+		Status:              string(order.Status),
+		ShopifyDraftOrderId: order.ShopifyDraftOrderID,
+		ShopifyOrderId:      order.ShopifyOrderID,
+		CustomerName:        order.CustomerName,
+		CustomerPhone:       order.CustomerPhone,
+		ShippingAddress:     addressToProto(order.ShippingAddress),
+		CartTotal:           order.CartTotal,
+		PaymentStatus:       order.PaymentStatus,
+		PaymentMethod:       order.PaymentMethod,
+		RejectionReason:     order.RejectionReason,
+		TrackingCarrier:     order.TrackingCarrier,
+		TrackingNumber:      order.TrackingNumber,
+		TrackingUrl:         order.TrackingURL,
+		CreatedAt:           timestamppb.New(order.CreatedAt),
+		UpdatedAt:           timestamppb.New(order.UpdatedAt),
+		ConfirmedAt:         timestampToProto(order.ConfirmedAt),
+		RejectedAt:          timestampToProto(order.RejectedAt),
+		ShippedAt:           timestampToProto(order.ShippedAt),
+		DeliveredAt:         timestampToProto(order.DeliveredAt),
+		CancelledAt:         timestampToProto(order.CancelledAt),
+	}
+}
+
+func timestampToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func addressToProto(addr domain.Address) *pb.ShippingAddress {
+	return &pb.ShippingAddress{
+		Street:     addr.Street,
+		City:       addr.City,
+		State:      addr.State,
+		PostalCode: addr.PostalCode,
+		Country:    addr.Country,
+		Address2:   addr.Address2,
+		Notes:      addr.Notes,
+	}
+}
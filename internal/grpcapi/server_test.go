@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/grpcapi/pb"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// listedOrders filters a fixed set of orders the way the listing queries do
+type listedOrders struct {
+	repository.SupplierOrderRepository
+	orders []*domain.SupplierOrder
+}
+
+func (f *listedOrders) filter(match func(*domain.SupplierOrder) bool) []*domain.SupplierOrder {
+	var orders []*domain.SupplierOrder
+	for _, order := range f.orders {
+		if match(order) {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+func (f *listedOrders) ListByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+	return f.filter(func(o *domain.SupplierOrder) bool { return o.Status == status }), nil
+}
+
+func (f *listedOrders) ListByStatusAfter(ctx context.Context, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	return f.filter(func(o *domain.SupplierOrder) bool { return o.Status == status }), nil
+}
+
+func (f *listedOrders) ListByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+	return f.filter(func(o *domain.SupplierOrder) bool { return o.PartnerID == partnerID && o.Status == status }), nil
+}
+
+func (f *listedOrders) ListByPartnerIDAndStatusAfter(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	return f.filter(func(o *domain.SupplierOrder) bool { return o.PartnerID == partnerID && o.Status == status }), nil
+}
+
+func TestListOrdersByStatusOnlyListsOwnOrders(t *testing.T) {
+	partnerA := &domain.Partner{ID: uuid.New(), Name: "A"}
+	partnerB := &domain.Partner{ID: uuid.New(), Name: "B"}
+	now := time.Now()
+	own := &domain.SupplierOrder{ID: uuid.New(), PartnerID: partnerA.ID, Status: domain.OrderStatusConfirmed, CreatedAt: now, UpdatedAt: now}
+	other := &domain.SupplierOrder{ID: uuid.New(), PartnerID: partnerB.ID, Status: domain.OrderStatusConfirmed, CustomerName: "B's customer", CreatedAt: now, UpdatedAt: now}
+	s := &orderServer{
+		repos:  &repository.Repositories{SupplierOrder: &listedOrders{orders: []*domain.SupplierOrder{own, other}}},
+		logger: zap.NewNop(),
+	}
+	ctx := context.WithValue(context.Background(), partnerContextKey{}, partnerA)
+
+	for _, in := range []*pb.ListOrdersRequest{
+		{Status: string(domain.OrderStatusConfirmed)},
+		{Status: string(domain.OrderStatusConfirmed), Offset: 10},
+	} {
+		resp, err := s.ListOrders(ctx, in)
+		if err != nil {
+			t.Fatalf("ListOrders(%v) error = %v", in, err)
+		}
+		if len(resp.Orders) != 1 || resp.Orders[0].Id != own.ID.String() {
+			t.Errorf("ListOrders(offset %d) returned %d orders, want only partner A's", in.Offset, len(resp.Orders))
+		}
+	}
+}
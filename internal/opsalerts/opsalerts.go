@@ -0,0 +1,155 @@
+// Package opsalerts posts operational alerts (orders waiting for staff, failed Shopify calls)
+// to Slack or Microsoft Teams incoming webhooks. Each event type goes to the webhook its
+// route names, or to OPS_ALERTS_WEBHOOK_URL. A nil Notifier does nothing.
+package opsalerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+)
+
+// sendTimeout bounds posting one alert
+const sendTimeout = 10 * time.Second
+
+// Event types, also the keys of OPS_ALERTS_ROUTES
+const (
+	EventOrderAwaitingConfirmation = "order.awaiting_confirmation"
+	EventConfirmationSLABreached   = "order.confirmation_sla_breached"
+	EventShopifyOperationFailed    = "shopify.operation_failed"
+)
+
+// EventTypes lists every event type
+var EventTypes = []string{
+	EventOrderAwaitingConfirmation,
+	EventConfirmationSLABreached,
+	EventShopifyOperationFailed,
+}
+
+// Field is a labelled value shown under an alert's text
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Alert is one message for the ops channel
+type Alert struct {
+	EventType string
+	Title     string
+	Text      string
+	Fields    []Field
+}
+
+// Notifier posts alerts in the background so requests never wait for Slack or Teams
+type Notifier struct {
+	cfg        config.OpsAlertsConfig
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// New creates a notifier for the configured webhooks
+func New(cfg config.OpsAlertsConfig, logger *zap.Logger) *Notifier {
+	return &Notifier{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout: sendTimeout,
+		},
+		logger: logger,
+	}
+}
+
+// Enabled reports whether alerts of the event type are posted anywhere
+func (n *Notifier) Enabled(eventType string) bool {
+	return n != nil && n.cfg.URL(eventType) != ""
+}
+
+// Notify posts the alert to its event type's webhook in the background
+func (n *Notifier) Notify(alert Alert) {
+	if !n.Enabled(alert.EventType) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		if err := n.Send(ctx, alert); err != nil {
+			n.logger.Warn("Failed to post ops alert", zap.String("event_type", alert.EventType), zap.Error(err))
+		}
+	}()
+}
+
+// Send posts the alert and waits for the webhook to accept it
+func (n *Notifier) Send(ctx context.Context, alert Alert) error {
+	target := n.cfg.URL(alert.EventType)
+	if target == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload(target, alert))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("ops alert webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// IsTeams reports whether a webhook URL belongs to Microsoft Teams (Office 365 connectors and
+// Power Automate workflows); every other URL is treated as Slack
+func IsTeams(webhookURL string) bool {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return strings.HasSuffix(host, ".office.com") || strings.HasSuffix(host, ".office365.com") || strings.HasSuffix(host, ".logic.azure.com")
+}
+
+// Payload is the JSON body posted for the alert: a Teams message card or a Slack message
+func Payload(webhookURL string, alert Alert) map[string]interface{} {
+	if IsTeams(webhookURL) {
+		facts := make([]map[string]string, len(alert.Fields))
+		for i, field := range alert.Fields {
+			facts[i] = map[string]string{"name": field.Name, "value": field.Value}
+		}
+		return map[string]interface{}{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  alert.Title,
+			"title":    alert.Title,
+			"text":     alert.Text,
+			"sections": []map[string]interface{}{{"facts": facts}},
+		}
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "*%s*", alert.Title)
+	if alert.Text != "" {
+		fmt.Fprintf(&text, "\n%s", alert.Text)
+	}
+	for _, field := range alert.Fields {
+		fmt.Fprintf(&text, "\n• %s: %s", field.Name, field.Value)
+	}
+	return map[string]interface{}{"text": text.String()}
+}
@@ -0,0 +1,69 @@
+package opsalerts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+)
+
+func TestPayload(t *testing.T) {
+	alert := Alert{
+		EventType: EventShopifyOperationFailed,
+		Title:     "Shopify operation failed",
+		Text:      "timeout",
+		Fields:    []Field{{Name: "Order", Value: "B2B-2024-000001"}},
+	}
+
+	slack := Payload("https://hooks.slack.com/services/T/B/X", alert)
+	if want := "*Shopify operation failed*\ntimeout\n• Order: B2B-2024-000001"; slack["text"] != want {
+		t.Errorf("Slack text = %q, want %q", slack["text"], want)
+	}
+
+	teams := Payload("https://contoso.webhook.office.com/webhookb2/abc", alert)
+	if teams["@type"] != "MessageCard" || teams["title"] != alert.Title || teams["text"] != "timeout" {
+		t.Errorf("Teams payload = %v, want a message card", teams)
+	}
+	body, _ := json.Marshal(teams)
+	if !strings.Contains(string(body), `"facts":[{"name":"Order","value":"B2B-2024-000001"}]`) {
+		t.Errorf("Teams payload %s has no facts for the fields", body)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	var posted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = append(posted, r.URL.Path)
+	}))
+	defer server.Close()
+
+	n := New(config.OpsAlertsConfig{
+		WebhookURL: server.URL + "/default",
+		Routes: map[string]string{
+			EventShopifyOperationFailed:  server.URL + "/shopify",
+			EventConfirmationSLABreached: "off",
+		},
+	}, zap.NewNop())
+
+	for _, eventType := range EventTypes {
+		if err := n.Send(context.Background(), Alert{EventType: eventType, Title: "test"}); err != nil {
+			t.Fatalf("Send(%s) error = %v", eventType, err)
+		}
+	}
+	if got := strings.Join(posted, ","); got != "/default,/shopify" {
+		t.Errorf("posted to %s, want /default,/shopify", got)
+	}
+	if n.Enabled(EventConfirmationSLABreached) {
+		t.Error("alerts routed to off are enabled")
+	}
+	var disabled *Notifier
+	if disabled.Enabled(EventOrderAwaitingConfirmation) {
+		t.Error("a nil notifier is enabled")
+	}
+}
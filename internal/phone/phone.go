@@ -0,0 +1,162 @@
+// Package phone validates customer phone numbers and converts them to E.164 for the
+// Jordan and GCC markets, where partners usually send local formats such as 0791234567.
+package phone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// countryRule describes a national numbering plan
+type countryRule struct {
+	callingCode string
+	// trunkPrefix is dropped from national numbers ("0" in 079...), empty if unused
+	trunkPrefix string
+	// subscriberLengths are the valid lengths without calling code or trunk prefix
+	subscriberLengths []int
+	// leadingDigits are the valid first digits of the subscriber number
+	leadingDigits string
+}
+
+// rules by ISO 3166-1 alpha-2 country code
+var rules = map[string]countryRule{
+	"JO": {callingCode: "962", trunkPrefix: "0", subscriberLengths: []int{8, 9}, leadingDigits: "234567"},
+	"SA": {callingCode: "966", trunkPrefix: "0", subscriberLengths: []int{8, 9}, leadingDigits: "1234567"},
+	"AE": {callingCode: "971", trunkPrefix: "0", subscriberLengths: []int{8, 9}, leadingDigits: "2345679"},
+	"KW": {callingCode: "965", subscriberLengths: []int{8}, leadingDigits: "12569"},
+	"QA": {callingCode: "974", subscriberLengths: []int{8}, leadingDigits: "34567"},
+	"BH": {callingCode: "973", subscriberLengths: []int{8}, leadingDigits: "136"},
+	"OM": {callingCode: "968", subscriberLengths: []int{8}, leadingDigits: "279"},
+}
+
+// mobileLengths pins mobile numbers (leading digit) to their exact length where a plan
+// mixes 8-digit landlines and 9-digit mobiles
+var mobileLengths = map[string]map[byte]int{
+	"JO": {'7': 9},
+	"SA": {'5': 9},
+	"AE": {'5': 9},
+}
+
+// ErrInvalid is returned for numbers that cannot be a valid phone number
+type ErrInvalid struct {
+	Reason string
+}
+
+func (e *ErrInvalid) Error() string {
+	return "invalid phone number: " + e.Reason
+}
+
+// Supported reports whether national numbers for the country can be normalized
+func Supported(country string) bool {
+	_, ok := rules[strings.ToUpper(strings.TrimSpace(country))]
+	return ok
+}
+
+// Normalize converts raw to E.164 (e.g. "+962791234567").
+// Numbers with an international prefix (+ or 00) are validated against their own
+// calling code; national numbers are read using the country (ISO alpha-2) of the
+// shipping address. Returns "" and no error when a national number is from a country
+// without a known numbering plan, and *ErrInvalid for numbers that are clearly wrong.
+func Normalize(raw, country string) (string, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return "", err
+	}
+
+	if international {
+		for code, rule := range rules {
+			if strings.HasPrefix(digits, rule.callingCode) {
+				return rule.e164(code, digits[len(rule.callingCode):])
+			}
+		}
+		// Outside our markets: only check the E.164 length
+		if len(digits) < 8 || len(digits) > 15 {
+			return "", &ErrInvalid{Reason: "wrong number of digits"}
+		}
+		return "+" + digits, nil
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(country))
+	rule, ok := rules[code]
+	if !ok {
+		return "", nil
+	}
+
+	// Calling code written without + or 00, e.g. 962791234567
+	if strings.HasPrefix(digits, rule.callingCode) && rule.validLength(len(digits)-len(rule.callingCode)) {
+		digits = digits[len(rule.callingCode):]
+	}
+	if rule.trunkPrefix != "" {
+		digits = strings.TrimPrefix(digits, rule.trunkPrefix)
+	}
+	return rule.e164(code, digits)
+}
+
+// clean strips formatting, maps Arabic-Indic digits to ASCII and reports whether the
+// number carried an international prefix
+func clean(raw string) (string, bool, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return "", false, &ErrInvalid{Reason: "empty"}
+	}
+
+	var digits strings.Builder
+	international := false
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= '٠' && r <= '٩': // Arabic-Indic
+			digits.WriteRune('0' + (r - '٠'))
+		case r >= '۰' && r <= '۹': // Extended Arabic-Indic (Persian/Urdu keyboards)
+			digits.WriteRune('0' + (r - '۰'))
+		case r == '+' && i == 0:
+			international = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+			// formatting
+		case r == '\u200e' || r == '\u200f' || r == '\u2066' || r == '\u2069':
+			// bidi marks pasted from right-to-left text
+		default:
+			return "", false, &ErrInvalid{Reason: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	d := digits.String()
+	if !international && strings.HasPrefix(d, "00") {
+		d = d[2:]
+		international = true
+	}
+	if d == "" {
+		return "", false, &ErrInvalid{Reason: "no digits"}
+	}
+	return d, international, nil
+}
+
+func (r countryRule) e164(country, subscriber string) (string, error) {
+	if r.trunkPrefix != "" {
+		// "+962 (0)79..." style
+		subscriber = strings.TrimPrefix(subscriber, r.trunkPrefix)
+	}
+	if subscriber == "" || !strings.ContainsRune(r.leadingDigits, rune(subscriber[0])) {
+		return "", &ErrInvalid{Reason: fmt.Sprintf("not a valid %s number", country)}
+	}
+
+	want, fixed := mobileLengths[country][subscriber[0]]
+	switch {
+	case fixed && len(subscriber) != want:
+		return "", &ErrInvalid{Reason: fmt.Sprintf("wrong number of digits for a %s mobile number", country)}
+	case !fixed && !r.validLength(len(subscriber)):
+		return "", &ErrInvalid{Reason: fmt.Sprintf("wrong number of digits for a %s number", country)}
+	}
+
+	return "+" + r.callingCode + subscriber, nil
+}
+
+func (r countryRule) validLength(n int) bool {
+	for _, l := range r.subscriberLengths {
+		if n == l {
+			return true
+		}
+	}
+	return false
+}
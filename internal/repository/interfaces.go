@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jafarshop/b2bapi/internal/domain"
@@ -13,6 +14,15 @@ type PartnerRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error)
 	Create(ctx context.Context, partner *domain.Partner) error
 	Update(ctx context.Context, partner *domain.Partner) error
+	// ListByOrganizationID lists the partners of an organization by name
+	ListByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]*domain.Partner, error)
+}
+
+// OrganizationRepository defines organization data access methods
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization *domain.Organization) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error)
+	List(ctx context.Context) ([]*domain.Organization, error)
 }
 
 // SupplierOrderRepository defines supplier order data access methods
@@ -20,13 +30,72 @@ type SupplierOrderRepository interface {
 	Create(ctx context.Context, order *domain.SupplierOrder) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error)
 	GetByPartnerIDAndPartnerOrderID(ctx context.Context, partnerID uuid.UUID, partnerOrderID string) (*domain.SupplierOrder, error)
+	// ListByPartnerIDAndIDs returns the partner's orders among ids; other partners' orders are left out
+	ListByPartnerIDAndIDs(ctx context.Context, partnerID uuid.UUID, ids []uuid.UUID) ([]*domain.SupplierOrder, error)
+	ListByPartnerIDAndPartnerOrderIDs(ctx context.Context, partnerID uuid.UUID, partnerOrderIDs []string) ([]*domain.SupplierOrder, error)
+	// GetLatestByContentHash returns the partner's newest order in the statuses created from a
+	// cart with the content hash at or after since
+	GetLatestByContentHash(ctx context.Context, partnerID uuid.UUID, contentHash string, since time.Time, statuses []domain.OrderStatus) (*domain.SupplierOrder, error)
+	GetByShopifyOrderID(ctx context.Context, shopifyOrderID int64) (*domain.SupplierOrder, error)
+	// SumOutstanding totals the cart value of a partner's orders in the statuses that are not paid
+	SumOutstanding(ctx context.Context, partnerID uuid.UUID, statuses []domain.OrderStatus) (float64, error)
+	GetByReference(ctx context.Context, reference string) (*domain.SupplierOrder, error)
+	NextReferenceNumber(ctx context.Context) (int64, error)
 	Update(ctx context.Context, order *domain.SupplierOrder) error
-	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string) error
-	UpdateTracking(ctx context.Context, id uuid.UUID, carrier, trackingNumber, trackingURL *string) error
-	UpdateShopifyDraftOrderID(ctx context.Context, id uuid.UUID, draftOrderID int64) error
-	UpdateShopifyOrderID(ctx context.Context, id uuid.UUID, orderID int64) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string, changedAt time.Time) error
+	// Reject sets the order rejected with a rejection code and optional free-text reason
+	Reject(ctx context.Context, id uuid.UUID, code domain.RejectionCode, reason *string, rejectedAt time.Time) error
+	// ExpirePending cancels the order if it is still pending confirmation and its PendingSince
+	// is before the cutoff; it returns false when the order moved on first
+	ExpirePending(ctx context.Context, id uuid.UUID, createdBefore, cancelledAt time.Time) (bool, error)
+	// UpdateStatusWithTracking sets the status, tracking and shipped_at together if the order
+	// is still in fromStatus; otherwise it returns ErrInvalidStateTransition and changes nothing
+	UpdateStatusWithTracking(ctx context.Context, id uuid.UUID, fromStatus, status domain.OrderStatus, carrier, trackingNumber, trackingURL *string, shippedAt time.Time) error
+	// Reassign moves the order to another partner if it still belongs to fromPartnerID;
+	// it returns false when it does not
+	Reassign(ctx context.Context, id, fromPartnerID, toPartnerID uuid.UUID) (bool, error)
+	// Hold and Release return ErrInvalidStateTransition and change nothing when the order is
+	// no longer in fromStatus, or no longer on hold. Release adds the time held to HeldSeconds.
+	Hold(ctx context.Context, id uuid.UUID, fromStatus domain.OrderStatus, reason string) error
+	Release(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error
+	// UpdateShopifyDraftOrderID and UpdateShopifyOrderID also store the name shown in Shopify
+	// admin (e.g. #D12, #1001); an empty name keeps the stored one
+	UpdateShopifyDraftOrderID(ctx context.Context, id uuid.UUID, draftOrderID int64, name string) error
+	UpdateShopifyOrderID(ctx context.Context, id uuid.UUID, orderID int64, name string) error
+	UpdateShopifyCustomerID(ctx context.Context, id uuid.UUID, customerID int64) error
+	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string, paymentMethod *string) error
 	ListByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*domain.SupplierOrder, error)
 	ListByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error)
+	ListByPartnerIDAfter(ctx context.Context, partnerID uuid.UUID, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error)
+	// ListByPartnerIDsAfter lists the orders of any of the partners, newest first, using keyset pagination
+	ListByPartnerIDsAfter(ctx context.Context, partnerIDs []uuid.UUID, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error)
+	ListByStatusAfter(ctx context.Context, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error)
+	// ListByPartnerIDAndStatus and ListByPartnerIDAndStatusAfter list one partner's orders in
+	// a status, newest first
+	ListByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error)
+	ListByPartnerIDAndStatusAfter(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error)
+	// ListWithoutShopifyOrderAfter lists orders in statuses that have neither a Shopify draft nor an order, created at or after since
+	ListWithoutShopifyOrderAfter(ctx context.Context, statuses []domain.OrderStatus, since time.Time, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error)
+	ListCreatedSince(ctx context.Context, since time.Time, statuses []domain.OrderStatus) ([]*domain.SupplierOrder, error)
+	// ListPendingCreatedBefore lists orders pending confirmation without a Shopify order whose
+	// PendingSince is before the time, oldest first
+	ListPendingCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*domain.SupplierOrder, error)
+	// ListNotExportedForFulfillment lists orders in the status that were not sent to the 3PL
+	// yet (see FulfillmentExportRepository), oldest first
+	ListNotExportedForFulfillment(ctx context.Context, status domain.OrderStatus, limit int) ([]*domain.SupplierOrder, error)
+	ListOldestByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit int) ([]*domain.SupplierOrder, error)
+	// FindInvariantViolations counts the orders created before createdBefore that break the
+	// invariant and returns up to limit of them, newest first
+	FindInvariantViolations(ctx context.Context, invariant domain.OrderInvariant, createdBefore time.Time, limit int) (int, []*domain.SupplierOrder, error)
+	// CountForExport and ListForExportAfter select the orders of an export, newest first
+	CountForExport(ctx context.Context, filter domain.OrderFilter) (int, error)
+	ListForExportAfter(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error)
+	// Report runs a custom report; groups are sorted by day, partner name and status
+	Report(ctx context.Context, query domain.ReportQuery) ([]*domain.ReportRow, error)
+	CountByStatusForPartner(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (map[domain.OrderStatus]int, error)
+	FunnelStats(ctx context.Context, from, to time.Time) ([]*domain.OrderFunnelStats, error)
+	CountRejectionReasons(ctx context.Context, from, to time.Time) ([]*domain.RejectionReasonCount, error)
+	CountRejectionCodes(ctx context.Context, from, to time.Time) ([]*domain.RejectionCodeCount, error)
 }
 
 // SupplierOrderItemRepository defines order item data access methods
@@ -34,28 +103,211 @@ type SupplierOrderItemRepository interface {
 	Create(ctx context.Context, item *domain.SupplierOrderItem) error
 	CreateBatch(ctx context.Context, items []*domain.SupplierOrderItem) error
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.SupplierOrderItem, error)
+	ListNonSupplierSKUs(ctx context.Context, from, to time.Time) ([]*domain.NonSupplierSKU, error)
+	// ListOrderedVariants lists the supplier variants each partner ordered since the given time
+	ListOrderedVariants(ctx context.Context, since time.Time) ([]*domain.OrderedVariant, error)
 }
 
 // IdempotencyKeyRepository defines idempotency key data access methods
 type IdempotencyKeyRepository interface {
 	GetByKey(ctx context.Context, key string) (*domain.IdempotencyKey, error)
 	Create(ctx context.Context, key *domain.IdempotencyKey) error
+	// ReassignOrder moves the keys of an order to the order's new partner and returns how many moved
+	ReassignOrder(ctx context.Context, supplierOrderID, partnerID uuid.UUID) (int64, error)
+	DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 // SKUMappingRepository defines SKU mapping data access methods
 type SKUMappingRepository interface {
 	GetBySKU(ctx context.Context, sku string) (*domain.SKUMapping, error)
+	ListByNormalizedSKU(ctx context.Context, normalizedSKU string) ([]*domain.SKUMapping, error)
+	ListByBarcode(ctx context.Context, barcode string) ([]*domain.SKUMapping, error)
 	GetActiveSKUs(ctx context.Context) ([]string, error)
-	Create(ctx context.Context, mapping *domain.SKUMapping) error
-	Update(ctx context.Context, mapping *domain.SKUMapping) error
-	Upsert(ctx context.Context, mapping *domain.SKUMapping) error
+	// Create, Update and Upsert record the actor in the mapping's history when what the SKU
+	// maps to changes
+	Create(ctx context.Context, mapping *domain.SKUMapping, actor domain.SKUMappingActor) error
+	Update(ctx context.Context, mapping *domain.SKUMapping, actor domain.SKUMappingActor) error
+	Upsert(ctx context.Context, mapping *domain.SKUMapping, actor domain.SKUMappingActor) error
 	GetAllActive(ctx context.Context) ([]*domain.SKUMapping, error)
+	// ListChangesAfter lists the history of SKU mappings, newest first
+	ListChangesAfter(ctx context.Context, filter domain.SKUMappingChangeFilter, after *domain.OrderCursor, limit int) ([]*domain.SKUMappingChange, error)
 }
 
 // OrderEventRepository defines order event data access methods
 type OrderEventRepository interface {
 	Create(ctx context.Context, event *domain.OrderEvent) error
 	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderEvent, error)
+	ListLatestByType(ctx context.Context, eventType string, from, to time.Time) ([]*domain.OrderEvent, error)
+	ListStatusChangesByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*domain.OrderStatusChange, error)
+	// DeleteCreatedBefore only deletes events of orders in the given statuses
+	DeleteCreatedBefore(ctx context.Context, before time.Time, statuses []domain.OrderStatus, limit int) (int64, error)
+	ListCreatedBefore(ctx context.Context, before time.Time, statuses []domain.OrderStatus, limit int) ([]*domain.OrderEvent, error)
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+	// Restore inserts archived events back, skipping ones that exist
+	Restore(ctx context.Context, events []*domain.OrderEvent) (int64, error)
+}
+
+// PartnerInvitationRepository defines partner invitation data access methods
+type PartnerInvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.PartnerInvitation) error
+	// Claim atomically marks an unexpired, unaccepted invitation as accepted
+	Claim(ctx context.Context, tokenHash string) (*domain.PartnerInvitation, error)
+	SetPartnerID(ctx context.Context, id uuid.UUID, partnerID uuid.UUID) error
+}
+
+// AuditLogRepository defines audit log data access methods
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *domain.AuditLog) error
+	ListByResource(ctx context.Context, resourceType, resourceID string) ([]*domain.AuditLog, error)
+	DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	ListCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*domain.AuditLog, error)
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+	// Restore inserts archived audit logs back, skipping ones that exist
+	Restore(ctx context.Context, logs []*domain.AuditLog) (int64, error)
+}
+
+// RevokedAPIKeyRepository defines API key revocation list data access methods
+type RevokedAPIKeyRepository interface {
+	Create(ctx context.Context, revoked *domain.RevokedAPIKey) error
+	IsRevoked(ctx context.Context, apiKeyHash string) (bool, error)
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.RevokedAPIKey, error)
+}
+
+// PartnerCatalogRepository defines per-partner allowed SKU data access methods
+type PartnerCatalogRepository interface {
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.SKUMapping, error)
+	Add(ctx context.Context, partnerID uuid.UUID, sku string) error
+	Remove(ctx context.Context, partnerID uuid.UUID, sku string) error
+}
+
+// WebhookDeliveryRepository defines webhook delivery data access methods
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) error
+	StatsByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*domain.WebhookDeliveryStats, error)
+	ListFailedByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time, limit int) ([]*domain.WebhookDelivery, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+	ListHistoryByPartnerIDAfter(ctx context.Context, partnerID uuid.UUID, filter domain.WebhookDeliveryFilter, after *domain.OrderCursor, limit int) ([]*domain.WebhookDeliveryHistory, error)
+}
+
+// WebhookSubscriptionRepository defines partner webhook event subscription data access methods
+type WebhookSubscriptionRepository interface {
+	// ListByPartnerID returns the subscribed event types; none means the partner receives every event
+	ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]string, error)
+	Replace(ctx context.Context, partnerID uuid.UUID, eventTypes []string) error
+}
+
+// OrderRollupRepository defines daily order rollup data access methods. Days are UTC dates
+// and ranges are [from, to) between UTC midnights.
+type OrderRollupRepository interface {
+	// Rebuild recomputes the rollups of every day in the range from supplier_orders and
+	// marks the days as rolled up. Returns the rollup rows written.
+	Rebuild(ctx context.Context, from, to time.Time) (int64, error)
+	// CountRolledUpDays counts the days in the range that have been rolled up
+	CountRolledUpDays(ctx context.Context, from, to time.Time) (int, error)
+	// FunnelStats is SupplierOrderRepository.FunnelStats read from the rollups
+	FunnelStats(ctx context.Context, from, to time.Time) ([]*domain.OrderFunnelStats, error)
+	// FirstOrderDay returns the day of the oldest order, nil when there are none
+	FirstOrderDay(ctx context.Context) (*time.Time, error)
+}
+
+// LowStockAlertRepository defines low-stock alert data access methods
+type LowStockAlertRepository interface {
+	// Record saves the alert unless the partner was already alerted about the variant; it
+	// returns false then, so concurrent checks never alert twice
+	Record(ctx context.Context, alert *domain.LowStockAlert) (bool, error)
+	// DeleteByVariantIDs clears the alerts of restocked variants, for every partner
+	DeleteByVariantIDs(ctx context.Context, variantIDs []int64) (int64, error)
+}
+
+// CreditLimitAlertRepository defines credit limit usage alert data access methods
+type CreditLimitAlertRepository interface {
+	// GetThreshold returns the highest usage threshold the partner was last alerted about, 0 if none
+	GetThreshold(ctx context.Context, partnerID uuid.UUID) (int, error)
+	// SetThreshold changes the partner's threshold from one value to another; it returns false
+	// when the threshold is no longer from, so concurrent checks never alert twice
+	SetThreshold(ctx context.Context, partnerID uuid.UUID, from, to int) (bool, error)
+}
+
+// ChangelogRepository defines changelog entry data access methods
+type ChangelogRepository interface {
+	Create(ctx context.Context, entry *domain.ChangelogEntry) error
+	// Delete retracts an entry
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListAfter lists entries newest first, starting after the cursor (created_at is the
+	// publication time)
+	ListAfter(ctx context.Context, filter domain.ChangelogFilter, after *domain.OrderCursor, limit int) ([]*domain.ChangelogEntry, error)
+}
+
+// ExportJobRepository defines export job data access methods
+type ExportJobRepository interface {
+	// Create records a running job
+	Create(ctx context.Context, job *domain.ExportJob) error
+	// GetByID returns the job without its content
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)
+	// GetContent returns the file of a completed job
+	GetContent(ctx context.Context, id uuid.UUID) ([]byte, error)
+	Complete(ctx context.Context, id uuid.UUID, content []byte, contentType, filename string, rowCount int, completedAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, reason string, completedAt time.Time) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// FulfillmentExportRepository defines data access methods for orders exported to the 3PL
+type FulfillmentExportRepository interface {
+	// Claim records the orders as exported in the file and returns the ones that were not
+	// exported before, so concurrent exports never send an order twice
+	Claim(ctx context.Context, orderIDs []uuid.UUID, fileKey string) ([]uuid.UUID, error)
+	// DeleteByFileKey releases the orders of a file that could not be uploaded
+	DeleteByFileKey(ctx context.Context, fileKey string) error
+}
+
+// ReviewAssignmentRepository defines data access methods for review queue assignments
+type ReviewAssignmentRepository interface {
+	// Claim assigns the order unless someone else holds it, and reports whether the assignee
+	// holds it now. Claiming an order the assignee already holds changes nothing.
+	Claim(ctx context.Context, assignment *domain.ReviewAssignment) (bool, error)
+	// Assign hands the order to the assignee, whoever held it before
+	Assign(ctx context.Context, assignment *domain.ReviewAssignment) error
+	// Release removes the assignee's assignment and reports whether they held the order
+	Release(ctx context.Context, orderID, assigneeID uuid.UUID) (bool, error)
+	GetByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.ReviewAssignment, error)
+	ListByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*domain.ReviewAssignment, error)
+}
+
+// DigestSubscriptionRepository defines partner digest subscription data access methods
+type DigestSubscriptionRepository interface {
+	Upsert(ctx context.Context, subscription *domain.DigestSubscription) error
+	GetByPartnerID(ctx context.Context, partnerID uuid.UUID) (*domain.DigestSubscription, error)
+	Delete(ctx context.Context, partnerID uuid.UUID) error
+	List(ctx context.Context) ([]*domain.DigestSubscription, error)
+	// ClaimSend sets last_sent_at to sentAt unless a digest was already sent at or after
+	// due; it returns false when another run claimed it first
+	ClaimSend(ctx context.Context, partnerID uuid.UUID, due, sentAt time.Time) (bool, error)
+}
+
+// APIRequestLogRepository defines partner API request log data access methods
+type APIRequestLogRepository interface {
+	Create(ctx context.Context, log *domain.APIRequestLog) error
+	StatsByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*domain.APIUsageStats, error)
+}
+
+// UnmatchedSKURepository defines unmatched cart SKU data access methods
+type UnmatchedSKURepository interface {
+	// Record counts one more occurrence of each SKU, keeping the latest title and reason
+	Record(ctx context.Context, skus []*domain.UnmatchedSKU) error
+	List(ctx context.Context, filter domain.UnmatchedSKUFilter) ([]*domain.UnmatchedSKU, error)
+}
+
+// ItemSubstitutionRepository defines item substitution data access methods
+type ItemSubstitutionRepository interface {
+	// Create records a proposal; it returns false when the item already has an open one
+	Create(ctx context.Context, substitution *domain.ItemSubstitution) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.ItemSubstitution, error)
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.ItemSubstitution, error)
+	// Accept marks an open proposal accepted and replaces the item with the substitute,
+	// adjusting the order's cart total; it returns false when the proposal is not open
+	Accept(ctx context.Context, id uuid.UUID, respondedAt time.Time) (bool, error)
+	// Decline marks an open proposal declined; it returns false when it is not open
+	Decline(ctx context.Context, id uuid.UUID, reason *string, respondedAt time.Time) (bool, error)
 }
 
 // Repositories aggregates all repositories
@@ -66,4 +318,22 @@ type Repositories struct {
 	IdempotencyKey   IdempotencyKeyRepository
 	SKUMapping       SKUMappingRepository
 	OrderEvent       OrderEventRepository
+	PartnerInvitation PartnerInvitationRepository
+	AuditLog         AuditLogRepository
+	PartnerCatalog   PartnerCatalogRepository
+	WebhookDelivery  WebhookDeliveryRepository
+	APIRequestLog    APIRequestLogRepository
+	DigestSubscription DigestSubscriptionRepository
+	UnmatchedSKU     UnmatchedSKURepository
+	RevokedAPIKey    RevokedAPIKeyRepository
+	WebhookSubscription WebhookSubscriptionRepository
+	OrderRollup      OrderRollupRepository
+	LowStockAlert    LowStockAlertRepository
+	ItemSubstitution ItemSubstitutionRepository
+	Organization     OrganizationRepository
+	CreditLimitAlert CreditLimitAlertRepository
+	Changelog        ChangelogRepository
+	ExportJob        ExportJobRepository
+	FulfillmentExport FulfillmentExportRepository
+	ReviewAssignment  ReviewAssignmentRepository
 }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// maxCachedPartners bounds the auth cache; it only grows with distinct valid API keys
+const maxCachedPartners = 10000
+
+type cachedPartner struct {
+	partner   domain.Partner
+	expiresAt time.Time
+}
+
+// cachedPartnerRepository keeps authenticated partners in memory for a short TTL, so
+// requests skip the database and the bcrypt check. Updates through it invalidate the
+// partner at once; other server instances see changes when their entry expires.
+type cachedPartnerRepository struct {
+	PartnerRepository
+	ttl time.Duration
+
+	mu sync.Mutex
+	// byLookupHash holds partners by the lookup hash of the API key they authenticated with
+	byLookupHash map[string]cachedPartner
+}
+
+// NewCachedPartnerRepository wraps a partner repository with an API key authentication cache
+func NewCachedPartnerRepository(inner PartnerRepository, ttl time.Duration) PartnerRepository {
+	return &cachedPartnerRepository{
+		PartnerRepository: inner,
+		ttl:               ttl,
+		byLookupHash:      make(map[string]cachedPartner),
+	}
+}
+
+func (r *cachedPartnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string) (*domain.Partner, error) {
+	lookupHash := domain.APIKeyLookupHash(apiKey)
+	now := time.Now()
+
+	r.mu.Lock()
+	entry, ok := r.byLookupHash[lookupHash]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		// A copy, so callers cannot change the cached partner
+		partner := entry.partner
+		return &partner, nil
+	}
+
+	partner, err := r.PartnerRepository.GetByAPIKeyHash(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.byLookupHash) >= maxCachedPartners {
+		r.evictExpired(now)
+	}
+	if len(r.byLookupHash) < maxCachedPartners {
+		r.byLookupHash[lookupHash] = cachedPartner{partner: *partner, expiresAt: now.Add(r.ttl)}
+	}
+	return partner, nil
+}
+
+func (r *cachedPartnerRepository) Update(ctx context.Context, partner *domain.Partner) error {
+	// Invalidate before and after, so a concurrent request cannot re-cache the old record
+	r.invalidate(partner.ID)
+	err := r.PartnerRepository.Update(ctx, partner)
+	r.invalidate(partner.ID)
+	return err
+}
+
+func (r *cachedPartnerRepository) invalidate(partnerID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for lookupHash, entry := range r.byLookupHash {
+		if entry.partner.ID == partnerID {
+			delete(r.byLookupHash, lookupHash)
+		}
+	}
+}
+
+func (r *cachedPartnerRepository) evictExpired(now time.Time) {
+	for lookupHash, entry := range r.byLookupHash {
+		if !now.Before(entry.expiresAt) {
+			delete(r.byLookupHash, lookupHash)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/secrets"
+)
+
+// encryptedPartnerRepository encrypts partner webhook secrets before they are stored and
+// decrypts them when partners are read. Callers only ever see plain text secrets.
+type encryptedPartnerRepository struct {
+	PartnerRepository
+	cipher *secrets.Cipher
+}
+
+// NewEncryptedPartnerRepository wraps a partner repository with webhook secret encryption. With
+// a nil cipher secrets are stored in plain text, and reading an encrypted one fails.
+func NewEncryptedPartnerRepository(inner PartnerRepository, cipher *secrets.Cipher) PartnerRepository {
+	return &encryptedPartnerRepository{
+		PartnerRepository: inner,
+		cipher:            cipher,
+	}
+}
+
+func (r *encryptedPartnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string) (*domain.Partner, error) {
+	partner, err := r.PartnerRepository.GetByAPIKeyHash(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.decrypt(partner); err != nil {
+		return nil, err
+	}
+	return partner, nil
+}
+
+func (r *encryptedPartnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error) {
+	partner, err := r.PartnerRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.decrypt(partner); err != nil {
+		return nil, err
+	}
+	return partner, nil
+}
+
+func (r *encryptedPartnerRepository) Create(ctx context.Context, partner *domain.Partner) error {
+	return r.withEncryptedSecret(partner, func() error {
+		return r.PartnerRepository.Create(ctx, partner)
+	})
+}
+
+func (r *encryptedPartnerRepository) Update(ctx context.Context, partner *domain.Partner) error {
+	return r.withEncryptedSecret(partner, func() error {
+		return r.PartnerRepository.Update(ctx, partner)
+	})
+}
+
+// withEncryptedSecret runs write with the partner's webhook secret encrypted, then puts the
+// plain text secret back
+func (r *encryptedPartnerRepository) withEncryptedSecret(partner *domain.Partner, write func() error) error {
+	plain := partner.WebhookSecret
+	if plain != nil {
+		encrypted, err := r.cipher.Encrypt(*plain)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt webhook secret: %w", err)
+		}
+		partner.WebhookSecret = &encrypted
+	}
+	defer func() { partner.WebhookSecret = plain }()
+
+	return write()
+}
+
+func (r *encryptedPartnerRepository) decrypt(partner *domain.Partner) error {
+	if partner.WebhookSecret == nil {
+		return nil
+	}
+	secret, err := r.cipher.Decrypt(*partner.WebhookSecret)
+	if err != nil {
+		return fmt.Errorf("webhook secret of partner %s: %w", partner.ID, err)
+	}
+	partner.WebhookSecret = &secret
+	return nil
+}
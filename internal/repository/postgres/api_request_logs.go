@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type apiRequestLogRepository struct {
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
+}
+
+// NewAPIRequestLogRepository creates a new API request log repository
+func NewAPIRequestLogRepository(db *sql.DB, logger *zap.Logger) *apiRequestLogRepository {
+	return &apiRequestLogRepository{
+		db:      db,
+		replica: db,
+		logger:  logger,
+	}
+}
+
+func (r *apiRequestLogRepository) Create(ctx context.Context, log *domain.APIRequestLog) error {
+	query := `
+		INSERT INTO api_request_logs (id, partner_id, method, route, status, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		log.ID,
+		log.PartnerID,
+		log.Method,
+		log.Route,
+		log.Status,
+		log.DurationMs,
+		log.CreatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to create API request log", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *apiRequestLogRepository) StatsByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*domain.APIUsageStats, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status >= 400 AND status < 500),
+			COUNT(*) FILTER (WHERE status >= 500),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms), 0)
+		FROM api_request_logs
+		WHERE partner_id = $1 AND created_at >= $2 AND created_at < $3
+	`
+
+	var stats domain.APIUsageStats
+	err := r.replica.QueryRowContext(ctx, query, partnerID, from, to).Scan(
+		&stats.TotalRequests,
+		&stats.ClientErrors,
+		&stats.ServerErrors,
+		&stats.P95LatencyMs,
+	)
+	if err != nil {
+		r.logger.Error("Failed to get API usage stats", zap.Error(err))
+		return nil, err
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type auditLogRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *sql.DB, logger *zap.Logger) *auditLogRepository {
+	return &auditLogRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, log *domain.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (id, actor, action, resource_type, resource_id, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	var dataJSON []byte
+	var err error
+	if log.Data != nil {
+		dataJSON, err = json.Marshal(log.Data)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		log.ID,
+		log.Actor,
+		log.Action,
+		log.ResourceType,
+		log.ResourceID,
+		dataJSON,
+		log.CreatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to create audit log", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *auditLogRepository) ListByResource(ctx context.Context, resourceType, resourceID string) ([]*domain.AuditLog, error) {
+	query := `
+		SELECT id, actor, action, resource_type, resource_id, data, created_at
+		FROM audit_logs
+		WHERE resource_type = $1 AND resource_id = $2
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, resourceType, resourceID)
+	if err != nil {
+		r.logger.Error("Failed to list audit logs by resource", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuditLogs(rows)
+}
+
+func scanAuditLogs(rows *sql.Rows) ([]*domain.AuditLog, error) {
+	var logs []*domain.AuditLog
+	for rows.Next() {
+		var log domain.AuditLog
+		var dataJSON []byte
+
+		err := rows.Scan(
+			&log.ID,
+			&log.Actor,
+			&log.Action,
+			&log.ResourceType,
+			&log.ResourceID,
+			&dataJSON,
+			&log.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(dataJSON) > 0 {
+			if err := json.Unmarshal(dataJSON, &log.Data); err != nil {
+				return nil, err
+			}
+		}
+
+		logs = append(logs, &log)
+	}
+
+	return logs, rows.Err()
+}
+
+// DeleteCreatedBefore deletes up to limit audit logs created before the cutoff and returns how many were deleted
+func (r *auditLogRepository) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM audit_logs
+		WHERE id IN (
+			SELECT id FROM audit_logs
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		r.logger.Error("Failed to delete audit logs", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListCreatedBefore lists up to limit audit logs created before the cutoff, oldest first
+func (r *auditLogRepository) ListCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*domain.AuditLog, error) {
+	query := `
+		SELECT id, actor, action, resource_type, resource_id, data, created_at
+		FROM audit_logs
+		WHERE created_at < $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		r.logger.Error("Failed to list audit logs by age", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuditLogs(rows)
+}
+
+func (r *auditLogRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	idValues := make([]string, len(ids))
+	for i, id := range ids {
+		idValues[i] = id.String()
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM audit_logs WHERE id = ANY($1::uuid[])`, pq.Array(idValues))
+	if err != nil {
+		r.logger.Error("Failed to delete audit logs", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Restore inserts archived audit logs back, skipping ones that are still present, and
+// returns how many were inserted
+func (r *auditLogRepository) Restore(ctx context.Context, logs []*domain.AuditLog) (int64, error) {
+	query := `
+		INSERT INTO audit_logs (id, actor, action, resource_type, resource_id, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	var restored int64
+	for _, log := range logs {
+		var dataJSON []byte
+		if log.Data != nil {
+			var err error
+			if dataJSON, err = json.Marshal(log.Data); err != nil {
+				return restored, err
+			}
+		}
+
+		result, err := r.db.ExecContext(ctx, query,
+			log.ID, log.Actor, log.Action, log.ResourceType, log.ResourceID, dataJSON, log.CreatedAt)
+		if err != nil {
+			r.logger.Error("Failed to restore audit log", zap.Error(err))
+			return restored, err
+		}
+		inserted, _ := result.RowsAffected()
+		restored += inserted
+	}
+	return restored, nil
+}
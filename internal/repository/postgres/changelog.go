@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type changelogRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewChangelogRepository creates a new changelog repository
+func NewChangelogRepository(db *sql.DB, logger *zap.Logger) *changelogRepository {
+	return &changelogRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *changelogRepository) Create(ctx context.Context, entry *domain.ChangelogEntry) error {
+	query := `
+		INSERT INTO changelog_entries (id, type, title, description, api_version, affects, effective_at, ends_at, published_by, published_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.Affects == nil {
+		entry.Affects = []string{}
+	}
+	entry.PublishedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID,
+		entry.Type,
+		entry.Title,
+		entry.Description,
+		entry.APIVersion,
+		pq.Array(entry.Affects),
+		entry.EffectiveAt,
+		entry.EndsAt,
+		entry.PublishedBy,
+		entry.PublishedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create changelog entry", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *changelogRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM changelog_entries WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.Error("Failed to delete changelog entry", zap.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &errors.ErrNotFound{Resource: "changelog entry", ID: id.String()}
+	}
+
+	return nil
+}
+
+func (r *changelogRepository) ListAfter(ctx context.Context, filter domain.ChangelogFilter, after *domain.OrderCursor, limit int) ([]*domain.ChangelogEntry, error) {
+	query := `
+		SELECT id, type, title, description, api_version, affects, effective_at, ends_at, published_by, published_at
+		FROM changelog_entries
+		WHERE ($1 = '' OR type = $1)
+			AND ($2::timestamptz IS NULL OR published_at > $2)
+			AND ($3::timestamptz IS NULL OR (published_at, id) < ($3, $4))
+		ORDER BY published_at DESC, id DESC
+		LIMIT $5
+	`
+
+	var since sql.NullTime
+	if filter.Since != nil {
+		since = sql.NullTime{Time: *filter.Since, Valid: true}
+	}
+	var afterPublishedAt sql.NullTime
+	var afterID uuid.NullUUID
+	if after != nil {
+		afterPublishedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = uuid.NullUUID{UUID: after.ID, Valid: true}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, string(filter.Type), since, afterPublishedAt, afterID, limit)
+	if err != nil {
+		r.logger.Error("Failed to list changelog entries", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.ChangelogEntry
+	for rows.Next() {
+		var entry domain.ChangelogEntry
+		var apiVersion sql.NullString
+		var effectiveAt, endsAt sql.NullTime
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Type,
+			&entry.Title,
+			&entry.Description,
+			&apiVersion,
+			pq.Array(&entry.Affects),
+			&effectiveAt,
+			&endsAt,
+			&entry.PublishedBy,
+			&entry.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		if apiVersion.Valid {
+			entry.APIVersion = &apiVersion.String
+		}
+		if effectiveAt.Valid {
+			entry.EffectiveAt = &effectiveAt.Time
+		}
+		if endsAt.Valid {
+			entry.EndsAt = &endsAt.Time
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
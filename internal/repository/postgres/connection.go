@@ -1,26 +1,91 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/config"
 )
 
-// NewConnection creates a new PostgreSQL database connection
+// NewConnection creates a new PostgreSQL database connection. The credentials are read for
+// every new connection, so a password rotated in the secrets backend is picked up as the pool
+// replaces its connections (at most ConnMaxLifetime later).
 func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
-	)
+	return open(credentialsConnector{cfg: cfg})
+}
+
+// NewReplicaConnection connects to the read replica, or returns nil when none is configured
+func NewReplicaConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	if cfg.ReplicaDSN == "" {
+		return nil, nil
+	}
+
+	connector, err := pq.NewConnector(cfg.ReplicaDSN)
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+	db, err := open(connector)
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+	return db, nil
+}
+
+// credentialsConnector connects with the current database credentials
+type credentialsConnector struct {
+	cfg config.DatabaseConfig
+}
 
-	db, err := sql.Open("postgres", dsn)
+func (c credentialsConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := pq.NewConnector(credentialsDSN(c.cfg))
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
+	return connector.Connect(ctx)
+}
+
+func (c credentialsConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// credentialsDSN returns the primary's DSN with the current database credentials
+func credentialsDSN(cfg config.DatabaseConfig) string {
+	user, password := cfg.Credentials()
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=UTC",
+		cfg.Host, cfg.Port, quoteDSNValue(user), quoteDSNValue(password), cfg.DBName, cfg.SSLMode,
+	)
+}
+
+// NewListener opens a dedicated connection to the primary for LISTEN/NOTIFY. It reconnects
+// on its own, with the credentials current when it was created, and sends a nil notification
+// after each reconnect since notifications may have been missed meanwhile.
+func NewListener(cfg config.DatabaseConfig, logger *zap.Logger) *pq.Listener {
+	return pq.NewListener(credentialsDSN(cfg), time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			logger.Warn("Database listener connection lost", zap.Error(err))
+		case pq.ListenerEventReconnected:
+			logger.Info("Database listener reconnected")
+		}
+	})
+}
+
+// quoteDSNValue quotes a DSN value, since generated passwords may contain spaces or quotes
+func quoteDSNValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+func open(connector driver.Connector) (*sql.DB, error) {
+	db := sql.OpenDB(connector)
 
 	// Configure connection pool
 	db.SetMaxOpenConns(25)
@@ -29,6 +94,7 @@ func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
 
 	// Test connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
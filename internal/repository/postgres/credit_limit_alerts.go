@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type creditLimitAlertRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewCreditLimitAlertRepository creates a new credit limit alert repository
+func NewCreditLimitAlertRepository(db *sql.DB, logger *zap.Logger) *creditLimitAlertRepository {
+	return &creditLimitAlertRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *creditLimitAlertRepository) GetThreshold(ctx context.Context, partnerID uuid.UUID) (int, error) {
+	query := `SELECT threshold FROM credit_limit_alerts WHERE partner_id = $1`
+
+	var threshold int
+	err := r.db.QueryRowContext(ctx, query, partnerID).Scan(&threshold)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to get credit limit alert threshold", zap.Error(err))
+		return 0, err
+	}
+	return threshold, nil
+}
+
+func (r *creditLimitAlertRepository) SetThreshold(ctx context.Context, partnerID uuid.UUID, from, to int) (bool, error) {
+	query := `
+		INSERT INTO credit_limit_alerts (partner_id, threshold, alerted_at)
+		VALUES ($1, $3, $4)
+		ON CONFLICT (partner_id) DO UPDATE
+		SET threshold = EXCLUDED.threshold, alerted_at = EXCLUDED.alerted_at
+		WHERE credit_limit_alerts.threshold = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, partnerID, from, to, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to set credit limit alert threshold", zap.Error(err))
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
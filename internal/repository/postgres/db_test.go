@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testDB connects to TEST_DATABASE_URL with a freshly migrated schema of its own, dropped
+// when the test ends. Tests and benchmarks that need a database are skipped without it.
+// timeZone sets the session time zone (empty keeps the server's).
+func testDB(tb testing.TB, timeZone string) *sql.DB {
+	tb.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		tb.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		tb.Fatalf("Failed to connect: %v", err)
+	}
+	defer admin.Close()
+	schema := "test_" + strings.ReplaceAll(uuid.NewString()[:8], "-", "")
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		tb.Fatalf("Failed to create schema: %v", err)
+	}
+
+	// The schema comes first on the search path, so extensions installed in public stay visible
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		tb.Fatalf("Invalid TEST_DATABASE_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	if timeZone != "" {
+		query.Set("timezone", timeZone)
+	}
+	u.RawQuery = query.Encode()
+	db, err := sql.Open("postgres", u.String())
+	if err != nil {
+		tb.Fatalf("Failed to connect: %v", err)
+	}
+	tb.Cleanup(func() {
+		if _, err := db.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			tb.Errorf("Failed to drop schema %s: %v", schema, err)
+		}
+		db.Close()
+	})
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "..", "migrations", "*.up.sql"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		migration, err := os.ReadFile(file)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			tb.Fatalf("Failed to migrate %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
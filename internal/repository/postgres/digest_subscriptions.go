@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type digestSubscriptionRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewDigestSubscriptionRepository creates a new digest subscription repository
+func NewDigestSubscriptionRepository(db *sql.DB, logger *zap.Logger) *digestSubscriptionRepository {
+	return &digestSubscriptionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *digestSubscriptionRepository) Upsert(ctx context.Context, subscription *domain.DigestSubscription) error {
+	query := `
+		INSERT INTO partner_digest_subscriptions (partner_id, email, send_time, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (partner_id) DO UPDATE
+		SET email = EXCLUDED.email, send_time = EXCLUDED.send_time, updated_at = EXCLUDED.updated_at
+		RETURNING last_sent_at, created_at, updated_at
+	`
+
+	var lastSentAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query,
+		subscription.PartnerID,
+		subscription.Email,
+		subscription.SendTime,
+		time.Now(),
+	).Scan(&lastSentAt, &subscription.CreatedAt, &subscription.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to upsert digest subscription", zap.Error(err))
+		return err
+	}
+
+	subscription.LastSentAt = nil
+	if lastSentAt.Valid {
+		subscription.LastSentAt = &lastSentAt.Time
+	}
+	return nil
+}
+
+func (r *digestSubscriptionRepository) GetByPartnerID(ctx context.Context, partnerID uuid.UUID) (*domain.DigestSubscription, error) {
+	query := `
+		SELECT partner_id, email, send_time, last_sent_at, created_at, updated_at
+		FROM partner_digest_subscriptions
+		WHERE partner_id = $1
+	`
+
+	subscription, err := r.scanSubscription(r.db.QueryRowContext(ctx, query, partnerID))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "digest subscription", ID: partnerID.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get digest subscription", zap.Error(err))
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *digestSubscriptionRepository) Delete(ctx context.Context, partnerID uuid.UUID) error {
+	query := `DELETE FROM partner_digest_subscriptions WHERE partner_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to delete digest subscription", zap.Error(err))
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &errors.ErrNotFound{Resource: "digest subscription", ID: partnerID.String()}
+	}
+
+	return nil
+}
+
+func (r *digestSubscriptionRepository) List(ctx context.Context) ([]*domain.DigestSubscription, error) {
+	query := `
+		SELECT s.partner_id, s.email, s.send_time, s.last_sent_at, s.created_at, s.updated_at
+		FROM partner_digest_subscriptions s
+		JOIN partners p ON p.id = s.partner_id
+		WHERE p.is_active = true
+		ORDER BY s.partner_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list digest subscriptions", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.DigestSubscription
+	for rows.Next() {
+		subscription, err := r.scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *digestSubscriptionRepository) ClaimSend(ctx context.Context, partnerID uuid.UUID, due, sentAt time.Time) (bool, error) {
+	query := `
+		UPDATE partner_digest_subscriptions
+		SET last_sent_at = $3
+		WHERE partner_id = $1 AND (last_sent_at IS NULL OR last_sent_at < $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, partnerID, due, sentAt)
+	if err != nil {
+		r.logger.Error("Failed to claim digest send", zap.Error(err))
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+func (r *digestSubscriptionRepository) scanSubscription(row rowScanner) (*domain.DigestSubscription, error) {
+	var subscription domain.DigestSubscription
+	var lastSentAt sql.NullTime
+
+	err := row.Scan(
+		&subscription.PartnerID,
+		&subscription.Email,
+		&subscription.SendTime,
+		&lastSentAt,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastSentAt.Valid {
+		subscription.LastSentAt = &lastSentAt.Time
+	}
+	return &subscription, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type exportJobRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewExportJobRepository creates a new export job repository
+func NewExportJobRepository(db *sql.DB, logger *zap.Logger) *exportJobRepository {
+	return &exportJobRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// exportJobColumns leaves out content, which only GetContent reads
+const exportJobColumns = `
+	id, type, params, status, content_type, filename, row_count, error,
+	requested_by, created_at, completed_at, expires_at
+`
+
+func (r *exportJobRepository) Create(ctx context.Context, job *domain.ExportJob) error {
+	query := `
+		INSERT INTO export_jobs (id, type, params, status, requested_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	job.Status = domain.ExportStatusRunning
+
+	params, err := json.Marshal(job.Params)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.QueryRowContext(ctx, query,
+		job.ID,
+		job.Type,
+		params,
+		job.Status,
+		job.RequestedBy,
+		job.ExpiresAt,
+	).Scan(&job.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create export job", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *exportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	query := `SELECT ` + exportJobColumns + ` FROM export_jobs WHERE id = $1`
+
+	var job domain.ExportJob
+	var params []byte
+	var contentType, filename, jobError sql.NullString
+	var completedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&job.ID,
+		&job.Type,
+		&params,
+		&job.Status,
+		&contentType,
+		&filename,
+		&job.RowCount,
+		&jobError,
+		&job.RequestedBy,
+		&job.CreatedAt,
+		&completedAt,
+		&job.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "export", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get export job", zap.Error(err))
+		return nil, err
+	}
+
+	if err := json.Unmarshal(params, &job.Params); err != nil {
+		return nil, err
+	}
+	if contentType.Valid {
+		job.ContentType = &contentType.String
+	}
+	if filename.Valid {
+		job.Filename = &filename.String
+	}
+	if jobError.Valid {
+		job.Error = &jobError.String
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+	return &job, nil
+}
+
+func (r *exportJobRepository) GetContent(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	query := `SELECT content FROM export_jobs WHERE id = $1 AND status = $2`
+
+	var content []byte
+	err := r.db.QueryRowContext(ctx, query, id, domain.ExportStatusCompleted).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "export", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get export content", zap.Error(err))
+		return nil, err
+	}
+	return content, nil
+}
+
+func (r *exportJobRepository) Complete(ctx context.Context, id uuid.UUID, content []byte, contentType, filename string, rowCount int, completedAt time.Time) error {
+	query := `
+		UPDATE export_jobs
+		SET status = $2, content = $3, content_type = $4, filename = $5, row_count = $6, completed_at = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, domain.ExportStatusCompleted, content, contentType, filename, rowCount, completedAt)
+	if err != nil {
+		r.logger.Error("Failed to complete export job", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *exportJobRepository) Fail(ctx context.Context, id uuid.UUID, reason string, completedAt time.Time) error {
+	query := `UPDATE export_jobs SET status = $2, error = $3, completed_at = $4 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, domain.ExportStatusFailed, reason, completedAt)
+	if err != nil {
+		r.logger.Error("Failed to mark export job failed", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *exportJobRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE expires_at <= $1`, now)
+	if err != nil {
+		r.logger.Error("Failed to delete expired export jobs", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
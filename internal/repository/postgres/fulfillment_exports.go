@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type fulfillmentExportRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewFulfillmentExportRepository creates a new fulfillment export repository
+func NewFulfillmentExportRepository(db *sql.DB, logger *zap.Logger) *fulfillmentExportRepository {
+	return &fulfillmentExportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *fulfillmentExportRepository) Claim(ctx context.Context, orderIDs []uuid.UUID, fileKey string) ([]uuid.UUID, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO fulfillment_exports (supplier_order_id, file_key)
+		SELECT id, $2 FROM UNNEST($1::uuid[]) AS id
+		ON CONFLICT (supplier_order_id) DO NOTHING
+		RETURNING supplier_order_id
+	`
+
+	ids := make([]string, len(orderIDs))
+	for i, id := range orderIDs {
+		ids[i] = id.String()
+	}
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids), fileKey)
+	if err != nil {
+		r.logger.Error("Failed to claim orders for fulfillment export", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var claimed []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, id)
+	}
+	return claimed, rows.Err()
+}
+
+func (r *fulfillmentExportRepository) DeleteByFileKey(ctx context.Context, fileKey string) error {
+	query := `DELETE FROM fulfillment_exports WHERE file_key = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, fileKey); err != nil {
+		r.logger.Error("Failed to delete fulfillment exports", zap.Error(err))
+		return err
+	}
+	return nil
+}
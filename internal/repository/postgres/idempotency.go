@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
@@ -77,3 +78,37 @@ func (r *idempotencyKeyRepository) Create(ctx context.Context, key *domain.Idemp
 
 	return nil
 }
+
+func (r *idempotencyKeyRepository) ReassignOrder(ctx context.Context, supplierOrderID, partnerID uuid.UUID) (int64, error) {
+	query := `
+		UPDATE idempotency_keys
+		SET partner_id = $2
+		WHERE supplier_order_id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, supplierOrderID, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to reassign idempotency keys", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteCreatedBefore deletes up to limit keys created before the cutoff and returns how many were deleted
+func (r *idempotencyKeyRepository) DeleteCreatedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE key IN (
+			SELECT key FROM idempotency_keys
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		r.logger.Error("Failed to delete idempotency keys", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
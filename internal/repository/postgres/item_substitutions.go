@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type itemSubstitutionRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewItemSubstitutionRepository creates a new item substitution repository
+func NewItemSubstitutionRepository(db *sql.DB, logger *zap.Logger) *itemSubstitutionRepository {
+	return &itemSubstitutionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const itemSubstitutionColumns = `
+	id, supplier_order_id, supplier_order_item_id, original_sku, original_price,
+	sku, title, shopify_variant_id, price, note, status, decline_reason, created_at, responded_at
+`
+
+func (r *itemSubstitutionRepository) Create(ctx context.Context, substitution *domain.ItemSubstitution) (bool, error) {
+	query := `
+		INSERT INTO item_substitutions (
+			id, supplier_order_id, supplier_order_item_id, original_sku, original_price,
+			sku, title, shopify_variant_id, price, note, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (supplier_order_item_id) WHERE status = 'PROPOSED' DO NOTHING
+		RETURNING created_at
+	`
+
+	if substitution.ID == uuid.Nil {
+		substitution.ID = uuid.New()
+	}
+	substitution.Status = domain.SubstitutionStatusProposed
+
+	err := r.db.QueryRowContext(ctx, query,
+		substitution.ID,
+		substitution.SupplierOrderID,
+		substitution.SupplierOrderItemID,
+		substitution.OriginalSKU,
+		substitution.OriginalPrice,
+		substitution.SKU,
+		substitution.Title,
+		substitution.ShopifyVariantID,
+		substitution.Price,
+		substitution.Note,
+		substitution.Status,
+	).Scan(&substitution.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to create item substitution", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *itemSubstitutionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.ItemSubstitution, error) {
+	query := `SELECT ` + itemSubstitutionColumns + ` FROM item_substitutions WHERE id = $1`
+
+	substitution, err := scanItemSubstitution(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "item_substitution", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get item substitution", zap.Error(err))
+		return nil, err
+	}
+	return substitution, nil
+}
+
+func (r *itemSubstitutionRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.ItemSubstitution, error) {
+	query := `
+		SELECT ` + itemSubstitutionColumns + `
+		FROM item_substitutions
+		WHERE supplier_order_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		r.logger.Error("Failed to list item substitutions", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var substitutions []*domain.ItemSubstitution
+	for rows.Next() {
+		substitution, err := scanItemSubstitution(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan item substitution", zap.Error(err))
+			return nil, err
+		}
+		substitutions = append(substitutions, substitution)
+	}
+	return substitutions, rows.Err()
+}
+
+func (r *itemSubstitutionRepository) Accept(ctx context.Context, id uuid.UUID, respondedAt time.Time) (bool, error) {
+	// The item and the order total change in the same statement as the proposal, so an
+	// accepted substitution is never half applied
+	query := `
+		WITH accepted AS (
+			UPDATE item_substitutions
+			SET status = 'ACCEPTED', responded_at = $2
+			WHERE id = $1 AND status = 'PROPOSED'
+			RETURNING supplier_order_id, supplier_order_item_id, sku, title, shopify_variant_id, price
+		),
+		item AS (
+			UPDATE supplier_order_items i
+			SET sku = a.sku, title = a.title, shopify_variant_id = a.shopify_variant_id,
+				price = a.price, list_price = a.price, discount = 0,
+				is_supplier_item = TRUE, product_url = NULL, match_method = NULL
+			FROM accepted a, supplier_order_items old
+			WHERE i.id = a.supplier_order_item_id AND old.id = i.id
+			RETURNING i.supplier_order_id, (a.price - old.price) * i.quantity AS delta
+		)
+		UPDATE supplier_orders o
+		SET cart_total = o.cart_total + item.delta, updated_at = CURRENT_TIMESTAMP
+		FROM item
+		WHERE o.id = item.supplier_order_id
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, respondedAt)
+	if err != nil {
+		r.logger.Error("Failed to accept item substitution", zap.Error(err))
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *itemSubstitutionRepository) Decline(ctx context.Context, id uuid.UUID, reason *string, respondedAt time.Time) (bool, error) {
+	query := `
+		UPDATE item_substitutions
+		SET status = 'DECLINED', decline_reason = $2, responded_at = $3
+		WHERE id = $1 AND status = 'PROPOSED'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, reason, respondedAt)
+	if err != nil {
+		r.logger.Error("Failed to decline item substitution", zap.Error(err))
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func scanItemSubstitution(row interface{ Scan(...interface{}) error }) (*domain.ItemSubstitution, error) {
+	var substitution domain.ItemSubstitution
+	var note, declineReason sql.NullString
+	var respondedAt sql.NullTime
+	err := row.Scan(
+		&substitution.ID,
+		&substitution.SupplierOrderID,
+		&substitution.SupplierOrderItemID,
+		&substitution.OriginalSKU,
+		&substitution.OriginalPrice,
+		&substitution.SKU,
+		&substitution.Title,
+		&substitution.ShopifyVariantID,
+		&substitution.Price,
+		&note,
+		&substitution.Status,
+		&declineReason,
+		&substitution.CreatedAt,
+		&respondedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if note.Valid {
+		substitution.Note = &note.String
+	}
+	if declineReason.Valid {
+		substitution.DeclineReason = &declineReason.String
+	}
+	if respondedAt.Valid {
+		substitution.RespondedAt = &respondedAt.Time
+	}
+	return &substitution, nil
+}
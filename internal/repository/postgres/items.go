@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,15 +14,48 @@ import (
 )
 
 type supplierOrderItemRepository struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
 }
 
 // NewSupplierOrderItemRepository creates a new supplier order item repository
 func NewSupplierOrderItemRepository(db *sql.DB, logger *zap.Logger) *supplierOrderItemRepository {
 	return &supplierOrderItemRepository{
-		db:     db,
-		logger: logger,
+		db:      db,
+		replica: db,
+		logger:  logger,
+	}
+}
+
+// itemColumnCount is the number of columns itemArgs returns values for
+const itemColumnCount = 17
+
+// itemArgs returns the insert values of an item in column order
+func itemArgs(item *domain.SupplierOrderItem) []interface{} {
+	var length, width, height *float64
+	if item.Dimensions != nil {
+		length, width, height = &item.Dimensions.LengthCm, &item.Dimensions.WidthCm, &item.Dimensions.HeightCm
+	}
+	return []interface{}{
+		item.ID,
+		item.SupplierOrderID,
+		item.SKU,
+		item.Title,
+		item.Price,
+		item.Quantity,
+		item.ProductURL,
+		item.IsSupplierItem,
+		item.ShopifyVariantID,
+		item.CreatedAt,
+		item.ListPrice,
+		item.Discount,
+		item.WeightGrams,
+		length,
+		width,
+		height,
+		item.MatchMethod,
 	}
 }
 
@@ -29,9 +63,10 @@ func (r *supplierOrderItemRepository) Create(ctx context.Context, item *domain.S
 	query := `
 		INSERT INTO supplier_order_items (
 			id, supplier_order_id, sku, title, price, quantity,
-			product_url, is_supplier_item, shopify_variant_id, created_at
+			product_url, is_supplier_item, shopify_variant_id, created_at,
+			list_price, discount, weight_grams, length_cm, width_cm, height_cm, match_method
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 	`
 
 	now := time.Now()
@@ -42,18 +77,7 @@ func (r *supplierOrderItemRepository) Create(ctx context.Context, item *domain.S
 		item.CreatedAt = now
 	}
 
-	_, err := r.db.ExecContext(ctx, query,
-		item.ID,
-		item.SupplierOrderID,
-		item.SKU,
-		item.Title,
-		item.Price,
-		item.Quantity,
-		item.ProductURL,
-		item.IsSupplierItem,
-		item.ShopifyVariantID,
-		item.CreatedAt,
-	)
+	_, err := r.db.ExecContext(ctx, query, itemArgs(item)...)
 
 	if err != nil {
 		r.logger.Error("Failed to create supplier order item", zap.Error(err))
@@ -71,19 +95,23 @@ func (r *supplierOrderItemRepository) CreateBatch(ctx context.Context, items []*
 	query := `
 		INSERT INTO supplier_order_items (
 			id, supplier_order_id, sku, title, price, quantity,
-			product_url, is_supplier_item, shopify_variant_id, created_at
+			product_url, is_supplier_item, shopify_variant_id, created_at,
+			list_price, discount, weight_grams, length_cm, width_cm, height_cm, match_method
 		)
 		VALUES `
 
-	args := make([]interface{}, 0, len(items)*10)
+	args := make([]interface{}, 0, len(items)*itemColumnCount)
 	now := time.Now()
 
 	for i, item := range items {
 		if i > 0 {
 			query += ", "
 		}
-		query += fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-			i*10+1, i*10+2, i*10+3, i*10+4, i*10+5, i*10+6, i*10+7, i*10+8, i*10+9, i*10+10)
+		placeholders := make([]string, itemColumnCount)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*itemColumnCount+j+1)
+		}
+		query += "(" + strings.Join(placeholders, ", ") + ")"
 
 		if item.ID == uuid.Nil {
 			item.ID = uuid.New()
@@ -92,18 +120,7 @@ func (r *supplierOrderItemRepository) CreateBatch(ctx context.Context, items []*
 			item.CreatedAt = now
 		}
 
-		args = append(args,
-			item.ID,
-			item.SupplierOrderID,
-			item.SKU,
-			item.Title,
-			item.Price,
-			item.Quantity,
-			item.ProductURL,
-			item.IsSupplierItem,
-			item.ShopifyVariantID,
-			item.CreatedAt,
-		)
+		args = append(args, itemArgs(item)...)
 	}
 
 	_, err := r.db.ExecContext(ctx, query, args...)
@@ -118,7 +135,8 @@ func (r *supplierOrderItemRepository) CreateBatch(ctx context.Context, items []*
 func (r *supplierOrderItemRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.SupplierOrderItem, error) {
 	query := `
 		SELECT id, supplier_order_id, sku, title, price, quantity,
-			product_url, is_supplier_item, shopify_variant_id, created_at
+			product_url, is_supplier_item, shopify_variant_id, created_at,
+			list_price, discount, weight_grams, length_cm, width_cm, height_cm, match_method
 		FROM supplier_order_items
 		WHERE supplier_order_id = $1
 		ORDER BY created_at ASC
@@ -134,8 +152,9 @@ func (r *supplierOrderItemRepository) GetByOrderID(ctx context.Context, orderID
 	var items []*domain.SupplierOrderItem
 	for rows.Next() {
 		var item domain.SupplierOrderItem
-		var productURL sql.NullString
-		var shopifyVariantID sql.NullInt64
+		var productURL, matchMethod sql.NullString
+		var shopifyVariantID, weightGrams sql.NullInt64
+		var length, width, height sql.NullFloat64
 
 		err := rows.Scan(
 			&item.ID,
@@ -148,6 +167,13 @@ func (r *supplierOrderItemRepository) GetByOrderID(ctx context.Context, orderID
 			&item.IsSupplierItem,
 			&shopifyVariantID,
 			&item.CreatedAt,
+			&item.ListPrice,
+			&item.Discount,
+			&weightGrams,
+			&length,
+			&width,
+			&height,
+			&matchMethod,
 		)
 
 		if err != nil {
@@ -160,9 +186,78 @@ func (r *supplierOrderItemRepository) GetByOrderID(ctx context.Context, orderID
 		if shopifyVariantID.Valid {
 			item.ShopifyVariantID = &shopifyVariantID.Int64
 		}
+		if matchMethod.Valid {
+			item.MatchMethod = &matchMethod.String
+		}
+		if weightGrams.Valid {
+			weight := int(weightGrams.Int64)
+			item.WeightGrams = &weight
+		}
+		if length.Valid && width.Valid && height.Valid {
+			item.Dimensions = &domain.Dimensions{LengthCm: length.Float64, WidthCm: width.Float64, HeightCm: height.Float64}
+		}
 
 		items = append(items, &item)
 	}
 
 	return items, rows.Err()
 }
+
+// ListNonSupplierSKUs lists the SKUs of non-supplier items created in [from, to), most
+// frequent first
+func (r *supplierOrderItemRepository) ListNonSupplierSKUs(ctx context.Context, from, to time.Time) ([]*domain.NonSupplierSKU, error) {
+	query := `
+		SELECT sku, COUNT(DISTINCT supplier_order_id), MAX(created_at)
+		FROM supplier_order_items
+		WHERE is_supplier_item = false AND created_at >= $1 AND created_at < $2
+		GROUP BY sku
+		ORDER BY COUNT(DISTINCT supplier_order_id) DESC, sku
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list non-supplier SKUs", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var skus []*domain.NonSupplierSKU
+	for rows.Next() {
+		sku := &domain.NonSupplierSKU{}
+		if err := rows.Scan(&sku.SKU, &sku.Orders, &sku.LastSeenAt); err != nil {
+			return nil, err
+		}
+		skus = append(skus, sku)
+	}
+
+	return skus, rows.Err()
+}
+
+func (r *supplierOrderItemRepository) ListOrderedVariants(ctx context.Context, since time.Time) ([]*domain.OrderedVariant, error) {
+	query := `
+		SELECT o.partner_id, i.sku, i.shopify_variant_id, MAX(o.created_at)
+		FROM supplier_order_items i
+		JOIN supplier_orders o ON o.id = i.supplier_order_id
+		WHERE i.is_supplier_item = true AND i.shopify_variant_id IS NOT NULL AND o.created_at >= $1
+		GROUP BY o.partner_id, i.sku, i.shopify_variant_id
+		ORDER BY o.partner_id, i.sku
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, since)
+	if err != nil {
+		r.logger.Error("Failed to list ordered variants", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []*domain.OrderedVariant
+	for rows.Next() {
+		v := &domain.OrderedVariant{}
+		if err := rows.Scan(&v.PartnerID, &v.SKU, &v.ShopifyVariantID, &v.LastOrderedAt); err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+
+	return variants, rows.Err()
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type lowStockAlertRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewLowStockAlertRepository creates a new low-stock alert repository
+func NewLowStockAlertRepository(db *sql.DB, logger *zap.Logger) *lowStockAlertRepository {
+	return &lowStockAlertRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *lowStockAlertRepository) Record(ctx context.Context, alert *domain.LowStockAlert) (bool, error) {
+	query := `
+		INSERT INTO low_stock_alerts (partner_id, shopify_variant_id, sku, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (partner_id, shopify_variant_id) DO NOTHING
+		RETURNING alerted_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		alert.PartnerID,
+		alert.ShopifyVariantID,
+		alert.SKU,
+		alert.Quantity,
+	).Scan(&alert.AlertedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to record low-stock alert", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *lowStockAlertRepository) DeleteByVariantIDs(ctx context.Context, variantIDs []int64) (int64, error) {
+	if len(variantIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `DELETE FROM low_stock_alerts WHERE shopify_variant_id = ANY($1::bigint[])`
+
+	result, err := r.db.ExecContext(ctx, query, pq.Array(variantIDs))
+	if err != nil {
+		r.logger.Error("Failed to delete low-stock alerts", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -7,21 +7,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
 )
 
 type orderEventRepository struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
 }
 
 // NewOrderEventRepository creates a new order event repository
 func NewOrderEventRepository(db *sql.DB, logger *zap.Logger) *orderEventRepository {
 	return &orderEventRepository{
-		db:     db,
-		logger: logger,
+		db:      db,
+		replica: db,
+		logger:  logger,
 	}
 }
 
@@ -79,6 +83,34 @@ func (r *orderEventRepository) GetByOrderID(ctx context.Context, orderID uuid.UU
 	}
 	defer rows.Close()
 
+	return scanOrderEvents(rows)
+}
+
+// ListLatestByType lists the newest event of the given type per order, for events created
+// in [from, to), newest first
+func (r *orderEventRepository) ListLatestByType(ctx context.Context, eventType string, from, to time.Time) ([]*domain.OrderEvent, error) {
+	query := `
+		SELECT id, supplier_order_id, event_type, event_data, created_at
+		FROM (
+			SELECT DISTINCT ON (supplier_order_id) id, supplier_order_id, event_type, event_data, created_at
+			FROM order_events
+			WHERE event_type = $1 AND created_at >= $2 AND created_at < $3
+			ORDER BY supplier_order_id, created_at DESC
+		) latest
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, eventType, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list order events by type", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrderEvents(rows)
+}
+
+func scanOrderEvents(rows *sql.Rows) ([]*domain.OrderEvent, error) {
 	var events []*domain.OrderEvent
 	for rows.Next() {
 		var event domain.OrderEvent
@@ -107,3 +139,146 @@ func (r *orderEventRepository) GetByOrderID(ctx context.Context, orderID uuid.UU
 
 	return events, rows.Err()
 }
+
+// ListStatusChangesByPartnerID lists status changes of a partner's orders in [from, to), oldest first
+func (r *orderEventRepository) ListStatusChangesByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time) ([]*domain.OrderStatusChange, error) {
+	query := `
+		SELECT o.id, o.partner_order_id, o.reference, e.event_data->>'from', e.event_data->>'to', e.created_at
+		FROM order_events e
+		JOIN supplier_orders o ON o.id = e.supplier_order_id
+		WHERE o.partner_id = $1 AND e.event_type = 'status_change'
+			AND e.created_at >= $2 AND e.created_at < $3
+		ORDER BY e.created_at ASC
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, partnerID, from, to)
+	if err != nil {
+		r.logger.Error("Failed to list status changes by partner ID", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*domain.OrderStatusChange
+	for rows.Next() {
+		var change domain.OrderStatusChange
+		var reference, fromStatus, toStatus sql.NullString
+
+		if err := rows.Scan(
+			&change.OrderID,
+			&change.PartnerOrderID,
+			&reference,
+			&fromStatus,
+			&toStatus,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if reference.Valid {
+			change.Reference = &reference.String
+		}
+		change.From = domain.OrderStatus(fromStatus.String)
+		change.To = domain.OrderStatus(toStatus.String)
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+// DeleteCreatedBefore deletes up to limit events created before the cutoff whose order is in
+// one of the given statuses, and returns how many were deleted
+func (r *orderEventRepository) DeleteCreatedBefore(ctx context.Context, before time.Time, statuses []domain.OrderStatus, limit int) (int64, error) {
+	query := `
+		DELETE FROM order_events
+		WHERE id IN (
+			SELECT e.id FROM order_events e
+			JOIN supplier_orders o ON o.id = e.supplier_order_id
+			WHERE e.created_at < $1 AND o.status = ANY($2)
+			LIMIT $3
+		)
+	`
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, before, pq.Array(statusValues), limit)
+	if err != nil {
+		r.logger.Error("Failed to delete order events", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListCreatedBefore lists up to limit events created before the cutoff whose order is in one
+// of the given statuses, oldest first
+func (r *orderEventRepository) ListCreatedBefore(ctx context.Context, before time.Time, statuses []domain.OrderStatus, limit int) ([]*domain.OrderEvent, error) {
+	query := `
+		SELECT e.id, e.supplier_order_id, e.event_type, e.event_data, e.created_at
+		FROM order_events e
+		JOIN supplier_orders o ON o.id = e.supplier_order_id
+		WHERE e.created_at < $1 AND o.status = ANY($2)
+		ORDER BY e.created_at ASC
+		LIMIT $3
+	`
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, before, pq.Array(statusValues), limit)
+	if err != nil {
+		r.logger.Error("Failed to list order events by age", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrderEvents(rows)
+}
+
+func (r *orderEventRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	idValues := make([]string, len(ids))
+	for i, id := range ids {
+		idValues[i] = id.String()
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM order_events WHERE id = ANY($1::uuid[])`, pq.Array(idValues))
+	if err != nil {
+		r.logger.Error("Failed to delete order events", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Restore inserts archived events back, skipping ones that are still present, and returns
+// how many were inserted
+func (r *orderEventRepository) Restore(ctx context.Context, events []*domain.OrderEvent) (int64, error) {
+	query := `
+		INSERT INTO order_events (id, supplier_order_id, event_type, event_data, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	var restored int64
+	for _, event := range events {
+		var eventDataJSON []byte
+		if event.EventData != nil {
+			var err error
+			if eventDataJSON, err = json.Marshal(event.EventData); err != nil {
+				return restored, err
+			}
+		}
+
+		result, err := r.db.ExecContext(ctx, query,
+			event.ID, event.SupplierOrderID, event.EventType, eventDataJSON, event.CreatedAt)
+		if err != nil {
+			r.logger.Error("Failed to restore order event", zap.Error(err))
+			return restored, err
+		}
+		inserted, _ := result.RowsAffected()
+		restored += inserted
+	}
+	return restored, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// rollupDate formats t as the UTC date the rollup queries compare days with; passing dates
+// as text keeps the session time zone out of the day boundaries
+func rollupDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+type orderRollupRepository struct {
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
+}
+
+// NewOrderRollupRepository creates a new order rollup repository
+func NewOrderRollupRepository(db *sql.DB, logger *zap.Logger) *orderRollupRepository {
+	return &orderRollupRepository{
+		db:      db,
+		replica: db,
+		logger:  logger,
+	}
+}
+
+// Rebuild recomputes the days in one statement, like webhook subscriptions are replaced:
+// reports never see a day half rebuilt. Rows of partners or statuses a day no longer has
+// are removed.
+func (r *orderRollupRepository) Rebuild(ctx context.Context, from, to time.Time) (int64, error) {
+	query := `
+		WITH fresh AS (
+			SELECT (created_at AT TIME ZONE 'UTC')::date AS day, partner_id, status,
+				COUNT(*) AS orders,
+				COUNT(confirmed_at) AS confirmed,
+				COUNT(shipped_at) AS shipped,
+				COUNT(delivered_at) AS delivered,
+				COUNT(rejected_at) AS rejected,
+				COUNT(cancelled_at) AS cancelled,
+				SUM(cart_total) AS revenue
+			FROM supplier_orders
+			WHERE created_at >= $1 AND created_at < $2
+			GROUP BY 1, 2, 3
+		),
+		removed AS (
+			DELETE FROM order_daily_rollups r
+			WHERE r.day >= $3::date AND r.day < $4::date
+				AND NOT EXISTS (
+					SELECT 1 FROM fresh f
+					WHERE f.day = r.day AND f.partner_id = r.partner_id AND f.status = r.status
+				)
+		),
+		marked AS (
+			INSERT INTO order_rollup_days (day, rolled_up_at)
+			SELECT d::date, CURRENT_TIMESTAMP
+			FROM generate_series($3::date, $4::date - 1, interval '1 day') AS d
+			ON CONFLICT (day) DO UPDATE SET rolled_up_at = EXCLUDED.rolled_up_at
+		)
+		INSERT INTO order_daily_rollups (
+			day, partner_id, status, orders, confirmed, shipped, delivered, rejected, cancelled, revenue
+		)
+		SELECT day, partner_id, status, orders, confirmed, shipped, delivered, rejected, cancelled, revenue
+		FROM fresh
+		ON CONFLICT (day, partner_id, status) DO UPDATE
+		SET orders = EXCLUDED.orders,
+			confirmed = EXCLUDED.confirmed,
+			shipped = EXCLUDED.shipped,
+			delivered = EXCLUDED.delivered,
+			rejected = EXCLUDED.rejected,
+			cancelled = EXCLUDED.cancelled,
+			revenue = EXCLUDED.revenue
+	`
+
+	result, err := r.db.ExecContext(ctx, query, from, to, rollupDate(from), rollupDate(to))
+	if err != nil {
+		r.logger.Error("Failed to rebuild order rollups", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *orderRollupRepository) CountRolledUpDays(ctx context.Context, from, to time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM order_rollup_days
+		WHERE day >= $1::date AND day < $2::date
+	`
+
+	var count int
+	if err := r.replica.QueryRowContext(ctx, query, rollupDate(from), rollupDate(to)).Scan(&count); err != nil {
+		r.logger.Error("Failed to count rolled up days", zap.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *orderRollupRepository) FunnelStats(ctx context.Context, from, to time.Time) ([]*domain.OrderFunnelStats, error) {
+	query := `
+		SELECT o.partner_id, p.name,
+			SUM(o.orders),
+			SUM(o.confirmed),
+			SUM(o.shipped),
+			SUM(o.delivered),
+			SUM(o.rejected),
+			SUM(o.cancelled),
+			COALESCE(SUM(o.revenue) FILTER (WHERE o.status NOT IN ('REJECTED', 'CANCELLED')), 0)
+		FROM order_daily_rollups o
+		JOIN partners p ON p.id = o.partner_id
+		WHERE o.day >= $1::date AND o.day < $2::date
+		GROUP BY o.partner_id, p.name
+		ORDER BY p.name
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, rollupDate(from), rollupDate(to))
+	if err != nil {
+		r.logger.Error("Failed to get rolled up order funnel stats", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.OrderFunnelStats
+	for rows.Next() {
+		s := &domain.OrderFunnelStats{}
+		if err := rows.Scan(
+			&s.PartnerID,
+			&s.PartnerName,
+			&s.Submitted,
+			&s.Confirmed,
+			&s.Shipped,
+			&s.Delivered,
+			&s.Rejected,
+			&s.Cancelled,
+			&s.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+func (r *orderRollupRepository) FirstOrderDay(ctx context.Context) (*time.Time, error) {
+	query := `SELECT (MIN(created_at) AT TIME ZONE 'UTC')::date FROM supplier_orders`
+
+	var day sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query).Scan(&day); err != nil {
+		r.logger.Error("Failed to get first order day", zap.Error(err))
+		return nil, err
+	}
+	if !day.Valid {
+		return nil, nil
+	}
+	return &day.Time, nil
+}
@@ -4,25 +4,44 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
+// supplierOrderColumns is the column list read by scanOrder, in scan order
+const supplierOrderColumns = `id, partner_id, partner_order_id, reference, status, shopify_draft_order_id, shopify_order_id,
+			customer_name, customer_phone, customer_phone_normalized, customer_email, shopify_customer_id, shipping_address, cart_total,
+			cart_tax, cart_shipping, payment_status, payment_method, locale, rejection_reason, tracking_carrier, tracking_number,
+			tracking_url, hold_reason, held_at, held_from_status, confirmed_at, rejected_at, shipped_at,
+			delivered_at, cancelled_at, created_at, updated_at, channel, rejection_code, content_hash,
+			shopify_draft_order_name, shopify_order_name, held_seconds`
+
+// pendingSince is when an order's confirmation timers started: its creation, moved forward by
+// the time it spent on hold (see domain.SupplierOrder.PendingSince)
+const pendingSince = `(created_at + held_seconds * INTERVAL '1 second')`
+
 type supplierOrderRepository struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
 }
 
 // NewSupplierOrderRepository creates a new supplier order repository
 func NewSupplierOrderRepository(db *sql.DB, logger *zap.Logger) *supplierOrderRepository {
 	return &supplierOrderRepository{
-		db:     db,
-		logger: logger,
+		db:      db,
+		replica: db,
+		logger:  logger,
 	}
 }
 
@@ -32,9 +51,10 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 			id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
 			customer_name, customer_phone, shipping_address, cart_total,
 			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
+			tracking_url, created_at, updated_at, reference, locale, customer_phone_normalized,
+			customer_email, shopify_customer_id, cart_tax, cart_shipping, channel, rejection_code, content_hash
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)
 	`
 
 	now := time.Now()
@@ -47,8 +67,11 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 	if order.UpdatedAt.IsZero() {
 		order.UpdatedAt = now
 	}
+	if order.Locale == "" {
+		order.Locale = domain.DefaultLocale
+	}
 
-	shippingAddressJSON, err := json.Marshal(order.ShippingAddress)
+	shippingAddressJSON, err := marshalAddress(order.ShippingAddress)
 	if err != nil {
 		return err
 	}
@@ -72,6 +95,16 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 		order.TrackingURL,
 		order.CreatedAt,
 		order.UpdatedAt,
+		order.Reference,
+		order.Locale,
+		order.CustomerPhoneE164,
+		order.CustomerEmail,
+		order.ShopifyCustomerID,
+		order.CartTax,
+		order.CartShipping,
+		order.Channel,
+		order.RejectionCode,
+		order.ContentHash,
 	)
 
 	if err != nil {
@@ -84,47 +117,12 @@ func (r *supplierOrderRepository) Create(ctx context.Context, order *domain.Supp
 
 func (r *supplierOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
+		SELECT ` + supplierOrderColumns + `
 		FROM supplier_orders
 		WHERE id = $1
 	`
 
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderID sql.NullInt64
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderID,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: id.String()}
 	}
@@ -133,84 +131,17 @@ func (r *supplierOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 		return nil, err
 	}
 
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderID.Valid {
-		order.ShopifyOrderID = &shopifyOrderID.Int64
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
-	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
-	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
-	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
-	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
-	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
-	}
-
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
+	return order, nil
 }
 
 func (r *supplierOrderRepository) GetByPartnerIDAndPartnerOrderID(ctx context.Context, partnerID uuid.UUID, partnerOrderID string) (*domain.SupplierOrder, error) {
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
+		SELECT ` + supplierOrderColumns + `
 		FROM supplier_orders
 		WHERE partner_id = $1 AND partner_order_id = $2
 	`
 
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderID sql.NullInt64
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, partnerID, partnerOrderID).Scan(
-		&order.ID,
-		&order.PartnerID,
-		&order.PartnerOrderID,
-		&order.Status,
-		&shopifyDraftOrderID,
-		&shopifyOrderID,
-		&order.CustomerName,
-		&customerPhone,
-		&shippingAddressJSON,
-		&order.CartTotal,
-		&paymentStatus,
-		&paymentMethod,
-		&rejectionReason,
-		&trackingCarrier,
-		&trackingNumber,
-		&trackingURL,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
-
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, partnerID, partnerOrderID))
 	if err == sql.ErrNoRows {
 		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: partnerOrderID}
 	}
@@ -219,39 +150,95 @@ func (r *supplierOrderRepository) GetByPartnerIDAndPartnerOrderID(ctx context.Co
 		return nil, err
 	}
 
-	if shopifyDraftOrderID.Valid {
-		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
-	}
-	if shopifyOrderID.Valid {
-		order.ShopifyOrderID = &shopifyOrderID.Int64
-	}
-	if customerPhone.Valid {
-		order.CustomerPhone = customerPhone.String
-	}
-	if paymentStatus.Valid {
-		order.PaymentStatus = paymentStatus.String
+	return order, nil
+}
+
+// ListByPartnerIDAndIDs reads from the primary: partners syncing statuses expect the latest ones
+func (r *supplierOrderRepository) ListByPartnerIDAndIDs(ctx context.Context, partnerID uuid.UUID, ids []uuid.UUID) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE partner_id = $1 AND id = ANY($2::uuid[])
+	`
+
+	idValues := make([]string, len(ids))
+	for i, id := range ids {
+		idValues[i] = id.String()
 	}
-	if paymentMethod.Valid {
-		order.PaymentMethod = &paymentMethod.String
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID, pq.Array(idValues))
+	if err != nil {
+		r.logger.Error("Failed to list supplier orders by IDs", zap.Error(err))
+		return nil, err
 	}
-	if rejectionReason.Valid {
-		order.RejectionReason = &rejectionReason.String
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+func (r *supplierOrderRepository) ListByPartnerIDAndPartnerOrderIDs(ctx context.Context, partnerID uuid.UUID, partnerOrderIDs []string) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE partner_id = $1 AND partner_order_id = ANY($2)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID, pq.Array(partnerOrderIDs))
+	if err != nil {
+		r.logger.Error("Failed to list supplier orders by partner order IDs", zap.Error(err))
+		return nil, err
 	}
-	if trackingCarrier.Valid {
-		order.TrackingCarrier = &trackingCarrier.String
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+func (r *supplierOrderRepository) GetByReference(ctx context.Context, reference string) (*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE reference = $1
+	`
+
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, reference))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: reference}
 	}
-	if trackingNumber.Valid {
-		order.TrackingNumber = &trackingNumber.String
+	if err != nil {
+		r.logger.Error("Failed to get supplier order by reference", zap.Error(err))
+		return nil, err
 	}
-	if trackingURL.Valid {
-		order.TrackingURL = &trackingURL.String
+
+	return order, nil
+}
+
+// NextReferenceNumber draws the next number for a human-friendly order reference
+func (r *supplierOrderRepository) NextReferenceNumber(ctx context.Context) (int64, error) {
+	var n int64
+	if err := r.db.QueryRowContext(ctx, `SELECT nextval('supplier_order_reference_seq')`).Scan(&n); err != nil {
+		r.logger.Error("Failed to get next order reference number", zap.Error(err))
+		return 0, err
 	}
+	return n, nil
+}
+
+func (r *supplierOrderRepository) GetByShopifyOrderID(ctx context.Context, shopifyOrderID int64) (*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE shopify_order_id = $1
+	`
 
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, shopifyOrderID))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: strconv.FormatInt(shopifyOrderID, 10)}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get supplier order by Shopify order ID", zap.Error(err))
 		return nil, err
 	}
 
-	return &order, nil
+	return order, nil
 }
 
 func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.SupplierOrder) error {
@@ -260,12 +247,13 @@ func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.Supp
 		SET status = $2, shopify_draft_order_id = $3, customer_name = $4,
 			customer_phone = $5, shipping_address = $6, cart_total = $7,
 			payment_status = $8, payment_method = $9, rejection_reason = $10, tracking_carrier = $11,
-			tracking_number = $12, tracking_url = $13, updated_at = $14
+			tracking_number = $12, tracking_url = $13, updated_at = $14, customer_phone_normalized = $15,
+			customer_email = $16, rejection_code = $17
 		WHERE id = $1
 	`
 
 	order.UpdatedAt = time.Now()
-	shippingAddressJSON, err := json.Marshal(order.ShippingAddress)
+	shippingAddressJSON, err := marshalAddress(order.ShippingAddress)
 	if err != nil {
 		return err
 	}
@@ -285,6 +273,9 @@ func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.Supp
 		order.TrackingNumber,
 		order.TrackingURL,
 		order.UpdatedAt,
+		order.CustomerPhoneE164,
+		order.CustomerEmail,
+		order.RejectionCode,
 	)
 
 	if err != nil {
@@ -295,14 +286,19 @@ func (r *supplierOrderRepository) Update(ctx context.Context, order *domain.Supp
 	return nil
 }
 
-func (r *supplierOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string) error {
+// UpdateStatus sets the status and stamps the matching <status>_at column with changedAt
+func (r *supplierOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string, changedAt time.Time) error {
 	query := `
 		UPDATE supplier_orders
-		SET status = $2, rejection_reason = $3, updated_at = $4
+		SET status = $2, rejection_reason = $3, updated_at = $4,
+			confirmed_at = CASE WHEN $2 = 'CONFIRMED' THEN $4 ELSE confirmed_at END,
+			rejected_at = CASE WHEN $2 = 'REJECTED' THEN $4 ELSE rejected_at END,
+			delivered_at = CASE WHEN $2 = 'DELIVERED' THEN $4 ELSE delivered_at END,
+			cancelled_at = CASE WHEN $2 = 'CANCELLED' THEN $4 ELSE cancelled_at END
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, id, status, rejectionReason, time.Now())
+	_, err := r.db.ExecContext(ctx, query, id, status, rejectionReason, changedAt)
 	if err != nil {
 		r.logger.Error("Failed to update supplier order status", zap.Error(err))
 		return err
@@ -311,155 +307,946 @@ func (r *supplierOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID
 	return nil
 }
 
-func (r *supplierOrderRepository) UpdateTracking(ctx context.Context, id uuid.UUID, carrier, trackingNumber, trackingURL *string) error {
+func (r *supplierOrderRepository) Reject(ctx context.Context, id uuid.UUID, code domain.RejectionCode, reason *string, rejectedAt time.Time) error {
 	query := `
 		UPDATE supplier_orders
-		SET tracking_carrier = $2, tracking_number = $3, tracking_url = $4,
-			status = $5, updated_at = $6
+		SET status = $2, rejection_code = $3, rejection_reason = $4, updated_at = $5, rejected_at = $5
 		WHERE id = $1
 	`
 
-	_, err := r.db.ExecContext(ctx, query, id, carrier, trackingNumber, trackingURL, domain.OrderStatusShipped, time.Now())
+	_, err := r.db.ExecContext(ctx, query, id, domain.OrderStatusRejected, code, reason, rejectedAt)
 	if err != nil {
-		r.logger.Error("Failed to update supplier order tracking", zap.Error(err))
+		r.logger.Error("Failed to reject supplier order", zap.Error(err))
 		return err
 	}
 
 	return nil
 }
 
-func (r *supplierOrderRepository) UpdateShopifyDraftOrderID(ctx context.Context, id uuid.UUID, draftOrderID int64) error {
+func (r *supplierOrderRepository) ExpirePending(ctx context.Context, id uuid.UUID, createdBefore, cancelledAt time.Time) (bool, error) {
 	query := `
 		UPDATE supplier_orders
-		SET shopify_draft_order_id = $2, updated_at = $3
-		WHERE id = $1
+		SET status = $2, updated_at = $5, cancelled_at = $5
+		WHERE id = $1 AND status = $3 AND ` + pendingSince + ` < $4 AND shopify_order_id IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, query, id, draftOrderID, time.Now())
+	result, err := r.db.ExecContext(ctx, query, id, domain.OrderStatusCancelled, domain.OrderStatusPendingConfirmation, createdBefore, cancelledAt)
 	if err != nil {
-		r.logger.Error("Failed to update Shopify draft order ID", zap.Error(err))
-		return err
+		r.logger.Error("Failed to expire pending supplier order", zap.Error(err))
+		return false, err
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
-func (r *supplierOrderRepository) UpdateShopifyOrderID(ctx context.Context, id uuid.UUID, orderID int64) error {
+func (r *supplierOrderRepository) Reassign(ctx context.Context, id, fromPartnerID, toPartnerID uuid.UUID) (bool, error) {
 	query := `
 		UPDATE supplier_orders
-		SET shopify_order_id = $2, updated_at = $3
-		WHERE id = $1
+		SET partner_id = $3, updated_at = $4
+		WHERE id = $1 AND partner_id = $2
 	`
 
-	_, err := r.db.ExecContext(ctx, query, id, orderID, time.Now())
+	result, err := r.db.ExecContext(ctx, query, id, fromPartnerID, toPartnerID, time.Now())
 	if err != nil {
-		r.logger.Error("Failed to update Shopify order ID", zap.Error(err))
-		return err
+		r.logger.Error("Failed to reassign supplier order", zap.Error(err))
+		return false, err
 	}
 
-	return nil
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
-func (r *supplierOrderRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*domain.SupplierOrder, error) {
+// UpdateStatusWithTracking locks the order, checks it is still in fromStatus and sets the
+// status, tracking fields and shipped_at in a single UPDATE, all in one transaction
+func (r *supplierOrderRepository) UpdateStatusWithTracking(ctx context.Context, id uuid.UUID, fromStatus, status domain.OrderStatus, carrier, trackingNumber, trackingURL *string, shippedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("Failed to begin supplier order tracking update", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	var current domain.OrderStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM supplier_orders WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return &errors.ErrNotFound{Resource: "supplier_order", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to lock supplier order for tracking update", zap.Error(err))
+		return err
+	}
+	// Changed since the caller checked the transition (e.g. cancelled meanwhile)
+	if current != fromStatus {
+		return &errors.ErrInvalidStateTransition{From: current, To: status}
+	}
+
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
-		FROM supplier_orders
-		WHERE partner_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		UPDATE supplier_orders
+		SET tracking_carrier = $2, tracking_number = $3, tracking_url = $4,
+			status = $5, updated_at = $6, shipped_at = $6
+		WHERE id = $1
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, partnerID, limit, offset)
-	if err != nil {
-		r.logger.Error("Failed to list supplier orders by partner ID", zap.Error(err))
-		return nil, err
+	if _, err := tx.ExecContext(ctx, query, id, carrier, trackingNumber, trackingURL, status, shippedAt); err != nil {
+		r.logger.Error("Failed to update supplier order tracking", zap.Error(err))
+		return err
 	}
-	defer rows.Close()
 
-	var orders []*domain.SupplierOrder
-	for rows.Next() {
-		order, err := r.scanOrder(rows)
-		if err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
+	if err := tx.Commit(); err != nil {
+		r.logger.Error("Failed to commit supplier order tracking update", zap.Error(err))
+		return err
 	}
 
-	return orders, rows.Err()
+	return nil
 }
 
-func (r *supplierOrderRepository) ListByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+// Hold puts the order on hold if it is still in fromStatus; otherwise it returns
+// ErrInvalidStateTransition and changes nothing
+func (r *supplierOrderRepository) Hold(ctx context.Context, id uuid.UUID, fromStatus domain.OrderStatus, reason string) error {
 	query := `
-		SELECT id, partner_id, partner_order_id, status, shopify_draft_order_id, shopify_order_id,
-			customer_name, customer_phone, shipping_address, cart_total,
-			payment_status, payment_method, rejection_reason, tracking_carrier, tracking_number,
-			tracking_url, created_at, updated_at
-		FROM supplier_orders
-		WHERE status = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		UPDATE supplier_orders
+		SET status = $2, held_from_status = $3, hold_reason = $4, held_at = $5, updated_at = $5
+		WHERE id = $1 AND status = $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	result, err := r.db.ExecContext(ctx, query, id, domain.OrderStatusOnHold, fromStatus, reason, time.Now())
 	if err != nil {
-		r.logger.Error("Failed to list supplier orders by status", zap.Error(err))
-		return nil, err
+		r.logger.Error("Failed to put supplier order on hold", zap.Error(err))
+		return err
 	}
-	defer rows.Close()
 
-	var orders []*domain.SupplierOrder
-	for rows.Next() {
-		order, err := r.scanOrder(rows)
-		if err != nil {
-			return nil, err
-		}
-		orders = append(orders, order)
+	return r.checkTransitioned(ctx, result, id, domain.OrderStatusOnHold)
+}
+
+// Release ends the hold if the order is still on hold; otherwise it returns
+// ErrInvalidStateTransition and changes nothing. The time held is added to held_seconds, so
+// the order's confirmation timers resume where they stopped.
+func (r *supplierOrderRepository) Release(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error {
+	query := `
+		UPDATE supplier_orders
+		SET status = $2,
+			held_seconds = held_seconds + GREATEST(0, COALESCE(EXTRACT(EPOCH FROM ($3 - held_at)), 0))::BIGINT,
+			held_from_status = NULL, hold_reason = NULL, held_at = NULL, updated_at = $3
+		WHERE id = $1 AND status = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, status, time.Now(), domain.OrderStatusOnHold)
+	if err != nil {
+		r.logger.Error("Failed to release supplier order hold", zap.Error(err))
+		return err
 	}
 
-	return orders, rows.Err()
+	return r.checkTransitioned(ctx, result, id, status)
 }
 
-func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrder, error) {
-	var order domain.SupplierOrder
-	var shippingAddressJSON []byte
-	var shopifyDraftOrderID sql.NullInt64
-	var shopifyOrderID sql.NullInt64
-	var customerPhone sql.NullString
-	var paymentStatus sql.NullString
-	var paymentMethod sql.NullString
-	var rejectionReason sql.NullString
-	var trackingCarrier sql.NullString
-	var trackingNumber sql.NullString
-	var trackingURL sql.NullString
+// checkTransitioned turns a status update that matched no row into ErrNotFound, or into
+// ErrInvalidStateTransition from the order's current status when it changed meanwhile
+func (r *supplierOrderRepository) checkTransitioned(ctx context.Context, result sql.Result, id uuid.UUID, to domain.OrderStatus) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
 
-	err := rows.Scan(
+	var current domain.OrderStatus
+	err = r.db.QueryRowContext(ctx, `SELECT status FROM supplier_orders WHERE id = $1`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return &errors.ErrNotFound{Resource: "supplier_order", ID: id.String()}
+	}
+	if err != nil {
+		return err
+	}
+	return &errors.ErrInvalidStateTransition{From: current, To: to}
+}
+
+// UpdateShopifyDraftOrderID links the draft order; an empty name keeps the one stored
+func (r *supplierOrderRepository) UpdateShopifyDraftOrderID(ctx context.Context, id uuid.UUID, draftOrderID int64, name string) error {
+	query := `
+		UPDATE supplier_orders
+		SET shopify_draft_order_id = $2, shopify_draft_order_name = COALESCE(NULLIF($3, ''), shopify_draft_order_name), updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, draftOrderID, name, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to update Shopify draft order ID", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// UpdateShopifyOrderID links the Shopify order; an empty name keeps the one stored
+func (r *supplierOrderRepository) UpdateShopifyOrderID(ctx context.Context, id uuid.UUID, orderID int64, name string) error {
+	query := `
+		UPDATE supplier_orders
+		SET shopify_order_id = $2, shopify_order_name = COALESCE(NULLIF($3, ''), shopify_order_name), updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, orderID, name, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to update Shopify order ID", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *supplierOrderRepository) UpdateShopifyCustomerID(ctx context.Context, id uuid.UUID, customerID int64) error {
+	query := `
+		UPDATE supplier_orders
+		SET shopify_customer_id = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, customerID, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to update Shopify customer ID", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePaymentStatus sets the payment status, keeping the payment method unless a new one is given
+func (r *supplierOrderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatus string, paymentMethod *string) error {
+	query := `
+		UPDATE supplier_orders
+		SET payment_status = $2, payment_method = COALESCE($3, payment_method), updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, paymentStatus, paymentMethod, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to update payment status", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// offsetListQuery selects the orders matching where (placeholders $1 to $len(args)), newest
+// first, one page at an offset
+func offsetListQuery(where string, args []interface{}, limit, offset int) (string, []interface{}) {
+	n := len(args)
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE ` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $` + strconv.Itoa(n+1) + ` OFFSET $` + strconv.Itoa(n+2)
+	return query, append(args, limit, offset)
+}
+
+// keysetListQuery selects the orders matching where (placeholders $1 to $len(args)), newest
+// first, one page after the cursor (nil for the first page)
+func keysetListQuery(where string, args []interface{}, after *domain.OrderCursor, limit int) (string, []interface{}) {
+	if after != nil {
+		n := len(args)
+		where += ` AND (created_at, id) < ($` + strconv.Itoa(n+1) + `, $` + strconv.Itoa(n+2) + `)`
+		args = append(args, after.CreatedAt, after.ID)
+	}
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE ` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $` + strconv.Itoa(len(args)+1)
+	return query, append(args, limit)
+}
+
+// listOrders runs a listing query on db and reads its orders
+func (r *supplierOrderRepository) listOrders(ctx context.Context, db *sql.DB, description, query string, args []interface{}) ([]*domain.SupplierOrder, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list supplier orders "+description, zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+func (r *supplierOrderRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID, limit, offset int) ([]*domain.SupplierOrder, error) {
+	query, args := offsetListQuery(`partner_id = $1`, []interface{}{partnerID}, limit, offset)
+	return r.listOrders(ctx, r.replica, "by partner ID", query, args)
+}
+
+func (r *supplierOrderRepository) ListByStatus(ctx context.Context, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+	query, args := offsetListQuery(`status = $1`, []interface{}{status}, limit, offset)
+	return r.listOrders(ctx, r.replica, "by status", query, args)
+}
+
+func (r *supplierOrderRepository) ListByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit, offset int) ([]*domain.SupplierOrder, error) {
+	query, args := offsetListQuery(`partner_id = $1 AND status = $2`, []interface{}{partnerID, status}, limit, offset)
+	return r.listOrders(ctx, r.replica, "by partner ID and status", query, args)
+}
+
+// ListByPartnerIDAfter lists a partner's orders using keyset pagination (after may be nil for the first page)
+func (r *supplierOrderRepository) ListByPartnerIDAfter(ctx context.Context, partnerID uuid.UUID, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	query, args := keysetListQuery(`partner_id = $1`, []interface{}{partnerID}, after, limit)
+	return r.listOrders(ctx, r.replica, "by partner ID", query, args)
+}
+
+// ListByPartnerIDsAfter lists the orders of any of the partners using keyset pagination (after may be nil for the first page)
+func (r *supplierOrderRepository) ListByPartnerIDsAfter(ctx context.Context, partnerIDs []uuid.UUID, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	idValues := make([]string, len(partnerIDs))
+	for i, id := range partnerIDs {
+		idValues[i] = id.String()
+	}
+
+	query, args := keysetListQuery(`partner_id = ANY($1::uuid[])`, []interface{}{pq.Array(idValues)}, after, limit)
+	return r.listOrders(ctx, r.replica, "by partner IDs", query, args)
+}
+
+// ListByStatusAfter lists orders in a status using keyset pagination (after may be nil for the first page)
+func (r *supplierOrderRepository) ListByStatusAfter(ctx context.Context, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	query, args := keysetListQuery(`status = $1`, []interface{}{status}, after, limit)
+	return r.listOrders(ctx, r.replica, "by status", query, args)
+}
+
+// ListByPartnerIDAndStatusAfter lists a partner's orders in a status using keyset pagination
+// (after may be nil for the first page)
+func (r *supplierOrderRepository) ListByPartnerIDAndStatusAfter(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	query, args := keysetListQuery(`partner_id = $1 AND status = $2`, []interface{}{partnerID, status}, after, limit)
+	return r.listOrders(ctx, r.replica, "by partner ID and status", query, args)
+}
+
+// ListWithoutShopifyOrderAfter reads from the primary: a backfill must not see orders it has just
+// given a draft order as still missing one
+func (r *supplierOrderRepository) ListWithoutShopifyOrderAfter(ctx context.Context, statuses []domain.OrderStatus, since time.Time, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	query, args := keysetListQuery(
+		`status = ANY($1) AND created_at >= $2 AND shopify_draft_order_id IS NULL AND shopify_order_id IS NULL`,
+		[]interface{}{pq.Array(statusValues), since},
+		after, limit,
+	)
+	return r.listOrders(ctx, r.db, "without Shopify order", query, args)
+}
+
+func (r *supplierOrderRepository) ListPendingCreatedBefore(ctx context.Context, before time.Time, limit int) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE status = $1 AND ` + pendingSince + ` < $2 AND shopify_order_id IS NULL
+		ORDER BY ` + pendingSince + `, id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.OrderStatusPendingConfirmation, before, limit)
+	if err != nil {
+		r.logger.Error("Failed to list expired pending supplier orders", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+func (r *supplierOrderRepository) ListNotExportedForFulfillment(ctx context.Context, status domain.OrderStatus, limit int) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE status = $1
+			AND NOT EXISTS (SELECT 1 FROM fulfillment_exports e WHERE e.supplier_order_id = supplier_orders.id)
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		r.logger.Error("Failed to list supplier orders not exported for fulfillment", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+func (r *supplierOrderRepository) SumOutstanding(ctx context.Context, partnerID uuid.UUID, statuses []domain.OrderStatus) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(cart_total), 0)
+		FROM supplier_orders
+		WHERE partner_id = $1 AND status = ANY($2) AND LOWER(COALESCE(payment_status, '')) <> $3
+	`
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	var outstanding float64
+	err := r.db.QueryRowContext(ctx, query, partnerID, pq.Array(statusValues), domain.PaymentStatusPaid).Scan(&outstanding)
+	if err != nil {
+		r.logger.Error("Failed to sum outstanding order value", zap.Error(err))
+		return 0, err
+	}
+
+	return outstanding, nil
+}
+
+func (r *supplierOrderRepository) GetLatestByContentHash(ctx context.Context, partnerID uuid.UUID, contentHash string, since time.Time, statuses []domain.OrderStatus) (*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE partner_id = $1 AND content_hash = $2 AND created_at >= $3 AND status = ANY($4)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	order, err := r.scanOrder(r.db.QueryRowContext(ctx, query, partnerID, contentHash, since, pq.Array(statusValues)))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: contentHash}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get supplier order by content hash", zap.Error(err))
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *supplierOrderRepository) ListCreatedSince(ctx context.Context, since time.Time, statuses []domain.OrderStatus) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE created_at >= $1 AND status = ANY($2)
+		ORDER BY created_at DESC
+	`
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, since, pq.Array(statusValues))
+	if err != nil {
+		r.logger.Error("Failed to list supplier orders created since", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.SupplierOrder
+	for rows.Next() {
+		order, err := r.scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+// invariantViolations are the conditions under which an order breaks each invariant
+var invariantViolations = map[domain.OrderInvariant]string{
+	domain.OrderInvariantShippedWithTracking: `status = 'SHIPPED' AND COALESCE(tracking_number, '') = ''`,
+	domain.OrderInvariantConfirmedInShopify:  `status = 'CONFIRMED' AND shopify_draft_order_id IS NULL AND shopify_order_id IS NULL`,
+	domain.OrderInvariantHasItems:            `NOT EXISTS (SELECT 1 FROM supplier_order_items i WHERE i.supplier_order_id = supplier_orders.id)`,
+}
+
+// FindInvariantViolations reads from the replica: the checker tolerates a little lag
+func (r *supplierOrderRepository) FindInvariantViolations(ctx context.Context, invariant domain.OrderInvariant, createdBefore time.Time, limit int) (int, []*domain.SupplierOrder, error) {
+	condition, ok := invariantViolations[invariant]
+	if !ok {
+		return 0, nil, fmt.Errorf("unknown order invariant %q", invariant)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM supplier_orders WHERE created_at < $1 AND ` + condition
+	if err := r.replica.QueryRowContext(ctx, countQuery, createdBefore).Scan(&total); err != nil {
+		r.logger.Error("Failed to count order invariant violations", zap.String("invariant", string(invariant)), zap.Error(err))
+		return 0, nil, err
+	}
+	if total == 0 || limit <= 0 {
+		return total, nil, nil
+	}
+
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE created_at < $1 AND ` + condition + `
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, createdBefore, limit)
+	if err != nil {
+		r.logger.Error("Failed to list order invariant violations", zap.String("invariant", string(invariant)), zap.Error(err))
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.SupplierOrder
+	for rows.Next() {
+		order, err := r.scanOrder(rows)
+		if err != nil {
+			return 0, nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return total, orders, rows.Err()
+}
+
+// orderFilterConditions filters supplier_orders o by an OrderFilter ($1-$4)
+const orderFilterConditions = `
+	($1::uuid IS NULL OR o.partner_id = $1)
+	AND ($2 = '' OR o.status = $2)
+	AND ($3::timestamptz IS NULL OR o.created_at >= $3)
+	AND ($4::timestamptz IS NULL OR o.created_at < $4)
+`
+
+func orderFilterArgs(filter domain.OrderFilter) []interface{} {
+	var partnerID uuid.NullUUID
+	if filter.PartnerID != nil {
+		partnerID = uuid.NullUUID{UUID: *filter.PartnerID, Valid: true}
+	}
+	var status string
+	if filter.Status != nil {
+		status = string(*filter.Status)
+	}
+	var from, to sql.NullTime
+	if filter.From != nil {
+		from = sql.NullTime{Time: *filter.From, Valid: true}
+	}
+	if filter.To != nil {
+		to = sql.NullTime{Time: *filter.To, Valid: true}
+	}
+	return []interface{}{partnerID, status, from, to}
+}
+
+func (r *supplierOrderRepository) CountForExport(ctx context.Context, filter domain.OrderFilter) (int, error) {
+	query := `SELECT COUNT(*) FROM supplier_orders o WHERE ` + orderFilterConditions
+
+	var total int
+	if err := r.replica.QueryRowContext(ctx, query, orderFilterArgs(filter)...).Scan(&total); err != nil {
+		r.logger.Error("Failed to count supplier orders for export", zap.Error(err))
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *supplierOrderRepository) ListForExportAfter(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders o
+		WHERE ` + orderFilterConditions + `
+			AND ($5::timestamptz IS NULL OR (o.created_at, o.id) < ($5, $6))
+		ORDER BY o.created_at DESC, o.id DESC
+		LIMIT $7
+	`
+
+	var afterCreatedAt sql.NullTime
+	var afterID uuid.NullUUID
+	if after != nil {
+		afterCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = uuid.NullUUID{UUID: after.ID, Valid: true}
+	}
+	args := append(orderFilterArgs(filter), afterCreatedAt, afterID, limit)
+
+	rows, err := r.replica.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list supplier orders for export", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+// reportDimensionColumns and reportMetricColumns are the only SQL a report query is built
+// from; everything the client sends is a key into them or a bound parameter
+var reportDimensionColumns = map[domain.ReportDimension][]string{
+	domain.ReportDimensionPartner: {"o.partner_id", "p.name"},
+	domain.ReportDimensionStatus:  {"o.status"},
+	domain.ReportDimensionDay:     {"date_trunc('day', o.created_at AT TIME ZONE 'UTC')"},
+}
+
+var reportMetricColumns = map[domain.ReportMetric]string{
+	domain.ReportMetricCount:         "COUNT(*)",
+	domain.ReportMetricRevenue:       "COALESCE(SUM(o.cart_total) FILTER (WHERE o.status NOT IN ('REJECTED', 'CANCELLED')), 0)",
+	domain.ReportMetricAvgTimeToShip: "AVG(EXTRACT(EPOCH FROM o.shipped_at - o.created_at) / 3600) FILTER (WHERE o.shipped_at IS NOT NULL)",
+}
+
+func (r *supplierOrderRepository) Report(ctx context.Context, query domain.ReportQuery) ([]*domain.ReportRow, error) {
+	var groupColumns, selectColumns, orderColumns []string
+	join := ""
+	for _, dimension := range query.GroupBy {
+		columns, ok := reportDimensionColumns[dimension]
+		if !ok {
+			return nil, fmt.Errorf("unknown report dimension %q", dimension)
+		}
+		groupColumns = append(groupColumns, columns...)
+		selectColumns = append(selectColumns, columns...)
+		if dimension == domain.ReportDimensionPartner {
+			join = "JOIN partners p ON p.id = o.partner_id"
+		}
+	}
+	// Rows read oldest day first, then by partner name and status, whatever the grouping order
+	for _, dimension := range []domain.ReportDimension{domain.ReportDimensionDay, domain.ReportDimensionPartner, domain.ReportDimensionStatus} {
+		for _, grouped := range query.GroupBy {
+			if grouped == dimension {
+				orderColumns = append(orderColumns, reportDimensionColumns[dimension][len(reportDimensionColumns[dimension])-1])
+			}
+		}
+	}
+	for _, metric := range query.Metrics {
+		column, ok := reportMetricColumns[metric]
+		if !ok {
+			return nil, fmt.Errorf("unknown report metric %q", metric)
+		}
+		selectColumns = append(selectColumns, column)
+	}
+	if len(query.Metrics) == 0 {
+		return nil, fmt.Errorf("report without metrics")
+	}
+
+	sqlQuery := `SELECT ` + strings.Join(selectColumns, ", ") + `
+		FROM supplier_orders o ` + join + `
+		WHERE ` + orderFilterConditions
+	if len(groupColumns) > 0 {
+		sqlQuery += ` GROUP BY ` + strings.Join(groupColumns, ", ") + ` ORDER BY ` + strings.Join(orderColumns, ", ")
+	}
+	sqlQuery += ` LIMIT $5`
+
+	rows, err := r.replica.QueryContext(ctx, sqlQuery, append(orderFilterArgs(query.Filter), query.Limit)...)
+	if err != nil {
+		r.logger.Error("Failed to run report", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var report []*domain.ReportRow
+	for rows.Next() {
+		row := &domain.ReportRow{}
+		var dest []interface{}
+		var avgTimeToShip sql.NullFloat64
+		for _, dimension := range query.GroupBy {
+			switch dimension {
+			case domain.ReportDimensionPartner:
+				row.PartnerID, row.PartnerName = new(uuid.UUID), new(string)
+				dest = append(dest, row.PartnerID, row.PartnerName)
+			case domain.ReportDimensionStatus:
+				row.Status = new(domain.OrderStatus)
+				dest = append(dest, row.Status)
+			case domain.ReportDimensionDay:
+				row.Day = new(time.Time)
+				dest = append(dest, row.Day)
+			}
+		}
+		for _, metric := range query.Metrics {
+			switch metric {
+			case domain.ReportMetricCount:
+				row.Count = new(int64)
+				dest = append(dest, row.Count)
+			case domain.ReportMetricRevenue:
+				row.Revenue = new(float64)
+				dest = append(dest, row.Revenue)
+			case domain.ReportMetricAvgTimeToShip:
+				dest = append(dest, &avgTimeToShip)
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if avgTimeToShip.Valid {
+			row.AvgTimeToShip = &avgTimeToShip.Float64
+		}
+		report = append(report, row)
+	}
+
+	return report, rows.Err()
+}
+
+func (r *supplierOrderRepository) CountByStatusForPartner(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (map[domain.OrderStatus]int, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM supplier_orders
+		WHERE partner_id = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY status
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, partnerID, from, to)
+	if err != nil {
+		r.logger.Error("Failed to count supplier orders by status", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.OrderStatus]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[domain.OrderStatus(status)] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// ListOldestByPartnerIDAndStatus lists a partner's orders in a status, oldest first
+func (r *supplierOrderRepository) ListOldestByPartnerIDAndStatus(ctx context.Context, partnerID uuid.UUID, status domain.OrderStatus, limit int) ([]*domain.SupplierOrder, error) {
+	query := `
+		SELECT ` + supplierOrderColumns + `
+		FROM supplier_orders
+		WHERE partner_id = $1 AND status = $2
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, partnerID, status, limit)
+	if err != nil {
+		r.logger.Error("Failed to list oldest supplier orders by status", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectOrders(rows)
+}
+
+// FunnelStats counts each partner's orders created in [from, to) per funnel stage, using
+// the status timestamps so orders that moved on still count for the stages they passed
+func (r *supplierOrderRepository) FunnelStats(ctx context.Context, from, to time.Time) ([]*domain.OrderFunnelStats, error) {
+	query := `
+		SELECT o.partner_id, p.name,
+			COUNT(*),
+			COUNT(o.confirmed_at),
+			COUNT(o.shipped_at),
+			COUNT(o.delivered_at),
+			COUNT(o.rejected_at),
+			COUNT(o.cancelled_at),
+			COALESCE(SUM(o.cart_total) FILTER (WHERE o.status NOT IN ('REJECTED', 'CANCELLED')), 0)
+		FROM supplier_orders o
+		JOIN partners p ON p.id = o.partner_id
+		WHERE o.created_at >= $1 AND o.created_at < $2
+		GROUP BY o.partner_id, p.name
+		ORDER BY p.name
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to get order funnel stats", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.OrderFunnelStats
+	for rows.Next() {
+		s := &domain.OrderFunnelStats{}
+		if err := rows.Scan(
+			&s.PartnerID,
+			&s.PartnerName,
+			&s.Submitted,
+			&s.Confirmed,
+			&s.Shipped,
+			&s.Delivered,
+			&s.Rejected,
+			&s.Cancelled,
+			&s.Revenue,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// CountRejectionReasons counts rejected orders created in [from, to) per partner and
+// reason, most frequent first. Reasons are compared case-insensitively; a missing
+// reason is reported as "unspecified".
+func (r *supplierOrderRepository) CountRejectionReasons(ctx context.Context, from, to time.Time) ([]*domain.RejectionReasonCount, error) {
+	query := `
+		SELECT partner_id, COALESCE(NULLIF(LOWER(TRIM(rejection_reason)), ''), 'unspecified') AS reason, COUNT(*)
+		FROM supplier_orders
+		WHERE status = 'REJECTED' AND created_at >= $1 AND created_at < $2
+		GROUP BY partner_id, reason
+		ORDER BY partner_id, COUNT(*) DESC, reason
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to count rejection reasons", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*domain.RejectionReasonCount
+	for rows.Next() {
+		c := &domain.RejectionReasonCount{}
+		if err := rows.Scan(&c.PartnerID, &c.Reason, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// CountRejectionCodes counts rejected orders created in [from, to) per partner and rejection
+// code, most frequent first
+func (r *supplierOrderRepository) CountRejectionCodes(ctx context.Context, from, to time.Time) ([]*domain.RejectionCodeCount, error) {
+	query := `
+		SELECT partner_id, COALESCE(rejection_code, 'OTHER') AS code, COUNT(*)
+		FROM supplier_orders
+		WHERE status = 'REJECTED' AND created_at >= $1 AND created_at < $2
+		GROUP BY partner_id, code
+		ORDER BY partner_id, COUNT(*) DESC, code
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, from, to)
+	if err != nil {
+		r.logger.Error("Failed to count rejection codes", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []*domain.RejectionCodeCount
+	for rows.Next() {
+		c := &domain.RejectionCodeCount{}
+		if err := rows.Scan(&c.PartnerID, &c.Code, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *supplierOrderRepository) collectOrders(rows *sql.Rows) ([]*domain.SupplierOrder, error) {
+	var orders []*domain.SupplierOrder
+	for rows.Next() {
+		order, err := r.scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *supplierOrderRepository) scanOrder(rows rowScanner) (*domain.SupplierOrder, error) {
+	var order domain.SupplierOrder
+	var shippingAddressJSON []byte
+	var reference sql.NullString
+	var shopifyDraftOrderID sql.NullInt64
+	var shopifyOrderID sql.NullInt64
+	var customerPhone sql.NullString
+	var customerPhoneE164 sql.NullString
+	var customerEmail sql.NullString
+	var shopifyCustomerID sql.NullInt64
+	var paymentStatus sql.NullString
+	var paymentMethod sql.NullString
+	var rejectionReason sql.NullString
+	var trackingCarrier sql.NullString
+	var trackingNumber sql.NullString
+	var trackingURL sql.NullString
+	var holdReason sql.NullString
+	var heldAt sql.NullTime
+	var heldFromStatus sql.NullString
+	var confirmedAt sql.NullTime
+	var rejectedAt sql.NullTime
+	var shippedAt sql.NullTime
+	var deliveredAt sql.NullTime
+	var cancelledAt sql.NullTime
+	var channel sql.NullString
+	var rejectionCode sql.NullString
+	var contentHash sql.NullString
+	var shopifyDraftOrderName, shopifyOrderName sql.NullString
+
+	err := rows.Scan(
 		&order.ID,
 		&order.PartnerID,
 		&order.PartnerOrderID,
+		&reference,
 		&order.Status,
 		&shopifyDraftOrderID,
 		&shopifyOrderID,
 		&order.CustomerName,
 		&customerPhone,
+		&customerPhoneE164,
+		&customerEmail,
+		&shopifyCustomerID,
 		&shippingAddressJSON,
 		&order.CartTotal,
+		&order.CartTax,
+		&order.CartShipping,
 		&paymentStatus,
 		&paymentMethod,
+		&order.Locale,
 		&rejectionReason,
 		&trackingCarrier,
 		&trackingNumber,
 		&trackingURL,
+		&holdReason,
+		&heldAt,
+		&heldFromStatus,
+		&confirmedAt,
+		&rejectedAt,
+		&shippedAt,
+		&deliveredAt,
+		&cancelledAt,
 		&order.CreatedAt,
 		&order.UpdatedAt,
+		&channel,
+		&rejectionCode,
+		&contentHash,
+		&shopifyDraftOrderName,
+		&shopifyOrderName,
+		&order.HeldSeconds,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if reference.Valid {
+		order.Reference = &reference.String
+	}
+	if channel.Valid {
+		order.Channel = &channel.String
+	}
+	if rejectionCode.Valid {
+		code := domain.RejectionCode(rejectionCode.String)
+		order.RejectionCode = &code
+	}
+	if contentHash.Valid {
+		order.ContentHash = &contentHash.String
+	}
+	if shopifyDraftOrderName.Valid {
+		order.ShopifyDraftOrderName = &shopifyDraftOrderName.String
+	}
+	if shopifyOrderName.Valid {
+		order.ShopifyOrderName = &shopifyOrderName.String
+	}
 	if shopifyDraftOrderID.Valid {
 		order.ShopifyDraftOrderID = &shopifyDraftOrderID.Int64
 	}
@@ -469,6 +1256,15 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	if customerPhone.Valid {
 		order.CustomerPhone = customerPhone.String
 	}
+	if customerPhoneE164.Valid {
+		order.CustomerPhoneE164 = &customerPhoneE164.String
+	}
+	if customerEmail.Valid {
+		order.CustomerEmail = &customerEmail.String
+	}
+	if shopifyCustomerID.Valid {
+		order.ShopifyCustomerID = &shopifyCustomerID.Int64
+	}
 	if paymentStatus.Valid {
 		order.PaymentStatus = paymentStatus.String
 	}
@@ -487,10 +1283,76 @@ func (r *supplierOrderRepository) scanOrder(rows *sql.Rows) (*domain.SupplierOrd
 	if trackingURL.Valid {
 		order.TrackingURL = &trackingURL.String
 	}
+	if holdReason.Valid {
+		order.HoldReason = &holdReason.String
+	}
+	if heldAt.Valid {
+		order.HeldAt = &heldAt.Time
+	}
+	if heldFromStatus.Valid {
+		status := domain.OrderStatus(heldFromStatus.String)
+		order.HeldFromStatus = &status
+	}
+	if confirmedAt.Valid {
+		order.ConfirmedAt = &confirmedAt.Time
+	}
+	if rejectedAt.Valid {
+		order.RejectedAt = &rejectedAt.Time
+	}
+	if shippedAt.Valid {
+		order.ShippedAt = &shippedAt.Time
+	}
+	if deliveredAt.Valid {
+		order.DeliveredAt = &deliveredAt.Time
+	}
+	if cancelledAt.Valid {
+		order.CancelledAt = &cancelledAt.Time
+	}
 
-	if err := json.Unmarshal(shippingAddressJSON, &order.ShippingAddress); err != nil {
+	shippingAddress, err := unmarshalAddress(shippingAddressJSON)
+	if err != nil {
 		return nil, err
 	}
+	order.ShippingAddress = shippingAddress
 
 	return &order, nil
 }
+
+// addressRecord is the JSONB shape of a shipping address
+type addressRecord struct {
+	Street     string  `json:"street"`
+	Address2   *string `json:"address2,omitempty"`
+	City       string  `json:"city"`
+	State      *string `json:"state,omitempty"`
+	PostalCode string  `json:"postal_code"`
+	Country    string  `json:"country"`
+	Notes      *string `json:"notes,omitempty"`
+}
+
+func marshalAddress(addr domain.Address) ([]byte, error) {
+	return json.Marshal(addressRecord{
+		Street:     addr.Street,
+		Address2:   addr.Address2,
+		City:       addr.City,
+		State:      addr.State,
+		PostalCode: addr.PostalCode,
+		Country:    addr.Country,
+		Notes:      addr.Notes,
+	})
+}
+
+func unmarshalAddress(data []byte) (domain.Address, error) {
+	var record addressRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return domain.Address{}, err
+	}
+	return domain.Address{
+		Street:     record.Street,
+		Address2:   record.Address2,
+		City:       record.City,
+		State:      record.State,
+		PostalCode: record.PostalCode,
+		Country:    record.Country,
+		Notes:      record.Notes,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// Listing benchmarks: one page of listingPageSize orders, listingDepth rows deep into
+// listingOrders orders of one partner, by offset and by keyset (cursor). The offset query
+// reads and discards every row before the page; the keyset query seeks to it in the index.
+const (
+	listingOrders   = 50000
+	listingDepth    = 20000
+	listingPageSize = 50
+)
+
+// listingStatuses are spread evenly over the seeded orders
+var listingStatuses = []domain.OrderStatus{
+	domain.OrderStatusPendingConfirmation,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusShipped,
+	domain.OrderStatusDelivered,
+}
+
+// seedListingOrders inserts listingOrders orders of one new partner, one minute apart
+func seedListingOrders(b *testing.B) (*supplierOrderRepository, uuid.UUID) {
+	db := testDB(b, "")
+	ctx := context.Background()
+
+	partner := &domain.Partner{Name: "Listing benchmark", APIKeyHash: "hash", IsActive: true}
+	if err := NewPartnerRepository(db, zap.NewNop()).Create(ctx, partner); err != nil {
+		b.Fatalf("Failed to create partner: %v", err)
+	}
+
+	statuses := make([]string, len(listingStatuses))
+	for i, status := range listingStatuses {
+		statuses[i] = string(status)
+	}
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO supplier_orders (partner_id, partner_order_id, status, customer_name, shipping_address, cart_total, created_at, updated_at)
+		SELECT $1, 'BENCH-' || i, ($2::text[])[1 + i % $3], 'Bench Customer',
+			'{"street": "Main St 1", "city": "Amman", "postal_code": "11118", "country": "JO"}', 10,
+			now() - i * INTERVAL '1 minute', now()
+		FROM generate_series(1, $4) AS i
+	`, partner.ID, pq.Array(statuses), len(statuses), listingOrders)
+	if err != nil {
+		b.Fatalf("Failed to seed orders: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `ANALYZE supplier_orders`); err != nil {
+		b.Fatalf("Failed to analyze: %v", err)
+	}
+
+	return NewSupplierOrderRepository(db, zap.NewNop()), partner.ID
+}
+
+// cursorAt is the cursor a client holds after paging through depth orders of the listing
+func cursorAt(b *testing.B, list func(after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error), depth int) *domain.OrderCursor {
+	var after *domain.OrderCursor
+	for seen := 0; seen < depth; {
+		page, err := list(after, min(1000, depth-seen))
+		if err != nil {
+			b.Fatalf("Failed to page to depth %d: %v", depth, err)
+		}
+		if len(page) == 0 {
+			b.Fatalf("listing has fewer than %d orders", depth)
+		}
+		seen += len(page)
+		after = domain.CursorAfter(page[len(page)-1])
+	}
+	return after
+}
+
+func BenchmarkListOrders(b *testing.B) {
+	orders, partnerID := seedListingOrders(b)
+	ctx := context.Background()
+	status := domain.OrderStatusConfirmed
+	// Only a quarter of the orders have the status, so page as deep into those
+	statusDepth := listingDepth / len(listingStatuses)
+
+	partnerCursor := cursorAt(b, func(after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+		return orders.ListByPartnerIDAfter(ctx, partnerID, after, limit)
+	}, listingDepth)
+	statusCursor := cursorAt(b, func(after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+		return orders.ListByStatusAfter(ctx, status, after, limit)
+	}, statusDepth)
+
+	benchmarks := []struct {
+		name string
+		list func() ([]*domain.SupplierOrder, error)
+	}{
+		{fmt.Sprintf("partner/offset/depth=%d", listingDepth), func() ([]*domain.SupplierOrder, error) {
+			return orders.ListByPartnerID(ctx, partnerID, listingPageSize, listingDepth)
+		}},
+		{fmt.Sprintf("partner/keyset/depth=%d", listingDepth), func() ([]*domain.SupplierOrder, error) {
+			return orders.ListByPartnerIDAfter(ctx, partnerID, partnerCursor, listingPageSize)
+		}},
+		{fmt.Sprintf("status/offset/depth=%d", statusDepth), func() ([]*domain.SupplierOrder, error) {
+			return orders.ListByStatus(ctx, status, listingPageSize, statusDepth)
+		}},
+		{fmt.Sprintf("status/keyset/depth=%d", statusDepth), func() ([]*domain.SupplierOrder, error) {
+			return orders.ListByStatusAfter(ctx, status, statusCursor, listingPageSize)
+		}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				page, err := bm.list()
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(page) != listingPageSize {
+					b.Fatalf("page has %d orders, want %d", len(page), listingPageSize)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+func TestKeysetListQuery(t *testing.T) {
+	partnerID := uuid.New()
+
+	query, args := keysetListQuery(`partner_id = $1 AND status = $2`, []interface{}{partnerID, "CONFIRMED"}, nil, 50)
+	if !strings.Contains(query, "WHERE partner_id = $1 AND status = $2\n") || !strings.HasSuffix(query, "LIMIT $3") {
+		t.Errorf("first page query = %s", query)
+	}
+	if len(args) != 3 || args[2] != 50 {
+		t.Errorf("first page args = %v", args)
+	}
+
+	after := &domain.OrderCursor{CreatedAt: time.Now(), ID: uuid.New()}
+	query, args = keysetListQuery(`partner_id = $1 AND status = $2`, []interface{}{partnerID, "CONFIRMED"}, after, 50)
+	if !strings.Contains(query, "AND (created_at, id) < ($3, $4)") || !strings.HasSuffix(query, "LIMIT $5") {
+		t.Errorf("next page query = %s", query)
+	}
+	if len(args) != 5 || args[3] != after.ID || args[4] != 50 {
+		t.Errorf("next page args = %v", args)
+	}
+}
+
+func TestOffsetListQuery(t *testing.T) {
+	query, args := offsetListQuery(`status = $1`, []interface{}{"CONFIRMED"}, 50, 100)
+	if !strings.Contains(query, "ORDER BY created_at DESC, id DESC") || !strings.HasSuffix(query, "LIMIT $2 OFFSET $3") {
+		t.Errorf("query = %s", query)
+	}
+	if len(args) != 3 || args[1] != 50 || args[2] != 100 {
+		t.Errorf("args = %v", args)
+	}
+}
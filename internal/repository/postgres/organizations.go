@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type organizationRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *sql.DB, logger *zap.Logger) *organizationRepository {
+	return &organizationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	query := `
+		INSERT INTO organizations (id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	now := time.Now()
+	if organization.ID == uuid.Nil {
+		organization.ID = uuid.New()
+	}
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		organization.ID,
+		organization.Name,
+		organization.CreatedAt,
+		organization.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create organization", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	query := `
+		SELECT id, name, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	var organization domain.Organization
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "organization", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get organization by ID", zap.Error(err))
+		return nil, err
+	}
+
+	return &organization, nil
+}
+
+// List lists every organization by name
+func (r *organizationRepository) List(ctx context.Context) ([]*domain.Organization, error) {
+	query := `
+		SELECT id, name, created_at, updated_at
+		FROM organizations
+		ORDER BY name, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list organizations", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizations []*domain.Organization
+	for rows.Next() {
+		var organization domain.Organization
+		if err := rows.Scan(
+			&organization.ID,
+			&organization.Name,
+			&organization.CreatedAt,
+			&organization.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, &organization)
+	}
+
+	return organizations, rows.Err()
+}
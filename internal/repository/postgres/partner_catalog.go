@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type partnerCatalogRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewPartnerCatalogRepository creates a new partner catalog repository
+func NewPartnerCatalogRepository(db *sql.DB, logger *zap.Logger) *partnerCatalogRepository {
+	return &partnerCatalogRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *partnerCatalogRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.SKUMapping, error) {
+	query := `
+		SELECT ` + skuMappingColumns + `
+		FROM partner_catalog pc
+		JOIN sku_mappings m ON m.id = pc.sku_mapping_id
+		WHERE pc.partner_id = $1
+		ORDER BY m.sku ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to list partner catalog", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mappings []*domain.SKUMapping
+	for rows.Next() {
+		mapping, err := scanSKUMapping(rows)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
+}
+
+func (r *partnerCatalogRepository) Add(ctx context.Context, partnerID uuid.UUID, sku string) error {
+	query := `
+		INSERT INTO partner_catalog (partner_id, sku_mapping_id, created_at)
+		SELECT $1, id, $3 FROM sku_mappings WHERE sku = $2
+		ON CONFLICT (partner_id, sku_mapping_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, partnerID, sku, time.Now())
+	if err != nil {
+		r.logger.Error("Failed to add SKU to partner catalog", zap.Error(err))
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		// Either already present or the SKU has no mapping
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM sku_mappings WHERE sku = $1)`, sku).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return &errors.ErrNotFound{Resource: "sku_mapping", ID: sku}
+		}
+	}
+
+	return nil
+}
+
+func (r *partnerCatalogRepository) Remove(ctx context.Context, partnerID uuid.UUID, sku string) error {
+	query := `
+		DELETE FROM partner_catalog
+		WHERE partner_id = $1
+		  AND sku_mapping_id = (SELECT id FROM sku_mappings WHERE sku = $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, partnerID, sku)
+	if err != nil {
+		r.logger.Error("Failed to remove SKU from partner catalog", zap.Error(err))
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return &errors.ErrNotFound{Resource: "partner_catalog_entry", ID: sku}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type partnerInvitationRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewPartnerInvitationRepository creates a new partner invitation repository
+func NewPartnerInvitationRepository(db *sql.DB, logger *zap.Logger) *partnerInvitationRepository {
+	return &partnerInvitationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *partnerInvitationRepository) Create(ctx context.Context, invitation *domain.PartnerInvitation) error {
+	query := `
+		INSERT INTO partner_invitations (id, partner_name, token_hash, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.New()
+	}
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		invitation.ID,
+		invitation.PartnerName,
+		invitation.TokenHash,
+		invitation.CreatedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to create partner invitation", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *partnerInvitationRepository) Claim(ctx context.Context, tokenHash string) (*domain.PartnerInvitation, error) {
+	// Single UPDATE so two concurrent exchanges of the same token can't both succeed
+	query := `
+		UPDATE partner_invitations
+		SET accepted_at = $2
+		WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > $2
+		RETURNING id, partner_name, token_hash, created_by, expires_at, accepted_at, created_at
+	`
+
+	var invitation domain.PartnerInvitation
+	var acceptedAt time.Time
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(
+		&invitation.ID,
+		&invitation.PartnerName,
+		&invitation.TokenHash,
+		&invitation.CreatedBy,
+		&invitation.ExpiresAt,
+		&acceptedAt,
+		&invitation.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrUnauthorized{Message: "invalid or expired invitation token"}
+	}
+	if err != nil {
+		r.logger.Error("Failed to claim partner invitation", zap.Error(err))
+		return nil, err
+	}
+
+	invitation.AcceptedAt = &acceptedAt
+	return &invitation, nil
+}
+
+func (r *partnerInvitationRepository) SetPartnerID(ctx context.Context, id uuid.UUID, partnerID uuid.UUID) error {
+	query := `
+		UPDATE partner_invitations
+		SET partner_id = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to set partner ID on invitation", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
@@ -26,15 +26,44 @@ func NewPartnerRepository(db *sql.DB, logger *zap.Logger) *partnerRepository {
 	}
 }
 
+// partnerColumns are the columns scanPartner reads
+const partnerColumns = `id, name, api_key_hash, webhook_url, locale, is_active, payment_terms, invoice_email,
+		legacy_status_codes, webhook_secret, credit_limit, credit_limit_action, api_key_lookup_hash, organization_id, api_key_prefix, substitutions,
+		created_at, updated_at`
+
+// GetByAPIKeyHash finds the active partner an API key belongs to: by its lookup hash, or, for
+// partners without one yet, by bcrypt-checking each of them. A partner found that way gets its
+// lookup hash stored, so the scan only happens once per partner.
 func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string) (*domain.Partner, error) {
-	// Since bcrypt hashes are salted and different each time, we can't do a direct lookup.
-	// We need to iterate through active partners and verify the API key against each hash.
-	// For production, consider adding a lookup_hash column (SHA256) for efficient lookup.
-	
+	lookupHash := domain.APIKeyLookupHash(apiKey)
+
+	query := `
+		SELECT ` + partnerColumns + `
+		FROM partners
+		WHERE api_key_lookup_hash = $1 AND is_active = true
+	`
+
+	partner, err := scanPartner(r.db.QueryRowContext(ctx, query, lookupHash))
+	if err == nil {
+		if bcrypt.CompareHashAndPassword([]byte(partner.APIKeyHash), []byte(apiKey)) == nil {
+			return partner, nil
+		}
+		return nil, &errors.ErrUnauthorized{Message: "invalid API key"}
+	}
+	if err != sql.ErrNoRows {
+		r.logger.Error("Failed to get partner by API key lookup hash", zap.Error(err))
+		return nil, err
+	}
+
+	return r.scanForAPIKey(ctx, apiKey, lookupHash)
+}
+
+// scanForAPIKey bcrypt-checks the API key against active partners that have no lookup hash yet
+func (r *partnerRepository) scanForAPIKey(ctx context.Context, apiKey, lookupHash string) (*domain.Partner, error) {
 	query := `
-		SELECT id, name, api_key_hash, webhook_url, is_active, created_at, updated_at
+		SELECT ` + partnerColumns + `
 		FROM partners
-		WHERE is_active = true
+		WHERE is_active = true AND api_key_lookup_hash IS NULL
 	`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -45,30 +74,20 @@ func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string)
 	defer rows.Close()
 
 	for rows.Next() {
-		var partner domain.Partner
-		var webhookURL sql.NullString
-
-		err := rows.Scan(
-			&partner.ID,
-			&partner.Name,
-			&partner.APIKeyHash,
-			&webhookURL,
-			&partner.IsActive,
-			&partner.CreatedAt,
-			&partner.UpdatedAt,
-		)
-
+		partner, err := scanPartner(rows)
 		if err != nil {
 			continue
 		}
 
 		// Verify API key against stored hash
 		if err := bcrypt.CompareHashAndPassword([]byte(partner.APIKeyHash), []byte(apiKey)); err == nil {
-			// Match found
-			if webhookURL.Valid {
-				partner.WebhookURL = &webhookURL.String
+			_, err := r.db.ExecContext(ctx, `UPDATE partners SET api_key_lookup_hash = $2 WHERE id = $1`, partner.ID, lookupHash)
+			if err != nil {
+				r.logger.Warn("Failed to store API key lookup hash", zap.String("partner_id", partner.ID.String()), zap.Error(err))
+			} else {
+				partner.APIKeyLookupHash = lookupHash
 			}
-			return &partner, nil
+			return partner, nil
 		}
 	}
 
@@ -77,43 +96,79 @@ func (r *partnerRepository) GetByAPIKeyHash(ctx context.Context, apiKey string)
 
 func (r *partnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error) {
 	query := `
-		SELECT id, name, api_key_hash, webhook_url, is_active, created_at, updated_at
+		SELECT ` + partnerColumns + `
 		FROM partners
 		WHERE id = $1
 	`
 
+	partner, err := scanPartner(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "partner", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get partner by ID", zap.Error(err))
+		return nil, err
+	}
+
+	return partner, nil
+}
+
+func scanPartner(row rowScanner) (*domain.Partner, error) {
 	var partner domain.Partner
-	var webhookURL sql.NullString
+	var webhookURL, invoiceEmail, webhookSecret, lookupHash, keyPrefix sql.NullString
+	var creditLimit sql.NullFloat64
+	var organizationID uuid.NullUUID
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&partner.ID,
 		&partner.Name,
 		&partner.APIKeyHash,
 		&webhookURL,
+		&partner.Locale,
 		&partner.IsActive,
+		&partner.PaymentTerms,
+		&invoiceEmail,
+		&partner.LegacyStatusCodes,
+		&webhookSecret,
+		&creditLimit,
+		&partner.CreditLimitAction,
+		&lookupHash,
+		&organizationID,
+		&keyPrefix,
+		&partner.Substitutions,
 		&partner.CreatedAt,
 		&partner.UpdatedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, &errors.ErrNotFound{Resource: "partner", ID: id.String()}
-	}
 	if err != nil {
-		r.logger.Error("Failed to get partner by ID", zap.Error(err))
 		return nil, err
 	}
 
 	if webhookURL.Valid {
 		partner.WebhookURL = &webhookURL.String
 	}
+	if invoiceEmail.Valid {
+		partner.InvoiceEmail = &invoiceEmail.String
+	}
+	if webhookSecret.Valid {
+		partner.WebhookSecret = &webhookSecret.String
+	}
+	if creditLimit.Valid {
+		partner.CreditLimit = &creditLimit.Float64
+	}
+	partner.APIKeyLookupHash = lookupHash.String
+	partner.APIKeyPrefix = keyPrefix.String
+	if organizationID.Valid {
+		partner.OrganizationID = &organizationID.UUID
+	}
 
 	return &partner, nil
 }
 
 func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner) error {
 	query := `
-		INSERT INTO partners (id, name, api_key_hash, webhook_url, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO partners (id, name, api_key_hash, webhook_url, is_active, created_at, updated_at, locale, payment_terms, invoice_email, legacy_status_codes, webhook_secret,
+			credit_limit, credit_limit_action, api_key_lookup_hash, organization_id, api_key_prefix, substitutions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), $16, NULLIF($17, ''), $18)
 	`
 
 	now := time.Now()
@@ -126,6 +181,15 @@ func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner)
 	if partner.UpdatedAt.IsZero() {
 		partner.UpdatedAt = now
 	}
+	if partner.Locale == "" {
+		partner.Locale = domain.DefaultLocale
+	}
+	if partner.PaymentTerms == "" {
+		partner.PaymentTerms = domain.PaymentTermsPrepaid
+	}
+	if partner.CreditLimitAction == "" {
+		partner.CreditLimitAction = domain.CreditLimitActionHold
+	}
 
 	_, err := r.db.ExecContext(ctx, query,
 		partner.ID,
@@ -135,6 +199,17 @@ func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner)
 		partner.IsActive,
 		partner.CreatedAt,
 		partner.UpdatedAt,
+		partner.Locale,
+		partner.PaymentTerms,
+		partner.InvoiceEmail,
+		partner.LegacyStatusCodes,
+		partner.WebhookSecret,
+		partner.CreditLimit,
+		partner.CreditLimitAction,
+		partner.APIKeyLookupHash,
+		partner.OrganizationID,
+		partner.APIKeyPrefix,
+		partner.Substitutions,
 	)
 
 	if err != nil {
@@ -148,7 +223,11 @@ func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner)
 func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner) error {
 	query := `
 		UPDATE partners
-		SET name = $2, api_key_hash = $3, webhook_url = $4, is_active = $5, updated_at = $6
+		SET name = $2, api_key_hash = $3, webhook_url = $4, is_active = $5, updated_at = $6, locale = $7,
+			payment_terms = $8, invoice_email = $9, legacy_status_codes = $10,
+			webhook_secret = $11, credit_limit = $12, credit_limit_action = $13,
+			api_key_lookup_hash = NULLIF($14, ''), organization_id = $15, api_key_prefix = NULLIF($16, ''),
+			substitutions = $17
 		WHERE id = $1
 	`
 
@@ -161,6 +240,17 @@ func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner)
 		partner.WebhookURL,
 		partner.IsActive,
 		partner.UpdatedAt,
+		partner.Locale,
+		partner.PaymentTerms,
+		partner.InvoiceEmail,
+		partner.LegacyStatusCodes,
+		partner.WebhookSecret,
+		partner.CreditLimit,
+		partner.CreditLimitAction,
+		partner.APIKeyLookupHash,
+		partner.OrganizationID,
+		partner.APIKeyPrefix,
+		partner.Substitutions,
 	)
 
 	if err != nil {
@@ -170,3 +260,31 @@ func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner)
 
 	return nil
 }
+
+// ListByOrganizationID lists the partners of an organization by name
+func (r *partnerRepository) ListByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]*domain.Partner, error) {
+	query := `
+		SELECT ` + partnerColumns + `
+		FROM partners
+		WHERE organization_id = $1
+		ORDER BY name, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID)
+	if err != nil {
+		r.logger.Error("Failed to list partners by organization ID", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partners []*domain.Partner
+	for rows.Next() {
+		partner, err := scanPartner(rows)
+		if err != nil {
+			return nil, err
+		}
+		partners = append(partners, partner)
+	}
+
+	return partners, rows.Err()
+}
@@ -16,5 +16,63 @@ func NewRepositories(db *sql.DB, logger *zap.Logger) *repository.Repositories {
 		IdempotencyKey:   NewIdempotencyKeyRepository(db, logger),
 		SKUMapping:       NewSKUMappingRepository(db, logger),
 		OrderEvent:       NewOrderEventRepository(db, logger),
+		PartnerInvitation: NewPartnerInvitationRepository(db, logger),
+		AuditLog:         NewAuditLogRepository(db, logger),
+		PartnerCatalog:   NewPartnerCatalogRepository(db, logger),
+		WebhookDelivery:  NewWebhookDeliveryRepository(db, logger),
+		APIRequestLog:    NewAPIRequestLogRepository(db, logger),
+		DigestSubscription: NewDigestSubscriptionRepository(db, logger),
+		UnmatchedSKU:     NewUnmatchedSKURepository(db, logger),
+		RevokedAPIKey:    NewRevokedAPIKeyRepository(db, logger),
+		WebhookSubscription: NewWebhookSubscriptionRepository(db, logger),
+		OrderRollup:      NewOrderRollupRepository(db, logger),
+		LowStockAlert:    NewLowStockAlertRepository(db, logger),
+		ItemSubstitution: NewItemSubstitutionRepository(db, logger),
+		Organization:     NewOrganizationRepository(db, logger),
+		CreditLimitAlert: NewCreditLimitAlertRepository(db, logger),
+		Changelog:        NewChangelogRepository(db, logger),
+		ExportJob:        NewExportJobRepository(db, logger),
+		FulfillmentExport: NewFulfillmentExportRepository(db, logger),
+		ReviewAssignment:  NewReviewAssignmentRepository(db, logger),
 	}
 }
+
+// NewRepositoriesWithReplica creates repositories that send listings and reports to the
+// replica. Writes and the reads they depend on (lookups by ID, idempotency, credit checks)
+// stay on the primary. A nil replica is the same as NewRepositories.
+func NewRepositoriesWithReplica(db, replica *sql.DB, logger *zap.Logger) *repository.Repositories {
+	repos := NewRepositories(db, logger)
+	if replica == nil {
+		return repos
+	}
+
+	orders := NewSupplierOrderRepository(db, logger)
+	orders.replica = replica
+	repos.SupplierOrder = orders
+
+	items := NewSupplierOrderItemRepository(db, logger)
+	items.replica = replica
+	repos.SupplierOrderItem = items
+
+	events := NewOrderEventRepository(db, logger)
+	events.replica = replica
+	repos.OrderEvent = events
+
+	deliveries := NewWebhookDeliveryRepository(db, logger)
+	deliveries.replica = replica
+	repos.WebhookDelivery = deliveries
+
+	requestLogs := NewAPIRequestLogRepository(db, logger)
+	requestLogs.replica = replica
+	repos.APIRequestLog = requestLogs
+
+	unmatched := NewUnmatchedSKURepository(db, logger)
+	unmatched.replica = replica
+	repos.UnmatchedSKU = unmatched
+
+	rollups := NewOrderRollupRepository(db, logger)
+	rollups.replica = replica
+	repos.OrderRollup = rollups
+
+	return repos
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type reviewAssignmentRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewReviewAssignmentRepository creates a new review assignment repository
+func NewReviewAssignmentRepository(db *sql.DB, logger *zap.Logger) *reviewAssignmentRepository {
+	return &reviewAssignmentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *reviewAssignmentRepository) Claim(ctx context.Context, assignment *domain.ReviewAssignment) (bool, error) {
+	// The no-op update returns the row when the assignee already holds the order; a row held
+	// by someone else is left alone and nothing is returned
+	query := `
+		INSERT INTO review_assignments (supplier_order_id, assignee_id, assigned_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (supplier_order_id) DO UPDATE SET assignee_id = review_assignments.assignee_id
+		WHERE review_assignments.assignee_id = EXCLUDED.assignee_id
+		RETURNING assigned_by, assigned_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		assignment.SupplierOrderID,
+		assignment.AssigneeID,
+		assignment.AssignedBy,
+	).Scan(&assignment.AssignedBy, &assignment.AssignedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to claim order for review", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *reviewAssignmentRepository) Assign(ctx context.Context, assignment *domain.ReviewAssignment) error {
+	query := `
+		INSERT INTO review_assignments (supplier_order_id, assignee_id, assigned_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (supplier_order_id) DO UPDATE
+		SET assignee_id = EXCLUDED.assignee_id, assigned_by = EXCLUDED.assigned_by, assigned_at = CURRENT_TIMESTAMP
+		RETURNING assigned_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		assignment.SupplierOrderID,
+		assignment.AssigneeID,
+		assignment.AssignedBy,
+	).Scan(&assignment.AssignedAt)
+	if err != nil {
+		r.logger.Error("Failed to assign order for review", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *reviewAssignmentRepository) Release(ctx context.Context, orderID, assigneeID uuid.UUID) (bool, error) {
+	query := `DELETE FROM review_assignments WHERE supplier_order_id = $1 AND assignee_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, orderID, assigneeID)
+	if err != nil {
+		r.logger.Error("Failed to release review assignment", zap.Error(err))
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *reviewAssignmentRepository) GetByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.ReviewAssignment, error) {
+	query := `
+		SELECT supplier_order_id, assignee_id, assigned_by, assigned_at
+		FROM review_assignments
+		WHERE supplier_order_id = $1
+	`
+
+	var assignment domain.ReviewAssignment
+	err := r.db.QueryRowContext(ctx, query, orderID).Scan(
+		&assignment.SupplierOrderID,
+		&assignment.AssigneeID,
+		&assignment.AssignedBy,
+		&assignment.AssignedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "review_assignment", ID: orderID.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get review assignment", zap.Error(err))
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *reviewAssignmentRepository) ListByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*domain.ReviewAssignment, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT supplier_order_id, assignee_id, assigned_by, assigned_at
+		FROM review_assignments
+		WHERE supplier_order_id = ANY($1::uuid[])
+	`
+
+	ids := make([]string, len(orderIDs))
+	for i, id := range orderIDs {
+		ids[i] = id.String()
+	}
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.logger.Error("Failed to list review assignments", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []*domain.ReviewAssignment
+	for rows.Next() {
+		var assignment domain.ReviewAssignment
+		if err := rows.Scan(
+			&assignment.SupplierOrderID,
+			&assignment.AssigneeID,
+			&assignment.AssignedBy,
+			&assignment.AssignedAt,
+		); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, &assignment)
+	}
+	return assignments, rows.Err()
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type revokedAPIKeyRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewRevokedAPIKeyRepository creates a new revoked API key repository
+func NewRevokedAPIKeyRepository(db *sql.DB, logger *zap.Logger) *revokedAPIKeyRepository {
+	return &revokedAPIKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create adds a key to the revocation list; a key that is already revoked is an ErrConflict
+func (r *revokedAPIKeyRepository) Create(ctx context.Context, revoked *domain.RevokedAPIKey) error {
+	query := `
+		INSERT INTO revoked_api_keys (api_key_hash, partner_id, reason, revoked_by, revoked_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (api_key_hash) DO NOTHING
+	`
+
+	if revoked.RevokedAt.IsZero() {
+		revoked.RevokedAt = time.Now()
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		revoked.APIKeyHash,
+		revoked.PartnerID,
+		revoked.Reason,
+		revoked.RevokedBy,
+		revoked.RevokedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to revoke API key", zap.Error(err))
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return &errors.ErrConflict{Message: "API key is already revoked"}
+	}
+	return nil
+}
+
+func (r *revokedAPIKeyRepository) IsRevoked(ctx context.Context, apiKeyHash string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_api_keys WHERE api_key_hash = $1)`
+
+	var revoked bool
+	if err := r.db.QueryRowContext(ctx, query, apiKeyHash).Scan(&revoked); err != nil {
+		r.logger.Error("Failed to check API key revocation", zap.Error(err))
+		return false, err
+	}
+	return revoked, nil
+}
+
+func (r *revokedAPIKeyRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.RevokedAPIKey, error) {
+	query := `
+		SELECT api_key_hash, partner_id, reason, revoked_by, revoked_at
+		FROM revoked_api_keys
+		WHERE partner_id = $1
+		ORDER BY revoked_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to list revoked API keys", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*domain.RevokedAPIKey
+	for rows.Next() {
+		var revoked domain.RevokedAPIKey
+		if err := rows.Scan(&revoked.APIKeyHash, &revoked.PartnerID, &revoked.Reason, &revoked.RevokedBy, &revoked.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &revoked)
+	}
+
+	return keys, rows.Err()
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
@@ -27,23 +28,12 @@ func NewSKUMappingRepository(db *sql.DB, logger *zap.Logger) *skuMappingReposito
 
 func (r *skuMappingRepository) GetBySKU(ctx context.Context, sku string) (*domain.SKUMapping, error) {
 	query := `
-		SELECT id, sku, shopify_product_id, shopify_variant_id, is_active, created_at, updated_at
-		FROM sku_mappings
-		WHERE sku = $1
+		SELECT ` + skuMappingColumns + `
+		FROM sku_mappings m
+		WHERE m.sku = $1
 	`
 
-	var mapping domain.SKUMapping
-
-	err := r.db.QueryRowContext(ctx, query, sku).Scan(
-		&mapping.ID,
-		&mapping.SKU,
-		&mapping.ShopifyProductID,
-		&mapping.ShopifyVariantID,
-		&mapping.IsActive,
-		&mapping.CreatedAt,
-		&mapping.UpdatedAt,
-	)
-
+	mapping, err := scanSKUMapping(r.db.QueryRowContext(ctx, query, sku))
 	if err == sql.ErrNoRows {
 		return nil, &errors.ErrNotFound{Resource: "sku_mapping", ID: sku}
 	}
@@ -52,7 +42,43 @@ func (r *skuMappingRepository) GetBySKU(ctx context.Context, sku string) (*domai
 		return nil, err
 	}
 
-	return &mapping, nil
+	return mapping, nil
+}
+
+func (r *skuMappingRepository) ListByNormalizedSKU(ctx context.Context, normalizedSKU string) ([]*domain.SKUMapping, error) {
+	return r.listWhere(ctx, "m.normalized_sku = $1", normalizedSKU)
+}
+
+func (r *skuMappingRepository) ListByBarcode(ctx context.Context, barcode string) ([]*domain.SKUMapping, error) {
+	return r.listWhere(ctx, "m.barcode = $1", barcode)
+}
+
+// listWhere lists the mappings matching a condition on one parameter, ordered by SKU
+func (r *skuMappingRepository) listWhere(ctx context.Context, condition string, arg interface{}) ([]*domain.SKUMapping, error) {
+	query := `
+		SELECT ` + skuMappingColumns + `
+		FROM sku_mappings m
+		WHERE ` + condition + `
+		ORDER BY m.sku ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		r.logger.Error("Failed to list SKU mappings", zap.String("condition", condition), zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mappings []*domain.SKUMapping
+	for rows.Next() {
+		mapping, err := scanSKUMapping(rows)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, rows.Err()
 }
 
 func (r *skuMappingRepository) GetActiveSKUs(ctx context.Context) ([]string, error) {
@@ -81,10 +107,10 @@ func (r *skuMappingRepository) GetActiveSKUs(ctx context.Context) ([]string, err
 	return skus, rows.Err()
 }
 
-func (r *skuMappingRepository) Create(ctx context.Context, mapping *domain.SKUMapping) error {
+func (r *skuMappingRepository) Create(ctx context.Context, mapping *domain.SKUMapping, actor domain.SKUMappingActor) error {
 	query := `
-		INSERT INTO sku_mappings (id, sku, shopify_product_id, shopify_variant_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO sku_mappings (id, sku, shopify_product_id, shopify_variant_id, is_active, created_at, updated_at, normalized_sku)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	now := time.Now()
@@ -97,16 +123,23 @@ func (r *skuMappingRepository) Create(ctx context.Context, mapping *domain.SKUMa
 	if mapping.UpdatedAt.IsZero() {
 		mapping.UpdatedAt = now
 	}
+	if mapping.NormalizedSKU == "" {
+		mapping.NormalizedSKU = mapping.SKU
+	}
 
-	_, err := r.db.ExecContext(ctx, query,
-		mapping.ID,
-		mapping.SKU,
-		mapping.ShopifyProductID,
-		mapping.ShopifyVariantID,
-		mapping.IsActive,
-		mapping.CreatedAt,
-		mapping.UpdatedAt,
-	)
+	err := r.asActor(ctx, actor, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			mapping.ID,
+			mapping.SKU,
+			mapping.ShopifyProductID,
+			mapping.ShopifyVariantID,
+			mapping.IsActive,
+			mapping.CreatedAt,
+			mapping.UpdatedAt,
+			mapping.NormalizedSKU,
+		)
+		return err
+	})
 
 	if err != nil {
 		r.logger.Error("Failed to create SKU mapping", zap.Error(err))
@@ -116,7 +149,7 @@ func (r *skuMappingRepository) Create(ctx context.Context, mapping *domain.SKUMa
 	return nil
 }
 
-func (r *skuMappingRepository) Update(ctx context.Context, mapping *domain.SKUMapping) error {
+func (r *skuMappingRepository) Update(ctx context.Context, mapping *domain.SKUMapping, actor domain.SKUMappingActor) error {
 	query := `
 		UPDATE sku_mappings
 		SET shopify_product_id = $2, shopify_variant_id = $3, is_active = $4, updated_at = $5
@@ -125,13 +158,16 @@ func (r *skuMappingRepository) Update(ctx context.Context, mapping *domain.SKUMa
 
 	mapping.UpdatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, query,
-		mapping.ID,
-		mapping.ShopifyProductID,
-		mapping.ShopifyVariantID,
-		mapping.IsActive,
-		mapping.UpdatedAt,
-	)
+	err := r.asActor(ctx, actor, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			mapping.ID,
+			mapping.ShopifyProductID,
+			mapping.ShopifyVariantID,
+			mapping.IsActive,
+			mapping.UpdatedAt,
+		)
+		return err
+	})
 
 	if err != nil {
 		r.logger.Error("Failed to update SKU mapping", zap.Error(err))
@@ -141,13 +177,23 @@ func (r *skuMappingRepository) Update(ctx context.Context, mapping *domain.SKUMa
 	return nil
 }
 
-func (r *skuMappingRepository) Upsert(ctx context.Context, mapping *domain.SKUMapping) error {
+func (r *skuMappingRepository) Upsert(ctx context.Context, mapping *domain.SKUMapping, actor domain.SKUMappingActor) error {
 	query := `
-		INSERT INTO sku_mappings (id, sku, shopify_product_id, shopify_variant_id, is_active, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO sku_mappings (id, sku, shopify_product_id, shopify_variant_id, is_active, created_at, updated_at, weight_grams,
+			product_title, variant_title, vendor, product_type, barcode, image_url, image_urls, normalized_sku)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (sku) DO UPDATE SET
+			normalized_sku = EXCLUDED.normalized_sku,
 			shopify_product_id = EXCLUDED.shopify_product_id,
 			shopify_variant_id = EXCLUDED.shopify_variant_id,
+			weight_grams = EXCLUDED.weight_grams,
+			product_title = EXCLUDED.product_title,
+			variant_title = EXCLUDED.variant_title,
+			vendor = EXCLUDED.vendor,
+			product_type = EXCLUDED.product_type,
+			barcode = EXCLUDED.barcode,
+			image_url = EXCLUDED.image_url,
+			image_urls = EXCLUDED.image_urls,
 			is_active = EXCLUDED.is_active,
 			updated_at = EXCLUDED.updated_at
 	`
@@ -160,16 +206,34 @@ func (r *skuMappingRepository) Upsert(ctx context.Context, mapping *domain.SKUMa
 		mapping.CreatedAt = now
 	}
 	mapping.UpdatedAt = now
+	if mapping.ImageURLs == nil {
+		mapping.ImageURLs = []string{}
+	}
+	if mapping.NormalizedSKU == "" {
+		mapping.NormalizedSKU = mapping.SKU
+	}
 
-	_, err := r.db.ExecContext(ctx, query,
-		mapping.ID,
-		mapping.SKU,
-		mapping.ShopifyProductID,
-		mapping.ShopifyVariantID,
-		mapping.IsActive,
-		mapping.CreatedAt,
-		mapping.UpdatedAt,
-	)
+	err := r.asActor(ctx, actor, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			mapping.ID,
+			mapping.SKU,
+			mapping.ShopifyProductID,
+			mapping.ShopifyVariantID,
+			mapping.IsActive,
+			mapping.CreatedAt,
+			mapping.UpdatedAt,
+			mapping.WeightGrams,
+			mapping.ProductTitle,
+			mapping.VariantTitle,
+			mapping.Vendor,
+			mapping.ProductType,
+			mapping.Barcode,
+			mapping.ImageURL,
+			pq.Array(mapping.ImageURLs),
+			mapping.NormalizedSKU,
+		)
+		return err
+	})
 
 	if err != nil {
 		r.logger.Error("Failed to upsert SKU mapping", zap.Error(err))
@@ -179,12 +243,118 @@ func (r *skuMappingRepository) Upsert(ctx context.Context, mapping *domain.SKUMa
 	return nil
 }
 
+// asActor runs a write in a transaction that names the actor for the history trigger
+// (see migration 000046)
+func (r *skuMappingRepository) asActor(ctx context.Context, actor domain.SKUMappingActor, write func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('b2b.change_source', $1, true), set_config('b2b.changed_by', $2, true)`, actor.Source, actor.ChangedBy); err != nil {
+		return err
+	}
+	if err := write(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *skuMappingRepository) ListChangesAfter(ctx context.Context, filter domain.SKUMappingChangeFilter, after *domain.OrderCursor, limit int) ([]*domain.SKUMappingChange, error) {
+	query := `
+		SELECT id, sku_mapping_id, sku, version, action,
+			previous_normalized_sku, normalized_sku, previous_shopify_product_id, shopify_product_id,
+			previous_shopify_variant_id, shopify_variant_id, previous_is_active, is_active,
+			source, changed_by, changed_at
+		FROM sku_mapping_history
+		WHERE ($1 = '' OR sku = $1)
+			AND ($2::bigint IS NULL OR shopify_variant_id = $2 OR previous_shopify_variant_id = $2)
+			AND ($3::timestamptz IS NULL OR (changed_at, id) < ($3, $4))
+		ORDER BY changed_at DESC, id DESC
+		LIMIT $5
+	`
+
+	var variantID sql.NullInt64
+	if filter.VariantID != nil {
+		variantID = sql.NullInt64{Int64: *filter.VariantID, Valid: true}
+	}
+	var afterChangedAt sql.NullTime
+	var afterID uuid.NullUUID
+	if after != nil {
+		afterChangedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = uuid.NullUUID{UUID: after.ID, Valid: true}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, filter.SKU, variantID, afterChangedAt, afterID, limit)
+	if err != nil {
+		r.logger.Error("Failed to list SKU mapping changes", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*domain.SKUMappingChange
+	for rows.Next() {
+		var change domain.SKUMappingChange
+		var previousNormalizedSKU, normalizedSKU sql.NullString
+		var previousProductID, productID, previousVariantID, variantID sql.NullInt64
+		var previousIsActive, isActive sql.NullBool
+		if err := rows.Scan(
+			&change.ID,
+			&change.SKUMappingID,
+			&change.SKU,
+			&change.Version,
+			&change.Action,
+			&previousNormalizedSKU,
+			&normalizedSKU,
+			&previousProductID,
+			&productID,
+			&previousVariantID,
+			&variantID,
+			&previousIsActive,
+			&isActive,
+			&change.Source,
+			&change.ChangedBy,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		if previousNormalizedSKU.Valid {
+			change.PreviousNormalizedSKU = &previousNormalizedSKU.String
+		}
+		if normalizedSKU.Valid {
+			change.NormalizedSKU = &normalizedSKU.String
+		}
+		if previousProductID.Valid {
+			change.PreviousShopifyProductID = &previousProductID.Int64
+		}
+		if productID.Valid {
+			change.ShopifyProductID = &productID.Int64
+		}
+		if previousVariantID.Valid {
+			change.PreviousShopifyVariantID = &previousVariantID.Int64
+		}
+		if variantID.Valid {
+			change.ShopifyVariantID = &variantID.Int64
+		}
+		if previousIsActive.Valid {
+			change.PreviousIsActive = &previousIsActive.Bool
+		}
+		if isActive.Valid {
+			change.IsActive = &isActive.Bool
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
 func (r *skuMappingRepository) GetAllActive(ctx context.Context) ([]*domain.SKUMapping, error) {
 	query := `
-		SELECT id, sku, shopify_product_id, shopify_variant_id, is_active, created_at, updated_at
-		FROM sku_mappings
-		WHERE is_active = true
-		ORDER BY sku ASC
+		SELECT ` + skuMappingColumns + `
+		FROM sku_mappings m
+		WHERE m.is_active = true
+		ORDER BY m.sku ASC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query)
@@ -196,23 +366,76 @@ func (r *skuMappingRepository) GetAllActive(ctx context.Context) ([]*domain.SKUM
 
 	var mappings []*domain.SKUMapping
 	for rows.Next() {
-		var mapping domain.SKUMapping
-		err := rows.Scan(
-			&mapping.ID,
-			&mapping.SKU,
-			&mapping.ShopifyProductID,
-			&mapping.ShopifyVariantID,
-			&mapping.IsActive,
-			&mapping.CreatedAt,
-			&mapping.UpdatedAt,
-		)
-
+		mapping, err := scanSKUMapping(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		mappings = append(mappings, &mapping)
+		mappings = append(mappings, mapping)
 	}
 
 	return mappings, rows.Err()
 }
+
+// skuMappingColumns are the columns scanSKUMapping reads, for queries aliasing sku_mappings as m
+const skuMappingColumns = `m.id, m.sku, m.normalized_sku, m.shopify_product_id, m.shopify_variant_id, m.weight_grams,
+		m.product_title, m.variant_title, m.vendor, m.product_type, m.barcode, m.image_url, m.image_urls,
+		m.is_active, m.version, m.created_at, m.updated_at`
+
+func scanSKUMapping(row rowScanner) (*domain.SKUMapping, error) {
+	var mapping domain.SKUMapping
+	var weightGrams sql.NullInt64
+	var productTitle sql.NullString
+	var variantTitle sql.NullString
+	var vendor sql.NullString
+	var productType sql.NullString
+	var barcode sql.NullString
+	var imageURL sql.NullString
+
+	err := row.Scan(
+		&mapping.ID,
+		&mapping.SKU,
+		&mapping.NormalizedSKU,
+		&mapping.ShopifyProductID,
+		&mapping.ShopifyVariantID,
+		&weightGrams,
+		&productTitle,
+		&variantTitle,
+		&vendor,
+		&productType,
+		&barcode,
+		&imageURL,
+		pq.Array(&mapping.ImageURLs),
+		&mapping.IsActive,
+		&mapping.Version,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if weightGrams.Valid {
+		weight := int(weightGrams.Int64)
+		mapping.WeightGrams = &weight
+	}
+	if productTitle.Valid {
+		mapping.ProductTitle = &productTitle.String
+	}
+	if variantTitle.Valid {
+		mapping.VariantTitle = &variantTitle.String
+	}
+	if vendor.Valid {
+		mapping.Vendor = &vendor.String
+	}
+	if productType.Valid {
+		mapping.ProductType = &productType.String
+	}
+	if barcode.Valid {
+		mapping.Barcode = &barcode.String
+	}
+	if imageURL.Valid {
+		mapping.ImageURL = &imageURL.String
+	}
+
+	return &mapping, nil
+}
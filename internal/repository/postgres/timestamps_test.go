@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// Timestamps are TIMESTAMPTZ (migration 000007), so whatever the session and the writer's
+// time zones, they read back as the same instant and format as the same UTC RFC3339 string
+func TestTimestamptzRoundTripIsUTC(t *testing.T) {
+	db := testDB(t, "America/New_York")
+	ctx := context.Background()
+
+	amman, err := time.LoadLocation("Asia/Amman")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	createdAt := time.Date(2024, 3, 1, 1, 30, 0, 0, amman)
+
+	partners := NewPartnerRepository(db, zap.NewNop())
+	partner := &domain.Partner{Name: "Timestamps", APIKeyHash: "hash", IsActive: true}
+	if err := partners.Create(ctx, partner); err != nil {
+		t.Fatalf("Failed to create partner: %v", err)
+	}
+	orders := NewSupplierOrderRepository(db, zap.NewNop())
+	order := &domain.SupplierOrder{
+		PartnerID:      partner.ID,
+		PartnerOrderID: "TZ-1",
+		Status:         domain.OrderStatusPendingConfirmation,
+		CustomerName:   "Jane Doe",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	if err := orders.Create(ctx, order); err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+
+	got, err := orders.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(createdAt) || !got.UpdatedAt.Equal(createdAt) {
+		t.Errorf("read back created_at %v, updated_at %v, want %v", got.CreatedAt, got.UpdatedAt, createdAt)
+	}
+	if formatted := got.CreatedAt.UTC().Format(time.RFC3339); formatted != "2024-02-29T23:30:00Z" {
+		t.Errorf("created_at formats as %s, want 2024-02-29T23:30:00Z", formatted)
+	}
+}
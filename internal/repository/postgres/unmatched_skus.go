@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+type unmatchedSKURepository struct {
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
+}
+
+// NewUnmatchedSKURepository creates a new unmatched SKU repository
+func NewUnmatchedSKURepository(db *sql.DB, logger *zap.Logger) *unmatchedSKURepository {
+	return &unmatchedSKURepository{
+		db:      db,
+		replica: db,
+		logger:  logger,
+	}
+}
+
+func (r *unmatchedSKURepository) Record(ctx context.Context, skus []*domain.UnmatchedSKU) error {
+	query := `
+		INSERT INTO unmatched_skus (partner_id, sku, title, reason, occurrences, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, 1, $5, $5)
+		ON CONFLICT (partner_id, sku) DO UPDATE
+		SET title = EXCLUDED.title,
+			reason = EXCLUDED.reason,
+			occurrences = unmatched_skus.occurrences + 1,
+			last_seen_at = EXCLUDED.last_seen_at
+	`
+
+	now := time.Now()
+	for _, sku := range skus {
+		if _, err := r.db.ExecContext(ctx, query, sku.PartnerID, sku.SKU, sku.Title, sku.Reason, now); err != nil {
+			r.logger.Error("Failed to record unmatched SKU", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List returns the unmatched SKUs last seen within the filter's period, most frequent first
+func (r *unmatchedSKURepository) List(ctx context.Context, filter domain.UnmatchedSKUFilter) ([]*domain.UnmatchedSKU, error) {
+	query := `
+		SELECT partner_id, sku, title, reason, occurrences, first_seen_at, last_seen_at
+		FROM unmatched_skus
+		WHERE ($1::uuid IS NULL OR partner_id = $1)
+			AND ($2 = '' OR reason = $2)
+			AND last_seen_at >= $3 AND last_seen_at < $4
+		ORDER BY occurrences DESC, last_seen_at DESC
+		LIMIT $5
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, filter.PartnerID, filter.Reason, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		r.logger.Error("Failed to list unmatched SKUs", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var skus []*domain.UnmatchedSKU
+	for rows.Next() {
+		sku := &domain.UnmatchedSKU{}
+		err := rows.Scan(
+			&sku.PartnerID,
+			&sku.SKU,
+			&sku.Title,
+			&sku.Reason,
+			&sku.Occurrences,
+			&sku.FirstSeenAt,
+			&sku.LastSeenAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		skus = append(skus, sku)
+	}
+
+	return skus, rows.Err()
+}
@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type webhookDeliveryRepository struct {
+	db *sql.DB
+	// replica serves listings and reports (db when no replica is configured)
+	replica *sql.DB
+	logger  *zap.Logger
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *sql.DB, logger *zap.Logger) *webhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		db:      db,
+		replica: db,
+		logger:  logger,
+	}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, partner_id, supplier_order_id, event_type, url, payload,
+			response_status, error, success, duration_ms, created_at, retry_of
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	payloadJSON, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.PartnerID,
+		delivery.SupplierOrderID,
+		delivery.EventType,
+		delivery.URL,
+		payloadJSON,
+		delivery.ResponseStatus,
+		delivery.Error,
+		delivery.Success,
+		delivery.DurationMs,
+		delivery.CreatedAt,
+		delivery.RetryOf,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to create webhook delivery", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryRepository) StatsByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*domain.WebhookDeliveryStats, error) {
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE success)
+		FROM webhook_deliveries
+		WHERE partner_id = $1 AND created_at >= $2 AND created_at < $3
+	`
+
+	var stats domain.WebhookDeliveryStats
+	err := r.replica.QueryRowContext(ctx, query, partnerID, from, to).Scan(&stats.Total, &stats.Succeeded)
+	if err != nil {
+		r.logger.Error("Failed to get webhook delivery stats", zap.Error(err))
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// ListFailedByPartnerID lists a partner's failed webhook deliveries in [from, to), newest first
+func (r *webhookDeliveryRepository) ListFailedByPartnerID(ctx context.Context, partnerID uuid.UUID, from, to time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, partner_id, supplier_order_id, event_type, url, response_status, error, success, duration_ms, created_at
+		FROM webhook_deliveries
+		WHERE partner_id = $1 AND NOT success AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.replica.QueryContext(ctx, query, partnerID, from, to, limit)
+	if err != nil {
+		r.logger.Error("Failed to list failed webhook deliveries", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		var orderID uuid.NullUUID
+		var responseStatus sql.NullInt64
+		var errMsg sql.NullString
+
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.PartnerID,
+			&orderID,
+			&delivery.EventType,
+			&delivery.URL,
+			&responseStatus,
+			&errMsg,
+			&delivery.Success,
+			&delivery.DurationMs,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if orderID.Valid {
+			delivery.SupplierOrderID = &orderID.UUID
+		}
+		if responseStatus.Valid {
+			status := int(responseStatus.Int64)
+			delivery.ResponseStatus = &status
+		}
+		if errMsg.Valid {
+			delivery.Error = &errMsg.String
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// GetByID gets a delivery, with its payload
+func (r *webhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	query := `
+		SELECT id, partner_id, supplier_order_id, event_type, url, payload, response_status, error,
+			success, duration_ms, created_at, retry_of
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+
+	var delivery domain.WebhookDelivery
+	var orderID, retryOf uuid.NullUUID
+	var payloadJSON []byte
+	var responseStatus sql.NullInt64
+	var errMsg sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&delivery.ID,
+		&delivery.PartnerID,
+		&orderID,
+		&delivery.EventType,
+		&delivery.URL,
+		&payloadJSON,
+		&responseStatus,
+		&errMsg,
+		&delivery.Success,
+		&delivery.DurationMs,
+		&delivery.CreatedAt,
+		&retryOf,
+	)
+	if err == sql.ErrNoRows {
+		return nil, &errors.ErrNotFound{Resource: "webhook_delivery", ID: id.String()}
+	}
+	if err != nil {
+		r.logger.Error("Failed to get webhook delivery", zap.Error(err))
+		return nil, err
+	}
+
+	if err := json.Unmarshal(payloadJSON, &delivery.Payload); err != nil {
+		return nil, err
+	}
+	if orderID.Valid {
+		delivery.SupplierOrderID = &orderID.UUID
+	}
+	if retryOf.Valid {
+		delivery.RetryOf = &retryOf.UUID
+	}
+	if responseStatus.Valid {
+		status := int(responseStatus.Int64)
+		delivery.ResponseStatus = &status
+	}
+	if errMsg.Valid {
+		delivery.Error = &errMsg.String
+	}
+
+	return &delivery, nil
+}
+
+// ListHistoryByPartnerIDAfter lists a partner's deliveries newest first, each with the number
+// of attempts (the delivery and its retries) and the outcome of the latest attempt. Pages
+// start after the cursor when one is given.
+func (r *webhookDeliveryRepository) ListHistoryByPartnerIDAfter(ctx context.Context, partnerID uuid.UUID, filter domain.WebhookDeliveryFilter, after *domain.OrderCursor, limit int) ([]*domain.WebhookDeliveryHistory, error) {
+	query := `
+		SELECT d.id, d.partner_id, d.supplier_order_id, d.event_type, d.url, d.payload, d.created_at,
+			a.attempts, a.delivered,
+			l.response_status, l.error, l.success, l.duration_ms, l.created_at
+		FROM webhook_deliveries d
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*) AS attempts, BOOL_OR(success) AS delivered
+			FROM webhook_deliveries r
+			WHERE r.id = d.id OR r.retry_of = d.id
+		) a
+		CROSS JOIN LATERAL (
+			SELECT response_status, error, success, duration_ms, created_at
+			FROM webhook_deliveries r
+			WHERE r.id = d.id OR r.retry_of = d.id
+			ORDER BY created_at DESC, id DESC
+			LIMIT 1
+		) l
+		WHERE d.partner_id = $1 AND d.retry_of IS NULL
+			AND ($2 = '' OR d.event_type = $2)
+			AND ($3::boolean IS NULL OR a.delivered = $3)
+			AND ($4::timestamptz IS NULL OR (d.created_at, d.id) < ($4, $5))
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $6
+	`
+
+	var afterCreatedAt sql.NullTime
+	var afterID uuid.NullUUID
+	if after != nil {
+		afterCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = uuid.NullUUID{UUID: after.ID, Valid: true}
+	}
+	var delivered sql.NullBool
+	if filter.Delivered != nil {
+		delivered = sql.NullBool{Bool: *filter.Delivered, Valid: true}
+	}
+
+	rows, err := r.replica.QueryContext(ctx, query, partnerID, filter.EventType, delivered, afterCreatedAt, afterID, limit)
+	if err != nil {
+		r.logger.Error("Failed to list webhook delivery history", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDeliveryHistory
+	for rows.Next() {
+		var history domain.WebhookDeliveryHistory
+		var orderID uuid.NullUUID
+		var payloadJSON []byte
+		var responseStatus sql.NullInt64
+		var errMsg sql.NullString
+
+		if err := rows.Scan(
+			&history.ID,
+			&history.PartnerID,
+			&orderID,
+			&history.EventType,
+			&history.URL,
+			&payloadJSON,
+			&history.CreatedAt,
+			&history.Attempts,
+			&history.Delivered,
+			&responseStatus,
+			&errMsg,
+			&history.LastAttempt.Success,
+			&history.LastAttempt.DurationMs,
+			&history.LastAttempt.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(payloadJSON, &history.Payload); err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			history.SupplierOrderID = &orderID.UUID
+		}
+		if responseStatus.Valid {
+			status := int(responseStatus.Int64)
+			history.LastAttempt.ResponseStatus = &status
+		}
+		if errMsg.Valid {
+			history.LastAttempt.Error = &errMsg.String
+		}
+		deliveries = append(deliveries, &history)
+	}
+
+	return deliveries, rows.Err()
+}
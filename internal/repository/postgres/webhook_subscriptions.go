@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type webhookSubscriptionRepository struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewWebhookSubscriptionRepository creates a new webhook subscription repository
+func NewWebhookSubscriptionRepository(db *sql.DB, logger *zap.Logger) *webhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *webhookSubscriptionRepository) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT event_type
+		FROM webhook_subscriptions
+		WHERE partner_id = $1
+		ORDER BY event_type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, partnerID)
+	if err != nil {
+		r.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var eventTypes []string
+	for rows.Next() {
+		var eventType string
+		if err := rows.Scan(&eventType); err != nil {
+			return nil, err
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	return eventTypes, rows.Err()
+}
+
+// Replace sets the partner's subscriptions to exactly eventTypes in one statement, so
+// concurrent deliveries never see a half-updated set. An empty list removes them all.
+func (r *webhookSubscriptionRepository) Replace(ctx context.Context, partnerID uuid.UUID, eventTypes []string) error {
+	query := `
+		WITH removed AS (
+			DELETE FROM webhook_subscriptions
+			WHERE partner_id = $1 AND NOT (event_type = ANY($2::text[]))
+		)
+		INSERT INTO webhook_subscriptions (partner_id, event_type)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (partner_id, event_type) DO NOTHING
+	`
+
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	if _, err := r.db.ExecContext(ctx, query, partnerID, pq.Array(eventTypes)); err != nil {
+		r.logger.Error("Failed to replace webhook subscriptions", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Package schemas holds the JSON Schemas of the API's request and webhook payloads. They are
+// published at /v1/schemas/{name} and requests are validated against them.
+package schemas
+
+import (
+	"embed"
+	"sort"
+	"strings"
+
+	"github.com/jafarshop/b2bapi/pkg/jsonschema"
+)
+
+// Schema names
+const (
+	Cart                = "cart"
+	WebhookOrderEvent   = "webhook_order_event"
+	WebhookPartnerEvent = "webhook_partner_event"
+)
+
+//go:embed *.json
+var files embed.FS
+
+var compiled = make(map[string]*jsonschema.Schema)
+
+func init() {
+	for _, name := range Names() {
+		data, _ := Get(name)
+		compiled[name] = jsonschema.MustCompile(data)
+	}
+}
+
+// Names lists the published schemas
+func Names() []string {
+	entries, _ := files.ReadDir(".")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the schema document of the given name
+func Get(name string) ([]byte, bool) {
+	data, err := files.ReadFile(name + ".json")
+	return data, err == nil
+}
+
+// Validate checks a JSON document against the named schema and returns the failing fields
+// with what is wrong with them, or nil when it matches. The error is set when body is not
+// JSON at all.
+func Validate(name string, body []byte) (map[string]string, error) {
+	errs, err := Check(name, body)
+	if err != nil || len(errs) == 0 {
+		return nil, err
+	}
+
+	fields := make(map[string]string, len(errs))
+	for _, e := range errs {
+		path := e.Path
+		if path == "" {
+			path = "body"
+		}
+		if existing, ok := fields[path]; ok {
+			fields[path] = existing + "; " + e.Message
+		} else {
+			fields[path] = e.Message
+		}
+	}
+	return fields, nil
+}
+
+// Check checks a JSON document against the named schema like Validate, but returns each
+// failure with the keyword that failed, ordered by path
+func Check(name string, body []byte) ([]jsonschema.ValidationError, error) {
+	return compiled[name].ValidateJSON(body)
+}
@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jafarshop/b2bapi/internal/awsv4"
+)
+
+type awsProvider struct {
+	region     string
+	endpoint   string
+	creds      awsv4.Credentials
+	httpClient *http.Client
+}
+
+func newAWSProvider(cfg Config) *awsProvider {
+	endpoint := strings.TrimRight(cfg.AWSEndpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", cfg.AWSRegion)
+	}
+	return &awsProvider{
+		region:   cfg.AWSRegion,
+		endpoint: endpoint,
+		creds: awsv4.Credentials{
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		},
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Get reads the current version of a secret, e.g. "prod/b2bapi/db#password". With a key the
+// secret must hold a JSON object, as the secrets rotated by RDS do.
+func (p *awsProvider) Get(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitRef(ref)
+
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awsv4.Sign(req, "/", "", body, p.creds, p.region, "secretsmanager", time.Now())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets manager: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("secrets manager returned %d for %s: %s", resp.StatusCode, secretID, strings.TrimSpace(string(data)))
+	}
+
+	var result struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("secrets manager: %w", err)
+	}
+	if result.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+	if key == "" {
+		return *result.SecretString, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(*result.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object, so it has no field %q", secretID, key)
+	}
+	return field(fields, ref, key)
+}
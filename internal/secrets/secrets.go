@@ -0,0 +1,85 @@
+// Package secrets fetches credentials from a secrets backend (HashiCorp Vault or AWS Secrets
+// Manager) so they do not have to be set as plaintext environment variables.
+//
+// A secret is referenced as "<path>#<key>": the path of a Vault KV secret or the name/ARN of
+// an AWS secret, and the field to read from it. Without "#<key>" the whole value is used,
+// which only works for AWS secrets stored as plain text.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Backends
+const (
+	BackendEnv   = "env"
+	BackendVault = "vault"
+	BackendAWS   = "aws"
+)
+
+// Provider reads secrets from a backend
+type Provider interface {
+	Get(ctx context.Context, ref string) (string, error)
+}
+
+// Config selects and authenticates the secrets backend
+type Config struct {
+	Backend string
+
+	VaultAddr      string
+	VaultToken     string
+	VaultNamespace string
+
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+	// AWSEndpoint overrides the Secrets Manager URL (e.g. LocalStack); empty means AWS in AWSRegion
+	AWSEndpoint string
+}
+
+// NewProvider creates the provider of the configured backend; the env backend has none (nil)
+func NewProvider(cfg Config) (Provider, error) {
+	switch cfg.Backend {
+	case "", BackendEnv:
+		return nil, nil
+	case BackendVault:
+		if cfg.VaultAddr == "" || cfg.VaultToken == "" {
+			return nil, fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required for the vault secrets backend")
+		}
+		return newVaultProvider(cfg), nil
+	case BackendAWS:
+		if cfg.AWSRegion == "" || cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" {
+			return nil, fmt.Errorf("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for the aws secrets backend")
+		}
+		return newAWSProvider(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend %q (want env, vault or aws)", cfg.Backend)
+	}
+}
+
+// splitRef splits "<path>#<key>" into path and key (empty when there is no "#")
+func splitRef(ref string) (string, string) {
+	path, key, _ := strings.Cut(ref, "#")
+	return path, key
+}
+
+// field reads key from a secret's fields. Non-string values (e.g. a port number) are
+// returned as their JSON text.
+func field(fields map[string]interface{}, ref, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok || value == nil {
+		return "", fmt.Errorf("secret %s has no field %q", ref, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,73 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type vaultProvider struct {
+	addr       string
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+func newVaultProvider(cfg Config) *vaultProvider {
+	return &vaultProvider{
+		addr:      strings.TrimRight(cfg.VaultAddr, "/"),
+		token:     cfg.VaultToken,
+		namespace: cfg.VaultNamespace,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Get reads a field of a KV secret. The path is the API path below /v1, e.g.
+// "secret/data/b2bapi#db_password" for KV version 2 or "kv/b2bapi#db_password" for version 1.
+func (p *vaultProvider) Get(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	if key == "" {
+		return "", fmt.Errorf("vault secret %s needs a field: <path>#<key>", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("vault returned %d for %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("vault: %w", err)
+	}
+
+	// KV version 2 nests the secret's fields under data.data
+	fields := result.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		if _, versioned := fields["metadata"]; versioned {
+			fields = nested
+		}
+	}
+	return field(fields, ref, key)
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Approval event types. approval_required is recorded when a cart over APPROVAL_THRESHOLD is
+// submitted; each admin confirmation of such an order is recorded as order_approved.
+const (
+	EventTypeApprovalRequired = "approval_required"
+	EventTypeOrderApproved    = "order_approved"
+)
+
+// RequiredApprovals is how many distinct admins must confirm an order over the threshold
+const RequiredApprovals = 2
+
+// Approval is one admin's confirmation of an order
+type Approval struct {
+	AdminID    uuid.UUID
+	ApprovedAt time.Time
+}
+
+// ApprovalStatus is how far an order is through the two-person rule. Required is 0 for
+// orders that need no approvals.
+type ApprovalStatus struct {
+	Required  int
+	Approvals []Approval
+}
+
+// Complete reports whether the order has all the approvals it needs
+func (a *ApprovalStatus) Complete() bool {
+	return len(a.Approvals) >= a.Required
+}
+
+func (a *ApprovalStatus) approvedBy(adminID uuid.UUID) bool {
+	for _, approval := range a.Approvals {
+		if approval.AdminID == adminID {
+			return true
+		}
+	}
+	return false
+}
+
+type approvalService struct {
+	cfg     *config.Config
+	repos   *repository.Repositories
+	orders  OrderService
+	shopify ShopifyService
+	logger  *zap.Logger
+}
+
+// NewApprovalService creates a new order approval service
+func NewApprovalService(cfg *config.Config, repos *repository.Repositories, orders OrderService, shopify ShopifyService, logger *zap.Logger) *approvalService {
+	return &approvalService{
+		cfg:     cfg,
+		repos:   repos,
+		orders:  orders,
+		shopify: shopify,
+		logger:  logger,
+	}
+}
+
+// RequiresApproval reports whether a cart total falls under the two-person rule
+func (s *approvalService) RequiresApproval(total float64) bool {
+	return s.cfg.Approvals.Threshold > 0 && total >= s.cfg.Approvals.Threshold
+}
+
+// Require records that the order needs RequiredApprovals admin approvals. The requirement is
+// kept on the order, so changing the threshold later does not affect submitted orders.
+func (s *approvalService) Require(ctx context.Context, order *domain.SupplierOrder) error {
+	return s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeApprovalRequired,
+		EventData: map[string]interface{}{
+			"required":  RequiredApprovals,
+			"threshold": s.cfg.Approvals.Threshold,
+			"total":     order.CartTotal,
+		},
+	})
+}
+
+// Status returns the order's required and received approvals
+func (s *approvalService) Status(ctx context.Context, orderID uuid.UUID) (*ApprovalStatus, error) {
+	return loadApprovalStatus(ctx, s.repos, orderID)
+}
+
+// Approve records adminID's confirmation of a pending order. Orders without an approval
+// requirement are confirmed straight away; otherwise the order is confirmed, and its Shopify
+// draft completed, once RequiredApprovals distinct admins have approved it.
+// The same admin approving twice is an ErrConflict.
+func (s *approvalService) Approve(ctx context.Context, orderID, adminID uuid.UUID) (*ApprovalStatus, error) {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(domain.OrderStatusConfirmed) {
+		return nil, &errors.ErrInvalidStateTransition{
+			From: order.Status,
+			To:   domain.OrderStatusConfirmed,
+		}
+	}
+
+	status, err := loadApprovalStatus(ctx, s.repos, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Required == 0 {
+		if err := s.orders.ConfirmOrder(ctx, orderID); err != nil {
+			return nil, err
+		}
+		// Drafts of partners with substitutions were left open until now
+		s.completeDraftOrder(ctx, order)
+		return status, nil
+	}
+	if status.approvedBy(adminID) {
+		return nil, &errors.ErrConflict{Message: "order is already approved by this admin; a second admin must confirm it"}
+	}
+
+	event := &domain.OrderEvent{
+		SupplierOrderID: orderID,
+		EventType:       EventTypeOrderApproved,
+		EventData: map[string]interface{}{
+			"admin_partner_id": adminID.String(),
+			"approval":         len(status.Approvals) + 1,
+			"required":         status.Required,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		return nil, err
+	}
+	status.Approvals = append(status.Approvals, Approval{AdminID: adminID, ApprovedAt: time.Now()})
+
+	if !status.Complete() {
+		return status, nil
+	}
+	if err := s.orders.ConfirmOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	s.completeDraftOrder(ctx, order)
+
+	return status, nil
+}
+
+// completeDraftOrder completes the draft order that was left open for approval or for
+// substitutions. Drafts of invoice-terms partners stay open until the payment is confirmed. A failure is logged, not
+// returned: the order is confirmed either way and staff can complete the draft in Shopify.
+func (s *approvalService) completeDraftOrder(ctx context.Context, order *domain.SupplierOrder) {
+	if order.ShopifyDraftOrderID == nil || order.ShopifyOrderID != nil {
+		return
+	}
+	if order.PaymentStatus != domain.PaymentStatusPaid {
+		partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+		if err != nil {
+			s.logger.Error("Failed to get partner for approved order", zap.Error(err))
+			return
+		}
+		if partner.PaymentTerms == domain.PaymentTermsInvoice {
+			return
+		}
+	}
+
+	if err := s.shopify.CompleteOrder(ctx, order); err != nil {
+		s.logger.Error("Failed to complete Shopify draft order after approval",
+			zap.String("operation", opShopifyCompleteOrder),
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_id", order.PartnerID.String()),
+			zap.Error(err),
+		)
+		alertShopifyFailure(s.cfg, s.logger, opShopifyCompleteOrder, order, err)
+	}
+}
+
+// loadApprovalStatus reads the order's approval requirement and approvals from its events
+func loadApprovalStatus(ctx context.Context, repos *repository.Repositories, orderID uuid.UUID) (*ApprovalStatus, error) {
+	events, err := repos.OrderEvent.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ApprovalStatus{}
+	for _, event := range events {
+		switch event.EventType {
+		case EventTypeApprovalRequired:
+			if required, ok := event.EventData["required"].(float64); ok {
+				status.Required = int(required)
+			} else {
+				status.Required = RequiredApprovals
+			}
+		case EventTypeOrderApproved:
+			adminID, err := uuid.Parse(fmt.Sprint(event.EventData["admin_partner_id"]))
+			if err != nil || status.approvedBy(adminID) {
+				continue
+			}
+			status.Approvals = append(status.Approvals, Approval{AdminID: adminID, ApprovedAt: event.CreatedAt})
+		}
+	}
+	return status, nil
+}
+
+// checkApproved returns an ErrConflict while the order is still waiting for approvals
+func checkApproved(ctx context.Context, repos *repository.Repositories, orderID uuid.UUID) error {
+	status, err := loadApprovalStatus(ctx, repos, orderID)
+	if err != nil {
+		return err
+	}
+	if !status.Complete() {
+		return &errors.ErrConflict{Message: fmt.Sprintf("order has %d of %d required approvals", len(status.Approvals), status.Required)}
+	}
+	return nil
+}
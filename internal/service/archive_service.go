@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/archive"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+type archiveService struct {
+	cfg    config.ArchiveConfig
+	store  archive.Store
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewArchiveService creates a service that moves old order events and audit logs to object storage
+func NewArchiveService(cfg config.ArchiveConfig, repos *repository.Repositories, logger *zap.Logger) *archiveService {
+	return &archiveService{
+		cfg: cfg,
+		store: archive.NewS3Store(archive.S3Config{
+			Endpoint:        cfg.Endpoint,
+			Region:          cfg.Region,
+			Bucket:          cfg.Bucket,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		}),
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// ArchiveOrderEvents uploads up to limit events created before the cutoff (of orders in the
+// given statuses) as one archive file, then deletes them. Nothing is deleted unless the
+// upload succeeded. Returns how many events were archived.
+func (s *archiveService) ArchiveOrderEvents(ctx context.Context, before time.Time, statuses []domain.OrderStatus, limit int) (int64, error) {
+	events, err := s.repos.OrderEvent.ListCreatedBefore(ctx, before, statuses, limit)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	records := make([]archive.OrderEventRecord, len(events))
+	ids := make([]uuid.UUID, len(events))
+	for i, event := range events {
+		records[i] = archive.FromOrderEvent(event)
+		ids[i] = event.ID
+	}
+
+	key := archive.Key(s.cfg.Prefix, archive.TableOrderEvents, events[0].CreatedAt, events[len(events)-1].CreatedAt)
+	if err := uploadRecords(ctx, s, key, records); err != nil {
+		return 0, err
+	}
+	return s.repos.OrderEvent.DeleteByIDs(ctx, ids)
+}
+
+// ArchiveAuditLogs uploads up to limit audit logs created before the cutoff as one archive
+// file, then deletes them. Returns how many logs were archived.
+func (s *archiveService) ArchiveAuditLogs(ctx context.Context, before time.Time, limit int) (int64, error) {
+	logs, err := s.repos.AuditLog.ListCreatedBefore(ctx, before, limit)
+	if err != nil || len(logs) == 0 {
+		return 0, err
+	}
+
+	records := make([]archive.AuditLogRecord, len(logs))
+	ids := make([]uuid.UUID, len(logs))
+	for i, log := range logs {
+		records[i] = archive.FromAuditLog(log)
+		ids[i] = log.ID
+	}
+
+	key := archive.Key(s.cfg.Prefix, archive.TableAuditLogs, logs[0].CreatedAt, logs[len(logs)-1].CreatedAt)
+	if err := uploadRecords(ctx, s, key, records); err != nil {
+		return 0, err
+	}
+	return s.repos.AuditLog.DeleteByIDs(ctx, ids)
+}
+
+// uploadRecords writes records to key as one archive file
+func uploadRecords[T any](ctx context.Context, s *archiveService, key string, records []T) error {
+	data, err := archive.Encode(records)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Put(ctx, key, data); err != nil {
+		s.logger.Error("Failed to upload archive file", zap.String("key", key), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Uploaded archive file", zap.String("key", key), zap.Int("bytes", len(data)))
+	return nil
+}
+
+// Keys lists a table's archive files dated within [from, to] (by day)
+func (s *archiveService) Keys(ctx context.Context, table string, from, to time.Time) ([]string, error) {
+	if err := checkArchiveTable(table); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	day := time.Date(from.UTC().Year(), from.UTC().Month(), from.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	for ; !day.After(to.UTC()); day = day.AddDate(0, 0, 1) {
+		dayKeys, err := s.store.List(ctx, archive.DayPrefix(s.cfg.Prefix, table, day))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, dayKeys...)
+	}
+	return keys, nil
+}
+
+// OrderEvents reads the events of an order_events archive file
+func (s *archiveService) OrderEvents(ctx context.Context, key string) ([]*domain.OrderEvent, error) {
+	data, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	records, err := archive.Decode[archive.OrderEventRecord](data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	events := make([]*domain.OrderEvent, len(records))
+	for i, record := range records {
+		events[i] = record.OrderEvent()
+	}
+	return events, nil
+}
+
+// AuditLogs reads the audit logs of an audit_logs archive file
+func (s *archiveService) AuditLogs(ctx context.Context, key string) ([]*domain.AuditLog, error) {
+	data, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	records, err := archive.Decode[archive.AuditLogRecord](data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	logs := make([]*domain.AuditLog, len(records))
+	for i, record := range records {
+		logs[i] = record.AuditLog()
+	}
+	return logs, nil
+}
+
+func checkArchiveTable(table string) error {
+	if table != archive.TableOrderEvents && table != archive.TableAuditLogs {
+		return fmt.Errorf("unknown archive table %q (want %s or %s)", table, archive.TableOrderEvents, archive.TableAuditLogs)
+	}
+	return nil
+}
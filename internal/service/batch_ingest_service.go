@@ -0,0 +1,392 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/archive"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/schemas"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Results of the orders in a batch file, as written to its result file
+const (
+	BatchResultCreated         = "created"
+	BatchResultDuplicate       = "duplicate"
+	BatchResultNoSupplierItems = "no_supplier_items"
+	BatchResultFailed          = "failed"
+)
+
+// batchRequiredColumns must be in the header of a batch order file. The other columns are
+// barcode, customer_phone, customer_email, address2, state, notes, tax, shipping,
+// payment_status and payment_method. There is one row per item; rows with the same
+// partner_order_id make up one order, whose customer, shipping and totals come from the first.
+var batchRequiredColumns = []string{
+	"partner_order_id", "sku", "title", "price", "quantity", "customer_name",
+	"street", "city", "postal_code", "country", "subtotal", "total",
+}
+
+var batchResultColumns = []string{"partner_order_id", "status", "supplier_order_id", "reference", "error"}
+
+// batchOrder is an order read from a batch file; Err is set when its rows could not be read
+type batchOrder struct {
+	Request CartSubmitRequest
+	Err     string
+}
+
+type batchIngestService struct {
+	cfg    config.BatchIngestConfig
+	store  archive.Store
+	repos  *repository.Repositories
+	carts  CartService
+	logger *zap.Logger
+}
+
+// NewBatchIngestService creates a service that submits the carts in CSV order files partners
+// drop in object storage, for partners that cannot call the API
+func NewBatchIngestService(cfg config.BatchIngestConfig, repos *repository.Repositories, carts CartService, logger *zap.Logger) *batchIngestService {
+	return &batchIngestService{
+		cfg: cfg,
+		store: archive.NewS3Store(archive.S3Config{
+			Endpoint:        cfg.Endpoint,
+			Region:          cfg.Region,
+			Bucket:          cfg.Bucket,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		}),
+		repos:  repos,
+		carts:  carts,
+		logger: logger,
+	}
+}
+
+// BatchResultKey is the key of the result file written for a batch order file
+func BatchResultKey(inboxKey string) string {
+	dir, name := path.Split(inboxKey)
+	return path.Join(path.Dir(strings.TrimSuffix(dir, "/")), "results", strings.TrimSuffix(name, ".csv")+".result.csv")
+}
+
+// Ingest submits the orders of every file under <prefix>/<partner id>/inbox/ that has no
+// result file yet, then writes the result file, which is also the acknowledgement: files
+// with one are never read again. Returns how many files were processed.
+func (s *batchIngestService) Ingest(ctx context.Context) (int, error) {
+	prefix := ""
+	if s.cfg.Prefix != "" {
+		prefix = s.cfg.Prefix + "/"
+	}
+	keys, err := s.store.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	done := make(map[string]bool)
+	var inbox []string
+	for _, key := range keys {
+		parts := strings.Split(strings.TrimPrefix(key, prefix), "/")
+		if len(parts) != 3 || !strings.HasSuffix(parts[2], ".csv") {
+			continue
+		}
+		switch parts[1] {
+		case "inbox":
+			inbox = append(inbox, key)
+		case "results":
+			done[key] = true
+		}
+	}
+
+	processed := 0
+	for _, key := range inbox {
+		if done[BatchResultKey(key)] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		if err := s.ingestFile(ctx, key, strings.Split(strings.TrimPrefix(key, prefix), "/")[0]); err != nil {
+			s.logger.Error("Failed to ingest batch order file", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// ingestFile submits the orders of one file and writes its result file. The returned error
+// means the result file was not written, so the file is tried again next time.
+func (s *batchIngestService) ingestFile(ctx context.Context, key, partnerID string) error {
+	var results [][]string
+	fail := func(reason string) error {
+		results = append(results, []string{"", BatchResultFailed, "", "", reason})
+		return s.writeResults(ctx, key, results)
+	}
+
+	id, err := uuid.Parse(partnerID)
+	if err != nil {
+		return fail("the folder is not a partner ID")
+	}
+	partner, err := s.repos.Partner.GetByID(ctx, id)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return fail("unknown partner")
+		}
+		return err
+	}
+	if !partner.IsActive {
+		return fail("partner is inactive")
+	}
+
+	data, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	orders, err := parseBatchOrders(data)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	created := 0
+	for _, order := range orders {
+		result := s.submit(ctx, partner, order)
+		if result[1] == BatchResultCreated {
+			created++
+		}
+		results = append(results, result)
+	}
+	s.logger.Info("Ingested batch order file",
+		zap.String("key", key),
+		zap.String("partner_id", partner.ID.String()),
+		zap.Int("orders", len(orders)),
+		zap.Int("created", created),
+	)
+	return s.writeResults(ctx, key, results)
+}
+
+// submit submits one order of a file like POST /v1/carts/submit and returns its result row
+func (s *batchIngestService) submit(ctx context.Context, partner *domain.Partner, order batchOrder) []string {
+	req := order.Request
+	failed := func(reason string) []string {
+		return []string{req.PartnerOrderID, BatchResultFailed, "", "", reason}
+	}
+	if order.Err != "" {
+		return failed(order.Err)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return failed(err.Error())
+	}
+	if fields, err := schemas.Validate(schemas.Cart, body); err != nil {
+		return failed(err.Error())
+	} else if len(fields) > 0 {
+		return failed(formatBatchFields(fields))
+	}
+
+	// A file put back in the inbox must not create its orders again
+	existing, err := s.repos.SupplierOrder.GetByPartnerIDAndPartnerOrderID(ctx, partner.ID, req.PartnerOrderID)
+	if err == nil {
+		return []string{req.PartnerOrderID, BatchResultDuplicate, existing.ID.String(), stringValue(existing.Reference), ""}
+	}
+	if _, ok := err.(*errors.ErrNotFound); !ok {
+		s.logger.Error("Failed to look up batch order", zap.String("partner_order_id", req.PartnerOrderID), zap.Error(err))
+		return failed("internal error")
+	}
+
+	created, hasSupplierSKU, err := s.carts.SubmitCart(ctx, partner, req)
+	if err != nil {
+		if e, ok := err.(*errors.ErrValidation); ok {
+			return failed(formatBatchFields(e.Fields))
+		}
+		s.logger.Error("Failed to submit batch order", zap.String("partner_order_id", req.PartnerOrderID), zap.Error(err))
+		return failed("internal error")
+	}
+	if !hasSupplierSKU {
+		return []string{req.PartnerOrderID, BatchResultNoSupplierItems, "", "", ""}
+	}
+	return []string{req.PartnerOrderID, BatchResultCreated, created.ID.String(), stringValue(created.Reference), ""}
+}
+
+func (s *batchIngestService) writeResults(ctx context.Context, key string, results [][]string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(batchResultColumns); err != nil {
+		return err
+	}
+	if err := w.WriteAll(results); err != nil {
+		return err
+	}
+	return s.store.Put(ctx, BatchResultKey(key), buf.Bytes())
+}
+
+// RunIngest picks up new batch order files at the given interval until ctx is cancelled
+func (s *batchIngestService) RunIngest(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Ingest(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to list batch order files", zap.Error(err))
+		}
+	}
+}
+
+// parseBatchOrders reads the orders of a batch file (see batchRequiredColumns), in file order. The
+// error is set when the file cannot be read at all; a row with bad values fails its order.
+func parseBatchOrders(data []byte) ([]batchOrder, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	var missing []string
+	for _, column := range batchRequiredColumns {
+		if _, ok := index[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	var orders []*batchOrder
+	byID := make(map[string]*batchOrder)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		line, _ := r.FieldPos(0)
+		value := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		optional := func(column string) *string {
+			if v := value(column); v != "" {
+				return &v
+			}
+			return nil
+		}
+		var rowErrs []string
+		number := func(column string) float64 {
+			v := value(column)
+			if v == "" {
+				return 0
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				rowErrs = append(rowErrs, fmt.Sprintf("line %d: %s is not a number", line, column))
+			}
+			return f
+		}
+
+		partnerOrderID := value("partner_order_id")
+		if partnerOrderID == "" && strings.Join(record, "") == "" {
+			continue
+		}
+		order, ok := byID[partnerOrderID]
+		if !ok {
+			order = &batchOrder{Request: CartSubmitRequest{
+				PartnerOrderID: partnerOrderID,
+				Customer: CustomerInfo{
+					Name:  value("customer_name"),
+					Phone: optional("customer_phone"),
+					Email: optional("customer_email"),
+				},
+				Shipping: ShippingAddress{
+					Street:     value("street"),
+					Address2:   optional("address2"),
+					City:       value("city"),
+					State:      optional("state"),
+					PostalCode: value("postal_code"),
+					Country:    value("country"),
+					Notes:      optional("notes"),
+				},
+				Totals: CartTotals{
+					Subtotal: number("subtotal"),
+					Tax:      number("tax"),
+					Shipping: number("shipping"),
+					Total:    number("total"),
+				},
+				PaymentStatus: value("payment_status"),
+				PaymentMethod: optional("payment_method"),
+			}}
+			byID[partnerOrderID] = order
+			orders = append(orders, order)
+		}
+
+		item := CartItem{
+			SKU:     value("sku"),
+			Barcode: optional("barcode"),
+			Title:   value("title"),
+			Price:   number("price"),
+		}
+		if quantity, err := strconv.Atoi(value("quantity")); err != nil {
+			rowErrs = append(rowErrs, fmt.Sprintf("line %d: quantity is not a whole number", line))
+		} else {
+			item.Quantity = quantity
+		}
+		order.Request.Items = append(order.Request.Items, item)
+		if len(rowErrs) > 0 && order.Err == "" {
+			order.Err = strings.Join(rowErrs, "; ")
+		}
+	}
+
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("the file has no orders")
+	}
+	parsed := make([]batchOrder, len(orders))
+	for i, order := range orders {
+		parsed[i] = *order
+	}
+	return parsed, nil
+}
+
+// formatBatchFields renders validation failures for the error column, ordered by field
+func formatBatchFields(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + ": " + fields[name]
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+const batchHeader = "partner_order_id,sku,title,price,quantity,customer_name,street,city,postal_code,country,subtotal,total\n"
+
+// memStore keeps batch files in memory
+type memStore struct {
+	files map[string][]byte
+}
+
+func (m *memStore) Put(ctx context.Context, key string, body []byte) error {
+	m.files[key] = body
+	return nil
+}
+
+func (m *memStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return m.files[key], nil
+}
+
+func (m *memStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for key := range m.files {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+type batchPartners struct {
+	repository.PartnerRepository
+	partner *domain.Partner
+}
+
+func (f *batchPartners) GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error) {
+	if id != f.partner.ID {
+		return nil, &errors.ErrNotFound{Resource: "partner", ID: id.String()}
+	}
+	return f.partner, nil
+}
+
+// batchOrders knows the orders of existing partner order IDs
+type batchOrders struct {
+	repository.SupplierOrderRepository
+	existing map[string]*domain.SupplierOrder
+}
+
+func (f *batchOrders) GetByPartnerIDAndPartnerOrderID(ctx context.Context, partnerID uuid.UUID, partnerOrderID string) (*domain.SupplierOrder, error) {
+	if order, ok := f.existing[partnerOrderID]; ok {
+		return order, nil
+	}
+	return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: partnerOrderID}
+}
+
+// batchCarts creates an order for every submitted cart
+type batchCarts struct {
+	CartService
+	submitted []CartSubmitRequest
+}
+
+func (f *batchCarts) SubmitCart(ctx context.Context, partner *domain.Partner, req CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+	f.submitted = append(f.submitted, req)
+	return &domain.SupplierOrder{ID: uuid.New(), PartnerID: partner.ID, PartnerOrderID: req.PartnerOrderID}, true, nil
+}
+
+func TestParseBatchOrders(t *testing.T) {
+	orders, err := parseBatchOrders([]byte(batchHeader +
+		"A-1,SKU-1,Mug,5,2,Jane Doe,Main St 1,Amman,11118,JO,15,15\n" +
+		"A-1,SKU-2,Plate,5,1,,,,,,,\n" +
+		"\n" +
+		"A-2,SKU-1,Mug,five,1,John Doe,Main St 2,Amman,11118,JO,5,5\n"))
+	if err != nil {
+		t.Fatalf("parseBatchOrders() error = %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("got %d orders, want 2", len(orders))
+	}
+
+	first := orders[0]
+	if first.Err != "" || first.Request.PartnerOrderID != "A-1" || len(first.Request.Items) != 2 {
+		t.Errorf("first order = %+v, want A-1 with 2 items", first)
+	}
+	if first.Request.Customer.Name != "Jane Doe" || first.Request.Totals.Total != 15 || first.Request.Items[0].Quantity != 2 {
+		t.Errorf("first order was not read from its first row: %+v", first.Request)
+	}
+	if want := "line 5: price is not a number"; orders[1].Err != want {
+		t.Errorf("second order error = %q, want %q", orders[1].Err, want)
+	}
+
+	if _, err := parseBatchOrders([]byte("partner_order_id,sku\nA-1,SKU-1\n")); err == nil || !strings.Contains(err.Error(), "missing columns: title") {
+		t.Errorf("parseBatchOrders() error = %v, want the missing columns", err)
+	}
+	if _, err := parseBatchOrders([]byte(batchHeader)); err == nil {
+		t.Error("parseBatchOrders() of a file without orders succeeded")
+	}
+}
+
+func TestBatchIngest(t *testing.T) {
+	partner := &domain.Partner{ID: uuid.New(), Name: "Zain Shop", IsActive: true}
+	inbox := "b2b-batch/" + partner.ID.String() + "/inbox/"
+	existing := &domain.SupplierOrder{ID: uuid.New(), PartnerOrderID: "A-2"}
+	store := &memStore{files: map[string][]byte{
+		inbox + "monday.csv": []byte(batchHeader +
+			"A-1,SKU-1,Mug,5,1,Jane Doe,Main St 1,Amman,11118,JO,5,5\n" +
+			"A-2,SKU-1,Mug,5,1,Jane Doe,Main St 1,Amman,11118,JO,5,5\n" +
+			"A-3,SKU-1,Mug,5,1,,Main St 1,Amman,11118,JO,5,5\n"),
+		inbox + "sunday.csv": []byte(batchHeader),
+		"b2b-batch/" + partner.ID.String() + "/results/sunday.result.csv": []byte("done"),
+		"b2b-batch/" + uuid.New().String() + "/inbox/other.csv":           []byte(batchHeader),
+	}}
+	carts := &batchCarts{}
+	s := &batchIngestService{
+		cfg:   config.BatchIngestConfig{Prefix: "b2b-batch"},
+		store: store,
+		repos: &repository.Repositories{
+			Partner:       &batchPartners{partner: partner},
+			SupplierOrder: &batchOrders{existing: map[string]*domain.SupplierOrder{"A-2": existing}},
+		},
+		carts:  carts,
+		logger: zap.NewNop(),
+	}
+
+	processed, err := s.Ingest(context.Background())
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if processed != 2 {
+		t.Errorf("processed %d files, want 2 (sunday.csv was done before)", processed)
+	}
+	if len(carts.submitted) != 1 || carts.submitted[0].PartnerOrderID != "A-1" {
+		t.Errorf("submitted %+v, want only A-1", carts.submitted)
+	}
+
+	result, ok := store.files[BatchResultKey(inbox+"monday.csv")]
+	if !ok {
+		t.Fatal("no result file for monday.csv")
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(result))).ReadAll()
+	if err != nil {
+		t.Fatalf("result file is not CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("result file has %d rows, want a header and 3 orders", len(rows))
+	}
+	for i, want := range []string{BatchResultCreated, BatchResultDuplicate, BatchResultFailed} {
+		if rows[i+1][1] != want {
+			t.Errorf("order %s status = %s, want %s", rows[i+1][0], rows[i+1][1], want)
+		}
+	}
+	if rows[2][2] != existing.ID.String() {
+		t.Errorf("duplicate order ID = %s, want %s", rows[2][2], existing.ID)
+	}
+	if !strings.Contains(rows[3][4], "customer.name") {
+		t.Errorf("failed order error = %q, want the customer name", rows[3][4])
+	}
+
+	// Files are acknowledged by their result file
+	if processed, _ := s.Ingest(context.Background()); processed != 0 {
+		t.Errorf("second Ingest() processed %d files, want 0", processed)
+	}
+}
+
+func TestBatchResultKey(t *testing.T) {
+	got := BatchResultKey("b2b-batch/p/inbox/orders-1.csv")
+	if want := "b2b-batch/p/results/orders-1.result.csv"; got != want {
+		t.Errorf("BatchResultKey() = %s, want %s", got, want)
+	}
+}
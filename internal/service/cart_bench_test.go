@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Benchmarks of the cart submission hot path. The repositories are in memory and count
+// their queries, so besides ns/op and allocs/op each benchmark reports queries/op: the
+// database round trips a cart costs, which bulk SKU lookup and caching are meant to cut.
+//
+//	go test -run '^$' -bench . -benchmem ./internal/service/
+//
+// Compare runs with benchstat before and after performance work; baseline numbers are
+// in the README.
+
+// benchCartItems is the size of a large cart
+const benchCartItems = 250
+
+// queryCounter counts repository calls
+type queryCounter struct {
+	queries int64
+}
+
+func (q *queryCounter) query() { atomic.AddInt64(&q.queries, 1) }
+
+// benchSKUMappings serves mappings from memory
+type benchSKUMappings struct {
+	repository.SKUMappingRepository
+	*queryCounter
+	bySKU        map[string]*domain.SKUMapping
+	byNormalized map[string][]*domain.SKUMapping
+	byBarcode    map[string][]*domain.SKUMapping
+}
+
+func (r *benchSKUMappings) GetBySKU(ctx context.Context, sku string) (*domain.SKUMapping, error) {
+	r.query()
+	mapping, ok := r.bySKU[sku]
+	if !ok {
+		return nil, &errors.ErrNotFound{Resource: "sku_mapping", ID: sku}
+	}
+	return mapping, nil
+}
+
+func (r *benchSKUMappings) ListByNormalizedSKU(ctx context.Context, normalizedSKU string) ([]*domain.SKUMapping, error) {
+	r.query()
+	return r.byNormalized[normalizedSKU], nil
+}
+
+func (r *benchSKUMappings) ListByBarcode(ctx context.Context, barcode string) ([]*domain.SKUMapping, error) {
+	r.query()
+	return r.byBarcode[barcode], nil
+}
+
+type benchCatalog struct {
+	repository.PartnerCatalogRepository
+	*queryCounter
+}
+
+func (r benchCatalog) ListByPartnerID(ctx context.Context, partnerID uuid.UUID) ([]*domain.SKUMapping, error) {
+	r.query()
+	return nil, nil
+}
+
+type benchUnmatchedSKUs struct {
+	repository.UnmatchedSKURepository
+	*queryCounter
+}
+
+func (r benchUnmatchedSKUs) Record(ctx context.Context, skus []*domain.UnmatchedSKU) error {
+	r.query()
+	return nil
+}
+
+type benchOrders struct {
+	repository.SupplierOrderRepository
+	*queryCounter
+	next int64
+}
+
+func (r *benchOrders) NextReferenceNumber(ctx context.Context) (int64, error) {
+	r.query()
+	return atomic.AddInt64(&r.next, 1), nil
+}
+
+func (r *benchOrders) Create(ctx context.Context, order *domain.SupplierOrder) error {
+	r.query()
+	order.ID = uuid.New()
+	return nil
+}
+
+type benchOrderItems struct {
+	repository.SupplierOrderItemRepository
+	*queryCounter
+}
+
+func (r benchOrderItems) CreateBatch(ctx context.Context, items []*domain.SupplierOrderItem) error {
+	r.query()
+	return nil
+}
+
+type benchEvents struct {
+	repository.OrderEventRepository
+	*queryCounter
+}
+
+func (r benchEvents) Create(ctx context.Context, event *domain.OrderEvent) error {
+	r.query()
+	return nil
+}
+
+// newBenchRepos returns repositories holding a mapping for every supplier SKU of
+// benchCart, and the counter of their queries
+func newBenchRepos(cfg *config.Config) (*repository.Repositories, *queryCounter) {
+	counter := &queryCounter{}
+	mappings := &benchSKUMappings{
+		queryCounter: counter,
+		bySKU:        make(map[string]*domain.SKUMapping),
+		byNormalized: make(map[string][]*domain.SKUMapping),
+		byBarcode:    make(map[string][]*domain.SKUMapping),
+	}
+	normalizer := NewSKUService(cfg, nil, zap.NewNop()).normalizer
+	for i := 0; i < benchCartItems; i++ {
+		barcode := fmt.Sprintf("62%011d", i)
+		mapping := &domain.SKUMapping{
+			SKU:              fmt.Sprintf("SUP-%04d", i),
+			ShopifyProductID: int64(1000 + i),
+			ShopifyVariantID: int64(5000 + i),
+			Barcode:          &barcode,
+			IsActive:         true,
+		}
+		mappings.bySKU[mapping.SKU] = mapping
+		normalized := normalizer.Normalize(mapping.SKU)
+		mappings.byNormalized[normalized] = append(mappings.byNormalized[normalized], mapping)
+		mappings.byBarcode[barcode] = append(mappings.byBarcode[barcode], mapping)
+	}
+
+	return &repository.Repositories{
+		SKUMapping:        mappings,
+		PartnerCatalog:    benchCatalog{queryCounter: counter},
+		UnmatchedSKU:      benchUnmatchedSKUs{queryCounter: counter},
+		SupplierOrder:     &benchOrders{queryCounter: counter},
+		SupplierOrderItem: benchOrderItems{queryCounter: counter},
+		OrderEvent:        benchEvents{queryCounter: counter},
+	}, counter
+}
+
+// benchCart is a cart of benchCartItems items: half match a supplier SKU exactly, a
+// tenth by normalized SKU and a tenth by barcode, the rest are the partner's own products
+func benchCart() CartSubmitRequest {
+	phone := "0791234567"
+	email := "Jane.Doe@example.com"
+	req := CartSubmitRequest{
+		PartnerOrderID: "BENCH-1",
+		Customer:       CustomerInfo{Name: "Jane Doe", Phone: &phone, Email: &email},
+		Shipping:       ShippingAddress{Street: "1 Main St", City: "Amman", PostalCode: "11118", Country: "JO"},
+		PaymentStatus:  "paid",
+	}
+	for i := 0; i < benchCartItems; i++ {
+		item := CartItem{
+			SKU:      fmt.Sprintf("SUP-%04d", i),
+			Title:    fmt.Sprintf("Widget %d", i),
+			Price:    9.5,
+			Quantity: 1 + i%3,
+		}
+		switch {
+		case i%10 < 5:
+		case i%10 == 5:
+			item.SKU = fmt.Sprintf(" sup-%04d ", i)
+		case i%10 == 6:
+			barcode := fmt.Sprintf("62%011d", i)
+			item.SKU = fmt.Sprintf("EAN-%d", i)
+			item.Barcode = &barcode
+		default:
+			item.SKU = fmt.Sprintf("OWN-%04d", i)
+		}
+		req.Items = append(req.Items, item)
+		req.Totals.Subtotal += item.Price * float64(item.Quantity)
+	}
+	req.Totals.Total = req.Totals.Subtotal
+	return req
+}
+
+func reportQueries(b *testing.B, counter *queryCounter) {
+	b.ReportMetric(float64(atomic.LoadInt64(&counter.queries))/float64(b.N), "queries/op")
+}
+
+func BenchmarkCheckCartForSupplierSKUs(b *testing.B) {
+	cfg := &config.Config{SKUNormalization: config.SKUNormalizationConfig{CaseFold: true, CollapseWhitespace: true}}
+	repos, counter := newBenchRepos(cfg)
+	skus := NewSKUService(cfg, repos, zap.NewNop())
+	req := benchCart()
+	partnerID := uuid.New()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := skus.CheckCartForSupplierSKUs(ctx, partnerID, req.Items, CatalogRestrictionIgnore); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	reportQueries(b, counter)
+}
+
+func BenchmarkCreateOrderFromCart(b *testing.B) {
+	cfg := &config.Config{SKUNormalization: config.SKUNormalizationConfig{CaseFold: true, CollapseWhitespace: true}}
+	repos, counter := newBenchRepos(cfg)
+	req := benchCart()
+	partnerID := uuid.New()
+	ctx := context.Background()
+
+	_, supplierItems, err := NewSKUService(cfg, repos, zap.NewNop()).CheckCartForSupplierSKUs(ctx, partnerID, req.Items, CatalogRestrictionIgnore)
+	if err != nil {
+		b.Fatal(err)
+	}
+	orders := &orderService{repos: repos, webhooks: noWebhooks{}, logger: zap.NewNop()}
+	atomic.StoreInt64(&counter.queries, 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := orders.CreateOrderFromCart(ctx, partnerID, req, supplierItems, "B2B"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	reportQueries(b, counter)
+}
+
+func BenchmarkCartJSON(b *testing.B) {
+	data, err := json.Marshal(benchCart())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var req CartSubmitRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Marshal", func(b *testing.B) {
+		req := benchCart()
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(req); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// EventTypeDuplicateSubmission is recorded on an order when the same cart is submitted again
+const EventTypeDuplicateSubmission = "duplicate_submission"
+
+// CartWarningDuplicateSubmission flags a response that is the order of an earlier submission
+const CartWarningDuplicateSubmission = "duplicate_submission"
+
+// duplicateSubmissionStatuses are the statuses of orders a resubmitted cart is answered
+// with; a cart whose order was rejected or cancelled may be submitted again
+var duplicateSubmissionStatuses = []domain.OrderStatus{
+	domain.OrderStatusPendingConfirmation,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusOnHold,
+	domain.OrderStatusShipped,
+	domain.OrderStatusDelivered,
+}
+
+// CartContentHash hashes what a cart orders and where it goes: items (in any order), customer,
+// shipping address and totals. The partner order ID, locale and payment details are left out,
+// so a cart resubmitted under a new partner order ID hashes the same; so do differences in
+// case, spacing and phone formatting.
+func CartContentHash(req CartSubmitRequest) string {
+	items := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = fmt.Sprintf("%s*%d@%d", strings.ToLower(strings.TrimSpace(item.SKU)), item.Quantity, toCents(item.Price))
+	}
+	sort.Strings(items)
+
+	var phone, email string
+	if req.Customer.Phone != nil {
+		phone = normalizePhone(*req.Customer.Phone)
+	}
+	if req.Customer.Email != nil {
+		email = strings.ToLower(strings.TrimSpace(*req.Customer.Email))
+	}
+	shipping := domain.Address{
+		Street:     req.Shipping.Street,
+		Address2:   req.Shipping.Address2,
+		City:       req.Shipping.City,
+		PostalCode: req.Shipping.PostalCode,
+		Country:    req.Shipping.Country,
+	}
+
+	parts := []string{
+		strings.Join(items, ","),
+		strings.ToLower(strings.Join(strings.Fields(req.Customer.Name), " ")),
+		phone,
+		email,
+		normalizeAddress(shipping),
+		fmt.Sprint(toCents(req.Totals.Total)),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// toCents rounds an amount to whole cents so float noise does not change a hash
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// CheckDuplicateSubmission returns the partner's order created from the same cart content
+// within DUPLICATE_SUBMISSION_WINDOW, or nil when there is none or the window is 0. The
+// resubmission is recorded as an event on that order. Submissions racing each other are not
+// caught; idempotency keys are the way to be sure.
+func (s *cartService) CheckDuplicateSubmission(ctx context.Context, partner *domain.Partner, req CartSubmitRequest) (*domain.SupplierOrder, error) {
+	window := s.cfg.Duplicates.SubmissionWindow
+	if window <= 0 {
+		return nil, nil
+	}
+
+	order, err := s.repos.SupplierOrder.GetLatestByContentHash(ctx, partner.ID, CartContentHash(req), time.Now().Add(-window), duplicateSubmissionStatuses)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Cart resubmitted, answering with the existing order",
+		zap.String("partner_id", partner.ID.String()),
+		zap.String("order_id", order.ID.String()),
+		zap.String("partner_order_id", req.PartnerOrderID),
+	)
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeDuplicateSubmission,
+		EventData: map[string]interface{}{
+			"partner_order_id": req.PartnerOrderID,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		s.logger.Warn("Failed to record duplicate submission event", zap.Error(err))
+	}
+
+	return order, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import "testing"
+
+func TestCartContentHash(t *testing.T) {
+	cart := func() CartSubmitRequest {
+		phone := "+962 79 123 4567"
+		return CartSubmitRequest{
+			PartnerOrderID: "PO-1",
+			Items: []CartItem{
+				{SKU: "SKU-A", Title: "A", Price: 10.5, Quantity: 1},
+				{SKU: "SKU-B", Title: "B", Price: 4, Quantity: 2},
+			},
+			Customer: CustomerInfo{Name: "Lina Haddad", Phone: &phone},
+			Shipping: ShippingAddress{Street: "12 Rainbow St", City: "Amman", PostalCode: "11181", Country: "JO"},
+			Totals:   CartTotals{Subtotal: 18.5, Total: 18.5},
+		}
+	}
+	base := CartContentHash(cart())
+
+	same := map[string]func(*CartSubmitRequest){
+		"new partner order ID": func(r *CartSubmitRequest) { r.PartnerOrderID = "PO-2" },
+		"items reordered":      func(r *CartSubmitRequest) { r.Items[0], r.Items[1] = r.Items[1], r.Items[0] },
+		"case and spacing": func(r *CartSubmitRequest) {
+			r.Items[0].SKU = " sku-a"
+			r.Customer.Name = "lina  haddad"
+			r.Shipping.Street = "12 rainbow st."
+		},
+		"phone formatting": func(r *CartSubmitRequest) {
+			phone := "0791234567"
+			r.Customer.Phone = &phone
+		},
+		"float noise": func(r *CartSubmitRequest) { r.Totals.Total = 18.500000001 },
+	}
+	for name, change := range same {
+		req := cart()
+		change(&req)
+		if got := CartContentHash(req); got != base {
+			t.Errorf("%s: hash changed, want the same cart", name)
+		}
+	}
+
+	different := map[string]func(*CartSubmitRequest){
+		"quantity": func(r *CartSubmitRequest) { r.Items[1].Quantity = 3 },
+		"price":    func(r *CartSubmitRequest) { r.Items[0].Price = 11 },
+		"extra item": func(r *CartSubmitRequest) {
+			r.Items = append(r.Items, CartItem{SKU: "SKU-C", Title: "C", Price: 1, Quantity: 1})
+		},
+		"customer": func(r *CartSubmitRequest) { r.Customer.Name = "Omar Haddad" },
+		"address":  func(r *CartSubmitRequest) { r.Shipping.City = "Irbid" },
+	}
+	for name, change := range different {
+		req := cart()
+		change(&req)
+		if got := CartContentHash(req); got == base {
+			t.Errorf("%s changed: hash is the same, want a different cart", name)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/phone"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// EventTypeTaxMismatch is recorded when the cart's tax differs from the expected rate
+const EventTypeTaxMismatch = "tax_mismatch"
+
+// EventTypeInvoiceSent is recorded when the draft order is sent as an invoice instead of completed
+const EventTypeInvoiceSent = "invoice_sent"
+
+type cartService struct {
+	cfg          *config.Config
+	repos        *repository.Repositories
+	orders       OrderService
+	skus         SKUService
+	shopify      ShopifyService
+	credit       CreditService
+	creditAlerts CreditAlertService
+	approvals    ApprovalService
+	logger       *zap.Logger
+}
+
+// NewCartService creates a new cart service
+func NewCartService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *cartService {
+	orders := NewOrderService(repos, logger)
+	shopifyService := NewShopifyService(cfg.Shopify, repos, logger)
+	credit := NewCreditService(repos, logger)
+	return &cartService{
+		cfg:          cfg,
+		repos:        repos,
+		orders:       orders,
+		skus:         NewSKUService(cfg, repos, logger),
+		shopify:      shopifyService,
+		credit:       credit,
+		creditAlerts: NewCreditAlertService(cfg, repos, credit, orders.webhooks, logger),
+		approvals:    NewApprovalService(cfg, repos, orders, shopifyService, logger),
+		logger:       logger,
+	}
+}
+
+// SubmitCart runs the full cart submission flow shared by the REST and gRPC APIs:
+// supplier SKU detection, order creation and Shopify draft order creation/completion.
+// Returns: order, hasSupplierSKU (false means nothing was created), error
+func (s *cartService) SubmitCart(
+	ctx context.Context,
+	partner *domain.Partner,
+	req CartSubmitRequest,
+) (*domain.SupplierOrder, bool, error) {
+	if fields := s.prepareCart(partner, &req); len(fields) > 0 {
+		return nil, false, &errors.ErrValidation{Message: "validation failed", Fields: fields}
+	}
+
+	// Check for supplier SKUs
+	hasSupplierSKU, supplierItems, err := s.skus.CheckCartForSupplierSKUs(ctx, partner.ID, req.Items, s.cfg.Tunables().CatalogRestrictionMode)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !hasSupplierSKU {
+		return nil, false, nil
+	}
+
+	// Carts over the credit limit are rejected, or held for review
+	holdForCredit, err := s.credit.CheckCart(ctx, partner, req.Totals.Total)
+	if err != nil {
+		return nil, true, err
+	}
+
+	// Create order
+	order, err := s.orders.CreateOrderFromCart(ctx, partner.ID, req, supplierItems, s.cfg.Orders.ReferencePrefix)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if holdForCredit {
+		if err := s.orders.HoldOrder(ctx, order.ID, CreditLimitHoldReason); err != nil {
+			s.logger.Error("Failed to hold order over the credit limit", zap.Error(err))
+		} else if held, err := s.repos.SupplierOrder.GetByID(ctx, order.ID); err == nil {
+			order = held
+		}
+	}
+
+	// Orders held for credit need a credit decision first, not a confirmation
+	if order.Status == domain.OrderStatusPendingConfirmation {
+		alertOrderAwaitingConfirmation(s.cfg, s.logger, order, partner)
+	}
+
+	// Warn the partner before carts start getting held or rejected for credit
+	if _, err := s.creditAlerts.Check(ctx, partner); err != nil {
+		s.logger.Error("Failed to check credit limit alerts", zap.String("partner_id", partner.ID.String()), zap.Error(err))
+	}
+
+	// Large orders need two admins to approve them before the Shopify order is completed
+	needsApproval := s.approvals.RequiresApproval(order.CartTotal)
+	if needsApproval {
+		if err := s.approvals.Require(ctx, order); err != nil {
+			return nil, true, err
+		}
+	}
+
+	// A tax discrepancy does not fail the cart, it is left on the order for review
+	if warning := CheckCartTax(s.cfg.Tax, req); warning != nil {
+		s.recordTaxMismatch(ctx, order, warning)
+	}
+
+	// Create Shopify draft order
+	// Get order items for draft order creation
+	orderItems, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		s.logger.Error("Failed to get order items for draft order", zap.Error(err))
+		// Don't fail the request, draft order can be created later
+		return order, true, nil
+	}
+
+	draftOrderID, draftOrderName, err := s.shopify.CreateDraftOrder(ctx, order, orderItems, partner.Name)
+	if err == shopify.ErrDryRun {
+		// Recorded as a pending operation; nothing exists in Shopify to complete
+		return order, true, nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to create Shopify draft order",
+			zap.String("operation", opShopifyCreateDraftOrder),
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_id", partner.ID.String()),
+			zap.Error(err),
+		)
+		alertShopifyFailure(s.cfg, s.logger, opShopifyCreateDraftOrder, order, err)
+		// Don't fail the request, draft order can be created later
+		return order, true, nil
+	}
+
+	// Update order with draft order ID
+	if err := s.repos.SupplierOrder.UpdateShopifyDraftOrderID(ctx, order.ID, draftOrderID, draftOrderName); err != nil {
+		s.logger.Warn("Failed to update order with draft order ID", zap.Error(err))
+	}
+	order.ShopifyDraftOrderID = &draftOrderID
+	if draftOrderName != "" {
+		order.ShopifyDraftOrderName = &draftOrderName
+	}
+
+	// Credit-terms partners pay later: the draft stays a draft until payment is recorded
+	if partner.PaymentTerms == domain.PaymentTermsInvoice {
+		s.sendInvoice(ctx, order, partner)
+		return order, true, nil
+	}
+
+	// The second approval completes the draft order (see approvalService.Approve)
+	if needsApproval {
+		return order, true, nil
+	}
+
+	// Orders held for the credit limit are completed when released (see ReleaseOrder)
+	if order.Status == domain.OrderStatusOnHold {
+		return order, true, nil
+	}
+
+	// Items can only be substituted while the draft is open: confirming the order completes it
+	if partner.Substitutions {
+		return order, true, nil
+	}
+
+	s.completeDraftOrder(ctx, order)
+	return order, true, nil
+}
+
+// ReleaseOrder releases a held order. The draft order of an order held for the credit limit
+// on submission is completed then, as SubmitCart would have done had the cart been within
+// the limit; drafts waiting for an invoice payment, a second approval or the confirmation
+// (substitutions) stay open.
+func (s *cartService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := s.orders.ReleaseOrder(ctx, orderID); err != nil {
+		return err
+	}
+
+	heldForCredit := order.HoldReason != nil && *order.HoldReason == CreditLimitHoldReason
+	if !heldForCredit || order.ShopifyDraftOrderID == nil || order.ShopifyOrderID != nil {
+		return nil
+	}
+	if s.approvals.RequiresApproval(order.CartTotal) {
+		return nil
+	}
+	partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+	if err != nil {
+		// The order is released either way; staff can complete the draft in Shopify
+		s.logger.Error("Failed to get partner for released order", zap.String("order_id", orderID.String()), zap.Error(err))
+		return nil
+	}
+	if partner.PaymentTerms == domain.PaymentTermsInvoice || partner.Substitutions {
+		return nil
+	}
+
+	s.completeDraftOrder(ctx, order)
+	return nil
+}
+
+// completeDraftOrder turns the order's draft order into a Shopify order. A failure is logged,
+// not returned: the draft exists either way and staff can complete it in Shopify.
+func (s *cartService) completeDraftOrder(ctx context.Context, order *domain.SupplierOrder) {
+	if err := s.shopify.CompleteOrder(ctx, order); err != nil {
+		s.logger.Error("Failed to complete Shopify draft order",
+			zap.String("operation", opShopifyCompleteOrder),
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_id", order.PartnerID.String()),
+			zap.Error(err),
+		)
+		alertShopifyFailure(s.cfg, s.logger, opShopifyCompleteOrder, order, err)
+	}
+}
+
+// sendInvoice sends the order's draft order as a Shopify invoice and marks the order invoiced
+func (s *cartService) sendInvoice(ctx context.Context, order *domain.SupplierOrder, partner *domain.Partner) {
+	invoiceURL, err := s.shopify.SendDraftOrderInvoice(ctx, *order.ShopifyDraftOrderID, partner.InvoiceEmail)
+	if err != nil {
+		// The draft exists either way; staff can send the invoice from Shopify admin
+		s.logger.Error("Failed to send Shopify draft order invoice",
+			zap.String("operation", opShopifySendInvoice),
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_id", partner.ID.String()),
+			zap.Error(err),
+		)
+		alertShopifyFailure(s.cfg, s.logger, opShopifySendInvoice, order, err)
+		return
+	}
+
+	if err := s.repos.SupplierOrder.UpdatePaymentStatus(ctx, order.ID, domain.PaymentStatusInvoiced, nil); err != nil {
+		s.logger.Warn("Failed to mark order invoiced", zap.Error(err))
+	} else {
+		order.PaymentStatus = domain.PaymentStatusInvoiced
+	}
+
+	data := map[string]interface{}{
+		"shopify_draft_order_id": *order.ShopifyDraftOrderID,
+		"invoice_url":            invoiceURL,
+	}
+	if partner.InvoiceEmail != nil {
+		data["email"] = *partner.InvoiceEmail
+	}
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeInvoiceSent,
+		EventData:       data,
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		s.logger.Warn("Failed to record invoice event", zap.Error(err))
+	}
+}
+
+// prepareCart resolves the cart's locale and checks the fields submission validates
+// before looking at SKUs. It returns the failing fields, if any.
+func (s *cartService) prepareCart(partner *domain.Partner, req *CartSubmitRequest) map[string]string {
+	fields := make(map[string]string)
+
+	// Customer-facing texts use the cart's locale, else the partner's
+	if req.Locale != nil {
+		if locale, ok := domain.ParseLocale(*req.Locale); ok {
+			localeStr := string(locale)
+			req.Locale = &localeStr
+		} else {
+			fields["locale"] = "unsupported locale, use en or ar"
+		}
+	} else if partner.Locale.IsValid() {
+		localeStr := string(partner.Locale)
+		req.Locale = &localeStr
+	}
+
+	// Payment methods are stored as codes; the labels of the partner guide still parse
+	if req.PaymentMethod != nil {
+		if method, ok := domain.ParsePaymentMethod(*req.PaymentMethod); ok {
+			methodStr := string(method)
+			req.PaymentMethod = &methodStr
+		} else if strings.TrimSpace(*req.PaymentMethod) == "" {
+			req.PaymentMethod = nil
+		} else {
+			fields["payment_method"] = paymentMethodError()
+		}
+	}
+
+	for i, item := range req.Items {
+		if _, _, ok := item.PriceBreakdown(); !ok {
+			fields[fmt.Sprintf("items[%d].price", i)] = "must equal list_price minus discount"
+		}
+	}
+
+	// Reject phone numbers that cannot be right for the shipping country
+	if req.Customer.Phone != nil && *req.Customer.Phone != "" {
+		if _, err := phone.Normalize(*req.Customer.Phone, req.Shipping.Country); err != nil {
+			fields["customer.phone"] = err.Error()
+		}
+	}
+
+	return fields
+}
+
+// paymentMethodError is the validation message for an unknown payment method
+func paymentMethodError() string {
+	methods := make([]string, len(domain.PaymentMethods))
+	for i, method := range domain.PaymentMethods {
+		methods[i] = string(method)
+	}
+	return "must be one of " + strings.Join(methods, ", ")
+}
+
+// recordTaxMismatch records a tax_mismatch event on the order
+func (s *cartService) recordTaxMismatch(ctx context.Context, order *domain.SupplierOrder, warning *CartWarning) {
+	s.logger.Warn("Cart tax does not match the expected rate",
+		zap.String("order_id", order.ID.String()),
+		zap.String("detail", warning.Message),
+	)
+
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeTaxMismatch,
+		EventData: map[string]interface{}{
+			"message": warning.Message,
+			"tax":     order.CartTax,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		s.logger.Warn("Failed to record tax mismatch event", zap.Error(err))
+	}
+}
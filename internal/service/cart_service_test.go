@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// cartOrder is the one order submitted in a cart test, shared by the order service and repository fakes
+type cartOrder struct {
+	order *domain.SupplierOrder
+}
+
+// cartOrderService creates the order, moves it on and off hold and confirms it
+type cartOrderService struct {
+	OrderService
+	*cartOrder
+}
+
+func (f *cartOrderService) CreateOrderFromCart(ctx context.Context, partnerID uuid.UUID, req CartSubmitRequest, supplierItems map[string]*CartItemMatch, referencePrefix string) (*domain.SupplierOrder, error) {
+	f.order = &domain.SupplierOrder{
+		ID:        uuid.New(),
+		PartnerID: partnerID,
+		Status:    domain.OrderStatusPendingConfirmation,
+		CartTotal: req.Totals.Total,
+	}
+	copied := *f.order
+	return &copied, nil
+}
+
+func (f *cartOrderService) HoldOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+	from := f.order.Status
+	f.order.Status = domain.OrderStatusOnHold
+	f.order.HeldFromStatus = &from
+	f.order.HoldReason = &reason
+	return nil
+}
+
+func (f *cartOrderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) error {
+	f.order.Status = domain.OrderStatusConfirmed
+	return nil
+}
+
+func (f *cartOrderService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	f.order.Status = *f.order.HeldFromStatus
+	f.order.HeldFromStatus = nil
+	f.order.HoldReason = nil
+	return nil
+}
+
+type cartOrders struct {
+	repository.SupplierOrderRepository
+	*cartOrder
+}
+
+func (f *cartOrders) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
+	copied := *f.order
+	return &copied, nil
+}
+
+func (f *cartOrders) UpdateShopifyDraftOrderID(ctx context.Context, id uuid.UUID, draftOrderID int64, name string) error {
+	f.order.ShopifyDraftOrderID = &draftOrderID
+	return nil
+}
+
+type cartOrderItems struct {
+	repository.SupplierOrderItemRepository
+	items []*domain.SupplierOrderItem
+}
+
+func (f cartOrderItems) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.SupplierOrderItem, error) {
+	return f.items, nil
+}
+
+type cartPartners struct {
+	repository.PartnerRepository
+	partner *domain.Partner
+}
+
+func (f cartPartners) GetByID(ctx context.Context, id uuid.UUID) (*domain.Partner, error) {
+	return f.partner, nil
+}
+
+type supplierSKUs struct {
+	SKUService
+}
+
+func (supplierSKUs) CheckCartForSupplierSKUs(ctx context.Context, partnerID uuid.UUID, items []CartItem, restrictionMode string) (bool, map[string]*CartItemMatch, error) {
+	return true, map[string]*CartItemMatch{}, nil
+}
+
+// overLimit holds every cart for the credit limit
+type overLimit struct {
+	CreditService
+}
+
+func (overLimit) CheckCart(ctx context.Context, partner *domain.Partner, total float64) (bool, error) {
+	return true, nil
+}
+
+type noCreditAlerts struct{}
+
+func (noCreditAlerts) Check(ctx context.Context, partner *domain.Partner) (int, error) {
+	return 0, nil
+}
+
+// draftShopify creates draft orders and records which orders were completed
+type draftShopify struct {
+	ShopifyService
+	completed []uuid.UUID
+}
+
+func (f *draftShopify) CreateDraftOrder(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, partnerName string) (int64, string, error) {
+	return 1001, "#D1001", nil
+}
+
+func (f *draftShopify) CompleteOrder(ctx context.Context, order *domain.SupplierOrder) error {
+	f.completed = append(f.completed, order.ID)
+	return nil
+}
+
+func TestSubmitCartHeldForCredit(t *testing.T) {
+	limit := 100.0
+	partner := &domain.Partner{
+		ID:                uuid.New(),
+		Name:              "Acme",
+		CreditLimit:       &limit,
+		CreditLimitAction: domain.CreditLimitActionHold,
+	}
+	held := &cartOrder{}
+	shopify := &draftShopify{}
+	cfg := &config.Config{}
+	cfg.SetTunables(config.Tunables{})
+	s := &cartService{
+		cfg: cfg,
+		repos: &repository.Repositories{
+			SupplierOrder:     &cartOrders{cartOrder: held},
+			SupplierOrderItem: cartOrderItems{},
+			Partner:           cartPartners{partner: partner},
+		},
+		orders:       &cartOrderService{cartOrder: held},
+		skus:         supplierSKUs{},
+		shopify:      shopify,
+		credit:       overLimit{},
+		creditAlerts: noCreditAlerts{},
+		approvals:    NewApprovalService(cfg, nil, nil, shopify, zap.NewNop()),
+		logger:       zap.NewNop(),
+	}
+
+	req := CartSubmitRequest{
+		Items:  []CartItem{{SKU: "SUP-1", Title: "Widget", Price: 250, Quantity: 1}},
+		Totals: CartTotals{Subtotal: 250, Total: 250},
+	}
+	order, _, err := s.SubmitCart(context.Background(), partner, req)
+	if err != nil {
+		t.Fatalf("SubmitCart() error = %v", err)
+	}
+	if order.Status != domain.OrderStatusOnHold {
+		t.Fatalf("order status = %s, want %s", order.Status, domain.OrderStatusOnHold)
+	}
+	if order.ShopifyDraftOrderID == nil {
+		t.Error("draft order was not created for the held order")
+	}
+	if len(shopify.completed) != 0 {
+		t.Fatal("CompleteOrder called for an order held for the credit limit")
+	}
+
+	if err := s.ReleaseOrder(context.Background(), order.ID); err != nil {
+		t.Fatalf("ReleaseOrder() error = %v", err)
+	}
+	if len(shopify.completed) != 1 || shopify.completed[0] != order.ID {
+		t.Errorf("completed orders after release = %v, want [%s]", shopify.completed, order.ID)
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// totalsTolerance absorbs rounding differences when totals are compared
+const totalsTolerance = 0.01
+
+// Cart validation warning codes
+const (
+	CartWarningPriceMismatch     = "price_mismatch"
+	CartWarningInsufficientStock = "insufficient_stock"
+	CartWarningUnavailable       = "unavailable"
+	CartWarningUnknownVariant    = "unknown_variant"
+	CartWarningSubtotalMismatch  = "subtotal_mismatch"
+	CartWarningTotalMismatch     = "total_mismatch"
+	CartWarningTaxMismatch       = "tax_mismatch"
+	CartWarningPricesUnchecked   = "prices_unchecked"
+	CartWarningCreditLimit       = "credit_limit_exceeded"
+)
+
+// Cart validation outcomes: what submitting the cart would do
+const (
+	CartOutcomeOrderCreated     = "order_created"
+	CartOutcomeNoSupplierItems  = "no_supplier_items" // 204, nothing is created
+	CartOutcomeValidationFailed = "validation_failed"
+	CartOutcomeDuplicate        = "duplicate_partner_order_id"
+)
+
+// CartWarning is a problem that does not stop submission but probably needs attention
+type CartWarning struct {
+	Code    string `json:"code"`
+	SKU     string `json:"sku,omitempty"`
+	Message string `json:"message"`
+}
+
+// CartItemValidation is the validation result of one cart item
+type CartItemValidation struct {
+	SKU          string `json:"sku"`
+	Quantity     int    `json:"quantity"`
+	SupplierItem bool   `json:"supplier_item"`
+	// Reason explains why the item is not a supplier item
+	Reason string `json:"reason,omitempty"`
+	// MatchMethod tells whether a supplier item matched by SKU, normalized SKU or barcode
+	MatchMethod string `json:"match_method,omitempty"`
+	// MatchedSKU is our SKU for items that did not match it exactly
+	MatchedSKU *string `json:"matched_sku,omitempty"`
+	// Shopify price and stock of supplier items, when they could be checked
+	ShopifyPrice      *float64 `json:"shopify_price,omitempty"`
+	AvailableQuantity *int     `json:"available_quantity,omitempty"`
+}
+
+// CartValidationResult tells whether a cart would be accepted and what to look at
+type CartValidationResult struct {
+	WouldSucceed bool                 `json:"would_succeed"`
+	Outcome      string               `json:"outcome"`
+	Errors       map[string]string    `json:"errors,omitempty"`
+	Warnings     []CartWarning        `json:"warnings"`
+	Items        []CartItemValidation `json:"items"`
+	// ExistingOrderID is set when partner_order_id was already submitted
+	ExistingOrderID *string `json:"existing_order_id,omitempty"`
+}
+
+// ValidateCart runs the checks of SubmitCart without creating anything, and also compares
+// supplier items with their Shopify price and stock and verifies the cart totals
+func (s *cartService) ValidateCart(ctx context.Context, partner *domain.Partner, req CartSubmitRequest) (*CartValidationResult, error) {
+	result := &CartValidationResult{
+		Errors:   s.prepareCart(partner, &req),
+		Warnings: []CartWarning{},
+	}
+
+	matches, err := s.skus.MatchCartItems(ctx, partner.ID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictionMode := s.cfg.Tunables().CatalogRestrictionMode
+	supplierItems := 0
+	result.Items = make([]CartItemValidation, len(matches))
+	for i, match := range matches {
+		result.Items[i] = CartItemValidation{
+			SKU:          match.Item.SKU,
+			Quantity:     match.Item.Quantity,
+			SupplierItem: match.Mapping != nil,
+			Reason:       match.Reason,
+			MatchMethod:  match.MatchMethod,
+		}
+		if match.Mapping != nil {
+			supplierItems++
+			if match.MatchMethod != domain.MatchMethodSKU {
+				result.Items[i].MatchedSKU = &match.Mapping.SKU
+			}
+		}
+		if match.Reason == SKUReasonNotInCatalog && restrictionMode == CatalogRestrictionReject {
+			result.Errors[match.Item.SKU] = "SKU is not in the partner catalog"
+		}
+	}
+
+	if supplierItems > 0 {
+		s.checkShopifyVariants(ctx, matches, result)
+	}
+	result.Warnings = append(result.Warnings, checkCartTotals(req)...)
+	if supplierItems > 0 {
+		if err := s.checkCredit(ctx, partner, req, result); err != nil {
+			return nil, err
+		}
+	}
+	if warning := CheckCartTax(s.cfg.Tax, req); warning != nil {
+		result.Warnings = append(result.Warnings, *warning)
+	}
+
+	// Submitting an already used partner_order_id fails
+	if supplierItems > 0 {
+		existing, err := s.repos.SupplierOrder.GetByPartnerIDAndPartnerOrderID(ctx, partner.ID, req.PartnerOrderID)
+		if err != nil {
+			if _, ok := err.(*errors.ErrNotFound); !ok {
+				return nil, err
+			}
+		} else {
+			id := existing.ID.String()
+			result.ExistingOrderID = &id
+		}
+	}
+
+	switch {
+	case len(result.Errors) > 0:
+		result.Outcome = CartOutcomeValidationFailed
+	case supplierItems == 0:
+		result.Outcome = CartOutcomeNoSupplierItems
+	case result.ExistingOrderID != nil:
+		result.Outcome = CartOutcomeDuplicate
+	default:
+		result.Outcome = CartOutcomeOrderCreated
+	}
+	result.WouldSucceed = len(result.Errors) == 0 && result.ExistingOrderID == nil
+
+	return result, nil
+}
+
+// checkCredit reports a cart total over the partner's remaining credit: an error when the
+// cart would be rejected, a warning when the order would be held
+func (s *cartService) checkCredit(ctx context.Context, partner *domain.Partner, req CartSubmitRequest, result *CartValidationResult) error {
+	if partner.CreditLimit == nil {
+		return nil
+	}
+	status, err := s.credit.Status(ctx, partner)
+	if err != nil {
+		return err
+	}
+	if status.Allows(req.Totals.Total) {
+		return nil
+	}
+
+	message := creditExceededMessage(status, req.Totals.Total)
+	if partner.CreditLimitAction == domain.CreditLimitActionReject {
+		result.Errors["totals.total"] = message
+		return nil
+	}
+	result.Warnings = append(result.Warnings, CartWarning{
+		Code:    CartWarningCreditLimit,
+		Message: message + "; the order would be held for review",
+	})
+	return nil
+}
+
+// checkShopifyVariants adds each supplier item's Shopify price and stock to the result
+// and warns about price differences and stock shortages
+func (s *cartService) checkShopifyVariants(ctx context.Context, matches []CartItemMatch, result *CartValidationResult) {
+	var variantIDs []int64
+	for _, match := range matches {
+		if match.Mapping != nil {
+			variantIDs = append(variantIDs, match.Mapping.ShopifyVariantID)
+		}
+	}
+
+	variants, err := s.shopify.GetVariants(ctx, variantIDs)
+	if err != nil {
+		if err != shopify.ErrDryRun {
+			s.logger.Warn("Failed to get Shopify variants for cart validation", zap.Error(err))
+		}
+		result.Warnings = append(result.Warnings, CartWarning{
+			Code:    CartWarningPricesUnchecked,
+			Message: "Shopify prices and stock could not be checked",
+		})
+		return
+	}
+
+	for i, match := range matches {
+		if match.Mapping == nil {
+			continue
+		}
+
+		variant, ok := variants[match.Mapping.ShopifyVariantID]
+		if !ok {
+			result.Warnings = append(result.Warnings, CartWarning{
+				Code:    CartWarningUnknownVariant,
+				SKU:     match.Item.SKU,
+				Message: "the mapped Shopify variant no longer exists",
+			})
+			continue
+		}
+
+		price := variant.Price
+		result.Items[i].ShopifyPrice = &price
+		result.Items[i].AvailableQuantity = variant.InventoryQuantity
+
+		if math.Abs(match.Item.Price-variant.Price) > totalsTolerance {
+			result.Warnings = append(result.Warnings, CartWarning{
+				Code:    CartWarningPriceMismatch,
+				SKU:     match.Item.SKU,
+				Message: fmt.Sprintf("cart price %.2f differs from the Shopify price %.2f", match.Item.Price, variant.Price),
+			})
+		}
+
+		switch {
+		case !variant.AvailableForSale:
+			result.Warnings = append(result.Warnings, CartWarning{
+				Code:    CartWarningUnavailable,
+				SKU:     match.Item.SKU,
+				Message: "the variant is not available for sale",
+			})
+		case variant.InventoryQuantity != nil && !variant.ContinueSelling && *variant.InventoryQuantity < match.Item.Quantity:
+			result.Warnings = append(result.Warnings, CartWarning{
+				Code:    CartWarningInsufficientStock,
+				SKU:     match.Item.SKU,
+				Message: fmt.Sprintf("%d requested, %d in stock", match.Item.Quantity, *variant.InventoryQuantity),
+			})
+		}
+	}
+}
+
+// checkCartTotals verifies the subtotal against the items and the total against its parts
+func checkCartTotals(req CartSubmitRequest) []CartWarning {
+	var warnings []CartWarning
+
+	itemsTotal := 0.0
+	for _, item := range req.Items {
+		itemsTotal += item.Price * float64(item.Quantity)
+	}
+	if math.Abs(itemsTotal-req.Totals.Subtotal) > totalsTolerance {
+		warnings = append(warnings, CartWarning{
+			Code:    CartWarningSubtotalMismatch,
+			Message: fmt.Sprintf("subtotal %.2f does not match the items (%.2f)", req.Totals.Subtotal, itemsTotal),
+		})
+	}
+
+	expectedTotal := req.Totals.Subtotal + req.Totals.Tax + req.Totals.Shipping
+	if math.Abs(expectedTotal-req.Totals.Total) > totalsTolerance {
+		warnings = append(warnings, CartWarning{
+			Code:    CartWarningTotalMismatch,
+			Message: fmt.Sprintf("total %.2f does not match subtotal + tax + shipping (%.2f)", req.Totals.Total, expectedTotal),
+		})
+	}
+
+	return warnings
+}
+
+// CheckCartTax compares the cart's tax with the configured rate of the shipping country
+// (applied to the subtotal). It returns nil when they match or the country has no rate.
+func CheckCartTax(cfg config.TaxConfig, req CartSubmitRequest) *CartWarning {
+	country := strings.ToUpper(strings.TrimSpace(req.Shipping.Country))
+	rate, ok := cfg.Rates[country]
+	if !ok {
+		return nil
+	}
+
+	expected := math.Round(req.Totals.Subtotal*rate*100) / 100
+	// Partners may round tax per line, so allow a cent per item
+	tolerance := totalsTolerance * float64(len(req.Items))
+	if math.Abs(expected-req.Totals.Tax) <= tolerance {
+		return nil
+	}
+
+	return &CartWarning{
+		Code:    CartWarningTaxMismatch,
+		Message: fmt.Sprintf("tax %.2f does not match the %s rate of %g%% (%.2f expected)", req.Totals.Tax, country, rate*100, expected),
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/mailer"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// AuditActionCreditThresholdReached is logged when a partner is alerted about its credit usage
+const AuditActionCreditThresholdReached = "credit_threshold_reached"
+
+type creditAlertService struct {
+	cfg      *config.Config
+	repos    *repository.Repositories
+	credit   CreditService
+	webhooks WebhookService
+	mailer   mailer.Mailer
+	logger   *zap.Logger
+}
+
+// NewCreditAlertService creates a service that alerts partners as their outstanding order
+// value approaches their credit limit
+func NewCreditAlertService(cfg *config.Config, repos *repository.Repositories, credit CreditService, webhooks WebhookService, logger *zap.Logger) *creditAlertService {
+	return &creditAlertService{
+		cfg:      cfg,
+		repos:    repos,
+		credit:   credit,
+		webhooks: webhooks,
+		mailer:   mailer.New(cfg.SMTP, logger),
+		logger:   logger,
+	}
+}
+
+// Check compares the partner's credit usage with the alert thresholds. When usage reached a
+// threshold the partner was not alerted about yet, the partner gets a credit.threshold_reached
+// webhook, an email if they subscribed to digests, and the alert is written to the audit log.
+// When usage dropped below the last threshold alerted, the threshold is lowered so it is
+// alerted again the next time it is reached. Returns the threshold alerted, or 0.
+func (s *creditAlertService) Check(ctx context.Context, partner *domain.Partner) (int, error) {
+	thresholds := s.cfg.CreditAlerts.Thresholds
+	if partner.CreditLimit == nil || len(thresholds) == 0 {
+		return 0, nil
+	}
+
+	status, err := s.credit.Status(ctx, partner)
+	if err != nil {
+		return 0, err
+	}
+	usage := status.Usage()
+	reached := 0
+	for _, threshold := range thresholds {
+		if usage >= float64(threshold) {
+			reached = threshold
+		}
+	}
+
+	previous, err := s.repos.CreditLimitAlert.GetThreshold(ctx, partner.ID)
+	if err != nil {
+		return 0, err
+	}
+	if reached == previous {
+		return 0, nil
+	}
+
+	// Setting the threshold claims the alert, so concurrent submissions never send it twice
+	claimed, err := s.repos.CreditLimitAlert.SetThreshold(ctx, partner.ID, previous, reached)
+	if err != nil {
+		return 0, err
+	}
+	if !claimed || reached < previous {
+		return 0, nil
+	}
+
+	data := map[string]interface{}{
+		"threshold":    reached,
+		"usage":        usage,
+		"credit_limit": *status.Limit,
+		"outstanding":  status.Outstanding,
+		"remaining":    *status.Remaining,
+		"action":       status.Action,
+	}
+	s.webhooks.NotifyPartnerEvent(partner, WebhookEventCreditThreshold, data)
+	s.emailAlert(ctx, partner, reached, status)
+
+	entry := &domain.AuditLog{
+		Actor:        "system",
+		Action:       AuditActionCreditThresholdReached,
+		ResourceType: "partner",
+		ResourceID:   partner.ID.String(),
+		Data:         data,
+	}
+	if err := s.repos.AuditLog.Create(ctx, entry); err != nil {
+		s.logger.Warn("Failed to write audit log", zap.String("action", entry.Action), zap.Error(err))
+	}
+
+	s.logger.Info("Credit limit alert sent",
+		zap.String("partner_id", partner.ID.String()),
+		zap.Int("threshold", reached),
+		zap.Float64("usage", usage),
+	)
+
+	return reached, nil
+}
+
+// emailAlert sends the alert to the partner's digest address, if they have one
+func (s *creditAlertService) emailAlert(ctx context.Context, partner *domain.Partner, threshold int, status *CreditStatus) {
+	subscription, err := s.repos.DigestSubscription.GetByPartnerID(ctx, partner.ID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); !ok {
+			s.logger.Error("Failed to get digest subscription for credit limit alert", zap.Error(err))
+		}
+		return
+	}
+
+	next := "held for review"
+	if status.Action == domain.CreditLimitActionReject {
+		next = "rejected"
+	}
+	body := fmt.Sprintf("%s has used %d%% of its credit limit.\n\n"+
+		"  Credit limit: %.2f\n  Outstanding:  %.2f\n  Remaining:    %.2f\n\n"+
+		"Orders that do not fit in the remaining credit will be %s. Record payments to free up credit.\n",
+		partner.Name, threshold, *status.Limit, status.Outstanding, *status.Remaining, next)
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      []string{subscription.Email},
+		Subject: fmt.Sprintf("You have used %d%% of your credit limit", threshold),
+		Body:    body,
+	}); err != nil {
+		s.logger.Error("Failed to email credit limit alert",
+			zap.String("partner_id", partner.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/mailer"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type outstandingOrders struct {
+	repository.SupplierOrderRepository
+	outstanding float64
+}
+
+func (r *outstandingOrders) SumOutstanding(ctx context.Context, partnerID uuid.UUID, statuses []domain.OrderStatus) (float64, error) {
+	return r.outstanding, nil
+}
+
+type memCreditAlerts struct {
+	thresholds map[uuid.UUID]int
+}
+
+func (r *memCreditAlerts) GetThreshold(ctx context.Context, partnerID uuid.UUID) (int, error) {
+	return r.thresholds[partnerID], nil
+}
+
+func (r *memCreditAlerts) SetThreshold(ctx context.Context, partnerID uuid.UUID, from, to int) (bool, error) {
+	if r.thresholds[partnerID] != from {
+		return false, nil
+	}
+	r.thresholds[partnerID] = to
+	return true, nil
+}
+
+type recordedAuditLogs struct {
+	repository.AuditLogRepository
+	entries []*domain.AuditLog
+}
+
+func (r *recordedAuditLogs) Create(ctx context.Context, entry *domain.AuditLog) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type noDigests struct {
+	repository.DigestSubscriptionRepository
+}
+
+func (noDigests) GetByPartnerID(ctx context.Context, partnerID uuid.UUID) (*domain.DigestSubscription, error) {
+	return nil, &errors.ErrNotFound{Resource: "digest_subscription", ID: partnerID.String()}
+}
+
+// partnerWebhooks records partner-level webhooks
+type partnerWebhooks struct {
+	WebhookService
+	events []string
+}
+
+func (w *partnerWebhooks) NotifyPartnerEvent(partner *domain.Partner, eventType string, data map[string]interface{}) {
+	w.events = append(w.events, eventType)
+}
+
+func TestCreditAlertServiceCheck(t *testing.T) {
+	limit := 1000.0
+	partner := &domain.Partner{ID: uuid.New(), Name: "Acme", CreditLimit: &limit}
+
+	orders := &outstandingOrders{}
+	audit := &recordedAuditLogs{}
+	webhooks := &partnerWebhooks{}
+	repos := &repository.Repositories{
+		SupplierOrder:      orders,
+		CreditLimitAlert:   &memCreditAlerts{thresholds: make(map[uuid.UUID]int)},
+		AuditLog:           audit,
+		DigestSubscription: noDigests{},
+	}
+	s := &creditAlertService{
+		cfg:      &config.Config{CreditAlerts: config.CreditAlertsConfig{Thresholds: []int{80, 95}}},
+		repos:    repos,
+		credit:   NewCreditService(repos, zap.NewNop()),
+		webhooks: webhooks,
+		mailer:   mailer.New(config.SMTPConfig{}, zap.NewNop()),
+		logger:   zap.NewNop(),
+	}
+
+	// Outstanding value after each step, and the threshold alerted at that step
+	steps := []struct {
+		outstanding float64
+		want        int
+	}{
+		{outstanding: 500},
+		{outstanding: 800, want: 80},
+		// Still above 80%: not alerted again
+		{outstanding: 900},
+		{outstanding: 990, want: 95},
+		// Straight past both thresholds only alerts the highest
+		{outstanding: 1200},
+		// A payment brings usage below 80%, which re-arms both thresholds
+		{outstanding: 300},
+		{outstanding: 960, want: 95},
+	}
+	alerts := 0
+	for i, step := range steps {
+		orders.outstanding = step.outstanding
+		got, err := s.Check(context.Background(), partner)
+		if err != nil {
+			t.Fatalf("step %d: Check() error = %v", i, err)
+		}
+		if got != step.want {
+			t.Errorf("step %d (outstanding %.0f): Check() = %d, want %d", i, step.outstanding, got, step.want)
+		}
+		if step.want != 0 {
+			alerts++
+		}
+	}
+
+	if len(webhooks.events) != alerts || len(audit.entries) != alerts {
+		t.Errorf("webhooks = %v, audit entries = %d; want %d of each", webhooks.events, len(audit.entries), alerts)
+	}
+	for _, event := range webhooks.events {
+		if event != WebhookEventCreditThreshold {
+			t.Errorf("webhook event = %q, want %q", event, WebhookEventCreditThreshold)
+		}
+	}
+
+	// Partners without a credit limit are never alerted
+	orders.outstanding = 1e6
+	if got, err := s.Check(context.Background(), &domain.Partner{ID: uuid.New()}); got != 0 || err != nil {
+		t.Errorf("Check() without a limit = %d, %v; want 0, nil", got, err)
+	}
+}
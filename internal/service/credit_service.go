@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// CreditLimitHoldReason is the hold reason of orders held for exceeding the credit limit
+const CreditLimitHoldReason = "Credit limit exceeded"
+
+// CreditStatus is a partner's credit limit and how much of it is used
+type CreditStatus struct {
+	// Limit is nil for partners without a credit limit; Remaining is then nil too
+	Limit       *float64
+	Outstanding float64
+	Remaining   *float64
+	Action      domain.CreditLimitAction
+}
+
+// Allows reports whether an order of amount fits in the remaining credit
+func (c *CreditStatus) Allows(amount float64) bool {
+	return c.Remaining == nil || amount <= *c.Remaining+totalsTolerance
+}
+
+// Usage returns the outstanding value as a percentage of the limit, rounded to two
+// decimals; 0 without a limit
+func (c *CreditStatus) Usage() float64 {
+	if c.Limit == nil {
+		return 0
+	}
+	if *c.Limit <= 0 {
+		if c.Outstanding > 0 {
+			return 100
+		}
+		return 0
+	}
+	return roundAmount(c.Outstanding / *c.Limit * 100)
+}
+
+type creditService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewCreditService creates a new partner credit limit service
+func NewCreditService(repos *repository.Repositories, logger *zap.Logger) *creditService {
+	return &creditService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Status returns the partner's credit limit and outstanding order value: the cart totals of
+// its open and fulfilled orders that are not marked paid
+func (s *creditService) Status(ctx context.Context, partner *domain.Partner) (*CreditStatus, error) {
+	outstanding, err := s.repos.SupplierOrder.SumOutstanding(ctx, partner.ID, domain.OutstandingStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &CreditStatus{
+		Limit:       partner.CreditLimit,
+		Outstanding: roundAmount(outstanding),
+		Action:      partner.CreditLimitAction,
+	}
+	if partner.CreditLimit != nil {
+		remaining := roundAmount(*partner.CreditLimit - outstanding)
+		status.Remaining = &remaining
+	}
+	return status, nil
+}
+
+// CheckCart checks a cart total against the partner's remaining credit. It returns
+// hold=true when the order should be created ON_HOLD, and ErrValidation when the partner's
+// action is reject.
+func (s *creditService) CheckCart(ctx context.Context, partner *domain.Partner, total float64) (bool, error) {
+	if partner.CreditLimit == nil {
+		return false, nil
+	}
+
+	status, err := s.Status(ctx, partner)
+	if err != nil {
+		return false, err
+	}
+	if status.Allows(total) {
+		return false, nil
+	}
+
+	if partner.CreditLimitAction == domain.CreditLimitActionReject {
+		return false, &errors.ErrValidation{
+			Message: "credit limit exceeded",
+			Fields:  map[string]string{"totals.total": creditExceededMessage(status, total)},
+		}
+	}
+	return true, nil
+}
+
+// creditExceededMessage explains by how much a cart total goes over the remaining credit
+func creditExceededMessage(status *CreditStatus, total float64) string {
+	return fmt.Sprintf("order total %.2f exceeds the remaining credit %.2f (limit %.2f)", total, *status.Remaining, *status.Limit)
+}
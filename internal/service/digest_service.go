@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/mailer"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// digestListLimit caps the pending orders and failed webhooks listed in one digest
+const digestListLimit = 20
+
+// PartnerDigest is what a partner's daily digest reports for one period
+type PartnerDigest struct {
+	Partner        *domain.Partner
+	From           time.Time
+	To             time.Time
+	StatusChanges  []*domain.OrderStatusChange
+	PendingOrders  []*domain.SupplierOrder
+	FailedWebhooks []*domain.WebhookDelivery
+}
+
+// IsEmpty reports whether there is nothing to tell the partner
+func (d *PartnerDigest) IsEmpty() bool {
+	return len(d.StatusChanges) == 0 && len(d.PendingOrders) == 0 && len(d.FailedWebhooks) == 0
+}
+
+var digestTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"orderRef": digestOrderRef,
+	"age":      digestAge,
+}).Parse(`Daily order digest for {{.Digest.Partner.Name}}
+{{.From}} - {{.To}} ({{.Zone}})
+{{with .Digest.StatusChanges}}
+Status changes ({{len .}}):
+{{range .}}  - {{orderRef .Reference .PartnerOrderID}}: {{.From}} -> {{.To}} at {{$.Time .ChangedAt}}
+{{end}}{{end}}{{with .Digest.PendingOrders}}
+Waiting for confirmation{{if $.PendingTruncated}} (oldest {{len .}}){{else}} ({{len .}}){{end}}:
+{{range .}}  - {{orderRef .Reference .PartnerOrderID}}: submitted {{age .CreatedAt $.Digest.To}} ago
+{{end}}{{end}}{{with .Digest.FailedWebhooks}}
+Failed webhook deliveries{{if $.WebhooksTruncated}} (latest {{len .}}){{else}} ({{len .}}){{end}}:
+{{range .}}  - {{.EventType}} at {{$.Time .CreatedAt}}: {{if .ResponseStatus}}HTTP {{.ResponseStatus}}{{else if .Error}}{{.Error}}{{else}}failed{{end}}
+{{end}}{{end}}`))
+
+// digestView adapts a digest for the template, formatting times in the digest time zone
+type digestView struct {
+	Digest            *PartnerDigest
+	From              string
+	To                string
+	Zone              string
+	PendingTruncated  bool
+	WebhooksTruncated bool
+	location          *time.Location
+}
+
+func (v digestView) Time(t time.Time) string {
+	return t.In(v.location).Format("2006-01-02 15:04")
+}
+
+func digestOrderRef(reference *string, partnerOrderID string) string {
+	if reference != nil {
+		return fmt.Sprintf("%s (%s)", *reference, partnerOrderID)
+	}
+	return partnerOrderID
+}
+
+func digestAge(since, now time.Time) string {
+	age := now.Sub(since)
+	if age >= 48*time.Hour {
+		return fmt.Sprintf("%d days", int(age/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%d hours", int(age/time.Hour))
+}
+
+type digestService struct {
+	cfg    *config.Config
+	repos  *repository.Repositories
+	mailer mailer.Mailer
+	logger *zap.Logger
+}
+
+// NewDigestService creates a new partner email digest service
+func NewDigestService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *digestService {
+	return &digestService{
+		cfg:    cfg,
+		repos:  repos,
+		mailer: mailer.New(cfg.SMTP, logger),
+		logger: logger,
+	}
+}
+
+// Subscribe opts the partner into the daily digest, or changes its address or send time.
+// An empty sendTime uses DIGEST_SEND_TIME.
+func (s *digestService) Subscribe(ctx context.Context, partner *domain.Partner, email, sendTime string) (*domain.DigestSubscription, error) {
+	fields := make(map[string]string)
+
+	address, err := mail.ParseAddress(email)
+	if err != nil {
+		fields["email"] = "must be a valid email address"
+	}
+	if sendTime == "" {
+		sendTime = s.cfg.Digest.SendTime
+	}
+	if _, err := time.Parse("15:04", sendTime); err != nil {
+		fields["send_time"] = "must be HH:MM (24-hour clock)"
+	}
+	if len(fields) > 0 {
+		return nil, &errors.ErrValidation{Message: "validation failed", Fields: fields}
+	}
+
+	subscription := &domain.DigestSubscription{
+		PartnerID: partner.ID,
+		Email:     address.Address,
+		SendTime:  sendTime,
+	}
+	if err := s.repos.DigestSubscription.Upsert(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Unsubscribe opts the partner out of the daily digest
+func (s *digestService) Unsubscribe(ctx context.Context, partner *domain.Partner) error {
+	return s.repos.DigestSubscription.Delete(ctx, partner.ID)
+}
+
+// BuildDigest collects the partner's status changes and failed webhooks in [from, to)
+// and the orders still waiting for confirmation
+func (s *digestService) BuildDigest(ctx context.Context, partner *domain.Partner, from, to time.Time) (*PartnerDigest, error) {
+	changes, err := s.repos.OrderEvent.ListStatusChangesByPartnerID(ctx, partner.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repos.SupplierOrder.ListOldestByPartnerIDAndStatus(ctx, partner.ID, domain.OrderStatusPendingConfirmation, digestListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	failed, err := s.repos.WebhookDelivery.ListFailedByPartnerID(ctx, partner.ID, from, to, digestListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PartnerDigest{
+		Partner:        partner,
+		From:           from,
+		To:             to,
+		StatusChanges:  changes,
+		PendingOrders:  pending,
+		FailedWebhooks: failed,
+	}, nil
+}
+
+// RenderDigest renders the digest email
+func (s *digestService) RenderDigest(digest *PartnerDigest) (string, string, error) {
+	location := s.cfg.Digest.Location
+	view := digestView{
+		Digest:            digest,
+		From:              digest.From.In(location).Format("2006-01-02 15:04"),
+		To:                digest.To.In(location).Format("2006-01-02 15:04"),
+		Zone:              location.String(),
+		PendingTruncated:  len(digest.PendingOrders) == digestListLimit,
+		WebhooksTruncated: len(digest.FailedWebhooks) == digestListLimit,
+		location:          location,
+	}
+
+	var body strings.Builder
+	if err := digestTemplate.Execute(&body, view); err != nil {
+		return "", "", err
+	}
+
+	subject := fmt.Sprintf("Order digest %s: %d status changes, %d pending, %d failed webhooks",
+		digest.To.In(location).Format("2006-01-02"),
+		len(digest.StatusChanges), len(digest.PendingOrders), len(digest.FailedWebhooks))
+	return subject, body.String(), nil
+}
+
+// SendDue sends the digest to every subscribed partner whose send time has passed since
+// their last digest. A digest covers the 24 hours before its send time. Sends are claimed
+// before the email goes out, so several API instances never send the same digest twice
+// (a failed send is logged and not retried).
+func (s *digestService) SendDue(ctx context.Context, now time.Time) {
+	subscriptions, err := s.repos.DigestSubscription.List(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list digest subscriptions", zap.Error(err))
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			return
+		}
+
+		due, err := lastDigestDue(subscription.SendTime, now, s.cfg.Digest.Location)
+		if err != nil {
+			s.logger.Warn("Invalid digest send time",
+				zap.String("partner_id", subscription.PartnerID.String()),
+				zap.String("send_time", subscription.SendTime),
+			)
+			continue
+		}
+		if subscription.LastSentAt != nil && !subscription.LastSentAt.Before(due) {
+			continue
+		}
+		// New subscriptions start with the next send time instead of a catch-up digest
+		if subscription.LastSentAt == nil && subscription.CreatedAt.After(due) {
+			continue
+		}
+
+		if err := s.sendDigest(ctx, subscription, due, now); err != nil {
+			s.logger.Error("Failed to send partner digest",
+				zap.String("partner_id", subscription.PartnerID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (s *digestService) sendDigest(ctx context.Context, subscription *domain.DigestSubscription, due, now time.Time) error {
+	claimed, err := s.repos.DigestSubscription.ClaimSend(ctx, subscription.PartnerID, due, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	partner, err := s.repos.Partner.GetByID(ctx, subscription.PartnerID)
+	if err != nil {
+		return err
+	}
+
+	digest, err := s.BuildDigest(ctx, partner, due.Add(-24*time.Hour), due)
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		return nil
+	}
+
+	subject, body, err := s.RenderDigest(digest)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      []string{subscription.Email},
+		Subject: subject,
+		Body:    body,
+	}); err != nil {
+		return err
+	}
+
+	s.logger.Info("Partner digest sent",
+		zap.String("partner_id", partner.ID.String()),
+		zap.Int("status_changes", len(digest.StatusChanges)),
+		zap.Int("pending_orders", len(digest.PendingOrders)),
+		zap.Int("failed_webhooks", len(digest.FailedWebhooks)),
+	)
+	return nil
+}
+
+// RunDigests checks every minute for digests that are due. It returns when ctx is cancelled.
+func (s *digestService) RunDigests(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.SendDue(ctx, now)
+		}
+	}
+}
+
+// lastDigestDue returns the most recent occurrence of sendTime (HH:MM in location) at or before now
+func lastDigestDue(sendTime string, now time.Time, location *time.Location) (time.Time, error) {
+	clock, err := time.Parse("15:04", sendTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := now.In(location)
+	due := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+	if due.After(local) {
+		due = due.AddDate(0, 0, -1)
+	}
+	return due, nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+)
+
+// EventTypeDraftOrderBackfilled is recorded when a backfill gives an order its Shopify draft order
+const EventTypeDraftOrderBackfilled = "draft_order_backfilled"
+
+// draftBackfillAttempts is how often one order is tried before it is reported as failed
+const draftBackfillAttempts = 3
+
+// DefaultDraftBackfillStatuses are the statuses whose orders still need a draft order to be fulfilled
+var DefaultDraftBackfillStatuses = []domain.OrderStatus{
+	domain.OrderStatusPendingConfirmation,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusOnHold,
+}
+
+// DraftBackfillOptions select the orders a backfill creates draft orders for and how fast
+type DraftBackfillOptions struct {
+	Statuses []domain.OrderStatus
+	// Since skips orders created before it; zero includes all
+	Since time.Time
+	// BatchSize is how many orders are read at a time
+	BatchSize int
+	// Rate caps draft orders created per second, to stay within the Shopify API limits
+	Rate float64
+	// Limit stops after this many orders; 0 processes all
+	Limit int
+	// DryRun lists the orders without calling Shopify
+	DryRun bool
+}
+
+// DraftBackfillFailure is an order the backfill gave up on
+type DraftBackfillFailure struct {
+	OrderID uuid.UUID
+	Error   string
+}
+
+// DraftBackfillProgress counts what a backfill has done so far
+type DraftBackfillProgress struct {
+	Processed int
+	Created   int
+	// Linked are orders whose draft order already existed in Shopify (e.g. from an
+	// earlier run that failed before saving it) and was attached instead of created again
+	Linked   int
+	Failed   int
+	Failures []DraftBackfillFailure
+}
+
+type draftBackfillService struct {
+	cfg    *config.Config
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewDraftBackfillService creates a new draft order backfill service
+func NewDraftBackfillService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *draftBackfillService {
+	return &draftBackfillService{
+		cfg:    cfg,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Run creates Shopify draft orders for orders that have none, newest first, in batches.
+// report is called after every batch. Orders that fail are retried a few times and then
+// left without a draft order, so running the backfill again picks them up.
+// Drafts are left open for staff to complete: historical orders may have been fulfilled outside Shopify.
+func (s *draftBackfillService) Run(ctx context.Context, opts DraftBackfillOptions, report func(DraftBackfillProgress)) (*DraftBackfillProgress, error) {
+	if len(opts.Statuses) == 0 {
+		opts.Statuses = DefaultDraftBackfillStatuses
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = 2
+	}
+
+	shopifyService := NewShopifyService(s.cfg.Shopify, s.repos, s.logger)
+	throttle := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+	defer throttle.Stop()
+
+	partnerNames := make(map[uuid.UUID]string)
+	progress := &DraftBackfillProgress{}
+	var cursor *domain.OrderCursor
+	for {
+		orders, err := s.repos.SupplierOrder.ListWithoutShopifyOrderAfter(ctx, opts.Statuses, opts.Since, cursor, opts.BatchSize)
+		if err != nil {
+			return progress, err
+		}
+		if len(orders) == 0 {
+			return progress, nil
+		}
+		cursor = domain.CursorAfter(orders[len(orders)-1])
+
+		for _, order := range orders {
+			if opts.Limit > 0 && progress.Processed >= opts.Limit {
+				return progress, nil
+			}
+			progress.Processed++
+			if opts.DryRun {
+				continue
+			}
+
+			linked, err := s.backfillOrder(ctx, shopifyService, throttle.C, order, partnerNames)
+			switch {
+			case err == shopify.ErrDryRun:
+				// Recorded as a pending operation on the order; nothing to count
+			case err != nil:
+				if ctx.Err() != nil {
+					return progress, ctx.Err()
+				}
+				progress.Failed++
+				progress.Failures = append(progress.Failures, DraftBackfillFailure{OrderID: order.ID, Error: err.Error()})
+				s.logger.Warn("Failed to backfill draft order",
+					zap.String("order_id", order.ID.String()),
+					zap.Error(err),
+				)
+			case linked:
+				progress.Linked++
+			default:
+				progress.Created++
+			}
+		}
+
+		if report != nil {
+			report(*progress)
+		}
+	}
+}
+
+// backfillOrder gives one order its draft order, retrying transient failures. It returns
+// true when an existing draft order was linked instead of created.
+func (s *draftBackfillService) backfillOrder(
+	ctx context.Context,
+	shopifyService *shopifyService,
+	throttle <-chan time.Time,
+	order *domain.SupplierOrder,
+	partnerNames map[uuid.UUID]string,
+) (bool, error) {
+	partnerName, ok := partnerNames[order.PartnerID]
+	if !ok {
+		partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get partner: %w", err)
+		}
+		partnerName = partner.Name
+		partnerNames[order.PartnerID] = partnerName
+	}
+
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get order items: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= draftBackfillAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-throttle:
+		}
+
+		linked, err := s.createOrLinkDraft(ctx, shopifyService, order, items, partnerName)
+		if err == nil || err == shopify.ErrDryRun {
+			return linked, err
+		}
+		lastErr = err
+
+		// Back off before retrying; Shopify throttling clears within seconds
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(time.Duration(attempt) * 2 * time.Second):
+		}
+	}
+	return false, lastErr
+}
+
+func (s *draftBackfillService) createOrLinkDraft(
+	ctx context.Context,
+	shopifyService *shopifyService,
+	order *domain.SupplierOrder,
+	items []*domain.SupplierOrderItem,
+	partnerName string,
+) (bool, error) {
+	// A previous attempt may have created the draft and failed before saving its ID
+	draftOrderID, draftOrderName, err := shopifyService.FindDraftOrder(ctx, order, partnerName)
+	if err != nil && !errors.Is(err, shopify.ErrDryRun) {
+		return false, err
+	}
+	linked := draftOrderID != 0
+
+	if !linked {
+		draftOrderID, draftOrderName, err = shopifyService.CreateDraftOrder(ctx, order, items, partnerName)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.repos.SupplierOrder.UpdateShopifyDraftOrderID(ctx, order.ID, draftOrderID, draftOrderName); err != nil {
+		return false, fmt.Errorf("failed to save draft order ID %d: %w", draftOrderID, err)
+	}
+
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeDraftOrderBackfilled,
+		EventData: map[string]interface{}{
+			"shopify_draft_order_id": draftOrderID,
+			"linked_existing":        linked,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		s.logger.Warn("Failed to record draft order backfill event", zap.Error(err))
+	}
+
+	return linked, nil
+}
@@ -1,5 +1,7 @@
 package service
 
+import "math"
+
 // CartSubmitRequest represents the cart submission payload
 type CartSubmitRequest struct {
 	PartnerOrderID string                 `json:"partner_order_id" binding:"required"`
@@ -9,27 +11,64 @@ type CartSubmitRequest struct {
 	Totals         CartTotals             `json:"totals" binding:"required"`
 	PaymentStatus  string                 `json:"payment_status"`
 	PaymentMethod  *string                `json:"payment_method,omitempty"`
+	// Channel is the partner's sales channel (e.g. web, app), passed on to the Shopify draft order
+	Channel        *string                `json:"channel,omitempty" binding:"omitempty,max=50"`
+	Locale         *string                `json:"locale,omitempty"` // en or ar; defaults to the partner's locale
 }
 
 type CartItem struct {
 	SKU        string  `json:"sku" binding:"required"`
+	// Barcode (EAN/UPC) matches the item when the SKU is unknown; without it the SKU is tried as a barcode
+	Barcode    *string `json:"barcode,omitempty"`
 	Title      string  `json:"title" binding:"required"`
-	Price      float64 `json:"price" binding:"required,min=0"`
+	Price      float64 `json:"price" binding:"required,min=0"` // final unit price, after discount
+	// ListPrice and Discount optionally break Price down per unit: price = list_price - discount
+	ListPrice  *float64 `json:"list_price,omitempty" binding:"omitempty,min=0"`
+	Discount   *float64 `json:"discount,omitempty" binding:"omitempty,min=0"`
 	Quantity   int     `json:"quantity" binding:"required,min=1"`
 	ProductURL *string `json:"product_url,omitempty"`
+	// WeightGrams and Dimensions are per unit; supplier items without a weight use the Shopify variant's
+	WeightGrams *int            `json:"weight_grams,omitempty" binding:"omitempty,min=0"`
+	Dimensions  *ItemDimensions `json:"dimensions,omitempty"`
+}
+
+// ItemDimensions are the outer dimensions of one unit in centimetres
+type ItemDimensions struct {
+	LengthCm float64 `json:"length_cm" binding:"required,gt=0"`
+	WidthCm  float64 `json:"width_cm" binding:"required,gt=0"`
+	HeightCm float64 `json:"height_cm" binding:"required,gt=0"`
+}
+
+// PriceBreakdown returns the per-unit list price and discount of the item. A missing value is
+// derived from Price; ok is false when the given values do not add up to Price.
+func (i CartItem) PriceBreakdown() (listPrice, discount float64, ok bool) {
+	switch {
+	case i.ListPrice != nil && i.Discount != nil:
+		listPrice, discount = *i.ListPrice, *i.Discount
+	case i.ListPrice != nil:
+		listPrice, discount = *i.ListPrice, roundAmount(*i.ListPrice-i.Price)
+	case i.Discount != nil:
+		listPrice, discount = roundAmount(i.Price+*i.Discount), *i.Discount
+	default:
+		return i.Price, 0, true
+	}
+	return listPrice, discount, discount >= 0 && math.Abs(listPrice-discount-i.Price) <= totalsTolerance
 }
 
 type CustomerInfo struct {
 	Name  string  `json:"name" binding:"required"`
 	Phone *string `json:"phone,omitempty"`
+	Email *string `json:"email,omitempty" binding:"omitempty,email,max=255"`
 }
 
 type ShippingAddress struct {
 	Street     string  `json:"street" binding:"required"`
+	Address2   *string `json:"address2,omitempty"`
 	City       string  `json:"city" binding:"required"`
 	State      *string `json:"state,omitempty"`
 	PostalCode string  `json:"postal_code" binding:"required"`
 	Country    string  `json:"country" binding:"required"`
+	Notes      *string `json:"notes,omitempty" binding:"omitempty,max=500"`
 }
 
 type CartTotals struct {
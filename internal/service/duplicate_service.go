@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// Duplicate match types
+const (
+	DuplicateMatchPhone   = "phone"
+	DuplicateMatchAddress = "address"
+)
+
+// EventTypeDuplicateSuspected is recorded on orders that belong to a duplicate cluster
+const EventTypeDuplicateSuspected = "duplicate_suspected"
+
+// phoneMatchDigits is how many trailing digits are compared, so that
+// "+962 79 123 4567" and "0791234567" are treated as the same number
+const phoneMatchDigits = 9
+
+// duplicateCheckStatuses are the statuses where catching a duplicate still prevents double-shipping
+var duplicateCheckStatuses = []domain.OrderStatus{
+	domain.OrderStatusPendingConfirmation,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusOnHold,
+}
+
+// DuplicateCluster is a group of open orders that share a normalized phone or address
+// across different partner order IDs
+type DuplicateCluster struct {
+	MatchedOn string
+	Key       string
+	Orders    []*domain.SupplierOrder
+}
+
+type duplicateService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewDuplicateService creates a new duplicate detection service
+func NewDuplicateService(repos *repository.Repositories, logger *zap.Logger) *duplicateService {
+	return &duplicateService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// FindDuplicates clusters open orders created within the window by normalized phone and address
+func (s *duplicateService) FindDuplicates(ctx context.Context, window time.Duration) ([]DuplicateCluster, error) {
+	orders, err := s.repos.SupplierOrder.ListCreatedSince(ctx, time.Now().Add(-window), duplicateCheckStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	byPhone := make(map[string][]*domain.SupplierOrder)
+	byAddress := make(map[string][]*domain.SupplierOrder)
+	for _, order := range orders {
+		phone := order.CustomerPhone
+		if order.CustomerPhoneE164 != nil {
+			phone = *order.CustomerPhoneE164
+		}
+		if key := normalizePhone(phone); key != "" {
+			byPhone[key] = append(byPhone[key], order)
+		}
+		if key := normalizeAddress(order.ShippingAddress); key != "" {
+			byAddress[key] = append(byAddress[key], order)
+		}
+	}
+
+	var clusters []DuplicateCluster
+	clusters = appendClusters(clusters, DuplicateMatchPhone, byPhone)
+	clusters = appendClusters(clusters, DuplicateMatchAddress, byAddress)
+
+	return clusters, nil
+}
+
+// RunDuplicateCheck periodically looks for duplicates and records a duplicate_suspected
+// event on each newly clustered order. window is read on every tick, so it can be reloaded.
+// It returns when ctx is cancelled.
+func (s *duplicateService) RunDuplicateCheck(ctx context.Context, interval time.Duration, window func() time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.flagDuplicates(ctx, window(), time.Now()); err != nil {
+			s.logger.Error("Duplicate order check failed", zap.Error(err))
+		}
+	}
+}
+
+// flagDuplicates records a duplicate_suspected event on each clustered order that does not
+// have one yet. Orders in the window were created within it, so their earlier events are too.
+func (s *duplicateService) flagDuplicates(ctx context.Context, window time.Duration, now time.Time) error {
+	clusters, err := s.FindDuplicates(ctx, window)
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return nil
+	}
+
+	events, err := s.repos.OrderEvent.ListLatestByType(ctx, EventTypeDuplicateSuspected, now.Add(-window), now.Add(time.Second))
+	if err != nil {
+		return err
+	}
+	flagged := make(map[uuid.UUID]bool, len(events))
+	for _, event := range events {
+		flagged[event.SupplierOrderID] = true
+	}
+
+	for _, cluster := range clusters {
+		for _, order := range cluster.Orders {
+			// An order matching on both phone and address is flagged once
+			if flagged[order.ID] {
+				continue
+			}
+			flagged[order.ID] = true
+
+			s.logger.Warn("Probable duplicate order",
+				zap.String("order_id", order.ID.String()),
+				zap.String("partner_order_id", order.PartnerOrderID),
+				zap.String("matched_on", cluster.MatchedOn),
+			)
+
+			event := &domain.OrderEvent{
+				SupplierOrderID: order.ID,
+				EventType:       EventTypeDuplicateSuspected,
+				EventData: map[string]interface{}{
+					"matched_on":        cluster.MatchedOn,
+					"related_order_ids": relatedOrderIDs(cluster.Orders, order.ID),
+				},
+			}
+			if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+				s.logger.Warn("Failed to record duplicate event", zap.Error(err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendClusters keeps groups that span more than one partner order ID
+func appendClusters(clusters []DuplicateCluster, matchedOn string, groups map[string][]*domain.SupplierOrder) []DuplicateCluster {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		orders := groups[key]
+		partnerOrderIDs := make(map[string]bool)
+		for _, order := range orders {
+			partnerOrderIDs[order.PartnerID.String()+"/"+order.PartnerOrderID] = true
+		}
+		if len(partnerOrderIDs) < 2 {
+			continue
+		}
+		clusters = append(clusters, DuplicateCluster{
+			MatchedOn: matchedOn,
+			Key:       key,
+			Orders:    orders,
+		})
+	}
+
+	return clusters
+}
+
+func relatedOrderIDs(orders []*domain.SupplierOrder, self uuid.UUID) []string {
+	var ids []string
+	for _, order := range orders {
+		if order.ID != self {
+			ids = append(ids, order.ID.String())
+		}
+	}
+	return ids
+}
+
+// normalizePhone keeps the trailing digits of a phone number
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits.WriteRune(r)
+		}
+	}
+
+	d := digits.String()
+	if len(d) < 7 {
+		return ""
+	}
+	if len(d) > phoneMatchDigits {
+		d = d[len(d)-phoneMatchDigits:]
+	}
+	return d
+}
+
+// normalizeAddress lowercases and strips punctuation from the address lines, city, postal code and country
+func normalizeAddress(addr domain.Address) string {
+	norm := func(val string) string {
+		return strings.Join(strings.FieldsFunc(strings.ToLower(val), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}), " ")
+	}
+
+	street := norm(addr.Street)
+	if street == "" {
+		return ""
+	}
+	address2 := ""
+	if addr.Address2 != nil {
+		address2 = norm(*addr.Address2)
+	}
+	return strings.Join([]string{street, address2, norm(addr.City), norm(addr.PostalCode), norm(addr.Country)}, "|")
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// recentOrders returns a fixed set of open orders
+type recentOrders struct {
+	repository.SupplierOrderRepository
+	orders []*domain.SupplierOrder
+}
+
+func (f *recentOrders) ListCreatedSince(ctx context.Context, since time.Time, statuses []domain.OrderStatus) ([]*domain.SupplierOrder, error) {
+	return f.orders, nil
+}
+
+func TestFlagDuplicatesSkipsFlaggedOrders(t *testing.T) {
+	now := time.Now()
+	order := func(partnerOrderID string) *domain.SupplierOrder {
+		return &domain.SupplierOrder{
+			ID:              uuid.New(),
+			PartnerID:       uuid.New(),
+			PartnerOrderID:  partnerOrderID,
+			Status:          domain.OrderStatusPendingConfirmation,
+			CustomerPhone:   "+962 79 123 4567",
+			ShippingAddress: domain.Address{Street: "1 Rainbow St", City: "Amman", Country: "JO"},
+			CreatedAt:       now.Add(-time.Hour),
+		}
+	}
+	first, second := order("1001"), order("1002")
+	events := &reviewEvents{flags: map[string][]uuid.UUID{}}
+	s := &duplicateService{
+		repos: &repository.Repositories{
+			SupplierOrder: &recentOrders{orders: []*domain.SupplierOrder{first, second}},
+			OrderEvent:    events,
+		},
+		logger: zap.NewNop(),
+	}
+
+	// The orders match on phone and address; each is flagged once
+	if err := s.flagDuplicates(context.Background(), 24*time.Hour, now); err != nil {
+		t.Fatalf("flagDuplicates() error = %v", err)
+	}
+	if len(events.recorded) != 2 {
+		t.Fatalf("recorded %d events, want one per order", len(events.recorded))
+	}
+
+	// The next tick, or another replica, finds the events and records nothing
+	for _, event := range events.recorded {
+		events.flags[event.EventType] = append(events.flags[event.EventType], event.SupplierOrderID)
+	}
+	events.recorded = nil
+	if err := s.flagDuplicates(context.Background(), 24*time.Hour, now.Add(time.Minute)); err != nil {
+		t.Fatalf("flagDuplicates() error = %v", err)
+	}
+	if len(events.recorded) != 0 {
+		t.Errorf("recorded %d events for orders already flagged", len(events.recorded))
+	}
+}
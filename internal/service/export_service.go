@@ -0,0 +1,208 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/signedurl"
+)
+
+const (
+	// exportPageSize is how many orders are read per query while generating an export
+	exportPageSize = 500
+	// exportTimeout bounds a background export
+	exportTimeout = 10 * time.Minute
+)
+
+// orderExportColumns is the header row of an orders CSV export
+var orderExportColumns = []string{
+	"id", "reference", "partner_id", "partner_order_id", "status",
+	"customer_name", "customer_phone", "customer_email", "city", "country",
+	"cart_total", "cart_tax", "cart_shipping", "payment_status",
+	"shopify_order_name", "tracking_carrier", "tracking_number",
+	"created_at", "confirmed_at", "shipped_at", "delivered_at",
+}
+
+type exportService struct {
+	cfg    *config.Config
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewExportService creates a service that generates export files and signs their download links
+func NewExportService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *exportService {
+	return &exportService{
+		cfg:    cfg,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Start creates an export job for the orders matching filter. Up to EXPORT_INLINE_ROWS
+// orders the file is generated before Start returns and the job is completed (or failed);
+// larger exports are generated in the background by this instance and the job is returned
+// running. Expired files are deleted on the way.
+func (s *exportService) Start(ctx context.Context, requestedBy uuid.UUID, filter domain.OrderFilter) (*domain.ExportJob, error) {
+	now := time.Now()
+	if deleted, err := s.repos.ExportJob.DeleteExpired(ctx, now); err != nil {
+		s.logger.Warn("Failed to delete expired exports", zap.Error(err))
+	} else if deleted > 0 {
+		s.logger.Info("Deleted expired exports", zap.Int64("deleted", deleted))
+	}
+
+	total, err := s.repos.SupplierOrder.CountForExport(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.ExportJob{
+		Type:        domain.ExportTypeOrdersCSV,
+		Params:      filter,
+		RequestedBy: requestedBy,
+		ExpiresAt:   now.Add(s.cfg.Exports.FileTTL),
+	}
+	if err := s.repos.ExportJob.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if total > s.cfg.Exports.InlineRows {
+		s.logger.Info("Generating export in the background",
+			zap.String("export_id", job.ID.String()),
+			zap.Int("orders", total),
+		)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			defer cancel()
+
+			s.generate(ctx, job.ID, filter)
+		}()
+		return job, nil
+	}
+
+	s.generate(ctx, job.ID, filter)
+	return s.repos.ExportJob.GetByID(ctx, job.ID)
+}
+
+// generate writes the CSV file of a job and completes it, or marks it failed
+func (s *exportService) generate(ctx context.Context, jobID uuid.UUID, filter domain.OrderFilter) {
+	content, rows, err := s.ordersCSV(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to generate export", zap.String("export_id", jobID.String()), zap.Error(err))
+		if err := s.repos.ExportJob.Fail(ctx, jobID, err.Error(), time.Now()); err != nil {
+			s.logger.Error("Failed to mark export failed", zap.String("export_id", jobID.String()), zap.Error(err))
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("orders-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	if err := s.repos.ExportJob.Complete(ctx, jobID, content, "text/csv; charset=utf-8", filename, rows, time.Now()); err != nil {
+		s.logger.Error("Failed to store export", zap.String("export_id", jobID.String()), zap.Error(err))
+	}
+}
+
+// ordersCSV renders the orders matching filter, newest first, and returns the row count
+func (s *exportService) ordersCSV(ctx context.Context, filter domain.OrderFilter) ([]byte, int, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(orderExportColumns); err != nil {
+		return nil, 0, err
+	}
+
+	rows := 0
+	var after *domain.OrderCursor
+	for {
+		orders, err := s.repos.SupplierOrder.ListForExportAfter(ctx, filter, after, exportPageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, order := range orders {
+			if err := w.Write(orderExportRow(order)); err != nil {
+				return nil, 0, err
+			}
+		}
+		rows += len(orders)
+		if len(orders) < exportPageSize {
+			break
+		}
+		after = domain.CursorAfter(orders[len(orders)-1])
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), rows, nil
+}
+
+// orderExportRow renders an order in orderExportColumns order
+func orderExportRow(order *domain.SupplierOrder) []string {
+	str := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	timestamp := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	amount := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	}
+
+	return []string{
+		order.ID.String(),
+		str(order.Reference),
+		order.PartnerID.String(),
+		order.PartnerOrderID,
+		string(order.Status),
+		order.CustomerName,
+		order.CustomerPhone,
+		str(order.CustomerEmail),
+		order.ShippingAddress.City,
+		order.ShippingAddress.Country,
+		amount(order.CartTotal),
+		amount(order.CartTax),
+		amount(order.CartShipping),
+		order.PaymentStatus,
+		str(order.ShopifyOrderName),
+		str(order.TrackingCarrier),
+		str(order.TrackingNumber),
+		timestamp(&order.CreatedAt),
+		timestamp(order.ConfirmedAt),
+		timestamp(order.ShippedAt),
+		timestamp(order.DeliveredAt),
+	}
+}
+
+// ExportDownloadPath is the download route of an export under an API version's base path
+func ExportDownloadPath(basePath string, jobID uuid.UUID) string {
+	return basePath + "/exports/" + jobID.String() + "/download"
+}
+
+// SignedExportDownloadURL returns a download link for a completed export that works for
+// EXPORT_LINK_TTL (never past the file's expiry), and when it stops working
+func SignedExportDownloadURL(cfg *config.Config, basePath string, job *domain.ExportJob, now time.Time) (string, time.Time) {
+	expires := now.Add(cfg.Exports.LinkTTL)
+	if job.ExpiresAt.Before(expires) {
+		expires = job.ExpiresAt
+	}
+	return signedurl.Sign([]byte(cfg.Exports.SigningKey), ExportDownloadPath(basePath, job.ID), expires), expires
+}
+
+// VerifyExportDownload checks the signature of a download request for path
+func VerifyExportDownload(cfg *config.Config, path, expires, signature string, now time.Time) error {
+	return signedurl.Verify([]byte(cfg.Exports.SigningKey), path, expires, signature, now)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/signedurl"
+)
+
+// exportOrders pages through a fixed list of orders, newest first
+type exportOrders struct {
+	repository.SupplierOrderRepository
+	orders []*domain.SupplierOrder
+}
+
+func (f *exportOrders) ListForExportAfter(ctx context.Context, filter domain.OrderFilter, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	start := 0
+	if after != nil {
+		for i, order := range f.orders {
+			if order.ID == after.ID {
+				start = i + 1
+			}
+		}
+	}
+	end := start + limit
+	if end > len(f.orders) {
+		end = len(f.orders)
+	}
+	return f.orders[start:end], nil
+}
+
+func TestOrdersCSV(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	orders := &exportOrders{}
+	for i := 0; i < exportPageSize+2; i++ {
+		orders.orders = append(orders.orders, &domain.SupplierOrder{
+			ID:             uuid.New(),
+			PartnerOrderID: "P-1",
+			Status:         domain.OrderStatusConfirmed,
+			CustomerName:   "Doe, Jane",
+			CartTotal:      12.5,
+			CreatedAt:      created,
+		})
+	}
+	s := NewExportService(&config.Config{}, &repository.Repositories{SupplierOrder: orders}, zap.NewNop())
+
+	content, rows, err := s.ordersCSV(context.Background(), domain.OrderFilter{})
+	if err != nil {
+		t.Fatalf("ordersCSV() error = %v", err)
+	}
+	if rows != exportPageSize+2 {
+		t.Errorf("rows = %d, want %d (every page)", rows, exportPageSize+2)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != rows+1 || strings.Join(records[0], ",") != strings.Join(orderExportColumns, ",") {
+		t.Fatalf("got %d records starting %v, want a header and %d rows", len(records), records[0], rows)
+	}
+	if got := records[1]; got[5] != "Doe, Jane" || got[10] != "12.50" || got[17] != "2024-03-01T12:00:00Z" || got[18] != "" {
+		t.Errorf("row = %v", got)
+	}
+}
+
+func TestSignedExportDownloadURL(t *testing.T) {
+	cfg := &config.Config{Exports: config.ExportsConfig{SigningKey: "key", LinkTTL: 15 * time.Minute}}
+	now := time.Now()
+	job := &domain.ExportJob{ID: uuid.New(), ExpiresAt: now.Add(5 * time.Minute)}
+
+	link, expires := SignedExportDownloadURL(cfg, "/v1", job, now)
+	// The link never outlives the file
+	if !expires.Equal(job.ExpiresAt) {
+		t.Errorf("link expires %v, want the file's expiry %v", expires, job.ExpiresAt)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("link does not parse: %v", err)
+	}
+	if u.Path != "/v1/exports/"+job.ID.String()+"/download" {
+		t.Errorf("path = %q", u.Path)
+	}
+	query := u.Query()
+	if err := VerifyExportDownload(cfg, u.Path, query.Get(signedurl.ExpiresParam), query.Get(signedurl.SignatureParam), now); err != nil {
+		t.Errorf("VerifyExportDownload() = %v, want the link accepted", err)
+	}
+	if err := VerifyExportDownload(cfg, "/v2/exports/"+uuid.NewString()+"/download", query.Get(signedurl.ExpiresParam), query.Get(signedurl.SignatureParam), now); err != signedurl.ErrInvalidSignature {
+		t.Errorf("VerifyExportDownload() for another export = %v, want %v", err, signedurl.ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/archive"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// fulfillmentExportBatch is the most orders put in one 3PL order file
+const fulfillmentExportBatch = 500
+
+// Results of the lines in a shipment file, as written to its result file
+const (
+	ShipmentResultShipped        = "shipped"
+	ShipmentResultAlreadyShipped = "already_shipped"
+	ShipmentResultFailed         = "failed"
+)
+
+var shipmentResultColumns = []string{"reference", "order_id", "tracking_number", "status", "error"}
+
+type fulfillmentService struct {
+	cfg    config.FulfillmentConfig
+	store  archive.Store
+	repos  *repository.Repositories
+	orders OrderService
+	logger *zap.Logger
+}
+
+// NewFulfillmentService creates a service that sends confirmed orders to a 3PL as flat files
+// and ships the orders in the shipment confirmation files it sends back
+func NewFulfillmentService(cfg config.FulfillmentConfig, repos *repository.Repositories, orders OrderService, logger *zap.Logger) *fulfillmentService {
+	return &fulfillmentService{
+		cfg: cfg,
+		store: archive.NewS3Store(archive.S3Config{
+			Endpoint:        cfg.Endpoint,
+			Region:          cfg.Region,
+			Bucket:          cfg.Bucket,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+		}),
+		repos:  repos,
+		orders: orders,
+		logger: logger,
+	}
+}
+
+// keyPrefix is the key prefix of the 3PL files, ending in a slash unless it is empty
+func (s *fulfillmentService) keyPrefix() string {
+	if s.cfg.Prefix == "" {
+		return ""
+	}
+	return s.cfg.Prefix + "/"
+}
+
+// Export writes the confirmed orders that were not exported yet, oldest first, to a new
+// order file at <prefix>/orders/. Orders are claimed before the file is uploaded, so
+// concurrent exports never send one twice; a failed upload releases them for the next run.
+// Returns how many orders were exported.
+func (s *fulfillmentService) Export(ctx context.Context, now time.Time) (int, error) {
+	orders, err := s.repos.SupplierOrder.ListNotExportedForFulfillment(ctx, domain.OrderStatusConfirmed, fulfillmentExportBatch)
+	if err != nil || len(orders) == 0 {
+		return 0, err
+	}
+
+	extension := "csv"
+	if s.cfg.Format == config.FulfillmentFormatFixed {
+		extension = "txt"
+	}
+	key := fmt.Sprintf("%sorders/orders-%s-%s.%s", s.keyPrefix(), now.UTC().Format("20060102T150405Z"), uuid.New().String()[:8], extension)
+
+	ids := make([]uuid.UUID, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	claimed, err := s.repos.FulfillmentExport.Claim(ctx, ids, key)
+	if err != nil || len(claimed) == 0 {
+		return 0, err
+	}
+
+	content, err := s.orderFile(ctx, orders, claimed)
+	if err == nil {
+		err = s.store.Put(ctx, key, content)
+	}
+	if err != nil {
+		if releaseErr := s.repos.FulfillmentExport.DeleteByFileKey(ctx, key); releaseErr != nil {
+			s.logger.Error("Failed to release orders of a failed 3PL order file", zap.String("key", key), zap.Error(releaseErr))
+		}
+		return 0, err
+	}
+
+	s.logger.Info("Exported orders to the 3PL", zap.String("key", key), zap.Int("orders", len(claimed)))
+	return len(claimed), nil
+}
+
+// orderFile renders the supplier items of the claimed orders, one line per item
+func (s *fulfillmentService) orderFile(ctx context.Context, orders []*domain.SupplierOrder, claimed []uuid.UUID) ([]byte, error) {
+	isClaimed := make(map[uuid.UUID]bool, len(claimed))
+	for _, id := range claimed {
+		isClaimed[id] = true
+	}
+
+	var lines [][]string
+	partnerNames := make(map[uuid.UUID]string)
+	for _, order := range orders {
+		if !isClaimed[order.ID] {
+			continue
+		}
+		partnerName, ok := partnerNames[order.PartnerID]
+		if !ok {
+			partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+			if err != nil {
+				return nil, err
+			}
+			partnerName = partner.Name
+			partnerNames[order.PartnerID] = partnerName
+		}
+
+		items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if !item.IsSupplierItem {
+				continue
+			}
+			line := make([]string, len(s.cfg.Fields))
+			for i, field := range s.cfg.Fields {
+				line[i] = fulfillmentValue(field.Name, order, item, partnerName)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if s.cfg.Format == config.FulfillmentFormatFixed {
+		return fixedWidthFile(s.cfg.Fields, lines), nil
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := make([]string, len(s.cfg.Fields))
+	for i, field := range s.cfg.Fields {
+		header[i] = field.Name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(lines); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fulfillmentValue is the value of one of config.FulfillmentFields for an item of an order
+func fulfillmentValue(field string, order *domain.SupplierOrder, item *domain.SupplierOrderItem, partnerName string) string {
+	switch field {
+	case "order_id":
+		return order.ID.String()
+	case "reference":
+		return stringValue(order.Reference)
+	case "partner_order_id":
+		return order.PartnerOrderID
+	case "partner_name":
+		return partnerName
+	case "confirmed_at":
+		if order.ConfirmedAt == nil {
+			return ""
+		}
+		return order.ConfirmedAt.UTC().Format(time.RFC3339)
+	case "customer_name":
+		return order.CustomerName
+	case "customer_phone":
+		return order.CustomerPhone
+	case "customer_email":
+		return stringValue(order.CustomerEmail)
+	case "street":
+		return order.ShippingAddress.Street
+	case "address2":
+		return stringValue(order.ShippingAddress.Address2)
+	case "city":
+		return order.ShippingAddress.City
+	case "state":
+		return stringValue(order.ShippingAddress.State)
+	case "postal_code":
+		return order.ShippingAddress.PostalCode
+	case "country":
+		return order.ShippingAddress.Country
+	case "notes":
+		return stringValue(order.ShippingAddress.Notes)
+	case "sku":
+		return item.SKU
+	case "title":
+		return item.Title
+	case "quantity":
+		return strconv.Itoa(item.Quantity)
+	case "price":
+		return strconv.FormatFloat(item.Price, 'f', 2, 64)
+	case "weight_grams":
+		if item.WeightGrams == nil {
+			return ""
+		}
+		return strconv.Itoa(*item.WeightGrams)
+	}
+	return ""
+}
+
+// fixedWidthFile renders lines with each value left-aligned in its field's width, padded
+// with spaces or cut off. Line breaks and tabs in values become spaces.
+func fixedWidthFile(fields []config.FulfillmentField, lines [][]string) []byte {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		for i, field := range fields {
+			value := strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(line[i])
+			if n := utf8.RuneCountInString(value); n > field.Width {
+				value = string([]rune(value)[:field.Width])
+			} else {
+				value += strings.Repeat(" ", field.Width-n)
+			}
+			buf.WriteString(value)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// ImportShipments ships the orders in every shipment file under <prefix>/shipments/inbox/
+// that has no result file yet, then writes its result file to <prefix>/shipments/results/.
+// As with batch order files, the result file acknowledges the file. Returns how many files
+// were processed.
+func (s *fulfillmentService) ImportShipments(ctx context.Context) (int, error) {
+	prefix := s.keyPrefix() + "shipments/"
+	keys, err := s.store.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	done := make(map[string]bool)
+	var inbox []string
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".csv") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(key, prefix+"inbox/"):
+			inbox = append(inbox, key)
+		case strings.HasPrefix(key, prefix+"results/"):
+			done[key] = true
+		}
+	}
+
+	processed := 0
+	for _, key := range inbox {
+		if done[BatchResultKey(key)] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		if err := s.importShipmentFile(ctx, key); err != nil {
+			s.logger.Error("Failed to import 3PL shipment file", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// shipmentLine is a line of a shipment file
+type shipmentLine struct {
+	Reference      string
+	OrderID        string
+	Carrier        string
+	TrackingNumber string
+	TrackingURL    string
+}
+
+// importShipmentFile ships the orders of one file and writes its result file. The returned
+// error means the result file was not written, so the file is tried again next time.
+func (s *fulfillmentService) importShipmentFile(ctx context.Context, key string) error {
+	data, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var results [][]string
+	lines, parseErr := parseShipmentLines(data)
+	shipped := 0
+	for _, line := range lines {
+		status, reason := s.ship(ctx, line)
+		if status == ShipmentResultShipped {
+			shipped++
+		}
+		results = append(results, []string{line.Reference, line.OrderID, line.TrackingNumber, status, reason})
+	}
+	// Lines before a malformed one are still shipped
+	if parseErr != nil {
+		results = append(results, []string{"", "", "", ShipmentResultFailed, parseErr.Error()})
+	}
+	s.logger.Info("Imported 3PL shipment file", zap.String("key", key), zap.Int("lines", len(lines)), zap.Int("shipped", shipped))
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(shipmentResultColumns); err != nil {
+		return err
+	}
+	if err := w.WriteAll(results); err != nil {
+		return err
+	}
+	return s.store.Put(ctx, BatchResultKey(key), buf.Bytes())
+}
+
+// ship ships the order of a shipment line and returns its result and the reason it failed
+func (s *fulfillmentService) ship(ctx context.Context, line shipmentLine) (string, string) {
+	carrier := line.Carrier
+	if carrier == "" {
+		carrier = s.cfg.Carrier
+	}
+	if carrier == "" {
+		return ShipmentResultFailed, "carrier is required"
+	}
+	if line.TrackingNumber == "" {
+		return ShipmentResultFailed, "tracking_number is required"
+	}
+
+	var order *domain.SupplierOrder
+	var err error
+	if line.OrderID != "" {
+		id, parseErr := uuid.Parse(line.OrderID)
+		if parseErr != nil {
+			return ShipmentResultFailed, "order_id is not a valid ID"
+		}
+		order, err = s.repos.SupplierOrder.GetByID(ctx, id)
+	} else {
+		order, err = s.repos.SupplierOrder.GetByReference(ctx, line.Reference)
+	}
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return ShipmentResultFailed, "order not found"
+		}
+		s.logger.Error("Failed to look up order of a 3PL shipment", zap.String("reference", line.Reference), zap.String("order_id", line.OrderID), zap.Error(err))
+		return ShipmentResultFailed, "internal error"
+	}
+
+	// A file sent again ships nothing twice
+	if (order.Status == domain.OrderStatusShipped || order.Status == domain.OrderStatusDelivered) &&
+		order.TrackingNumber != nil && *order.TrackingNumber == line.TrackingNumber {
+		return ShipmentResultAlreadyShipped, ""
+	}
+
+	var trackingURL *string
+	if line.TrackingURL != "" {
+		trackingURL = &line.TrackingURL
+	}
+	if err := s.orders.ShipOrder(ctx, order.ID, carrier, line.TrackingNumber, trackingURL); err != nil {
+		switch err.(type) {
+		case *errors.ErrInvalidStateTransition, *errors.ErrConflict, *errors.ErrNotFound:
+			return ShipmentResultFailed, err.Error()
+		}
+		s.logger.Error("Failed to ship order from a 3PL shipment", zap.String("order_id", order.ID.String()), zap.Error(err))
+		return ShipmentResultFailed, "internal error"
+	}
+	return ShipmentResultShipped, ""
+}
+
+// parseShipmentLines reads a shipment file: a header row, then one line per shipped order
+// with reference or order_id, tracking_number and optionally carrier and tracking_url
+func parseShipmentLines(data []byte) ([]shipmentLine, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	_, hasReference := index["reference"]
+	_, hasOrderID := index["order_id"]
+	if !hasReference && !hasOrderID {
+		return nil, fmt.Errorf("missing columns: reference or order_id")
+	}
+	if _, ok := index["tracking_number"]; !ok {
+		return nil, fmt.Errorf("missing columns: tracking_number")
+	}
+
+	var lines []shipmentLine
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, fmt.Errorf("invalid CSV: %v", err)
+		}
+		value := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		line := shipmentLine{
+			Reference:      value("reference"),
+			OrderID:        value("order_id"),
+			Carrier:        value("carrier"),
+			TrackingNumber: value("tracking_number"),
+			TrackingURL:    value("tracking_url"),
+		}
+		if line.Reference == "" && line.OrderID == "" && line.TrackingNumber == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+}
+
+// RunFulfillment exports confirmed orders and imports shipment files at the given interval
+// until ctx is cancelled
+func (s *fulfillmentService) RunFulfillment(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Export(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to export orders to the 3PL", zap.Error(err))
+		}
+		if _, err := s.ImportShipments(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to list 3PL shipment files", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// fulfillmentOrders lists and looks up a fixed set of orders
+type fulfillmentOrders struct {
+	repository.SupplierOrderRepository
+	orders []*domain.SupplierOrder
+}
+
+func (f *fulfillmentOrders) ListNotExportedForFulfillment(ctx context.Context, status domain.OrderStatus, limit int) ([]*domain.SupplierOrder, error) {
+	return f.orders, nil
+}
+
+func (f *fulfillmentOrders) GetByReference(ctx context.Context, reference string) (*domain.SupplierOrder, error) {
+	for _, order := range f.orders {
+		if order.Reference != nil && *order.Reference == reference {
+			return order, nil
+		}
+	}
+	return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: reference}
+}
+
+type fulfillmentItems struct {
+	repository.SupplierOrderItemRepository
+	items map[uuid.UUID][]*domain.SupplierOrderItem
+}
+
+func (f *fulfillmentItems) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.SupplierOrderItem, error) {
+	return f.items[orderID], nil
+}
+
+// fulfillmentClaims remembers claimed orders; already is claimed by another export
+type fulfillmentClaims struct {
+	already  map[uuid.UUID]bool
+	released []string
+}
+
+func (f *fulfillmentClaims) Claim(ctx context.Context, orderIDs []uuid.UUID, fileKey string) ([]uuid.UUID, error) {
+	var claimed []uuid.UUID
+	for _, id := range orderIDs {
+		if !f.already[id] {
+			f.already[id] = true
+			claimed = append(claimed, id)
+		}
+	}
+	return claimed, nil
+}
+
+func (f *fulfillmentClaims) DeleteByFileKey(ctx context.Context, fileKey string) error {
+	f.released = append(f.released, fileKey)
+	return nil
+}
+
+// shippingOrders records shipped orders
+type shippingOrders struct {
+	OrderService
+	shipped map[uuid.UUID]string
+}
+
+func (f *shippingOrders) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error {
+	f.shipped[orderID] = carrier + " " + trackingNumber
+	return nil
+}
+
+func TestFulfillmentExportFixedWidth(t *testing.T) {
+	reference := "B2B-2024-000001"
+	order := &domain.SupplierOrder{
+		ID:              uuid.New(),
+		PartnerID:       uuid.New(),
+		Reference:       &reference,
+		Status:          domain.OrderStatusConfirmed,
+		CustomerName:    "Jane Doe",
+		ShippingAddress: domain.Address{City: "Amman\nWest"},
+	}
+	taken := &domain.SupplierOrder{ID: uuid.New(), PartnerID: order.PartnerID, Status: domain.OrderStatusConfirmed}
+	claims := &fulfillmentClaims{already: map[uuid.UUID]bool{taken.ID: true}}
+	store := &memStore{files: map[string][]byte{}}
+	s := &fulfillmentService{
+		cfg: config.FulfillmentConfig{
+			Prefix: "3pl",
+			Format: config.FulfillmentFormatFixed,
+			Fields: []config.FulfillmentField{{Name: "reference", Width: 16}, {Name: "city", Width: 8}, {Name: "sku", Width: 4}, {Name: "quantity", Width: 3}},
+		},
+		store: store,
+		repos: &repository.Repositories{
+			Partner:       &batchPartners{partner: &domain.Partner{ID: order.PartnerID, Name: "Zain Shop"}},
+			SupplierOrder: &fulfillmentOrders{orders: []*domain.SupplierOrder{order, taken}},
+			SupplierOrderItem: &fulfillmentItems{items: map[uuid.UUID][]*domain.SupplierOrderItem{
+				order.ID: {
+					{SKU: "MUG-BLUE", Quantity: 2, IsSupplierItem: true},
+					{SKU: "THEIRS", Quantity: 1},
+				},
+			}},
+			FulfillmentExport: claims,
+		},
+		logger: zap.NewNop(),
+	}
+
+	exported, err := s.Export(context.Background(), time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if exported != 1 {
+		t.Errorf("exported %d orders, want 1 (the other was claimed before)", exported)
+	}
+	if len(store.files) != 1 {
+		t.Fatalf("wrote %d files, want 1", len(store.files))
+	}
+	for key, content := range store.files {
+		if !strings.HasPrefix(key, "3pl/orders/orders-20240301T120000Z-") || !strings.HasSuffix(key, ".txt") {
+			t.Errorf("file key = %s", key)
+		}
+		if want := "B2B-2024-000001 Amman WeMUG-2  \n"; string(content) != want {
+			t.Errorf("file = %q, want %q", content, want)
+		}
+	}
+	if len(claims.released) != 0 {
+		t.Errorf("released %v after a successful upload", claims.released)
+	}
+}
+
+func TestFulfillmentImportShipments(t *testing.T) {
+	reference, shippedReference := "B2B-2024-000001", "B2B-2024-000002"
+	tracking := "TRK-2"
+	confirmed := &domain.SupplierOrder{ID: uuid.New(), Reference: &reference, Status: domain.OrderStatusConfirmed}
+	shipped := &domain.SupplierOrder{ID: uuid.New(), Reference: &shippedReference, Status: domain.OrderStatusShipped, TrackingNumber: &tracking}
+	store := &memStore{files: map[string][]byte{
+		"3pl/shipments/inbox/day-1.csv": []byte("reference,tracking_number,carrier\n" +
+			reference + ",TRK-1,\n" +
+			shippedReference + ",TRK-2,aramex\n" +
+			"B2B-2024-999999,TRK-3,aramex\n"),
+	}}
+	orders := &shippingOrders{shipped: make(map[uuid.UUID]string)}
+	s := &fulfillmentService{
+		cfg:   config.FulfillmentConfig{Prefix: "3pl", Carrier: "dhl"},
+		store: store,
+		repos: &repository.Repositories{
+			SupplierOrder: &fulfillmentOrders{orders: []*domain.SupplierOrder{confirmed, shipped}},
+		},
+		orders: orders,
+		logger: zap.NewNop(),
+	}
+
+	processed, err := s.ImportShipments(context.Background())
+	if err != nil {
+		t.Fatalf("ImportShipments() error = %v", err)
+	}
+	if processed != 1 {
+		t.Errorf("processed %d files, want 1", processed)
+	}
+	if got := orders.shipped[confirmed.ID]; got != "dhl TRK-1" {
+		t.Errorf("shipped confirmed order with %q, want the default carrier and TRK-1", got)
+	}
+	if len(orders.shipped) != 1 {
+		t.Errorf("shipped %d orders, want 1", len(orders.shipped))
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(string(store.files["3pl/shipments/results/day-1.result.csv"]))).ReadAll()
+	if err != nil {
+		t.Fatalf("result file is not CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("result file has %d rows, want a header and 3 lines", len(rows))
+	}
+	for i, want := range []string{ShipmentResultShipped, ShipmentResultAlreadyShipped, ShipmentResultFailed} {
+		if rows[i+1][3] != want {
+			t.Errorf("line %s status = %s, want %s", rows[i+1][0], rows[i+1][3], want)
+		}
+	}
+
+	if processed, _ := s.ImportShipments(context.Background()); processed != 0 {
+		t.Errorf("second ImportShipments() processed %d files, want 0", processed)
+	}
+}
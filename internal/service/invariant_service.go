@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// invariantGracePeriod leaves out orders created this recently: their items, Shopify draft
+// or tracking may still be on their way in
+const invariantGracePeriod = 5 * time.Minute
+
+// InvariantResult is how many orders break an invariant, with a sample of them
+type InvariantResult struct {
+	Invariant  domain.OrderInvariant
+	Violations int
+	Sample     []InvariantViolation
+	// Failed is set when the invariant could not be checked; Violations is then from the last
+	// run that succeeded
+	Failed bool
+}
+
+// InvariantViolation is an order that breaks an invariant
+type InvariantViolation struct {
+	OrderID   uuid.UUID
+	PartnerID uuid.UUID
+	Status    domain.OrderStatus
+	CreatedAt time.Time
+}
+
+// InvariantMetrics are the results of the latest invariant check and the run counters since
+// the process started
+type InvariantMetrics struct {
+	Runs      int64
+	Failures  int64
+	LastRunAt time.Time
+	Results   []InvariantResult
+}
+
+// invariantMetrics are shared by the background job and the admin endpoint
+var invariantMetrics struct {
+	sync.Mutex
+	InvariantMetrics
+}
+
+// InvariantCheckMetrics returns the latest invariant check results
+func InvariantCheckMetrics() InvariantMetrics {
+	invariantMetrics.Lock()
+	defer invariantMetrics.Unlock()
+
+	metrics := invariantMetrics.InvariantMetrics
+	metrics.Results = append([]InvariantResult(nil), metrics.Results...)
+	return metrics
+}
+
+func recordInvariantCheck(results []InvariantResult, failed bool, at time.Time) {
+	invariantMetrics.Lock()
+	defer invariantMetrics.Unlock()
+
+	// A failed invariant keeps its last known count so the gauge does not drop to zero
+	previous := make(map[domain.OrderInvariant]InvariantResult, len(invariantMetrics.Results))
+	for _, result := range invariantMetrics.Results {
+		previous[result.Invariant] = result
+	}
+	for i, result := range results {
+		if result.Failed {
+			results[i].Violations = previous[result.Invariant].Violations
+			results[i].Sample = previous[result.Invariant].Sample
+		}
+	}
+
+	invariantMetrics.Runs++
+	if failed {
+		invariantMetrics.Failures++
+	}
+	invariantMetrics.LastRunAt = at
+	invariantMetrics.Results = results
+}
+
+type invariantService struct {
+	cfg    *config.Config
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewInvariantService creates a service that scans orders for impossible data
+func NewInvariantService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *invariantService {
+	return &invariantService{
+		cfg:    cfg,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// invariants are the invariants checked: without Shopify (dry-run) no order has Shopify IDs
+func (s *invariantService) invariants() []domain.OrderInvariant {
+	var invariants []domain.OrderInvariant
+	for _, invariant := range domain.OrderInvariants {
+		if invariant == domain.OrderInvariantConfirmedInShopify && s.cfg.Shopify.DryRun {
+			continue
+		}
+		invariants = append(invariants, invariant)
+	}
+	return invariants
+}
+
+// Check counts the orders breaking each invariant and records the results for metrics and
+// the admin endpoint. An invariant that cannot be checked is logged and does not stop the others.
+func (s *invariantService) Check(ctx context.Context, now time.Time) []InvariantResult {
+	var results []InvariantResult
+	failed := false
+	for _, invariant := range s.invariants() {
+		total, orders, err := s.repos.SupplierOrder.FindInvariantViolations(ctx, invariant, now.Add(-invariantGracePeriod), s.cfg.Invariants.SampleSize)
+		if err != nil {
+			s.logger.Error("Failed to check order invariant", zap.String("invariant", string(invariant)), zap.Error(err))
+			results = append(results, InvariantResult{Invariant: invariant, Failed: true})
+			failed = true
+			continue
+		}
+
+		result := InvariantResult{Invariant: invariant, Violations: total}
+		for _, order := range orders {
+			result.Sample = append(result.Sample, InvariantViolation{
+				OrderID:   order.ID,
+				PartnerID: order.PartnerID,
+				Status:    order.Status,
+				CreatedAt: order.CreatedAt,
+			})
+		}
+		if total > 0 {
+			s.logger.Warn("Orders break an invariant",
+				zap.String("invariant", string(invariant)),
+				zap.Int("violations", total),
+			)
+		}
+		results = append(results, result)
+	}
+
+	recordInvariantCheck(results, failed, now)
+	return results
+}
+
+// RunInvariantCheck checks the invariants every interval until ctx is done
+func (s *invariantService) RunInvariantCheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Check(ctx, now)
+		}
+	}
+}
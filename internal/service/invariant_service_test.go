@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// invariantOrders returns a fixed number of violations per invariant, or fails the ones in failing
+type invariantOrders struct {
+	repository.SupplierOrderRepository
+	violations map[domain.OrderInvariant]int
+	failing    map[domain.OrderInvariant]bool
+	checked    []domain.OrderInvariant
+}
+
+func (f *invariantOrders) FindInvariantViolations(ctx context.Context, invariant domain.OrderInvariant, createdBefore time.Time, limit int) (int, []*domain.SupplierOrder, error) {
+	f.checked = append(f.checked, invariant)
+	if f.failing[invariant] {
+		return 0, nil, fmt.Errorf("connection refused")
+	}
+	var orders []*domain.SupplierOrder
+	for i := 0; i < f.violations[invariant] && i < limit; i++ {
+		orders = append(orders, &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusShipped})
+	}
+	return f.violations[invariant], orders, nil
+}
+
+func TestInvariantCheck(t *testing.T) {
+	orders := &invariantOrders{violations: map[domain.OrderInvariant]int{
+		domain.OrderInvariantShippedWithTracking: 3,
+	}}
+	cfg := &config.Config{Invariants: config.InvariantsConfig{SampleSize: 2}}
+	cfg.Shopify.DryRun = true
+	s := NewInvariantService(cfg, &repository.Repositories{SupplierOrder: orders}, zap.NewNop())
+
+	results := s.Check(context.Background(), time.Now())
+
+	// Without Shopify no order has Shopify IDs to check
+	for _, invariant := range orders.checked {
+		if invariant == domain.OrderInvariantConfirmedInShopify {
+			t.Errorf("checked %s in dry-run mode", invariant)
+		}
+	}
+	if len(results) != 2 || results[0].Violations != 3 || len(results[0].Sample) != 2 || results[1].Violations != 0 {
+		t.Fatalf("results = %+v, want 3 shipped orders without tracking (2 sampled) and no order without items", results)
+	}
+
+	// A failed check keeps the last known count
+	orders.failing = map[domain.OrderInvariant]bool{domain.OrderInvariantShippedWithTracking: true}
+	s.Check(context.Background(), time.Now())
+
+	metrics := InvariantCheckMetrics()
+	if !metrics.Results[0].Failed || metrics.Results[0].Violations != 3 {
+		t.Errorf("after a failed check: %+v, want failed with the last count of 3", metrics.Results[0])
+	}
+	if metrics.Failures == 0 {
+		t.Errorf("failures = 0, want the failed check counted")
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// Item statuses shown to partners: where each item of an order stands
+const (
+	ItemStatusPending              = "pending"
+	ItemStatusAccepted             = "accepted"
+	ItemStatusOnHold               = "on_hold"
+	ItemStatusSubstitutionProposed = "substitution_proposed"
+	ItemStatusSubstituted          = "substituted"
+	ItemStatusShipped              = "shipped"
+	ItemStatusDelivered            = "delivered"
+	ItemStatusRejected             = "rejected"
+	ItemStatusCancelled            = "cancelled"
+	// ItemStatusNotSupplied is a cart item that did not match a supplier SKU; the partner
+	// fulfills it
+	ItemStatusNotSupplied = "not_supplied"
+)
+
+// ItemProgress is the partner-facing progress of one order item
+type ItemProgress struct {
+	Status          string
+	ShippedQuantity int
+	// SubstitutedSKU is the SKU the item replaced, when a substitution was accepted
+	SubstitutedSKU *string
+}
+
+// ItemProgressFor derives the progress of an item from its order's status and the order's
+// substitutions. Orders are fulfilled whole, so a shipped order has shipped every unit.
+func ItemProgressFor(order *domain.SupplierOrder, item *domain.SupplierOrderItem, substitutions []*domain.ItemSubstitution) ItemProgress {
+	if !item.IsSupplierItem {
+		return ItemProgress{Status: ItemStatusNotSupplied}
+	}
+
+	var progress ItemProgress
+	var proposed bool
+	for _, substitution := range substitutions {
+		if substitution.SupplierOrderItemID != item.ID {
+			continue
+		}
+		switch substitution.Status {
+		case domain.SubstitutionStatusAccepted:
+			sku := substitution.OriginalSKU
+			progress.SubstitutedSKU = &sku
+		case domain.SubstitutionStatusProposed:
+			proposed = true
+		}
+	}
+
+	switch order.Status {
+	case domain.OrderStatusShipped:
+		progress.Status = ItemStatusShipped
+		progress.ShippedQuantity = item.Quantity
+	case domain.OrderStatusDelivered:
+		progress.Status = ItemStatusDelivered
+		progress.ShippedQuantity = item.Quantity
+	case domain.OrderStatusRejected:
+		progress.Status = ItemStatusRejected
+	case domain.OrderStatusCancelled:
+		progress.Status = ItemStatusCancelled
+	case domain.OrderStatusOnHold:
+		progress.Status = ItemStatusOnHold
+	default:
+		// Open orders: a pending proposal waits on the partner, then an accepted substitute
+		switch {
+		case proposed:
+			progress.Status = ItemStatusSubstitutionProposed
+		case progress.SubstitutedSKU != nil:
+			progress.Status = ItemStatusSubstituted
+		case order.Status == domain.OrderStatusConfirmed:
+			progress.Status = ItemStatusAccepted
+		default:
+			progress.Status = ItemStatusPending
+		}
+	}
+	return progress
+}
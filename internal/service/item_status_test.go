@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+func TestItemProgressFor(t *testing.T) {
+	item := &domain.SupplierOrderItem{ID: uuid.New(), SKU: "SKU-B", Quantity: 3, IsSupplierItem: true}
+	substitution := func(status domain.SubstitutionStatus) []*domain.ItemSubstitution {
+		return []*domain.ItemSubstitution{
+			{SupplierOrderItemID: uuid.New(), OriginalSKU: "OTHER", Status: domain.SubstitutionStatusProposed},
+			{SupplierOrderItemID: item.ID, OriginalSKU: "SKU-A", Status: status},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		status        domain.OrderStatus
+		substitutions []*domain.ItemSubstitution
+		want          string
+		shipped       int
+		substituted   bool
+	}{
+		{"pending", domain.OrderStatusPendingConfirmation, nil, ItemStatusPending, 0, false},
+		{"confirmed", domain.OrderStatusConfirmed, nil, ItemStatusAccepted, 0, false},
+		{"on hold", domain.OrderStatusOnHold, nil, ItemStatusOnHold, 0, false},
+		{"proposal open", domain.OrderStatusConfirmed, substitution(domain.SubstitutionStatusProposed), ItemStatusSubstitutionProposed, 0, false},
+		{"proposal declined", domain.OrderStatusConfirmed, substitution(domain.SubstitutionStatusDeclined), ItemStatusAccepted, 0, false},
+		{"substituted", domain.OrderStatusConfirmed, substitution(domain.SubstitutionStatusAccepted), ItemStatusSubstituted, 0, true},
+		{"substituted and shipped", domain.OrderStatusShipped, substitution(domain.SubstitutionStatusAccepted), ItemStatusShipped, 3, true},
+		{"delivered", domain.OrderStatusDelivered, nil, ItemStatusDelivered, 3, false},
+		{"rejected", domain.OrderStatusRejected, nil, ItemStatusRejected, 0, false},
+		{"cancelled", domain.OrderStatusCancelled, nil, ItemStatusCancelled, 0, false},
+	}
+
+	for _, tt := range tests {
+		got := ItemProgressFor(&domain.SupplierOrder{Status: tt.status}, item, tt.substitutions)
+		if got.Status != tt.want || got.ShippedQuantity != tt.shipped || (got.SubstitutedSKU != nil) != tt.substituted {
+			t.Errorf("%s: got %+v, want status %s, %d shipped, substituted %v", tt.name, got, tt.want, tt.shipped, tt.substituted)
+		}
+		if tt.substituted && got.SubstitutedSKU != nil && *got.SubstitutedSKU != "SKU-A" {
+			t.Errorf("%s: substituted SKU = %s, want SKU-A", tt.name, *got.SubstitutedSKU)
+		}
+	}
+
+	partnerItem := &domain.SupplierOrderItem{ID: uuid.New(), Quantity: 1}
+	if got := ItemProgressFor(&domain.SupplierOrder{Status: domain.OrderStatusShipped}, partnerItem, nil); got.Status != ItemStatusNotSupplied || got.ShippedQuantity != 0 {
+		t.Errorf("item without a supplier SKU: got %+v, want not_supplied", got)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/mailer"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// lowStockVariantBatch is how many variants one Shopify stock lookup asks for
+const lowStockVariantBatch = 100
+
+// LowStockItem is a variant a partner ordered recently whose stock is running low
+type LowStockItem struct {
+	SKU              string    `json:"sku"`
+	ShopifyVariantID int64     `json:"shopify_variant_id"`
+	Quantity         int       `json:"quantity"`
+	LastOrderedAt    time.Time `json:"last_ordered_at"`
+}
+
+type lowStockService struct {
+	cfg      *config.Config
+	repos    *repository.Repositories
+	shopify  ShopifyService
+	webhooks WebhookService
+	mailer   mailer.Mailer
+	logger   *zap.Logger
+}
+
+// NewLowStockService creates a service that alerts partners when variants they ordered
+// recently are running out in Shopify
+func NewLowStockService(cfg *config.Config, repos *repository.Repositories, shopify ShopifyService, webhooks WebhookService, logger *zap.Logger) *lowStockService {
+	return &lowStockService{
+		cfg:      cfg,
+		repos:    repos,
+		shopify:  shopify,
+		webhooks: webhooks,
+		mailer:   mailer.New(cfg.SMTP, logger),
+		logger:   logger,
+	}
+}
+
+// Check looks up the Shopify stock of the variants partners ordered within the window and
+// alerts each partner once about those below the threshold: an inventory.low_stock webhook
+// and, for partners with a digest subscription, an email. Variants that are back at or
+// above the threshold are cleared, so they are alerted again the next time they run low.
+// Untracked variants and variants that keep selling when out of stock are never low.
+// Returns how many partners were alerted.
+func (s *lowStockService) Check(ctx context.Context, now time.Time) (int, error) {
+	ordered, err := s.repos.SupplierOrderItem.ListOrderedVariants(ctx, now.Add(-s.cfg.LowStock.Window))
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[int64]bool)
+	var variantIDs []int64
+	for _, v := range ordered {
+		if !seen[v.ShopifyVariantID] {
+			seen[v.ShopifyVariantID] = true
+			variantIDs = append(variantIDs, v.ShopifyVariantID)
+		}
+	}
+
+	low := make(map[int64]int)
+	var restocked []int64
+	for start := 0; start < len(variantIDs); start += lowStockVariantBatch {
+		batch := variantIDs[start:min(start+lowStockVariantBatch, len(variantIDs))]
+		variants, err := s.shopify.GetVariants(ctx, batch)
+		if err == shopify.ErrDryRun {
+			// No stock to compare with
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range batch {
+			// Unknown variants were deleted in Shopify; the SKU sync deactivates them
+			variant, ok := variants[id]
+			if !ok {
+				continue
+			}
+			if variant.InventoryQuantity != nil && !variant.ContinueSelling && *variant.InventoryQuantity < s.cfg.LowStock.Threshold {
+				low[id] = *variant.InventoryQuantity
+			} else {
+				restocked = append(restocked, id)
+			}
+		}
+	}
+
+	if _, err := s.repos.LowStockAlert.DeleteByVariantIDs(ctx, restocked); err != nil {
+		return 0, err
+	}
+
+	// Recorded alerts claim the variant, so several instances never send the same one
+	itemsByPartner := make(map[uuid.UUID][]LowStockItem)
+	var partnerIDs []uuid.UUID
+	for _, v := range ordered {
+		quantity, ok := low[v.ShopifyVariantID]
+		if !ok {
+			continue
+		}
+		claimed, err := s.repos.LowStockAlert.Record(ctx, &domain.LowStockAlert{
+			PartnerID:        v.PartnerID,
+			ShopifyVariantID: v.ShopifyVariantID,
+			SKU:              v.SKU,
+			Quantity:         quantity,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if !claimed {
+			continue
+		}
+		if _, ok := itemsByPartner[v.PartnerID]; !ok {
+			partnerIDs = append(partnerIDs, v.PartnerID)
+		}
+		itemsByPartner[v.PartnerID] = append(itemsByPartner[v.PartnerID], LowStockItem{
+			SKU:              v.SKU,
+			ShopifyVariantID: v.ShopifyVariantID,
+			Quantity:         quantity,
+			LastOrderedAt:    v.LastOrderedAt,
+		})
+	}
+
+	for _, partnerID := range partnerIDs {
+		partner, err := s.repos.Partner.GetByID(ctx, partnerID)
+		if err != nil {
+			s.logger.Error("Failed to get partner for low-stock alert",
+				zap.String("partner_id", partnerID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		items := itemsByPartner[partnerID]
+		s.webhooks.NotifyPartnerEvent(partner, WebhookEventInventoryLow, map[string]interface{}{
+			"threshold": s.cfg.LowStock.Threshold,
+			"items":     items,
+		})
+		s.emailAlert(ctx, partner, items)
+
+		s.logger.Info("Low-stock alert sent",
+			zap.String("partner_id", partner.ID.String()),
+			zap.Int("items", len(items)),
+		)
+	}
+
+	return len(partnerIDs), nil
+}
+
+// emailAlert sends the alert to the partner's digest address, if they have one
+func (s *lowStockService) emailAlert(ctx context.Context, partner *domain.Partner, items []LowStockItem) {
+	subscription, err := s.repos.DigestSubscription.GetByPartnerID(ctx, partner.ID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); !ok {
+			s.logger.Error("Failed to get digest subscription for low-stock alert", zap.Error(err))
+		}
+		return
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Stock is running low for SKUs %s ordered recently (fewer than %d left):\n\n", partner.Name, s.cfg.LowStock.Threshold)
+	for _, item := range items {
+		fmt.Fprintf(&body, "  - %s: %d left\n", item.SKU, item.Quantity)
+	}
+	body.WriteString("\nConsider pausing these listings to avoid orders that cannot be fulfilled.\n")
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      []string{subscription.Email},
+		Subject: fmt.Sprintf("Low stock: %d SKU(s) you ordered recently", len(items)),
+		Body:    body.String(),
+	}); err != nil {
+		s.logger.Error("Failed to email low-stock alert",
+			zap.String("partner_id", partner.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// RunLowStockCheck periodically checks the stock of recently ordered variants. It returns
+// when ctx is cancelled.
+func (s *lowStockService) RunLowStockCheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.Check(ctx, now); err != nil {
+				s.logger.Error("Low-stock check failed", zap.Error(err))
+			}
+		}
+	}
+}
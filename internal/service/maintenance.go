@@ -0,0 +1,98 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+)
+
+// Sources of maintenance mode
+const (
+	MaintenanceSourceConfig = "config"
+	MaintenanceSourceAdmin  = "admin"
+)
+
+// MaintenanceStatus is whether mutations are rejected for maintenance, and why
+type MaintenanceStatus struct {
+	Enabled bool
+	// Source is MaintenanceSourceConfig (MAINTENANCE_MODE) or MaintenanceSourceAdmin
+	Source string
+	// Message, StartedAt, EndsAt and StartedBy are set for maintenance started by an admin
+	Message   string
+	StartedAt *time.Time
+	EndsAt    *time.Time
+	StartedBy *uuid.UUID
+	// RetryAfter is how long clients should wait before retrying
+	RetryAfter time.Duration
+}
+
+// Maintenance switches maintenance mode, in which mutations are rejected so that database
+// migrations and Shopify maintenance windows do not leave orders half-written. It is on
+// while MAINTENANCE_MODE is set or an admin started it; the admin switch is held in
+// memory, per instance, and does not survive a restart.
+type Maintenance struct {
+	cfg *config.Config
+
+	mu     sync.RWMutex
+	status *MaintenanceStatus
+}
+
+// NewMaintenance creates the maintenance switch
+func NewMaintenance(cfg *config.Config) *Maintenance {
+	return &Maintenance{cfg: cfg}
+}
+
+// Status returns the current maintenance status. A nil switch is never on.
+func (m *Maintenance) Status() MaintenanceStatus {
+	if m == nil {
+		return MaintenanceStatus{}
+	}
+	retryAfter := m.cfg.Tunables().MaintenanceRetryAfter
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.status != nil {
+		status := *m.status
+		status.RetryAfter = retryAfter
+		// Until the announced end, clients are told to come back then
+		if status.EndsAt != nil {
+			if untilEnd := time.Until(*status.EndsAt).Round(time.Second); untilEnd >= time.Second {
+				status.RetryAfter = untilEnd
+			}
+		}
+		return status
+	}
+	if m.cfg.Tunables().Maintenance {
+		return MaintenanceStatus{Enabled: true, Source: MaintenanceSourceConfig, RetryAfter: retryAfter}
+	}
+	return MaintenanceStatus{}
+}
+
+// Start turns maintenance mode on. endsAt is when it is expected to end; it does not end
+// by itself, End does.
+func (m *Maintenance) Start(message string, endsAt *time.Time, adminID uuid.UUID) MaintenanceStatus {
+	now := time.Now()
+	m.mu.Lock()
+	m.status = &MaintenanceStatus{
+		Enabled:   true,
+		Source:    MaintenanceSourceAdmin,
+		Message:   message,
+		StartedAt: &now,
+		EndsAt:    endsAt,
+		StartedBy: &adminID,
+	}
+	m.mu.Unlock()
+	return m.Status()
+}
+
+// End turns off maintenance mode started by an admin. MAINTENANCE_MODE stays in effect.
+func (m *Maintenance) End() MaintenanceStatus {
+	m.mu.Lock()
+	m.status = nil
+	m.mu.Unlock()
+	return m.Status()
+}
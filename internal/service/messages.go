@@ -0,0 +1,143 @@
+package service
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+)
+
+// MessageOrderTracking is the key of the tracking-page message for shipped orders
+const MessageOrderTracking = "order.tracking"
+
+// Unicode directional isolates (LRI ... PDI) keep Latin references and tracking
+// numbers readable inside right-to-left text
+const (
+	leftToRightIsolate    = "\u2066"
+	popDirectionalIsolate = "\u2069"
+)
+
+// orderMessageSources are the customer-facing texts partners may show their customers,
+// keyed by locale and then by webhook event type (or MessageOrderTracking)
+var orderMessageSources = map[domain.Locale]map[string]string{
+	domain.LocaleEnglish: {
+		WebhookEventOrderConfirmed: "Your order {{ltr .Reference}} has been confirmed.",
+		WebhookEventOrderShipped:   "Your order {{ltr .Reference}} has shipped with {{ltr .Carrier}}. Tracking number: {{ltr .TrackingNumber}}",
+		WebhookEventOrderDelivered: "Your order {{ltr .Reference}} has been delivered.",
+		WebhookEventOrderOnHold:    "Your order {{ltr .Reference}} is on hold. We will contact you shortly.",
+		WebhookEventOrderReleased:  "Your order {{ltr .Reference}} is being processed again.",
+		MessageOrderTracking:       "Your order {{ltr .Reference}} has shipped with {{ltr .Carrier}}. Tracking number: {{ltr .TrackingNumber}}",
+	},
+	domain.LocaleArabic: {
+		WebhookEventOrderConfirmed: "تم تأكيد طلبك {{ltr .Reference}}.",
+		WebhookEventOrderShipped:   "تم شحن طلبك {{ltr .Reference}} مع {{ltr .Carrier}}. رقم التتبع: {{ltr .TrackingNumber}}",
+		WebhookEventOrderDelivered: "تم توصيل طلبك {{ltr .Reference}}.",
+		WebhookEventOrderOnHold:    "طلبك {{ltr .Reference}} معلّق مؤقتاً. سنتواصل معك قريباً.",
+		WebhookEventOrderReleased:  "تمت متابعة معالجة طلبك {{ltr .Reference}}.",
+		MessageOrderTracking:       "تم شحن طلبك {{ltr .Reference}} مع {{ltr .Carrier}}. رقم التتبع: {{ltr .TrackingNumber}}",
+	},
+}
+
+// statusLabels are customer-facing order status names
+var statusLabels = map[domain.Locale]map[domain.OrderStatus]string{
+	domain.LocaleEnglish: {
+		domain.OrderStatusPendingConfirmation: "Pending confirmation",
+		domain.OrderStatusConfirmed:           "Confirmed",
+		domain.OrderStatusOnHold:              "On hold",
+		domain.OrderStatusRejected:            "Rejected",
+		domain.OrderStatusShipped:             "Shipped",
+		domain.OrderStatusDelivered:           "Delivered",
+		domain.OrderStatusCancelled:           "Cancelled",
+	},
+	domain.LocaleArabic: {
+		domain.OrderStatusPendingConfirmation: "بانتظار التأكيد",
+		domain.OrderStatusConfirmed:           "مؤكد",
+		domain.OrderStatusOnHold:              "معلّق",
+		domain.OrderStatusRejected:            "مرفوض",
+		domain.OrderStatusShipped:             "تم الشحن",
+		domain.OrderStatusDelivered:           "تم التوصيل",
+		domain.OrderStatusCancelled:           "ملغى",
+	},
+}
+
+var orderMessages = parseOrderMessages()
+
+// orderMessageData is what the message templates can refer to
+type orderMessageData struct {
+	Reference      string
+	Status         string
+	Carrier        string
+	TrackingNumber string
+}
+
+func parseOrderMessages() map[domain.Locale]map[string]*template.Template {
+	parsed := make(map[domain.Locale]map[string]*template.Template, len(orderMessageSources))
+	for locale, sources := range orderMessageSources {
+		rtl := locale.IsRTL()
+		funcs := template.FuncMap{
+			"ltr": func(s string) string {
+				if !rtl || s == "" {
+					return s
+				}
+				return leftToRightIsolate + s + popDirectionalIsolate
+			},
+		}
+
+		parsed[locale] = make(map[string]*template.Template, len(sources))
+		for key, source := range sources {
+			parsed[locale][key] = template.Must(template.New(key).Funcs(funcs).Parse(source))
+		}
+	}
+	return parsed
+}
+
+// ResolveLocale returns the order's locale, falling back to the default
+func ResolveLocale(order *domain.SupplierOrder) domain.Locale {
+	if order.Locale.IsValid() {
+		return order.Locale
+	}
+	return domain.DefaultLocale
+}
+
+// RenderOrderMessage renders the customer-facing message for key in the given locale.
+// Returns false if there is no message for key.
+func RenderOrderMessage(locale domain.Locale, key string, order *domain.SupplierOrder) (string, bool) {
+	tmpl, ok := orderMessages[locale][key]
+	if !ok {
+		tmpl, ok = orderMessages[domain.DefaultLocale][key]
+	}
+	if !ok {
+		return "", false
+	}
+
+	data := orderMessageData{
+		Reference: order.PartnerOrderID,
+		Status:    StatusLabel(locale, order.Status),
+	}
+	if order.Reference != nil {
+		data.Reference = *order.Reference
+	}
+	if order.TrackingCarrier != nil {
+		data.Carrier = *order.TrackingCarrier
+	}
+	if order.TrackingNumber != nil {
+		data.TrackingNumber = *order.TrackingNumber
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", false
+	}
+	return out.String(), true
+}
+
+// StatusLabel returns the customer-facing name of an order status
+func StatusLabel(locale domain.Locale, status domain.OrderStatus) string {
+	if label, ok := statusLabels[locale][status]; ok {
+		return label
+	}
+	if label, ok := statusLabels[domain.DefaultLocale][status]; ok {
+		return label
+	}
+	return string(status)
+}
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/apikey"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// DefaultInvitationTTL is how long an invitation token stays valid when no TTL is given
+const DefaultInvitationTTL = 72 * time.Hour
+
+// Audit log actions for the onboarding flow
+const (
+	AuditActionInvitationCreated    = "invitation_created"
+	AuditActionInvitationAccepted   = "invitation_accepted"
+	AuditActionPartnerCreated       = "partner_created"
+	AuditActionWebhookURLUpdated    = "webhook_url_updated"
+	AuditActionLocaleUpdated        = "locale_updated"
+	AuditActionPaymentTermsUpdated  = "payment_terms_updated"
+	AuditActionStatusCodesUpdated   = "status_codes_updated"
+	AuditActionWebhookSecretRotated = "webhook_secret_rotated"
+	AuditActionCreditLimitUpdated   = "credit_limit_updated"
+	AuditActionSubstitutionsUpdated = "substitutions_updated"
+	AuditActionAPIKeyRevoked        = "api_key_revoked"
+	AuditActionAPIKeyIssued         = "api_key_issued"
+	AuditActionAPIKeyImported       = "api_key_imported"
+	AuditActionOrganizationCreated  = "organization_created"
+	AuditActionOrganizationUpdated  = "partner_organization_updated"
+)
+
+type onboardingService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewOnboardingService creates a new partner onboarding service
+func NewOnboardingService(repos *repository.Repositories, logger *zap.Logger) *onboardingService {
+	return &onboardingService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// CreateInvitation issues a one-time invitation token for a new partner.
+// The plain token is only returned here; we store its SHA256 hash.
+func (s *onboardingService) CreateInvitation(
+	ctx context.Context,
+	actor string,
+	partnerName string,
+	ttl time.Duration,
+) (*domain.PartnerInvitation, string, error) {
+	if ttl <= 0 {
+		ttl = DefaultInvitationTTL
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	invitation := &domain.PartnerInvitation{
+		PartnerName: partnerName,
+		TokenHash:   hashToken(token),
+		CreatedBy:   actor,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+
+	if err := s.repos.PartnerInvitation.Create(ctx, invitation); err != nil {
+		return nil, "", err
+	}
+
+	s.audit(ctx, actor, AuditActionInvitationCreated, "partner_invitation", invitation.ID.String(), map[string]interface{}{
+		"partner_name": partnerName,
+		"expires_at":   invitation.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+
+	return invitation, token, nil
+}
+
+// AcceptInvitation exchanges an invitation token for a new active partner and its API key.
+// The plain API key is only returned here.
+func (s *onboardingService) AcceptInvitation(
+	ctx context.Context,
+	token string,
+	webhookURL *string,
+) (*domain.Partner, string, error) {
+	if webhookURL != nil {
+		if err := ValidateWebhookURL(*webhookURL); err != nil {
+			return nil, "", err
+		}
+	}
+
+	invitation, err := s.repos.PartnerInvitation.Claim(ctx, hashToken(token))
+	if err != nil {
+		return nil, "", err
+	}
+
+	actor := fmt.Sprintf("invitation:%s", invitation.ID)
+	s.audit(ctx, actor, AuditActionInvitationAccepted, "partner_invitation", invitation.ID.String(), nil)
+
+	apiKey, err := apikey.Generate(apikey.ShortCode(invitation.PartnerName))
+	if err != nil {
+		return nil, "", err
+	}
+	keyPrefix, _ := apikey.Parse(apiKey)
+
+	apiKeyHash, err := bcrypt.GenerateFromPassword([]byte(apiKey), 10)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to hash API key: %w", err)
+	}
+
+	webhookSecret, err := newWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	partner := &domain.Partner{
+		Name:             invitation.PartnerName,
+		APIKeyHash:       string(apiKeyHash),
+		APIKeyLookupHash: domain.APIKeyLookupHash(apiKey),
+		APIKeyPrefix:     keyPrefix.String(),
+		WebhookURL:       webhookURL,
+		WebhookSecret:    &webhookSecret,
+		IsActive:         true,
+	}
+
+	if err := s.repos.Partner.Create(ctx, partner); err != nil {
+		// The invitation is already consumed - an admin has to issue a new one
+		s.logger.Error("Failed to create partner from invitation",
+			zap.String("invitation_id", invitation.ID.String()),
+			zap.Error(err),
+		)
+		return nil, "", err
+	}
+
+	if err := s.repos.PartnerInvitation.SetPartnerID(ctx, invitation.ID, partner.ID); err != nil {
+		s.logger.Warn("Failed to link invitation to partner", zap.Error(err))
+	}
+
+	data := map[string]interface{}{
+		"name":          partner.Name,
+		"invitation_id": invitation.ID.String(),
+	}
+	if webhookURL != nil {
+		data["webhook_url"] = *webhookURL
+	}
+	s.audit(ctx, actor, AuditActionPartnerCreated, "partner", partner.ID.String(), data)
+
+	return partner, apiKey, nil
+}
+
+// UpdateWebhookURL lets a partner set (or clear, with nil) their own webhook URL. Partners
+// without a webhook secret get one; it is returned only then, otherwise secret is empty.
+func (s *onboardingService) UpdateWebhookURL(ctx context.Context, partner *domain.Partner, webhookURL *string) (secret string, err error) {
+	if webhookURL != nil {
+		if err := ValidateWebhookURL(*webhookURL); err != nil {
+			return "", err
+		}
+		if partner.WebhookSecret == nil {
+			if secret, err = newWebhookSecret(); err != nil {
+				return "", err
+			}
+			partner.WebhookSecret = &secret
+		}
+	}
+
+	previous := partner.WebhookURL
+	partner.WebhookURL = webhookURL
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return "", err
+	}
+
+	data := map[string]interface{}{
+		"from": previous,
+		"to":   webhookURL,
+	}
+	s.audit(ctx, fmt.Sprintf("partner:%s", partner.ID), AuditActionWebhookURLUpdated, "partner", partner.ID.String(), data)
+
+	return secret, nil
+}
+
+// RotateWebhookSecret replaces the partner's webhook signing secret and returns the new one.
+// Webhooks are signed with the new secret right away.
+func (s *onboardingService) RotateWebhookSecret(ctx context.Context, partner *domain.Partner) (string, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+
+	partner.WebhookSecret = &secret
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return "", err
+	}
+
+	s.audit(ctx, fmt.Sprintf("partner:%s", partner.ID), AuditActionWebhookSecretRotated, "partner", partner.ID.String(), nil)
+
+	return secret, nil
+}
+
+// RevokeAPIKey puts the partner's current API key on the revocation list. Every server rejects
+// it from the next request on, even while it holds the partner in its auth cache. The partner
+// stays active and needs a new key.
+func (s *onboardingService) RevokeAPIKey(ctx context.Context, actor string, partner *domain.Partner, reason string) (*domain.RevokedAPIKey, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"reason": "is required"},
+		}
+	}
+
+	revoked := &domain.RevokedAPIKey{
+		APIKeyHash: partner.APIKeyHash,
+		PartnerID:  partner.ID,
+		Reason:     reason,
+		RevokedBy:  actor,
+	}
+	if err := s.repos.RevokedAPIKey.Create(ctx, revoked); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actor, AuditActionAPIKeyRevoked, "partner", partner.ID.String(), map[string]interface{}{"reason": reason})
+
+	return revoked, nil
+}
+
+// MinImportedAPIKeyLength is the shortest key ImportAPIKey accepts
+const MinImportedAPIKeyLength = 16
+
+// IssueAPIKey replaces the partner's API key with a newly generated one (see pkg/apikey) and
+// returns it; the plain key is only returned here. The old key stops working at once.
+func (s *onboardingService) IssueAPIKey(ctx context.Context, actor string, partner *domain.Partner) (string, error) {
+	apiKey, err := apikey.Generate(apikey.ShortCode(partner.Name))
+	if err != nil {
+		return "", err
+	}
+	if err := s.replaceAPIKey(ctx, actor, partner, apiKey); err != nil {
+		return "", err
+	}
+	s.audit(ctx, actor, AuditActionAPIKeyIssued, "partner", partner.ID.String(), map[string]interface{}{"api_key_prefix": partner.APIKeyPrefix})
+	return apiKey, nil
+}
+
+// ImportAPIKey replaces the partner's API key with a given one, for keys partners already use
+// with another system. Only for legacy imports: generated keys are stronger.
+func (s *onboardingService) ImportAPIKey(ctx context.Context, actor string, partner *domain.Partner, apiKey string) error {
+	if len(apiKey) < MinImportedAPIKeyLength || strings.ContainsAny(apiKey, " \t\r\n") {
+		return &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"api_key": fmt.Sprintf("must be at least %d characters without whitespace", MinImportedAPIKeyLength)},
+		}
+	}
+	if err := s.replaceAPIKey(ctx, actor, partner, apiKey); err != nil {
+		return err
+	}
+	s.audit(ctx, actor, AuditActionAPIKeyImported, "partner", partner.ID.String(), map[string]interface{}{"api_key_prefix": partner.APIKeyPrefix})
+	return nil
+}
+
+// replaceAPIKey stores apiKey as the partner's key. The old key is put on the revocation list
+// first, so servers holding the partner in their auth cache reject it too.
+func (s *onboardingService) replaceAPIKey(ctx context.Context, actor string, partner *domain.Partner, apiKey string) error {
+	apiKeyHash, err := bcrypt.GenerateFromPassword([]byte(apiKey), 10)
+	if err != nil {
+		return fmt.Errorf("failed to hash API key: %w", err)
+	}
+
+	if partner.APIKeyHash != "" {
+		revoked := &domain.RevokedAPIKey{
+			APIKeyHash: partner.APIKeyHash,
+			PartnerID:  partner.ID,
+			Reason:     "replaced by a new key",
+			RevokedBy:  actor,
+		}
+		if err := s.repos.RevokedAPIKey.Create(ctx, revoked); err != nil {
+			// Already revoked
+			if _, ok := err.(*errors.ErrConflict); !ok {
+				return err
+			}
+		}
+	}
+
+	partner.APIKeyHash = string(apiKeyHash)
+	partner.APIKeyLookupHash = domain.APIKeyLookupHash(apiKey)
+	partner.APIKeyPrefix = ""
+	if prefix, ok := apikey.Parse(apiKey); ok {
+		partner.APIKeyPrefix = prefix.String()
+	}
+	return s.repos.Partner.Update(ctx, partner)
+}
+
+// UpdateLocale sets the partner's default locale for customer-facing texts
+func (s *onboardingService) UpdateLocale(ctx context.Context, partner *domain.Partner, tag string) error {
+	locale, ok := domain.ParseLocale(tag)
+	if !ok {
+		return &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"locale": "unsupported locale, use en or ar"},
+		}
+	}
+
+	previous := partner.Locale
+	partner.Locale = locale
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"from": previous,
+		"to":   locale,
+	}
+	s.audit(ctx, fmt.Sprintf("partner:%s", partner.ID), AuditActionLocaleUpdated, "partner", partner.ID.String(), data)
+
+	return nil
+}
+
+// UpdateLegacyStatusCodes switches the partner between 200 (legacy) and 201/202 responses
+// for new orders on /v1
+func (s *onboardingService) UpdateLegacyStatusCodes(ctx context.Context, partner *domain.Partner, legacy bool) error {
+	previous := partner.LegacyStatusCodes
+	partner.LegacyStatusCodes = legacy
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"from": previous,
+		"to":   legacy,
+	}
+	s.audit(ctx, fmt.Sprintf("partner:%s", partner.ID), AuditActionStatusCodesUpdated, "partner", partner.ID.String(), data)
+
+	return nil
+}
+
+// UpdateSubstitutions sets whether the partner's draft orders stay open until the order is
+// confirmed, so items can be substituted
+func (s *onboardingService) UpdateSubstitutions(ctx context.Context, actor string, partner *domain.Partner, enabled bool) error {
+	previous := partner.Substitutions
+	partner.Substitutions = enabled
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"from": previous,
+		"to":   enabled,
+	}
+	s.audit(ctx, actor, AuditActionSubstitutionsUpdated, "partner", partner.ID.String(), data)
+
+	return nil
+}
+
+// UpdatePaymentTerms sets whether the partner's draft orders are completed right away or
+// sent as invoices, and where invoices go (nil leaves it to the order's customer email)
+func (s *onboardingService) UpdatePaymentTerms(ctx context.Context, actor string, partner *domain.Partner, terms domain.PaymentTerms, invoiceEmail *string) error {
+	if !terms.IsValid() {
+		return &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"payment_terms": "unsupported payment terms, use prepaid or invoice"},
+		}
+	}
+
+	previous := partner.PaymentTerms
+	partner.PaymentTerms = terms
+	partner.InvoiceEmail = invoiceEmail
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"from":          previous,
+		"to":            terms,
+		"invoice_email": invoiceEmail,
+	}
+	s.audit(ctx, actor, AuditActionPaymentTermsUpdated, "partner", partner.ID.String(), data)
+
+	return nil
+}
+
+// UpdateCreditLimit sets the partner's credit limit (nil removes it) and what happens to carts over it
+func (s *onboardingService) UpdateCreditLimit(ctx context.Context, actor string, partner *domain.Partner, limit *float64, action domain.CreditLimitAction) error {
+	fields := make(map[string]string)
+	if limit != nil && *limit < 0 {
+		fields["credit_limit"] = "must not be negative"
+	}
+	if !action.IsValid() {
+		fields["action"] = "unsupported action, use hold or reject"
+	}
+	if len(fields) > 0 {
+		return &errors.ErrValidation{Message: "validation failed", Fields: fields}
+	}
+
+	previous := partner.CreditLimit
+	partner.CreditLimit = limit
+	partner.CreditLimitAction = action
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"from":   previous,
+		"to":     limit,
+		"action": action,
+	}
+	s.audit(ctx, actor, AuditActionCreditLimitUpdated, "partner", partner.ID.String(), data)
+
+	return nil
+}
+
+// ValidateWebhookURL checks that a webhook URL is an absolute http(s) URL
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return &errors.ErrValidation{
+			Message: "invalid webhook URL",
+			Fields:  map[string]string{"webhook_url": "must be an absolute http(s) URL"},
+		}
+	}
+	return nil
+}
+
+// audit writes an audit log entry; failures are logged but never fail the action
+func (s *onboardingService) audit(ctx context.Context, actor, action, resourceType, resourceID string, data map[string]interface{}) {
+	entry := &domain.AuditLog{
+		Actor:        actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Data:         data,
+	}
+	if err := s.repos.AuditLog.Create(ctx, entry); err != nil {
+		s.logger.Warn("Failed to write audit log", zap.String("action", action), zap.Error(err))
+	}
+}
+
+// randomToken returns n bytes of crypto/rand entropy, base64url encoded
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newWebhookSecret returns a webhook signing secret, prefixed so it is recognisable in config
+func newWebhookSecret() (string, error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + secret, nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
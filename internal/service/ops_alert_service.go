@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/opsalerts"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// EventTypeConfirmationSLAAlerted is recorded when the ops channel was told an order is
+// pending confirmation longer than the SLA, so it is alerted about once
+const EventTypeConfirmationSLAAlerted = "confirmation_sla_alerted"
+
+// slaCheckPageSize is how many pending orders one page of the SLA check reads
+const slaCheckPageSize = 200
+
+// orderAlertFields are the fields every order alert shows
+func orderAlertFields(order *domain.SupplierOrder, partnerName string) []opsalerts.Field {
+	reference := stringValue(order.Reference)
+	if reference == "" {
+		reference = order.ID.String()
+	}
+	fields := []opsalerts.Field{{Name: "Order", Value: reference}}
+	if partnerName != "" {
+		fields = append(fields, opsalerts.Field{Name: "Partner", Value: partnerName})
+	}
+	return append(fields,
+		opsalerts.Field{Name: "Partner order ID", Value: order.PartnerOrderID},
+		opsalerts.Field{Name: "Total", Value: fmt.Sprintf("%.2f", order.CartTotal)},
+	)
+}
+
+// alertOrderAwaitingConfirmation tells the ops channel about a new order staff must confirm
+func alertOrderAwaitingConfirmation(cfg *config.Config, logger *zap.Logger, order *domain.SupplierOrder, partner *domain.Partner) {
+	opsalerts.New(cfg.OpsAlerts, logger).Notify(opsalerts.Alert{
+		EventType: opsalerts.EventOrderAwaitingConfirmation,
+		Title:     "New order awaiting confirmation",
+		Fields:    orderAlertFields(order, partner.Name),
+	})
+}
+
+// alertShopifyFailure tells the ops channel about a Shopify call that failed for an order;
+// the order is left as it is for staff to fix in Shopify or retry
+func alertShopifyFailure(cfg *config.Config, logger *zap.Logger, operation string, order *domain.SupplierOrder, err error) {
+	opsalerts.New(cfg.OpsAlerts, logger).Notify(opsalerts.Alert{
+		EventType: opsalerts.EventShopifyOperationFailed,
+		Title:     "Shopify operation failed",
+		Text:      err.Error(),
+		Fields:    append(orderAlertFields(order, ""), opsalerts.Field{Name: "Operation", Value: operation}),
+	})
+}
+
+type opsAlertService struct {
+	cfg    *config.Config
+	repos  *repository.Repositories
+	alerts *opsalerts.Notifier
+	logger *zap.Logger
+}
+
+// NewOpsAlertService creates a service that alerts the ops channel about orders left
+// pending confirmation longer than the confirmation SLA
+func NewOpsAlertService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *opsAlertService {
+	return &opsAlertService{
+		cfg:    cfg,
+		repos:  repos,
+		alerts: opsalerts.New(cfg.OpsAlerts, logger),
+		logger: logger,
+	}
+}
+
+// CheckConfirmationSLA alerts about every order pending confirmation for longer than the
+// SLA that was not alerted about before, and records the alert on the order. Returns how
+// many orders were alerted about.
+func (s *opsAlertService) CheckConfirmationSLA(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.Add(-s.cfg.OpsAlerts.ConfirmationSLA)
+
+	// Pending orders are listed newest first, so the overdue ones come last
+	var overdue []*domain.SupplierOrder
+	var after *domain.OrderCursor
+	for {
+		orders, err := s.repos.SupplierOrder.ListByStatusAfter(ctx, domain.OrderStatusPendingConfirmation, after, slaCheckPageSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, order := range orders {
+			// Time on hold does not count toward the SLA
+			if order.PendingSince().Before(cutoff) {
+				overdue = append(overdue, order)
+			}
+		}
+		if len(orders) < slaCheckPageSize {
+			break
+		}
+		after = domain.CursorAfter(orders[len(orders)-1])
+	}
+	if len(overdue) == 0 {
+		return 0, nil
+	}
+
+	oldest := overdue[len(overdue)-1].CreatedAt
+	events, err := s.repos.OrderEvent.ListLatestByType(ctx, EventTypeConfirmationSLAAlerted, oldest, now.Add(time.Second))
+	if err != nil {
+		return 0, err
+	}
+	alerted := make(map[uuid.UUID]bool, len(events))
+	for _, event := range events {
+		alerted[event.SupplierOrderID] = true
+	}
+
+	count := 0
+	partnerNames := make(map[uuid.UUID]string)
+	for _, order := range overdue {
+		if alerted[order.ID] {
+			continue
+		}
+		partnerName, ok := partnerNames[order.PartnerID]
+		if !ok {
+			if partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID); err == nil {
+				partnerName = partner.Name
+			}
+			partnerNames[order.PartnerID] = partnerName
+		}
+
+		event := &domain.OrderEvent{
+			SupplierOrderID: order.ID,
+			EventType:       EventTypeConfirmationSLAAlerted,
+			EventData: map[string]interface{}{
+				"sla":           s.cfg.OpsAlerts.ConfirmationSLA.String(),
+				"pending_since": order.PendingSince().UTC().Format(time.RFC3339),
+			},
+		}
+		if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+			return count, err
+		}
+
+		s.alerts.Notify(opsalerts.Alert{
+			EventType: opsalerts.EventConfirmationSLABreached,
+			Title:     "Order pending confirmation past the SLA",
+			Text:      fmt.Sprintf("Pending for %s (SLA %s)", now.Sub(order.PendingSince()).Round(time.Minute), s.cfg.OpsAlerts.ConfirmationSLA),
+			Fields:    orderAlertFields(order, partnerName),
+		})
+		count++
+	}
+	return count, nil
+}
+
+// RunSLACheck checks pending orders against the confirmation SLA at the given interval until
+// ctx is cancelled
+func (s *opsAlertService) RunSLACheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.CheckConfirmationSLA(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to check the confirmation SLA", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+func TestCheckConfirmationSLAPausesOnHold(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	partner := &domain.Partner{ID: uuid.New(), Name: "Zain Shop"}
+	overdue := &domain.SupplierOrder{ID: uuid.New(), PartnerID: partner.ID, Status: domain.OrderStatusPendingConfirmation, CreatedAt: now.Add(-5 * time.Hour)}
+	// Pending 5 hours, 2 of them on hold
+	released := &domain.SupplierOrder{ID: uuid.New(), PartnerID: partner.ID, Status: domain.OrderStatusPendingConfirmation, CreatedAt: now.Add(-5 * time.Hour), HeldSeconds: 2 * 60 * 60}
+	events := &reviewEvents{}
+	s := &opsAlertService{
+		cfg: &config.Config{OpsAlerts: config.OpsAlertsConfig{ConfirmationSLA: 4 * time.Hour}},
+		repos: &repository.Repositories{
+			Partner:       &batchPartners{partner: partner},
+			SupplierOrder: &reviewOrders{orders: []*domain.SupplierOrder{released, overdue}},
+			OrderEvent:    events,
+		},
+		logger: zap.NewNop(),
+	}
+
+	alerted, err := s.CheckConfirmationSLA(context.Background(), now)
+	if err != nil {
+		t.Fatalf("CheckConfirmationSLA() error = %v", err)
+	}
+	if alerted != 1 || len(events.recorded) != 1 || events.recorded[0].SupplierOrderID != overdue.ID {
+		t.Errorf("alerted %d orders, want only the one never held", alerted)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// FieldDifference is one field whose recorded value differs from Shopify's
+type FieldDifference struct {
+	Field    string `json:"field"`
+	Recorded string `json:"recorded"`
+	Shopify  string `json:"shopify"`
+}
+
+// CustomLineComparison counts the custom (non-variant) lines, which have no stable key to
+// compare them one by one
+type CustomLineComparison struct {
+	Recorded int  `json:"recorded_quantity"`
+	Shopify  int  `json:"shopify_quantity"`
+	Differs  bool `json:"differs"`
+}
+
+// FulfillmentComparison puts the order status next to the Shopify order's fulfillment state
+type FulfillmentComparison struct {
+	Status                   domain.OrderStatus `json:"status"`
+	ShopifyFulfillmentStatus string             `json:"shopify_fulfillment_status"`
+	ShopifyFinancialStatus   string             `json:"shopify_financial_status"`
+	ShopifyCancelled         bool               `json:"shopify_cancelled"`
+	// Consistent is false when one side shipped (or cancelled) and the other did not
+	Consistent             bool     `json:"consistent"`
+	TrackingNumber         *string  `json:"tracking_number"`
+	ShopifyTrackingNumbers []string `json:"shopify_tracking_numbers"`
+	TrackingMatches        bool     `json:"tracking_matches"`
+}
+
+// OrderDiff is our record of an order compared with its Shopify order
+type OrderDiff struct {
+	ShopifyOrderID   int64                 `json:"shopify_order_id"`
+	ShopifyOrderName string                `json:"shopify_order_name"`
+	InSync           bool                  `json:"in_sync"`
+	Items            []LineItemDivergence  `json:"items"`
+	CustomLines      CustomLineComparison  `json:"custom_lines"`
+	Totals           *TotalComparison      `json:"totals"`
+	Address          []FieldDifference     `json:"address"`
+	Fulfillment      FulfillmentComparison `json:"fulfillment"`
+}
+
+type orderDiffService struct {
+	cfg     *config.Config
+	repos   *repository.Repositories
+	shopify ShopifyService
+	logger  *zap.Logger
+}
+
+// NewOrderDiffService creates a new order/Shopify diff service
+func NewOrderDiffService(cfg *config.Config, repos *repository.Repositories, shopify ShopifyService, logger *zap.Logger) *orderDiffService {
+	return &orderDiffService{
+		cfg:     cfg,
+		repos:   repos,
+		shopify: shopify,
+		logger:  logger,
+	}
+}
+
+// DiffOrder compares the order with its Shopify order as it is now. Unlike the edit sync and
+// total check it records nothing; it is meant for support looking into one order.
+// It returns nil for orders without a Shopify order.
+func (s *orderDiffService) DiffOrder(ctx context.Context, order *domain.SupplierOrder) (*OrderDiff, error) {
+	if order.ShopifyOrderID == nil {
+		return nil, nil
+	}
+
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+snapshot, err := s.shopify.GetOrderSnapshot(ctx, *order.ShopifyOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &OrderDiff{
+		ShopifyOrderID:   *order.ShopifyOrderID,
+		ShopifyOrderName: snapshot.Name,
+		Items:            diffLineItems(items, snapshot.LineItems),
+		CustomLines:      compareCustomLines(items, snapshot.LineItems),
+		Totals:           compareTotals(s.cfg, order, snapshot.Total, snapshot.Currency),
+		Address:          diffAddress(order.ShippingAddress, snapshot.ShippingAddress),
+		Fulfillment:      compareFulfillment(order, snapshot),
+	}
+	if diff.Items == nil {
+		diff.Items = []LineItemDivergence{}
+	}
+	diff.InSync = len(diff.Items) == 0 && !diff.CustomLines.Differs && !diff.Totals.Mismatch &&
+		len(diff.Address) == 0 && diff.Fulfillment.Consistent && diff.Fulfillment.TrackingMatches
+
+	return diff, nil
+}
+
+func compareCustomLines(items []*domain.SupplierOrderItem, lineItems []ShopifyLineItem) CustomLineComparison {
+	var comparison CustomLineComparison
+	for _, item := range items {
+		if !item.IsSupplierItem || item.ShopifyVariantID == nil {
+			comparison.Recorded += item.Quantity
+		}
+	}
+	for _, line := range lineItems {
+		// The tax line added in cart tax mode is not one of our items
+		if line.VariantID == nil && line.Title != "Tax" {
+			comparison.Shopify += line.Quantity
+		}
+	}
+	comparison.Differs = comparison.Recorded != comparison.Shopify
+	return comparison
+}
+
+// diffAddress compares the address fields sent to Shopify, ignoring case and surrounding space
+func diffAddress(recorded domain.Address, shopify *domain.Address) []FieldDifference {
+	differences := []FieldDifference{}
+	if shopify == nil {
+		return append(differences, FieldDifference{Field: "shipping_address", Recorded: recorded.Street})
+	}
+
+	compare := func(field, ours, theirs string) {
+		if !strings.EqualFold(strings.TrimSpace(ours), strings.TrimSpace(theirs)) {
+			differences = append(differences, FieldDifference{Field: field, Recorded: ours, Shopify: theirs})
+		}
+	}
+	compare("street", recorded.Street, shopify.Street)
+	compare("address2", stringValue(recorded.Address2), stringValue(shopify.Address2))
+	compare("city", recorded.City, shopify.City)
+	compare("state", stringValue(recorded.State), stringValue(shopify.State))
+	compare("postal_code", recorded.PostalCode, shopify.PostalCode)
+	compare("country", recorded.Country, shopify.Country)
+	return differences
+}
+
+func compareFulfillment(order *domain.SupplierOrder, snapshot *ShopifyOrderSnapshot) FulfillmentComparison {
+	comparison := FulfillmentComparison{
+		Status:                   order.Status,
+		ShopifyFulfillmentStatus: snapshot.FulfillmentStatus,
+		ShopifyFinancialStatus:   snapshot.FinancialStatus,
+		ShopifyCancelled:         snapshot.Cancelled,
+		TrackingNumber:           order.TrackingNumber,
+		ShopifyTrackingNumbers:   snapshot.TrackingNumbers,
+	}
+	if comparison.ShopifyTrackingNumbers == nil {
+		comparison.ShopifyTrackingNumbers = []string{}
+	}
+
+	shipped := order.Status == domain.OrderStatusShipped || order.Status == domain.OrderStatusDelivered
+	closed := order.Status == domain.OrderStatusRejected || order.Status == domain.OrderStatusCancelled
+	shopifyShipped := snapshot.FulfillmentStatus == "FULFILLED" || snapshot.FulfillmentStatus == "PARTIALLY_FULFILLED"
+	comparison.Consistent = shipped == shopifyShipped && closed == snapshot.Cancelled
+
+	// Tracking only has to match when both sides have some
+	comparison.TrackingMatches = true
+	if order.TrackingNumber != nil && len(snapshot.TrackingNumbers) > 0 {
+		comparison.TrackingMatches = false
+		for _, number := range snapshot.TrackingNumbers {
+			if strings.EqualFold(number, *order.TrackingNumber) {
+				comparison.TrackingMatches = true
+				break
+			}
+		}
+	}
+
+	return comparison
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
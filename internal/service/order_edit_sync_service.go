@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// EventTypeShopifyItemsDiverged is recorded when the Shopify order's line items no longer
+// match the items we recorded (e.g. warehouse staff edited the order in Shopify admin)
+const EventTypeShopifyItemsDiverged = "shopify_items_diverged"
+
+// WebhookEventOrderItemsChanged tells the partner that items were changed in Shopify
+const WebhookEventOrderItemsChanged = "order.items_changed"
+
+// Line item divergence kinds
+const (
+	DivergenceQuantityChanged = "quantity_changed"
+	DivergenceRemoved         = "removed"
+	DivergenceAdded           = "added"
+)
+
+// editSyncStatuses are the statuses in which staff may still edit the Shopify order
+var editSyncStatuses = []domain.OrderStatus{
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusShipped,
+}
+
+// LineItemDivergence is one difference between our items and the Shopify order
+type LineItemDivergence struct {
+	Change           string `json:"change"`
+	SKU              string `json:"sku"`
+	ShopifyVariantID int64  `json:"shopify_variant_id"`
+	Recorded         int    `json:"recorded_quantity"`
+	Shopify          int    `json:"shopify_quantity"`
+}
+
+type orderEditSyncService struct {
+	cfg      *config.Config
+	repos    *repository.Repositories
+	shopify  ShopifyService
+	webhooks WebhookService
+	logger   *zap.Logger
+}
+
+// NewOrderEditSyncService creates a new Shopify order edit reconciliation service
+func NewOrderEditSyncService(cfg *config.Config, repos *repository.Repositories, shopify ShopifyService, webhooks WebhookService, logger *zap.Logger) *orderEditSyncService {
+	return &orderEditSyncService{
+		cfg:      cfg,
+		repos:    repos,
+		shopify:  shopify,
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+// ReconcileOrder compares the supplier items of an order with the current line items of its
+// Shopify order. Only variant-backed (supplier) lines are compared; custom lines for partner
+// items have no stable key. A divergence event is recorded, and the partner optionally
+// notified, only when the differences changed since the last recorded divergence.
+func (s *orderEditSyncService) ReconcileOrder(ctx context.Context, order *domain.SupplierOrder) ([]LineItemDivergence, error) {
+	if order.ShopifyOrderID == nil {
+		return nil, nil
+	}
+
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	lineItems, edited, err := s.shopify.GetOrderLineItems(ctx, *order.ShopifyOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if !edited {
+		return nil, nil
+	}
+
+	divergences := diffLineItems(items, lineItems)
+	if len(divergences) == 0 {
+		return nil, nil
+	}
+
+	signature := divergenceSignature(divergences)
+	recorded, err := s.lastDivergenceSignature(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if recorded == signature {
+		return divergences, nil
+	}
+
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeShopifyItemsDiverged,
+		EventData: map[string]interface{}{
+			"shopify_order_id": *order.ShopifyOrderID,
+			"differences":      divergences,
+			"signature":        signature,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("Shopify order items diverged from recorded items",
+		zap.String("order_id", order.ID.String()),
+		zap.Int64("shopify_order_id", *order.ShopifyOrderID),
+		zap.Int("differences", len(divergences)),
+	)
+
+	if s.cfg.Tunables().NotifyPartnerOnShopifyEdits {
+		s.webhooks.NotifyOrderEvent(order, WebhookEventOrderItemsChanged, map[string]interface{}{
+			"differences": divergences,
+		})
+	}
+
+	return divergences, nil
+}
+
+// RunEditSync periodically reconciles recent confirmed and shipped orders. It returns when ctx is cancelled.
+func (s *orderEditSyncService) RunEditSync(ctx context.Context, interval, window time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		orders, err := s.repos.SupplierOrder.ListCreatedSince(ctx, time.Now().Add(-window), editSyncStatuses)
+		if err != nil {
+			s.logger.Error("Shopify edit sync failed", zap.Error(err))
+			continue
+		}
+
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return
+			}
+			if _, err := s.ReconcileOrder(ctx, order); err != nil {
+				s.logger.Warn("Failed to reconcile order with Shopify",
+					zap.String("order_id", order.ID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
+
+func (s *orderEditSyncService) lastDivergenceSignature(ctx context.Context, order *domain.SupplierOrder) (string, error) {
+	events, err := s.repos.OrderEvent.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return "", err
+	}
+
+	signature := ""
+	var latest time.Time
+	for _, event := range events {
+		if event.EventType != EventTypeShopifyItemsDiverged || event.CreatedAt.Before(latest) {
+			continue
+		}
+		if sig, ok := event.EventData["signature"].(string); ok {
+			signature = sig
+			latest = event.CreatedAt
+		}
+	}
+	return signature, nil
+}
+
+// diffLineItems compares recorded supplier item quantities with Shopify quantities per variant
+func diffLineItems(items []*domain.SupplierOrderItem, lineItems []ShopifyLineItem) []LineItemDivergence {
+	recorded := make(map[int64]int)
+	skus := make(map[int64]string)
+	for _, item := range items {
+		if !item.IsSupplierItem || item.ShopifyVariantID == nil {
+			continue
+		}
+		recorded[*item.ShopifyVariantID] += item.Quantity
+		skus[*item.ShopifyVariantID] = item.SKU
+	}
+
+	current := make(map[int64]int)
+	for _, line := range lineItems {
+		if line.VariantID == nil {
+			continue
+		}
+		current[*line.VariantID] += line.Quantity
+		if _, ok := skus[*line.VariantID]; !ok {
+			skus[*line.VariantID] = line.SKU
+		}
+	}
+
+	var divergences []LineItemDivergence
+	for variantID, quantity := range recorded {
+		shopifyQuantity := current[variantID]
+		switch {
+		case shopifyQuantity == 0:
+			divergences = append(divergences, LineItemDivergence{Change: DivergenceRemoved, SKU: skus[variantID], ShopifyVariantID: variantID, Recorded: quantity})
+		case shopifyQuantity != quantity:
+			divergences = append(divergences, LineItemDivergence{Change: DivergenceQuantityChanged, SKU: skus[variantID], ShopifyVariantID: variantID, Recorded: quantity, Shopify: shopifyQuantity})
+		}
+	}
+	for variantID, quantity := range current {
+		if _, ok := recorded[variantID]; !ok && quantity > 0 {
+			divergences = append(divergences, LineItemDivergence{Change: DivergenceAdded, SKU: skus[variantID], ShopifyVariantID: variantID, Shopify: quantity})
+		}
+	}
+
+	sort.Slice(divergences, func(i, j int) bool {
+		return divergences[i].ShopifyVariantID < divergences[j].ShopifyVariantID
+	})
+	return divergences
+}
+
+// divergenceSignature identifies a set of differences so unchanged ones are not recorded twice
+func divergenceSignature(divergences []LineItemDivergence) string {
+	parts := make([]string, len(divergences))
+	for i, d := range divergences {
+		parts[i] = fmt.Sprintf("%s:%d:%d:%d", d.Change, d.ShopifyVariantID, d.Recorded, d.Shopify)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+)
+
+// EventTypeAutoCancelled is recorded when an order pending confirmation for too long is cancelled
+const EventTypeAutoCancelled = "auto_cancelled"
+
+// CancelReasonExpired is the reason given to partners for orders cancelled by the expiry policy
+const CancelReasonExpired = "not confirmed in time"
+
+// orderExpiryBatch is how many expired orders one pass cancels before listing again
+const orderExpiryBatch = 100
+
+type orderExpiryService struct {
+	cfg      *config.Config
+	repos    *repository.Repositories
+	shopify  ShopifyService
+	webhooks WebhookService
+	logger   *zap.Logger
+}
+
+// NewOrderExpiryService creates a service that cancels orders left pending confirmation
+// beyond ORDER_PENDING_EXPIRY
+func NewOrderExpiryService(cfg *config.Config, repos *repository.Repositories, shopify ShopifyService, webhooks WebhookService, logger *zap.Logger) *orderExpiryService {
+	return &orderExpiryService{
+		cfg:      cfg,
+		repos:    repos,
+		shopify:  shopify,
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+// ExpirePending cancels every order still pending confirmation PendingExpiry after it was
+// created, not counting time on hold, deletes its Shopify draft order and tells the partner (order.cancelled). Orders
+// already completed in Shopify are left alone. Returns how many orders were cancelled.
+func (s *orderExpiryService) ExpirePending(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.Add(-s.cfg.Orders.PendingExpiry)
+
+	cancelled := 0
+	for {
+		orders, err := s.repos.SupplierOrder.ListPendingCreatedBefore(ctx, cutoff, orderExpiryBatch)
+		if err != nil {
+			return cancelled, err
+		}
+
+		expired := 0
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return cancelled, ctx.Err()
+			}
+
+			// Only cancels if nobody confirmed, rejected or held the order meanwhile
+			ok, err := s.repos.SupplierOrder.ExpirePending(ctx, order.ID, cutoff, now)
+			if err != nil {
+				return cancelled, err
+			}
+			if !ok {
+				continue
+			}
+			expired++
+			cancelled++
+			s.cancelled(ctx, order, now)
+		}
+
+		// Orders that could not be expired are not listed again
+		if len(orders) < orderExpiryBatch || expired == 0 {
+			return cancelled, nil
+		}
+	}
+}
+
+func (s *orderExpiryService) cancelled(ctx context.Context, order *domain.SupplierOrder, cancelledAt time.Time) {
+	eventData := map[string]interface{}{
+		"pending_expiry": s.cfg.Orders.PendingExpiry.String(),
+		"created_at":     order.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	// A draft left open would still show up for staff to complete
+	if order.ShopifyDraftOrderID != nil {
+		eventData["shopify_draft_order_id"] = *order.ShopifyDraftOrderID
+		err := s.shopify.DeleteDraftOrder(ctx, *order.ShopifyDraftOrderID)
+		eventData["draft_order_deleted"] = err == nil
+		if err != nil && err != shopify.ErrDryRun {
+			eventData["draft_order_error"] = err.Error()
+			s.logger.Warn("Failed to delete draft order of expired order",
+				zap.String("order_id", order.ID.String()),
+				zap.Int64("draft_order_id", *order.ShopifyDraftOrderID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       "status_change",
+		EventData: map[string]interface{}{
+			"from":   order.Status,
+			"to":     domain.OrderStatusCancelled,
+			"reason": CancelReasonExpired,
+		},
+	})
+	s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeAutoCancelled,
+		EventData:       eventData,
+	})
+
+	s.logger.Info("Cancelled order pending confirmation for too long",
+		zap.String("order_id", order.ID.String()),
+		zap.Time("created_at", order.CreatedAt),
+	)
+
+	order.Status = domain.OrderStatusCancelled
+	order.CancelledAt = &cancelledAt
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderCancelled, map[string]interface{}{
+		"reason":       CancelReasonExpired,
+		"cancelled_at": cancelledAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// RunExpiry periodically cancels expired pending orders. It returns when ctx is cancelled.
+func (s *orderExpiryService) RunExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.ExpirePending(ctx, now); err != nil {
+				s.logger.Error("Order expiry failed", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+type orderLookupService struct {
+	repos   *repository.Repositories
+	shopify ShopifyService
+	logger  *zap.Logger
+}
+
+// NewOrderLookupService creates a new order lookup service
+func NewOrderLookupService(repos *repository.Repositories, shopify ShopifyService, logger *zap.Logger) *orderLookupService {
+	return &orderLookupService{
+		repos:   repos,
+		shopify: shopify,
+		logger:  logger,
+	}
+}
+
+// ResolveShopifyOrder resolves a Shopify order ID back to our SupplierOrder.
+// It first checks our own shopify_order_id linkage and falls back to the
+// b2b.supplier_order_id metafield stored on the Shopify order.
+func (s *orderLookupService) ResolveShopifyOrder(ctx context.Context, shopifyOrderID int64) (*domain.SupplierOrder, error) {
+	order, err := s.repos.SupplierOrder.GetByShopifyOrderID(ctx, shopifyOrderID)
+	if err == nil {
+		return order, nil
+	}
+	if _, ok := err.(*errors.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	// Not linked locally (e.g. the order ID update failed) - ask Shopify
+	metafields, err := s.shopify.GetOrderLinkageMetafields(ctx, shopifyOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	supplierOrderIDStr, ok := metafields[MetafieldKeySupplierOrderID]
+	if !ok {
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: strconv.FormatInt(shopifyOrderID, 10)}
+	}
+
+	supplierOrderID, err := uuid.Parse(supplierOrderIDStr)
+	if err != nil {
+		s.logger.Warn("Invalid supplier_order_id metafield on Shopify order",
+			zap.Int64("shopify_order_id", shopifyOrderID),
+			zap.String("value", supplierOrderIDStr),
+		)
+		return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: supplierOrderIDStr}
+	}
+
+	order, err = s.repos.SupplierOrder.GetByID(ctx, supplierOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repair the missing local linkage so the next lookup is a direct hit
+	if order.ShopifyOrderID == nil {
+		if err := s.repos.SupplierOrder.UpdateShopifyOrderID(ctx, order.ID, shopifyOrderID, ""); err != nil {
+			s.logger.Warn("Failed to backfill Shopify order ID", zap.Error(err))
+		} else {
+			order.ShopifyOrderID = &shopifyOrderID
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// EventTypeOrderReassigned is recorded when an admin moves an order to another partner
+const EventTypeOrderReassigned = "partner_reassigned"
+
+// Webhook events of a reassignment: the new partner gets the order, the old one is told
+// it is gone
+const (
+	WebhookEventOrderReassigned     = "order.reassigned"
+	WebhookEventOrderReassignedAway = "order.reassigned_away"
+)
+
+// ReassignOrder moves an order that was submitted from the wrong partner account to
+// toPartnerID. The order keeps its status, items and Shopify orders; its idempotency keys
+// move with it, so the old partner can no longer replay them. The reassignment is
+// recorded on the order timeline with the admin and reason, and both partners are notified.
+func (s *orderService) ReassignOrder(ctx context.Context, orderID, toPartnerID uuid.UUID, reason string, adminID uuid.UUID) (*domain.SupplierOrder, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"reason": "reason is required"},
+		}
+	}
+
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.PartnerID == toPartnerID {
+		return nil, &errors.ErrConflict{Message: "order already belongs to this partner"}
+	}
+
+	from, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.repos.Partner.GetByID(ctx, toPartnerID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return nil, &errors.ErrValidation{
+				Message: "validation failed",
+				Fields:  map[string]string{"partner_id": "partner not found"},
+			}
+		}
+		return nil, err
+	}
+	if !to.IsActive {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"partner_id": "partner is inactive"},
+		}
+	}
+
+	// Partner order IDs are unique per partner
+	existing, err := s.repos.SupplierOrder.GetByPartnerIDAndPartnerOrderID(ctx, to.ID, order.PartnerOrderID)
+	if err == nil {
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("partner already has order %s with partner_order_id %s", existing.ID, order.PartnerOrderID)}
+	}
+	if _, ok := err.(*errors.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	moved, err := s.repos.SupplierOrder.Reassign(ctx, order.ID, from.ID, to.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, &errors.ErrConflict{Message: "order was reassigned in the meantime"}
+	}
+
+	// The order is moved either way; keys left behind only make the old partner's retries fail
+	keysMoved, err := s.repos.IdempotencyKey.ReassignOrder(ctx, order.ID, to.ID)
+	if err != nil {
+		s.logger.Error("Failed to move idempotency keys of reassigned order",
+			zap.String("order_id", order.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeOrderReassigned,
+		EventData: map[string]interface{}{
+			"from_partner_id":        from.ID.String(),
+			"from_partner_name":      from.Name,
+			"to_partner_id":          to.ID.String(),
+			"to_partner_name":        to.Name,
+			"admin_partner_id":       adminID.String(),
+			"reason":                 reason,
+			"idempotency_keys_moved": keysMoved,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		s.logger.Error("Failed to record order reassignment", zap.String("order_id", order.ID.String()), zap.Error(err))
+	}
+
+	s.logger.Info("Order reassigned",
+		zap.String("order_id", order.ID.String()),
+		zap.String("from_partner_id", from.ID.String()),
+		zap.String("to_partner_id", to.ID.String()),
+		zap.String("admin_partner_id", adminID.String()),
+		zap.String("reason", reason),
+	)
+
+	order.PartnerID = to.ID
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderReassigned, map[string]interface{}{
+		"reason": reason,
+	})
+	s.webhooks.NotifyPartnerEvent(from, WebhookEventOrderReassignedAway, map[string]interface{}{
+		"supplier_order_id": order.ID.String(),
+		"partner_order_id":  order.PartnerOrderID,
+		"reference":         stringValue(order.Reference),
+		"reason":            reason,
+	})
+
+	return order, nil
+}
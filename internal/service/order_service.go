@@ -2,34 +2,41 @@ package service
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/phone"
 	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
 type orderService struct {
-	repos  *repository.Repositories
-	logger *zap.Logger
+	repos    *repository.Repositories
+	webhooks WebhookService
+	logger   *zap.Logger
 }
 
 // NewOrderService creates a new order service
 func NewOrderService(repos *repository.Repositories, logger *zap.Logger) *orderService {
 	return &orderService{
-		repos:  repos,
-		logger: logger,
+		repos:    repos,
+		webhooks: NewWebhookService(repos, logger),
+		logger:   logger,
 	}
 }
 
-// CreateOrderFromCart creates a supplier order from a cart submission
+// CreateOrderFromCart creates a supplier order from a cart submission.
+// referencePrefix starts the order's human-friendly reference (e.g. B2B-2024-000123).
 func (s *orderService) CreateOrderFromCart(
 	ctx context.Context,
 	partnerID uuid.UUID,
 	req CartSubmitRequest,
-	supplierItems map[string]*domain.SKUMapping,
+	supplierItems map[string]*CartItemMatch,
+	referencePrefix string,
 ) (*domain.SupplierOrder, error) {
 	// Create order
 	order := &domain.SupplierOrder{
@@ -38,23 +45,50 @@ func (s *orderService) CreateOrderFromCart(
 		Status:         domain.OrderStatusPendingConfirmation,
 		CustomerName:   req.Customer.Name,
 		CartTotal:      req.Totals.Total,
+		CartTax:        req.Totals.Tax,
+		CartShipping:   req.Totals.Shipping,
 		PaymentStatus:  req.PaymentStatus,
 		PaymentMethod:  req.PaymentMethod,
+		Channel:        req.Channel,
+		CreatedAt:      time.Now(),
 	}
+	order.UpdatedAt = order.CreatedAt
+
+	referenceNumber, err := s.repos.SupplierOrder.NextReferenceNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reference := domain.FormatOrderReference(referencePrefix, order.CreatedAt, referenceNumber)
+	order.Reference = &reference
+
+	if req.Locale != nil {
+		order.Locale = domain.Locale(*req.Locale)
+	}
+
+	contentHash := CartContentHash(req)
+	order.ContentHash = &contentHash
 
 	if req.Customer.Phone != nil {
 		order.CustomerPhone = *req.Customer.Phone
+		// Already validated by the cart service; nil for countries we have no numbering plan for
+		if normalized, err := phone.Normalize(*req.Customer.Phone, req.Shipping.Country); err == nil && normalized != "" {
+			order.CustomerPhoneE164 = &normalized
+		}
 	}
 
-	// Convert shipping address to map
-	order.ShippingAddress = map[string]interface{}{
-		"street":      req.Shipping.Street,
-		"city":        req.Shipping.City,
-		"postal_code": req.Shipping.PostalCode,
-		"country":     req.Shipping.Country,
+	if req.Customer.Email != nil && *req.Customer.Email != "" {
+		email := strings.ToLower(strings.TrimSpace(*req.Customer.Email))
+		order.CustomerEmail = &email
 	}
-	if req.Shipping.State != nil {
-		order.ShippingAddress["state"] = *req.Shipping.State
+
+	order.ShippingAddress = domain.Address{
+		Street:     req.Shipping.Street,
+		Address2:   req.Shipping.Address2,
+		City:       req.Shipping.City,
+		State:      req.Shipping.State,
+		PostalCode: req.Shipping.PostalCode,
+		Country:    req.Shipping.Country,
+		Notes:      req.Shipping.Notes,
 	}
 
 	// Create order in database
@@ -65,19 +99,30 @@ func (s *orderService) CreateOrderFromCart(
 	// Create order items
 	items := make([]*domain.SupplierOrderItem, 0, len(req.Items))
 	for _, cartItem := range req.Items {
+		listPrice, discount, _ := cartItem.PriceBreakdown()
 		item := &domain.SupplierOrderItem{
 			SupplierOrderID: order.ID,
 			SKU:             cartItem.SKU,
 			Title:           cartItem.Title,
 			Price:           cartItem.Price,
+			ListPrice:       listPrice,
+			Discount:        discount,
 			Quantity:        cartItem.Quantity,
 			ProductURL:      cartItem.ProductURL,
+			WeightGrams:     cartItem.WeightGrams,
+		}
+		if d := cartItem.Dimensions; d != nil {
+			item.Dimensions = &domain.Dimensions{LengthCm: d.LengthCm, WidthCm: d.WidthCm, HeightCm: d.HeightCm}
 		}
 
 		// Check if this is a supplier item
-		if mapping, ok := supplierItems[cartItem.SKU]; ok {
+		if match, ok := supplierItems[cartItem.SKU]; ok {
 			item.IsSupplierItem = true
-			item.ShopifyVariantID = &mapping.ShopifyVariantID
+			item.ShopifyVariantID = &match.Mapping.ShopifyVariantID
+			item.MatchMethod = &match.MatchMethod
+			if item.WeightGrams == nil {
+				item.WeightGrams = match.Mapping.WeightGrams
+			}
 		}
 
 		items = append(items, item)
@@ -117,8 +162,13 @@ func (s *orderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) erro
 		}
 	}
 
+	// Orders over the approval threshold are confirmed by the second admin approval
+	if err := checkApproved(ctx, s.repos, orderID); err != nil {
+		return err
+	}
+
 	// Update status
-	if err := s.repos.SupplierOrder.UpdateStatus(ctx, orderID, domain.OrderStatusConfirmed, nil); err != nil {
+	if err := s.repos.SupplierOrder.UpdateStatus(ctx, orderID, domain.OrderStatusConfirmed, nil, time.Now()); err != nil {
 		return err
 	}
 
@@ -133,11 +183,20 @@ func (s *orderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) erro
 	}
 	s.repos.OrderEvent.Create(ctx, event)
 
+	order.Status = domain.OrderStatusConfirmed
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderConfirmed, nil)
+
 	return nil
 }
 
-// RejectOrder rejects an order
-func (s *orderService) RejectOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+// RejectOrder rejects an order with a rejection code and an optional free-text reason.
+// Without a code the reason is required and the code is RejectionCodeOther.
+func (s *orderService) RejectOrder(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) error {
+	code, err := validateRejection(code, reason)
+	if err != nil {
+		return err
+	}
+
 	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
 	if err != nil {
 		return err
@@ -151,8 +210,13 @@ func (s *orderService) RejectOrder(ctx context.Context, orderID uuid.UUID, reaso
 		}
 	}
 
+	var reasonPtr *string
+	if reason != "" {
+		reasonPtr = &reason
+	}
+
 	// Update status
-	if err := s.repos.SupplierOrder.UpdateStatus(ctx, orderID, domain.OrderStatusRejected, &reason); err != nil {
+	if err := s.repos.SupplierOrder.Reject(ctx, orderID, code, reasonPtr, time.Now()); err != nil {
 		return err
 	}
 
@@ -163,14 +227,108 @@ func (s *orderService) RejectOrder(ctx context.Context, orderID uuid.UUID, reaso
 		EventData: map[string]interface{}{
 			"from":   order.Status,
 			"to":     domain.OrderStatusRejected,
+			"code":   code,
 			"reason": reason,
 		},
 	}
 	s.repos.OrderEvent.Create(ctx, event)
 
+	order.Status = domain.OrderStatusRejected
+	order.RejectionCode = &code
+	order.RejectionReason = reasonPtr
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderRejected, map[string]interface{}{
+		"code":   code,
+		"reason": reason,
+	})
+
 	return nil
 }
 
+// Bulk rejection results
+const (
+	BulkRejectRejected          = "rejected"
+	BulkRejectNotFound          = "not_found"
+	BulkRejectInvalidTransition = "invalid_transition"
+	BulkRejectFailed            = "failed"
+	// The order was not rejected because its ETag was stale or missing
+	BulkRejectPreconditionFailed   = "precondition_failed"
+	BulkRejectPreconditionRequired = "precondition_required"
+)
+
+// BulkRejectResult is the outcome of rejecting one order of a bulk rejection
+type BulkRejectResult struct {
+	OrderID uuid.UUID `json:"order_id"`
+	Result  string    `json:"result"`
+	Error   string    `json:"error,omitempty"`
+	// ETag is the order's current ETag when its precondition failed
+	ETag string `json:"etag,omitempty"`
+}
+
+// RejectOrders rejects each order with the same code and reason, as RejectOrder does for
+// one (events and webhooks included). Orders are rejected independently: one that cannot
+// be rejected does not stop the others. An invalid code or reason fails the whole call
+// with ErrValidation before any order is touched. Repeated IDs are rejected once.
+func (s *orderService) RejectOrders(ctx context.Context, orderIDs []uuid.UUID, code domain.RejectionCode, reason string) ([]BulkRejectResult, error) {
+	code, err := validateRejection(code, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(orderIDs))
+	results := make([]BulkRejectResult, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if seen[orderID] {
+			continue
+		}
+		seen[orderID] = true
+
+		result := BulkRejectResult{OrderID: orderID, Result: BulkRejectRejected}
+		if err := s.RejectOrder(ctx, orderID, code, reason); err != nil {
+			result.Error = err.Error()
+			switch err.(type) {
+			case *errors.ErrNotFound:
+				result.Result = BulkRejectNotFound
+			case *errors.ErrInvalidStateTransition:
+				result.Result = BulkRejectInvalidTransition
+			default:
+				s.logger.Error("Failed to reject order in bulk",
+					zap.String("order_id", orderID.String()),
+					zap.Error(err),
+				)
+				result.Result = BulkRejectFailed
+				result.Error = "failed to reject order"
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// validateRejection checks the rejection code and returns the one to record
+func validateRejection(code domain.RejectionCode, reason string) (domain.RejectionCode, error) {
+	if code == "" {
+		code = domain.RejectionCodeOther
+	}
+	if !code.IsValid() {
+		codes := make([]string, len(domain.RejectionCodes))
+		for i, c := range domain.RejectionCodes {
+			codes[i] = string(c)
+		}
+		return "", &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"code": "must be one of " + strings.Join(codes, ", ")},
+		}
+	}
+	if code == domain.RejectionCodeOther && strings.TrimSpace(reason) == "" {
+		return "", &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"reason": "reason is required when the code is OTHER or missing"},
+		}
+	}
+	return code, nil
+}
+
 // ShipOrder marks an order as shipped with tracking information
 func (s *orderService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error {
 	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
@@ -186,8 +344,13 @@ func (s *orderService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier
 		}
 	}
 
-	// Update tracking
-	if err := s.repos.SupplierOrder.UpdateTracking(ctx, orderID, &carrier, &trackingNumber, trackingURL); err != nil {
+	// Never ship an order that is still waiting for approvals
+	if err := checkApproved(ctx, s.repos, orderID); err != nil {
+		return err
+	}
+
+	// Status and tracking change together, and only if nobody moved the order meanwhile
+	if err := s.repos.SupplierOrder.UpdateStatusWithTracking(ctx, orderID, order.Status, domain.OrderStatusShipped, &carrier, &trackingNumber, trackingURL, time.Now()); err != nil {
 		return err
 	}
 
@@ -207,5 +370,143 @@ func (s *orderService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier
 	}
 	s.repos.OrderEvent.Create(ctx, event)
 
+	order.Status = domain.OrderStatusShipped
+	order.TrackingCarrier = &carrier
+	order.TrackingNumber = &trackingNumber
+	order.TrackingURL = trackingURL
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderShipped, map[string]interface{}{
+		"carrier":         carrier,
+		"tracking_number": trackingNumber,
+		"tracking_url":    trackingURL,
+	})
+
+	return nil
+}
+
+// DeliverOrder marks a shipped order delivered. source says who reported the delivery
+// (e.g. a carrier code) and proofOfDelivery is kept on the status_change event when given.
+func (s *orderService) DeliverOrder(ctx context.Context, orderID uuid.UUID, deliveredAt time.Time, source string, proofOfDelivery map[string]interface{}) error {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	// Validate state transition
+	if !order.Status.CanTransitionTo(domain.OrderStatusDelivered) {
+		return &errors.ErrInvalidStateTransition{
+			From: order.Status,
+			To:   domain.OrderStatusDelivered,
+		}
+	}
+
+	// Update status
+	if err := s.repos.SupplierOrder.UpdateStatus(ctx, orderID, domain.OrderStatusDelivered, nil, deliveredAt); err != nil {
+		return err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: orderID,
+		EventType:       "status_change",
+		EventData: map[string]interface{}{
+			"from":         order.Status,
+			"to":           domain.OrderStatusDelivered,
+			"source":       source,
+			"delivered_at": deliveredAt.UTC().Format(time.RFC3339),
+		},
+	}
+	if len(proofOfDelivery) > 0 {
+		event.EventData["proof_of_delivery"] = proofOfDelivery
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order.Status = domain.OrderStatusDelivered
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderDelivered, map[string]interface{}{
+		"delivered_at": deliveredAt.UTC().Format(time.RFC3339),
+	})
+
+	return nil
+}
+
+// HoldOrder puts an order on hold for payment or fraud review. While held the
+// order cannot be confirmed or shipped.
+func (s *orderService) HoldOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	// Validate state transition
+	if !order.Status.CanTransitionTo(domain.OrderStatusOnHold) {
+		return &errors.ErrInvalidStateTransition{
+			From: order.Status,
+			To:   domain.OrderStatusOnHold,
+		}
+	}
+
+	// Update status
+	if err := s.repos.SupplierOrder.Hold(ctx, orderID, order.Status, reason); err != nil {
+		return err
+	}
+
+	// Log event
+	event := &domain.OrderEvent{
+		SupplierOrderID: orderID,
+		EventType:       "status_change",
+		EventData: map[string]interface{}{
+			"from":   order.Status,
+			"to":     domain.OrderStatusOnHold,
+			"reason": reason,
+		},
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order.Status = domain.OrderStatusOnHold
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderOnHold, map[string]interface{}{
+		"reason": reason,
+	})
+
+	return nil
+}
+
+// ReleaseOrder releases a held order back to the status it was held from
+func (s *orderService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != domain.OrderStatusOnHold || order.HeldFromStatus == nil || !order.HeldFromStatus.CanHoldFrom() {
+		return &errors.ErrConflict{Message: "order is not on hold"}
+	}
+	releaseTo := *order.HeldFromStatus
+
+	// Update status
+	if err := s.repos.SupplierOrder.Release(ctx, orderID, releaseTo); err != nil {
+		// Released or cancelled by someone else since it was read
+		if _, ok := err.(*errors.ErrInvalidStateTransition); ok {
+			return &errors.ErrConflict{Message: "order is not on hold"}
+		}
+		return err
+	}
+
+	// Log event
+	eventData := map[string]interface{}{
+		"from": order.Status,
+		"to":   releaseTo,
+	}
+	if order.HeldAt != nil {
+		eventData["held_seconds"] = int(time.Since(*order.HeldAt).Seconds())
+	}
+	event := &domain.OrderEvent{
+		SupplierOrderID: orderID,
+		EventType:       "status_change",
+		EventData:       eventData,
+	}
+	s.repos.OrderEvent.Create(ctx, event)
+
+	order.Status = releaseTo
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderReleased, nil)
+
 	return nil
 }
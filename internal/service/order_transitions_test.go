@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// transition is a status change written to the order repository
+type transition struct {
+	from, to domain.OrderStatus
+}
+
+// transitionOrders holds a single order and records every status write to it
+type transitionOrders struct {
+	repository.SupplierOrderRepository
+	t      *testing.T
+	order  domain.SupplierOrder
+	writes []transition
+}
+
+func (f *transitionOrders) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
+	order := f.order
+	return &order, nil
+}
+
+func (f *transitionOrders) write(to domain.OrderStatus) {
+	f.writes = append(f.writes, transition{from: f.order.Status, to: to})
+	f.order.Status = to
+}
+
+func (f *transitionOrders) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.OrderStatus, rejectionReason *string, changedAt time.Time) error {
+	f.write(status)
+	return nil
+}
+
+func (f *transitionOrders) Reject(ctx context.Context, id uuid.UUID, code domain.RejectionCode, reason *string, rejectedAt time.Time) error {
+	f.write(domain.OrderStatusRejected)
+	return nil
+}
+
+func (f *transitionOrders) UpdateStatusWithTracking(ctx context.Context, id uuid.UUID, fromStatus, status domain.OrderStatus, carrier, trackingNumber, trackingURL *string, shippedAt time.Time) error {
+	if fromStatus != f.order.Status {
+		f.t.Errorf("UpdateStatusWithTracking() from %s, order is %s", fromStatus, f.order.Status)
+	}
+	f.write(status)
+	return nil
+}
+
+func (f *transitionOrders) Hold(ctx context.Context, id uuid.UUID, fromStatus domain.OrderStatus, reason string) error {
+	if fromStatus != f.order.Status {
+		f.t.Errorf("Hold() from %s, order is %s", fromStatus, f.order.Status)
+	}
+	f.write(domain.OrderStatusOnHold)
+	return nil
+}
+
+func (f *transitionOrders) Release(ctx context.Context, id uuid.UUID, status domain.OrderStatus) error {
+	f.write(status)
+	return nil
+}
+
+// noEvents has no approval requirements and discards logged events
+type noEvents struct {
+	repository.OrderEventRepository
+}
+
+func (noEvents) Create(ctx context.Context, event *domain.OrderEvent) error { return nil }
+
+func (noEvents) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderEvent, error) {
+	return nil, nil
+}
+
+// noWebhooks drops notifications
+type noWebhooks struct {
+	WebhookService
+}
+
+func (noWebhooks) NotifyOrderEvent(order *domain.SupplierOrder, eventType string, data map[string]interface{}) {
+}
+
+// orderActions are all orderService methods that change an order's status
+var orderActions = map[string]func(s *orderService, id uuid.UUID) error{
+	"ConfirmOrder": func(s *orderService, id uuid.UUID) error {
+		return s.ConfirmOrder(context.Background(), id)
+	},
+	"RejectOrder": func(s *orderService, id uuid.UUID) error {
+		return s.RejectOrder(context.Background(), id, domain.RejectionCode("OUT_OF_STOCK"), "")
+	},
+	"ShipOrder": func(s *orderService, id uuid.UUID) error {
+		return s.ShipOrder(context.Background(), id, "dhl", "JD0001", nil)
+	},
+	"DeliverOrder": func(s *orderService, id uuid.UUID) error {
+		return s.DeliverOrder(context.Background(), id, time.Now(), "dhl", nil)
+	},
+	"HoldOrder": func(s *orderService, id uuid.UUID) error {
+		return s.HoldOrder(context.Background(), id, "payment review")
+	},
+	"ReleaseOrder": func(s *orderService, id uuid.UUID) error {
+		return s.ReleaseOrder(context.Background(), id)
+	},
+}
+
+// orderStates are the orders the actions are tried on: every status, statuses the
+// lifecycle does not define, and held orders with each possible held-from status
+func orderStates() []domain.SupplierOrder {
+	statuses := []domain.OrderStatus{
+		domain.OrderStatusPendingConfirmation, domain.OrderStatusConfirmed, domain.OrderStatusRejected,
+		domain.OrderStatusShipped, domain.OrderStatusDelivered, domain.OrderStatusCancelled,
+		domain.OrderStatusOnHold, "PARTIALLY_SHIPPED", "",
+	}
+	states := make([]domain.SupplierOrder, 0, len(statuses)*2)
+	for _, status := range statuses {
+		states = append(states, domain.SupplierOrder{ID: uuid.New(), Status: status})
+	}
+	for _, heldFrom := range statuses {
+		heldFrom := heldFrom
+		heldAt := time.Now().Add(-time.Hour)
+		states = append(states, domain.SupplierOrder{
+			ID:             uuid.New(),
+			Status:         domain.OrderStatusOnHold,
+			HeldAt:         &heldAt,
+			HeldFromStatus: &heldFrom,
+		})
+	}
+	return states
+}
+
+// TestOrderServiceTransitions tries every status-changing method on every order
+// state: each write must be a defined transition, and refused actions write nothing
+func TestOrderServiceTransitions(t *testing.T) {
+	for name, action := range orderActions {
+		for _, state := range orderStates() {
+			orders := &transitionOrders{t: t, order: state}
+			s := &orderService{
+				repos:    &repository.Repositories{SupplierOrder: orders, OrderEvent: noEvents{}},
+				webhooks: noWebhooks{},
+				logger:   zap.NewNop(),
+			}
+
+			err := action(s, state.ID)
+
+			heldFrom := "-"
+			if state.HeldFromStatus != nil {
+				heldFrom = string(*state.HeldFromStatus)
+			}
+			if err != nil {
+				switch err.(type) {
+				case *errors.ErrInvalidStateTransition, *errors.ErrConflict:
+				default:
+					t.Errorf("%s on %q (held from %s): unexpected error %v", name, state.Status, heldFrom, err)
+				}
+				if len(orders.writes) != 0 {
+					t.Errorf("%s on %q (held from %s) failed but wrote %v", name, state.Status, heldFrom, orders.writes)
+				}
+				continue
+			}
+
+			if len(orders.writes) != 1 {
+				t.Errorf("%s on %q (held from %s): %d status writes, want 1", name, state.Status, heldFrom, len(orders.writes))
+				continue
+			}
+			w := orders.writes[0]
+			if !w.to.IsValid() {
+				t.Errorf("%s on %q (held from %s) wrote undefined status %q", name, state.Status, heldFrom, w.to)
+			}
+			if !definedTransition(state, w) {
+				t.Errorf("%s on %q (held from %s) made undefined transition %q -> %q", name, state.Status, heldFrom, w.from, w.to)
+			}
+		}
+	}
+}
+
+// definedTransition reports whether w is allowed by CanTransitionTo or is the release
+// of a hold back into the status the order was held from
+func definedTransition(order domain.SupplierOrder, w transition) bool {
+	if w.from.CanTransitionTo(w.to) {
+		return true
+	}
+	return w.from == domain.OrderStatusOnHold &&
+		order.HeldFromStatus != nil &&
+		*order.HeldFromStatus == w.to &&
+		w.to.CanHoldFrom()
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// CreateOrganization creates an organization that partners can then be added to
+func (s *onboardingService) CreateOrganization(ctx context.Context, actor, name string) (*domain.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"name": "is required"},
+		}
+	}
+
+	organization := &domain.Organization{Name: name}
+	if err := s.repos.Organization.Create(ctx, organization); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actor, AuditActionOrganizationCreated, "organization", organization.ID.String(), map[string]interface{}{"name": name})
+
+	return organization, nil
+}
+
+// UpdatePartnerOrganization adds the partner to an organization, or removes it from its
+// organization when organizationID is nil. The partner's orders stay attributed to it; only
+// who else in the organization can see them changes.
+func (s *onboardingService) UpdatePartnerOrganization(ctx context.Context, actor string, partner *domain.Partner, organizationID *uuid.UUID) error {
+	if organizationID != nil {
+		if _, err := s.repos.Organization.GetByID(ctx, *organizationID); err != nil {
+			if _, ok := err.(*errors.ErrNotFound); ok {
+				return &errors.ErrValidation{
+					Message: "validation failed",
+					Fields:  map[string]string{"organization_id": "organization not found"},
+				}
+			}
+			return err
+		}
+	}
+
+	previous := partner.OrganizationID
+	partner.OrganizationID = organizationID
+	if err := s.repos.Partner.Update(ctx, partner); err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"from": previous,
+		"to":   organizationID,
+	}
+	s.audit(ctx, actor, AuditActionOrganizationUpdated, "partner", partner.ID.String(), data)
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Partner-initiated event types accepted by POST /v1/orders/:id/events
+const (
+	PartnerEventPaymentConfirmed      = "payment_confirmed"
+	PartnerEventCustomerCancelRequest = "customer_cancel_request"
+	PartnerEventAddressCorrection     = "address_correction"
+)
+
+// Actions taken in response to a partner event
+const (
+	PartnerEventActionRecorded = "recorded"
+	PartnerEventActionHeld     = "held"
+)
+
+// PartnerOrderEventRequest is an update pushed by a partner about one of its orders
+type PartnerOrderEventRequest struct {
+	Type            string           `json:"type" binding:"required,oneof=payment_confirmed customer_cancel_request address_correction"`
+	Reason          *string          `json:"reason,omitempty" binding:"omitempty,max=500"`
+	PaymentMethod   *string          `json:"payment_method,omitempty"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+}
+
+// PartnerOrderEventResult describes what was done with a partner event
+type PartnerOrderEventResult struct {
+	Event  *domain.OrderEvent
+	Action string
+}
+
+type partnerEventService struct {
+	cfg          *config.Config
+	repos        *repository.Repositories
+	orders       OrderService
+	shopify      ShopifyService
+	creditAlerts CreditAlertService
+	logger       *zap.Logger
+}
+
+// NewPartnerEventService creates a new partner event inbox service
+func NewPartnerEventService(cfg *config.Config, repos *repository.Repositories, orders OrderService, shopify ShopifyService, creditAlerts CreditAlertService, logger *zap.Logger) *partnerEventService {
+	return &partnerEventService{
+		cfg:          cfg,
+		repos:        repos,
+		orders:       orders,
+		shopify:      shopify,
+		creditAlerts: creditAlerts,
+		logger:       logger,
+	}
+}
+
+// RecordEvent validates a partner event against the order, applies its side effects and
+// records it on the order timeline as partner_<type>.
+// Payment confirmations complete a draft order that was kept open for payment (invoice terms).
+// Cancellation requests and address corrections put open orders on hold for an admin to review;
+// they are never applied automatically.
+func (s *partnerEventService) RecordEvent(ctx context.Context, order *domain.SupplierOrder, req PartnerOrderEventRequest) (*PartnerOrderEventResult, error) {
+	data := map[string]interface{}{}
+	if req.Reason != nil {
+		data["reason"] = *req.Reason
+	}
+
+	action := PartnerEventActionRecorded
+	switch req.Type {
+	case PartnerEventPaymentConfirmed:
+		if order.Status == domain.OrderStatusRejected || order.Status == domain.OrderStatusCancelled {
+			return nil, &errors.ErrConflict{Message: "order is already closed"}
+		}
+		if req.PaymentMethod != nil {
+			method, ok := domain.ParsePaymentMethod(*req.PaymentMethod)
+			if !ok {
+				return nil, &errors.ErrValidation{
+					Message: "validation failed",
+					Fields:  map[string]string{"payment_method": paymentMethodError()},
+				}
+			}
+			methodStr := string(method)
+			req.PaymentMethod = &methodStr
+			data["payment_method"] = methodStr
+		}
+		if err := s.repos.SupplierOrder.UpdatePaymentStatus(ctx, order.ID, domain.PaymentStatusPaid, req.PaymentMethod); err != nil {
+			return nil, err
+		}
+		s.checkCreditAlerts(ctx, order)
+		if order.ShopifyDraftOrderID != nil && order.ShopifyOrderID == nil {
+			awaitingConfirmation, err := s.awaitingConfirmation(ctx, order)
+			if err != nil {
+				return nil, err
+			}
+			// An order waiting for approvals is completed by the last approval instead, and
+			// one open for substitutions by its confirmation
+			if err := checkApproved(ctx, s.repos, order.ID); err != nil {
+				if _, ok := err.(*errors.ErrConflict); !ok {
+					return nil, err
+				}
+				data["shopify_order_completed"] = false
+				data["awaiting_approval"] = true
+			} else if awaitingConfirmation {
+				data["shopify_order_completed"] = false
+				data["awaiting_confirmation"] = true
+			} else {
+				data["shopify_order_completed"] = s.completeDraftOrder(ctx, order)
+			}
+		}
+
+	case PartnerEventCustomerCancelRequest:
+		if order.Status == domain.OrderStatusRejected || order.Status == domain.OrderStatusCancelled {
+			return nil, &errors.ErrConflict{Message: "order is already closed"}
+		}
+		if order.Status.CanHoldFrom() {
+			if err := s.hold(ctx, order, "Customer cancellation requested", req.Reason); err != nil {
+				return nil, err
+			}
+			action = PartnerEventActionHeld
+		}
+
+	case PartnerEventAddressCorrection:
+		if req.ShippingAddress == nil {
+			return nil, &errors.ErrValidation{
+				Message: "validation failed",
+				Fields:  map[string]string{"shipping_address": "required for address_correction"},
+			}
+		}
+		if !order.Status.CanHoldFrom() && order.Status != domain.OrderStatusOnHold {
+			return nil, &errors.ErrConflict{Message: fmt.Sprintf("address can no longer be changed for %s orders", order.Status)}
+		}
+		data["shipping_address"] = req.ShippingAddress
+		if order.Status.CanHoldFrom() {
+			if err := s.hold(ctx, order, "Address correction requested", req.Reason); err != nil {
+				return nil, err
+			}
+			action = PartnerEventActionHeld
+		}
+
+	default:
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"type": "unsupported event type"},
+		}
+	}
+
+	data["action"] = action
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       "partner_" + req.Type,
+		EventData:       data,
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	// Anything the partner asks for that we did not apply needs a human
+	if req.Type != PartnerEventPaymentConfirmed {
+		s.logger.Warn("Partner event needs admin review",
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_order_id", order.PartnerOrderID),
+			zap.String("event_type", req.Type),
+			zap.String("status", string(order.Status)),
+		)
+	}
+
+	return &PartnerOrderEventResult{Event: event, Action: action}, nil
+}
+
+// checkCreditAlerts re-arms the partner's credit limit alerts once a payment frees up credit
+func (s *partnerEventService) checkCreditAlerts(ctx context.Context, order *domain.SupplierOrder) {
+	partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+	if err == nil {
+		_, err = s.creditAlerts.Check(ctx, partner)
+	}
+	if err != nil {
+		s.logger.Error("Failed to check credit limit alerts", zap.String("partner_id", order.PartnerID.String()), zap.Error(err))
+	}
+}
+
+// awaitingConfirmation reports whether the order's draft stays open for substitutions until
+// the order is confirmed
+func (s *partnerEventService) awaitingConfirmation(ctx context.Context, order *domain.SupplierOrder) (bool, error) {
+	if order.Status != domain.OrderStatusPendingConfirmation && order.Status != domain.OrderStatusOnHold {
+		return false, nil
+	}
+	partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+	if err != nil {
+		return false, err
+	}
+	return partner.Substitutions, nil
+}
+
+// completeDraftOrder completes the order's open draft order now that it is paid. A failure is
+// logged, not returned: the payment is recorded either way and staff can complete the draft in Shopify.
+func (s *partnerEventService) completeDraftOrder(ctx context.Context, order *domain.SupplierOrder) bool {
+	if err := s.shopify.CompleteOrder(ctx, order); err != nil {
+		s.logger.Error("Failed to complete Shopify draft order after payment",
+			zap.String("operation", opShopifyCompleteOrder),
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_id", order.PartnerID.String()),
+			zap.Error(err),
+		)
+		alertShopifyFailure(s.cfg, s.logger, opShopifyCompleteOrder, order, err)
+		return false
+	}
+	return true
+}
+
+func (s *partnerEventService) hold(ctx context.Context, order *domain.SupplierOrder, reason string, detail *string) error {
+	if detail != nil && *detail != "" {
+		reason += ": " + *detail
+	}
+	if err := s.orders.HoldOrder(ctx, order.ID, reason); err != nil {
+		return err
+	}
+	order.Status = domain.OrderStatusOnHold
+	return nil
+}
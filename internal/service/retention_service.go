@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// Tables purged by the retention job
+const (
+	RetentionTableIdempotencyKeys = "idempotency_keys"
+	RetentionTableOrderEvents     = "order_events"
+	RetentionTableAuditLogs       = "audit_logs"
+)
+
+// retentionOrderStatuses are the order statuses whose events may be purged: an open order
+// keeps its whole timeline (holds, approvals) however old it is
+var retentionOrderStatuses = []domain.OrderStatus{
+	domain.OrderStatusDelivered,
+	domain.OrderStatusRejected,
+	domain.OrderStatusCancelled,
+}
+
+// PurgeResult is what one run purged from a table
+type PurgeResult struct {
+	Table  string
+	Before time.Time
+	Purged int64
+	// Complete is false when the run stopped at RETENTION_MAX_BATCHES with rows left to purge
+	Complete bool
+}
+
+// RetentionTableMetrics are the purge counters of one table since the process started
+type RetentionTableMetrics struct {
+	Table       string
+	PurgedTotal int64
+	Runs        int64
+	Failures    int64
+	LastRunAt   time.Time
+	LastPurged  int64
+}
+
+// retentionMetrics are shared by the background job and the admin endpoint
+var retentionMetrics = struct {
+	sync.Mutex
+	tables map[string]*RetentionTableMetrics
+}{tables: make(map[string]*RetentionTableMetrics)}
+
+// RetentionMetrics returns the purge counters by table, sorted by table name
+func RetentionMetrics() []RetentionTableMetrics {
+	retentionMetrics.Lock()
+	defer retentionMetrics.Unlock()
+
+	metrics := make([]RetentionTableMetrics, 0, len(retentionMetrics.tables))
+	for _, m := range retentionMetrics.tables {
+		metrics = append(metrics, *m)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Table < metrics[j].Table })
+	return metrics
+}
+
+func recordPurge(table string, purged int64, failed bool) {
+	retentionMetrics.Lock()
+	defer retentionMetrics.Unlock()
+
+	m, ok := retentionMetrics.tables[table]
+	if !ok {
+		m = &RetentionTableMetrics{Table: table}
+		retentionMetrics.tables[table] = m
+	}
+	m.Runs++
+	m.PurgedTotal += purged
+	m.LastPurged = purged
+	m.LastRunAt = time.Now()
+	if failed {
+		m.Failures++
+	}
+}
+
+type retentionService struct {
+	cfg     config.RetentionConfig
+	archive *archiveService
+	repos   *repository.Repositories
+	logger  *zap.Logger
+}
+
+// NewRetentionService creates a new data retention service. With archiving configured, order
+// events and audit logs are uploaded to object storage before they are deleted.
+func NewRetentionService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *retentionService {
+	s := &retentionService{
+		cfg:    cfg.Retention,
+		repos:  repos,
+		logger: logger,
+	}
+	if cfg.Archive.Enabled() {
+		s.archive = NewArchiveService(cfg.Archive, repos, logger)
+	}
+	return s
+}
+
+// retentionTable is a purgeable table: its retention period and a batched delete
+type retentionTable struct {
+	name   string
+	period time.Duration
+	delete func(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+func (s *retentionService) tables() []retentionTable {
+	tables := []retentionTable{
+		{RetentionTableIdempotencyKeys, s.cfg.IdempotencyKeys, s.repos.IdempotencyKey.DeleteCreatedBefore},
+		{RetentionTableOrderEvents, s.cfg.OrderEvents, func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			return s.repos.OrderEvent.DeleteCreatedBefore(ctx, before, retentionOrderStatuses, limit)
+		}},
+		{RetentionTableAuditLogs, s.cfg.AuditLogs, s.repos.AuditLog.DeleteCreatedBefore},
+	}
+	if s.archive != nil {
+		// Each batch becomes one archive file, deleted only once it is uploaded
+		tables[1].delete = func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			return s.archive.ArchiveOrderEvents(ctx, before, retentionOrderStatuses, limit)
+		}
+		tables[2].delete = s.archive.ArchiveAuditLogs
+	}
+	return tables
+}
+
+// Purge deletes rows older than each table's retention period, in batches of BatchSize and
+// at most MaxBatches per table. Tables with no retention period are skipped.
+// A failing table is logged and does not stop the others.
+func (s *retentionService) Purge(ctx context.Context) []PurgeResult {
+	var results []PurgeResult
+	for _, table := range s.tables() {
+		if table.period <= 0 {
+			continue
+		}
+
+		result := PurgeResult{Table: table.name, Before: time.Now().Add(-table.period)}
+		var err error
+		for batch := 0; batch < s.cfg.MaxBatches && ctx.Err() == nil; batch++ {
+			var deleted int64
+			deleted, err = table.delete(ctx, result.Before, s.cfg.BatchSize)
+			if err != nil {
+				break
+			}
+			result.Purged += deleted
+			if deleted < int64(s.cfg.BatchSize) {
+				result.Complete = true
+				break
+			}
+		}
+
+		recordPurge(table.name, result.Purged, err != nil)
+		if err != nil {
+			s.logger.Error("Retention purge failed",
+				zap.String("table", table.name),
+				zap.Int64("purged", result.Purged),
+				zap.Error(err),
+			)
+		} else if result.Purged > 0 {
+			s.logger.Info("Purged rows past retention",
+				zap.String("table", table.name),
+				zap.Int64("purged", result.Purged),
+				zap.Bool("complete", result.Complete),
+			)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// RunPurge periodically purges rows past their retention period. It returns when ctx is cancelled.
+func (s *retentionService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.Purge(ctx)
+	}
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Event types recorded when staff take or give up an order of the review queue
+const (
+	EventTypeReviewAssigned = "review_assigned"
+	EventTypeReviewReleased = "review_released"
+)
+
+// Reasons an order is in the review queue besides its flags, which are event types
+const (
+	ReviewReasonPendingConfirmation = "pending_confirmation"
+	ReviewReasonOnHold              = "on_hold"
+)
+
+// defaultReviewFlagEvents flag orders for review when REVIEW_QUEUE_FLAG_EVENTS is not set
+var defaultReviewFlagEvents = []string{
+	EventTypeDuplicateSuspected,
+	EventTypeTaxMismatch,
+	EventTypeShopifyTotalMismatch,
+	EventTypeShopifyItemsDiverged,
+}
+
+// reviewQueuePageSize is how many orders of a status one page of the queue reads
+const reviewQueuePageSize = 200
+
+// ReviewQueueItem is an order that needs staff attention
+type ReviewQueueItem struct {
+	Order *domain.SupplierOrder
+	// Reasons say why the order is queued: its status and the flag event types it has
+	Reasons []string
+	// Assignment is nil while nobody works the order
+	Assignment *domain.ReviewAssignment
+}
+
+// ReviewQueueFilter narrows the review queue to the orders of one assignee, or to the
+// orders nobody works
+type ReviewQueueFilter struct {
+	AssigneeID *uuid.UUID
+	Unassigned bool
+}
+
+type reviewQueueService struct {
+	cfg    config.ReviewQueueConfig
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewReviewQueueService creates a service for the admin review queue: orders pending
+// confirmation, on hold or flagged, and the staff working them
+func NewReviewQueueService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *reviewQueueService {
+	return &reviewQueueService{
+		cfg:    cfg.ReviewQueue,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// reviewable reports whether staff can still act on an order in the status
+func reviewable(status domain.OrderStatus) bool {
+	return status == domain.OrderStatusPendingConfirmation ||
+		status == domain.OrderStatusConfirmed ||
+		status == domain.OrderStatusOnHold
+}
+
+// List returns the orders needing attention, oldest first: every order pending confirmation
+// or on hold, and open orders with a flag event within the flag window
+func (s *reviewQueueService) List(ctx context.Context, filter ReviewQueueFilter, now time.Time) ([]*ReviewQueueItem, error) {
+	items := make(map[uuid.UUID]*ReviewQueueItem)
+	for _, queued := range []struct {
+		status domain.OrderStatus
+		reason string
+	}{
+		{domain.OrderStatusPendingConfirmation, ReviewReasonPendingConfirmation},
+		{domain.OrderStatusOnHold, ReviewReasonOnHold},
+	} {
+		var after *domain.OrderCursor
+		for {
+			orders, err := s.repos.SupplierOrder.ListByStatusAfter(ctx, queued.status, after, reviewQueuePageSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, order := range orders {
+				items[order.ID] = &ReviewQueueItem{Order: order, Reasons: []string{queued.reason}}
+			}
+			if len(orders) < reviewQueuePageSize {
+				break
+			}
+			after = domain.CursorAfter(orders[len(orders)-1])
+		}
+	}
+
+	flagEvents := s.cfg.FlagEvents
+	if len(flagEvents) == 0 {
+		flagEvents = defaultReviewFlagEvents
+	}
+	for _, eventType := range flagEvents {
+		events, err := s.repos.OrderEvent.ListLatestByType(ctx, eventType, now.Add(-s.cfg.FlagWindow), now.Add(time.Second))
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			item, ok := items[event.SupplierOrderID]
+			if !ok {
+				order, err := s.repos.SupplierOrder.GetByID(ctx, event.SupplierOrderID)
+				if err != nil {
+					if _, ok := err.(*errors.ErrNotFound); ok {
+						continue
+					}
+					return nil, err
+				}
+				if !reviewable(order.Status) {
+					continue
+				}
+				item = &ReviewQueueItem{Order: order}
+				items[order.ID] = item
+			}
+			item.Reasons = append(item.Reasons, eventType)
+		}
+	}
+
+	orderIDs := make([]uuid.UUID, 0, len(items))
+	for id := range items {
+		orderIDs = append(orderIDs, id)
+	}
+	assignments, err := s.repos.ReviewAssignment.ListByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		items[assignment.SupplierOrderID].Assignment = assignment
+	}
+
+	queue := make([]*ReviewQueueItem, 0, len(items))
+	for _, item := range items {
+		switch {
+		case filter.Unassigned && item.Assignment != nil:
+			continue
+		case filter.AssigneeID != nil && (item.Assignment == nil || item.Assignment.AssigneeID != *filter.AssigneeID):
+			continue
+		}
+		queue = append(queue, item)
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		if !queue[i].Order.CreatedAt.Equal(queue[j].Order.CreatedAt) {
+			return queue[i].Order.CreatedAt.Before(queue[j].Order.CreatedAt)
+		}
+		return queue[i].Order.ID.String() < queue[j].Order.ID.String()
+	})
+	return queue, nil
+}
+
+// Claim assigns the order to the staff member working it. It fails with ErrConflict while
+// someone else holds the order; claiming an order they already hold changes nothing.
+func (s *reviewQueueService) Claim(ctx context.Context, orderID, staffID uuid.UUID) (*domain.ReviewAssignment, error) {
+	if _, err := s.reviewableOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	assignment := &domain.ReviewAssignment{SupplierOrderID: orderID, AssigneeID: staffID, AssignedBy: staffID}
+	held, err := s.repos.ReviewAssignment.GetByOrderID(ctx, orderID)
+	if err == nil && held.AssigneeID == staffID {
+		return held, nil
+	}
+	if _, ok := err.(*errors.ErrNotFound); err != nil && !ok {
+		return nil, err
+	}
+
+	claimed, err := s.repos.ReviewAssignment.Claim(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, s.heldByOther(ctx, orderID)
+	}
+
+	s.recordEvent(ctx, orderID, EventTypeReviewAssigned, map[string]interface{}{
+		"assignee_id": staffID.String(),
+		"assigned_by": staffID.String(),
+	})
+	return assignment, nil
+}
+
+// Assign hands the order to a staff member, taking it from whoever held it
+func (s *reviewQueueService) Assign(ctx context.Context, orderID, assigneeID, adminID uuid.UUID) (*domain.ReviewAssignment, error) {
+	if _, err := s.reviewableOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	assignee, err := s.repos.Partner.GetByID(ctx, assigneeID)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); ok {
+			return nil, &errors.ErrValidation{
+				Message: "validation failed",
+				Fields:  map[string]string{"assignee_id": "partner not found"},
+			}
+		}
+		return nil, err
+	}
+	if !assignee.IsActive {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"assignee_id": "partner is inactive"},
+		}
+	}
+
+	assignment := &domain.ReviewAssignment{SupplierOrderID: orderID, AssigneeID: assigneeID, AssignedBy: adminID}
+	if err := s.repos.ReviewAssignment.Assign(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, orderID, EventTypeReviewAssigned, map[string]interface{}{
+		"assignee_id": assigneeID.String(),
+		"assigned_by": adminID.String(),
+	})
+	return assignment, nil
+}
+
+// Release gives up the staff member's claim on the order. It fails with ErrConflict when
+// someone else holds the order and ErrNotFound when nobody does.
+func (s *reviewQueueService) Release(ctx context.Context, orderID, staffID uuid.UUID) error {
+	released, err := s.repos.ReviewAssignment.Release(ctx, orderID, staffID)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return s.heldByOther(ctx, orderID)
+	}
+
+	s.recordEvent(ctx, orderID, EventTypeReviewReleased, map[string]interface{}{
+		"released_by": staffID.String(),
+	})
+	return nil
+}
+
+// reviewableOrder returns the order, or ErrConflict once it is past review
+func (s *reviewQueueService) reviewableOrder(ctx context.Context, orderID uuid.UUID) (*domain.SupplierOrder, error) {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewable(order.Status) {
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("order is %s and no longer needs review", order.Status)}
+	}
+	return order, nil
+}
+
+// heldByOther is the error for an order the caller does not hold: ErrConflict naming the
+// assignee, or ErrNotFound when the order is not assigned
+func (s *reviewQueueService) heldByOther(ctx context.Context, orderID uuid.UUID) error {
+	held, err := s.repos.ReviewAssignment.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	assignee := held.AssigneeID.String()
+	if partner, err := s.repos.Partner.GetByID(ctx, held.AssigneeID); err == nil {
+		assignee = partner.Name
+	}
+	return &errors.ErrConflict{Message: fmt.Sprintf("order is assigned to %s", assignee)}
+}
+
+// recordEvent adds the assignment change to the order timeline; a failure is only logged,
+// as the assignment itself succeeded
+func (s *reviewQueueService) recordEvent(ctx context.Context, orderID uuid.UUID, eventType string, data map[string]interface{}) {
+	event := &domain.OrderEvent{
+		SupplierOrderID: orderID,
+		EventType:       eventType,
+		EventData:       data,
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		s.logger.Warn("Failed to record review assignment event", zap.String("order_id", orderID.String()), zap.Error(err))
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// reviewOrders lists orders by status and looks them up by ID
+type reviewOrders struct {
+	repository.SupplierOrderRepository
+	orders []*domain.SupplierOrder
+}
+
+func (f *reviewOrders) ListByStatusAfter(ctx context.Context, status domain.OrderStatus, after *domain.OrderCursor, limit int) ([]*domain.SupplierOrder, error) {
+	var orders []*domain.SupplierOrder
+	for _, order := range f.orders {
+		if order.Status == status {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (f *reviewOrders) GetByID(ctx context.Context, id uuid.UUID) (*domain.SupplierOrder, error) {
+	for _, order := range f.orders {
+		if order.ID == id {
+			return order, nil
+		}
+	}
+	return nil, &errors.ErrNotFound{Resource: "supplier_order", ID: id.String()}
+}
+
+// reviewEvents returns flag events by type and records the rest
+type reviewEvents struct {
+	repository.OrderEventRepository
+	flags    map[string][]uuid.UUID
+	recorded []*domain.OrderEvent
+}
+
+func (f *reviewEvents) ListLatestByType(ctx context.Context, eventType string, from, to time.Time) ([]*domain.OrderEvent, error) {
+	var events []*domain.OrderEvent
+	for _, id := range f.flags[eventType] {
+		events = append(events, &domain.OrderEvent{SupplierOrderID: id, EventType: eventType})
+	}
+	return events, nil
+}
+
+func (f *reviewEvents) Create(ctx context.Context, event *domain.OrderEvent) error {
+	f.recorded = append(f.recorded, event)
+	return nil
+}
+
+// memAssignments keeps review assignments in memory
+type memAssignments struct {
+	assignments map[uuid.UUID]*domain.ReviewAssignment
+}
+
+func (m *memAssignments) Claim(ctx context.Context, assignment *domain.ReviewAssignment) (bool, error) {
+	if held, ok := m.assignments[assignment.SupplierOrderID]; ok {
+		return held.AssigneeID == assignment.AssigneeID, nil
+	}
+	m.assignments[assignment.SupplierOrderID] = assignment
+	return true, nil
+}
+
+func (m *memAssignments) Assign(ctx context.Context, assignment *domain.ReviewAssignment) error {
+	m.assignments[assignment.SupplierOrderID] = assignment
+	return nil
+}
+
+func (m *memAssignments) Release(ctx context.Context, orderID, assigneeID uuid.UUID) (bool, error) {
+	if held, ok := m.assignments[orderID]; ok && held.AssigneeID == assigneeID {
+		delete(m.assignments, orderID)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *memAssignments) GetByOrderID(ctx context.Context, orderID uuid.UUID) (*domain.ReviewAssignment, error) {
+	if held, ok := m.assignments[orderID]; ok {
+		return held, nil
+	}
+	return nil, &errors.ErrNotFound{Resource: "review_assignment", ID: orderID.String()}
+}
+
+func (m *memAssignments) ListByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*domain.ReviewAssignment, error) {
+	var assignments []*domain.ReviewAssignment
+	for _, id := range orderIDs {
+		if held, ok := m.assignments[id]; ok {
+			assignments = append(assignments, held)
+		}
+	}
+	return assignments, nil
+}
+
+func TestReviewQueueList(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	pending := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusPendingConfirmation, CreatedAt: now.Add(-time.Hour)}
+	held := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusOnHold, CreatedAt: now.Add(-3 * time.Hour)}
+	flagged := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusConfirmed, CreatedAt: now.Add(-2 * time.Hour)}
+	shipped := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusShipped, CreatedAt: now.Add(-4 * time.Hour)}
+	staff := uuid.New()
+	assignments := &memAssignments{assignments: map[uuid.UUID]*domain.ReviewAssignment{
+		held.ID: {SupplierOrderID: held.ID, AssigneeID: staff, AssignedBy: staff},
+	}}
+	s := &reviewQueueService{
+		cfg: config.ReviewQueueConfig{FlagWindow: 24 * time.Hour},
+		repos: &repository.Repositories{
+			SupplierOrder: &reviewOrders{orders: []*domain.SupplierOrder{pending, held, flagged, shipped}},
+			OrderEvent: &reviewEvents{flags: map[string][]uuid.UUID{
+				EventTypeTaxMismatch:        {pending.ID, flagged.ID},
+				EventTypeDuplicateSuspected: {shipped.ID},
+			}},
+			ReviewAssignment: assignments,
+		},
+		logger: zap.NewNop(),
+	}
+
+	queue, err := s.List(context.Background(), ReviewQueueFilter{}, now)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []struct {
+		order   *domain.SupplierOrder
+		reasons string
+	}{
+		{held, ReviewReasonOnHold},
+		{flagged, EventTypeTaxMismatch},
+		{pending, ReviewReasonPendingConfirmation + "," + EventTypeTaxMismatch},
+	}
+	if len(queue) != len(want) {
+		t.Fatalf("queue has %d orders, want %d (shipped orders are past review)", len(queue), len(want))
+	}
+	for i, w := range want {
+		if queue[i].Order != w.order || strings.Join(queue[i].Reasons, ",") != w.reasons {
+			t.Errorf("queue[%d] = %s %v, want %s %s", i, queue[i].Order.Status, queue[i].Reasons, w.order.Status, w.reasons)
+		}
+	}
+	if queue[0].Assignment == nil || queue[0].Assignment.AssigneeID != staff {
+		t.Errorf("held order assignment = %+v, want %s", queue[0].Assignment, staff)
+	}
+
+	mine, _ := s.List(context.Background(), ReviewQueueFilter{AssigneeID: &staff}, now)
+	if len(mine) != 1 || mine[0].Order != held {
+		t.Errorf("assignee filter returned %d orders, want the held one", len(mine))
+	}
+	unassigned, _ := s.List(context.Background(), ReviewQueueFilter{Unassigned: true}, now)
+	if len(unassigned) != 2 {
+		t.Errorf("unassigned filter returned %d orders, want 2", len(unassigned))
+	}
+}
+
+func TestReviewQueueClaimAndRelease(t *testing.T) {
+	order := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusPendingConfirmation}
+	delivered := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusDelivered}
+	alice := &domain.Partner{ID: uuid.New(), Name: "Alice", IsActive: true}
+	bob := uuid.New()
+	events := &reviewEvents{}
+	s := &reviewQueueService{
+		repos: &repository.Repositories{
+			Partner:          &batchPartners{partner: alice},
+			SupplierOrder:    &reviewOrders{orders: []*domain.SupplierOrder{order, delivered}},
+			OrderEvent:       events,
+			ReviewAssignment: &memAssignments{assignments: map[uuid.UUID]*domain.ReviewAssignment{}},
+		},
+		logger: zap.NewNop(),
+	}
+	ctx := context.Background()
+
+	if _, err := s.Claim(ctx, order.ID, alice.ID); err != nil {
+		t.Fatalf("Claim() error = %v", err)
+	}
+	if _, err := s.Claim(ctx, order.ID, alice.ID); err != nil {
+		t.Errorf("claiming an order again error = %v", err)
+	}
+	if len(events.recorded) != 1 || events.recorded[0].EventType != EventTypeReviewAssigned {
+		t.Errorf("recorded %d events, want one review_assigned", len(events.recorded))
+	}
+
+	_, err := s.Claim(ctx, order.ID, bob)
+	if _, ok := err.(*errors.ErrConflict); !ok || !strings.Contains(err.Error(), "Alice") {
+		t.Errorf("claim of a held order error = %v, want a conflict naming Alice", err)
+	}
+	if err := s.Release(ctx, order.ID, bob); err == nil {
+		t.Error("Release() by someone else succeeded")
+	}
+	if err := s.Release(ctx, order.ID, alice.ID); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := s.Claim(ctx, order.ID, bob); err != nil {
+		t.Errorf("claim of a released order error = %v", err)
+	}
+
+	if _, err := s.Claim(ctx, delivered.ID, bob); err == nil {
+		t.Error("Claim() of a delivered order succeeded")
+	}
+}
+
+func TestReviewQueueAssign(t *testing.T) {
+	order := &domain.SupplierOrder{ID: uuid.New(), Status: domain.OrderStatusOnHold}
+	alice := &domain.Partner{ID: uuid.New(), Name: "Alice", IsActive: true}
+	admin, bob := uuid.New(), uuid.New()
+	assignments := &memAssignments{assignments: map[uuid.UUID]*domain.ReviewAssignment{
+		order.ID: {SupplierOrderID: order.ID, AssigneeID: bob, AssignedBy: bob},
+	}}
+	s := &reviewQueueService{
+		repos: &repository.Repositories{
+			Partner:          &batchPartners{partner: alice},
+			SupplierOrder:    &reviewOrders{orders: []*domain.SupplierOrder{order}},
+			OrderEvent:       &reviewEvents{},
+			ReviewAssignment: assignments,
+		},
+		logger: zap.NewNop(),
+	}
+
+	if _, err := s.Assign(context.Background(), order.ID, alice.ID, admin); err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if held := assignments.assignments[order.ID]; held.AssigneeID != alice.ID || held.AssignedBy != admin {
+		t.Errorf("assignment = %+v, want Alice assigned by the admin", held)
+	}
+
+	_, err := s.Assign(context.Background(), order.ID, uuid.New(), admin)
+	if _, ok := err.(*errors.ErrValidation); !ok {
+		t.Errorf("Assign() to an unknown partner error = %v, want a validation error", err)
+	}
+}
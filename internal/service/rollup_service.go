@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// utcDay returns the UTC midnight starting t's day
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+type rollupService struct {
+	cfg    config.RollupsConfig
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewRollupService creates a new daily order rollup service
+func NewRollupService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *rollupService {
+	return &rollupService{
+		cfg:    cfg.Rollups,
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// Rebuild recomputes the rollups of every UTC day from from's day up to (not including)
+// to's day, one day at a time so no statement runs for long. report, if set, is called
+// after each day with the rollup rows written.
+func (s *rollupService) Rebuild(ctx context.Context, from, to time.Time, report func(day time.Time, rows int64)) error {
+	for day := utcDay(from); day.Before(utcDay(to)); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := s.repos.OrderRollup.Rebuild(ctx, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return err
+		}
+		if report != nil {
+			report(day, rows)
+		}
+	}
+	return nil
+}
+
+// RollUpRecent rebuilds the finished days within the lookback period. Today is left to the
+// live queries until it is over.
+func (s *rollupService) RollUpRecent(ctx context.Context, now time.Time) error {
+	today := utcDay(now)
+	from := utcDay(today.Add(-s.cfg.Lookback))
+	var rows int64
+	err := s.Rebuild(ctx, from, today, func(_ time.Time, n int64) { rows += n })
+	if err != nil {
+		s.logger.Error("Order rollup failed", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Rolled up orders",
+		zap.String("from", from.Format("2006-01-02")),
+		zap.String("to", today.Format("2006-01-02")),
+		zap.Int64("rows", rows),
+	)
+	return nil
+}
+
+// RunRollups periodically rolls up recent days. It returns when ctx is cancelled.
+func (s *rollupService) RunRollups(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.RollUpRecent(ctx, now)
+		}
+	}
+}
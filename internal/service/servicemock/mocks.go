@@ -0,0 +1,688 @@
+// Code generated by servicemock/gen from services.go. DO NOT EDIT.
+
+package servicemock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/service"
+)
+
+var (
+	_ service.OrderService         = (*OrderService)(nil)
+	_ service.CartService          = (*CartService)(nil)
+	_ service.SKUService           = (*SKUService)(nil)
+	_ service.ShopifyService       = (*ShopifyService)(nil)
+	_ service.WebhookService       = (*WebhookService)(nil)
+	_ service.DuplicateService     = (*DuplicateService)(nil)
+	_ service.ApprovalService      = (*ApprovalService)(nil)
+	_ service.ShippingService      = (*ShippingService)(nil)
+	_ service.OrderLookupService   = (*OrderLookupService)(nil)
+	_ service.SubstitutionService  = (*SubstitutionService)(nil)
+	_ service.OnboardingService    = (*OnboardingService)(nil)
+	_ service.CreditService        = (*CreditService)(nil)
+	_ service.CreditAlertService   = (*CreditAlertService)(nil)
+	_ service.ReviewQueueService   = (*ReviewQueueService)(nil)
+	_ service.PartnerEventService  = (*PartnerEventService)(nil)
+	_ service.OrderEditSyncService = (*OrderEditSyncService)(nil)
+	_ service.TotalCheckService    = (*TotalCheckService)(nil)
+	_ service.OrderDiffService     = (*OrderDiffService)(nil)
+)
+
+// OrderService mocks service.OrderService
+type OrderService struct {
+	recorder
+
+	CreateOrderFromCartFunc func(ctx context.Context, partnerID uuid.UUID, req service.CartSubmitRequest, supplierItems map[string]*service.CartItemMatch, referencePrefix string) (*domain.SupplierOrder, error)
+	ConfirmOrderFunc        func(ctx context.Context, orderID uuid.UUID) error
+	RejectOrderFunc         func(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) error
+	PlanRejectOrderFunc     func(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) (*service.TransitionPlan, error)
+	RejectOrdersFunc        func(ctx context.Context, orderIDs []uuid.UUID, code domain.RejectionCode, reason string) ([]service.BulkRejectResult, error)
+	ShipOrderFunc           func(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error
+	DeliverOrderFunc        func(ctx context.Context, orderID uuid.UUID, deliveredAt time.Time, source string, proofOfDelivery map[string]interface{}) error
+	HoldOrderFunc           func(ctx context.Context, orderID uuid.UUID, reason string) error
+	ReleaseOrderFunc        func(ctx context.Context, orderID uuid.UUID) error
+	ReassignOrderFunc       func(ctx context.Context, orderID, toPartnerID uuid.UUID, reason string, adminID uuid.UUID) (*domain.SupplierOrder, error)
+}
+
+// NewOrderService creates a new OrderService mock
+func NewOrderService(t testing.TB) *OrderService {
+	return &OrderService{recorder: recorder{t: t}}
+}
+
+func (m *OrderService) CreateOrderFromCart(ctx context.Context, partnerID uuid.UUID, req service.CartSubmitRequest, supplierItems map[string]*service.CartItemMatch, referencePrefix string) (*domain.SupplierOrder, error) {
+	m.record("CreateOrderFromCart", m.CreateOrderFromCartFunc != nil, partnerID, req, supplierItems, referencePrefix)
+	return m.CreateOrderFromCartFunc(ctx, partnerID, req, supplierItems, referencePrefix)
+}
+
+func (m *OrderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) error {
+	m.record("ConfirmOrder", m.ConfirmOrderFunc != nil, orderID)
+	return m.ConfirmOrderFunc(ctx, orderID)
+}
+
+func (m *OrderService) RejectOrder(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) error {
+	m.record("RejectOrder", m.RejectOrderFunc != nil, orderID, code, reason)
+	return m.RejectOrderFunc(ctx, orderID, code, reason)
+}
+
+func (m *OrderService) PlanRejectOrder(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) (*service.TransitionPlan, error) {
+	m.record("PlanRejectOrder", m.PlanRejectOrderFunc != nil, orderID, code, reason)
+	return m.PlanRejectOrderFunc(ctx, orderID, code, reason)
+}
+
+func (m *OrderService) RejectOrders(ctx context.Context, orderIDs []uuid.UUID, code domain.RejectionCode, reason string) ([]service.BulkRejectResult, error) {
+	m.record("RejectOrders", m.RejectOrdersFunc != nil, orderIDs, code, reason)
+	return m.RejectOrdersFunc(ctx, orderIDs, code, reason)
+}
+
+func (m *OrderService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error {
+	m.record("ShipOrder", m.ShipOrderFunc != nil, orderID, carrier, trackingNumber, trackingURL)
+	return m.ShipOrderFunc(ctx, orderID, carrier, trackingNumber, trackingURL)
+}
+
+func (m *OrderService) DeliverOrder(ctx context.Context, orderID uuid.UUID, deliveredAt time.Time, source string, proofOfDelivery map[string]interface{}) error {
+	m.record("DeliverOrder", m.DeliverOrderFunc != nil, orderID, deliveredAt, source, proofOfDelivery)
+	return m.DeliverOrderFunc(ctx, orderID, deliveredAt, source, proofOfDelivery)
+}
+
+func (m *OrderService) HoldOrder(ctx context.Context, orderID uuid.UUID, reason string) error {
+	m.record("HoldOrder", m.HoldOrderFunc != nil, orderID, reason)
+	return m.HoldOrderFunc(ctx, orderID, reason)
+}
+
+func (m *OrderService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	m.record("ReleaseOrder", m.ReleaseOrderFunc != nil, orderID)
+	return m.ReleaseOrderFunc(ctx, orderID)
+}
+
+func (m *OrderService) ReassignOrder(ctx context.Context, orderID, toPartnerID uuid.UUID, reason string, adminID uuid.UUID) (*domain.SupplierOrder, error) {
+	m.record("ReassignOrder", m.ReassignOrderFunc != nil, orderID, toPartnerID, reason, adminID)
+	return m.ReassignOrderFunc(ctx, orderID, toPartnerID, reason, adminID)
+}
+
+// CartService mocks service.CartService
+type CartService struct {
+	recorder
+
+	SubmitCartFunc               func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error)
+	ValidateCartFunc             func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*service.CartValidationResult, error)
+	CheckDuplicateSubmissionFunc func(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, error)
+	ReleaseOrderFunc             func(ctx context.Context, orderID uuid.UUID) error
+}
+
+// NewCartService creates a new CartService mock
+func NewCartService(t testing.TB) *CartService {
+	return &CartService{recorder: recorder{t: t}}
+}
+
+func (m *CartService) SubmitCart(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, bool, error) {
+	m.record("SubmitCart", m.SubmitCartFunc != nil, partner, req)
+	return m.SubmitCartFunc(ctx, partner, req)
+}
+
+func (m *CartService) ValidateCart(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*service.CartValidationResult, error) {
+	m.record("ValidateCart", m.ValidateCartFunc != nil, partner, req)
+	return m.ValidateCartFunc(ctx, partner, req)
+}
+
+func (m *CartService) CheckDuplicateSubmission(ctx context.Context, partner *domain.Partner, req service.CartSubmitRequest) (*domain.SupplierOrder, error) {
+	m.record("CheckDuplicateSubmission", m.CheckDuplicateSubmissionFunc != nil, partner, req)
+	return m.CheckDuplicateSubmissionFunc(ctx, partner, req)
+}
+
+func (m *CartService) ReleaseOrder(ctx context.Context, orderID uuid.UUID) error {
+	m.record("ReleaseOrder", m.ReleaseOrderFunc != nil, orderID)
+	return m.ReleaseOrderFunc(ctx, orderID)
+}
+
+// SKUService mocks service.SKUService
+type SKUService struct {
+	recorder
+
+	CheckCartForSupplierSKUsFunc func(ctx context.Context, partnerID uuid.UUID, items []service.CartItem, restrictionMode string) (bool, map[string]*service.CartItemMatch, error)
+	MatchCartItemsFunc           func(ctx context.Context, partnerID uuid.UUID, items []service.CartItem) ([]service.CartItemMatch, error)
+	MissedMatchesFunc            func(ctx context.Context, from, to time.Time) ([]service.MissedSKUMatch, error)
+}
+
+// NewSKUService creates a new SKUService mock
+func NewSKUService(t testing.TB) *SKUService {
+	return &SKUService{recorder: recorder{t: t}}
+}
+
+func (m *SKUService) CheckCartForSupplierSKUs(ctx context.Context, partnerID uuid.UUID, items []service.CartItem, restrictionMode string) (bool, map[string]*service.CartItemMatch, error) {
+	m.record("CheckCartForSupplierSKUs", m.CheckCartForSupplierSKUsFunc != nil, partnerID, items, restrictionMode)
+	return m.CheckCartForSupplierSKUsFunc(ctx, partnerID, items, restrictionMode)
+}
+
+func (m *SKUService) MatchCartItems(ctx context.Context, partnerID uuid.UUID, items []service.CartItem) ([]service.CartItemMatch, error) {
+	m.record("MatchCartItems", m.MatchCartItemsFunc != nil, partnerID, items)
+	return m.MatchCartItemsFunc(ctx, partnerID, items)
+}
+
+func (m *SKUService) MissedMatches(ctx context.Context, from, to time.Time) ([]service.MissedSKUMatch, error) {
+	m.record("MissedMatches", m.MissedMatchesFunc != nil, from, to)
+	return m.MissedMatchesFunc(ctx, from, to)
+}
+
+// ShopifyService mocks service.ShopifyService
+type ShopifyService struct {
+	recorder
+
+	CreateDraftOrderFunc          func(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, partnerName string) (int64, string, error)
+	UpdateDraftOrderLineItemsFunc func(ctx context.Context, draftOrderID int64, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error
+	FindDraftOrderFunc            func(ctx context.Context, order *domain.SupplierOrder, partnerName string) (int64, string, error)
+	DeleteDraftOrderFunc          func(ctx context.Context, draftOrderID int64) error
+	CompleteDraftOrderFunc        func(ctx context.Context, draftOrderID int64) (int64, string, error)
+	SendDraftOrderInvoiceFunc     func(ctx context.Context, draftOrderID int64, email *string) (string, error)
+	CompleteOrderFunc             func(ctx context.Context, order *domain.SupplierOrder) error
+	FindOrCreateCustomerFunc      func(ctx context.Context, order *domain.SupplierOrder) (int64, error)
+	SetOrderLinkageMetafieldsFunc func(ctx context.Context, shopifyOrderID int64, order *domain.SupplierOrder) error
+	GetOrderLinkageMetafieldsFunc func(ctx context.Context, shopifyOrderID int64) (map[string]string, error)
+	GetOrderLineItemsFunc         func(ctx context.Context, shopifyOrderID int64) ([]service.ShopifyLineItem, bool, error)
+	GetOrderTotalFunc             func(ctx context.Context, shopifyOrderID int64) (float64, string, error)
+	GetOrderSnapshotFunc          func(ctx context.Context, shopifyOrderID int64) (*service.ShopifyOrderSnapshot, error)
+	GetVariantsFunc               func(ctx context.Context, variantIDs []int64) (map[int64]*service.ShopifyVariant, error)
+}
+
+// NewShopifyService creates a new ShopifyService mock
+func NewShopifyService(t testing.TB) *ShopifyService {
+	return &ShopifyService{recorder: recorder{t: t}}
+}
+
+func (m *ShopifyService) CreateDraftOrder(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, partnerName string) (int64, string, error) {
+	m.record("CreateDraftOrder", m.CreateDraftOrderFunc != nil, order, items, partnerName)
+	return m.CreateDraftOrderFunc(ctx, order, items, partnerName)
+}
+
+func (m *ShopifyService) UpdateDraftOrderLineItems(ctx context.Context, draftOrderID int64, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error {
+	m.record("UpdateDraftOrderLineItems", m.UpdateDraftOrderLineItemsFunc != nil, draftOrderID, order, items)
+	return m.UpdateDraftOrderLineItemsFunc(ctx, draftOrderID, order, items)
+}
+
+func (m *ShopifyService) FindDraftOrder(ctx context.Context, order *domain.SupplierOrder, partnerName string) (int64, string, error) {
+	m.record("FindDraftOrder", m.FindDraftOrderFunc != nil, order, partnerName)
+	return m.FindDraftOrderFunc(ctx, order, partnerName)
+}
+
+func (m *ShopifyService) DeleteDraftOrder(ctx context.Context, draftOrderID int64) error {
+	m.record("DeleteDraftOrder", m.DeleteDraftOrderFunc != nil, draftOrderID)
+	return m.DeleteDraftOrderFunc(ctx, draftOrderID)
+}
+
+func (m *ShopifyService) CompleteDraftOrder(ctx context.Context, draftOrderID int64) (int64, string, error) {
+	m.record("CompleteDraftOrder", m.CompleteDraftOrderFunc != nil, draftOrderID)
+	return m.CompleteDraftOrderFunc(ctx, draftOrderID)
+}
+
+func (m *ShopifyService) SendDraftOrderInvoice(ctx context.Context, draftOrderID int64, email *string) (string, error) {
+	m.record("SendDraftOrderInvoice", m.SendDraftOrderInvoiceFunc != nil, draftOrderID, email)
+	return m.SendDraftOrderInvoiceFunc(ctx, draftOrderID, email)
+}
+
+func (m *ShopifyService) CompleteOrder(ctx context.Context, order *domain.SupplierOrder) error {
+	m.record("CompleteOrder", m.CompleteOrderFunc != nil, order)
+	return m.CompleteOrderFunc(ctx, order)
+}
+
+func (m *ShopifyService) FindOrCreateCustomer(ctx context.Context, order *domain.SupplierOrder) (int64, error) {
+	m.record("FindOrCreateCustomer", m.FindOrCreateCustomerFunc != nil, order)
+	return m.FindOrCreateCustomerFunc(ctx, order)
+}
+
+func (m *ShopifyService) SetOrderLinkageMetafields(ctx context.Context, shopifyOrderID int64, order *domain.SupplierOrder) error {
+	m.record("SetOrderLinkageMetafields", m.SetOrderLinkageMetafieldsFunc != nil, shopifyOrderID, order)
+	return m.SetOrderLinkageMetafieldsFunc(ctx, shopifyOrderID, order)
+}
+
+func (m *ShopifyService) GetOrderLinkageMetafields(ctx context.Context, shopifyOrderID int64) (map[string]string, error) {
+	m.record("GetOrderLinkageMetafields", m.GetOrderLinkageMetafieldsFunc != nil, shopifyOrderID)
+	return m.GetOrderLinkageMetafieldsFunc(ctx, shopifyOrderID)
+}
+
+func (m *ShopifyService) GetOrderLineItems(ctx context.Context, shopifyOrderID int64) ([]service.ShopifyLineItem, bool, error) {
+	m.record("GetOrderLineItems", m.GetOrderLineItemsFunc != nil, shopifyOrderID)
+	return m.GetOrderLineItemsFunc(ctx, shopifyOrderID)
+}
+
+func (m *ShopifyService) GetOrderTotal(ctx context.Context, shopifyOrderID int64) (float64, string, error) {
+	m.record("GetOrderTotal", m.GetOrderTotalFunc != nil, shopifyOrderID)
+	return m.GetOrderTotalFunc(ctx, shopifyOrderID)
+}
+
+func (m *ShopifyService) GetOrderSnapshot(ctx context.Context, shopifyOrderID int64) (*service.ShopifyOrderSnapshot, error) {
+	m.record("GetOrderSnapshot", m.GetOrderSnapshotFunc != nil, shopifyOrderID)
+	return m.GetOrderSnapshotFunc(ctx, shopifyOrderID)
+}
+
+func (m *ShopifyService) GetVariants(ctx context.Context, variantIDs []int64) (map[int64]*service.ShopifyVariant, error) {
+	m.record("GetVariants", m.GetVariantsFunc != nil, variantIDs)
+	return m.GetVariantsFunc(ctx, variantIDs)
+}
+
+// WebhookService mocks service.WebhookService. Methods without results do nothing
+// unless their Func is set, since most actions call them on the side.
+type WebhookService struct {
+	recorder
+
+	NotifyOrderEventFunc    func(order *domain.SupplierOrder, eventType string, data map[string]interface{})
+	NotifyPartnerEventFunc  func(partner *domain.Partner, eventType string, data map[string]interface{})
+	DeliverOrderEventFunc   func(ctx context.Context, order *domain.SupplierOrder, eventType string, data map[string]interface{}) (*domain.WebhookDelivery, error)
+	UpdateSubscriptionsFunc func(ctx context.Context, partner *domain.Partner, eventTypes []string) ([]string, error)
+	RetryDeliveryFunc       func(ctx context.Context, partner *domain.Partner, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+}
+
+// NewWebhookService creates a new WebhookService mock
+func NewWebhookService(t testing.TB) *WebhookService {
+	return &WebhookService{recorder: recorder{t: t}}
+}
+
+func (m *WebhookService) NotifyOrderEvent(order *domain.SupplierOrder, eventType string, data map[string]interface{}) {
+	m.record("NotifyOrderEvent", true, order, eventType, data)
+	if m.NotifyOrderEventFunc != nil {
+		m.NotifyOrderEventFunc(order, eventType, data)
+	}
+}
+
+func (m *WebhookService) NotifyPartnerEvent(partner *domain.Partner, eventType string, data map[string]interface{}) {
+	m.record("NotifyPartnerEvent", true, partner, eventType, data)
+	if m.NotifyPartnerEventFunc != nil {
+		m.NotifyPartnerEventFunc(partner, eventType, data)
+	}
+}
+
+func (m *WebhookService) DeliverOrderEvent(ctx context.Context, order *domain.SupplierOrder, eventType string, data map[string]interface{}) (*domain.WebhookDelivery, error) {
+	m.record("DeliverOrderEvent", m.DeliverOrderEventFunc != nil, order, eventType, data)
+	return m.DeliverOrderEventFunc(ctx, order, eventType, data)
+}
+
+func (m *WebhookService) UpdateSubscriptions(ctx context.Context, partner *domain.Partner, eventTypes []string) ([]string, error) {
+	m.record("UpdateSubscriptions", m.UpdateSubscriptionsFunc != nil, partner, eventTypes)
+	return m.UpdateSubscriptionsFunc(ctx, partner, eventTypes)
+}
+
+func (m *WebhookService) RetryDelivery(ctx context.Context, partner *domain.Partner, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	m.record("RetryDelivery", m.RetryDeliveryFunc != nil, partner, deliveryID)
+	return m.RetryDeliveryFunc(ctx, partner, deliveryID)
+}
+
+// DuplicateService mocks service.DuplicateService
+type DuplicateService struct {
+	recorder
+
+	FindDuplicatesFunc func(ctx context.Context, window time.Duration) ([]service.DuplicateCluster, error)
+}
+
+// NewDuplicateService creates a new DuplicateService mock
+func NewDuplicateService(t testing.TB) *DuplicateService {
+	return &DuplicateService{recorder: recorder{t: t}}
+}
+
+func (m *DuplicateService) FindDuplicates(ctx context.Context, window time.Duration) ([]service.DuplicateCluster, error) {
+	m.record("FindDuplicates", m.FindDuplicatesFunc != nil, window)
+	return m.FindDuplicatesFunc(ctx, window)
+}
+
+// ApprovalService mocks service.ApprovalService
+type ApprovalService struct {
+	recorder
+
+	RequiresApprovalFunc func(total float64) bool
+	RequireFunc          func(ctx context.Context, order *domain.SupplierOrder) error
+	StatusFunc           func(ctx context.Context, orderID uuid.UUID) (*service.ApprovalStatus, error)
+	ApproveFunc          func(ctx context.Context, orderID, adminID uuid.UUID) (*service.ApprovalStatus, error)
+	PlanApproveFunc      func(ctx context.Context, orderID, adminID uuid.UUID) (*service.TransitionPlan, error)
+}
+
+// NewApprovalService creates a new ApprovalService mock
+func NewApprovalService(t testing.TB) *ApprovalService {
+	return &ApprovalService{recorder: recorder{t: t}}
+}
+
+func (m *ApprovalService) RequiresApproval(total float64) bool {
+	m.record("RequiresApproval", m.RequiresApprovalFunc != nil, total)
+	return m.RequiresApprovalFunc(total)
+}
+
+func (m *ApprovalService) Require(ctx context.Context, order *domain.SupplierOrder) error {
+	m.record("Require", m.RequireFunc != nil, order)
+	return m.RequireFunc(ctx, order)
+}
+
+func (m *ApprovalService) Status(ctx context.Context, orderID uuid.UUID) (*service.ApprovalStatus, error) {
+	m.record("Status", m.StatusFunc != nil, orderID)
+	return m.StatusFunc(ctx, orderID)
+}
+
+func (m *ApprovalService) Approve(ctx context.Context, orderID, adminID uuid.UUID) (*service.ApprovalStatus, error) {
+	m.record("Approve", m.ApproveFunc != nil, orderID, adminID)
+	return m.ApproveFunc(ctx, orderID, adminID)
+}
+
+func (m *ApprovalService) PlanApprove(ctx context.Context, orderID, adminID uuid.UUID) (*service.TransitionPlan, error) {
+	m.record("PlanApprove", m.PlanApproveFunc != nil, orderID, adminID)
+	return m.PlanApproveFunc(ctx, orderID, adminID)
+}
+
+// ShippingService mocks service.ShippingService
+type ShippingService struct {
+	recorder
+
+	ShipOrderFunc         func(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error
+	PlanShipOrderFunc     func(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) (*service.TransitionPlan, error)
+	QuoteOrderFunc        func(ctx context.Context, orderID uuid.UUID) (domain.Parcel, []service.CarrierQuotes, error)
+	PollShippedOrdersFunc func(ctx context.Context) (int, error)
+}
+
+// NewShippingService creates a new ShippingService mock
+func NewShippingService(t testing.TB) *ShippingService {
+	return &ShippingService{recorder: recorder{t: t}}
+}
+
+func (m *ShippingService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error {
+	m.record("ShipOrder", m.ShipOrderFunc != nil, orderID, carrier, trackingNumber, trackingURL)
+	return m.ShipOrderFunc(ctx, orderID, carrier, trackingNumber, trackingURL)
+}
+
+func (m *ShippingService) PlanShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) (*service.TransitionPlan, error) {
+	m.record("PlanShipOrder", m.PlanShipOrderFunc != nil, orderID, carrier, trackingNumber, trackingURL)
+	return m.PlanShipOrderFunc(ctx, orderID, carrier, trackingNumber, trackingURL)
+}
+
+func (m *ShippingService) QuoteOrder(ctx context.Context, orderID uuid.UUID) (domain.Parcel, []service.CarrierQuotes, error) {
+	m.record("QuoteOrder", m.QuoteOrderFunc != nil, orderID)
+	return m.QuoteOrderFunc(ctx, orderID)
+}
+
+func (m *ShippingService) PollShippedOrders(ctx context.Context) (int, error) {
+	m.record("PollShippedOrders", m.PollShippedOrdersFunc != nil)
+	return m.PollShippedOrdersFunc(ctx)
+}
+
+// OrderLookupService mocks service.OrderLookupService
+type OrderLookupService struct {
+	recorder
+
+	ResolveShopifyOrderFunc func(ctx context.Context, shopifyOrderID int64) (*domain.SupplierOrder, error)
+}
+
+// NewOrderLookupService creates a new OrderLookupService mock
+func NewOrderLookupService(t testing.TB) *OrderLookupService {
+	return &OrderLookupService{recorder: recorder{t: t}}
+}
+
+func (m *OrderLookupService) ResolveShopifyOrder(ctx context.Context, shopifyOrderID int64) (*domain.SupplierOrder, error) {
+	m.record("ResolveShopifyOrder", m.ResolveShopifyOrderFunc != nil, shopifyOrderID)
+	return m.ResolveShopifyOrderFunc(ctx, shopifyOrderID)
+}
+
+// SubstitutionService mocks service.SubstitutionService
+type SubstitutionService struct {
+	recorder
+
+	ProposeFunc func(ctx context.Context, order *domain.SupplierOrder, req service.ProposeSubstitutionRequest) (*domain.ItemSubstitution, error)
+	AcceptFunc  func(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID) (*domain.ItemSubstitution, error)
+	DeclineFunc func(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID, reason *string) (*domain.ItemSubstitution, error)
+}
+
+// NewSubstitutionService creates a new SubstitutionService mock
+func NewSubstitutionService(t testing.TB) *SubstitutionService {
+	return &SubstitutionService{recorder: recorder{t: t}}
+}
+
+func (m *SubstitutionService) Propose(ctx context.Context, order *domain.SupplierOrder, req service.ProposeSubstitutionRequest) (*domain.ItemSubstitution, error) {
+	m.record("Propose", m.ProposeFunc != nil, order, req)
+	return m.ProposeFunc(ctx, order, req)
+}
+
+func (m *SubstitutionService) Accept(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID) (*domain.ItemSubstitution, error) {
+	m.record("Accept", m.AcceptFunc != nil, order, substitutionID)
+	return m.AcceptFunc(ctx, order, substitutionID)
+}
+
+func (m *SubstitutionService) Decline(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID, reason *string) (*domain.ItemSubstitution, error) {
+	m.record("Decline", m.DeclineFunc != nil, order, substitutionID, reason)
+	return m.DeclineFunc(ctx, order, substitutionID, reason)
+}
+
+// OnboardingService mocks service.OnboardingService
+type OnboardingService struct {
+	recorder
+
+	CreateInvitationFunc          func(ctx context.Context, actor string, partnerName string, ttl time.Duration) (*domain.PartnerInvitation, string, error)
+	AcceptInvitationFunc          func(ctx context.Context, token string, webhookURL *string) (*domain.Partner, string, error)
+	UpdateWebhookURLFunc          func(ctx context.Context, partner *domain.Partner, webhookURL *string) (string, error)
+	RotateWebhookSecretFunc       func(ctx context.Context, partner *domain.Partner) (string, error)
+	RevokeAPIKeyFunc              func(ctx context.Context, actor string, partner *domain.Partner, reason string) (*domain.RevokedAPIKey, error)
+	IssueAPIKeyFunc               func(ctx context.Context, actor string, partner *domain.Partner) (string, error)
+	ImportAPIKeyFunc              func(ctx context.Context, actor string, partner *domain.Partner, apiKey string) error
+	UpdateLocaleFunc              func(ctx context.Context, partner *domain.Partner, tag string) error
+	UpdateLegacyStatusCodesFunc   func(ctx context.Context, partner *domain.Partner, legacy bool) error
+	UpdatePaymentTermsFunc        func(ctx context.Context, actor string, partner *domain.Partner, terms domain.PaymentTerms, invoiceEmail *string) error
+	UpdateCreditLimitFunc         func(ctx context.Context, actor string, partner *domain.Partner, limit *float64, action domain.CreditLimitAction) error
+	UpdateSubstitutionsFunc       func(ctx context.Context, actor string, partner *domain.Partner, enabled bool) error
+	CreateOrganizationFunc        func(ctx context.Context, actor, name string) (*domain.Organization, error)
+	UpdatePartnerOrganizationFunc func(ctx context.Context, actor string, partner *domain.Partner, organizationID *uuid.UUID) error
+}
+
+// NewOnboardingService creates a new OnboardingService mock
+func NewOnboardingService(t testing.TB) *OnboardingService {
+	return &OnboardingService{recorder: recorder{t: t}}
+}
+
+func (m *OnboardingService) CreateInvitation(ctx context.Context, actor string, partnerName string, ttl time.Duration) (*domain.PartnerInvitation, string, error) {
+	m.record("CreateInvitation", m.CreateInvitationFunc != nil, actor, partnerName, ttl)
+	return m.CreateInvitationFunc(ctx, actor, partnerName, ttl)
+}
+
+func (m *OnboardingService) AcceptInvitation(ctx context.Context, token string, webhookURL *string) (*domain.Partner, string, error) {
+	m.record("AcceptInvitation", m.AcceptInvitationFunc != nil, token, webhookURL)
+	return m.AcceptInvitationFunc(ctx, token, webhookURL)
+}
+
+func (m *OnboardingService) UpdateWebhookURL(ctx context.Context, partner *domain.Partner, webhookURL *string) (string, error) {
+	m.record("UpdateWebhookURL", m.UpdateWebhookURLFunc != nil, partner, webhookURL)
+	return m.UpdateWebhookURLFunc(ctx, partner, webhookURL)
+}
+
+func (m *OnboardingService) RotateWebhookSecret(ctx context.Context, partner *domain.Partner) (string, error) {
+	m.record("RotateWebhookSecret", m.RotateWebhookSecretFunc != nil, partner)
+	return m.RotateWebhookSecretFunc(ctx, partner)
+}
+
+func (m *OnboardingService) RevokeAPIKey(ctx context.Context, actor string, partner *domain.Partner, reason string) (*domain.RevokedAPIKey, error) {
+	m.record("RevokeAPIKey", m.RevokeAPIKeyFunc != nil, actor, partner, reason)
+	return m.RevokeAPIKeyFunc(ctx, actor, partner, reason)
+}
+
+func (m *OnboardingService) IssueAPIKey(ctx context.Context, actor string, partner *domain.Partner) (string, error) {
+	m.record("IssueAPIKey", m.IssueAPIKeyFunc != nil, actor, partner)
+	return m.IssueAPIKeyFunc(ctx, actor, partner)
+}
+
+func (m *OnboardingService) ImportAPIKey(ctx context.Context, actor string, partner *domain.Partner, apiKey string) error {
+	m.record("ImportAPIKey", m.ImportAPIKeyFunc != nil, actor, partner, apiKey)
+	return m.ImportAPIKeyFunc(ctx, actor, partner, apiKey)
+}
+
+func (m *OnboardingService) UpdateLocale(ctx context.Context, partner *domain.Partner, tag string) error {
+	m.record("UpdateLocale", m.UpdateLocaleFunc != nil, partner, tag)
+	return m.UpdateLocaleFunc(ctx, partner, tag)
+}
+
+func (m *OnboardingService) UpdateLegacyStatusCodes(ctx context.Context, partner *domain.Partner, legacy bool) error {
+	m.record("UpdateLegacyStatusCodes", m.UpdateLegacyStatusCodesFunc != nil, partner, legacy)
+	return m.UpdateLegacyStatusCodesFunc(ctx, partner, legacy)
+}
+
+func (m *OnboardingService) UpdatePaymentTerms(ctx context.Context, actor string, partner *domain.Partner, terms domain.PaymentTerms, invoiceEmail *string) error {
+	m.record("UpdatePaymentTerms", m.UpdatePaymentTermsFunc != nil, actor, partner, terms, invoiceEmail)
+	return m.UpdatePaymentTermsFunc(ctx, actor, partner, terms, invoiceEmail)
+}
+
+func (m *OnboardingService) UpdateCreditLimit(ctx context.Context, actor string, partner *domain.Partner, limit *float64, action domain.CreditLimitAction) error {
+	m.record("UpdateCreditLimit", m.UpdateCreditLimitFunc != nil, actor, partner, limit, action)
+	return m.UpdateCreditLimitFunc(ctx, actor, partner, limit, action)
+}
+
+func (m *OnboardingService) UpdateSubstitutions(ctx context.Context, actor string, partner *domain.Partner, enabled bool) error {
+	m.record("UpdateSubstitutions", m.UpdateSubstitutionsFunc != nil, actor, partner, enabled)
+	return m.UpdateSubstitutionsFunc(ctx, actor, partner, enabled)
+}
+
+func (m *OnboardingService) CreateOrganization(ctx context.Context, actor, name string) (*domain.Organization, error) {
+	m.record("CreateOrganization", m.CreateOrganizationFunc != nil, actor, name)
+	return m.CreateOrganizationFunc(ctx, actor, name)
+}
+
+func (m *OnboardingService) UpdatePartnerOrganization(ctx context.Context, actor string, partner *domain.Partner, organizationID *uuid.UUID) error {
+	m.record("UpdatePartnerOrganization", m.UpdatePartnerOrganizationFunc != nil, actor, partner, organizationID)
+	return m.UpdatePartnerOrganizationFunc(ctx, actor, partner, organizationID)
+}
+
+// CreditService mocks service.CreditService
+type CreditService struct {
+	recorder
+
+	StatusFunc    func(ctx context.Context, partner *domain.Partner) (*service.CreditStatus, error)
+	CheckCartFunc func(ctx context.Context, partner *domain.Partner, total float64) (bool, error)
+}
+
+// NewCreditService creates a new CreditService mock
+func NewCreditService(t testing.TB) *CreditService {
+	return &CreditService{recorder: recorder{t: t}}
+}
+
+func (m *CreditService) Status(ctx context.Context, partner *domain.Partner) (*service.CreditStatus, error) {
+	m.record("Status", m.StatusFunc != nil, partner)
+	return m.StatusFunc(ctx, partner)
+}
+
+func (m *CreditService) CheckCart(ctx context.Context, partner *domain.Partner, total float64) (bool, error) {
+	m.record("CheckCart", m.CheckCartFunc != nil, partner, total)
+	return m.CheckCartFunc(ctx, partner, total)
+}
+
+// CreditAlertService mocks service.CreditAlertService
+type CreditAlertService struct {
+	recorder
+
+	CheckFunc func(ctx context.Context, partner *domain.Partner) (int, error)
+}
+
+// NewCreditAlertService creates a new CreditAlertService mock
+func NewCreditAlertService(t testing.TB) *CreditAlertService {
+	return &CreditAlertService{recorder: recorder{t: t}}
+}
+
+func (m *CreditAlertService) Check(ctx context.Context, partner *domain.Partner) (int, error) {
+	m.record("Check", m.CheckFunc != nil, partner)
+	return m.CheckFunc(ctx, partner)
+}
+
+// ReviewQueueService mocks service.ReviewQueueService
+type ReviewQueueService struct {
+	recorder
+
+	ListFunc    func(ctx context.Context, filter service.ReviewQueueFilter, now time.Time) ([]*service.ReviewQueueItem, error)
+	ClaimFunc   func(ctx context.Context, orderID, staffID uuid.UUID) (*domain.ReviewAssignment, error)
+	AssignFunc  func(ctx context.Context, orderID, assigneeID, adminID uuid.UUID) (*domain.ReviewAssignment, error)
+	ReleaseFunc func(ctx context.Context, orderID, staffID uuid.UUID) error
+}
+
+// NewReviewQueueService creates a new ReviewQueueService mock
+func NewReviewQueueService(t testing.TB) *ReviewQueueService {
+	return &ReviewQueueService{recorder: recorder{t: t}}
+}
+
+func (m *ReviewQueueService) List(ctx context.Context, filter service.ReviewQueueFilter, now time.Time) ([]*service.ReviewQueueItem, error) {
+	m.record("List", m.ListFunc != nil, filter, now)
+	return m.ListFunc(ctx, filter, now)
+}
+
+func (m *ReviewQueueService) Claim(ctx context.Context, orderID, staffID uuid.UUID) (*domain.ReviewAssignment, error) {
+	m.record("Claim", m.ClaimFunc != nil, orderID, staffID)
+	return m.ClaimFunc(ctx, orderID, staffID)
+}
+
+func (m *ReviewQueueService) Assign(ctx context.Context, orderID, assigneeID, adminID uuid.UUID) (*domain.ReviewAssignment, error) {
+	m.record("Assign", m.AssignFunc != nil, orderID, assigneeID, adminID)
+	return m.AssignFunc(ctx, orderID, assigneeID, adminID)
+}
+
+func (m *ReviewQueueService) Release(ctx context.Context, orderID, staffID uuid.UUID) error {
+	m.record("Release", m.ReleaseFunc != nil, orderID, staffID)
+	return m.ReleaseFunc(ctx, orderID, staffID)
+}
+
+// PartnerEventService mocks service.PartnerEventService
+type PartnerEventService struct {
+	recorder
+
+	RecordEventFunc func(ctx context.Context, order *domain.SupplierOrder, req service.PartnerOrderEventRequest) (*service.PartnerOrderEventResult, error)
+}
+
+// NewPartnerEventService creates a new PartnerEventService mock
+func NewPartnerEventService(t testing.TB) *PartnerEventService {
+	return &PartnerEventService{recorder: recorder{t: t}}
+}
+
+func (m *PartnerEventService) RecordEvent(ctx context.Context, order *domain.SupplierOrder, req service.PartnerOrderEventRequest) (*service.PartnerOrderEventResult, error) {
+	m.record("RecordEvent", m.RecordEventFunc != nil, order, req)
+	return m.RecordEventFunc(ctx, order, req)
+}
+
+// OrderEditSyncService mocks service.OrderEditSyncService
+type OrderEditSyncService struct {
+	recorder
+
+	ReconcileOrderFunc func(ctx context.Context, order *domain.SupplierOrder) ([]service.LineItemDivergence, error)
+}
+
+// NewOrderEditSyncService creates a new OrderEditSyncService mock
+func NewOrderEditSyncService(t testing.TB) *OrderEditSyncService {
+	return &OrderEditSyncService{recorder: recorder{t: t}}
+}
+
+func (m *OrderEditSyncService) ReconcileOrder(ctx context.Context, order *domain.SupplierOrder) ([]service.LineItemDivergence, error) {
+	m.record("ReconcileOrder", m.ReconcileOrderFunc != nil, order)
+	return m.ReconcileOrderFunc(ctx, order)
+}
+
+// TotalCheckService mocks service.TotalCheckService
+type TotalCheckService struct {
+	recorder
+
+	CompareOrderFunc func(ctx context.Context, order *domain.SupplierOrder) (*service.TotalComparison, error)
+}
+
+// NewTotalCheckService creates a new TotalCheckService mock
+func NewTotalCheckService(t testing.TB) *TotalCheckService {
+	return &TotalCheckService{recorder: recorder{t: t}}
+}
+
+func (m *TotalCheckService) CompareOrder(ctx context.Context, order *domain.SupplierOrder) (*service.TotalComparison, error) {
+	m.record("CompareOrder", m.CompareOrderFunc != nil, order)
+	return m.CompareOrderFunc(ctx, order)
+}
+
+// OrderDiffService mocks service.OrderDiffService
+type OrderDiffService struct {
+	recorder
+
+	DiffOrderFunc func(ctx context.Context, order *domain.SupplierOrder) (*service.OrderDiff, error)
+}
+
+// NewOrderDiffService creates a new OrderDiffService mock
+func NewOrderDiffService(t testing.TB) *OrderDiffService {
+	return &OrderDiffService{recorder: recorder{t: t}}
+}
+
+func (m *OrderDiffService) DiffOrder(ctx context.Context, order *domain.SupplierOrder) (*service.OrderDiff, error) {
+	m.record("DiffOrder", m.DiffOrderFunc != nil, order)
+	return m.DiffOrderFunc(ctx, order)
+}
@@ -0,0 +1,59 @@
+// Package servicemock has mock implementations of the service interfaces for handler tests.
+// Set the Func field of each method a test expects to be called; calling a method without
+// one fails the test. Calls are recorded in the order they were made.
+//
+// The mocks in mocks.go are generated from internal/service/services.go; run go generate
+// after changing a service interface.
+package servicemock
+
+//go:generate go run ./gen -source ../services.go -out mocks.go
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Call is a recorded method call
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// recorder records calls and fails the test on unexpected ones
+type recorder struct {
+	t     testing.TB
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, expected bool, args ...interface{}) {
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	r.mu.Unlock()
+
+	if !expected {
+		// Not t.Fatalf: it must not be called outside the test's goroutine (e.g. from a
+		// background webhook notification)
+		r.t.Errorf("unexpected call to %s", method)
+		panic(fmt.Sprintf("servicemock: unexpected call to %s", method))
+	}
+}
+
+// Calls returns the calls made so far
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallCount returns how often method was called
+func (r *recorder) CallCount(method string) int {
+	n := 0
+	for _, call := range r.Calls() {
+		if call.Method == method {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/errortracking"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// OrderService moves supplier orders through their lifecycle
+type OrderService interface {
+	CreateOrderFromCart(ctx context.Context, partnerID uuid.UUID, req CartSubmitRequest, supplierItems map[string]*CartItemMatch, referencePrefix string) (*domain.SupplierOrder, error)
+	ConfirmOrder(ctx context.Context, orderID uuid.UUID) error
+	RejectOrder(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) error
+	// PlanRejectOrder returns what RejectOrder would do, changing nothing
+	PlanRejectOrder(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) (*TransitionPlan, error)
+	RejectOrders(ctx context.Context, orderIDs []uuid.UUID, code domain.RejectionCode, reason string) ([]BulkRejectResult, error)
+	ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error
+	DeliverOrder(ctx context.Context, orderID uuid.UUID, deliveredAt time.Time, source string, proofOfDelivery map[string]interface{}) error
+	HoldOrder(ctx context.Context, orderID uuid.UUID, reason string) error
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID) error
+	ReassignOrder(ctx context.Context, orderID, toPartnerID uuid.UUID, reason string, adminID uuid.UUID) (*domain.SupplierOrder, error)
+}
+
+// CartService validates and submits partner carts
+type CartService interface {
+	SubmitCart(ctx context.Context, partner *domain.Partner, req CartSubmitRequest) (*domain.SupplierOrder, bool, error)
+	ValidateCart(ctx context.Context, partner *domain.Partner, req CartSubmitRequest) (*CartValidationResult, error)
+	// CheckDuplicateSubmission returns the order already created from the same cart content
+	// within the duplicate submission window, nil when there is none
+	CheckDuplicateSubmission(ctx context.Context, partner *domain.Partner, req CartSubmitRequest) (*domain.SupplierOrder, error)
+	// ReleaseOrder releases a held order, completing the draft order of a cart that was held
+	// for the credit limit
+	ReleaseOrder(ctx context.Context, orderID uuid.UUID) error
+}
+
+// SKUService matches cart items to supplier SKUs
+type SKUService interface {
+	CheckCartForSupplierSKUs(ctx context.Context, partnerID uuid.UUID, items []CartItem, restrictionMode string) (bool, map[string]*CartItemMatch, error)
+	MatchCartItems(ctx context.Context, partnerID uuid.UUID, items []CartItem) ([]CartItemMatch, error)
+	MissedMatches(ctx context.Context, from, to time.Time) ([]MissedSKUMatch, error)
+}
+
+// ShopifyService is the store's side of supplier orders: draft orders, orders and variants
+type ShopifyService interface {
+	// CreateDraftOrder, FindDraftOrder and CompleteDraftOrder return the Shopify ID and name
+	CreateDraftOrder(ctx context.Context, order *domain.SupplierOrder, items []*domain.SupplierOrderItem, partnerName string) (int64, string, error)
+	UpdateDraftOrderLineItems(ctx context.Context, draftOrderID int64, order *domain.SupplierOrder, items []*domain.SupplierOrderItem) error
+	FindDraftOrder(ctx context.Context, order *domain.SupplierOrder, partnerName string) (int64, string, error)
+	DeleteDraftOrder(ctx context.Context, draftOrderID int64) error
+	CompleteDraftOrder(ctx context.Context, draftOrderID int64) (int64, string, error)
+	SendDraftOrderInvoice(ctx context.Context, draftOrderID int64, email *string) (string, error)
+	CompleteOrder(ctx context.Context, order *domain.SupplierOrder) error
+	FindOrCreateCustomer(ctx context.Context, order *domain.SupplierOrder) (int64, error)
+	SetOrderLinkageMetafields(ctx context.Context, shopifyOrderID int64, order *domain.SupplierOrder) error
+	GetOrderLinkageMetafields(ctx context.Context, shopifyOrderID int64) (map[string]string, error)
+	GetOrderLineItems(ctx context.Context, shopifyOrderID int64) (items []ShopifyLineItem, edited bool, err error)
+	GetOrderTotal(ctx context.Context, shopifyOrderID int64) (total float64, currency string, err error)
+	GetOrderSnapshot(ctx context.Context, shopifyOrderID int64) (*ShopifyOrderSnapshot, error)
+	GetVariants(ctx context.Context, variantIDs []int64) (map[int64]*ShopifyVariant, error)
+}
+
+// WebhookService delivers events to partner webhooks
+type WebhookService interface {
+	NotifyOrderEvent(order *domain.SupplierOrder, eventType string, data map[string]interface{})
+	NotifyPartnerEvent(partner *domain.Partner, eventType string, data map[string]interface{})
+	DeliverOrderEvent(ctx context.Context, order *domain.SupplierOrder, eventType string, data map[string]interface{}) (*domain.WebhookDelivery, error)
+	UpdateSubscriptions(ctx context.Context, partner *domain.Partner, eventTypes []string) ([]string, error)
+	RetryDelivery(ctx context.Context, partner *domain.Partner, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+}
+
+// DuplicateService finds open orders that look like the same order placed twice
+type DuplicateService interface {
+	FindDuplicates(ctx context.Context, window time.Duration) ([]DuplicateCluster, error)
+}
+
+// ApprovalService applies the two-person rule to orders over APPROVAL_THRESHOLD
+type ApprovalService interface {
+	RequiresApproval(total float64) bool
+	Require(ctx context.Context, order *domain.SupplierOrder) error
+	Status(ctx context.Context, orderID uuid.UUID) (*ApprovalStatus, error)
+	Approve(ctx context.Context, orderID, adminID uuid.UUID) (*ApprovalStatus, error)
+	// PlanApprove returns what Approve would do, changing nothing
+	PlanApprove(ctx context.Context, orderID, adminID uuid.UUID) (*TransitionPlan, error)
+}
+
+// ShippingService ships orders with known carriers and quotes their rates
+type ShippingService interface {
+	ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error
+	// PlanShipOrder returns what ShipOrder would do, changing nothing
+	PlanShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) (*TransitionPlan, error)
+	QuoteOrder(ctx context.Context, orderID uuid.UUID) (domain.Parcel, []CarrierQuotes, error)
+	PollShippedOrders(ctx context.Context) (int, error)
+}
+
+// OrderLookupService finds the supplier order behind a Shopify order
+type OrderLookupService interface {
+	ResolveShopifyOrder(ctx context.Context, shopifyOrderID int64) (*domain.SupplierOrder, error)
+}
+
+// SubstitutionService proposes substitutes for order items and applies the partner's answer
+type SubstitutionService interface {
+	Propose(ctx context.Context, order *domain.SupplierOrder, req ProposeSubstitutionRequest) (*domain.ItemSubstitution, error)
+	Accept(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID) (*domain.ItemSubstitution, error)
+	Decline(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID, reason *string) (*domain.ItemSubstitution, error)
+}
+
+// OnboardingService manages partner accounts: invitations, API keys, webhooks and terms
+type OnboardingService interface {
+	CreateInvitation(ctx context.Context, actor string, partnerName string, ttl time.Duration) (*domain.PartnerInvitation, string, error)
+	AcceptInvitation(ctx context.Context, token string, webhookURL *string) (*domain.Partner, string, error)
+	UpdateWebhookURL(ctx context.Context, partner *domain.Partner, webhookURL *string) (string, error)
+	RotateWebhookSecret(ctx context.Context, partner *domain.Partner) (string, error)
+	RevokeAPIKey(ctx context.Context, actor string, partner *domain.Partner, reason string) (*domain.RevokedAPIKey, error)
+	IssueAPIKey(ctx context.Context, actor string, partner *domain.Partner) (string, error)
+	ImportAPIKey(ctx context.Context, actor string, partner *domain.Partner, apiKey string) error
+	UpdateLocale(ctx context.Context, partner *domain.Partner, tag string) error
+	UpdateLegacyStatusCodes(ctx context.Context, partner *domain.Partner, legacy bool) error
+	UpdatePaymentTerms(ctx context.Context, actor string, partner *domain.Partner, terms domain.PaymentTerms, invoiceEmail *string) error
+	UpdateCreditLimit(ctx context.Context, actor string, partner *domain.Partner, limit *float64, action domain.CreditLimitAction) error
+	UpdateSubstitutions(ctx context.Context, actor string, partner *domain.Partner, enabled bool) error
+	CreateOrganization(ctx context.Context, actor, name string) (*domain.Organization, error)
+	UpdatePartnerOrganization(ctx context.Context, actor string, partner *domain.Partner, organizationID *uuid.UUID) error
+}
+
+// CreditService tracks partners' outstanding orders against their credit limit
+type CreditService interface {
+	Status(ctx context.Context, partner *domain.Partner) (*CreditStatus, error)
+	CheckCart(ctx context.Context, partner *domain.Partner, total float64) (bool, error)
+}
+
+// CreditAlertService alerts partners as their outstanding orders approach their credit limit
+type CreditAlertService interface {
+	Check(ctx context.Context, partner *domain.Partner) (int, error)
+}
+
+// ReviewQueueService lists the orders needing staff attention and who works them
+type ReviewQueueService interface {
+	List(ctx context.Context, filter ReviewQueueFilter, now time.Time) ([]*ReviewQueueItem, error)
+	Claim(ctx context.Context, orderID, staffID uuid.UUID) (*domain.ReviewAssignment, error)
+	Assign(ctx context.Context, orderID, assigneeID, adminID uuid.UUID) (*domain.ReviewAssignment, error)
+	Release(ctx context.Context, orderID, staffID uuid.UUID) error
+}
+
+// PartnerEventService records the updates partners push about their orders
+type PartnerEventService interface {
+	RecordEvent(ctx context.Context, order *domain.SupplierOrder, req PartnerOrderEventRequest) (*PartnerOrderEventResult, error)
+}
+
+// OrderEditSyncService compares orders with their Shopify orders after edits in Shopify
+type OrderEditSyncService interface {
+	ReconcileOrder(ctx context.Context, order *domain.SupplierOrder) ([]LineItemDivergence, error)
+}
+
+// TotalCheckService compares order cart totals with the totals of their Shopify orders
+type TotalCheckService interface {
+	CompareOrder(ctx context.Context, order *domain.SupplierOrder) (*TotalComparison, error)
+}
+
+// OrderDiffService compares an order with its Shopify order as it is now
+type OrderDiffService interface {
+	DiffOrder(ctx context.Context, order *domain.SupplierOrder) (*OrderDiff, error)
+}
+
+var (
+	_ OrderService         = (*orderService)(nil)
+	_ CartService          = (*cartService)(nil)
+	_ SKUService           = (*skuService)(nil)
+	_ ShopifyService       = (*shopifyService)(nil)
+	_ WebhookService       = (*webhookService)(nil)
+	_ DuplicateService     = (*duplicateService)(nil)
+	_ ApprovalService      = (*approvalService)(nil)
+	_ ShippingService      = (*shippingService)(nil)
+	_ OrderLookupService   = (*orderLookupService)(nil)
+	_ SubstitutionService  = (*substitutionService)(nil)
+	_ OnboardingService    = (*onboardingService)(nil)
+	_ CreditService        = (*creditService)(nil)
+	_ CreditAlertService   = (*creditAlertService)(nil)
+	_ ReviewQueueService   = (*reviewQueueService)(nil)
+	_ PartnerEventService  = (*partnerEventService)(nil)
+	_ OrderEditSyncService = (*orderEditSyncService)(nil)
+	_ TotalCheckService    = (*totalCheckService)(nil)
+	_ OrderDiffService     = (*orderDiffService)(nil)
+)
+
+// Services holds the services shared by every request. Build it once at startup with
+// NewServices and hand it to the API layers, so the services (and the Shopify and webhook
+// HTTP clients behind them) are shared instead of rebuilt per request; tests can swap any
+// of them for a fake.
+//
+// Services that depend on others are given the shared ones, so an order transition behaves
+// the same whichever service makes it. The background jobs are built once at startup the
+// same way, from the shared Orders, Shopify and Webhooks.
+type Services struct {
+	Orders        OrderService
+	Carts         CartService
+	SKUs          SKUService
+	Shopify       ShopifyService
+	Webhooks      WebhookService
+	Duplicates    DuplicateService
+	Approvals     ApprovalService
+	Shipping      ShippingService
+	Lookup        OrderLookupService
+	Substitutions SubstitutionService
+	Onboarding    OnboardingService
+	Credit        CreditService
+	CreditAlerts  CreditAlertService
+	ReviewQueue   ReviewQueueService
+	PartnerEvents PartnerEventService
+	EditSync      OrderEditSyncService
+	TotalChecks   TotalCheckService
+	Diffs         OrderDiffService
+	// Errors reports to Sentry; disabled without SENTRY_DSN
+	Errors *errortracking.Tracker
+	// Maintenance rejects mutations while it is on
+	Maintenance *Maintenance
+}
+
+// Operations logged with failed Shopify calls (the "operation" field), so the failures can be
+// told apart in error reports
+const (
+	opShopifyCreateDraftOrder = "shopify.create_draft_order"
+	opShopifyUpdateDraftOrder = "shopify.update_draft_order"
+	opShopifyCompleteOrder    = "shopify.complete_order"
+	opShopifySendInvoice      = "shopify.send_invoice"
+)
+
+// NewServices wires up the shared services. Errors the services log are reported to Sentry
+// when it is enabled.
+func NewServices(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *Services {
+	errorTracker := errortracking.New(cfg.Sentry, logger)
+	logger = errorTracker.Wrap(logger)
+
+	webhooks := NewWebhookService(repos, logger)
+	shopifyService := NewShopifyService(cfg.Shopify, repos, logger)
+	skus := NewSKUService(cfg, repos, logger)
+
+	orders := NewOrderService(repos, logger)
+	orders.webhooks = webhooks
+
+	credit := NewCreditService(repos, logger)
+	creditAlerts := NewCreditAlertService(cfg, repos, credit, webhooks, logger)
+	approvals := NewApprovalService(cfg, repos, orders, shopifyService, logger)
+
+	carts := NewCartService(cfg, repos, logger)
+	carts.orders = orders
+	carts.skus = skus
+	carts.shopify = shopifyService
+	carts.credit = credit
+	carts.creditAlerts = creditAlerts
+	carts.approvals = approvals
+
+	return &Services{
+		Orders:        orders,
+		Carts:         carts,
+		SKUs:          skus,
+		Shopify:       shopifyService,
+		Webhooks:      webhooks,
+		Duplicates:    NewDuplicateService(repos, logger),
+		Approvals:     approvals,
+		Shipping:      NewShippingService(cfg.Carriers, repos, orders, logger),
+		Lookup:        NewOrderLookupService(repos, shopifyService, logger),
+		Substitutions: NewSubstitutionService(cfg, repos, shopifyService, webhooks, logger),
+		Onboarding:    NewOnboardingService(repos, logger),
+		Credit:        credit,
+		CreditAlerts:  creditAlerts,
+		ReviewQueue:   NewReviewQueueService(cfg, repos, logger),
+		PartnerEvents: NewPartnerEventService(cfg, repos, orders, shopifyService, creditAlerts, logger),
+		EditSync:      NewOrderEditSyncService(cfg, repos, shopifyService, webhooks, logger),
+		TotalChecks:   NewTotalCheckService(cfg, repos, shopifyService, logger),
+		Diffs:         NewOrderDiffService(cfg, repos, shopifyService, logger),
+		Errors:        errorTracker,
+		Maintenance:   NewMaintenance(cfg),
+	}
+}
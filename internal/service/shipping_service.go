@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/carriers"
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// deliveryPollPageSize is how many shipped orders are loaded per query while polling
+const deliveryPollPageSize = 100
+
+type shippingService struct {
+	registry *carriers.Registry
+	repos    *repository.Repositories
+	orders   OrderService
+	logger   *zap.Logger
+}
+
+// NewShippingService creates a new shipping service backed by the carriers registry
+func NewShippingService(cfg config.CarriersConfig, repos *repository.Repositories, orders OrderService, logger *zap.Logger) *shippingService {
+	registry := carriers.NewRegistry(cfg)
+	if cfg.DHLAPIKey != "" {
+		registry.SetTracker("dhl", carriers.NewDHLTracker(cfg.DHLAPIKey, cfg.DHLTrackingURL))
+	}
+
+	return &shippingService{
+		registry: registry,
+		repos:    repos,
+		orders:   orders,
+		logger:   logger,
+	}
+}
+
+// Registry returns the carriers registry
+func (s *shippingService) Registry() *carriers.Registry {
+	return s.registry
+}
+
+// ShipOrder validates the carrier against the registry, fills in the tracking URL from the
+// carrier's template when none is given, and marks the order shipped
+func (s *shippingService) ShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) error {
+	name, trackingURL, err := s.resolveCarrier(carrier, trackingNumber, trackingURL)
+	if err != nil {
+		return err
+	}
+
+	return s.orders.ShipOrder(ctx, orderID, name, trackingNumber, trackingURL)
+}
+
+// resolveCarrier returns the carrier's display name and the tracking URL to record
+func (s *shippingService) resolveCarrier(carrier, trackingNumber string, trackingURL *string) (string, *string, error) {
+	name := carrier
+	if c, ok := s.registry.Lookup(carrier); ok {
+		name = c.Name
+		if trackingURL == nil || *trackingURL == "" {
+			if generated := c.TrackingURL(trackingNumber); generated != "" {
+				trackingURL = &generated
+			}
+		}
+	} else if !s.registry.AllowUnknown() {
+		return "", nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"carrier": "unknown carrier"},
+		}
+	}
+	return name, trackingURL, nil
+}
+
+// RunDeliveryPolling periodically asks carrier tracking APIs about shipped orders and marks
+// delivered ones DELIVERED. It returns when ctx is cancelled.
+func (s *shippingService) RunDeliveryPolling(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		delivered, err := s.PollShippedOrders(ctx)
+		if err != nil {
+			s.logger.Error("Delivery polling failed", zap.Error(err))
+			continue
+		}
+		if delivered > 0 {
+			s.logger.Info("Marked orders delivered from carrier tracking", zap.Int("count", delivered))
+		}
+	}
+}
+
+// PollShippedOrders checks every shipped order whose carrier has a tracking adapter once.
+// Returns the number of orders marked delivered.
+func (s *shippingService) PollShippedOrders(ctx context.Context) (int, error) {
+	delivered := 0
+
+	var cursor *domain.OrderCursor
+	for {
+		orders, err := s.repos.SupplierOrder.ListByStatusAfter(ctx, domain.OrderStatusShipped, cursor, deliveryPollPageSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return delivered, ctx.Err()
+			}
+			if order.TrackingCarrier == nil || order.TrackingNumber == nil {
+				continue
+			}
+			carrier, ok := s.registry.Lookup(*order.TrackingCarrier)
+			if !ok || carrier.Tracker == nil {
+				continue
+			}
+
+			status, err := carrier.Tracker.Track(ctx, *order.TrackingNumber)
+			if err != nil {
+				s.logger.Warn("Failed to track shipment",
+					zap.String("order_id", order.ID.String()),
+					zap.String("carrier", carrier.Code),
+					zap.Error(err),
+				)
+				continue
+			}
+			if !status.Delivered {
+				continue
+			}
+
+			deliveredAt := time.Now()
+			if status.DeliveredAt != nil {
+				deliveredAt = *status.DeliveredAt
+			}
+			if err := s.orders.DeliverOrder(ctx, order.ID, deliveredAt, carrier.Code, status.ProofOfDelivery); err != nil {
+				s.logger.Warn("Failed to mark order delivered",
+					zap.String("order_id", order.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			delivered++
+		}
+
+		if len(orders) < deliveryPollPageSize {
+			return delivered, nil
+		}
+		cursor = domain.CursorAfter(orders[len(orders)-1])
+	}
+}
+
+// CarrierQuotes are the rates one carrier quoted for an order's parcel
+type CarrierQuotes struct {
+	Carrier string
+	Quotes  []carriers.RateQuote
+	// Error is set when the carrier's API failed; other carriers are still quoted
+	Error string
+}
+
+// QuoteOrder aggregates the weight and dimensions of the order's items and asks every
+// carrier with a rate adapter for quotes to the shipping address
+func (s *shippingService) QuoteOrder(ctx context.Context, orderID uuid.UUID) (domain.Parcel, []CarrierQuotes, error) {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return domain.Parcel{}, nil, err
+	}
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return domain.Parcel{}, nil, err
+	}
+
+	parcel := domain.ParcelFor(items)
+	var results []CarrierQuotes
+	for _, carrier := range s.registry.Carriers() {
+		if carrier.Quoter == nil {
+			continue
+		}
+		result := CarrierQuotes{Carrier: carrier.Code}
+		quotes, err := carrier.Quoter.Quote(ctx, parcel, order.ShippingAddress)
+		if err != nil {
+			s.logger.Warn("Failed to get shipping quotes",
+				zap.String("order_id", order.ID.String()),
+				zap.String("carrier", carrier.Code),
+				zap.Error(err),
+			)
+			result.Error = "carrier rate API failed"
+		}
+		result.Quotes = quotes
+		results = append(results, result)
+	}
+
+	return parcel, results, nil
+}
@@ -13,25 +13,75 @@ import (
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
 	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
+// EventTypeShopifyOperationPending records a Shopify call that was not sent because of dry-run mode
+const EventTypeShopifyOperationPending = "shopify_operation_pending"
+
 type shopifyService struct {
 	client  *shopify.Client
 	repos   *repository.Repositories
 	logger  *zap.Logger
+	// linkCustomers attaches draft orders to Shopify customers (SHOPIFY_LINK_CUSTOMERS)
+	linkCustomers bool
+	taxMode       string
+	tagTemplates  []string
 }
 
 // NewShopifyService creates a new Shopify service
 func NewShopifyService(cfg config.ShopifyConfig, repos *repository.Repositories, logger *zap.Logger) *shopifyService {
 	return &shopifyService{
-		client: shopify.NewClient(cfg, logger),
-		repos:  repos,
-		logger: logger,
+		client:        shopify.NewClient(cfg, logger),
+		repos:         repos,
+		logger:        logger,
+		linkCustomers: cfg.LinkCustomers,
+		taxMode:       cfg.TaxMode,
+		tagTemplates:  cfg.TagTemplates,
+	}
+}
+
+// DeleteDraftOrder deletes a draft order that was not completed. In dry-run mode it returns
+// shopify.ErrDryRun.
+func (s *shopifyService) DeleteDraftOrder(ctx context.Context, draftOrderID int64) error {
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"id": fmt.Sprintf("gid://shopify/DraftOrder/%d", draftOrderID),
+		},
+	}
+
+	resp, err := s.client.Execute(shopify.DraftOrderDeleteMutation, variables)
+	if err == shopify.ErrDryRun {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete draft order: %w", err)
+	}
+
+	var result struct {
+		DraftOrderDelete struct {
+			DeletedID  string `json:"deletedId"`
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"draftOrderDelete"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("failed to parse draft order delete response: %w", err)
+	}
+
+	if len(result.DraftOrderDelete.UserErrors) > 0 {
+		return fmt.Errorf("shopify user errors: %v", result.DraftOrderDelete.UserErrors)
 	}
+
+	return nil
 }
 
-// CompleteDraftOrder completes a Shopify draft order and returns the Shopify Order numeric ID.
-func (s *shopifyService) CompleteDraftOrder(ctx context.Context, draftOrderID int64) (int64, error) {
+// CompleteDraftOrder completes a Shopify draft order and returns the Shopify Order numeric ID
+// and name (e.g. #1001).
+func (s *shopifyService) CompleteDraftOrder(ctx context.Context, draftOrderID int64) (int64, string, error) {
 	draftOrderGID := fmt.Sprintf("gid://shopify/DraftOrder/%d", draftOrderID)
 	variables := map[string]interface{}{
 		"id": draftOrderGID,
@@ -39,7 +89,7 @@ func (s *shopifyService) CompleteDraftOrder(ctx context.Context, draftOrderID in
 
 	resp, err := s.client.Execute(shopify.DraftOrderCompleteMutation, variables)
 	if err != nil {
-		return 0, fmt.Errorf("failed to complete draft order: %w", err)
+		return 0, "", fmt.Errorf("failed to complete draft order: %w", err)
 	}
 
 	// resp.Data is already the "data" object from GraphQL response
@@ -48,7 +98,8 @@ func (s *shopifyService) CompleteDraftOrder(ctx context.Context, draftOrderID in
 			DraftOrder struct {
 				ID    string `json:"id"`
 				Order struct {
-					ID string `json:"id"`
+					ID   string `json:"id"`
+					Name string `json:"name"`
 				} `json:"order"`
 			} `json:"draftOrder"`
 			UserErrors []struct {
@@ -59,70 +110,509 @@ func (s *shopifyService) CompleteDraftOrder(ctx context.Context, draftOrderID in
 	}
 
 	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return 0, fmt.Errorf("failed to parse draft order complete response: %w", err)
+		return 0, "", fmt.Errorf("failed to parse draft order complete response: %w", err)
 	}
 
 	if len(result.DraftOrderComplete.UserErrors) > 0 {
-		return 0, fmt.Errorf("shopify user errors: %v", result.DraftOrderComplete.UserErrors)
+		return 0, "", fmt.Errorf("shopify user errors: %v", result.DraftOrderComplete.UserErrors)
 	}
 
 	// Extract numeric Order ID from GID (gid://shopify/Order/123)
 	orderGID := result.DraftOrderComplete.DraftOrder.Order.ID
 	orderID, err := extractIDFromGID(orderGID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to extract order ID: %w", err)
+		return 0, "", fmt.Errorf("failed to extract order ID: %w", err)
 	}
-	return orderID, nil
+	return orderID, result.DraftOrderComplete.DraftOrder.Order.Name, nil
 }
 
-// CreateDraftOrder creates a Shopify draft order from a supplier order
-func (s *shopifyService) CreateDraftOrder(
-	ctx context.Context,
-	order *domain.SupplierOrder,
-	items []*domain.SupplierOrderItem,
-	partnerName string,
-) (int64, error) {
-	// Build line items
-	lineItems := make([]shopify.DraftOrderLineItemInput, 0, len(items))
-	
-	for _, item := range items {
-		if item.IsSupplierItem && item.ShopifyVariantID != nil {
-			// Supplier item - use variant
-			variantIDStr := fmt.Sprintf("gid://shopify/ProductVariant/%d", *item.ShopifyVariantID)
-			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
-				VariantID: &variantIDStr,
-				Quantity:  item.Quantity,
-			})
-		} else {
-			// Non-supplier item - use custom line item
-			priceStr := fmt.Sprintf("%.2f", item.Price)
-			title := item.Title
-			if item.ProductURL != nil {
-				title = fmt.Sprintf("%s (URL: %s)", title, *item.ProductURL)
+// SendDraftOrderInvoice emails the draft order's invoice, to the given address or else to the
+// draft order's customer, and returns the invoice (checkout) URL
+func (s *shopifyService) SendDraftOrderInvoice(ctx context.Context, draftOrderID int64, email *string) (string, error) {
+	variables := map[string]interface{}{
+		"id": fmt.Sprintf("gid://shopify/DraftOrder/%d", draftOrderID),
+	}
+	if email != nil && *email != "" {
+		variables["email"] = shopify.EmailInput{To: *email}
+	}
+
+	resp, err := s.client.Execute(shopify.DraftOrderInvoiceSendMutation, variables)
+	if err != nil {
+		return "", fmt.Errorf("failed to send draft order invoice: %w", err)
+	}
+
+	var result struct {
+		DraftOrderInvoiceSend struct {
+			DraftOrder struct {
+				InvoiceURL string `json:"invoiceUrl"`
+			} `json:"draftOrder"`
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"draftOrderInvoiceSend"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return "", fmt.Errorf("failed to parse draft order invoice response: %w", err)
+	}
+
+	if len(result.DraftOrderInvoiceSend.UserErrors) > 0 {
+		return "", fmt.Errorf("shopify user errors: %v", result.DraftOrderInvoiceSend.UserErrors)
+	}
+
+	return result.DraftOrderInvoiceSend.DraftOrder.InvoiceURL, nil
+}
+
+// CompleteOrder completes the order's draft order into a real Shopify Order (so it shows
+// under Orders, not Drafts), stores the Shopify order ID and name and links the Shopify
+// order back to us via b2b metafields
+func (s *shopifyService) CompleteOrder(ctx context.Context, order *domain.SupplierOrder) error {
+	shopifyOrderID, shopifyOrderName, err := s.CompleteDraftOrder(ctx, *order.ShopifyDraftOrderID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repos.SupplierOrder.UpdateShopifyOrderID(ctx, order.ID, shopifyOrderID, shopifyOrderName); err != nil {
+		s.logger.Warn("Failed to update order with Shopify order ID", zap.Error(err))
+	}
+	order.ShopifyOrderID = &shopifyOrderID
+	if shopifyOrderName != "" {
+		order.ShopifyOrderName = &shopifyOrderName
+	}
+
+	if err := s.SetOrderLinkageMetafields(ctx, shopifyOrderID, order); err != nil {
+		s.logger.Warn("Failed to set Shopify order metafields", zap.Error(err))
+	}
+
+	return nil
+}
+
+// Metafield keys written to Shopify orders (namespace shopify.MetafieldNamespace)
+const (
+	MetafieldKeySupplierOrderID = "supplier_order_id"
+	MetafieldKeyPartnerOrderID  = "partner_order_id"
+)
+
+// SetOrderLinkageMetafields writes the supplier_order_id and partner_order_id into
+// metafields on the Shopify order so support can jump from Shopify back to our order.
+func (s *shopifyService) SetOrderLinkageMetafields(ctx context.Context, shopifyOrderID int64, order *domain.SupplierOrder) error {
+	orderGID := fmt.Sprintf("gid://shopify/Order/%d", shopifyOrderID)
+	metafields := []shopify.MetafieldsSetInput{
+		{
+			OwnerID:   orderGID,
+			Namespace: shopify.MetafieldNamespace,
+			Key:       MetafieldKeySupplierOrderID,
+			Type:      "single_line_text_field",
+			Value:     order.ID.String(),
+		},
+		{
+			OwnerID:   orderGID,
+			Namespace: shopify.MetafieldNamespace,
+			Key:       MetafieldKeyPartnerOrderID,
+			Type:      "single_line_text_field",
+			Value:     order.PartnerOrderID,
+		},
+	}
+
+	variables := map[string]interface{}{
+		"metafields": metafields,
+	}
+
+	resp, err := s.client.Execute(shopify.MetafieldsSetMutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to set order metafields: %w", err)
+	}
+
+	var result struct {
+		MetafieldsSet struct {
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"metafieldsSet"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("failed to parse metafields set response: %w", err)
+	}
+
+	if len(result.MetafieldsSet.UserErrors) > 0 {
+		return fmt.Errorf("shopify user errors: %v", result.MetafieldsSet.UserErrors)
+	}
+
+	return nil
+}
+
+// GetOrderLinkageMetafields reads the b2b metafields of a Shopify order as a key -> value map.
+func (s *shopifyService) GetOrderLinkageMetafields(ctx context.Context, shopifyOrderID int64) (map[string]string, error) {
+	variables := map[string]interface{}{
+		"id":        fmt.Sprintf("gid://shopify/Order/%d", shopifyOrderID),
+		"namespace": shopify.MetafieldNamespace,
+	}
+
+	resp, err := s.client.Execute(shopify.OrderMetafieldsQuery, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order metafields: %w", err)
+	}
+
+	var result struct {
+		Node *struct {
+			ID         string `json:"id"`
+			Metafields struct {
+				Edges []struct {
+					Node struct {
+						Key   string `json:"key"`
+						Value string `json:"value"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"metafields"`
+		} `json:"node"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse order metafields response: %w", err)
+	}
+
+	if result.Node == nil {
+		return nil, &errors.ErrNotFound{Resource: "shopify_order", ID: strconv.FormatInt(shopifyOrderID, 10)}
+	}
+
+	values := make(map[string]string, len(result.Node.Metafields.Edges))
+	for _, edge := range result.Node.Metafields.Edges {
+		values[edge.Node.Key] = edge.Node.Value
+	}
+
+	return values, nil
+}
+
+// ShopifyLineItem is a line item of a Shopify order as it is now, after any edits
+type ShopifyLineItem struct {
+	SKU       string
+	Title     string
+	VariantID *int64
+	Quantity  int
+}
+
+// GetOrderLineItems fetches the current line items of a Shopify order. Removed items
+// have a quantity of 0. edited reports whether the order was edited in Shopify at all.
+func (s *shopifyService) GetOrderLineItems(ctx context.Context, shopifyOrderID int64) (items []ShopifyLineItem, edited bool, err error) {
+	variables := map[string]interface{}{
+		"id": fmt.Sprintf("gid://shopify/Order/%d", shopifyOrderID),
+	}
+
+	resp, err := s.client.Execute(shopify.OrderLineItemsQuery, variables)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get order line items: %w", err)
+	}
+
+	var result struct {
+		Node *struct {
+			ID        string `json:"id"`
+			Edited    bool   `json:"edited"`
+			LineItems struct {
+				Edges []struct {
+					Node struct {
+						SKU             *string `json:"sku"`
+						Title           string  `json:"title"`
+						CurrentQuantity int     `json:"currentQuantity"`
+						Variant         *struct {
+							ID string `json:"id"`
+						} `json:"variant"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"lineItems"`
+		} `json:"node"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, false, fmt.Errorf("failed to parse order line items response: %w", err)
+	}
+
+	if result.Node == nil {
+		return nil, false, &errors.ErrNotFound{Resource: "shopify_order", ID: strconv.FormatInt(shopifyOrderID, 10)}
+	}
+
+	items = make([]ShopifyLineItem, 0, len(result.Node.LineItems.Edges))
+	for _, edge := range result.Node.LineItems.Edges {
+		item := ShopifyLineItem{
+			Title:    edge.Node.Title,
+			Quantity: edge.Node.CurrentQuantity,
+		}
+		if edge.Node.SKU != nil {
+			item.SKU = *edge.Node.SKU
+		}
+		if edge.Node.Variant != nil {
+			if variantID, err := extractIDFromGID(edge.Node.Variant.ID); err == nil {
+				item.VariantID = &variantID
 			}
-			
-			customAttrs := []shopify.DraftOrderAttributeInput{
-				{Key: "product_url", Value: *item.ProductURL},
+		}
+		items = append(items, item)
+	}
+
+	return items, result.Node.Edited, nil
+}
+
+// GetOrderTotal fetches the current total (after edits and refunds) of a Shopify order
+// in the shop's currency
+func (s *shopifyService) GetOrderTotal(ctx context.Context, shopifyOrderID int64) (total float64, currency string, err error) {
+	variables := map[string]interface{}{
+		"id": fmt.Sprintf("gid://shopify/Order/%d", shopifyOrderID),
+	}
+
+	resp, err := s.client.Execute(shopify.OrderTotalsQuery, variables)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to get order totals: %w", err)
+	}
+
+	var result struct {
+		Node *struct {
+			CurrentTotalPriceSet struct {
+				ShopMoney struct {
+					Amount       string `json:"amount"`
+					CurrencyCode string `json:"currencyCode"`
+				} `json:"shopMoney"`
+			} `json:"currentTotalPriceSet"`
+		} `json:"node"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return 0, "", fmt.Errorf("failed to parse order totals response: %w", err)
+	}
+
+	if result.Node == nil {
+		return 0, "", &errors.ErrNotFound{Resource: "shopify_order", ID: strconv.FormatInt(shopifyOrderID, 10)}
+	}
+
+	money := result.Node.CurrentTotalPriceSet.ShopMoney
+	total, err = strconv.ParseFloat(money.Amount, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid order total %q: %w", money.Amount, err)
+	}
+	return total, money.CurrencyCode, nil
+}
+
+// ShopifyOrderSnapshot is a Shopify order as it is now
+type ShopifyOrderSnapshot struct {
+	Name              string
+	Cancelled         bool
+	FulfillmentStatus string // displayFulfillmentStatus, e.g. UNFULFILLED, FULFILLED
+	FinancialStatus   string // displayFinancialStatus, e.g. PENDING, PAID
+	Total             float64
+	Currency          string
+	// ShippingAddress is nil when the order has none
+	ShippingAddress *domain.Address
+	LineItems       []ShopifyLineItem
+	TrackingNumbers []string
+}
+
+// GetOrderSnapshot fetches the current line items, total, shipping address and fulfillment
+// state of a Shopify order
+func (s *shopifyService) GetOrderSnapshot(ctx context.Context, shopifyOrderID int64) (*ShopifyOrderSnapshot, error) {
+	variables := map[string]interface{}{
+		"id": fmt.Sprintf("gid://shopify/Order/%d", shopifyOrderID),
+	}
+
+	resp, err := s.client.Execute(shopify.OrderSnapshotQuery, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	var result struct {
+		Node *struct {
+			Name                     string  `json:"name"`
+			CancelledAt              *string `json:"cancelledAt"`
+			DisplayFulfillmentStatus string  `json:"displayFulfillmentStatus"`
+			DisplayFinancialStatus   string  `json:"displayFinancialStatus"`
+			CurrentTotalPriceSet     struct {
+				ShopMoney struct {
+					Amount       string `json:"amount"`
+					CurrencyCode string `json:"currencyCode"`
+				} `json:"shopMoney"`
+			} `json:"currentTotalPriceSet"`
+			ShippingAddress *struct {
+				Address1      string  `json:"address1"`
+				Address2      *string `json:"address2"`
+				City          string  `json:"city"`
+				Province      *string `json:"province"`
+				Zip           string  `json:"zip"`
+				CountryCodeV2 string  `json:"countryCodeV2"`
+			} `json:"shippingAddress"`
+			LineItems struct {
+				Edges []struct {
+					Node struct {
+						SKU             *string `json:"sku"`
+						Title           string  `json:"title"`
+						CurrentQuantity int     `json:"currentQuantity"`
+						Variant         *struct {
+							ID string `json:"id"`
+						} `json:"variant"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"lineItems"`
+			Fulfillments []struct {
+				Status       string `json:"status"`
+				TrackingInfo []struct {
+					Number *string `json:"number"`
+				} `json:"trackingInfo"`
+			} `json:"fulfillments"`
+		} `json:"node"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+
+	if result.Node == nil {
+		return nil, &errors.ErrNotFound{Resource: "shopify_order", ID: strconv.FormatInt(shopifyOrderID, 10)}
+	}
+	node := result.Node
+
+	snapshot := &ShopifyOrderSnapshot{
+		Name:              node.Name,
+		Cancelled:         node.CancelledAt != nil,
+		FulfillmentStatus: node.DisplayFulfillmentStatus,
+		FinancialStatus:   node.DisplayFinancialStatus,
+		Currency:          node.CurrentTotalPriceSet.ShopMoney.CurrencyCode,
+	}
+	amount := node.CurrentTotalPriceSet.ShopMoney.Amount
+	if snapshot.Total, err = strconv.ParseFloat(amount, 64); err != nil {
+		return nil, fmt.Errorf("invalid order total %q: %w", amount, err)
+	}
+
+	if addr := node.ShippingAddress; addr != nil {
+		snapshot.ShippingAddress = &domain.Address{
+			Street:     addr.Address1,
+			Address2:   addr.Address2,
+			City:       addr.City,
+			State:      addr.Province,
+			PostalCode: addr.Zip,
+			Country:    addr.CountryCodeV2,
+		}
+	}
+
+	for _, edge := range node.LineItems.Edges {
+		item := ShopifyLineItem{
+			Title:    edge.Node.Title,
+			Quantity: edge.Node.CurrentQuantity,
+		}
+		if edge.Node.SKU != nil {
+			item.SKU = *edge.Node.SKU
+		}
+		if edge.Node.Variant != nil {
+			if variantID, err := extractIDFromGID(edge.Node.Variant.ID); err == nil {
+				item.VariantID = &variantID
 			}
-			if item.ProductURL == nil {
-				customAttrs = []shopify.DraftOrderAttributeInput{}
+		}
+		snapshot.LineItems = append(snapshot.LineItems, item)
+	}
+
+	for _, fulfillment := range node.Fulfillments {
+		// Cancelled fulfillments no longer ship anything
+		if fulfillment.Status == "CANCELLED" {
+			continue
+		}
+		for _, tracking := range fulfillment.TrackingInfo {
+			if tracking.Number != nil && *tracking.Number != "" {
+				snapshot.TrackingNumbers = append(snapshot.TrackingNumbers, *tracking.Number)
 			}
-			
-			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
-				Title:  &title,
-				OriginalUnitPrice: &priceStr,
-				Quantity: item.Quantity,
-				CustomAttributes: customAttrs,
-			})
 		}
 	}
 
+	return snapshot, nil
+}
+
+// ShopifyVariant is the price and stock of a product variant
+type ShopifyVariant struct {
+	ID               int64
+	Price            float64
+	AvailableForSale bool
+	// InventoryQuantity is nil when the variant's inventory is not tracked
+	InventoryQuantity *int
+	// ContinueSelling means the variant can be ordered when out of stock
+	ContinueSelling bool
+}
+
+// GetVariants fetches price and stock of variants by ID. Unknown variants are missing
+// from the result. In dry-run mode it returns shopify.ErrDryRun.
+func (s *shopifyService) GetVariants(ctx context.Context, variantIDs []int64) (map[int64]*ShopifyVariant, error) {
+	ids := make([]string, len(variantIDs))
+	for i, id := range variantIDs {
+		ids[i] = fmt.Sprintf("gid://shopify/ProductVariant/%d", id)
+	}
+
+	resp, err := s.client.Execute(shopify.VariantsQuery, map[string]interface{}{"ids": ids})
+	if err == shopify.ErrDryRun {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	var result struct {
+		Nodes []*struct {
+			ID                string `json:"id"`
+			Price             string `json:"price"`
+			AvailableForSale  bool   `json:"availableForSale"`
+			InventoryQuantity *int   `json:"inventoryQuantity"`
+			InventoryPolicy   string `json:"inventoryPolicy"`
+			InventoryItem     struct {
+				Tracked bool `json:"tracked"`
+			} `json:"inventoryItem"`
+		} `json:"nodes"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse variants response: %w", err)
+	}
+
+	variants := make(map[int64]*ShopifyVariant, len(result.Nodes))
+	for _, node := range result.Nodes {
+		// Unknown IDs come back as null nodes
+		if node == nil || node.ID == "" {
+			continue
+		}
+		id, err := extractIDFromGID(node.ID)
+		if err != nil {
+			continue
+		}
+		price, err := strconv.ParseFloat(node.Price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q for variant %d", node.Price, id)
+		}
+		variant := &ShopifyVariant{
+			ID:               id,
+			Price:            price,
+			AvailableForSale: node.AvailableForSale,
+			ContinueSelling:  node.InventoryPolicy == "CONTINUE",
+		}
+		if node.InventoryItem.Tracked {
+			variant.InventoryQuantity = node.InventoryQuantity
+		}
+		variants[id] = variant
+	}
+
+	return variants, nil
+}
+
+// CreateDraftOrder creates a Shopify draft order from a supplier order and returns its
+// numeric ID and name (e.g. #D12)
+func (s *shopifyService) CreateDraftOrder(
+	ctx context.Context,
+	order *domain.SupplierOrder,
+	items []*domain.SupplierOrderItem,
+	partnerName string,
+) (int64, string, error) {
+	lineItems := draftOrderLineItems(items)
+
 	// Build shipping address
 	shippingAddr := shopify.DraftOrderAddressInput{
-		Address1: getStringFromMap(order.ShippingAddress, "street"),
-		City:     getStringFromMap(order.ShippingAddress, "city"),
-		Zip:      getStringFromMap(order.ShippingAddress, "postal_code"),
-		Country:  getStringFromMap(order.ShippingAddress, "country"),
+		Address1: order.ShippingAddress.Street,
+		Address2: order.ShippingAddress.Address2,
+		City:     order.ShippingAddress.City,
+		Province: order.ShippingAddress.State,
+		Zip:      order.ShippingAddress.PostalCode,
+		Country:  order.ShippingAddress.Country,
 	}
 	
 	// Parse customer name (assume "FirstName LastName" or just "Name")
@@ -135,21 +625,15 @@ func (s *shopifyService) CreateDraftOrder(
 		}
 	}
 	
-	if state, ok := order.ShippingAddress["state"].(string); ok && state != "" {
-		shippingAddr.Province = &state
-	}
-	
-	if order.CustomerPhone != "" {
+	if order.CustomerPhoneE164 != nil {
+		shippingAddr.Phone = order.CustomerPhoneE164
+	} else if order.CustomerPhone != "" {
 		shippingAddr.Phone = &order.CustomerPhone
 	}
 
 	// Build tags
-	tags := []string{
-		fmt.Sprintf("partner:%s", partnerName),
-		fmt.Sprintf("partner_order:%s", order.PartnerOrderID),
-		"pending_confirmation",
-	}
-	
+	tags := s.draftOrderTags(order, partnerName)
+
 	// Check if mixed cart (has both supplier and non-supplier items)
 	hasSupplierItems := false
 	hasNonSupplierItems := false
@@ -170,7 +654,56 @@ func (s *shopifyService) CreateDraftOrder(
 		LineItems:      lineItems,
 		ShippingAddress: &shippingAddr,
 		Tags:           tags,
-		Note:           stringPtr(fmt.Sprintf("Partner Order ID: %s", order.PartnerOrderID)),
+		Note:           stringPtr(draftOrderNote(order)),
+		CustomAttributes: draftOrderAttributes(order),
+	}
+
+	// Tax: calculated by Shopify (default), none, or the partner's amount as its own line
+	switch s.taxMode {
+	case config.TaxModeExempt:
+		input.TaxExempt = boolPtr(true)
+	case config.TaxModeCart:
+		input.TaxExempt = boolPtr(true)
+		input.LineItems = s.appendTaxLineItem(input.LineItems, order)
+	}
+
+	// Dry-run: keep what would have been sent on the order timeline instead
+	if s.client.DryRun() {
+		event := &domain.OrderEvent{
+			SupplierOrderID: order.ID,
+			EventType:       EventTypeShopifyOperationPending,
+			EventData: map[string]interface{}{
+				"operation": "draftOrderCreate",
+				"input":     input,
+			},
+		}
+		if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+			s.logger.Warn("Failed to record dry-run Shopify operation", zap.Error(err))
+		}
+		s.logger.Info("Shopify dry-run: draft order recorded as pending operation",
+			zap.String("order_id", order.ID.String()),
+			zap.Int("line_items", len(lineItems)),
+		)
+		return 0, "", shopify.ErrDryRun
+	}
+
+	// Attach the order to the customer so repeat customers build up a history in Shopify.
+	// Without a customer the order is still created, just anonymous.
+	if s.linkCustomers {
+		customerID, err := s.FindOrCreateCustomer(ctx, order)
+		if err != nil {
+			s.logger.Warn("Failed to link Shopify customer",
+				zap.String("order_id", order.ID.String()),
+				zap.Error(err),
+			)
+		} else if customerID != 0 {
+			customerGID := fmt.Sprintf("gid://shopify/Customer/%d", customerID)
+			input.CustomerID = &customerGID
+			order.ShopifyCustomerID = &customerID
+			if err := s.repos.SupplierOrder.UpdateShopifyCustomerID(ctx, order.ID, customerID); err != nil {
+				s.logger.Warn("Failed to update order with Shopify customer ID", zap.Error(err))
+			}
+		}
 	}
 
 	// Execute mutation
@@ -180,7 +713,7 @@ func (s *shopifyService) CreateDraftOrder(
 
 	resp, err := s.client.Execute(shopify.DraftOrderCreateMutation, variables)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create draft order: %w", err)
+		return 0, "", fmt.Errorf("failed to create draft order: %w", err)
 	}
 
 	// Parse response to get draft order ID
@@ -189,7 +722,8 @@ func (s *shopifyService) CreateDraftOrder(
 	var result struct {
 		DraftOrderCreate struct {
 			DraftOrder struct {
-				ID string `json:"id"`
+				ID   string `json:"id"`
+				Name string `json:"name"`
 			} `json:"draftOrder"`
 			UserErrors []struct {
 				Field   []string `json:"field"`
@@ -199,35 +733,343 @@ func (s *shopifyService) CreateDraftOrder(
 	}
 
 	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return 0, fmt.Errorf("failed to parse draft order response: %w", err)
+		return 0, "", fmt.Errorf("failed to parse draft order response: %w", err)
 	}
 
 	if len(result.DraftOrderCreate.UserErrors) > 0 {
-		return 0, fmt.Errorf("shopify user errors: %v", result.DraftOrderCreate.UserErrors)
+		return 0, "", fmt.Errorf("shopify user errors: %v", result.DraftOrderCreate.UserErrors)
 	}
 
 	// Extract numeric ID from GID
 	draftOrderGID := result.DraftOrderCreate.DraftOrder.ID
 	draftOrderID, err := extractIDFromGID(draftOrderGID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to extract draft order ID: %w", err)
+		return 0, "", fmt.Errorf("failed to extract draft order ID: %w", err)
+	}
+
+	return draftOrderID, result.DraftOrderCreate.DraftOrder.Name, nil
+}
+
+// UpdateDraftOrderLineItems replaces the line items of the order's open draft order with the
+// given items, e.g. after a substitution was accepted. In dry-run mode the update is recorded
+// as a pending operation and shopify.ErrDryRun is returned.
+func (s *shopifyService) UpdateDraftOrderLineItems(
+	ctx context.Context,
+	draftOrderID int64,
+	order *domain.SupplierOrder,
+	items []*domain.SupplierOrderItem,
+) error {
+	lineItems := draftOrderLineItems(items)
+	if s.taxMode == config.TaxModeCart {
+		lineItems = s.appendTaxLineItem(lineItems, order)
+	}
+	input := map[string]interface{}{
+		"lineItems": lineItems,
+	}
+
+	if s.client.DryRun() {
+		event := &domain.OrderEvent{
+			SupplierOrderID: order.ID,
+			EventType:       EventTypeShopifyOperationPending,
+			EventData: map[string]interface{}{
+				"operation":      "draftOrderUpdate",
+				"draft_order_id": draftOrderID,
+				"input":          input,
+			},
+		}
+		if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+			s.logger.Warn("Failed to record dry-run Shopify operation", zap.Error(err))
+		}
+		return shopify.ErrDryRun
+	}
+
+	variables := map[string]interface{}{
+		"id":    fmt.Sprintf("gid://shopify/DraftOrder/%d", draftOrderID),
+		"input": input,
+	}
+
+	resp, err := s.client.Execute(shopify.DraftOrderUpdateMutation, variables)
+	if err != nil {
+		return fmt.Errorf("failed to update draft order: %w", err)
+	}
+
+	var result struct {
+		DraftOrderUpdate struct {
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"draftOrderUpdate"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return fmt.Errorf("failed to parse draft order update response: %w", err)
+	}
+
+	if len(result.DraftOrderUpdate.UserErrors) > 0 {
+		return fmt.Errorf("shopify user errors: %v", result.DraftOrderUpdate.UserErrors)
+	}
+
+	return nil
+}
+
+// draftOrderLineItems builds the draft order line items of an order's items. Supplier items
+// are variant lines priced by Shopify; other items are custom lines at the partner's price.
+func draftOrderLineItems(items []*domain.SupplierOrderItem) []shopify.DraftOrderLineItemInput {
+	lineItems := make([]shopify.DraftOrderLineItemInput, 0, len(items))
+	for _, item := range items {
+		if item.IsSupplierItem && item.ShopifyVariantID != nil {
+			// Supplier item - use variant
+			variantIDStr := fmt.Sprintf("gid://shopify/ProductVariant/%d", *item.ShopifyVariantID)
+			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
+				VariantID: &variantIDStr,
+				Quantity:  item.Quantity,
+			})
+		} else {
+			// Non-supplier item - use custom line item
+			priceStr := fmt.Sprintf("%.2f", item.Price)
+			title := item.Title
+			customAttrs := []shopify.DraftOrderAttributeInput{}
+			if item.ProductURL != nil {
+				title = fmt.Sprintf("%s (URL: %s)", title, *item.ProductURL)
+				customAttrs = append(customAttrs, shopify.DraftOrderAttributeInput{Key: "product_url", Value: *item.ProductURL})
+			}
+
+			lineItems = append(lineItems, shopify.DraftOrderLineItemInput{
+				Title:             &title,
+				OriginalUnitPrice: &priceStr,
+				Quantity:          item.Quantity,
+				CustomAttributes:  customAttrs,
+			})
+		}
+	}
+	return lineItems
+}
+
+// appendTaxLineItem adds the partner's cart tax as its own line (TAX_MODE=cart)
+func (s *shopifyService) appendTaxLineItem(lineItems []shopify.DraftOrderLineItemInput, order *domain.SupplierOrder) []shopify.DraftOrderLineItemInput {
+	if order.CartTax <= 0 {
+		return lineItems
+	}
+	return append(lineItems, shopify.DraftOrderLineItemInput{
+		Title:             stringPtr("Tax"),
+		OriginalUnitPrice: stringPtr(fmt.Sprintf("%.2f", order.CartTax)),
+		Quantity:          1,
+		Taxable:           boolPtr(false),
+		RequiresShipping:  boolPtr(false),
+	})
+}
+
+// FindDraftOrder returns the ID and name of the draft order CreateDraftOrder made for the
+// order, found by its tags, or 0 when there is none. Shopify indexes new drafts with a short delay.
+func (s *shopifyService) FindDraftOrder(ctx context.Context, order *domain.SupplierOrder, partnerName string) (int64, string, error) {
+	tags := s.draftOrderTags(order, partnerName)
+	query := fmt.Sprintf("tag:%q AND tag:%q", tags[0], tags[1])
+	resp, err := s.client.Execute(shopify.DraftOrdersSearchQuery, map[string]interface{}{"query": query})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to search draft orders: %w", err)
+	}
+
+	var result struct {
+		DraftOrders struct {
+			Edges []struct {
+				Node struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"draftOrders"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return 0, "", fmt.Errorf("failed to parse draft orders response: %w", err)
+	}
+
+	if len(result.DraftOrders.Edges) == 0 {
+		return 0, "", nil
+	}
+	node := result.DraftOrders.Edges[0].Node
+	draftOrderID, err := extractIDFromGID(node.ID)
+	if err != nil {
+		return 0, "", err
+	}
+	return draftOrderID, node.Name, nil
+}
+
+// FindOrCreateCustomer returns the Shopify customer with the order's phone (E.164) or
+// email, creating one when there is none. It returns 0 when the order has neither.
+func (s *shopifyService) FindOrCreateCustomer(ctx context.Context, order *domain.SupplierOrder) (int64, error) {
+	var searches []string
+	if order.CustomerPhoneE164 != nil {
+		searches = append(searches, fmt.Sprintf("phone:%q", *order.CustomerPhoneE164))
+	}
+	if order.CustomerEmail != nil {
+		searches = append(searches, fmt.Sprintf("email:%q", *order.CustomerEmail))
+	}
+	if len(searches) == 0 {
+		return 0, nil
+	}
+
+	for _, query := range searches {
+		customerID, err := s.findCustomer(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		if customerID != 0 {
+			return customerID, nil
+		}
 	}
 
-	return draftOrderID, nil
+	return s.createCustomer(ctx, order)
+}
+
+func (s *shopifyService) findCustomer(ctx context.Context, query string) (int64, error) {
+	resp, err := s.client.Execute(shopify.CustomersSearchQuery, map[string]interface{}{"query": query})
+	if err != nil {
+		return 0, fmt.Errorf("failed to search customers: %w", err)
+	}
+
+	var result struct {
+		Customers struct {
+			Edges []struct {
+				Node struct {
+					ID string `json:"id"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"customers"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse customers response: %w", err)
+	}
+
+	if len(result.Customers.Edges) == 0 {
+		return 0, nil
+	}
+	return extractIDFromGID(result.Customers.Edges[0].Node.ID)
+}
+
+func (s *shopifyService) createCustomer(ctx context.Context, order *domain.SupplierOrder) (int64, error) {
+	input := shopify.CustomerInput{
+		Email: order.CustomerEmail,
+		Phone: order.CustomerPhoneE164,
+		Tags:  []string{"b2b"},
+	}
+	nameParts := strings.Fields(order.CustomerName)
+	if len(nameParts) > 0 {
+		input.FirstName = &nameParts[0]
+		if len(nameParts) > 1 {
+			lastName := strings.Join(nameParts[1:], " ")
+			input.LastName = &lastName
+		}
+	}
+
+	resp, err := s.client.Execute(shopify.CustomerCreateMutation, map[string]interface{}{"input": input})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	var result struct {
+		CustomerCreate struct {
+			Customer *struct {
+				ID string `json:"id"`
+			} `json:"customer"`
+			UserErrors []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"customerCreate"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return 0, fmt.Errorf("failed to parse customer create response: %w", err)
+	}
+
+	if len(result.CustomerCreate.UserErrors) > 0 {
+		return 0, fmt.Errorf("shopify user errors: %v", result.CustomerCreate.UserErrors)
+	}
+	if result.CustomerCreate.Customer == nil {
+		return 0, fmt.Errorf("customer create returned no customer")
+	}
+
+	return extractIDFromGID(result.CustomerCreate.Customer.ID)
 }
 
 // Helper functions
-func getStringFromMap(m map[string]interface{}, key string) string {
-	if val, ok := m[key].(string); ok {
-		return val
+// draftOrderNote builds the draft order note, including the partner's delivery notes
+// draftOrderTags are the tags every draft order gets, cut to Shopify's tag limits: the
+// partner and partner order tags FindDraftOrder searches by, then the configured templates
+func (s *shopifyService) draftOrderTags(order *domain.SupplierOrder, partnerName string) []string {
+	tags := []string{
+		shopify.SanitizeTag(fmt.Sprintf("partner:%s", partnerName)),
+		shopify.SanitizeTag(fmt.Sprintf("partner_order:%s", order.PartnerOrderID)),
+	}
+	values := tagTemplateValues(order, partnerName)
+	for _, template := range s.tagTemplates {
+		if tag := shopify.RenderTagTemplate(template, values); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// tagTemplateValues are the order fields tag templates can use (config.TagTemplateFields)
+func tagTemplateValues(order *domain.SupplierOrder, partnerName string) map[string]string {
+	return map[string]string{
+		"name":             partnerName,
+		"partner_order_id": order.PartnerOrderID,
+		"reference":        stringValue(order.Reference),
+		"channel":          stringValue(order.Channel),
+		"payment_method":   stringValue(order.PaymentMethod),
+		"locale":           string(order.Locale),
+		"shipping.country": order.ShippingAddress.Country,
+		"shipping.city":    order.ShippingAddress.City,
+		"shipping.state":   stringValue(order.ShippingAddress.State),
 	}
-	return ""
+}
+
+// draftOrderAttributes carry the partner context to Shopify in full, where tags would be
+// cut: they show under "Additional details" and are copied to the completed order
+func draftOrderAttributes(order *domain.SupplierOrder) []shopify.DraftOrderAttributeInput {
+	attrs := []shopify.DraftOrderAttributeInput{
+		{Key: "partner_id", Value: order.PartnerID.String()},
+		{Key: "supplier_order_id", Value: order.ID.String()},
+		{Key: "partner_order_id", Value: order.PartnerOrderID},
+	}
+	if order.Reference != nil {
+		attrs = append(attrs, shopify.DraftOrderAttributeInput{Key: "b2b_reference", Value: *order.Reference})
+	}
+	if order.PaymentMethod != nil && *order.PaymentMethod != "" {
+		attrs = append(attrs, shopify.DraftOrderAttributeInput{Key: "payment_method", Value: *order.PaymentMethod})
+	}
+	if order.Channel != nil && *order.Channel != "" {
+		attrs = append(attrs, shopify.DraftOrderAttributeInput{Key: "partner_channel", Value: *order.Channel})
+	}
+	return attrs
+}
+
+func draftOrderNote(order *domain.SupplierOrder) string {
+	note := fmt.Sprintf("Partner Order ID: %s", order.PartnerOrderID)
+	if order.Reference != nil {
+		note += fmt.Sprintf("\nB2B Reference: %s", *order.Reference)
+	}
+	if order.PaymentMethod != nil && *order.PaymentMethod != "" {
+		note += fmt.Sprintf("\nPayment: %s", domain.PaymentMethod(*order.PaymentMethod).Label())
+	}
+	if order.ShippingAddress.Notes != nil && *order.ShippingAddress.Notes != "" {
+		note += fmt.Sprintf("\nDelivery notes: %s", *order.ShippingAddress.Notes)
+	}
+	return note
 }
 
 func stringPtr(s string) *string {
 	return &s
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 func extractIDFromGID(gid string) (int64, error) {
 	// GID format: "gid://shopify/DraftOrder/123456"
 	parts := strings.Split(gid, "/")
@@ -2,45 +2,272 @@ package service
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/jafarshop/b2bapi/internal/config"
 	"github.com/jafarshop/b2bapi/internal/domain"
 	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/skunorm"
+	"github.com/jafarshop/b2bapi/pkg/errors"
 )
 
 type skuService struct {
-	repos  *repository.Repositories
-	logger *zap.Logger
+	normalizer *skunorm.Normalizer
+	repos      *repository.Repositories
+	logger     *zap.Logger
 }
 
 // NewSKUService creates a new SKU service
-func NewSKUService(repos *repository.Repositories, logger *zap.Logger) *skuService {
+func NewSKUService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *skuService {
 	return &skuService{
-		repos:  repos,
-		logger: logger,
+		normalizer: skunorm.New(cfg.SKUNormalization),
+		repos:      repos,
+		logger:     logger,
 	}
 }
 
+// Catalog restriction modes (see config.CatalogConfig)
+const (
+	CatalogRestrictionIgnore = "ignore"
+	CatalogRestrictionReject = "reject"
+)
+
 // CheckCartForSupplierSKUs checks if cart contains at least one supplier SKU
-// Returns: hasSupplierSKU, supplierItems map (SKU -> mapping), error
+// the partner is allowed to order. Items whose SKU is not an exact match are matched by
+// normalized SKU or barcode (see MatchCartItems). SKUs outside a restricted partner catalog are
+// treated as non-supplier items, or fail the cart with ErrValidation in reject mode.
+// Returns: hasSupplierSKU, supplierItems map (cart item SKU -> match), error
 func (s *skuService) CheckCartForSupplierSKUs(
 	ctx context.Context,
+	partnerID uuid.UUID,
 	items []CartItem,
-) (bool, map[string]*domain.SKUMapping, error) {
-	supplierItems := make(map[string]*domain.SKUMapping)
+	restrictionMode string,
+) (bool, map[string]*CartItemMatch, error) {
+	supplierItems := make(map[string]*CartItemMatch)
+
+	matches, err := s.MatchCartItems(ctx, partnerID, items)
+	if err != nil {
+		return false, nil, err
+	}
+	s.recordUnmatched(ctx, partnerID, matches)
+	disallowed := make(map[string]string)
+
+	for i, match := range matches {
+		switch {
+		case match.Mapping != nil:
+			supplierItems[match.Item.SKU] = &matches[i]
+		case match.Reason == SKUReasonNotInCatalog:
+			disallowed[match.Item.SKU] = "SKU is not in the partner catalog"
+		}
+	}
+
+	if len(disallowed) > 0 && restrictionMode == CatalogRestrictionReject {
+		return false, nil, &errors.ErrValidation{
+			Message: "cart contains SKUs outside the partner catalog",
+			Fields:  disallowed,
+		}
+	}
+
+	return len(supplierItems) > 0, supplierItems, nil
+}
+
+// recordUnmatched counts the cart items that did not match a supplier SKU for the
+// unmatched SKU report. Failures are logged and do not fail the cart.
+func (s *skuService) recordUnmatched(ctx context.Context, partnerID uuid.UUID, matches []CartItemMatch) {
+	seen := make(map[string]bool)
+	var unmatched []*domain.UnmatchedSKU
+	for _, match := range matches {
+		if match.Mapping != nil || seen[match.Item.SKU] {
+			continue
+		}
+		seen[match.Item.SKU] = true
+		unmatched = append(unmatched, &domain.UnmatchedSKU{
+			PartnerID: partnerID,
+			SKU:       match.Item.SKU,
+			Title:     match.Item.Title,
+			Reason:    match.Reason,
+		})
+	}
+	if len(unmatched) == 0 {
+		return
+	}
 
-	for _, item := range items {
-		mapping, err := s.repos.SKUMapping.GetBySKU(ctx, item.SKU)
+	if err := s.repos.UnmatchedSKU.Record(ctx, unmatched); err != nil {
+		s.logger.Warn("Failed to record unmatched SKUs",
+			zap.String("partner_id", partnerID.String()),
+			zap.Int("count", len(unmatched)),
+			zap.Error(err),
+		)
+	}
+}
+
+// Reasons a cart item is not a supplier item
+const (
+	SKUReasonUnknown      = "unknown_sku"
+	SKUReasonInactive     = "inactive"
+	SKUReasonNotInCatalog = "not_in_catalog"
+	// SKUReasonAmbiguousSKU: several mappings have the same normalized SKU
+	SKUReasonAmbiguousSKU = "ambiguous_sku"
+	// SKUReasonAmbiguousBarcode: the SKU is unknown and several mappings share the barcode
+	SKUReasonAmbiguousBarcode = "ambiguous_barcode"
+)
+
+// CartItemMatch is how one cart item matched the supplier SKUs
+type CartItemMatch struct {
+	Item        CartItem
+	Mapping     *domain.SKUMapping // nil when the item is not a supplier item
+	MatchMethod string             // domain.MatchMethod*, set with Mapping
+	Reason      string             // why it is not a supplier item
+}
+
+// MatchCartItems looks up every cart item in the SKU mappings and the partner's catalog.
+// Items whose SKU has no exact mapping are looked up by normalized SKU (see skunorm), then
+// by barcode: the item's barcode when given, otherwise the SKU itself (partners that only
+// know EAN/UPC codes send them as SKU).
+func (s *skuService) MatchCartItems(ctx context.Context, partnerID uuid.UUID, items []CartItem) ([]CartItemMatch, error) {
+	allowed, err := s.allowedSKUs(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]CartItemMatch, len(items))
+	for i, item := range items {
+		matches[i].Item = item
+
+		mapping, method, reason, err := s.lookup(ctx, item)
+		matches[i].Reason = reason
 		if err != nil {
-			// SKU not found or error - skip
+			// Lookup failed - not a supplier item
+			matches[i].Reason = SKUReasonUnknown
+			continue
+		}
+		if mapping == nil {
+			continue
+		}
+
+		if !mapping.IsActive {
+			matches[i].Reason = SKUReasonInactive
+			continue
+		}
+
+		if allowed != nil && !allowed[mapping.SKU] {
+			matches[i].Reason = SKUReasonNotInCatalog
 			continue
 		}
 
-		if mapping.IsActive {
-			supplierItems[item.SKU] = mapping
+		matches[i].Mapping = mapping
+		matches[i].MatchMethod = method
+	}
+
+	return matches, nil
+}
+
+// lookup finds the item's mapping by exact SKU, normalized SKU or barcode, in that order.
+// It returns how the mapping was found, or a nil mapping and the reason when none matched.
+func (s *skuService) lookup(ctx context.Context, item CartItem) (*domain.SKUMapping, string, string, error) {
+	mapping, err := s.repos.SKUMapping.GetBySKU(ctx, item.SKU)
+	if err == nil {
+		return mapping, domain.MatchMethodSKU, "", nil
+	}
+	if _, ok := err.(*errors.ErrNotFound); !ok {
+		return nil, "", "", err
+	}
+
+	mappings, err := s.repos.SKUMapping.ListByNormalizedSKU(ctx, s.normalizer.Normalize(item.SKU))
+	if err != nil {
+		return nil, "", "", err
+	}
+	if len(mappings) == 1 {
+		return mappings[0], domain.MatchMethodNormalizedSKU, "", nil
+	}
+	if len(mappings) > 1 {
+		return nil, "", SKUReasonAmbiguousSKU, nil
+	}
+
+	barcode := item.SKU
+	if item.Barcode != nil && *item.Barcode != "" {
+		barcode = *item.Barcode
+	}
+	mappings, err = s.repos.SKUMapping.ListByBarcode(ctx, strings.TrimSpace(barcode))
+	if err != nil {
+		return nil, "", "", err
+	}
+	switch len(mappings) {
+	case 0:
+		return nil, "", SKUReasonUnknown, nil
+	case 1:
+		return mappings[0], domain.MatchMethodBarcode, "", nil
+	}
+	return nil, "", SKUReasonAmbiguousBarcode, nil
+}
+
+// allowedSKUs returns the partner's catalog as a set, or nil if the partner is unrestricted
+func (s *skuService) allowedSKUs(ctx context.Context, partnerID uuid.UUID) (map[string]bool, error) {
+	catalog, err := s.repos.PartnerCatalog.ListByPartnerID(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(catalog) == 0 {
+		return nil, nil
+	}
+
+	allowed := make(map[string]bool, len(catalog))
+	for _, mapping := range catalog {
+		allowed[mapping.SKU] = true
+	}
+	return allowed, nil
+}
+
+// MissedSKUMatch is a SKU stored as a non-supplier item that now matches a mapping
+type MissedSKUMatch struct {
+	SKU         string
+	MatchedSKU  string
+	MatchMethod string
+	Orders      int
+	LastSeenAt  time.Time
+}
+
+// MissedMatches finds the non-supplier item SKUs of orders created in [from, to) that
+// the current normalization rules (or barcodes) would match to an active mapping. Mapping
+// SKUs are normalized with the current rules, so the report also previews rule changes
+// that are not yet applied to stored mappings.
+func (s *skuService) MissedMatches(ctx context.Context, from, to time.Time) ([]MissedSKUMatch, error) {
+	nonSupplier, err := s.repos.SupplierOrderItem.ListNonSupplierSKUs(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := s.repos.SKUMapping.GetAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byNormalized := make(map[string][]*domain.SKUMapping)
+	byBarcode := make(map[string][]*domain.SKUMapping)
+	for _, mapping := range mappings {
+		normalized := s.normalizer.Normalize(mapping.SKU)
+		byNormalized[normalized] = append(byNormalized[normalized], mapping)
+		if mapping.Barcode != nil {
+			byBarcode[*mapping.Barcode] = append(byBarcode[*mapping.Barcode], mapping)
 		}
 	}
 
-	return len(supplierItems) > 0, supplierItems, nil
+	missed := []MissedSKUMatch{}
+	for _, item := range nonSupplier {
+		match := MissedSKUMatch{SKU: item.SKU, Orders: item.Orders, LastSeenAt: item.LastSeenAt}
+		if candidates := byNormalized[s.normalizer.Normalize(item.SKU)]; len(candidates) == 1 {
+			match.MatchedSKU, match.MatchMethod = candidates[0].SKU, domain.MatchMethodNormalizedSKU
+		} else if candidates := byBarcode[strings.TrimSpace(item.SKU)]; len(candidates) == 1 {
+			match.MatchedSKU, match.MatchMethod = candidates[0].SKU, domain.MatchMethodBarcode
+		}
+		// Exact SKUs were left out for another reason (inactive, not in the partner catalog)
+		if match.MatchedSKU == "" || match.MatchedSKU == item.SKU {
+			continue
+		}
+		missed = append(missed, match)
+	}
+	return missed, nil
 }
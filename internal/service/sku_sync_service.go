@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/internal/skunorm"
+)
+
+// bulkPollInterval is how often a running bulk export is polled
+const bulkPollInterval = 5 * time.Second
+
+// SKUSyncResult summarizes a SKU mapping sync
+type SKUSyncResult struct {
+	Products     int
+	Variants     int
+	Upserted     int
+	SkippedNoSKU int
+}
+
+type skuSyncService struct {
+	client     *shopify.Client
+	normalizer *skunorm.Normalizer
+	repos      *repository.Repositories
+	logger     *zap.Logger
+}
+
+// NewSKUSyncService creates a new service that syncs SKU mappings from Shopify
+func NewSKUSyncService(cfg *config.Config, repos *repository.Repositories, logger *zap.Logger) *skuSyncService {
+	return &skuSyncService{
+		client:     shopify.NewClient(cfg.Shopify, logger),
+		normalizer: skunorm.New(cfg.SKUNormalization),
+		repos:      repos,
+		logger:     logger,
+	}
+}
+
+// bulkProductNode is one JSONL line of BulkProductVariantsQuery: a product, a product image or a variant
+type bulkProductNode struct {
+	ID          string   `json:"id"`
+	SKU         string   `json:"sku"`
+	Title       string   `json:"title"`
+	Vendor      string   `json:"vendor"`
+	ProductType string   `json:"productType"`
+	Barcode     string   `json:"barcode"`
+	URL         string   `json:"url"`
+	Weight      *float64 `json:"weight"`
+	WeightUnit  string   `json:"weightUnit"`
+	// FeaturedImage is set on products, Image on variants
+	FeaturedImage *struct {
+		URL string `json:"url"`
+	} `json:"featuredImage"`
+	Image *struct {
+		URL string `json:"url"`
+	} `json:"image"`
+	ParentID string `json:"__parentId"`
+}
+
+// bulkProduct collects a product's lines; its variants are upserted once all lines are read
+type bulkProduct struct {
+	node      bulkProductNode
+	imageURLs []string
+	variants  []bulkProductNode
+}
+
+// SyncFromShopify exports every product variant with a Shopify bulk operation and
+// upserts a SKU mapping, with the product details, for each variant that has a SKU
+func (s *skuSyncService) SyncFromShopify(ctx context.Context) (*SKUSyncResult, error) {
+	opID, err := s.client.RunBulkQuery(shopify.BulkProductVariantsQuery)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Started Shopify bulk export", zap.String("bulk_operation_id", opID))
+
+	op, err := s.client.WaitForBulkOperation(ctx, opID, bulkPollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SKUSyncResult{}
+	var current *bulkProduct
+	err = s.client.StreamBulkResult(ctx, op, func(line json.RawMessage) error {
+		var node bulkProductNode
+		if err := json.Unmarshal(line, &node); err != nil {
+			return fmt.Errorf("failed to parse bulk result line: %w", err)
+		}
+
+		// Products come first, followed by their images and variants (which carry __parentId)
+		if node.ParentID == "" {
+			if err := s.upsertProduct(ctx, current, result); err != nil {
+				return err
+			}
+			current = &bulkProduct{node: node}
+			result.Products++
+			return nil
+		}
+		if current == nil || node.ParentID != current.node.ID {
+			return fmt.Errorf("bulk result line for %s does not follow its product", node.ParentID)
+		}
+
+		if node.ID == "" && node.URL != "" {
+			current.imageURLs = append(current.imageURLs, node.URL)
+			return nil
+		}
+		if !strings.Contains(node.ID, "/ProductVariant/") {
+			return nil
+		}
+
+		result.Variants++
+		current.variants = append(current.variants, node)
+		return nil
+	})
+	if err == nil {
+		err = s.upsertProduct(ctx, current, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Synced SKU mappings from Shopify",
+		zap.Int("products", result.Products),
+		zap.Int("variants", result.Variants),
+		zap.Int("upserted", result.Upserted),
+		zap.Int("skipped_no_sku", result.SkippedNoSKU),
+	)
+
+	return result, nil
+}
+
+// upsertProduct upserts a SKU mapping for each variant of the product that has a SKU
+func (s *skuSyncService) upsertProduct(ctx context.Context, product *bulkProduct, result *SKUSyncResult) error {
+	if product == nil {
+		return nil
+	}
+
+	productID, err := extractIDFromGID(product.node.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, variant := range product.variants {
+		sku := strings.TrimSpace(variant.SKU)
+		if sku == "" {
+			result.SkippedNoSKU++
+			continue
+		}
+
+		variantID, err := extractIDFromGID(variant.ID)
+		if err != nil {
+			return err
+		}
+
+		mapping := &domain.SKUMapping{
+			SKU:              sku,
+			NormalizedSKU:    s.normalizer.Normalize(sku),
+			ShopifyProductID: productID,
+			ShopifyVariantID: variantID,
+			WeightGrams:      weightInGrams(variant.Weight, variant.WeightUnit),
+			ProductTitle:     optionalString(product.node.Title),
+			VariantTitle:     optionalString(variant.Title),
+			Vendor:           optionalString(product.node.Vendor),
+			ProductType:      optionalString(product.node.ProductType),
+			Barcode:          optionalString(strings.TrimSpace(variant.Barcode)),
+			ImageURL:         variantImageURL(product, variant),
+			ImageURLs:        product.imageURLs,
+			IsActive:         true,
+		}
+		if err := s.repos.SKUMapping.Upsert(ctx, mapping, domain.SKUMappingActor{Source: domain.SKUMappingSourceShopifySync}); err != nil {
+			return err
+		}
+		result.Upserted++
+	}
+	return nil
+}
+
+// variantImageURL is the variant's own image, falling back to the product's featured image
+func variantImageURL(product *bulkProduct, variant bulkProductNode) *string {
+	if variant.Image != nil && variant.Image.URL != "" {
+		return &variant.Image.URL
+	}
+	if product.node.FeaturedImage != nil && product.node.FeaturedImage.URL != "" {
+		return &product.node.FeaturedImage.URL
+	}
+	if len(product.imageURLs) > 0 {
+		return &product.imageURLs[0]
+	}
+	return nil
+}
+
+// optionalString returns nil for an empty Shopify field
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// weightInGrams converts a Shopify variant weight; nil when the variant has no weight set
+func weightInGrams(weight *float64, unit string) *int {
+	if weight == nil || *weight <= 0 {
+		return nil
+	}
+	grams := *weight
+	switch unit {
+	case "KILOGRAMS":
+		grams *= 1000
+	case "POUNDS":
+		grams *= 453.59237
+	case "OUNCES":
+		grams *= 28.349523125
+	}
+	rounded := int(math.Round(grams))
+	return &rounded
+}
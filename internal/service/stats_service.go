@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// maxRejectionReasons is how many distinct rejection reasons are reported per partner;
+// the rest are folded into "other" to keep free-text reasons from exploding metric labels
+const maxRejectionReasons = 10
+
+// PartnerFunnel is one partner's order funnel over a period
+type PartnerFunnel struct {
+	domain.OrderFunnelStats
+	// RejectionReasons counts rejected orders by (normalized) reason
+	RejectionReasons map[string]int
+	// RejectionCodes counts rejected orders by rejection code
+	RejectionCodes map[domain.RejectionCode]int
+}
+
+// ConfirmationRate returns the share of submitted orders that were confirmed
+func (f *PartnerFunnel) ConfirmationRate() float64 {
+	return ratio(f.Confirmed, f.Submitted)
+}
+
+// ShipRate returns the share of confirmed orders that were shipped
+func (f *PartnerFunnel) ShipRate() float64 {
+	return ratio(f.Shipped, f.Confirmed)
+}
+
+// DeliveryRate returns the share of shipped orders that were delivered
+func (f *PartnerFunnel) DeliveryRate() float64 {
+	return ratio(f.Delivered, f.Shipped)
+}
+
+// Conversion returns the share of submitted orders that were delivered
+func (f *PartnerFunnel) Conversion() float64 {
+	return ratio(f.Delivered, f.Submitted)
+}
+
+// RejectionRate returns the share of submitted orders that were rejected
+func (f *PartnerFunnel) RejectionRate() float64 {
+	return ratio(f.Rejected, f.Submitted)
+}
+
+// FunnelReport is the order funnel of every partner with orders in a period
+type FunnelReport struct {
+	From     time.Time
+	To       time.Time
+	Partners []*PartnerFunnel
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+type statsService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewStatsService creates a new order statistics service
+func NewStatsService(repos *repository.Repositories, logger *zap.Logger) *statsService {
+	return &statsService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// OrderFunnel builds the per-partner order funnel for orders created in [from, to)
+func (s *statsService) OrderFunnel(ctx context.Context, from, to time.Time) (*FunnelReport, error) {
+	stats, err := s.funnelStats(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons, err := s.repos.SupplierOrder.CountRejectionReasons(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reasons arrive most frequent first per partner
+	reasonsByPartner := make(map[uuid.UUID]map[string]int)
+	for _, r := range reasons {
+		counts, ok := reasonsByPartner[r.PartnerID]
+		if !ok {
+			counts = make(map[string]int)
+			reasonsByPartner[r.PartnerID] = counts
+		}
+		if len(counts) < maxRejectionReasons {
+			counts[r.Reason] = r.Count
+		} else {
+			counts["other"] += r.Count
+		}
+	}
+
+	codes, err := s.repos.SupplierOrder.CountRejectionCodes(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	codesByPartner := make(map[uuid.UUID]map[domain.RejectionCode]int)
+	for _, c := range codes {
+		if codesByPartner[c.PartnerID] == nil {
+			codesByPartner[c.PartnerID] = make(map[domain.RejectionCode]int)
+		}
+		codesByPartner[c.PartnerID][c.Code] = c.Count
+	}
+
+	report := &FunnelReport{From: from, To: to}
+	for _, st := range stats {
+		funnel := &PartnerFunnel{
+			OrderFunnelStats: *st,
+			RejectionReasons: reasonsByPartner[st.PartnerID],
+		}
+		if funnel.RejectionReasons == nil {
+			funnel.RejectionReasons = make(map[string]int)
+		}
+		funnel.RejectionCodes = codesByPartner[st.PartnerID]
+		if funnel.RejectionCodes == nil {
+			funnel.RejectionCodes = make(map[domain.RejectionCode]int)
+		}
+		report.Partners = append(report.Partners, funnel)
+	}
+
+	return report, nil
+}
+
+// PartnersFunnel builds the order funnel of only the given partners, such as the members
+// of an organization, for orders created in [from, to). Partners without orders in the
+// period are left out.
+func (s *statsService) PartnersFunnel(ctx context.Context, partnerIDs []uuid.UUID, from, to time.Time) (*FunnelReport, error) {
+	report, err := s.OrderFunnel(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[uuid.UUID]bool, len(partnerIDs))
+	for _, id := range partnerIDs {
+		members[id] = true
+	}
+	partners := report.Partners[:0]
+	for _, funnel := range report.Partners {
+		if members[funnel.PartnerID] {
+			partners = append(partners, funnel)
+		}
+	}
+	report.Partners = partners
+
+	return report, nil
+}
+
+// Total adds up the funnels of every partner in the report
+func (r *FunnelReport) Total() *PartnerFunnel {
+	total := &PartnerFunnel{
+		RejectionReasons: make(map[string]int),
+		RejectionCodes:   make(map[domain.RejectionCode]int),
+	}
+	for _, p := range r.Partners {
+		total.Submitted += p.Submitted
+		total.Confirmed += p.Confirmed
+		total.Shipped += p.Shipped
+		total.Delivered += p.Delivered
+		total.Rejected += p.Rejected
+		total.Cancelled += p.Cancelled
+		total.Revenue += p.Revenue
+		for reason, count := range p.RejectionReasons {
+			total.RejectionReasons[reason] += count
+		}
+		for code, count := range p.RejectionCodes {
+			total.RejectionCodes[code] += count
+		}
+	}
+	return total
+}
+
+// funnelStats reads the whole days of the period from the daily rollups and only the partial
+// days at either end from supplier_orders. If a day in between has not been rolled up, the
+// whole period is counted from supplier_orders.
+func (s *statsService) funnelStats(ctx context.Context, from, to time.Time) ([]*domain.OrderFunnelStats, error) {
+	start := utcDay(from)
+	if start.Before(from) {
+		start = start.AddDate(0, 0, 1)
+	}
+	end := utcDay(to)
+	if !start.Before(end) {
+		return s.repos.SupplierOrder.FunnelStats(ctx, from, to)
+	}
+
+	days := int(end.Sub(start).Hours()/24 + 0.5)
+	rolledUp, err := s.repos.OrderRollup.CountRolledUpDays(ctx, start, end)
+	if err != nil || rolledUp < days {
+		if err != nil {
+			s.logger.Warn("Failed to check order rollups, counting orders instead", zap.Error(err))
+		}
+		return s.repos.SupplierOrder.FunnelStats(ctx, from, to)
+	}
+
+	stats, err := s.repos.OrderRollup.FunnelStats(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range [][2]time.Time{{from, start}, {end, to}} {
+		if !edge[0].Before(edge[1]) {
+			continue
+		}
+		live, err := s.repos.SupplierOrder.FunnelStats(ctx, edge[0], edge[1])
+		if err != nil {
+			return nil, err
+		}
+		stats = mergeFunnelStats(stats, live)
+	}
+	return stats, nil
+}
+
+// mergeFunnelStats adds the counts of b to a by partner, keeping the partners sorted by name
+func mergeFunnelStats(a, b []*domain.OrderFunnelStats) []*domain.OrderFunnelStats {
+	byPartner := make(map[uuid.UUID]*domain.OrderFunnelStats, len(a))
+	for _, st := range a {
+		byPartner[st.PartnerID] = st
+	}
+	for _, st := range b {
+		existing, ok := byPartner[st.PartnerID]
+		if !ok {
+			a = append(a, st)
+			byPartner[st.PartnerID] = st
+			continue
+		}
+		existing.Submitted += st.Submitted
+		existing.Confirmed += st.Confirmed
+		existing.Shipped += st.Shipped
+		existing.Delivered += st.Delivered
+		existing.Rejected += st.Rejected
+		existing.Cancelled += st.Cancelled
+		existing.Revenue += st.Revenue
+	}
+	sort.SliceStable(a, func(i, j int) bool { return a[i].PartnerName < a[j].PartnerName })
+	return a
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/internal/shopify"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Substitution event types recorded on the order timeline
+const (
+	EventTypeSubstitutionProposed = "substitution_proposed"
+	EventTypeSubstitutionAccepted = "substitution_accepted"
+	EventTypeSubstitutionDeclined = "substitution_declined"
+)
+
+// ProposeSubstitutionRequest is an admin's proposal to replace an order item with another SKU
+type ProposeSubstitutionRequest struct {
+	ItemID string  `json:"item_id" binding:"required,uuid"`
+	SKU    string  `json:"sku" binding:"required,max=255"`
+	Price  float64 `json:"price" binding:"required,gt=0"`
+	// Title defaults to the product and variant title cached from Shopify
+	Title *string `json:"title,omitempty" binding:"omitempty,max=500"`
+	Note  *string `json:"note,omitempty" binding:"omitempty,max=1000"`
+}
+
+// DeclineSubstitutionRequest is a partner declining a proposed substitution
+type DeclineSubstitutionRequest struct {
+	Reason *string `json:"reason,omitempty" binding:"omitempty,max=1000"`
+}
+
+type substitutionService struct {
+	cfg      *config.Config
+	repos    *repository.Repositories
+	shopify  ShopifyService
+	webhooks WebhookService
+	logger   *zap.Logger
+}
+
+// NewSubstitutionService creates a new item substitution service
+func NewSubstitutionService(cfg *config.Config, repos *repository.Repositories, shopify ShopifyService, webhooks WebhookService, logger *zap.Logger) *substitutionService {
+	return &substitutionService{
+		cfg:      cfg,
+		repos:    repos,
+		shopify:  shopify,
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+// checkSubstitutable returns a conflict unless the order's items can still change: the order
+// is open and its draft order has not been completed into a Shopify order
+func checkSubstitutable(order *domain.SupplierOrder) error {
+	if order.Status != domain.OrderStatusPendingConfirmation && order.Status != domain.OrderStatusOnHold {
+		return &errors.ErrConflict{Message: fmt.Sprintf("items can no longer be substituted for %s orders", order.Status)}
+	}
+	if order.ShopifyOrderID != nil {
+		return &errors.ErrConflict{Message: "items can no longer be substituted: the Shopify order was already created"}
+	}
+	return nil
+}
+
+// Propose records a substitution for one of the order's items and tells the partner
+// (order.substitution_proposed). The substitute must be an active supplier SKU. An item has
+// at most one open proposal.
+func (s *substitutionService) Propose(ctx context.Context, order *domain.SupplierOrder, req ProposeSubstitutionRequest) (*domain.ItemSubstitution, error) {
+	if err := checkSubstitutable(order); err != nil {
+		return nil, err
+	}
+
+	itemID, err := uuid.Parse(req.ItemID)
+	if err != nil {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"item_id": "invalid item ID"},
+		}
+	}
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	var item *domain.SupplierOrderItem
+	for _, it := range items {
+		if it.ID == itemID {
+			item = it
+			break
+		}
+	}
+	if item == nil {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"item_id": "not an item of this order"},
+		}
+	}
+
+	sku := strings.TrimSpace(req.SKU)
+	if sku == item.SKU {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"sku": "must differ from the item's SKU"},
+		}
+	}
+	mapping, err := s.repos.SKUMapping.GetBySKU(ctx, sku)
+	if err != nil {
+		if _, ok := err.(*errors.ErrNotFound); !ok {
+			return nil, err
+		}
+		mapping = nil
+	}
+	if mapping == nil || !mapping.IsActive {
+		return nil, &errors.ErrValidation{
+			Message: "validation failed",
+			Fields:  map[string]string{"sku": "not an active supplier SKU"},
+		}
+	}
+
+	title := substituteTitle(mapping)
+	if req.Title != nil && strings.TrimSpace(*req.Title) != "" {
+		title = strings.TrimSpace(*req.Title)
+	}
+	substitution := &domain.ItemSubstitution{
+		SupplierOrderID:     order.ID,
+		SupplierOrderItemID: item.ID,
+		OriginalSKU:         item.SKU,
+		OriginalPrice:       item.Price,
+		SKU:                 mapping.SKU,
+		Title:               title,
+		ShopifyVariantID:    mapping.ShopifyVariantID,
+		Price:               req.Price,
+		Note:                req.Note,
+	}
+	created, err := s.repos.ItemSubstitution.Create(ctx, substitution)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, &errors.ErrConflict{Message: "the item already has an open substitution"}
+	}
+
+	data := substitutionEventData(substitution)
+	data["quantity"] = item.Quantity
+	if substitution.Note != nil {
+		data["note"] = *substitution.Note
+	}
+	s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeSubstitutionProposed,
+		EventData:       data,
+	})
+	s.webhooks.NotifyOrderEvent(order, WebhookEventOrderSubstitutionProposed, data)
+
+	s.logger.Info("Item substitution proposed",
+		zap.String("order_id", order.ID.String()),
+		zap.String("substitution_id", substitution.ID.String()),
+		zap.String("original_sku", substitution.OriginalSKU),
+		zap.String("sku", substitution.SKU),
+	)
+
+	return substitution, nil
+}
+
+// Accept replaces the item with the substitute and updates the order's cart total and its
+// Shopify draft order. A failed draft update is logged and kept on the event rather than
+// returned: the substitution is applied either way and staff can edit the draft in Shopify.
+func (s *substitutionService) Accept(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID) (*domain.ItemSubstitution, error) {
+	substitution, err := s.openSubstitution(ctx, order, substitutionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSubstitutable(order); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	accepted, err := s.repos.ItemSubstitution.Accept(ctx, substitution.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, &errors.ErrConflict{Message: "substitution was already answered"}
+	}
+	substitution.Status = domain.SubstitutionStatusAccepted
+	substitution.RespondedAt = &now
+
+	data := substitutionEventData(substitution)
+	if order.ShopifyDraftOrderID != nil {
+		data["draft_order_updated"] = s.updateDraftOrder(ctx, order, data)
+	}
+	s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeSubstitutionAccepted,
+		EventData:       data,
+	})
+
+	s.logger.Info("Item substitution accepted",
+		zap.String("order_id", order.ID.String()),
+		zap.String("substitution_id", substitution.ID.String()),
+	)
+
+	return substitution, nil
+}
+
+// Decline records that the partner does not want the substitute; the item stays as ordered
+func (s *substitutionService) Decline(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID, reason *string) (*domain.ItemSubstitution, error) {
+	substitution, err := s.openSubstitution(ctx, order, substitutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	declined, err := s.repos.ItemSubstitution.Decline(ctx, substitution.ID, reason, now)
+	if err != nil {
+		return nil, err
+	}
+	if !declined {
+		return nil, &errors.ErrConflict{Message: "substitution was already answered"}
+	}
+	substitution.Status = domain.SubstitutionStatusDeclined
+	substitution.DeclineReason = reason
+	substitution.RespondedAt = &now
+
+	data := substitutionEventData(substitution)
+	if reason != nil {
+		data["reason"] = *reason
+	}
+	s.repos.OrderEvent.Create(ctx, &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeSubstitutionDeclined,
+		EventData:       data,
+	})
+
+	// Declined substitutes usually end in a rejection; staff decide
+	s.logger.Warn("Item substitution declined",
+		zap.String("order_id", order.ID.String()),
+		zap.String("substitution_id", substitution.ID.String()),
+	)
+
+	return substitution, nil
+}
+
+// openSubstitution gets a substitution of the order that still waits for an answer
+func (s *substitutionService) openSubstitution(ctx context.Context, order *domain.SupplierOrder, substitutionID uuid.UUID) (*domain.ItemSubstitution, error) {
+	substitution, err := s.repos.ItemSubstitution.GetByID(ctx, substitutionID)
+	if err != nil {
+		return nil, err
+	}
+	if substitution.SupplierOrderID != order.ID {
+		return nil, &errors.ErrNotFound{Resource: "item_substitution", ID: substitutionID.String()}
+	}
+	if substitution.Status != domain.SubstitutionStatusProposed {
+		return nil, &errors.ErrConflict{Message: fmt.Sprintf("substitution was already %s", strings.ToLower(string(substitution.Status)))}
+	}
+	return substitution, nil
+}
+
+// updateDraftOrder sends the order's current items to its draft order
+func (s *substitutionService) updateDraftOrder(ctx context.Context, order *domain.SupplierOrder, data map[string]interface{}) bool {
+	items, err := s.repos.SupplierOrderItem.GetByOrderID(ctx, order.ID)
+	if err == nil {
+		err = s.shopify.UpdateDraftOrderLineItems(ctx, *order.ShopifyDraftOrderID, order, items)
+	}
+	if err == shopify.ErrDryRun {
+		return false
+	}
+	if err != nil {
+		data["draft_order_error"] = err.Error()
+		s.logger.Error("Failed to update draft order after substitution",
+			zap.String("operation", opShopifyUpdateDraftOrder),
+			zap.String("order_id", order.ID.String()),
+			zap.String("partner_id", order.PartnerID.String()),
+			zap.Int64("draft_order_id", *order.ShopifyDraftOrderID),
+			zap.Error(err),
+		)
+		alertShopifyFailure(s.cfg, s.logger, opShopifyUpdateDraftOrder, order, err)
+		return false
+	}
+	return true
+}
+
+// substituteTitle names a substitute after its Shopify product and variant
+func substituteTitle(mapping *domain.SKUMapping) string {
+	if mapping.ProductTitle == nil || *mapping.ProductTitle == "" {
+		return mapping.SKU
+	}
+	title := *mapping.ProductTitle
+	if mapping.VariantTitle != nil && *mapping.VariantTitle != "" && *mapping.VariantTitle != "Default Title" {
+		title += " - " + *mapping.VariantTitle
+	}
+	return title
+}
+
+func substitutionEventData(substitution *domain.ItemSubstitution) map[string]interface{} {
+	return map[string]interface{}{
+		"substitution_id": substitution.ID.String(),
+		"item_id":         substitution.SupplierOrderItemID.String(),
+		"original_sku":    substitution.OriginalSKU,
+		"original_price":  substitution.OriginalPrice,
+		"sku":             substitution.SKU,
+		"title":           substitution.Title,
+		"price":           substitution.Price,
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+type orderEvents struct {
+	repository.OrderEventRepository
+	events []*domain.OrderEvent
+}
+
+func (f *orderEvents) Create(ctx context.Context, event *domain.OrderEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *orderEvents) GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]*domain.OrderEvent, error) {
+	return f.events, nil
+}
+
+// activeSKUs maps every SKU to an active supplier variant
+type activeSKUs struct {
+	repository.SKUMappingRepository
+}
+
+func (activeSKUs) GetBySKU(ctx context.Context, sku string) (*domain.SKUMapping, error) {
+	return &domain.SKUMapping{ID: uuid.New(), SKU: sku, ShopifyVariantID: 2002, IsActive: true}, nil
+}
+
+type itemSubstitutions struct {
+	repository.ItemSubstitutionRepository
+}
+
+func (itemSubstitutions) Create(ctx context.Context, substitution *domain.ItemSubstitution) (bool, error) {
+	substitution.ID = uuid.New()
+	return true, nil
+}
+
+func TestSubstitutionOfSubmittedOrder(t *testing.T) {
+	ctx := context.Background()
+	partner := &domain.Partner{
+		ID:            uuid.New(),
+		Name:          "Acme",
+		PaymentTerms:  domain.PaymentTermsPrepaid,
+		Substitutions: true,
+	}
+	item := &domain.SupplierOrderItem{ID: uuid.New(), SKU: "SUP-1", Title: "Widget", Price: 25, Quantity: 2}
+	submitted := &cartOrder{}
+	shopify := &draftShopify{}
+	cfg := &config.Config{}
+	cfg.SetTunables(config.Tunables{})
+	repos := &repository.Repositories{
+		SupplierOrder:     &cartOrders{cartOrder: submitted},
+		SupplierOrderItem: cartOrderItems{items: []*domain.SupplierOrderItem{item}},
+		Partner:           cartPartners{partner: partner},
+		OrderEvent:        &orderEvents{},
+		SKUMapping:        activeSKUs{},
+		ItemSubstitution:  itemSubstitutions{},
+	}
+	orders := &cartOrderService{cartOrder: submitted}
+	approvals := NewApprovalService(cfg, repos, orders, shopify, zap.NewNop())
+	carts := &cartService{
+		cfg:          cfg,
+		repos:        repos,
+		orders:       orders,
+		skus:         supplierSKUs{},
+		shopify:      shopify,
+		credit:       NewCreditService(repos, zap.NewNop()),
+		creditAlerts: noCreditAlerts{},
+		approvals:    approvals,
+		logger:       zap.NewNop(),
+	}
+	substitutions := NewSubstitutionService(cfg, repos, shopify, noWebhooks{}, zap.NewNop())
+
+	req := CartSubmitRequest{
+		Items:  []CartItem{{SKU: "SUP-1", Title: "Widget", Price: 25, Quantity: 2}},
+		Totals: CartTotals{Subtotal: 50, Total: 50},
+	}
+	order, _, err := carts.SubmitCart(ctx, partner, req)
+	if err != nil {
+		t.Fatalf("SubmitCart() error = %v", err)
+	}
+	if order.Status != domain.OrderStatusPendingConfirmation {
+		t.Fatalf("order status = %s, want %s", order.Status, domain.OrderStatusPendingConfirmation)
+	}
+	if order.ShopifyDraftOrderID == nil {
+		t.Fatal("draft order was not created for the submitted order")
+	}
+	if len(shopify.completed) != 0 {
+		t.Fatal("CompleteOrder called at submission for a partner with substitutions")
+	}
+
+	proposal := ProposeSubstitutionRequest{ItemID: item.ID.String(), SKU: "SUP-2", Price: 24}
+	if _, err := substitutions.Propose(ctx, order, proposal); err != nil {
+		t.Fatalf("Propose() error = %v", err)
+	}
+
+	if _, err := approvals.Approve(ctx, order.ID, uuid.New()); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if len(shopify.completed) != 1 || shopify.completed[0] != order.ID {
+		t.Fatalf("completed orders after confirmation = %v, want [%s]", shopify.completed, order.ID)
+	}
+
+	confirmed, _ := repos.SupplierOrder.GetByID(ctx, order.ID)
+	if _, err := substitutions.Propose(ctx, confirmed, proposal); err == nil {
+		t.Error("Propose() succeeded for a confirmed order")
+	}
+}
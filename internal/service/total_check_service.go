@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// EventTypeShopifyTotalMismatch is recorded when the Shopify order total differs from the
+// partner's cart total by more than the tolerance (e.g. the two systems price items differently)
+const EventTypeShopifyTotalMismatch = "shopify_total_mismatch"
+
+// totalCheckStatuses are the statuses of orders that have a completed Shopify order
+var totalCheckStatuses = []domain.OrderStatus{
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusShipped,
+	domain.OrderStatusDelivered,
+}
+
+// TotalComparison is the partner's cart total next to the Shopify order total
+type TotalComparison struct {
+	CartTotal float64 `json:"cart_total"`
+	// ExpectedTotal is the cart total without the amounts that are not sent to Shopify
+	// (shipping, and tax for tax exempt orders)
+	ExpectedTotal float64 `json:"expected_total"`
+	ShopifyTotal  float64 `json:"shopify_total"`
+	Difference    float64 `json:"difference"`
+	Currency      string  `json:"currency"`
+	Mismatch      bool    `json:"mismatch"`
+}
+
+type totalCheckService struct {
+	cfg     *config.Config
+	repos   *repository.Repositories
+	shopify ShopifyService
+	logger  *zap.Logger
+}
+
+// NewTotalCheckService creates a new cart/Shopify total reconciliation service
+func NewTotalCheckService(cfg *config.Config, repos *repository.Repositories, shopify ShopifyService, logger *zap.Logger) *totalCheckService {
+	return &totalCheckService{
+		cfg:     cfg,
+		repos:   repos,
+		shopify: shopify,
+		logger:  logger,
+	}
+}
+
+// CompareOrder compares the order's cart total with the current total of its Shopify order.
+// A mismatch event is recorded only when the totals changed since the last recorded mismatch.
+// It returns nil for orders without a Shopify order.
+func (s *totalCheckService) CompareOrder(ctx context.Context, order *domain.SupplierOrder) (*TotalComparison, error) {
+	if order.ShopifyOrderID == nil {
+		return nil, nil
+	}
+
+shopifyTotal, currency, err := s.shopify.GetOrderTotal(ctx, *order.ShopifyOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := compareTotals(s.cfg, order, shopifyTotal, currency)
+	if !comparison.Mismatch {
+		return comparison, nil
+	}
+
+	recorded, err := s.lastMismatch(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if recorded != nil && recorded.ShopifyTotal == comparison.ShopifyTotal && recorded.ExpectedTotal == comparison.ExpectedTotal {
+		return comparison, nil
+	}
+
+	event := &domain.OrderEvent{
+		SupplierOrderID: order.ID,
+		EventType:       EventTypeShopifyTotalMismatch,
+		EventData: map[string]interface{}{
+			"shopify_order_id": *order.ShopifyOrderID,
+			"partner_id":       order.PartnerID.String(),
+			"partner_order_id": order.PartnerOrderID,
+			"cart_total":       comparison.CartTotal,
+			"expected_total":   comparison.ExpectedTotal,
+			"shopify_total":    comparison.ShopifyTotal,
+			"difference":       comparison.Difference,
+			"currency":         comparison.Currency,
+		},
+	}
+	if err := s.repos.OrderEvent.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("Shopify order total differs from cart total",
+		zap.String("order_id", order.ID.String()),
+		zap.Int64("shopify_order_id", *order.ShopifyOrderID),
+		zap.Float64("expected_total", comparison.ExpectedTotal),
+		zap.Float64("shopify_total", comparison.ShopifyTotal),
+	)
+
+	return comparison, nil
+}
+
+// compareTotals compares the order's cart total with a Shopify order total
+func compareTotals(cfg *config.Config, order *domain.SupplierOrder, shopifyTotal float64, currency string) *TotalComparison {
+	expected := order.CartTotal - order.CartShipping
+	if cfg.Shopify.TaxMode == config.TaxModeExempt {
+		expected -= order.CartTax
+	}
+	comparison := &TotalComparison{
+		CartTotal:     order.CartTotal,
+		ExpectedTotal: roundAmount(expected),
+		ShopifyTotal:  shopifyTotal,
+		Difference:    roundAmount(shopifyTotal - expected),
+		Currency:      currency,
+	}
+	comparison.Mismatch = math.Abs(comparison.Difference) > cfg.TotalCheck.Tolerance
+	return comparison
+}
+
+// RunTotalCheck periodically compares totals of recent orders. It returns when ctx is cancelled.
+func (s *totalCheckService) RunTotalCheck(ctx context.Context, interval, window time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		orders, err := s.repos.SupplierOrder.ListCreatedSince(ctx, time.Now().Add(-window), totalCheckStatuses)
+		if err != nil {
+			s.logger.Error("Total check failed", zap.Error(err))
+			continue
+		}
+
+		for _, order := range orders {
+			if ctx.Err() != nil {
+				return
+			}
+			if _, err := s.CompareOrder(ctx, order); err != nil {
+				s.logger.Warn("Failed to compare order total with Shopify",
+					zap.String("order_id", order.ID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
+
+func (s *totalCheckService) lastMismatch(ctx context.Context, order *domain.SupplierOrder) (*TotalComparison, error) {
+	events, err := s.repos.OrderEvent.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var last *TotalComparison
+	var latest time.Time
+	for _, event := range events {
+		if event.EventType != EventTypeShopifyTotalMismatch || event.CreatedAt.Before(latest) {
+			continue
+		}
+		expected, _ := event.EventData["expected_total"].(float64)
+		shopifyTotal, _ := event.EventData["shopify_total"].(float64)
+		last = &TotalComparison{ExpectedTotal: expected, ShopifyTotal: shopifyTotal}
+		latest = event.CreatedAt
+	}
+	return last, nil
+}
+
+// roundAmount rounds to cents so float noise does not show up as a difference
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
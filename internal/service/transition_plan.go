@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+)
+
+// Kinds of transition effects
+const (
+	EffectStatus   = "status"
+	EffectApproval = "approval"
+	EffectEvent    = "event"
+	EffectWebhook  = "webhook"
+	EffectShopify  = "shopify"
+)
+
+// TransitionEffect is one change an admin transition would make or message it would send
+type TransitionEffect struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+// TransitionPlan is what an admin transition would do, worked out with the same checks as
+// the transition but without changing anything. The transition can still fail if the order
+// changes before it is applied.
+type TransitionPlan struct {
+	OrderID uuid.UUID
+	From    domain.OrderStatus
+	// To is the status after the transition; From when it only records an approval
+	To      domain.OrderStatus
+	Effects []TransitionEffect
+	// Approvals are the order's approvals after the transition, for confirmations
+	Approvals *ApprovalStatus
+	// Carrier, TrackingNumber and TrackingURL are what a shipment would record
+	Carrier        string
+	TrackingNumber string
+	TrackingURL    *string
+}
+
+func (p *TransitionPlan) add(kind, format string, args ...interface{}) {
+	p.Effects = append(p.Effects, TransitionEffect{Kind: kind, Description: fmt.Sprintf(format, args...)})
+}
+
+// addStatusChange adds the effects every status change has: the status, its event and webhook
+func (p *TransitionPlan) addStatusChange(webhookEvent string) {
+	p.add(EffectStatus, "%s -> %s", p.From, p.To)
+	p.add(EffectEvent, "status_change recorded on the order timeline")
+	p.add(EffectWebhook, "%s sent to the partner's webhook", webhookEvent)
+}
+
+// PlanApprove works out what Approve would do for adminID without recording anything
+func (s *approvalService) PlanApprove(ctx context.Context, orderID, adminID uuid.UUID) (*TransitionPlan, error) {
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(domain.OrderStatusConfirmed) {
+		return nil, &errors.ErrInvalidStateTransition{
+			From: order.Status,
+			To:   domain.OrderStatusConfirmed,
+		}
+	}
+
+	status, err := loadApprovalStatus(ctx, s.repos, orderID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &TransitionPlan{OrderID: orderID, From: order.Status, To: order.Status, Approvals: status}
+	if status.Required == 0 {
+		plan.To = domain.OrderStatusConfirmed
+		plan.addStatusChange(WebhookEventOrderConfirmed)
+		if order.ShopifyDraftOrderID != nil && order.ShopifyOrderID == nil {
+			if err := s.planCompleteDraftOrder(ctx, order, plan); err != nil {
+				return nil, err
+			}
+		}
+		return plan, nil
+	}
+	if status.approvedBy(adminID) {
+		return nil, &errors.ErrConflict{Message: "order is already approved by this admin; a second admin must confirm it"}
+	}
+
+	approvals := *status
+	approvals.Approvals = append(append([]Approval(nil), status.Approvals...), Approval{AdminID: adminID})
+	plan.Approvals = &approvals
+	plan.add(EffectApproval, "approval %d of %d recorded", len(approvals.Approvals), approvals.Required)
+	if !approvals.Complete() {
+		return plan, nil
+	}
+
+	plan.To = domain.OrderStatusConfirmed
+	plan.addStatusChange(WebhookEventOrderConfirmed)
+	if err := s.planCompleteDraftOrder(ctx, order, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// planCompleteDraftOrder adds what completeDraftOrder would do with the order's Shopify draft
+func (s *approvalService) planCompleteDraftOrder(ctx context.Context, order *domain.SupplierOrder, plan *TransitionPlan) error {
+	switch {
+	case order.ShopifyOrderID != nil:
+		return nil
+	case order.ShopifyDraftOrderID == nil:
+		plan.add(EffectShopify, "no Shopify draft order to complete; complete the order in Shopify by hand")
+		return nil
+	}
+	if order.PaymentStatus != domain.PaymentStatusPaid {
+		partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+		if err != nil {
+			return err
+		}
+		if partner.PaymentTerms == domain.PaymentTermsInvoice {
+			plan.add(EffectShopify, "draft order %d stays open until the invoice is paid", *order.ShopifyDraftOrderID)
+			return nil
+		}
+	}
+	if s.cfg.Shopify.DryRun {
+		plan.add(EffectShopify, "draft order %d would be completed, but SHOPIFY_DRY_RUN is set", *order.ShopifyDraftOrderID)
+		return nil
+	}
+	plan.add(EffectShopify, "draft order %d completed into an order", *order.ShopifyDraftOrderID)
+	return nil
+}
+
+// PlanRejectOrder works out what RejectOrder would do without rejecting the order
+func (s *orderService) PlanRejectOrder(ctx context.Context, orderID uuid.UUID, code domain.RejectionCode, reason string) (*TransitionPlan, error) {
+	code, err := validateRejection(code, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(domain.OrderStatusRejected) {
+		return nil, &errors.ErrInvalidStateTransition{
+			From: order.Status,
+			To:   domain.OrderStatusRejected,
+		}
+	}
+
+	plan := &TransitionPlan{OrderID: orderID, From: order.Status, To: domain.OrderStatusRejected}
+	plan.addStatusChange(WebhookEventOrderRejected)
+	plan.add(EffectStatus, "rejection code %s", code)
+	if order.ShopifyOrderID != nil || order.ShopifyDraftOrderID != nil {
+		plan.add(EffectShopify, "nothing changes in Shopify; cancel the Shopify order there if needed")
+	}
+	return plan, nil
+}
+
+// PlanShipOrder works out what ShipOrder would do, including the carrier name and tracking
+// URL it would record, without shipping the order
+func (s *shippingService) PlanShipOrder(ctx context.Context, orderID uuid.UUID, carrier, trackingNumber string, trackingURL *string) (*TransitionPlan, error) {
+	name, trackingURL, err := s.resolveCarrier(carrier, trackingNumber, trackingURL)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.repos.SupplierOrder.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.CanTransitionTo(domain.OrderStatusShipped) {
+		return nil, &errors.ErrInvalidStateTransition{
+			From: order.Status,
+			To:   domain.OrderStatusShipped,
+		}
+	}
+	if err := checkApproved(ctx, s.repos, orderID); err != nil {
+		return nil, err
+	}
+
+	plan := &TransitionPlan{
+		OrderID:        orderID,
+		From:           order.Status,
+		To:             domain.OrderStatusShipped,
+		Carrier:        name,
+		TrackingNumber: trackingNumber,
+		TrackingURL:    trackingURL,
+	}
+	plan.addStatusChange(WebhookEventOrderShipped)
+	plan.add(EffectStatus, "tracking %s %s recorded", name, trackingNumber)
+	return plan, nil
+}
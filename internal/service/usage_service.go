@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+)
+
+// PartnerUsageReport summarizes a partner's API usage, orders and webhooks over a period
+type PartnerUsageReport struct {
+	From     time.Time
+	To       time.Time
+	API      domain.APIUsageStats
+	Orders   map[domain.OrderStatus]int
+	Webhooks domain.WebhookDeliveryStats
+}
+
+// ErrorRate returns the share of requests that failed with a 4xx or 5xx response
+func (r *PartnerUsageReport) ErrorRate() float64 {
+	if r.API.TotalRequests == 0 {
+		return 0
+	}
+	return float64(r.API.ClientErrors+r.API.ServerErrors) / float64(r.API.TotalRequests)
+}
+
+// TotalOrders returns the number of orders created in the period
+func (r *PartnerUsageReport) TotalOrders() int {
+	total := 0
+	for _, count := range r.Orders {
+		total += count
+	}
+	return total
+}
+
+// WebhookSuccessRate returns the share of webhook deliveries that succeeded
+func (r *PartnerUsageReport) WebhookSuccessRate() float64 {
+	if r.Webhooks.Total == 0 {
+		return 0
+	}
+	return float64(r.Webhooks.Succeeded) / float64(r.Webhooks.Total)
+}
+
+type usageService struct {
+	repos  *repository.Repositories
+	logger *zap.Logger
+}
+
+// NewUsageService creates a new partner usage reporting service
+func NewUsageService(repos *repository.Repositories, logger *zap.Logger) *usageService {
+	return &usageService{
+		repos:  repos,
+		logger: logger,
+	}
+}
+
+// PartnerUsage builds the usage report for a partner over [from, to)
+func (s *usageService) PartnerUsage(ctx context.Context, partnerID uuid.UUID, from, to time.Time) (*PartnerUsageReport, error) {
+	apiStats, err := s.repos.APIRequestLog.StatsByPartnerID(ctx, partnerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.repos.SupplierOrder.CountByStatusForPartner(ctx, partnerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookStats, err := s.repos.WebhookDelivery.StatsByPartnerID(ctx, partnerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PartnerUsageReport{
+		From:     from,
+		To:       to,
+		API:      *apiStats,
+		Orders:   orders,
+		Webhooks: *webhookStats,
+	}, nil
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/webhookverify"
+)
+
+// Webhook event types sent to partners
+const (
+	WebhookEventOrderConfirmed = "order.confirmed"
+	WebhookEventOrderRejected  = "order.rejected"
+	WebhookEventOrderShipped   = "order.shipped"
+	WebhookEventOrderDelivered = "order.delivered"
+	WebhookEventOrderCancelled = "order.cancelled"
+	WebhookEventOrderOnHold    = "order.on_hold"
+	WebhookEventOrderReleased  = "order.released"
+	WebhookEventCatalogUpdated = "catalog.updated"
+	WebhookEventInventoryLow   = "inventory.low_stock"
+
+	WebhookEventOrderSubstitutionProposed = "order.substitution_proposed"
+
+	WebhookEventCreditThreshold = "credit.threshold_reached"
+)
+
+// WebhookEventType describes an event partners can subscribe to
+type WebhookEventType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// WebhookEventTypes is the catalog of events partners can subscribe to
+var WebhookEventTypes = []WebhookEventType{
+	{WebhookEventOrderConfirmed, "An order was confirmed"},
+	{WebhookEventOrderRejected, "An order was rejected; data.code and data.reason say why"},
+	{WebhookEventOrderShipped, "An order was shipped; data has the carrier and tracking"},
+	{WebhookEventOrderDelivered, "An order was delivered"},
+	{WebhookEventOrderCancelled, "An order was cancelled; data.reason says why"},
+	{WebhookEventOrderOnHold, "An order was put on hold for review"},
+	{WebhookEventOrderReleased, "A held order is being processed again"},
+	{WebhookEventOrderItemsChanged, "Items of an order were changed in Shopify"},
+	{WebhookEventOrderReassigned, "An order submitted from another of your merchant's partner accounts was moved to you"},
+	{WebhookEventOrderReassignedAway, "An order you submitted was moved to another partner account of your merchant"},
+	{WebhookEventOrderSubstitutionProposed, "A substitute was proposed for an item that cannot be supplied; accept or decline it"},
+	{WebhookEventCatalogUpdated, "SKUs were added to or removed from your catalog"},
+	{WebhookEventInventoryLow, "Stock of SKUs you ordered recently dropped below the low-stock threshold"},
+	{WebhookEventCreditThreshold, "Your outstanding order value reached data.threshold percent of your credit limit"},
+}
+
+// IsWebhookEventType reports whether name is in the event catalog
+func IsWebhookEventType(name string) bool {
+	for _, eventType := range WebhookEventTypes {
+		if eventType.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookTimeout bounds a single delivery attempt
+const webhookTimeout = 10 * time.Second
+
+type webhookService struct {
+	repos      *repository.Repositories
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewWebhookService creates a new partner webhook service
+func NewWebhookService(repos *repository.Repositories, logger *zap.Logger) *webhookService {
+	return &webhookService{
+		repos: repos,
+		httpClient: &http.Client{
+			Timeout: webhookTimeout,
+		},
+		logger: logger,
+	}
+}
+
+// NotifyOrderEvent delivers an order event to the owning partner's webhook in the
+// background. Partners without a webhook URL are skipped.
+func (s *webhookService) NotifyOrderEvent(order *domain.SupplierOrder, eventType string, data map[string]interface{}) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*webhookTimeout)
+		defer cancel()
+
+		if _, err := s.DeliverOrderEvent(ctx, order, eventType, data); err != nil {
+			s.logger.Warn("Failed to deliver partner webhook",
+				zap.String("order_id", order.ID.String()),
+				zap.String("event_type", eventType),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// NotifyPartnerEvent delivers an event that is not about a single order (e.g. catalog.updated)
+// to the partner's webhook in the background
+func (s *webhookService) NotifyPartnerEvent(partner *domain.Partner, eventType string, data map[string]interface{}) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*webhookTimeout)
+		defer cancel()
+
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		payload := map[string]interface{}{
+			"event_type":  eventType,
+			"partner_id":  partner.ID.String(),
+			"data":        data,
+			"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		}
+		if _, err := s.deliver(ctx, partner, nil, eventType, payload); err != nil {
+			s.logger.Warn("Failed to deliver partner webhook",
+				zap.String("partner_id", partner.ID.String()),
+				zap.String("event_type", eventType),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// DeliverOrderEvent synchronously POSTs an order event to the partner webhook and
+// records the attempt. Returns nil delivery when the partner has no webhook URL or is
+// not subscribed to eventType.
+func (s *webhookService) DeliverOrderEvent(
+	ctx context.Context,
+	order *domain.SupplierOrder,
+	eventType string,
+	data map[string]interface{},
+) (*domain.WebhookDelivery, error) {
+	partner, err := s.repos.Partner.GetByID(ctx, order.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	locale := ResolveLocale(order)
+	payload := map[string]interface{}{
+		"event_type":        eventType,
+		"supplier_order_id": order.ID.String(),
+		"partner_order_id":  order.PartnerOrderID,
+		"status":            order.Status,
+		"status_label":      StatusLabel(locale, order.Status),
+		"locale":            locale,
+		"text_direction":    locale.Direction(),
+		"data":              data,
+		"occurred_at":       time.Now().UTC().Format(time.RFC3339),
+	}
+	// Customer-facing text the partner can forward as-is
+	if message, ok := RenderOrderMessage(locale, eventType, order); ok {
+		payload["message"] = message
+	}
+
+	orderID := order.ID
+	return s.deliver(ctx, partner, &orderID, eventType, payload)
+}
+
+// deliver POSTs payload to the partner webhook and records the attempt, unless the partner
+// has no webhook URL or is not subscribed to eventType (nil delivery)
+func (s *webhookService) deliver(
+	ctx context.Context,
+	partner *domain.Partner,
+	orderID *uuid.UUID,
+	eventType string,
+	payload map[string]interface{},
+) (*domain.WebhookDelivery, error) {
+	if partner.WebhookURL == nil || *partner.WebhookURL == "" {
+		return nil, nil
+	}
+
+	subscribed, err := s.subscribed(ctx, partner.ID, eventType)
+	if err != nil {
+		return nil, err
+	}
+	if !subscribed {
+		return nil, nil
+	}
+
+	delivery := &domain.WebhookDelivery{
+		PartnerID:       partner.ID,
+		SupplierOrderID: orderID,
+		EventType:       eventType,
+		URL:             *partner.WebhookURL,
+		Payload:         payload,
+	}
+
+	var secret []byte
+	if partner.WebhookSecret != nil {
+		secret = []byte(*partner.WebhookSecret)
+	}
+	deliveryErr := s.post(ctx, delivery, secret)
+
+	if err := s.repos.WebhookDelivery.Create(ctx, delivery); err != nil {
+		s.logger.Warn("Failed to record webhook delivery", zap.Error(err))
+	}
+
+	return delivery, deliveryErr
+}
+
+// RetryDelivery resends one of the partner's deliveries: the same payload is POSTed to the
+// partner's current webhook URL under a new delivery ID and recorded as a retry of the
+// original delivery. Subscriptions are not checked since the partner asked for it. The
+// outcome of the attempt is on the returned delivery; the error is only set when it could
+// not be made.
+func (s *webhookService) RetryDelivery(ctx context.Context, partner *domain.Partner, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	original, err := s.repos.WebhookDelivery.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.PartnerID != partner.ID {
+		return nil, &errors.ErrNotFound{Resource: "webhook_delivery", ID: deliveryID.String()}
+	}
+	if partner.WebhookURL == nil || *partner.WebhookURL == "" {
+		return nil, &errors.ErrValidation{
+			Message: "no webhook URL",
+			Fields:  map[string]string{"webhook_url": "set a webhook URL before retrying deliveries"},
+		}
+	}
+
+	// Retries of a retry belong to the same original delivery
+	retryOf := original.ID
+	if original.RetryOf != nil {
+		retryOf = *original.RetryOf
+	}
+	retry := &domain.WebhookDelivery{
+		PartnerID:       partner.ID,
+		SupplierOrderID: original.SupplierOrderID,
+		EventType:       original.EventType,
+		URL:             *partner.WebhookURL,
+		Payload:         original.Payload,
+		RetryOf:         &retryOf,
+	}
+
+	var secret []byte
+	if partner.WebhookSecret != nil {
+		secret = []byte(*partner.WebhookSecret)
+	}
+	if err := s.post(ctx, retry, secret); err != nil {
+		s.logger.Info("Webhook retry failed",
+			zap.String("partner_id", partner.ID.String()),
+			zap.String("delivery_id", retryOf.String()),
+			zap.Error(err),
+		)
+	}
+
+	if err := s.repos.WebhookDelivery.Create(ctx, retry); err != nil {
+		return nil, err
+	}
+	return retry, nil
+}
+
+// UpdateSubscriptions replaces the event types the partner receives. Unknown event types are
+// an ErrValidation; duplicates are dropped. An empty list subscribes the partner to every event.
+func (s *webhookService) UpdateSubscriptions(ctx context.Context, partner *domain.Partner, eventTypes []string) ([]string, error) {
+	fields := make(map[string]string)
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(eventTypes))
+	for i, eventType := range eventTypes {
+		if !IsWebhookEventType(eventType) {
+			fields[fmt.Sprintf("event_types[%d]", i)] = "unknown event type " + strconv.Quote(eventType)
+			continue
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			unique = append(unique, eventType)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &errors.ErrValidation{Message: "invalid event types", Fields: fields}
+	}
+
+	if err := s.repos.WebhookSubscription.Replace(ctx, partner.ID, unique); err != nil {
+		return nil, err
+	}
+	return s.repos.WebhookSubscription.ListByPartnerID(ctx, partner.ID)
+}
+
+// subscribed reports whether the partner receives eventType; partners that never chose
+// subscriptions receive every event
+func (s *webhookService) subscribed(ctx context.Context, partnerID uuid.UUID, eventType string) (bool, error) {
+	eventTypes, err := s.repos.WebhookSubscription.ListByPartnerID(ctx, partnerID)
+	if err != nil {
+		return false, err
+	}
+	if len(eventTypes) == 0 {
+		return true, nil
+	}
+	for _, subscribed := range eventTypes {
+		if subscribed == eventType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// post sends the payload, signed with secret unless it is empty, and fills in the outcome
+// fields of the delivery
+func (s *webhookService) post(ctx context.Context, delivery *domain.WebhookDelivery, secret []byte) error {
+	start := time.Now()
+	defer func() {
+		delivery.DurationMs = int(time.Since(start).Milliseconds())
+	}()
+
+	fail := func(err error) error {
+		msg := err.Error()
+		delivery.Error = &msg
+		return err
+	}
+
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return fail(fmt.Errorf("failed to marshal webhook payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return fail(fmt.Errorf("failed to create webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-B2B-Event", delivery.EventType)
+
+	// The delivery ID lets partners drop replays; it is also the ID we record the attempt under
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	req.Header.Set(webhookverify.DeliveryHeader, delivery.ID.String())
+	if delivery.RetryOf != nil {
+		req.Header.Set(webhookverify.RetryOfHeader, delivery.RetryOf.String())
+	}
+	if len(secret) > 0 {
+		now := time.Now()
+		req.Header.Set(webhookverify.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(webhookverify.SignatureHeader, webhookverify.Sign(secret, now, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fail(fmt.Errorf("failed to send webhook: %w", err))
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	delivery.ResponseStatus = &status
+	if status < 200 || status >= 300 {
+		return fail(fmt.Errorf("webhook endpoint returned status %d", status))
+	}
+
+	delivery.Success = true
+	return nil
+}
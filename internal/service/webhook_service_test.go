@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jafarshop/b2bapi/internal/domain"
+	"github.com/jafarshop/b2bapi/internal/repository"
+	"github.com/jafarshop/b2bapi/pkg/errors"
+	"github.com/jafarshop/b2bapi/pkg/webhookverify"
+)
+
+// memDeliveries keeps webhook deliveries in memory
+type memDeliveries struct {
+	repository.WebhookDeliveryRepository
+	deliveries map[uuid.UUID]*domain.WebhookDelivery
+}
+
+func (r *memDeliveries) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (r *memDeliveries) GetByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, &errors.ErrNotFound{Resource: "webhook_delivery", ID: id.String()}
+	}
+	return delivery, nil
+}
+
+func TestWebhookServiceRetryDelivery(t *testing.T) {
+	var retryOfHeaders []string
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		retryOfHeaders = append(retryOfHeaders, r.Header.Get(webhookverify.RetryOfHeader))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	url := server.URL
+	partner := &domain.Partner{ID: uuid.New(), WebhookURL: &url}
+	original := &domain.WebhookDelivery{
+		ID:        uuid.New(),
+		PartnerID: partner.ID,
+		EventType: WebhookEventOrderShipped,
+		URL:       "https://old.example.com/hooks",
+		Payload:   map[string]interface{}{"event_type": WebhookEventOrderShipped},
+	}
+	deliveries := &memDeliveries{deliveries: map[uuid.UUID]*domain.WebhookDelivery{original.ID: original}}
+	s := NewWebhookService(&repository.Repositories{WebhookDelivery: deliveries}, zap.NewNop())
+	ctx := context.Background()
+
+	// A failing retry is recorded rather than returned as an error
+	first, err := s.RetryDelivery(ctx, partner, original.ID)
+	if err != nil {
+		t.Fatalf("RetryDelivery() error = %v", err)
+	}
+	if first.Success || first.ResponseStatus == nil || *first.ResponseStatus != status {
+		t.Errorf("first retry = success %v, status %v; want a failed attempt with status %d", first.Success, first.ResponseStatus, status)
+	}
+	if first.URL != url {
+		t.Errorf("retry URL = %q, want the partner's current URL %q", first.URL, url)
+	}
+
+	// Retrying the retry still points at the original delivery
+	status = http.StatusOK
+	second, err := s.RetryDelivery(ctx, partner, first.ID)
+	if err != nil {
+		t.Fatalf("RetryDelivery() error = %v", err)
+	}
+	if !second.Success {
+		t.Errorf("second retry failed: %v", second.Error)
+	}
+	for _, retry := range []*domain.WebhookDelivery{first, second} {
+		if retry.RetryOf == nil || *retry.RetryOf != original.ID || retry.ID == original.ID {
+			t.Errorf("retry %s: retry_of = %v, want %s", retry.ID, retry.RetryOf, original.ID)
+		}
+	}
+	for _, header := range retryOfHeaders {
+		if header != original.ID.String() {
+			t.Errorf("%s = %q, want %s", webhookverify.RetryOfHeader, header, original.ID)
+		}
+	}
+	if len(deliveries.deliveries) != 3 {
+		t.Errorf("recorded %d deliveries, want the original and two retries", len(deliveries.deliveries))
+	}
+
+	// Other partners' deliveries are not found
+	other := &domain.Partner{ID: uuid.New(), WebhookURL: &url}
+	if _, err := s.RetryDelivery(ctx, other, original.ID); err == nil {
+		t.Error("RetryDelivery() of another partner's delivery succeeded")
+	} else if _, ok := err.(*errors.ErrNotFound); !ok {
+		t.Errorf("RetryDelivery() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,172 @@
+package shopify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Bulk operation statuses
+const (
+	BulkOperationStatusCreated   = "CREATED"
+	BulkOperationStatusRunning   = "RUNNING"
+	BulkOperationStatusCompleted = "COMPLETED"
+	BulkOperationStatusFailed    = "FAILED"
+	BulkOperationStatusCanceled  = "CANCELED"
+	BulkOperationStatusExpired   = "EXPIRED"
+)
+
+// maxBulkLineSize bounds a single JSONL line in a bulk result
+const maxBulkLineSize = 1024 * 1024
+
+// BulkOperation is the state of a Shopify bulk operation
+type BulkOperation struct {
+	ID             string  `json:"id"`
+	Status         string  `json:"status"`
+	ErrorCode      *string `json:"errorCode"`
+	ObjectCount    string  `json:"objectCount"`
+	URL            *string `json:"url"`
+	PartialDataURL *string `json:"partialDataUrl"`
+}
+
+// RunBulkQuery starts a bulk query and returns the bulk operation ID.
+// Shopify allows one running bulk query per shop at a time.
+func (c *Client) RunBulkQuery(query string) (string, error) {
+	resp, err := c.Execute(BulkOperationRunQueryMutation, map[string]interface{}{
+		"query": query,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start bulk operation: %w", err)
+	}
+
+	var result struct {
+		BulkOperationRunQuery struct {
+			BulkOperation *BulkOperation `json:"bulkOperation"`
+			UserErrors    []struct {
+				Field   []string `json:"field"`
+				Message string   `json:"message"`
+			} `json:"userErrors"`
+		} `json:"bulkOperationRunQuery"`
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return "", fmt.Errorf("failed to parse bulk operation response: %w", err)
+	}
+
+	if len(result.BulkOperationRunQuery.UserErrors) > 0 {
+		return "", fmt.Errorf("shopify user errors: %v", result.BulkOperationRunQuery.UserErrors)
+	}
+	if result.BulkOperationRunQuery.BulkOperation == nil {
+		return "", fmt.Errorf("shopify returned no bulk operation")
+	}
+
+	return result.BulkOperationRunQuery.BulkOperation.ID, nil
+}
+
+// GetBulkOperation fetches the current state of a bulk operation
+func (c *Client) GetBulkOperation(id string) (*BulkOperation, error) {
+	resp, err := c.Execute(BulkOperationQuery, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk operation: %w", err)
+	}
+
+	var result struct {
+		Node *BulkOperation `json:"node"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse bulk operation: %w", err)
+	}
+	if result.Node == nil {
+		return nil, fmt.Errorf("bulk operation %s not found", id)
+	}
+
+	return result.Node, nil
+}
+
+// WaitForBulkOperation polls until the bulk operation finishes. It returns an error
+// unless the operation completed.
+func (c *Client) WaitForBulkOperation(ctx context.Context, id string, pollInterval time.Duration) (*BulkOperation, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		op, err := c.GetBulkOperation(id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Status {
+		case BulkOperationStatusCompleted:
+			return op, nil
+		case BulkOperationStatusFailed, BulkOperationStatusCanceled, BulkOperationStatusExpired:
+			errorCode := ""
+			if op.ErrorCode != nil {
+				errorCode = *op.ErrorCode
+			}
+			return op, fmt.Errorf("bulk operation %s ended with status %s %s", id, op.Status, errorCode)
+		}
+
+		c.logger.Debug("Waiting for bulk operation",
+			zap.String("id", id),
+			zap.String("status", op.Status),
+			zap.String("object_count", op.ObjectCount),
+		)
+
+		select {
+		case <-ctx.Done():
+			return op, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamBulkResult downloads a bulk operation's JSONL result and calls fn for each line.
+// The line is only valid during the call.
+// A completed operation with no results has no URL; fn is then never called.
+func (c *Client) StreamBulkResult(ctx context.Context, op *BulkOperation, fn func(line json.RawMessage) error) error {
+	if op.URL == nil || *op.URL == "" {
+		return nil
+	}
+
+	// The result URL is a signed storage URL - no Shopify auth header
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *op.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	// Result files can be large; don't apply the GraphQL client's timeout
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download bulk result: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bulk result download failed: status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxBulkLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(json.RawMessage(line)); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read bulk result: %w", err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package shopify
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,9 +15,13 @@ import (
 	"github.com/jafarshop/b2bapi/internal/config"
 )
 
+// ErrDryRun is returned by Execute in dry-run mode, where nothing is sent to Shopify
+var ErrDryRun = errors.New("shopify dry-run mode: request not sent")
+
 type Client struct {
 	shopDomain  string
-	accessToken string
+	accessToken func() string
+	dryRun      bool
 	httpClient  *http.Client
 	logger      *zap.Logger
 }
@@ -31,7 +36,8 @@ func NewClient(cfg config.ShopifyConfig, logger *zap.Logger) *Client {
 	
 	return &Client{
 		shopDomain:  shopDomain,
-		accessToken: cfg.AccessToken,
+		accessToken: cfg.CurrentAccessToken,
+		dryRun:      cfg.DryRun,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -59,6 +65,11 @@ type GraphQLError struct {
 
 // Execute executes a GraphQL query/mutation
 func (c *Client) Execute(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	if c.dryRun {
+		c.logger.Info("Shopify dry-run: skipping request", zap.String("operation", operationName(query)))
+		return nil, ErrDryRun
+	}
+
 	url := fmt.Sprintf("https://%s/admin/api/2024-01/graphql.json", c.shopDomain)
 
 	reqBody := GraphQLRequest{
@@ -77,7 +88,7 @@ func (c *Client) Execute(query string, variables map[string]interface{}) (*Graph
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Shopify-Access-Token", c.accessToken)
+	req.Header.Set("X-Shopify-Access-Token", c.accessToken())
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -109,3 +120,18 @@ func (c *Client) Execute(query string, variables map[string]interface{}) (*Graph
 
 	return &graphQLResp, nil
 }
+
+// DryRun reports whether the client is in dry-run mode
+func (c *Client) DryRun() bool {
+	return c.dryRun
+}
+
+// operationName returns the first line of a query/mutation for logging, e.g. "mutation draftOrderCreate($input: DraftOrderInput!) {"
+func operationName(query string) string {
+	for _, line := range strings.Split(query, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -27,6 +27,7 @@ mutation draftOrderComplete($id: ID!) {
       id
       order {
         id
+        name
       }
     }
     userErrors {
@@ -37,6 +38,26 @@ mutation draftOrderComplete($id: ID!) {
 }
 `
 
+// MetafieldsSetMutation sets metafields on any owner resource (e.g. an Order).
+const MetafieldsSetMutation = `
+mutation metafieldsSet($metafields: [MetafieldsSetInput!]!) {
+  metafieldsSet(metafields: $metafields) {
+    metafields {
+      key
+      namespace
+      value
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// MetafieldNamespace is the namespace used for all B2B linkage metafields on Shopify resources.
+const MetafieldNamespace = "b2b"
+
 // DraftOrderInput represents the input for creating a draft order
 type DraftOrderInput struct {
 	LineItems     []DraftOrderLineItemInput `json:"lineItems"`
@@ -46,6 +67,7 @@ type DraftOrderInput struct {
 	Tags          []string                   `json:"tags,omitempty"`
 	Note          *string                   `json:"note,omitempty"`
 	CustomAttributes []DraftOrderAttributeInput `json:"customAttributes,omitempty"`
+	TaxExempt     *bool                      `json:"taxExempt,omitempty"`
 }
 
 type DraftOrderLineItemInput struct {
@@ -55,6 +77,8 @@ type DraftOrderLineItemInput struct {
 	OriginalUnitPrice *string `json:"originalUnitPrice,omitempty"`
 	Quantity     int      `json:"quantity"`
 	CustomAttributes []DraftOrderAttributeInput `json:"customAttributes,omitempty"`
+	Taxable          *bool                      `json:"taxable,omitempty"`
+	RequiresShipping *bool                      `json:"requiresShipping,omitempty"`
 }
 
 type DraftOrderAddressInput struct {
@@ -73,3 +97,104 @@ type DraftOrderAttributeInput struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// MetafieldsSetInput represents a single metafield to set on a Shopify resource
+type MetafieldsSetInput struct {
+	OwnerID   string `json:"ownerId"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+}
+
+// BulkOperationRunQueryMutation starts an asynchronous bulk query. Results are
+// written by Shopify to a JSONL file, see BulkOperationQuery.
+const BulkOperationRunQueryMutation = `
+mutation bulkOperationRunQuery($query: String!) {
+  bulkOperationRunQuery(query: $query) {
+    bulkOperation {
+      id
+      status
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// CustomerCreateMutation creates a customer
+const CustomerCreateMutation = `
+mutation customerCreate($input: CustomerInput!) {
+  customerCreate(input: $input) {
+    customer {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// CustomerInput represents the input for creating a customer
+type CustomerInput struct {
+	FirstName *string  `json:"firstName,omitempty"`
+	LastName  *string  `json:"lastName,omitempty"`
+	Email     *string  `json:"email,omitempty"`
+	Phone     *string  `json:"phone,omitempty"` // E.164
+	Tags      []string `json:"tags,omitempty"`
+}
+
+// DraftOrderInvoiceSendMutation emails the draft order's invoice (checkout link) to the
+// customer, or to the given address
+const DraftOrderInvoiceSendMutation = `
+mutation draftOrderInvoiceSend($id: ID!, $email: EmailInput) {
+  draftOrderInvoiceSend(id: $id, email: $email) {
+    draftOrder {
+      id
+      invoiceUrl
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// EmailInput represents the recipient of a draft order invoice
+type EmailInput struct {
+	To string `json:"to"`
+}
+
+// DraftOrderDeleteMutation deletes a draft order that was never completed
+const DraftOrderDeleteMutation = `
+mutation draftOrderDelete($input: DraftOrderDeleteInput!) {
+  draftOrderDelete(input: $input) {
+    deletedId
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
+
+// DraftOrderUpdateMutation updates a draft order that was not completed yet. Line items
+// given replace all of the draft's line items.
+const DraftOrderUpdateMutation = `
+mutation draftOrderUpdate($id: ID!, $input: DraftOrderInput!) {
+  draftOrderUpdate(id: $id, input: $input) {
+    draftOrder {
+      id
+    }
+    userErrors {
+      field
+      message
+    }
+  }
+}
+`
@@ -0,0 +1,189 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Order is an order as returned by OrderByIDQuery and OrderByNumberQueryTemplate.
+// Shopify returns null for much of an order: the customer of a guest checkout, the
+// shipping address of an order that is not shipped, the variant of a line item whose
+// product was deleted, contact details that were never given. Every such field is a
+// pointer here, so callers have to decide what a missing value means.
+type Order struct {
+	ID                       string             `json:"id"`
+	Name                     string             `json:"name"`
+	DisplayFulfillmentStatus string             `json:"displayFulfillmentStatus"`
+	DisplayFinancialStatus   string             `json:"displayFinancialStatus"`
+	CreatedAt                string             `json:"createdAt"`
+	UpdatedAt                string             `json:"updatedAt"`
+	TotalPriceSet            *MoneyBag          `json:"totalPriceSet"`
+	Customer                 *OrderCustomer     `json:"customer"`
+	ShippingAddress          *OrderAddress      `json:"shippingAddress"`
+	LineItems                []OrderLineItem    `json:"-"`
+	Fulfillments             []OrderFulfillment `json:"fulfillments"`
+}
+
+// UnmarshalJSON flattens the lineItems connection into LineItems
+func (o *Order) UnmarshalJSON(data []byte) error {
+	type order Order
+	var raw struct {
+		*order
+		LineItems *struct {
+			Edges []struct {
+				Node *OrderLineItem `json:"node"`
+			} `json:"edges"`
+		} `json:"lineItems"`
+	}
+	raw.order = (*order)(o)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	o.LineItems = nil
+	if raw.LineItems != nil {
+		for _, edge := range raw.LineItems.Edges {
+			if edge.Node != nil {
+				o.LineItems = append(o.LineItems, *edge.Node)
+			}
+		}
+	}
+	return nil
+}
+
+// MoneyBag is an amount in the shop's currency
+type MoneyBag struct {
+	ShopMoney struct {
+		Amount       string `json:"amount"`
+		CurrencyCode string `json:"currencyCode"`
+	} `json:"shopMoney"`
+}
+
+// Amount parses the amount; a nil MoneyBag has no amount
+func (m *MoneyBag) Amount() (float64, string, error) {
+	if m == nil {
+		return 0, "", fmt.Errorf("no amount")
+	}
+	amount, err := strconv.ParseFloat(m.ShopMoney.Amount, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid amount %q: %w", m.ShopMoney.Amount, err)
+	}
+	return amount, m.ShopMoney.CurrencyCode, nil
+}
+
+// OrderCustomer is the customer of an order; it is nil for guest checkouts
+type OrderCustomer struct {
+	FirstName *string `json:"firstName"`
+	LastName  *string `json:"lastName"`
+	Email     *string `json:"email"`
+	Phone     *string `json:"phone"`
+}
+
+// Name returns the customer's first and last name, whichever are set
+func (c *OrderCustomer) Name() string {
+	if c == nil {
+		return ""
+	}
+	return strings.TrimSpace(Value(c.FirstName) + " " + Value(c.LastName))
+}
+
+// OrderAddress is a shipping address
+type OrderAddress struct {
+	Address1 *string `json:"address1"`
+	Address2 *string `json:"address2"`
+	City     *string `json:"city"`
+	Province *string `json:"province"`
+	Zip      *string `json:"zip"`
+	Country  *string `json:"country"`
+}
+
+// OrderLineItem is a line item of an order. Variant is nil for custom lines and for lines
+// whose variant was deleted since the order was placed.
+type OrderLineItem struct {
+	ID                   string        `json:"id"`
+	SKU                  *string       `json:"sku"`
+	Title                string        `json:"title"`
+	Quantity             int           `json:"quantity"`
+	Variant              *OrderVariant `json:"variant"`
+	OriginalUnitPriceSet *MoneyBag     `json:"originalUnitPriceSet"`
+}
+
+// EffectiveSKU returns the SKU recorded on the line, or the variant's when the line has
+// none. The line keeps its SKU after the variant is deleted.
+func (li OrderLineItem) EffectiveSKU() string {
+	if sku := Value(li.SKU); sku != "" {
+		return sku
+	}
+	if li.Variant != nil {
+		return Value(li.Variant.SKU)
+	}
+	return ""
+}
+
+// OrderVariant is the product variant of a line item
+type OrderVariant struct {
+	ID    string  `json:"id"`
+	SKU   *string `json:"sku"`
+	Title string  `json:"title"`
+	Price *string `json:"price"`
+}
+
+// OrderFulfillment is a fulfillment of an order with its tracking information
+type OrderFulfillment struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	TrackingInfo []struct {
+		Number  *string `json:"number"`
+		URL     *string `json:"url"`
+		Company *string `json:"company"`
+	} `json:"trackingInfo"`
+}
+
+// ParseOrderByID parses the response data of OrderByIDQuery. The order is nil when no
+// order has the ID (the node is null, or is not an order).
+func ParseOrderByID(data json.RawMessage) (*Order, error) {
+	var result struct {
+		Node *Order `json:"node"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse order response: %w", err)
+	}
+	if result.Node == nil || result.Node.ID == "" {
+		return nil, nil
+	}
+	return result.Node, nil
+}
+
+// ParseOrderByNumber parses the response data of OrderByNumberQueryTemplate. The order is
+// nil when no order matched.
+func ParseOrderByNumber(data json.RawMessage) (*Order, error) {
+	var result struct {
+		Orders *struct {
+			Edges []struct {
+				Node *Order `json:"node"`
+			} `json:"edges"`
+		} `json:"orders"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse orders response: %w", err)
+	}
+	if result.Orders == nil {
+		return nil, nil
+	}
+	for _, edge := range result.Orders.Edges {
+		if edge.Node != nil && edge.Node.ID != "" {
+			return edge.Node, nil
+		}
+	}
+	return nil, nil
+}
+
+// Value returns the string s points to, or "" for nil
+func Value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
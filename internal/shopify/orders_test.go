@@ -0,0 +1,154 @@
+package shopify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseOrderByIDGuestCheckout(t *testing.T) {
+	order, err := ParseOrderByID(readFixture(t, "order_guest_checkout.json"))
+	if err != nil {
+		t.Fatalf("ParseOrderByID() error = %v", err)
+	}
+	if order == nil {
+		t.Fatal("ParseOrderByID() = nil, want the order")
+	}
+
+	if order.Name != "#45246" {
+		t.Errorf("Name = %q, want #45246", order.Name)
+	}
+	if order.Customer != nil {
+		t.Errorf("Customer = %+v, want nil for a guest checkout", order.Customer)
+	}
+	if name := order.Customer.Name(); name != "" {
+		t.Errorf("Customer.Name() = %q, want empty", name)
+	}
+	total, currency, err := order.TotalPriceSet.Amount()
+	if err != nil || total != 27.5 || currency != "JOD" {
+		t.Errorf("TotalPriceSet.Amount() = %v, %q, %v; want 27.5 JOD", total, currency, err)
+	}
+	if addr := order.ShippingAddress; addr == nil || Value(addr.City) != "Amman" || addr.Address2 != nil || addr.Zip != nil {
+		t.Errorf("ShippingAddress = %+v, want Amman without address2 and zip", addr)
+	}
+
+	if len(order.LineItems) != 3 {
+		t.Fatalf("len(LineItems) = %d, want 3", len(order.LineItems))
+	}
+	tests := []struct {
+		sku        string
+		hasVariant bool
+		hasPrice   bool
+	}{
+		{sku: "SUP-0001", hasVariant: true, hasPrice: true},
+		// Deleted variant: the line keeps its SKU
+		{sku: "SUP-0002", hasPrice: true},
+		// Custom line
+		{sku: ""},
+	}
+	for i, tt := range tests {
+		item := order.LineItems[i]
+		if got := item.EffectiveSKU(); got != tt.sku {
+			t.Errorf("LineItems[%d].EffectiveSKU() = %q, want %q", i, got, tt.sku)
+		}
+		if (item.Variant != nil) != tt.hasVariant {
+			t.Errorf("LineItems[%d].Variant = %+v, want present %v", i, item.Variant, tt.hasVariant)
+		}
+		if (item.OriginalUnitPriceSet != nil) != tt.hasPrice {
+			t.Errorf("LineItems[%d].OriginalUnitPriceSet = %+v, want present %v", i, item.OriginalUnitPriceSet, tt.hasPrice)
+		}
+	}
+	if len(order.Fulfillments) != 0 {
+		t.Errorf("Fulfillments = %+v, want none", order.Fulfillments)
+	}
+}
+
+func TestParseOrderByIDNulls(t *testing.T) {
+	order, err := ParseOrderByID(readFixture(t, "order_nulls.json"))
+	if err != nil {
+		t.Fatalf("ParseOrderByID() error = %v", err)
+	}
+	if order == nil {
+		t.Fatal("ParseOrderByID() = nil, want the order")
+	}
+
+	if _, _, err := order.TotalPriceSet.Amount(); err == nil {
+		t.Error("TotalPriceSet.Amount() error = nil, want an error for a missing total")
+	}
+	if order.ShippingAddress != nil {
+		t.Errorf("ShippingAddress = %+v, want nil", order.ShippingAddress)
+	}
+	if name := order.Customer.Name(); name != "Haddad" {
+		t.Errorf("Customer.Name() = %q, want Haddad", name)
+	}
+	if order.Customer.Email != nil {
+		t.Errorf("Customer.Email = %q, want nil", Value(order.Customer.Email))
+	}
+
+	// The null line item node is skipped
+	if len(order.LineItems) != 1 {
+		t.Fatalf("len(LineItems) = %d, want 1", len(order.LineItems))
+	}
+	item := order.LineItems[0]
+	if got := item.EffectiveSKU(); got != "SUP-0001" {
+		t.Errorf("EffectiveSKU() = %q, want the variant's SKU", got)
+	}
+	if item.Variant.Price != nil {
+		t.Errorf("Variant.Price = %q, want nil", Value(item.Variant.Price))
+	}
+
+	if len(order.Fulfillments) != 1 || len(order.Fulfillments[0].TrackingInfo) != 1 {
+		t.Fatalf("Fulfillments = %+v, want one with one tracking info", order.Fulfillments)
+	}
+	tracking := order.Fulfillments[0].TrackingInfo[0]
+	if tracking.Number != nil || Value(tracking.Company) != "Aramex" {
+		t.Errorf("TrackingInfo = %+v, want Aramex without a number", tracking)
+	}
+}
+
+func TestParseOrderByIDNotFound(t *testing.T) {
+	for _, fixture := range []string{"order_not_found.json", "order_not_an_order.json"} {
+		t.Run(fixture, func(t *testing.T) {
+			order, err := ParseOrderByID(readFixture(t, fixture))
+			if err != nil {
+				t.Fatalf("ParseOrderByID() error = %v", err)
+			}
+			if order != nil {
+				t.Errorf("ParseOrderByID() = %+v, want nil", order)
+			}
+		})
+	}
+}
+
+func TestParseOrderByNumber(t *testing.T) {
+	order, err := ParseOrderByNumber(readFixture(t, "orders_by_number.json"))
+	if err != nil {
+		t.Fatalf("ParseOrderByNumber() error = %v", err)
+	}
+	if order == nil || order.Name != "#45246" {
+		t.Fatalf("ParseOrderByNumber() = %+v, want #45246", order)
+	}
+	if order.Customer != nil || order.ShippingAddress != nil || order.LineItems != nil || order.Fulfillments != nil {
+		t.Errorf("ParseOrderByNumber() = %+v, want null fields left empty", order)
+	}
+
+	order, err = ParseOrderByNumber(readFixture(t, "orders_by_number_empty.json"))
+	if err != nil || order != nil {
+		t.Errorf("ParseOrderByNumber() = %+v, %v; want nil, nil when no order matched", order, err)
+	}
+}
+
+func TestParseOrderByIDInvalid(t *testing.T) {
+	if _, err := ParseOrderByID([]byte(`{"node": {"lineItems": {"edges": "oops"}}}`)); err == nil {
+		t.Error("ParseOrderByID() error = nil, want an error for a malformed response")
+	}
+}
@@ -66,6 +66,7 @@ query getOrderByNumber {
           edges {
             node {
               id
+              sku
               title
               quantity
               variant {
@@ -133,6 +134,7 @@ query getOrderByID($id: ID!) {
         edges {
           node {
             id
+            sku
             title
             quantity
             variant {
@@ -162,4 +164,222 @@ query getOrderByID($id: ID!) {
     }
   }
 }
-`
\ No newline at end of file
+`
+
+// OrderLineItemsQuery fetches the current (post-edit) line items of an order by its Shopify GID
+const OrderLineItemsQuery = `
+query getOrderLineItems($id: ID!) {
+  node(id: $id) {
+    ... on Order {
+      id
+      edited
+      lineItems(first: 250) {
+        edges {
+          node {
+            id
+            sku
+            title
+            currentQuantity
+            variant {
+              id
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// OrderTotalsQuery fetches the current total of an order by its Shopify GID
+const OrderTotalsQuery = `
+query getOrderTotals($id: ID!) {
+  node(id: $id) {
+    ... on Order {
+      id
+      currentTotalPriceSet {
+        shopMoney {
+          amount
+          currencyCode
+        }
+      }
+    }
+  }
+}
+`
+
+// OrderSnapshotQuery fetches what support compares against our record: current line items,
+// total, shipping address and fulfillment state of an order by its Shopify GID
+const OrderSnapshotQuery = `
+query getOrderSnapshot($id: ID!) {
+  node(id: $id) {
+    ... on Order {
+      id
+      name
+      cancelledAt
+      displayFulfillmentStatus
+      displayFinancialStatus
+      currentTotalPriceSet {
+        shopMoney {
+          amount
+          currencyCode
+        }
+      }
+      shippingAddress {
+        address1
+        address2
+        city
+        province
+        zip
+        countryCodeV2
+      }
+      lineItems(first: 250) {
+        edges {
+          node {
+            sku
+            title
+            currentQuantity
+            variant {
+              id
+            }
+          }
+        }
+      }
+      fulfillments {
+        status
+        trackingInfo {
+          number
+          url
+          company
+        }
+      }
+    }
+  }
+}
+`
+
+// CustomersSearchQuery finds customers with Shopify search syntax, e.g. phone:+962791234567
+const CustomersSearchQuery = `
+query findCustomers($query: String!) {
+  customers(first: 1, query: $query) {
+    edges {
+      node {
+        id
+      }
+    }
+  }
+}
+`
+
+// DraftOrdersSearchQuery finds draft orders with Shopify search syntax, e.g. tag:'b2b_ref:B2B-2024-000123'
+const DraftOrdersSearchQuery = `
+query findDraftOrders($query: String!) {
+  draftOrders(first: 1, query: $query) {
+    edges {
+      node {
+        id
+        name
+      }
+    }
+  }
+}
+`
+
+// VariantsQuery fetches price and stock of product variants by their Shopify GIDs
+const VariantsQuery = `
+query getVariants($ids: [ID!]!) {
+  nodes(ids: $ids) {
+    ... on ProductVariant {
+      id
+      price
+      availableForSale
+      inventoryQuantity
+      inventoryPolicy
+      inventoryItem {
+        tracked
+      }
+    }
+  }
+}
+`
+
+// OrderMetafieldsQuery fetches the B2B linkage metafields of an order by its Shopify GID
+const OrderMetafieldsQuery = `
+query getOrderMetafields($id: ID!, $namespace: String!) {
+  node(id: $id) {
+    ... on Order {
+      id
+      name
+      metafields(first: 10, namespace: $namespace) {
+        edges {
+          node {
+            key
+            value
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// BulkOperationQuery polls a bulk operation by ID
+const BulkOperationQuery = `
+query bulkOperation($id: ID!) {
+  node(id: $id) {
+    ... on BulkOperation {
+      id
+      status
+      errorCode
+      objectCount
+      url
+      partialDataUrl
+    }
+  }
+}
+`
+
+// BulkProductVariantsQuery exports every product variant with its SKU and product details.
+// It is passed to bulkOperationRunQuery, so it has no pagination arguments.
+// Each JSONL line is a product ({"id", "title", "vendor", "productType", "featuredImage"}),
+// a product image ({"url", "__parentId"}) or a variant
+// ({"id", "sku", "title", "barcode", "weight", "weightUnit", "image", "__parentId"}).
+const BulkProductVariantsQuery = `
+{
+  products {
+    edges {
+      node {
+        id
+        title
+        vendor
+        productType
+        featuredImage {
+          url
+        }
+        images {
+          edges {
+            node {
+              url
+            }
+          }
+        }
+        variants {
+          edges {
+            node {
+              id
+              sku
+              title
+              barcode
+              weight
+              weightUnit
+              image {
+                url
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
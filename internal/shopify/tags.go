@@ -0,0 +1,41 @@
+package shopify
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTagLength is the longest tag Shopify accepts on orders and draft orders
+const MaxTagLength = 40
+
+// SanitizeTag makes tag acceptable to Shopify: commas separate tags there, so they become
+// spaces, whitespace is collapsed and the result is cut to MaxTagLength characters
+func SanitizeTag(tag string) string {
+	tag = strings.Join(strings.Fields(strings.ReplaceAll(tag, ",", " ")), " ")
+	if utf8.RuneCountInString(tag) <= MaxTagLength {
+		return tag
+	}
+	runes := []rune(tag)
+	return strings.TrimSpace(string(runes[:MaxTagLength]))
+}
+
+var tagTemplateField = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// RenderTagTemplate fills the {{field}} placeholders of template from values and sanitizes
+// the result. It returns "" when a field has no value, so the tag is left out instead of
+// being sent half empty.
+func RenderTagTemplate(template string, values map[string]string) string {
+	missing := false
+	tag := tagTemplateField.ReplaceAllStringFunc(template, func(placeholder string) string {
+		value := strings.TrimSpace(values[tagTemplateField.FindStringSubmatch(placeholder)[1]])
+		if value == "" {
+			missing = true
+		}
+		return value
+	})
+	if missing {
+		return ""
+	}
+	return SanitizeTag(tag)
+}
@@ -0,0 +1,62 @@
+// Package skunorm normalizes SKUs so that cart items match SKU mappings despite
+// formatting differences such as "scm 8502" vs "SCM  8502" or a partner's own prefix.
+// Mappings store the normalized form when they are written; cart SKUs are normalized
+// with the same rules before they are looked up.
+package skunorm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jafarshop/b2bapi/internal/config"
+)
+
+// Normalizer applies the configured rules: trim, collapse whitespace, case-fold and
+// strip known prefixes, in that order
+type Normalizer struct {
+	caseFold           bool
+	collapseWhitespace bool
+	// prefixes are normalized like SKUs, longest first
+	prefixes []string
+}
+
+// New creates a normalizer from configuration
+func New(cfg config.SKUNormalizationConfig) *Normalizer {
+	n := &Normalizer{
+		caseFold:           cfg.CaseFold,
+		collapseWhitespace: cfg.CollapseWhitespace,
+	}
+	for _, prefix := range cfg.StripPrefixes {
+		if prefix = n.clean(prefix); prefix != "" {
+			n.prefixes = append(n.prefixes, prefix)
+		}
+	}
+	sort.Slice(n.prefixes, func(i, j int) bool { return len(n.prefixes[i]) > len(n.prefixes[j]) })
+	return n
+}
+
+// Normalize returns the form SKUs are compared in. A SKU that is nothing but a prefix is
+// kept as is rather than normalized to "".
+func (n *Normalizer) Normalize(sku string) string {
+	normalized := n.clean(sku)
+	for _, prefix := range n.prefixes {
+		if rest, ok := strings.CutPrefix(normalized, prefix); ok {
+			if rest = strings.TrimSpace(rest); rest != "" {
+				return rest
+			}
+			break
+		}
+	}
+	return normalized
+}
+
+func (n *Normalizer) clean(value string) string {
+	value = strings.TrimSpace(value)
+	if n.collapseWhitespace {
+		value = strings.Join(strings.Fields(value), " ")
+	}
+	if n.caseFold {
+		value = strings.ToUpper(value)
+	}
+	return value
+}
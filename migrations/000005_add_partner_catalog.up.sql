@@ -0,0 +1,9 @@
+-- Per-partner allowed SKU catalog. A partner without rows may order any active SKU.
+CREATE TABLE partner_catalog (
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    sku_mapping_id UUID NOT NULL REFERENCES sku_mappings(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, sku_mapping_id)
+);
+
+CREATE INDEX idx_partner_catalog_sku_mapping_id ON partner_catalog(sku_mapping_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS held_from_status,
+DROP COLUMN IF EXISTS held_at,
+DROP COLUMN IF EXISTS hold_reason;
@@ -0,0 +1,24 @@
+-- Hold state for payment/fraud review
+ALTER TABLE supplier_orders
+ADD COLUMN hold_reason VARCHAR(500),
+ADD COLUMN held_at TIMESTAMP,
+ADD COLUMN held_from_status VARCHAR(50);
+
+-- Partner webhook delivery log
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    supplier_order_id UUID REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    payload JSONB NOT NULL,
+    response_status INTEGER,
+    error TEXT,
+    success BOOLEAN NOT NULL DEFAULT false,
+    duration_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_partner_id ON webhook_deliveries(partner_id);
+CREATE INDEX idx_webhook_deliveries_supplier_order_id ON webhook_deliveries(supplier_order_id);
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries(created_at);
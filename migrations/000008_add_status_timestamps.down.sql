@@ -0,0 +1,3 @@
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS cancelled_at,
+DROP COLUMN IF EXISTS rejected_at;
@@ -0,0 +1,55 @@
+-- Remaining status transition timestamps (confirmed/shipped/delivered added in 000007)
+ALTER TABLE supplier_orders
+ADD COLUMN rejected_at TIMESTAMPTZ,
+ADD COLUMN cancelled_at TIMESTAMPTZ;
+
+-- Backfill from the first matching status_change event
+UPDATE supplier_orders o
+SET confirmed_at = e.changed_at
+FROM (
+    SELECT supplier_order_id, MIN(created_at) AS changed_at
+    FROM order_events
+    WHERE event_type = 'status_change' AND event_data->>'to' = 'CONFIRMED'
+    GROUP BY supplier_order_id
+) e
+WHERE o.id = e.supplier_order_id AND o.confirmed_at IS NULL;
+
+UPDATE supplier_orders o
+SET rejected_at = e.changed_at
+FROM (
+    SELECT supplier_order_id, MIN(created_at) AS changed_at
+    FROM order_events
+    WHERE event_type = 'status_change' AND event_data->>'to' = 'REJECTED'
+    GROUP BY supplier_order_id
+) e
+WHERE o.id = e.supplier_order_id AND o.rejected_at IS NULL;
+
+UPDATE supplier_orders o
+SET shipped_at = e.changed_at
+FROM (
+    SELECT supplier_order_id, MIN(created_at) AS changed_at
+    FROM order_events
+    WHERE event_type = 'status_change' AND event_data->>'to' = 'SHIPPED'
+    GROUP BY supplier_order_id
+) e
+WHERE o.id = e.supplier_order_id AND o.shipped_at IS NULL;
+
+UPDATE supplier_orders o
+SET delivered_at = e.changed_at
+FROM (
+    SELECT supplier_order_id, MIN(created_at) AS changed_at
+    FROM order_events
+    WHERE event_type = 'status_change' AND event_data->>'to' = 'DELIVERED'
+    GROUP BY supplier_order_id
+) e
+WHERE o.id = e.supplier_order_id AND o.delivered_at IS NULL;
+
+UPDATE supplier_orders o
+SET cancelled_at = e.changed_at
+FROM (
+    SELECT supplier_order_id, MIN(created_at) AS changed_at
+    FROM order_events
+    WHERE event_type = 'status_change' AND event_data->>'to' = 'CANCELLED'
+    GROUP BY supplier_order_id
+) e
+WHERE o.id = e.supplier_order_id AND o.cancelled_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_partner_id_created_at;
+DROP TABLE IF EXISTS api_request_logs;
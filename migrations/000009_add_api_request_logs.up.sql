@@ -0,0 +1,13 @@
+-- Partner API request log for usage and latency reporting
+CREATE TABLE api_request_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    status INTEGER NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_request_logs_partner_id_created_at ON api_request_logs(partner_id, created_at);
+CREATE INDEX idx_webhook_deliveries_partner_id_created_at ON webhook_deliveries(partner_id, created_at);
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_supplier_orders_partner_id ON supplier_orders(partner_id);
+CREATE INDEX IF NOT EXISTS idx_supplier_orders_status ON supplier_orders(status);
+
+DROP INDEX IF EXISTS idx_supplier_orders_status_created_at;
+DROP INDEX IF EXISTS idx_supplier_orders_partner_id_created_at;
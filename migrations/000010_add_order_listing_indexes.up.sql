@@ -0,0 +1,9 @@
+-- Composite indexes matching the order listings (filter, then created_at DESC, id DESC),
+-- so both the first page and keyset pages are index range scans without a sort.
+-- (partner_id, partner_order_id) is already covered by the UNIQUE constraint from 000001.
+CREATE INDEX idx_supplier_orders_partner_id_created_at ON supplier_orders(partner_id, created_at DESC, id DESC);
+CREATE INDEX idx_supplier_orders_status_created_at ON supplier_orders(status, created_at DESC, id DESC);
+
+-- Superseded by the composite indexes above
+DROP INDEX IF EXISTS idx_supplier_orders_partner_id;
+DROP INDEX IF EXISTS idx_supplier_orders_status;
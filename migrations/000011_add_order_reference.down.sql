@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_supplier_orders_reference;
+
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS reference;
+
+DROP SEQUENCE IF EXISTS supplier_order_reference_seq;
@@ -0,0 +1,16 @@
+-- Human-friendly order references, e.g. B2B-2024-000123
+CREATE SEQUENCE supplier_order_reference_seq;
+
+ALTER TABLE supplier_orders
+ADD COLUMN reference VARCHAR(50);
+
+-- Backfill existing orders in creation order with the default prefix
+UPDATE supplier_orders o
+SET reference = 'B2B-' || to_char(o.created_at AT TIME ZONE 'UTC', 'YYYY') || '-' || lpad(r.n::text, 6, '0')
+FROM (
+    SELECT id, nextval('supplier_order_reference_seq') AS n
+    FROM (SELECT id FROM supplier_orders ORDER BY created_at, id) ordered
+) r
+WHERE o.id = r.id;
+
+CREATE UNIQUE INDEX idx_supplier_orders_reference ON supplier_orders(reference);
@@ -0,0 +1,5 @@
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS locale;
+
+ALTER TABLE partners
+DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,6 @@
+-- Language used for customer-facing notification text (en, ar)
+ALTER TABLE partners
+ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+ALTER TABLE supplier_orders
+ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'en';
@@ -0,0 +1,3 @@
+-- E.164 form of customer_phone (which keeps what the partner sent)
+ALTER TABLE supplier_orders
+ADD COLUMN customer_phone_normalized VARCHAR(20);
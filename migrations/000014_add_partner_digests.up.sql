@@ -0,0 +1,9 @@
+-- Daily email digest opt-in per partner
+CREATE TABLE partner_digest_subscriptions (
+    partner_id UUID PRIMARY KEY REFERENCES partners(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    send_time VARCHAR(5) NOT NULL, -- HH:MM in DIGEST_TIMEZONE
+    last_sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS shopify_customer_id,
+DROP COLUMN IF EXISTS customer_email;
@@ -0,0 +1,4 @@
+-- Customer email from the cart and the Shopify customer the order is linked to
+ALTER TABLE supplier_orders
+ADD COLUMN customer_email VARCHAR(255),
+ADD COLUMN shopify_customer_id BIGINT;
@@ -0,0 +1,3 @@
+-- Tax amount from the partner's cart (sent to Shopify in TAX_MODE=cart)
+ALTER TABLE supplier_orders
+ADD COLUMN cart_tax DECIMAL(10, 2) NOT NULL DEFAULT 0;
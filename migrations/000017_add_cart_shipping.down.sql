@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_order_events_event_type_created_at;
+
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS cart_shipping;
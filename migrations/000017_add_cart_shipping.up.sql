@@ -0,0 +1,5 @@
+-- Shipping amount from the partner's cart (not sent to Shopify, needed to compare totals)
+ALTER TABLE supplier_orders
+ADD COLUMN cart_shipping DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_order_events_event_type_created_at ON order_events(event_type, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE partners
+DROP COLUMN IF EXISTS invoice_email,
+DROP COLUMN IF EXISTS payment_terms;
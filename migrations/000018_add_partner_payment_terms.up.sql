@@ -0,0 +1,4 @@
+-- Credit-terms partners get Shopify draft orders sent as invoices instead of completed orders
+ALTER TABLE partners
+ADD COLUMN payment_terms VARCHAR(20) NOT NULL DEFAULT 'prepaid',
+ADD COLUMN invoice_email VARCHAR(255);
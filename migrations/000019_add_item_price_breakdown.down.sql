@@ -0,0 +1,3 @@
+ALTER TABLE supplier_order_items
+DROP COLUMN IF EXISTS discount,
+DROP COLUMN IF EXISTS list_price;
@@ -0,0 +1,10 @@
+-- Per-unit list price and discount next to the final unit price (price)
+ALTER TABLE supplier_order_items
+ADD COLUMN list_price DECIMAL(10, 2),
+ADD COLUMN discount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- Existing items were recorded without a breakdown: the charged price is the list price
+UPDATE supplier_order_items SET list_price = price;
+
+ALTER TABLE supplier_order_items
+ALTER COLUMN list_price SET NOT NULL;
@@ -0,0 +1,5 @@
+-- Partners created before 201/202 cart responses keep getting 200 on /v1 until they opt out
+ALTER TABLE partners
+ADD COLUMN legacy_status_codes BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE partners SET legacy_status_codes = true;
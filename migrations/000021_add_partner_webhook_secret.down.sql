@@ -0,0 +1,2 @@
+ALTER TABLE partners
+DROP COLUMN IF EXISTS webhook_secret;
@@ -0,0 +1,3 @@
+-- Secret for signing partner webhooks (X-B2B-Signature)
+ALTER TABLE partners
+ADD COLUMN webhook_secret VARCHAR(100);
@@ -0,0 +1,8 @@
+ALTER TABLE supplier_order_items
+DROP COLUMN IF EXISTS height_cm,
+DROP COLUMN IF EXISTS width_cm,
+DROP COLUMN IF EXISTS length_cm,
+DROP COLUMN IF EXISTS weight_grams;
+
+ALTER TABLE sku_mappings
+DROP COLUMN IF EXISTS weight_grams;
@@ -0,0 +1,10 @@
+-- Variant weight cached from Shopify by the SKU sync
+ALTER TABLE sku_mappings
+ADD COLUMN weight_grams INTEGER;
+
+-- Per-unit weight and dimensions of order items, for shipping rates
+ALTER TABLE supplier_order_items
+ADD COLUMN weight_grams INTEGER,
+ADD COLUMN length_cm DECIMAL(8, 2),
+ADD COLUMN width_cm DECIMAL(8, 2),
+ADD COLUMN height_cm DECIMAL(8, 2);
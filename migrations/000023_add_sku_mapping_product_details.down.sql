@@ -0,0 +1,8 @@
+ALTER TABLE sku_mappings
+DROP COLUMN IF EXISTS image_urls,
+DROP COLUMN IF EXISTS image_url,
+DROP COLUMN IF EXISTS barcode,
+DROP COLUMN IF EXISTS product_type,
+DROP COLUMN IF EXISTS vendor,
+DROP COLUMN IF EXISTS variant_title,
+DROP COLUMN IF EXISTS product_title;
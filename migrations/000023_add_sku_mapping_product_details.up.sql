@@ -0,0 +1,9 @@
+-- Product details cached from Shopify by the SKU sync, served in the partner catalog feed
+ALTER TABLE sku_mappings
+ADD COLUMN product_title VARCHAR(255),
+ADD COLUMN variant_title VARCHAR(255),
+ADD COLUMN vendor VARCHAR(255),
+ADD COLUMN product_type VARCHAR(255),
+ADD COLUMN barcode VARCHAR(255),
+ADD COLUMN image_url TEXT,
+ADD COLUMN image_urls TEXT[] NOT NULL DEFAULT '{}';
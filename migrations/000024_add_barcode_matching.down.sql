@@ -0,0 +1,4 @@
+ALTER TABLE supplier_order_items
+DROP COLUMN IF EXISTS match_method;
+
+DROP INDEX IF EXISTS idx_sku_mappings_barcode;
@@ -0,0 +1,8 @@
+-- Cart items without a known SKU are matched by barcode
+CREATE INDEX idx_sku_mappings_barcode ON sku_mappings(barcode) WHERE barcode IS NOT NULL;
+
+-- How a supplier item was matched to its SKU mapping: sku or barcode
+ALTER TABLE supplier_order_items
+ADD COLUMN match_method VARCHAR(20);
+
+UPDATE supplier_order_items SET match_method = 'sku' WHERE is_supplier_item = true;
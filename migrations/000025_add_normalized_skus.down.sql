@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sku_mappings_normalized_sku;
+
+ALTER TABLE sku_mappings
+DROP COLUMN IF EXISTS normalized_sku;
@@ -0,0 +1,11 @@
+-- SKUs as compared with cart SKUs (see internal/skunorm). Backfilled with the default
+-- rules: trimmed, whitespace collapsed, upper case. Re-run the SKU sync after changing them.
+ALTER TABLE sku_mappings
+ADD COLUMN normalized_sku VARCHAR(255);
+
+UPDATE sku_mappings SET normalized_sku = UPPER(REGEXP_REPLACE(BTRIM(sku), '\s+', ' ', 'g'));
+
+ALTER TABLE sku_mappings
+ALTER COLUMN normalized_sku SET NOT NULL;
+
+CREATE INDEX idx_sku_mappings_normalized_sku ON sku_mappings(normalized_sku);
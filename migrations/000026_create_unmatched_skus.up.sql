@@ -0,0 +1,13 @@
+-- Cart items that did not match a supplier SKU, per partner, for the catalog team
+CREATE TABLE unmatched_skus (
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    sku VARCHAR(255) NOT NULL,
+    title VARCHAR(500) NOT NULL, -- latest title the partner sent
+    reason VARCHAR(50) NOT NULL, -- latest reason (unknown_sku, inactive, not_in_catalog, ...)
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, sku)
+);
+
+CREATE INDEX idx_unmatched_skus_last_seen_at ON unmatched_skus(last_seen_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_supplier_orders_partner_id_status;
+
+ALTER TABLE partners
+DROP COLUMN IF EXISTS credit_limit_action,
+DROP COLUMN IF EXISTS credit_limit;
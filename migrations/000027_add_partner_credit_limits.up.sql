@@ -0,0 +1,7 @@
+-- Per-partner credit limit on outstanding (unpaid) order value; NULL means no limit
+ALTER TABLE partners
+ADD COLUMN credit_limit DECIMAL(12, 2),
+ADD COLUMN credit_limit_action VARCHAR(10) NOT NULL DEFAULT 'hold'; -- hold or reject
+
+-- Outstanding value is summed over a partner's open orders
+CREATE INDEX idx_supplier_orders_partner_id_status ON supplier_orders(partner_id, status);
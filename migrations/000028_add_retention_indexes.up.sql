@@ -0,0 +1,2 @@
+-- The retention job deletes idempotency keys by age
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys(created_at);
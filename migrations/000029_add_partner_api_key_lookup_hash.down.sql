@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_partners_api_key_lookup_hash;
+
+ALTER TABLE partners
+DROP COLUMN IF EXISTS api_key_lookup_hash;
@@ -0,0 +1,6 @@
+-- SHA-256 of the API key, so authentication finds the partner with an index lookup instead of
+-- bcrypt-checking every active partner. Existing partners get it on their next successful request.
+ALTER TABLE partners
+ADD COLUMN api_key_lookup_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_partners_api_key_lookup_hash ON partners(api_key_lookup_hash);
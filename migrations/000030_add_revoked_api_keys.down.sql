@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS revoked_api_keys;
+
+-- Fails while encrypted secrets are longer than 100 characters
+ALTER TABLE partners
+ALTER COLUMN webhook_secret TYPE VARCHAR(100);
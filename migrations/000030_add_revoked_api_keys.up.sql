@@ -0,0 +1,14 @@
+-- Encrypted webhook secrets are longer than the plain ones
+ALTER TABLE partners
+ALTER COLUMN webhook_secret TYPE TEXT;
+
+-- API keys revoked by an admin, rejected on every request
+CREATE TABLE revoked_api_keys (
+    api_key_hash VARCHAR(255) PRIMARY KEY,
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    revoked_by VARCHAR(255) NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_revoked_api_keys_partner_id ON revoked_api_keys(partner_id);
@@ -0,0 +1,7 @@
+-- Webhook event types each partner receives; partners without rows receive every event
+CREATE TABLE webhook_subscriptions (
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, event_type)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS channel;
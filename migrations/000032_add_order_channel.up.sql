@@ -0,0 +1,3 @@
+-- Partner sales channel the cart came from (e.g. web, app), passed on to Shopify
+ALTER TABLE supplier_orders
+ADD COLUMN channel VARCHAR(50);
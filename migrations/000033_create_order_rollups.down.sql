@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS order_rollup_days;
+DROP TABLE IF EXISTS order_daily_rollups;
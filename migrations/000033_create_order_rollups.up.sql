@@ -0,0 +1,21 @@
+-- Orders per UTC day (of created_at), partner and current status, so reports do not scan
+-- supplier_orders. Stage columns count the orders that reached each stage, as in the funnel.
+CREATE TABLE order_daily_rollups (
+    day DATE NOT NULL,
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    orders INTEGER NOT NULL,
+    confirmed INTEGER NOT NULL,
+    shipped INTEGER NOT NULL,
+    delivered INTEGER NOT NULL,
+    rejected INTEGER NOT NULL,
+    cancelled INTEGER NOT NULL,
+    revenue DECIMAL(14, 2) NOT NULL,
+    PRIMARY KEY (day, partner_id, status)
+);
+
+-- Days that have been rolled up; a day without orders has no rollup rows but is still here
+CREATE TABLE order_rollup_days (
+    day DATE PRIMARY KEY,
+    rolled_up_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
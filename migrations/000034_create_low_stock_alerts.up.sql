@@ -0,0 +1,12 @@
+-- Low-stock alerts sent to partners; a variant is alerted again only after it was restocked
+-- (its row is removed when the stock is back at or above the threshold)
+CREATE TABLE low_stock_alerts (
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    shopify_variant_id BIGINT NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    alerted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, shopify_variant_id)
+);
+
+CREATE INDEX idx_low_stock_alerts_variant ON low_stock_alerts(shopify_variant_id);
@@ -0,0 +1,6 @@
+-- Enumerated rejection reason (see domain.RejectionCode); rejection_reason keeps the free text
+ALTER TABLE supplier_orders
+ADD COLUMN rejection_code VARCHAR(50);
+
+-- Orders rejected with free text only
+UPDATE supplier_orders SET rejection_code = 'OTHER' WHERE status = 'REJECTED';
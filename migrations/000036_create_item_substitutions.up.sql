@@ -0,0 +1,21 @@
+-- Substitutions proposed by admins for order items that cannot be supplied; the partner
+-- accepts or declines each one. At most one proposal per item is open at a time.
+CREATE TABLE item_substitutions (
+    id UUID PRIMARY KEY,
+    supplier_order_id UUID NOT NULL REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    supplier_order_item_id UUID NOT NULL REFERENCES supplier_order_items(id) ON DELETE CASCADE,
+    original_sku VARCHAR(255) NOT NULL,
+    original_price DECIMAL(10, 2) NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    shopify_variant_id BIGINT NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PROPOSED',
+    decline_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    responded_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_item_substitutions_order ON item_substitutions(supplier_order_id, created_at);
+CREATE UNIQUE INDEX idx_item_substitutions_open ON item_substitutions(supplier_order_item_id) WHERE status = 'PROPOSED';
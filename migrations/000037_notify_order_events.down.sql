@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS notify_order_events ON order_events;
+DROP FUNCTION IF EXISTS notify_order_event();
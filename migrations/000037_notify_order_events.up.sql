@@ -0,0 +1,20 @@
+-- Announce every order event on the order_events channel (LISTEN order_events), so admin
+-- dashboards can be updated live by whichever instance they are connected to. The payload
+-- stays far below the 8000 byte NOTIFY limit: listeners look the order up themselves.
+CREATE OR REPLACE FUNCTION notify_order_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('order_events', json_build_object(
+        'id', NEW.id,
+        'supplier_order_id', NEW.supplier_order_id,
+        'event_type', NEW.event_type,
+        'from', NEW.event_data->>'from',
+        'to', NEW.event_data->>'to',
+        'created_at', NEW.created_at
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notify_order_events AFTER INSERT ON order_events
+    FOR EACH ROW EXECUTE FUNCTION notify_order_event();
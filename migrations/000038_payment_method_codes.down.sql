@@ -0,0 +1 @@
+ALTER TABLE supplier_orders DROP CONSTRAINT IF EXISTS supplier_orders_payment_method_check;
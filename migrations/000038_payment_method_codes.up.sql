@@ -0,0 +1,16 @@
+-- Payment methods are codes: cod, prepaid or credit
+UPDATE supplier_orders
+SET payment_method = CASE
+    WHEN lower(trim(payment_method)) IN ('cod', 'cash on delivery', 'cash on delivery (cod)', 'card on delivery') THEN 'cod'
+    WHEN lower(trim(payment_method)) IN ('prepaid', 'credit card', 'zaincash') THEN 'prepaid'
+    WHEN lower(trim(payment_method)) = 'credit' THEN 'credit'
+    ELSE payment_method
+END
+WHERE payment_method IS NOT NULL;
+
+UPDATE supplier_orders SET payment_method = NULL WHERE trim(payment_method) = '';
+
+-- NOT VALID: older orders with other free-text values are kept as they are
+ALTER TABLE supplier_orders
+ADD CONSTRAINT supplier_orders_payment_method_check
+CHECK (payment_method IN ('cod', 'prepaid', 'credit')) NOT VALID;
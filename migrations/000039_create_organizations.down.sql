@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_partners_organization_id;
+ALTER TABLE partners DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organizations;
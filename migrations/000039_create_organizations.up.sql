@@ -0,0 +1,13 @@
+-- Organizations group partners, e.g. one API key per store branch of the same merchant.
+-- Orders stay attributed to the partner (key) that submitted them.
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE partners
+ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_partners_organization_id ON partners(organization_id) WHERE organization_id IS NOT NULL;
@@ -0,0 +1,7 @@
+-- The highest credit limit usage threshold (percent) each partner was alerted about. A
+-- threshold is alerted again only after usage dropped below it (threshold is lowered).
+CREATE TABLE credit_limit_alerts (
+    partner_id UUID PRIMARY KEY REFERENCES partners(id) ON DELETE CASCADE,
+    threshold INTEGER NOT NULL,
+    alerted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
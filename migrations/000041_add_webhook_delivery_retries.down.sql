@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_retry_of;
+
+ALTER TABLE webhook_deliveries
+DROP COLUMN IF EXISTS retry_of;
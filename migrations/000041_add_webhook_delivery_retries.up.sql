@@ -0,0 +1,6 @@
+-- Deliveries resent by the partner (POST /v1/webhooks/deliveries/:id/retry) point at the
+-- original delivery, so a delivery's attempts can be listed together
+ALTER TABLE webhook_deliveries
+ADD COLUMN retry_of UUID REFERENCES webhook_deliveries(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_webhook_deliveries_retry_of ON webhook_deliveries(retry_of) WHERE retry_of IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Announcements to partner integrations (GET /v1/changelog): new fields, deprecations,
+-- maintenance windows. affects lists the endpoints or fields concerned.
+CREATE TABLE changelog_entries (
+    id UUID PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    api_version VARCHAR(10),
+    affects TEXT[] NOT NULL DEFAULT '{}',
+    effective_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    published_by UUID NOT NULL REFERENCES partners(id),
+    published_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_changelog_entries_published_at ON changelog_entries(published_at DESC, id DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_supplier_orders_content_hash;
+
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,6 @@
+-- Hash of the normalized cart an order was created from (partner order ID excluded), to
+-- recognize resubmissions of the same cart without an idempotency key
+ALTER TABLE supplier_orders
+ADD COLUMN content_hash VARCHAR(64);
+
+CREATE INDEX idx_supplier_orders_content_hash ON supplier_orders(partner_id, content_hash, created_at DESC) WHERE content_hash IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE supplier_orders
+DROP COLUMN IF EXISTS shopify_order_name,
+DROP COLUMN IF EXISTS shopify_draft_order_name;
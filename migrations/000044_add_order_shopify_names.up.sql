@@ -0,0 +1,4 @@
+-- Human-facing Shopify names (e.g. #D12 and #1001), so staff can search for orders in Shopify admin
+ALTER TABLE supplier_orders
+ADD COLUMN shopify_draft_order_name VARCHAR(64),
+ADD COLUMN shopify_order_name VARCHAR(64);
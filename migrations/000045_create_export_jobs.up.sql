@@ -0,0 +1,19 @@
+-- Generated export files (POST /v1/admin/exports). The file is kept in content until
+-- expires_at and downloaded through a signed, short-lived link, so no API key is needed.
+CREATE TABLE export_jobs (
+    id UUID PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    content BYTEA,
+    content_type VARCHAR(100),
+    filename VARCHAR(255),
+    row_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by UUID NOT NULL REFERENCES partners(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_export_jobs_expires_at ON export_jobs(expires_at);
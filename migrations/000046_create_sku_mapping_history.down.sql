@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS record_sku_mapping_changes ON sku_mappings;
+DROP FUNCTION IF EXISTS record_sku_mapping_change();
+DROP TRIGGER IF EXISTS bump_sku_mapping_version ON sku_mappings;
+DROP FUNCTION IF EXISTS bump_sku_mapping_version();
+DROP TABLE IF EXISTS sku_mapping_history;
+ALTER TABLE sku_mappings DROP COLUMN IF EXISTS version;
@@ -0,0 +1,82 @@
+-- Every change to what a SKU maps to, so a wrong shipment can be traced to the mapping
+-- change behind it. Rows are written by a trigger, so manual SQL fixes are recorded too.
+-- The API names its source and actor with set_config('b2b.change_source' / 'b2b.changed_by')
+-- in the writing transaction; otherwise the source is 'sql' and the actor the database user.
+ALTER TABLE sku_mappings ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE sku_mapping_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sku_mapping_id UUID NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    version INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    previous_normalized_sku VARCHAR(255),
+    normalized_sku VARCHAR(255),
+    previous_shopify_product_id BIGINT,
+    shopify_product_id BIGINT,
+    previous_shopify_variant_id BIGINT,
+    shopify_variant_id BIGINT,
+    previous_is_active BOOLEAN,
+    is_active BOOLEAN,
+    source VARCHAR(50) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sku_mapping_history_sku ON sku_mapping_history(sku, changed_at DESC);
+CREATE INDEX idx_sku_mapping_history_variant ON sku_mapping_history(shopify_variant_id);
+CREATE INDEX idx_sku_mapping_history_previous_variant ON sku_mapping_history(previous_shopify_variant_id);
+CREATE INDEX idx_sku_mapping_history_changed_at ON sku_mapping_history(changed_at DESC, id DESC);
+
+-- A new version whenever what the SKU maps to changes; the Shopify sync rewrites every
+-- mapping and only bumps the ones that changed
+CREATE OR REPLACE FUNCTION bump_sku_mapping_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (NEW.sku, NEW.normalized_sku, NEW.shopify_product_id, NEW.shopify_variant_id, NEW.is_active)
+        IS DISTINCT FROM (OLD.sku, OLD.normalized_sku, OLD.shopify_product_id, OLD.shopify_variant_id, OLD.is_active) THEN
+        NEW.version = OLD.version + 1;
+    ELSE
+        NEW.version = OLD.version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bump_sku_mapping_version BEFORE UPDATE ON sku_mappings
+    FOR EACH ROW EXECUTE FUNCTION bump_sku_mapping_version();
+
+CREATE OR REPLACE FUNCTION record_sku_mapping_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    change_source TEXT := COALESCE(NULLIF(current_setting('b2b.change_source', true), ''), 'sql');
+    change_actor TEXT := COALESCE(NULLIF(current_setting('b2b.changed_by', true), ''), current_user);
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO sku_mapping_history (sku_mapping_id, sku, version, action,
+            normalized_sku, shopify_product_id, shopify_variant_id, is_active, source, changed_by)
+        VALUES (NEW.id, NEW.sku, NEW.version, 'created',
+            NEW.normalized_sku, NEW.shopify_product_id, NEW.shopify_variant_id, NEW.is_active, change_source, change_actor);
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF NEW.version <> OLD.version THEN
+            INSERT INTO sku_mapping_history (sku_mapping_id, sku, version, action,
+                previous_normalized_sku, normalized_sku, previous_shopify_product_id, shopify_product_id,
+                previous_shopify_variant_id, shopify_variant_id, previous_is_active, is_active, source, changed_by)
+            VALUES (NEW.id, NEW.sku, NEW.version, 'updated',
+                OLD.normalized_sku, NEW.normalized_sku, OLD.shopify_product_id, NEW.shopify_product_id,
+                OLD.shopify_variant_id, NEW.shopify_variant_id, OLD.is_active, NEW.is_active, change_source, change_actor);
+        END IF;
+    ELSE
+        INSERT INTO sku_mapping_history (sku_mapping_id, sku, version, action,
+            previous_normalized_sku, previous_shopify_product_id, previous_shopify_variant_id, previous_is_active,
+            source, changed_by)
+        VALUES (OLD.id, OLD.sku, OLD.version + 1, 'deleted',
+            OLD.normalized_sku, OLD.shopify_product_id, OLD.shopify_variant_id, OLD.is_active,
+            change_source, change_actor);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_sku_mapping_changes AFTER INSERT OR UPDATE OR DELETE ON sku_mappings
+    FOR EACH ROW EXECUTE FUNCTION record_sku_mapping_change();
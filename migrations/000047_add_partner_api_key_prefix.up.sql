@@ -0,0 +1,6 @@
+-- Identifying prefix of the partner's API key (e.g. b2b_zain_v2), so a key seen in logs, support
+-- tickets or error reports can be traced to its partner. NULL for keys issued before the format.
+ALTER TABLE partners
+ADD COLUMN api_key_prefix VARCHAR(32);
+
+CREATE INDEX idx_partners_api_key_prefix ON partners(api_key_prefix);
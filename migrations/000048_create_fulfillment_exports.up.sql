@@ -0,0 +1,9 @@
+-- Confirmed orders sent to the 3PL in a flat file. A row claims the order, so several instances
+-- never export it twice; rows of a file that failed to upload are removed again.
+CREATE TABLE fulfillment_exports (
+    supplier_order_id UUID PRIMARY KEY REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    file_key VARCHAR(1024) NOT NULL,
+    exported_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fulfillment_exports_file_key ON fulfillment_exports(file_key);
@@ -0,0 +1,10 @@
+-- Staff working an order in the admin review queue. One row per order, so two agents never
+-- hold the same order; claiming an order someone else holds fails until they release it.
+CREATE TABLE review_assignments (
+    supplier_order_id UUID PRIMARY KEY REFERENCES supplier_orders(id) ON DELETE CASCADE,
+    assignee_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    assigned_by UUID NOT NULL,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_review_assignments_assignee_id ON review_assignments(assignee_id);
@@ -0,0 +1 @@
+ALTER TABLE supplier_orders DROP COLUMN IF EXISTS held_seconds;
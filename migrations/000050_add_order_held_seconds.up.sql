@@ -0,0 +1,3 @@
+-- Total time an order spent on hold, so confirmation timers (pending expiry, the confirmation
+-- SLA) pause while it is held
+ALTER TABLE supplier_orders ADD COLUMN held_seconds BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Partners whose orders may get item substitutions keep their draft orders open until the
+-- order is confirmed, since a completed Shopify order can no longer be substituted
+ALTER TABLE partners ADD COLUMN substitutions BOOLEAN NOT NULL DEFAULT false;
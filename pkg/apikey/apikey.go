@@ -0,0 +1,172 @@
+// Package apikey generates partner API keys in a recognisable format and finds them in text.
+//
+// A key looks like b2b_zain_v2_<random><checksum>: the fixed "b2b" marker, the partner's
+// short code, the key format version, 32 random base62 characters and a 6 character CRC32
+// checksum of everything before it. The prefix (b2b_zain_v2) names the partner in logs and
+// support tickets without revealing the key; the checksum lets scanners tell real keys from
+// look-alikes without a database lookup.
+//
+//	key, err := apikey.Generate(apikey.ShortCode("Zain Shop"))
+//	...
+//	prefix, ok := apikey.Parse(key) // prefix.String() == "b2b_zain_v2"
+package apikey
+
+import (
+	"crypto/rand"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the format version of generated keys
+const Version = 2
+
+const (
+	marker         = "b2b"
+	randomLength   = 32
+	checksumLength = 6
+	// MaxShortCodeLength bounds partner short codes
+	MaxShortCodeLength = 12
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// keyPattern matches anything shaped like a key; Parse checks the checksum
+var keyPattern = regexp.MustCompile(`\bb2b_([a-z0-9]{1,12})_v([0-9]{1,3})_([0-9A-Za-z]{38})\b`)
+
+// Prefix is the identifying, non-secret part of a key
+type Prefix struct {
+	ShortCode string
+	Version   int
+}
+
+// String returns the prefix as it appears in keys, e.g. b2b_zain_v2
+func (p Prefix) String() string {
+	return fmt.Sprintf("%s_%s_v%d", marker, p.ShortCode, p.Version)
+}
+
+// ShortCode derives a partner short code from its name: lower case letters and digits only,
+// at most MaxShortCodeLength of them, "partner" when the name has none
+func ShortCode(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			if b.Len() == MaxShortCodeLength {
+				break
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "partner"
+	}
+	return b.String()
+}
+
+// Generate returns a new key for the partner with the short code
+func Generate(shortCode string) (string, error) {
+	if !validShortCode(shortCode) {
+		return "", fmt.Errorf("apikey: invalid short code %q", shortCode)
+	}
+
+	random, err := randomBase62(randomLength)
+	if err != nil {
+		return "", err
+	}
+	body := Prefix{ShortCode: shortCode, Version: Version}.String() + "_" + random
+	return body + checksum(body), nil
+}
+
+// Parse returns the prefix of a key in this format; ok is false for anything else,
+// including keys with a wrong checksum and keys issued before the format existed
+func Parse(key string) (prefix Prefix, ok bool) {
+	match := keyPattern.FindStringSubmatch(key)
+	if match == nil || match[0] != key {
+		return Prefix{}, false
+	}
+	if !validChecksum(key) {
+		return Prefix{}, false
+	}
+	version, err := strconv.Atoi(match[2])
+	if err != nil {
+		return Prefix{}, false
+	}
+	return Prefix{ShortCode: match[1], Version: version}, true
+}
+
+// Find returns the keys in text, in the order they appear
+func Find(text string) []string {
+	var keys []string
+	for _, candidate := range keyPattern.FindAllString(text, -1) {
+		if validChecksum(candidate) {
+			keys = append(keys, candidate)
+		}
+	}
+	return keys
+}
+
+// Redact replaces the keys in text with their prefix followed by _[REDACTED] and returns the
+// prefixes of the keys it replaced
+func Redact(text string) (string, []Prefix) {
+	var found []Prefix
+	redacted := keyPattern.ReplaceAllStringFunc(text, func(candidate string) string {
+		prefix, ok := Parse(candidate)
+		if !ok {
+			return candidate
+		}
+		found = append(found, prefix)
+		return prefix.String() + "_[REDACTED]"
+	})
+	return redacted, found
+}
+
+func validShortCode(shortCode string) bool {
+	if shortCode == "" || len(shortCode) > MaxShortCodeLength {
+		return false
+	}
+	for _, r := range shortCode {
+		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+func validChecksum(key string) bool {
+	if len(key) <= checksumLength {
+		return false
+	}
+	body := key[:len(key)-checksumLength]
+	return checksum(body) == key[len(key)-checksumLength:]
+}
+
+// checksum is the CRC32 of body in checksumLength base62 characters
+func checksum(body string) string {
+	n := crc32.ChecksumIEEE([]byte(body))
+	out := make([]byte, checksumLength)
+	for i := checksumLength - 1; i >= 0; i-- {
+		out[i] = base62[n%62]
+		n /= 62
+	}
+	return string(out)
+}
+
+// randomBase62 returns n uniformly random base62 characters
+func randomBase62(n int) (string, error) {
+	out := make([]byte, 0, n)
+	buf := make([]byte, n*2)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("apikey: failed to generate key: %w", err)
+		}
+		for _, b := range buf {
+			// 248 is the largest multiple of 62 below 256; rejecting the rest avoids bias
+			if b < 248 && len(out) < n {
+				out = append(out, base62[b%62])
+			}
+		}
+	}
+	return string(out), nil
+}
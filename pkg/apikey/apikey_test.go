@@ -0,0 +1,70 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateParse(t *testing.T) {
+	key, err := Generate("zain")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasPrefix(key, "b2b_zain_v2_") || len(key) != len("b2b_zain_v2_")+randomLength+checksumLength {
+		t.Fatalf("Generate() = %q, want b2b_zain_v2_ and %d characters", key, randomLength+checksumLength)
+	}
+
+	prefix, ok := Parse(key)
+	if !ok || prefix.ShortCode != "zain" || prefix.Version != Version || prefix.String() != "b2b_zain_v2" {
+		t.Errorf("Parse(%q) = %+v, %v", key, prefix, ok)
+	}
+
+	// One changed character breaks the checksum
+	last := key[len(key)-1]
+	tampered := key[:len(key)-1] + string(map[bool]byte{true: 'b', false: 'a'}[last == 'a'])
+	for _, invalid := range []string{tampered, "zain-api-key-12345", key + "x", " " + key} {
+		if _, ok := Parse(invalid); ok {
+			t.Errorf("Parse(%q) ok, want not a key", invalid)
+		}
+	}
+
+	if _, err := Generate("Zain Shop"); err == nil {
+		t.Error("Generate() with an invalid short code succeeded")
+	}
+}
+
+func TestShortCode(t *testing.T) {
+	tests := map[string]string{
+		"Zain Shop":               "zainshop",
+		"Al-Nour Electronics LLC": "alnourelectr",
+		"متجر":                    "partner",
+		"7eleven":                 "7eleven",
+	}
+	for name, want := range tests {
+		if got := ShortCode(name); got != want {
+			t.Errorf("ShortCode(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFindRedact(t *testing.T) {
+	key, _ := Generate("zain")
+	other, _ := Generate("nour")
+	lookalike := "b2b_fake_v2_" + strings.Repeat("a", randomLength+checksumLength)
+	text := "request failed: Authorization: Bearer " + key + " (retried with " + other + ", " + lookalike + ")"
+
+	if found := Find(text); len(found) != 2 || found[0] != key || found[1] != other {
+		t.Errorf("Find() = %v, want the two generated keys", found)
+	}
+
+	redacted, prefixes := Redact(text)
+	if strings.Contains(redacted, key) || strings.Contains(redacted, other) {
+		t.Errorf("Redact() = %q, still has a key", redacted)
+	}
+	if !strings.Contains(redacted, "Bearer b2b_zain_v2_[REDACTED]") || !strings.Contains(redacted, lookalike) {
+		t.Errorf("Redact() = %q, want the keys' prefixes and the look-alike kept", redacted)
+	}
+	if len(prefixes) != 2 || prefixes[0].ShortCode != "zain" || prefixes[1].ShortCode != "nour" {
+		t.Errorf("Redact() prefixes = %v", prefixes)
+	}
+}
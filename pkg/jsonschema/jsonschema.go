@@ -0,0 +1,476 @@
+// Package jsonschema validates JSON documents against JSON Schemas (draft 2020-12).
+//
+// Only the keywords the B2B API's published schemas use are supported: type, enum, const,
+// properties, required, additionalProperties, items, minItems, maxItems, minLength,
+// maxLength, pattern, format (email, uuid, date-time, uri), minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum and $ref to "#/$defs/<name>". Annotations ($schema,
+// $id, title, description, examples) are ignored; any other keyword is a compile error, so
+// a schema never silently checks less than it says.
+//
+// Errors name the failing value the way the API reports fields: items[0].price.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	Types                []string
+	Enum                 []interface{}
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *Schema // nil allows any; a false schema has Never set
+	Items                *Schema
+	MinItems, MaxItems   *int
+	MinLength, MaxLength *int
+	Pattern              *regexp.Regexp
+	Format               string
+	Minimum, Maximum     *float64
+	ExclusiveMinimum     *float64
+	ExclusiveMaximum     *float64
+	Never                bool // the false schema
+
+	ref  string
+	defs map[string]*Schema
+}
+
+// ValidationError is one way a document does not match its schema
+type ValidationError struct {
+	// Path is the failing value, e.g. items[0].price; empty for the document itself
+	Path string
+	// Keyword is the schema keyword that failed, e.g. minimum or required
+	Keyword string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "examples": true, "default": true,
+}
+
+// Compile parses a schema document
+func Compile(data []byte) (*Schema, error) {
+	var raw interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+
+	root, err := compile(raw, "#")
+	if err != nil {
+		return nil, err
+	}
+	if err := root.resolve(root.defs, make(map[*Schema]bool)); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// MustCompile is Compile for schemas known to be valid, e.g. embedded ones. It panics on error.
+func MustCompile(data []byte) *Schema {
+	schema, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+func compile(raw interface{}, at string) (*Schema, error) {
+	if b, ok := raw.(bool); ok {
+		return &Schema{Never: !b}, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or boolean", at)
+	}
+
+	s := &Schema{}
+	var err error
+	for key, value := range obj {
+		switch key {
+		case "type":
+			switch t := value.(type) {
+			case string:
+				s.Types = []string{t}
+			case []interface{}:
+				for _, v := range t {
+					name, ok := v.(string)
+					if !ok {
+						return nil, fmt.Errorf("%s/type: must be a string or list of strings", at)
+					}
+					s.Types = append(s.Types, name)
+				}
+			default:
+				return nil, fmt.Errorf("%s/type: must be a string or list of strings", at)
+			}
+		case "enum":
+			values, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s/enum: must be a list", at)
+			}
+			s.Enum = values
+		case "const":
+			s.Enum = []interface{}{value}
+		case "properties":
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s/properties: must be an object", at)
+			}
+			s.Properties = make(map[string]*Schema, len(props))
+			for name, prop := range props {
+				if s.Properties[name], err = compile(prop, at+"/properties/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			names, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s/required: must be a list", at)
+			}
+			for _, n := range names {
+				name, ok := n.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s/required: must list strings", at)
+				}
+				s.Required = append(s.Required, name)
+			}
+		case "additionalProperties":
+			if s.AdditionalProperties, err = compile(value, at+"/additionalProperties"); err != nil {
+				return nil, err
+			}
+		case "items":
+			if s.Items, err = compile(value, at+"/items"); err != nil {
+				return nil, err
+			}
+		case "minItems", "maxItems", "minLength", "maxLength":
+			n, err := intValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", at, key, err)
+			}
+			switch key {
+			case "minItems":
+				s.MinItems = &n
+			case "maxItems":
+				s.MaxItems = &n
+			case "minLength":
+				s.MinLength = &n
+			case "maxLength":
+				s.MaxLength = &n
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			n, ok := numberValue(value)
+			if !ok {
+				return nil, fmt.Errorf("%s/%s: must be a number", at, key)
+			}
+			switch key {
+			case "minimum":
+				s.Minimum = &n
+			case "maximum":
+				s.Maximum = &n
+			case "exclusiveMinimum":
+				s.ExclusiveMinimum = &n
+			case "exclusiveMaximum":
+				s.ExclusiveMaximum = &n
+			}
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/pattern: must be a string", at)
+			}
+			if s.Pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("%s/pattern: %w", at, err)
+			}
+		case "format":
+			format, ok := value.(string)
+			if !ok || formats[format] == nil {
+				return nil, fmt.Errorf("%s/format: unsupported format %v", at, value)
+			}
+			s.Format = format
+		case "$ref":
+			ref, ok := value.(string)
+			if !ok || !strings.HasPrefix(ref, "#/$defs/") {
+				return nil, fmt.Errorf("%s/$ref: only #/$defs/<name> references are supported", at)
+			}
+			s.ref = strings.TrimPrefix(ref, "#/$defs/")
+		case "$defs":
+			defs, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s/$defs: must be an object", at)
+			}
+			s.defs = make(map[string]*Schema, len(defs))
+			for name, def := range defs {
+				if s.defs[name], err = compile(def, at+"/$defs/"+name); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if !annotations[key] {
+				return nil, fmt.Errorf("%s: unsupported keyword %q", at, key)
+			}
+		}
+	}
+	return s, nil
+}
+
+// resolve replaces $ref schemas by the definitions they point to
+func (s *Schema) resolve(defs map[string]*Schema, seen map[*Schema]bool) error {
+	if s == nil || seen[s] {
+		return nil
+	}
+	seen[s] = true
+
+	if s.ref != "" {
+		def, ok := defs[s.ref]
+		if !ok {
+			return fmt.Errorf("$ref: unknown definition %q", s.ref)
+		}
+		if err := def.resolve(defs, seen); err != nil {
+			return err
+		}
+		*s = *def
+		return nil
+	}
+	for _, def := range s.defs {
+		if err := def.resolve(defs, seen); err != nil {
+			return err
+		}
+	}
+	for _, prop := range s.Properties {
+		if err := prop.resolve(defs, seen); err != nil {
+			return err
+		}
+	}
+	if err := s.AdditionalProperties.resolve(defs, seen); err != nil {
+		return err
+	}
+	return s.Items.resolve(defs, seen)
+}
+
+// ValidateJSON checks a JSON document. It returns an error only if data is not JSON.
+func (s *Schema) ValidateJSON(data []byte) ([]ValidationError, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return s.Validate(doc), nil
+}
+
+// Validate checks a decoded JSON document (numbers as json.Number or float64). Errors are
+// ordered by path.
+func (s *Schema) Validate(doc interface{}) []ValidationError {
+	var errs []ValidationError
+	s.validate(doc, "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *[]ValidationError) {
+	fail := func(keyword, format string, args ...interface{}) {
+		*errs = append(*errs, ValidationError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Never {
+		fail("false", "is not allowed")
+		return
+	}
+	if len(s.Types) > 0 && !s.matchesType(v) {
+		fail("type", "must be %s", strings.Join(s.Types, " or "))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		fail("enum", "must be one of %s", enumList(s.Enum))
+		return
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				*errs = append(*errs, ValidationError{Path: join(path, name), Keyword: "required", Message: "is required"})
+			}
+		}
+		for name, prop := range value {
+			if schema, ok := s.Properties[name]; ok {
+				schema.validate(prop, join(path, name), errs)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(prop, join(path, name), errs)
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			fail("minItems", "must have at least %d item(s)", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			fail("maxItems", "must have at most %d item(s)", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(value)
+		if s.MinLength != nil && length < *s.MinLength {
+			if *s.MinLength == 1 {
+				fail("minLength", "must not be empty")
+			} else {
+				fail("minLength", "must be at least %d characters", *s.MinLength)
+			}
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("maxLength", "must be at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(value) {
+			fail("pattern", "must match %s", s.Pattern)
+		}
+		if s.Format != "" && !formats[s.Format](value) {
+			fail("format", "must be a valid %s", s.Format)
+		}
+	default:
+		n, ok := numberValue(v)
+		if !ok {
+			return
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			fail("minimum", "must be at least %g", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("maximum", "must be at most %g", *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+			fail("exclusiveMinimum", "must be greater than %g", *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+			fail("exclusiveMaximum", "must be less than %g", *s.ExclusiveMaximum)
+		}
+	}
+}
+
+func (s *Schema) matchesType(v interface{}) bool {
+	for _, t := range s.Types {
+		switch t {
+		case "null":
+			if v == nil {
+				return true
+			}
+		case "boolean":
+			if _, ok := v.(bool); ok {
+				return true
+			}
+		case "string":
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case "object":
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case "number":
+			if _, ok := numberValue(v); ok {
+				return true
+			}
+		case "integer":
+			if n, ok := numberValue(v); ok && n == math.Trunc(n) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func intValue(v interface{}) (int, error) {
+	n, ok := numberValue(v)
+	if !ok || n < 0 || n != math.Trunc(n) {
+		return 0, fmt.Errorf("must be a non-negative integer")
+	}
+	return int(n), nil
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if en, ok := numberValue(e); ok {
+			if n, ok := numberValue(v); ok && n == en {
+				return true
+			}
+			continue
+		}
+		// Objects and lists are not comparable with ==
+		want, _ := json.Marshal(e)
+		got, _ := json.Marshal(v)
+		if bytes.Equal(want, got) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		b, _ := json.Marshal(e)
+		values[i] = string(b)
+	}
+	return strings.Join(values, ", ")
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var formats = map[string]func(string) bool{
+	"email": func(s string) bool {
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	},
+	"uuid": uuidPattern.MatchString,
+	"date-time": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"uri": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	},
+}
@@ -0,0 +1,95 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+const cartSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "items"],
+  "properties": {
+    "id": {"type": "string", "minLength": 1, "maxLength": 5},
+    "email": {"type": ["string", "null"], "format": "email"},
+    "locale": {"enum": ["en", "ar"]},
+    "items": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/item"}}
+  },
+  "$defs": {
+    "item": {
+      "type": "object",
+      "required": ["price", "quantity"],
+      "additionalProperties": false,
+      "properties": {
+        "price": {"type": "number", "exclusiveMinimum": 0},
+        "quantity": {"type": "integer", "minimum": 1}
+      }
+    }
+  }
+}`
+
+func TestValidateJSON(t *testing.T) {
+	schema := MustCompile([]byte(cartSchema))
+
+	tests := []struct {
+		name string
+		doc  string
+		want []ValidationError
+	}{
+		{"valid", `{"id": "A1", "email": null, "locale": "ar", "items": [{"price": 1.5, "quantity": 2}]}`, nil},
+		{"not an object", `[]`, []ValidationError{{"", "type", "must be object"}}},
+		{"missing fields", `{}`, []ValidationError{{"id", "required", "is required"}, {"items", "required", "is required"}}},
+		{"string bounds", `{"id": "", "items": [{"price": 1, "quantity": 1}]}`, []ValidationError{{"id", "minLength", "must not be empty"}}},
+		{"too long", `{"id": "ABCDEF", "items": [{"price": 1, "quantity": 1}]}`, []ValidationError{{"id", "maxLength", "must be at most 5 characters"}}},
+		{"format", `{"id": "A", "email": "nope", "items": [{"price": 1, "quantity": 1}]}`, []ValidationError{{"email", "format", "must be a valid email"}}},
+		{"enum", `{"id": "A", "locale": "fr", "items": [{"price": 1, "quantity": 1}]}`, []ValidationError{{"locale", "enum", `must be one of "en", "ar"`}}},
+		{"empty list", `{"id": "A", "items": []}`, []ValidationError{{"items", "minItems", "must have at least 1 item(s)"}}},
+		{"nested item", `{"id": "A", "items": [{"price": 1, "quantity": 1}, {"price": 0, "quantity": 1.5, "color": "red"}]}`, []ValidationError{
+			{"items[1].color", "false", "is not allowed"},
+			{"items[1].price", "exclusiveMinimum", "must be greater than 0"},
+			{"items[1].quantity", "type", "must be integer"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schema.ValidateJSON([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("ValidateJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateJSONInvalidDocument(t *testing.T) {
+	schema := MustCompile([]byte(`{"type": "object"}`))
+	if _, err := schema.ValidateJSON([]byte(`{"id":`)); err == nil {
+		t.Error("ValidateJSON() error = nil for truncated JSON")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"not JSON", `{`},
+		{"not a schema", `"string"`},
+		{"unsupported keyword", `{"oneOf": []}`},
+		{"unsupported format", `{"format": "hostname"}`},
+		{"bad pattern", `{"pattern": "("}`},
+		{"remote ref", `{"$ref": "https://example.com/schema.json"}`},
+		{"unknown ref", `{"$ref": "#/$defs/missing"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile([]byte(tt.schema)); err == nil {
+				t.Errorf("Compile(%s) error = nil", tt.schema)
+			}
+		})
+	}
+}
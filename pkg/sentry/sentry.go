@@ -0,0 +1,159 @@
+// Package sentry reports errors to Sentry, or any service that accepts Sentry's store API
+// (e.g. GlitchTip), without pulling in the Sentry SDK.
+//
+// A DSN such as https://<public key>@o1.ingest.sentry.io/<project id> names the project
+// events are stored in. Events are sent with Capture:
+//
+//	client, err := sentry.New(dsn, sentry.Options{Environment: "production"})
+//	...
+//	client.Capture(ctx, &sentry.Event{Message: "payment failed", Tags: map[string]string{"partner_id": id}})
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Event levels
+const (
+	LevelError   = "error"
+	LevelFatal   = "fatal"
+	LevelWarning = "warning"
+)
+
+// ErrInvalidDSN is returned by New for DSNs without a key or project
+var ErrInvalidDSN = errors.New("sentry: invalid DSN")
+
+// Options are set on every event a client sends
+type Options struct {
+	Environment string
+	Release     string
+	// ServerName defaults to the host name
+	ServerName string
+	// HTTPClient defaults to a client with a 5 second timeout
+	HTTPClient *http.Client
+}
+
+// Event is one error report. Empty fields are filled in by Capture.
+type Event struct {
+	EventID   string    `json:"event_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Platform  string    `json:"platform"`
+	Logger    string    `json:"logger,omitempty"`
+	// Message is shown as the title when there is no exception
+	Message   string            `json:"message,omitempty"`
+	Exception []Exception       `json:"exception,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	// Extra holds additional data, e.g. a stack trace that is not parsed into frames
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+}
+
+// Exception is an error in an event: its type (e.g. *errors.errorString or "panic") and
+// its message
+type Exception struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Client sends events to one project
+type Client struct {
+	storeURL   string
+	authHeader string
+	options    Options
+}
+
+// New creates a client for the project the DSN names
+func New(dsn string, options Options) (*Client, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" {
+		return nil, ErrInvalidDSN
+	}
+	path := strings.Trim(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	projectID := path[i+1:]
+	if projectID == "" {
+		return nil, ErrInvalidDSN
+	}
+	prefix := ""
+	if i >= 0 {
+		prefix = "/" + path[:i]
+	}
+
+	if options.ServerName == "" {
+		options.ServerName, _ = os.Hostname()
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	return &Client{
+		storeURL:   fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, projectID),
+		authHeader: fmt.Sprintf("Sentry sentry_version=7, sentry_client=b2bapi/1.0, sentry_key=%s", u.User.Username()),
+		options:    options,
+	}, nil
+}
+
+// Capture sends the event and returns its ID
+func (c *Client) Capture(ctx context.Context, event *Event) (string, error) {
+	if event.EventID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", err
+		}
+		event.EventID = hex.EncodeToString(id)
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	event.Timestamp = event.Timestamp.UTC()
+	if event.Level == "" {
+		event.Level = LevelError
+	}
+	if event.Platform == "" {
+		event.Platform = "go"
+	}
+	if event.Environment == "" {
+		event.Environment = c.options.Environment
+	}
+	if event.Release == "" {
+		event.Release = c.options.Release
+	}
+	if event.ServerName == "" {
+		event.ServerName = c.options.ServerName
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", c.authHeader)
+
+	resp, err := c.options.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("sentry: store returned status %d", resp.StatusCode)
+	}
+	return event.EventID, nil
+}
@@ -0,0 +1,71 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		dsn      string
+		storeURL string
+		err      error
+	}{
+		{"https://abc123@o1.ingest.sentry.io/42", "https://o1.ingest.sentry.io/api/42/store/", nil},
+		{"https://abc123@errors.example.com/glitchtip/7", "https://errors.example.com/glitchtip/api/7/store/", nil},
+		{"https://o1.ingest.sentry.io/42", "", ErrInvalidDSN},
+		{"https://abc123@o1.ingest.sentry.io/", "", ErrInvalidDSN},
+		{"::", "", ErrInvalidDSN},
+	}
+
+	for _, tt := range tests {
+		client, err := New(tt.dsn, Options{})
+		if err != tt.err {
+			t.Errorf("New(%q) error = %v, want %v", tt.dsn, err, tt.err)
+			continue
+		}
+		if err == nil && client.storeURL != tt.storeURL {
+			t.Errorf("New(%q) store URL = %q, want %q", tt.dsn, client.storeURL, tt.storeURL)
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	var auth string
+	var got Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("X-Sentry-Auth")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("body is not an event: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "http://", "http://key1@", 1) + "/3"
+	client, err := New(dsn, Options{Environment: "staging", ServerName: "api-1"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	id, err := client.Capture(context.Background(), &Event{
+		Exception: []Exception{{Type: "panic", Value: "boom"}},
+		Tags:      map[string]string{"partner_id": "p1"},
+	})
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+
+	if len(id) != 32 || got.EventID != id {
+		t.Errorf("event ID = %q (sent %q), want 32 hex characters", id, got.EventID)
+	}
+	if !strings.Contains(auth, "sentry_key=key1") {
+		t.Errorf("X-Sentry-Auth = %q, want the DSN key", auth)
+	}
+	if got.Level != LevelError || got.Environment != "staging" || got.ServerName != "api-1" || got.Tags["partner_id"] != "p1" {
+		t.Errorf("event = %+v, want level, environment, server name and tags filled in", got)
+	}
+}
@@ -0,0 +1,49 @@
+package signedurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	key := []byte("signing-key")
+	now := time.Unix(1700000000, 0)
+	path := "/v1/exports/abc/download"
+
+	signed := Sign(key, path, now.Add(15*time.Minute))
+	if !strings.HasPrefix(signed, path+"?") {
+		t.Fatalf("Sign() = %q, want the path with a query", signed)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("signed URL does not parse: %v", err)
+	}
+	expires, signature := u.Query().Get(ExpiresParam), u.Query().Get(SignatureParam)
+
+	tests := []struct {
+		name      string
+		key       []byte
+		path      string
+		expires   string
+		signature string
+		now       time.Time
+		err       error
+	}{
+		{"valid", key, path, expires, signature, now, nil},
+		{"expired", key, path, expires, signature, now.Add(15 * time.Minute), ErrExpired},
+		{"other path", key, "/v1/exports/def/download", expires, signature, now, ErrInvalidSignature},
+		{"other key", []byte("other"), path, expires, signature, now, ErrInvalidSignature},
+		{"extended expiry", key, path, "1800000000", signature, now, ErrInvalidSignature},
+		{"not hex", key, path, expires, "zz", now, ErrInvalidSignature},
+		{"bad expiry", key, path, "soon", signature, now, ErrInvalidExpiry},
+		{"unsigned", key, path, "", "", now, ErrMissingSignature},
+	}
+
+	for _, tt := range tests {
+		if err := Verify(tt.key, tt.path, tt.expires, tt.signature, tt.now); err != tt.err {
+			t.Errorf("%s: Verify() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
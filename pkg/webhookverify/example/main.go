@@ -0,0 +1,55 @@
+// Command example is a minimal partner webhook receiver that verifies B2B API webhooks.
+//
+//	WEBHOOK_SECRET=<secret from PUT /v1/partner/webhook> go run ./pkg/webhookverify/example
+//
+// It listens on :8090 (PORT overrides it) and logs every verified event.
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jafarshop/b2bapi/pkg/webhookverify"
+)
+
+func main() {
+	secret := os.Getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("WEBHOOK_SECRET is required")
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8090"
+	}
+
+	verifier := &webhookverify.Verifier{
+		Secret: []byte(secret),
+		Replay: webhookverify.NewMemoryReplayCache(),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/b2b", verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			EventType       string `json:"event_type"`
+			SupplierOrderID string `json:"supplier_order_id"`
+			PartnerOrderID  string `json:"partner_order_id"`
+			Status          string `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("delivery %s: %s for order %s (%s), status %s",
+			r.Header.Get(webhookverify.DeliveryHeader), event.EventType,
+			event.PartnerOrderID, event.SupplierOrderID, event.Status)
+
+		// Acknowledge quickly; do slow work asynchronously
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	log.Printf("listening on :%s, POST webhooks to /webhooks/b2b", port)
+	log.Fatal(http.ListenAndServe(":"+port, mux))
+}
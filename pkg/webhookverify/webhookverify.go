@@ -0,0 +1,192 @@
+// Package webhookverify signs and verifies B2B API partner webhooks.
+//
+// Every webhook carries three headers:
+//
+//	X-B2B-Delivery:  unique delivery ID (use it to drop replays)
+//	X-B2B-Timestamp: Unix time the delivery was signed
+//	X-B2B-Signature: v1=<hex HMAC-SHA256 of "<timestamp>.<raw body>" keyed with the webhook secret>
+//
+// Deliveries the partner asked to resend get a new delivery ID and carry the ID of the
+// original delivery in X-B2B-Retry-Of.
+//
+// Receivers should verify the signature over the raw request body, reject timestamps
+// outside a small tolerance and remember delivery IDs for at least as long.
+// Verifier does all three.
+package webhookverify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook headers
+const (
+	DeliveryHeader  = "X-B2B-Delivery"
+	TimestampHeader = "X-B2B-Timestamp"
+	SignatureHeader = "X-B2B-Signature"
+	RetryOfHeader   = "X-B2B-Retry-Of"
+)
+
+// signatureVersion prefixes signatures so the scheme can change without ambiguity
+const signatureVersion = "v1"
+
+// DefaultTolerance is how far a delivery's timestamp may be from the receiver's clock
+const DefaultTolerance = 5 * time.Minute
+
+// maxBodySize bounds the webhook body VerifyRequest reads
+const maxBodySize = 1 << 20
+
+// Verification errors
+var (
+	ErrMissingHeaders   = errors.New("webhookverify: missing signature headers")
+	ErrInvalidTimestamp = errors.New("webhookverify: invalid timestamp")
+	ErrTimestampExpired = errors.New("webhookverify: timestamp outside tolerance")
+	ErrInvalidSignature = errors.New("webhookverify: signature mismatch")
+	ErrReplayed         = errors.New("webhookverify: delivery already received")
+)
+
+// Sign returns the X-B2B-Signature value for a body signed at timestamp
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	return signatureVersion + "=" + hex.EncodeToString(mac(secret, timestamp.Unix(), body))
+}
+
+// Verify checks a signature header against the body and the timestamp header.
+// The timestamp must be within tolerance of now.
+func Verify(secret []byte, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	if signature == "" || timestamp == "" {
+		return ErrMissingHeaders
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	expected := mac(secret, unix, body)
+	// A header may carry several signatures (e.g. during secret rotation)
+	for _, part := range strings.Split(signature, ",") {
+		version, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || version != signatureVersion {
+			continue
+		}
+		given, err := hex.DecodeString(value)
+		if err == nil && hmac.Equal(given, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret []byte, unix int64, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	fmt.Fprintf(h, "%d.", unix)
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// ReplayCache remembers delivery IDs. Seen records id until expiresAt and reports
+// whether it was already recorded.
+type ReplayCache interface {
+	Seen(id string, expiresAt time.Time) bool
+}
+
+// MemoryReplayCache is an in-process ReplayCache. Receivers running several instances
+// should back ReplayCache with a shared store instead.
+type MemoryReplayCache struct {
+	mu  sync.Mutex
+	ids map[string]time.Time
+}
+
+// NewMemoryReplayCache creates an empty in-process replay cache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{ids: make(map[string]time.Time)}
+}
+
+// Seen implements ReplayCache. Expired IDs are dropped as new ones arrive.
+func (c *MemoryReplayCache) Seen(id string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for seen, expiry := range c.ids {
+		if now.After(expiry) {
+			delete(c.ids, seen)
+		}
+	}
+	if _, ok := c.ids[id]; ok {
+		return true
+	}
+	c.ids[id] = expiresAt
+	return false
+}
+
+// Verifier verifies incoming webhook requests
+type Verifier struct {
+	Secret []byte
+	// Tolerance defaults to DefaultTolerance
+	Tolerance time.Duration
+	// Replay, if set, rejects deliveries whose ID was already received
+	Replay ReplayCache
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// VerifyRequest reads and verifies the request body and returns it. The body is also
+// replaced, so handlers after VerifyRequest can read it again.
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	if err := Verify(v.Secret, r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), body, now, tolerance); err != nil {
+		return nil, err
+	}
+
+	if v.Replay != nil {
+		id := r.Header.Get(DeliveryHeader)
+		if id == "" {
+			return nil, ErrMissingHeaders
+		}
+		// A replay older than the tolerance already fails the timestamp check
+		if v.Replay.Seen(id, now.Add(2*tolerance)) {
+			return nil, ErrReplayed
+		}
+	}
+
+	return body, nil
+}
+
+// Middleware rejects requests that fail VerifyRequest with 401 before calling next
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := v.VerifyRequest(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,99 @@
+package webhookverify
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var secret = []byte("whsec_test")
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event_type":"order.on_hold"}`)
+	signature := Sign(secret, now, body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		signature string
+		timestamp string
+		body      []byte
+		now       time.Time
+		want      error
+	}{
+		{"valid", secret, signature, timestamp, body, now, nil},
+		{"valid within tolerance", secret, signature, timestamp, body, now.Add(4 * time.Minute), nil},
+		{"one of several signatures", secret, "v1=00ff," + signature, timestamp, body, now, nil},
+		{"tampered body", secret, signature, timestamp, []byte(`{"event_type":"order.released"}`), now, ErrInvalidSignature},
+		{"wrong secret", []byte("other"), signature, timestamp, body, now, ErrInvalidSignature},
+		{"unknown version", secret, "v0=" + signature[3:], timestamp, body, now, ErrInvalidSignature},
+		{"expired", secret, signature, timestamp, body, now.Add(6 * time.Minute), ErrTimestampExpired},
+		{"from the future", secret, signature, timestamp, body, now.Add(-6 * time.Minute), ErrTimestampExpired},
+		{"timestamp not signed", secret, signature, strconv.FormatInt(now.Unix()+1, 10), body, now, ErrInvalidSignature},
+		{"invalid timestamp", secret, signature, "yesterday", body, now, ErrInvalidTimestamp},
+		{"missing signature", secret, "", timestamp, body, now, ErrMissingHeaders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.secret, tt.signature, tt.timestamp, tt.body, tt.now, DefaultTolerance); err != tt.want {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierVerifyRequest(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"event_type":"order.on_hold"}`)
+	verifier := &Verifier{Secret: secret, Replay: NewMemoryReplayCache()}
+
+	deliver := func(deliveryID string) error {
+		req := httptest.NewRequest("POST", "/webhooks/b2b", bytes.NewReader(body))
+		req.Header.Set(DeliveryHeader, deliveryID)
+		req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(SignatureHeader, Sign(secret, now, body))
+
+		got, err := verifier.VerifyRequest(req)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(got, body) {
+			t.Errorf("VerifyRequest() body = %s, want %s", got, body)
+		}
+		// Handlers after verification can still read the body
+		if again, _ := io.ReadAll(req.Body); !bytes.Equal(again, body) {
+			t.Errorf("request body after VerifyRequest = %s, want %s", again, body)
+		}
+		return nil
+	}
+
+	if err := deliver("delivery-1"); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	if err := deliver("delivery-1"); err != ErrReplayed {
+		t.Errorf("replayed delivery: got %v, want %v", err, ErrReplayed)
+	}
+	if err := deliver("delivery-2"); err != nil {
+		t.Errorf("second delivery: %v", err)
+	}
+}
+
+func TestMemoryReplayCacheExpiry(t *testing.T) {
+	cache := NewMemoryReplayCache()
+	if cache.Seen("a", time.Now().Add(-time.Second)) {
+		t.Fatal("new ID reported as seen")
+	}
+	// "a" expired, so it is dropped before the next lookup
+	if cache.Seen("a", time.Now().Add(time.Minute)) {
+		t.Error("expired ID reported as seen")
+	}
+	if !cache.Seen("a", time.Now().Add(time.Minute)) {
+		t.Error("recorded ID not reported as seen")
+	}
+}